@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ionut-t/perp/internal/config"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+func completionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: "Generate shell completion scripts",
+		Long: `Generate the completion script for the given shell.
+
+  bash: source <(perp completion bash)
+  zsh:  perp completion zsh > "${fpath[1]}/_perp"
+  fish: perp completion fish > ~/.config/fish/completions/perp.fish`,
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			}
+
+			return fmt.Errorf("unsupported shell %q", args[0])
+		},
+	}
+}
+
+// completeServerNames completes the names of the saved servers
+func completeServerNames(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	storage, err := config.GetStorage()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	servers, err := server.Load(storage)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var names []string
+	for _, srv := range servers {
+		if strings.HasPrefix(srv.Name, toComplete) {
+			names = append(names, srv.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -57,10 +57,16 @@ func configCmd() *cobra.Command {
 
 			if !flagsSet {
 				openInEditor(configPath)
+
+				if err := config.Validate(configPath); err != nil {
+					fmt.Println(err)
+				}
 			}
 		},
 	}
 
+	cmd.AddCommand(validateConfigCmd())
+
 	cmd.Flags().StringP(config.EditorKey, "e", "", "Set the editor to use for editing config")
 	cmd.Flags().StringP(config.LLMProviderKey, "p", "", "Set the LLM provider (e.g., gemini, vertexai)")
 	cmd.Flags().StringP(config.LLMModelKey, "m", "", "Set the LLM model")
@@ -68,6 +74,24 @@ func configCmd() *cobra.Command {
 	return cmd
 }
 
+func validateConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for unknown keys and invalid values",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			configPath := config.GetConfigFilePath()
+
+			if err := config.Validate(configPath); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Config is valid:", configPath)
+		},
+	}
+}
+
 func openInEditor(configPath string) {
 	editor := config.GetEditor()
 
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	mcobra "github.com/muesli/mango-cobra"
+	"github.com/muesli/roff"
+	"github.com/spf13/cobra"
+)
+
+func manCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:                   "man",
+		Short:                 "Generate the perp man page",
+		Long:                  "Writes the man page to stdout, e.g. perp man > /usr/local/share/man/man1/perp.1",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			page, err := mcobra.NewManPage(1, cmd.Root())
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprint(cmd.OutOrStdout(), page.Build(roff.NewDocument()))
+			return err
+		},
+	}
+}
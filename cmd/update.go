@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ionut-t/perp/internal/version"
+	"github.com/ionut-t/perp/pkg/update"
+	"github.com/spf13/cobra"
+)
+
+func updateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update perp to the latest release",
+		Long: "Downloads the latest release for this platform, verifies its checksum and replaces the current binary. " +
+			"The previous binary is kept and can be restored with --rollback.",
+		Run: func(cmd *cobra.Command, args []string) {
+			rollback, _ := cmd.Flags().GetBool("rollback")
+
+			updater, err := update.NewUpdater(version.Version())
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if rollback {
+				if err := updater.Rollback(); err != nil {
+					fmt.Println("Error rolling back:", err)
+					os.Exit(1)
+				}
+				fmt.Println("Previous version restored.")
+				return
+			}
+
+			result, err := updater.Install(cmd.Context())
+			if err != nil {
+				fmt.Println("Error updating perp:", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("perp updated to %s. Run `perp update --rollback` to restore the previous version.\n", result.Version)
+		},
+	}
+
+	cmd.Flags().Bool("rollback", false, "Restore the binary replaced by the last update")
+
+	return cmd
+}
@@ -3,11 +3,16 @@ package config
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"text/template"
 
+	"github.com/ionut-t/perp/pkg/db"
 	"github.com/spf13/viper"
 )
 
@@ -18,14 +23,39 @@ var defaultConfig string
 var defaultLLMInstructions string
 
 const (
-	EditorKey           = "editor"
-	MaxHistoryLengthKey = "max_history_length"
-	MaxHistoryDaysKey   = "max_history_days"
-	LLMProviderKey      = "llm_provider"
-	LLMModelKey         = "llm_model"
-	AutoUpdateKey       = "auto_update"
-	UpdateCheckInterval = "update_check_interval"
-	LeaderKey           = "leader_key"
+	EditorKey            = "editor"
+	MaxHistoryLengthKey  = "max_history_length"
+	MaxHistoryDaysKey    = "max_history_days"
+	LLMProviderKey       = "llm_provider"
+	LLMModelKey          = "llm_model"
+	AutoUpdateKey        = "auto_update"
+	UpdateCheckInterval  = "update_check_interval"
+	LeaderKey            = "leader_key"
+	PaneCommandKey       = "pane_command"
+	DisplayTimeZoneKey   = "display_timezone"
+	TimestampFormatKey   = "timestamp_format"
+	HighlightRulesKey    = "highlight_rules"
+	UppercaseKeywordsKey = "uppercase_keywords"
+	WorkingDirectoryKey  = "working_directory"
+	CommandAliasesKey    = "command_aliases"
+	StorageKey           = "storage"
+
+	// LLM generation settings are stored per provider as <provider>_<setting>,
+	// e.g. gemini_temperature or vertexai_timeout.
+	LLMTemperatureSetting = "temperature"
+	LLMMaxTokensSetting   = "max_tokens"
+	LLMTimeoutSetting     = "timeout"
+
+	// Display options set with \pset are stored as pset_<option>, e.g. pset_null.
+	psetKeyPrefix = "pset_"
+
+	// Connection pool settings, named in db.PoolSettingNames, are stored as
+	// pool_<setting>, e.g. pool_max_conns.
+	poolKeyPrefix = "pool_"
+
+	// envPrefix starts the environment variables overriding the config
+	// keys, e.g. PERP_EDITOR overrides editor
+	envPrefix = "PERP"
 
 	rootDir                 = ".perp"
 	configFileName          = ".config.toml"
@@ -46,9 +76,30 @@ type Config interface {
 	AutoUpdateEnabled() bool
 	UpdateCheckIntervalHours() float64
 	GetLeaderKey() string
+	PaneCommand() string
+	DisplayTimeZone() string
+	TimestampFormat() string
+	HighlightRules() []string
+	UppercaseKeywords() bool
+	WorkingDirectory() string
+	CommandAliases() []string
+	SetUppercaseKeywords(enabled bool) error
 	SetLeaderKey(key string) error
+	GetLLMSetting(provider, setting string) string
+	SetLLMSetting(provider, setting, value string) error
+	GetPsetOption(option string) string
+	SetPsetOption(option, value string) error
+	GetPoolSetting(setting string) string
 }
 
+// LLMProviders lists the providers that have their own generation settings
+var LLMProviders = []string{"gemini", "vertexai"}
+
+var llmSettings = []string{LLMTemperatureSetting, LLMMaxTokensSetting, LLMTimeoutSetting}
+
+// PsetOptions lists the display options that can be stored with \pset
+var PsetOptions = []string{"border", "null", "expanded", "footer", "pager"}
+
 type configData struct {
 	Editor              string
 	MaxHistoryLength    int
@@ -58,6 +109,16 @@ type configData struct {
 	AutoUpdate          bool
 	UpdateCheckInterval float64
 	LeaderKey           string
+	PaneCommand         string
+	DisplayTimeZone     string
+	TimestampFormat     string
+	HighlightRules      []string
+	UppercaseKeywords   bool
+	WorkingDirectory    string
+	CommandAliases      []string
+	LLMSettings         map[string]string
+	PsetOptions         map[string]string
+	PoolSettings        map[string]string
 }
 
 type config struct {
@@ -65,7 +126,17 @@ type config struct {
 	data    configData
 }
 
-func getConfigData() configData {
+func getConfigData() (configData, error) {
+	highlightRules, err := getStrings(HighlightRulesKey)
+	if err != nil {
+		return configData{}, err
+	}
+
+	commandAliases, err := getStrings(CommandAliasesKey)
+	if err != nil {
+		return configData{}, err
+	}
+
 	return configData{
 		Editor:              GetEditor(),
 		MaxHistoryLength:    viper.GetInt(MaxHistoryLengthKey),
@@ -75,7 +146,72 @@ func getConfigData() configData {
 		AutoUpdate:          viper.GetBool(AutoUpdateKey),
 		UpdateCheckInterval: viper.GetFloat64(UpdateCheckInterval),
 		LeaderKey:           viper.GetString(LeaderKey),
+		PaneCommand:         viper.GetString(PaneCommandKey),
+		DisplayTimeZone:     viper.GetString(DisplayTimeZoneKey),
+		TimestampFormat:     viper.GetString(TimestampFormatKey),
+		HighlightRules:      highlightRules,
+		UppercaseKeywords:   viper.GetBool(UppercaseKeywordsKey),
+		WorkingDirectory:    viper.GetString(WorkingDirectoryKey),
+		CommandAliases:      commandAliases,
+		LLMSettings:         getLLMSettings(),
+		PsetOptions:         getPsetOptions(),
+		PoolSettings:        getPoolSettings(),
+	}, nil
+}
+
+// EnvVar returns the environment variable overriding a config key, e.g.
+// PERP_MAX_HISTORY_DAYS for max_history_days. Lists are written as JSON
+// arrays, e.g. PERP_COMMAND_ALIASES='["x = export * out.json"]'.
+func EnvVar(key string) string {
+	return envPrefix + "_" + strings.ToUpper(key)
+}
+
+// getStrings returns the list of strings set for key, read as a JSON array
+// when it comes from the environment, since its strings may hold spaces. A
+// variable that isn't one is an error rather than split on spaces.
+func getStrings(key string) ([]string, error) {
+	value := os.Getenv(EnvVar(key))
+	if value == "" {
+		return viper.GetStringSlice(key), nil
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("%s: %s", EnvVar(key), envStringsMessage)
+	}
+
+	return values, nil
+}
+
+func getLLMSettings() map[string]string {
+	settings := make(map[string]string, len(LLMProviders)*len(llmSettings))
+	for _, provider := range LLMProviders {
+		for _, setting := range llmSettings {
+			key := llmSettingKey(provider, setting)
+			settings[key] = viper.GetString(key)
+		}
+	}
+	return settings
+}
+
+func llmSettingKey(provider, setting string) string {
+	return provider + "_" + setting
+}
+
+func getPsetOptions() map[string]string {
+	options := make(map[string]string, len(PsetOptions))
+	for _, option := range PsetOptions {
+		options[option] = viper.GetString(psetKeyPrefix + option)
 	}
+	return options
+}
+
+func getPoolSettings() map[string]string {
+	settings := make(map[string]string, len(db.PoolSettingNames))
+	for _, setting := range db.PoolSettingNames {
+		settings[setting] = viper.GetString(poolKeyPrefix + setting)
+	}
+	return settings
 }
 
 func New() (Config, error) {
@@ -84,9 +220,14 @@ func New() (Config, error) {
 		return nil, err
 	}
 
+	data, err := getConfigData()
+	if err != nil {
+		return nil, err
+	}
+
 	return &config{
 		storage: storage,
-		data:    getConfigData(),
+		data:    data,
 	}, nil
 }
 
@@ -107,11 +248,66 @@ func (c *config) SetLeaderKey(key string) error {
 		return nil
 	}
 
+	if err := checkLeaderKey(key); err != nil {
+		return err
+	}
+
 	c.data.LeaderKey = key
 
 	return c.updateValueInConfig(LeaderKey, key)
 }
 
+// PaneCommand returns the command template used to open results in a new
+// terminal pane. An empty value means tmux or wezterm is detected.
+func (c *config) PaneCommand() string {
+	return c.data.PaneCommand
+}
+
+// DisplayTimeZone returns the time zone timestamptz values are shown in.
+// An empty value keeps the session time zone of the server.
+func (c *config) DisplayTimeZone() string {
+	return c.data.DisplayTimeZone
+}
+
+// TimestampFormat returns the Go time layout used to show timestamps
+func (c *config) TimestampFormat() string {
+	return c.data.TimestampFormat
+}
+
+// HighlightRules returns the rules used to highlight rows of the results,
+// e.g. "status = 'failed' -> red"
+func (c *config) HighlightRules() []string {
+	return c.data.HighlightRules
+}
+
+// UppercaseKeywords reports whether SQL keywords are upper-cased as they are
+// typed in the editor
+func (c *config) UppercaseKeywords() bool {
+	return c.data.UppercaseKeywords
+}
+
+func (c *config) SetUppercaseKeywords(enabled bool) error {
+	if enabled == c.UppercaseKeywords() {
+		return nil
+	}
+
+	c.data.UppercaseKeywords = enabled
+
+	return c.updateValueInConfig(UppercaseKeywordsKey, strconv.FormatBool(enabled))
+}
+
+// WorkingDirectory returns the directory relative paths given to \i start
+// from. An empty value keeps the directory perp was started in.
+func (c *config) WorkingDirectory() string {
+	return c.data.WorkingDirectory
+}
+
+// CommandAliases returns the aliases of the command bar commands, e.g.
+// "x = export * out.json"
+func (c *config) CommandAliases() []string {
+	return c.data.CommandAliases
+}
+
 func (c *config) Editor() string {
 	return c.data.Editor
 }
@@ -153,6 +349,10 @@ func (c *config) SetLLMProvider(provider string) error {
 		return fmt.Errorf("%s cannot be empty", LLMProviderKey)
 	}
 
+	if err := checkLLMProvider(provider); err != nil {
+		return err
+	}
+
 	c.data.LLMProvider = provider
 
 	return c.updateValueInConfig(LLMProviderKey, provider)
@@ -178,6 +378,46 @@ func (c *config) SetLLMModel(model string) error {
 	return c.updateValueInConfig(LLMModelKey, model)
 }
 
+func (c *config) GetLLMSetting(provider, setting string) string {
+	return c.data.LLMSettings[llmSettingKey(provider, setting)]
+}
+
+func (c *config) SetLLMSetting(provider, setting, value string) error {
+	if !slices.Contains(LLMProviders, provider) {
+		return fmt.Errorf("unsupported LLM provider: %s", provider)
+	}
+
+	if !slices.Contains(llmSettings, setting) {
+		return fmt.Errorf("unknown LLM setting: %s", setting)
+	}
+
+	key := llmSettingKey(provider, setting)
+	c.data.LLMSettings[key] = value
+
+	return c.updateValueInConfig(key, value)
+}
+
+// GetPsetOption returns the stored value of a \pset display option, empty when unset
+func (c *config) GetPsetOption(option string) string {
+	return c.data.PsetOptions[option]
+}
+
+func (c *config) SetPsetOption(option, value string) error {
+	if !slices.Contains(PsetOptions, option) {
+		return fmt.Errorf("unknown display option: %s", option)
+	}
+
+	c.data.PsetOptions[option] = value
+
+	return c.updateValueInConfig(psetKeyPrefix+option, value)
+}
+
+// GetPoolSetting returns the configured value of a connection pool setting,
+// empty to keep the default
+func (c *config) GetPoolSetting(setting string) string {
+	return c.data.PoolSettings[setting]
+}
+
 func (c *config) GetLLMInstructions() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -269,8 +509,34 @@ func InitialiseConfigFile() (string, error) {
 			viper.SetDefault(LLMProviderKey, "")
 			viper.SetDefault(LLMModelKey, "gemini-2.0-flash")
 			viper.SetDefault(LeaderKey, " ")
+			viper.SetDefault(PaneCommandKey, "")
+			viper.SetDefault(DisplayTimeZoneKey, "")
+			viper.SetDefault(TimestampFormatKey, "")
+			viper.SetDefault(HighlightRulesKey, []string{})
+			viper.SetDefault(UppercaseKeywordsKey, false)
+			viper.SetDefault(WorkingDirectoryKey, "")
+			viper.SetDefault(CommandAliasesKey, []string{})
+
+			for _, provider := range LLMProviders {
+				viper.SetDefault(llmSettingKey(provider, LLMTemperatureSetting), "")
+				viper.SetDefault(llmSettingKey(provider, LLMMaxTokensSetting), "")
+				viper.SetDefault(llmSettingKey(provider, LLMTimeoutSetting), "30s")
+			}
+
+			for _, option := range PsetOptions {
+				viper.SetDefault(psetKeyPrefix+option, "")
+			}
+
+			viper.SetDefault(poolKeyPrefix+db.PoolMaxConns, "")
+			viper.SetDefault(poolKeyPrefix+db.PoolMaxConnIdleTime, "30m")
+			viper.SetDefault(poolKeyPrefix+db.PoolHealthCheckPeriod, "1m")
+
+			data, err := getConfigData()
+			if err != nil {
+				return "", err
+			}
 
-			if err := writeConfig(getConfigData()); err != nil {
+			if err := writeConfig(data); err != nil {
 				return "", err
 			}
 
@@ -281,6 +547,10 @@ func InitialiseConfigFile() (string, error) {
 		}
 	}
 
+	// bound once the config is written, so it doesn't keep the overrides
+	viper.SetEnvPrefix(envPrefix)
+	viper.AutomaticEnv()
+
 	return configPath, nil
 }
 
@@ -301,9 +571,13 @@ func GetConfigFilePath() string {
 }
 
 func GetStorage() (string, error) {
-	storage := viper.GetString("storage")
+	storage := viper.GetString(StorageKey)
 
 	if storage != "" {
+		// e.g. a directory given with PERP_STORAGE in a container
+		if err := os.MkdirAll(storage, 0o755); err != nil {
+			return "", err
+		}
 		return storage, nil
 	}
 
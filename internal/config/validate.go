@@ -0,0 +1,451 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/spf13/viper"
+)
+
+// Issue is a problem with a key of the config file
+type Issue struct {
+	Key     string
+	Line    int // counted from 1, 0 when unknown
+	Message string
+}
+
+func (i Issue) Error() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Key, i.Message)
+	}
+
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Key, i.Message)
+}
+
+// ValidationError lists the problems of the config file
+type ValidationError struct {
+	Path   string
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config %s:", e.Path)
+	for _, issue := range e.Issues {
+		b.WriteString("\n  ")
+		b.WriteString(issue.Error())
+	}
+
+	return b.String()
+}
+
+// valueKind is the type of the value of a key
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindBool
+	kindInteger
+	kindNumber
+	kindStrings
+)
+
+// schemaKey describes the value of a key, checked after its type when check
+// is set
+type schemaKey struct {
+	kind  valueKind
+	check func(value string) error
+}
+
+// schema returns the keys of the config file
+func schema() map[string]schemaKey {
+	keys := map[string]schemaKey{
+		StorageKey:           {kind: kindString},
+		EditorKey:            {kind: kindString},
+		MaxHistoryLengthKey:  {kind: kindInteger, check: checkNotNegative},
+		MaxHistoryDaysKey:    {kind: kindInteger, check: checkNotNegative},
+		LLMProviderKey:       {kind: kindString, check: checkLLMProvider},
+		LLMModelKey:          {kind: kindString},
+		AutoUpdateKey:        {kind: kindBool},
+		UpdateCheckInterval:  {kind: kindNumber, check: checkNotNegative},
+		LeaderKey:            {kind: kindString, check: checkLeaderKey},
+		PaneCommandKey:       {kind: kindString},
+		DisplayTimeZoneKey:   {kind: kindString, check: checkTimeZone},
+		TimestampFormatKey:   {kind: kindString},
+		HighlightRulesKey:    {kind: kindStrings},
+		UppercaseKeywordsKey: {kind: kindBool},
+		WorkingDirectoryKey:  {kind: kindString},
+		CommandAliasesKey:    {kind: kindStrings},
+	}
+
+	for _, provider := range LLMProviders {
+		for _, setting := range llmSettings {
+			keys[llmSettingKey(provider, setting)] = schemaKey{kind: kindString, check: func(value string) error {
+				settings := llm.DefaultSettings()
+				return settings.Set(setting, value)
+			}}
+		}
+	}
+
+	for _, option := range PsetOptions {
+		keys[psetKeyPrefix+option] = schemaKey{kind: kindString, check: func(value string) error {
+			// like in the app, an empty value keeps the default
+			if value == "" {
+				return nil
+			}
+
+			options := psql.DefaultDisplayOptions()
+			_, err := options.Set(option, value)
+			return err
+		}}
+	}
+
+	for _, setting := range db.PoolSettingNames {
+		keys[poolKeyPrefix+setting] = schemaKey{kind: kindString, check: func(value string) error {
+			var settings db.PoolSettings
+			return settings.Set(setting, value)
+		}}
+	}
+
+	return keys
+}
+
+// Validate checks the config file at path: its TOML syntax, keys that perp
+// doesn't know, values of the wrong type and invalid values. The values of
+// the PERP_* environment variables overriding it are checked too. It
+// returns a *ValidationError listing every problem found.
+func Validate(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var issues []Issue
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		issue := Issue{Key: "syntax", Message: err.Error()}
+
+		// the TOML decoder reports where the file stopped parsing
+		var positioned interface{ Position() (int, int) }
+		if errors.As(err, &positioned) {
+			issue.Line, _ = positioned.Position()
+		}
+
+		issues = append(issues, issue)
+	} else {
+		issues = validateSettings(v.AllSettings(), keyLines(string(content)))
+	}
+
+	issues = append(issues, validateEnv(os.Getenv)...)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Path: path, Issues: issues}
+}
+
+// validateSettings checks the values read from the config file, by key
+func validateSettings(settings map[string]any, lines map[string]int) []Issue {
+	keys := schema()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+
+	var issues []Issue
+	for name, value := range settings {
+		issue := Issue{Key: name, Line: lines[name]}
+
+		key, ok := keys[name]
+		if !ok {
+			issue.Message = "unknown key"
+			if suggestion := closestName(name, names); suggestion != "" {
+				issue.Message += fmt.Sprintf(", did you mean %s?", suggestion)
+			}
+			issues = append(issues, issue)
+			continue
+		}
+
+		if err := key.validate(value); err != nil {
+			issue.Message = err.Error()
+			issues = append(issues, issue)
+		}
+	}
+
+	// in the order of the file, then by name for the keys without a line
+	slices.SortFunc(issues, func(a, b Issue) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return issues
+}
+
+// envStringsMessage describes the value expected of a variable overriding a
+// list
+const envStringsMessage = `expected a JSON array of strings, e.g. ["a", "b"]`
+
+// validateEnv checks the values of the environment variables overriding the
+// config keys, named after the variables
+func validateEnv(getenv func(string) string) []Issue {
+	keys := schema()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var issues []Issue
+	for _, name := range names {
+		key := keys[name]
+
+		text := getenv(EnvVar(name))
+		if text == "" {
+			continue
+		}
+
+		var value any = text
+		if key.kind == kindStrings {
+			var values []any
+			if err := json.Unmarshal([]byte(text), &values); err != nil {
+				issues = append(issues, Issue{Key: EnvVar(name), Message: envStringsMessage})
+				continue
+			}
+			value = values
+		}
+
+		if err := key.validate(value); err != nil {
+			issues = append(issues, Issue{Key: EnvVar(name), Message: err.Error()})
+		}
+	}
+
+	return issues
+}
+
+// validate checks the type of the value, then the value. Values written as
+// strings are accepted for the other types when they convert, since the app
+// writes the values it changes quoted.
+func (k schemaKey) validate(value any) error {
+	var text string
+
+	switch k.kind {
+	case kindStrings:
+		values, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected a list of strings, got %s", describe(value))
+		}
+
+		for i, item := range values {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("expected a list of strings, item %d is %s", i+1, describe(item))
+			}
+		}
+
+		return nil
+
+	case kindBool:
+		switch v := value.(type) {
+		case bool:
+			text = strconv.FormatBool(v)
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("expected true or false, got %q", v)
+			}
+			text = v
+		default:
+			return fmt.Errorf("expected true or false, got %s", describe(value))
+		}
+
+	case kindInteger:
+		switch v := value.(type) {
+		case int64:
+			text = strconv.FormatInt(v, 10)
+		case string:
+			if _, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err != nil {
+				return fmt.Errorf("expected a whole number, got %q", v)
+			}
+			text = strings.TrimSpace(v)
+		default:
+			return fmt.Errorf("expected a whole number, got %s", describe(value))
+		}
+
+	case kindNumber:
+		switch v := value.(type) {
+		case int64:
+			text = strconv.FormatInt(v, 10)
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return fmt.Errorf("expected a number, got %q", v)
+			}
+			text = strings.TrimSpace(v)
+		default:
+			return fmt.Errorf("expected a number, got %s", describe(value))
+		}
+
+	default:
+		switch v := value.(type) {
+		case string:
+			text = v
+		// numbers are read as strings too, e.g. pool_max_conns = 8
+		case int64, float64:
+			text = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("expected a string, got %s", describe(value))
+		}
+	}
+
+	if k.check == nil {
+		return nil
+	}
+
+	return k.check(text)
+}
+
+// describe names the TOML type of a value for the error messages
+func describe(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return fmt.Sprintf("the boolean %t", v)
+	case int64, float64:
+		return fmt.Sprintf("the number %v", v)
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "a table"
+	case time.Time:
+		return "a date"
+	}
+
+	return fmt.Sprintf("a %T", value)
+}
+
+func checkNotNegative(value string) error {
+	number, err := strconv.ParseFloat(value, 64)
+	if err == nil && number < 0 {
+		return fmt.Errorf("expected a positive number or 0, got %s", value)
+	}
+
+	return nil
+}
+
+func checkLLMProvider(value string) error {
+	if value == "" || slices.Contains(LLMProviders, strings.ToLower(value)) {
+		return nil
+	}
+
+	return fmt.Errorf("unsupported LLM provider %q, expected one of %s", value, strings.Join(LLMProviders, ", "))
+}
+
+// leaderKeyPattern matches the names of the keys that can be the leader key,
+// with their modifiers, e.g. ctrl+a, alt+space or f2
+var leaderKeyPattern = regexp.MustCompile(`^(ctrl\+)?(alt\+)?(shift\+)?(space|tab|f([1-9]|1[0-2])|[^\s+]|\+)$`)
+
+// checkLeaderKey checks the leader key is a single character, " " for space,
+// or a key name as the app reports it, e.g. "ctrl+a"
+func checkLeaderKey(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	if utf8.RuneCountInString(value) == 1 {
+		r, _ := utf8.DecodeRuneInString(value)
+		if unicode.IsPrint(r) {
+			return nil
+		}
+	}
+
+	if leaderKeyPattern.MatchString(value) {
+		return nil
+	}
+
+	return fmt.Errorf(`invalid leader key %q, expected a single character such as "," or a key such as "space", "ctrl+a" or "f2"`, value)
+}
+
+func checkTimeZone(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(value); err != nil {
+		return fmt.Errorf(`unknown time zone %q, expected a name such as "UTC", "Local" or "Europe/London"`, value)
+	}
+
+	return nil
+}
+
+// keyPattern matches the key of a line setting a value
+var keyPattern = regexp.MustCompile(`^\s*"?([A-Za-z0-9_-]+)"?\s*=`)
+
+// keyLines returns the line each key of the file is set on
+func keyLines(content string) map[string]int {
+	lines := map[string]int{}
+	for i, line := range strings.Split(content, "\n") {
+		if match := keyPattern.FindStringSubmatch(line); match != nil {
+			key := strings.ToLower(match[1])
+			if _, ok := lines[key]; !ok {
+				lines[key] = i + 1
+			}
+		}
+	}
+
+	return lines
+}
+
+// closestName returns the name differing from name by the fewest edits, or
+// an empty string when every one differs by more than a third of it
+func closestName(name string, names []string) string {
+	closest, best := "", len(name)/3+1
+	for _, candidate := range names {
+		if distance := editDistance(name, candidate); distance < best ||
+			(distance == best && closest != "" && candidate < closest) {
+			closest, best = candidate, distance
+		}
+	}
+
+	return closest
+}
+
+// editDistance counts the characters to insert, delete or replace to turn a
+// into b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
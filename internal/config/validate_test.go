@@ -0,0 +1,134 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := map[string]any{
+		EditorKey:                 "nvim",
+		MaxHistoryLengthKey:       int64(1000),
+		MaxHistoryDaysKey:         "ninety",
+		AutoUpdateKey:             "yes",
+		UppercaseKeywordsKey:      "true",
+		UpdateCheckInterval:       12.5,
+		LeaderKey:                 "ctrl+",
+		LLMProviderKey:            "OpenAI",
+		DisplayTimeZoneKey:        "Mars/Olympus",
+		HighlightRulesKey:         []any{"status = 'failed' -> red", int64(3)},
+		CommandAliasesKey:         []any{},
+		"pool_max_conns":          int64(8),
+		"pool_max_conn_idle_time": "soon",
+		"pset_border":             "3",
+		"pset_null":               "∅",
+		"gemini_temperature":      "",
+		"gemini_timeout":          "45s",
+		"vertexai_max_tokens":     "-5",
+		"edtor":                   "vim",
+		"theme":                   "dark",
+	}
+
+	lines := map[string]int{MaxHistoryDaysKey: 4, AutoUpdateKey: 2, "edtor": 9}
+
+	var messages []string
+	for _, issue := range validateSettings(settings, lines) {
+		messages = append(messages, issue.Error())
+	}
+
+	assert.Equal(t, []string{
+		`display_timezone: unknown time zone "Mars/Olympus", expected a name such as "UTC", "Local" or "Europe/London"`,
+		`highlight_rules: expected a list of strings, item 2 is the number 3`,
+		`leader_key: invalid leader key "ctrl+", expected a single character such as "," or a key such as "space", "ctrl+a" or "f2"`,
+		`llm_provider: unsupported LLM provider "OpenAI", expected one of gemini, vertexai`,
+		`pool_max_conn_idle_time: invalid duration "soon": expected a duration such as 30s or 5m`,
+		`pset_border: \pset: border must be 0, 1 or 2`,
+		`theme: unknown key`,
+		`vertexai_max_tokens: invalid max tokens "-5": expected a positive integer`,
+		`line 2: auto_update: expected true or false, got "yes"`,
+		`line 4: max_history_days: expected a whole number, got "ninety"`,
+		`line 9: edtor: unknown key, did you mean editor?`,
+	}, messages)
+}
+
+func TestValidateEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"PERP_EDITOR":              "nano",
+		"PERP_MAX_HISTORY_DAYS":    "30",
+		"PERP_AUTO_UPDATE":         "off",
+		"PERP_COMMAND_ALIASES":     `["x = export * out.json"]`,
+		"PERP_HIGHLIGHT_RULES":     "status = 'failed' -> red",
+		"PERP_LLM_PROVIDER":        "",
+		"PERP_DISPLAY_TIMEZONE":    "Europe/London",
+		"PERP_POOL_MAX_CONNS":      "-2",
+		"PERP_UNKNOWN_CONFIG_KEYS": "ignored",
+	}
+
+	var messages []string
+	for _, issue := range validateEnv(func(name string) string { return env[name] }) {
+		messages = append(messages, issue.Error())
+	}
+
+	assert.Equal(t, []string{
+		`PERP_AUTO_UPDATE: expected true or false, got "off"`,
+		`PERP_HIGHLIGHT_RULES: expected a JSON array of strings, e.g. ["a", "b"]`,
+		`PERP_POOL_MAX_CONNS: invalid maximum connections "-2": expected a positive integer`,
+	}, messages)
+}
+
+func TestEnvVar(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "PERP_EDITOR", EnvVar(EditorKey))
+	assert.Equal(t, "PERP_GEMINI_TIMEOUT", EnvVar(llmSettingKey("gemini", LLMTimeoutSetting)))
+}
+
+func TestGetStrings(t *testing.T) {
+	t.Setenv(EnvVar(CommandAliasesKey), `["x = export * out.json", "y = \\dt"]`)
+
+	values, err := getStrings(CommandAliasesKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x = export * out.json", `y = \dt`}, values)
+
+	t.Setenv(EnvVar(HighlightRulesKey), "status = 'failed' -> red")
+
+	_, err = getStrings(HighlightRulesKey)
+	require.EqualError(t, err, `PERP_HIGHLIGHT_RULES: expected a JSON array of strings, e.g. ["a", "b"]`,
+		"not split on spaces")
+}
+
+func TestCheckLeaderKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{" ", "", ",", "\\", "space", "ctrl+a", "alt+space", "ctrl+alt+x", "f2", "f12", "+"} {
+		assert.NoError(t, checkLeaderKey(key), key)
+	}
+
+	for _, key := range []string{"ctrl+", "esc", "f13", "leader", "ctrl+ab"} {
+		assert.Error(t, checkLeaderKey(key), key)
+	}
+}
+
+func TestKeyLines(t *testing.T) {
+	t.Parallel()
+
+	content := "# perp\nauto_update = true\n\n  editor = \"vim\" # editor = \"nano\"\n\"leader_key\" = \" \"\n"
+
+	assert.Equal(t, map[string]int{"auto_update": 2, "editor": 4, "leader_key": 5}, keyLines(content))
+}
+
+func TestClosestName(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"editor", "leader_key", "llm_model", "pool_max_conns"}
+
+	assert.Equal(t, "leader_key", closestName("leaderkey", names))
+	assert.Equal(t, "pool_max_conns", closestName("pool_max_con", names))
+	assert.Equal(t, "", closestName("theme", names))
+}
@@ -12,6 +12,11 @@ var ForceQuit = key.NewBinding(
 	key.WithHelp("ctrl+c", "force quit"),
 )
 
+var Suspend = key.NewBinding(
+	key.WithKeys("ctrl+z"),
+	key.WithHelp("ctrl+z", "suspend"),
+)
+
 var Editor = key.NewBinding(
 	key.WithKeys("ctrl+e"),
 	key.WithHelp("ctrl+e", "open in external editor"),
@@ -7,25 +7,47 @@ type MenuContext struct {
 	ServerName  string
 
 	// View state
-	InServersView   bool
-	InExportView    bool
-	InMainView      bool
-	InHistoryView   bool
-	InSnippetsView  bool
-	FocusedOnTable  bool
-	FocusedOnEditor bool
-	IsFullScreen    bool
-	IsHelpVisible   bool
+	InServersView     bool
+	InExportView      bool
+	InMainView        bool
+	InHistoryView     bool
+	InSnippetsView    bool
+	InLLMExamplesView bool
+	FocusedOnTable    bool
+	FocusedOnEditor   bool
+	IsFullScreen      bool
+	IsHelpVisible     bool
 
 	// Data state
-	HasQueryResults bool
-	HasHistory      bool
-	ResultCount     int
-	HistoryCount    int
+	HasQueryResults  bool
+	HasPinnedResults bool // results are pinned next to the current ones
+	InVisualMode     bool // cells of the results are being selected
+	HasHistory       bool
+	ResultCount      int
+	HistoryCount     int
 
 	// Feature availability
-	LLMEnabled      bool
-	LLMSchemaShared bool
+	LLMEnabled             bool
+	LLMSchemaShared        bool
+	LLMTemperature         string
+	LLMMaxTokens           string
+	LLMTimeout             string
+	HasLLMExampleCandidate bool
+	HasSelectedPlanNode    bool
+
+	// Data quality
+	HasRowsQuery          bool // a row of a data quality report is selected
+	HasNotValidConstraint bool // a row of the NOT VALID constraints report is selected
+
+	// Statistics
+	HasDropSuggestions bool // the index usage report has unused indexes
+
+	// Editor settings
+	UppercaseKeywords bool // SQL keywords are upper-cased as they are typed
+
+	// Workspaces
+	Workspace  string   // name of the active workspace
+	Workspaces []string // names of the saved workspaces
 
 	// Update availability
 	HasUpdate bool
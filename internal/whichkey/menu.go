@@ -110,4 +110,6 @@ type ShowSubmenuMsg struct {
 // ExecuteAndCloseMsg carries both an action message and signals menu closure
 type ExecuteAndCloseMsg struct {
 	ActionMsg tea.Msg
+	Menu      *Menu    // the menu the action was reached from
+	Keys      []string // the keys typed from Menu to reach the action
 }
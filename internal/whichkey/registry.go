@@ -1,28 +1,34 @@
 package whichkey
 
 import (
+	"fmt"
 	"slices"
+	"strconv"
 
 	tea "charm.land/bubbletea/v2"
 )
 
 // Registry manages all available menus
 type Registry struct {
-	context      *MenuContext
-	rootMenu     *Menu
-	serverMenu   *Menu
-	exportMenu   *Menu
-	llmMenu      *Menu
-	databaseMenu *Menu
-	historyMenu  *Menu
-	snippetsMenu *Menu
-	configMenu   *Menu
+	context       *MenuContext
+	rootMenu      *Menu
+	serverMenu    *Menu
+	exportMenu    *Menu
+	llmMenu       *Menu
+	databaseMenu  *Menu
+	historyMenu   *Menu
+	snippetsMenu  *Menu
+	configMenu    *Menu
+	workspaceMenu *Menu
+	resultsMenu   *Menu
+	usage         map[string]int // runs of the actions of the root menu by key path
 }
 
 // NewRegistry creates a new menu registry with all menus
 func NewRegistry() *Registry {
 	r := &Registry{
 		context: NewMenuContext(),
+		usage:   map[string]int{},
 	}
 	r.buildMenus()
 	return r
@@ -56,6 +62,12 @@ func (r *Registry) buildExportsMenu() *Menu {
 					Description: "Open in external editor",
 					Action:      CommandAction{Cmd: ExternalEditorCmd},
 				},
+				{
+					Key:         "p",
+					Label:       "Open in pane",
+					Description: "View the record in a new tmux/wezterm pane",
+					Action:      CommandAction{Cmd: OpenInPaneCmd},
+				},
 				{
 					Key:         "c",
 					Label:       "Close",
@@ -101,6 +113,12 @@ func (r *Registry) buildExportsMenu() *Menu {
 					Description: "Export all rows in CSV format",
 					Action:      CommandAction{Cmd: ExportCSVCmd},
 				},
+				MenuItem{
+					Key:         "p",
+					Label:       "Open in pane",
+					Description: "View all rows in a new tmux/wezterm pane",
+					Action:      CommandAction{Cmd: OpenInPaneCmd},
+				},
 			)
 		}
 
@@ -123,6 +141,54 @@ func (r *Registry) buildLLMMenu() *Menu {
 				Description: "Switch LLM model",
 				Action:      CommandAction{Cmd: ChangeLLMModelCmd},
 			},
+			{
+				Key:         "t",
+				Label:       fmt.Sprintf("Temperature (%s)", r.context.LLMTemperature),
+				Description: "Set the generation temperature",
+				Action:      CommandAction{Cmd: SetLLMTemperatureCmd},
+			},
+			{
+				Key:         "k",
+				Label:       fmt.Sprintf("Max tokens (%s)", r.context.LLMMaxTokens),
+				Description: "Set the maximum response tokens",
+				Action:      CommandAction{Cmd: SetLLMMaxTokensCmd},
+			},
+			{
+				Key:         "o",
+				Label:       fmt.Sprintf("Timeout (%s)", r.context.LLMTimeout),
+				Description: "Set the request timeout",
+				Action:      CommandAction{Cmd: SetLLMTimeoutCmd},
+			},
+			{
+				Key:         "x",
+				Label:       "Examples",
+				Description: "Manage few-shot examples",
+				Action:      CommandAction{Cmd: ListLLMExamplesCmd},
+			},
+			{
+				Key:         "g",
+				Label:       "Schema tour",
+				Description: "Walk through the major tables",
+				Action:      CommandAction{Cmd: SchemaTourCmd},
+			},
+		}
+
+		if r.context.HasSelectedPlanNode {
+			items = append(items, MenuItem{
+				Key:         "p",
+				Label:       "Ask about plan node",
+				Description: "Ask why the selected plan node is slow",
+				Action:      CommandAction{Cmd: ExplainPlanNodeCmd},
+			})
+		}
+
+		if r.context.HasLLMExampleCandidate {
+			items = append(items, MenuItem{
+				Key:         "a",
+				Label:       "Keep as example",
+				Description: "Save the last /ask query as an example",
+				Action:      CommandAction{Cmd: SaveLLMExampleCmd},
+			})
 		}
 
 		if r.context.LLMSchemaShared {
@@ -146,51 +212,159 @@ func (r *Registry) buildLLMMenu() *Menu {
 }
 
 func (r *Registry) buildDatabaseMenu() *Menu {
-	return NewMenu("Database Operations", []MenuItem{
-		{
-			Key:         "s",
-			Label:       "View schema",
-			Description: "Display database schema",
-			Action: CommandAction{
-				Cmd: ViewSchemaCmd,
-				Validator: func(ctx *MenuContext) bool {
-					return ctx.IsConnected
+	return NewDynamicMenu("Database Operations", func() []MenuItem {
+		items := []MenuItem{
+			{
+				Key:         "s",
+				Label:       "View schema",
+				Description: "Display database schema",
+				Action: CommandAction{
+					Cmd: ViewSchemaCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
 				},
 			},
-		},
-		{
-			Key:         "t",
-			Label:       "List tables",
-			Description: "Show all tables",
-			Action: CommandAction{
-				Cmd: ListTablesCmd,
-				Validator: func(ctx *MenuContext) bool {
-					return ctx.IsConnected
+			{
+				Key:         "t",
+				Label:       "List tables",
+				Description: "Show all tables",
+				Action: CommandAction{
+					Cmd: ListTablesCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
 				},
 			},
-		},
-		{
-			Key:         "i",
-			Label:       "View indexes",
-			Description: "Show table indexes",
-			Action: CommandAction{
-				Cmd: ViewIndexesCmd,
-				Validator: func(ctx *MenuContext) bool {
-					return ctx.IsConnected
+			{
+				Key:         "i",
+				Label:       "View indexes",
+				Description: "Show table indexes",
+				Action: CommandAction{
+					Cmd: ViewIndexesCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
 				},
 			},
-		},
-		{
-			Key:         "c",
-			Label:       "View constraints",
-			Description: "Show table constraints",
-			Action: CommandAction{
-				Cmd: ViewConstraintsCmd,
-				Validator: func(ctx *MenuContext) bool {
-					return ctx.IsConnected
+			{
+				Key:         "c",
+				Label:       "View constraints",
+				Description: "Show table constraints",
+				Action: CommandAction{
+					Cmd: ViewConstraintsCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
 				},
 			},
-		},
+			{
+				Key:         "b",
+				Label:       "Build a query",
+				Description: "Pick a table, columns, filters and order to write a SELECT",
+				Action: CommandAction{
+					Cmd: BuildQueryCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "d",
+				Label:       "Find duplicates",
+				Description: "Find rows repeating the values of some columns",
+				Action: CommandAction{
+					Cmd: FindDuplicatesCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "o",
+				Label:       "Find orphaned rows",
+				Description: "Find rows whose foreign key parent is missing",
+				Action: CommandAction{
+					Cmd: FindOrphansCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "v",
+				Label:       "NOT VALID constraints",
+				Description: "List the constraints not validated yet",
+				Action: CommandAction{
+					Cmd: ListNotValidConstraintsCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "u",
+				Label:       "Index usage",
+				Description: "Show index scans and sizes, flagging unused indexes",
+				Action: CommandAction{
+					Cmd: IndexUsageCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "h",
+				Label:       "Health",
+				Description: "Show cache hits, connections, deadlocks and replication lag",
+				Action: CommandAction{
+					Cmd: DatabaseHealthCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+			{
+				Key:         "w",
+				Label:       "WAL and checkpoints",
+				Description: "Show WAL and checkpoint counters with the change since the last refresh",
+				Action: CommandAction{
+					Cmd: WALStatsCmd,
+					Validator: func(ctx *MenuContext) bool {
+						return ctx.IsConnected
+					},
+				},
+			},
+		}
+
+		if r.context.HasRowsQuery {
+			items = append(items, MenuItem{
+				Key:         "r",
+				Label:       "Show rows",
+				Description: "List the rows behind the selected report row",
+				Action:      CommandAction{Cmd: ShowRowsCmd},
+			})
+		}
+
+		if r.context.HasNotValidConstraint {
+			items = append(items, MenuItem{
+				Key:         "V",
+				Label:       "Validate constraint",
+				Description: "Validate the selected NOT VALID constraint",
+				Action:      CommandAction{Cmd: ValidateConstraintCmd},
+			})
+		}
+
+		if r.context.HasDropSuggestions {
+			items = append(items, MenuItem{
+				Key:         "D",
+				Label:       "Review DROP INDEX",
+				Description: "Put the DROP statements of the unused indexes in the editor",
+				Action:      CommandAction{Cmd: ReviewDropIndexesCmd},
+			})
+		}
+
+		return items
 	})
 }
 
@@ -289,118 +463,111 @@ func (r *Registry) buildSnippetsMenu() *Menu {
 }
 
 func (r *Registry) buildConfigMenu() *Menu {
-	return NewMenu("Configuration", []MenuItem{
-		{
-			Key:         "e",
-			Label:       "External editor",
-			Description: "Set external editor",
-			Action:      CommandAction{Cmd: SetEditorCmd},
-		},
-		{
-			Key:         "l",
-			Label:       "Leader key",
-			Description: "Change leader key",
-			Action:      CommandAction{Cmd: ChangeLeaderCmd},
-		},
+	return NewDynamicMenu("Configuration", func() []MenuItem {
+		keywordCase := "off"
+		if r.context.UppercaseKeywords {
+			keywordCase = "on"
+		}
+
+		return []MenuItem{
+			{
+				Key:         "e",
+				Label:       "External editor",
+				Description: "Set external editor",
+				Action:      CommandAction{Cmd: SetEditorCmd},
+			},
+			{
+				Key:         "l",
+				Label:       "Leader key",
+				Description: "Change leader key",
+				Action:      CommandAction{Cmd: ChangeLeaderCmd},
+			},
+			{
+				Key:         "k",
+				Label:       fmt.Sprintf("Uppercase keywords (%s)", keywordCase),
+				Description: "Upper-case SQL keywords as you type",
+				Action:      CommandAction{Cmd: ToggleUppercaseKeywordsCmd},
+			},
+			{
+				Key:         "u",
+				Label:       "Storage usage",
+				Description: "Show the disk usage per server",
+				Action:      CommandAction{Cmd: StorageUsageCmd},
+			},
+		}
 	})
 }
 
-func (r *Registry) buildRootMenu() *Menu {
-	return NewDynamicMenu("Perp Commands", func() []MenuItem {
-		fullScreenLabel := "Enter full-screen"
-		helpLabel := "Show help"
-
-		if r.context.IsFullScreen {
-			fullScreenLabel = "Exit full-screen"
+func (r *Registry) buildWorkspaceMenu() *Menu {
+	return NewDynamicMenu("Workspaces", func() []MenuItem {
+		items := []MenuItem{
+			{
+				Key:         "s",
+				Label:       "Save workspace",
+				Description: "Save the server, buffer and layout",
+				Action:      CommandAction{Cmd: SaveWorkspaceCmd},
+			},
 		}
 
-		if r.context.IsHelpVisible {
-			helpLabel = "Hide help"
+		if r.context.Workspace != "" {
+			items = append(items, MenuItem{
+				Key:         "d",
+				Label:       fmt.Sprintf("Delete %s", r.context.Workspace),
+				Description: "Delete the active workspace",
+				Action:      CommandAction{Cmd: DeleteWorkspaceCmd},
+			})
 		}
 
-		// In servers view - only show quit
-		if r.context.InServersView {
-			return []MenuItem{
-				{
-					Key:         "q",
-					Label:       "Quit",
-					Description: "Exit application",
-					Action:      CommandAction{Cmd: QuitCmd},
-				},
+		// the first nine workspaces are switched to with their number
+		for i, name := range r.context.Workspaces {
+			if i == 9 {
+				break
 			}
-		}
 
-		// In export view - return export menu items (which are context-aware)
-		if r.context.InExportView {
-			return r.exportMenu.GetItems()
-		}
+			description := "Switch to this workspace"
+			if name == r.context.Workspace {
+				description = "Active workspace"
+			}
 
-		// In history view - return history menu items (which are context-aware)
-		if r.context.InHistoryView {
-			return r.historyMenu.GetItems()
+			items = append(items, MenuItem{
+				Key:         strconv.Itoa(i + 1),
+				Label:       name,
+				Description: description,
+				Action:      CommandAction{Cmd: SwitchWorkspaceCmd(name)},
+			})
 		}
 
-		// In snippets view - return snippets menu items (which are context-aware)
-		if r.context.InSnippetsView {
-			return r.snippetsMenu.GetItems()
+		return items
+	})
+}
+
+// buildRootMenu builds the menu the leader key opens, its most used actions
+// first in the main view
+func (r *Registry) buildRootMenu() *Menu {
+	return NewDynamicMenu("Perp Commands", func() []MenuItem {
+		if !r.context.InMainView {
+			return r.rootItems()
 		}
+		return append(r.frequentItems(), r.rootItems()...)
+	})
+}
 
-		items := []MenuItem{
-			{
-				Key:         "d",
-				Label:       "Database",
-				Description: "Database schema operations",
-				Action:      SubmenuAction{Menu: r.databaseMenu},
-			},
-			{
-				Key:         "e",
-				Label:       "Export",
-				Description: "Export query results",
-				Action:      SubmenuAction{Menu: r.exportMenu},
-			},
-			{
-				Key:         "h",
-				Label:       "History",
-				Description: "Query history management",
-				Action:      SubmenuAction{Menu: r.historyMenu},
-			},
-			{
-				Key:         "s",
-				Label:       "Snippets",
-				Description: "SQL snippet library",
-				Action:      SubmenuAction{Menu: r.snippetsMenu},
-			},
-			{
-				Key:         "l",
-				Label:       "LLM",
-				Description: "AI-powered SQL assistance",
-				Action:      SubmenuAction{Menu: r.llmMenu},
-			},
-			{
-				Key:         "S",
-				Label:       "Servers",
-				Description: "Manage database connections",
-				Action:      SubmenuAction{Menu: r.serverMenu},
-			},
+// rootItems returns the items of the root menu in the current context
+func (r *Registry) rootItems() []MenuItem {
+	fullScreenLabel := "Enter full-screen"
+	helpLabel := "Show help"
 
-			{
-				Key:         "c",
-				Label:       "Config",
-				Description: "Application settings",
-				Action:      SubmenuAction{Menu: r.configMenu},
-			},
-			{
-				Key:         "f",
-				Label:       fullScreenLabel,
-				Description: "Toggle full-screen mode",
-				Action:      CommandAction{Cmd: ToggleFullscreenCmd},
-			},
-			{
-				Key:         "?",
-				Label:       helpLabel,
-				Description: "Toggle help",
-				Action:      CommandAction{Cmd: ToggleHelpCmd},
-			},
+	if r.context.IsFullScreen {
+		fullScreenLabel = "Exit full-screen"
+	}
+
+	if r.context.IsHelpVisible {
+		helpLabel = "Hide help"
+	}
+
+	// In servers view - only show quit
+	if r.context.InServersView {
+		return []MenuItem{
 			{
 				Key:         "q",
 				Label:       "Quit",
@@ -408,33 +575,260 @@ func (r *Registry) buildRootMenu() *Menu {
 				Action:      CommandAction{Cmd: QuitCmd},
 			},
 		}
+	}
 
-		if !r.context.LLMEnabled {
-			items = slices.DeleteFunc(items, func(item MenuItem) bool {
-				return item.Label == "llm"
-			})
+	// In export view - return export menu items (which are context-aware)
+	if r.context.InExportView {
+		return r.exportMenu.GetItems()
+	}
+
+	// In history view - return history menu items (which are context-aware)
+	if r.context.InHistoryView {
+		return r.historyMenu.GetItems()
+	}
+
+	// In snippets view - return snippets menu items (which are context-aware)
+	if r.context.InSnippetsView {
+		return r.snippetsMenu.GetItems()
+	}
+
+	if r.context.InLLMExamplesView {
+		return []MenuItem{
+			{
+				Key:         "c",
+				Label:       "Close",
+				Description: "Close LLM examples",
+				Action:      CommandAction{Cmd: CloseLLMExamplesCmd},
+			},
 		}
+	}
 
+	items := []MenuItem{
+		{
+			Key:         "d",
+			Label:       "Database",
+			Description: "Database schema operations",
+			Action:      SubmenuAction{Menu: r.databaseMenu},
+		},
+		{
+			Key:         "e",
+			Label:       "Export",
+			Description: "Export query results",
+			Action:      SubmenuAction{Menu: r.exportMenu},
+		},
+		{
+			Key:         "h",
+			Label:       "History",
+			Description: "Query history management",
+			Action:      SubmenuAction{Menu: r.historyMenu},
+		},
+		{
+			Key:         "s",
+			Label:       "Snippets",
+			Description: "SQL snippet library",
+			Action:      SubmenuAction{Menu: r.snippetsMenu},
+		},
+		{
+			Key:         "l",
+			Label:       "LLM",
+			Description: "AI-powered SQL assistance",
+			Action:      SubmenuAction{Menu: r.llmMenu},
+		},
+		{
+			Key:         "S",
+			Label:       "Servers",
+			Description: "Manage database connections",
+			Action:      SubmenuAction{Menu: r.serverMenu},
+		},
+
+		{
+			Key:         "w",
+			Label:       "Workspaces",
+			Description: "Save and switch workspaces",
+			Action:      SubmenuAction{Menu: r.workspaceMenu},
+		},
+		{
+			Key:         "c",
+			Label:       "Config",
+			Description: "Application settings",
+			Action:      SubmenuAction{Menu: r.configMenu},
+		},
+		{
+			Key:         "f",
+			Label:       "Find",
+			Description: "Search snippets, exports, servers and history",
+			Action:      CommandAction{Cmd: FindCmd},
+		},
+		{
+			Key:         "/",
+			Label:       "Search files",
+			Description: "Search the text of the exports and snippets",
+			Action:      CommandAction{Cmd: SearchFilesCmd},
+		},
+		{
+			Key:         "F",
+			Label:       fullScreenLabel,
+			Description: "Toggle full-screen mode",
+			Action:      CommandAction{Cmd: ToggleFullscreenCmd},
+		},
+		{
+			Key:         "?",
+			Label:       helpLabel,
+			Description: "Toggle help",
+			Action:      CommandAction{Cmd: ToggleHelpCmd},
+		},
+		{
+			Key:         "q",
+			Label:       "Quit",
+			Description: "Exit application",
+			Action:      CommandAction{Cmd: QuitCmd},
+		},
+	}
+
+	if !r.context.LLMEnabled {
+		items = slices.DeleteFunc(items, func(item MenuItem) bool {
+			return item.Label == "llm"
+		})
+	}
+
+	items = append(items, MenuItem{
+		Key:         "u",
+		Label:       "Release notes",
+		Description: "Show the changelog of the installed version",
+		Action:      CommandAction{Cmd: ShowReleaseNotesCmd},
+	})
+
+	if r.context.HasUpdate {
 		items = append(items, MenuItem{
-			Key:         "u",
-			Label:       "Release notes",
+			Key:         "o",
+			Label:       "Open release",
 			Description: "View latest release in browser",
 			Action:      CommandAction{Cmd: OpenReleaseCmd},
 		})
 
-		if r.context.HasUpdate {
-			items = append(items, MenuItem{
-				Key:         "x",
-				Label:       "Dismiss update",
-				Description: "Hide the update notification",
-				Action:      CommandAction{Cmd: DismissUpdateCmd},
-			})
+		items = append(items, MenuItem{
+			Key:         "i",
+			Label:       "Install update",
+			Description: "Download and install the latest release",
+			Action:      CommandAction{Cmd: InstallUpdateCmd},
+		})
+
+		items = append(items, MenuItem{
+			Key:         "x",
+			Label:       "Dismiss update",
+			Description: "Hide the update notification",
+			Action:      CommandAction{Cmd: DismissUpdateCmd},
+		})
+	}
+
+	return items
+}
+
+// buildResultsMenu builds the menu shown instead of the root menu while the
+// results table is focused: the actions on the results, followed by the root
+// menu items their keys leave free
+func (r *Registry) buildResultsMenu() *Menu {
+	return NewDynamicMenu("Results", func() []MenuItem {
+		items := r.resultsItems()
+
+		for _, item := range r.rootMenu.GetItems() {
+			if !hasKey(items, item.Key) {
+				items = append(items, item)
+			}
 		}
 
 		return items
 	})
 }
 
+// resultsItems returns the actions on the results of the results menu
+func (r *Registry) resultsItems() []MenuItem {
+	var items []MenuItem
+
+	yankDescription := "Copy all rows in a format"
+	if r.context.InVisualMode {
+		yankDescription = "Copy the selected cells in a format"
+
+		items = append(items, MenuItem{
+			Key:         "e",
+			Label:       "Export selection",
+			Description: "Export the selected cells to a file",
+			Action:      CommandAction{Cmd: ExportSelectionCmd},
+		})
+	}
+
+	pinLabel := "Pin results"
+	if r.context.HasPinnedResults {
+		pinLabel = "Unpin results"
+	}
+
+	items = append(items,
+		MenuItem{
+			Key:         "y",
+			Label:       "Yank as",
+			Description: yankDescription,
+			Action:      SubmenuAction{Menu: r.yankMenu()},
+		},
+		MenuItem{
+			Key:         "Q",
+			Label:       "Yank query",
+			Description: "Copy the query of the results",
+			Action:      CommandAction{Cmd: YankResultsQueryCmd},
+		},
+		MenuItem{
+			Key:         "I",
+			Label:       "Statistics",
+			Description: "Rows, timing and planner estimate",
+			Action:      CommandAction{Cmd: ShowResultInfoCmd},
+		},
+		MenuItem{
+			Key:         "p",
+			Label:       pinLabel,
+			Description: "Compare with the next results side by side",
+			Action:      CommandAction{Cmd: PinResultsCmd},
+		},
+		MenuItem{
+			Key:         "R",
+			Label:       "Toggle raw numbers",
+			Description: "Show numbers with or without separators",
+			Action:      CommandAction{Cmd: ToggleRawNumbersCmd},
+		},
+	)
+
+	return items
+}
+
+// hasKey reports whether one of items is opened with key
+func hasKey(items []MenuItem, key string) bool {
+	return slices.ContainsFunc(items, func(item MenuItem) bool { return item.Key == key })
+}
+
+// yankMenu lists the formats the rows, or the selected cells, are copied in
+func (r *Registry) yankMenu() *Menu {
+	menu := NewMenu("Yank as", []MenuItem{
+		{
+			Key:         "j",
+			Label:       "JSON",
+			Description: "An array of objects",
+			Action:      CommandAction{Cmd: YankResultsCmd(YankJSON)},
+		},
+		{
+			Key:         "c",
+			Label:       "CSV",
+			Description: "Comma-separated values with a header",
+			Action:      CommandAction{Cmd: YankResultsCmd(YankCSV)},
+		},
+		{
+			Key:         "t",
+			Label:       "TSV",
+			Description: "Tab-separated values, e.g. for spreadsheets",
+			Action:      CommandAction{Cmd: YankResultsCmd(YankTSV)},
+		},
+	})
+	menu.SetParent(r.resultsMenu)
+	return menu
+}
+
 // buildMenus constructs the menu hierarchy
 func (r *Registry) buildMenus() {
 	r.serverMenu = r.buildServersMenu()
@@ -444,7 +838,9 @@ func (r *Registry) buildMenus() {
 	r.historyMenu = r.buildHistoryMenu()
 	r.snippetsMenu = r.buildSnippetsMenu()
 	r.configMenu = r.buildConfigMenu()
+	r.workspaceMenu = r.buildWorkspaceMenu()
 	r.rootMenu = r.buildRootMenu()
+	r.resultsMenu = r.buildResultsMenu()
 
 	// Set parent references for navigation
 	r.serverMenu.SetParent(r.rootMenu)
@@ -454,6 +850,7 @@ func (r *Registry) buildMenus() {
 	r.historyMenu.SetParent(r.rootMenu)
 	r.snippetsMenu.SetParent(r.rootMenu)
 	r.configMenu.SetParent(r.rootMenu)
+	r.workspaceMenu.SetParent(r.rootMenu)
 }
 
 // GetRootMenu returns the root menu
@@ -461,6 +858,15 @@ func (r *Registry) GetRootMenu() *Menu {
 	return r.rootMenu
 }
 
+// GetContextMenu returns the menu the leader key opens: the results menu
+// while the results table is focused, the root menu otherwise
+func (r *Registry) GetContextMenu() *Menu {
+	if r.context.InMainView && r.context.FocusedOnTable && r.context.HasQueryResults {
+		return r.resultsMenu
+	}
+	return r.rootMenu
+}
+
 // GetMenu returns a specific menu by type
 func (r *Registry) GetMenu(menuType string) *Menu {
 	switch menuType {
@@ -476,6 +882,10 @@ func (r *Registry) GetMenu(menuType string) *Menu {
 		return r.historyMenu
 	case "config":
 		return r.configMenu
+	case "workspace":
+		return r.workspaceMenu
+	case "results":
+		return r.resultsMenu
 	default:
 		return r.rootMenu
 	}
@@ -496,6 +906,7 @@ type (
 	BackToMainMsg     struct{}
 	CloseExportMsg    struct{}
 	ExternalEditorMsg struct{}
+	OpenInPaneMsg     struct{}
 )
 
 func ListExportsCmd() tea.Msg    { return ListExportsMsg{} }
@@ -503,32 +914,98 @@ func ExportJSONCmd() tea.Msg     { return ExportJSONMsg{} }
 func ExportCSVCmd() tea.Msg      { return ExportCSVMsg{} }
 func CloseExportCmd() tea.Msg    { return CloseExportMsg{} }
 func ExternalEditorCmd() tea.Msg { return ExternalEditorMsg{} }
+func OpenInPaneCmd() tea.Msg     { return OpenInPaneMsg{} }
+
+// Results actions
+type (
+	ExportSelectionMsg  struct{}
+	YankResultsMsg      struct{ Format YankFormat }
+	YankResultsQueryMsg struct{}
+	ShowResultInfoMsg   struct{}
+	PinResultsMsg       struct{}
+	ToggleRawNumbersMsg struct{}
+)
+
+// YankFormat is the format results are copied to the clipboard in
+type YankFormat int
+
+const (
+	YankJSON YankFormat = iota
+	YankCSV
+	YankTSV
+)
+
+func ExportSelectionCmd() tea.Msg  { return ExportSelectionMsg{} }
+func YankResultsQueryCmd() tea.Msg { return YankResultsQueryMsg{} }
+func ShowResultInfoCmd() tea.Msg   { return ShowResultInfoMsg{} }
+func PinResultsCmd() tea.Msg       { return PinResultsMsg{} }
+func ToggleRawNumbersCmd() tea.Msg { return ToggleRawNumbersMsg{} }
+
+func YankResultsCmd(format YankFormat) func() tea.Msg {
+	return func() tea.Msg { return YankResultsMsg{Format: format} }
+}
 
 // LLM actions
 type (
-	ViewLLMSchemaMsg   struct{}
-	ChangeLLMModelMsg  struct{}
-	EnableDBSchemaMsg  struct{}
-	DisableDBSchemaMsg struct{}
+	ViewLLMSchemaMsg     struct{}
+	ChangeLLMModelMsg    struct{}
+	EnableDBSchemaMsg    struct{}
+	DisableDBSchemaMsg   struct{}
+	SetLLMTemperatureMsg struct{}
+	SetLLMMaxTokensMsg   struct{}
+	SetLLMTimeoutMsg     struct{}
+	ListLLMExamplesMsg   struct{}
+	SaveLLMExampleMsg    struct{}
+	CloseLLMExamplesMsg  struct{}
+	ExplainPlanNodeMsg   struct{}
+	SchemaTourMsg        struct{}
 )
 
-func ViewLLMSchemaCmd() tea.Msg   { return ViewLLMSchemaMsg{} }
-func ChangeLLMModelCmd() tea.Msg  { return ChangeLLMModelMsg{} }
-func EnableDBSchemaCmd() tea.Msg  { return EnableDBSchemaMsg{} }
-func DisableDBSchemaCmd() tea.Msg { return DisableDBSchemaMsg{} }
+func ViewLLMSchemaCmd() tea.Msg     { return ViewLLMSchemaMsg{} }
+func ChangeLLMModelCmd() tea.Msg    { return ChangeLLMModelMsg{} }
+func EnableDBSchemaCmd() tea.Msg    { return EnableDBSchemaMsg{} }
+func DisableDBSchemaCmd() tea.Msg   { return DisableDBSchemaMsg{} }
+func SetLLMTemperatureCmd() tea.Msg { return SetLLMTemperatureMsg{} }
+func SetLLMMaxTokensCmd() tea.Msg   { return SetLLMMaxTokensMsg{} }
+func SetLLMTimeoutCmd() tea.Msg     { return SetLLMTimeoutMsg{} }
+func ListLLMExamplesCmd() tea.Msg   { return ListLLMExamplesMsg{} }
+func SaveLLMExampleCmd() tea.Msg    { return SaveLLMExampleMsg{} }
+func CloseLLMExamplesCmd() tea.Msg  { return CloseLLMExamplesMsg{} }
+func ExplainPlanNodeCmd() tea.Msg   { return ExplainPlanNodeMsg{} }
+func SchemaTourCmd() tea.Msg        { return SchemaTourMsg{} }
 
 // Database actions
 type (
-	ViewSchemaMsg      struct{}
-	ListTablesMsg      struct{}
-	ViewIndexesMsg     struct{}
-	ViewConstraintsMsg struct{}
+	ViewSchemaMsg              struct{}
+	ListTablesMsg              struct{}
+	ViewIndexesMsg             struct{}
+	ViewConstraintsMsg         struct{}
+	FindDuplicatesMsg          struct{}
+	FindOrphansMsg             struct{}
+	ShowRowsMsg                struct{}
+	ListNotValidConstraintsMsg struct{}
+	ValidateConstraintMsg      struct{}
+	IndexUsageMsg              struct{}
+	ReviewDropIndexesMsg       struct{}
+	DatabaseHealthMsg          struct{}
+	WALStatsMsg                struct{}
+	BuildQueryMsg              struct{}
 )
 
-func ViewSchemaCmd() tea.Msg      { return ViewSchemaMsg{} }
-func ListTablesCmd() tea.Msg      { return ListTablesMsg{} }
-func ViewIndexesCmd() tea.Msg     { return ViewIndexesMsg{} }
-func ViewConstraintsCmd() tea.Msg { return ViewConstraintsMsg{} }
+func ViewSchemaCmd() tea.Msg              { return ViewSchemaMsg{} }
+func ListTablesCmd() tea.Msg              { return ListTablesMsg{} }
+func ViewIndexesCmd() tea.Msg             { return ViewIndexesMsg{} }
+func ViewConstraintsCmd() tea.Msg         { return ViewConstraintsMsg{} }
+func FindDuplicatesCmd() tea.Msg          { return FindDuplicatesMsg{} }
+func FindOrphansCmd() tea.Msg             { return FindOrphansMsg{} }
+func ShowRowsCmd() tea.Msg                { return ShowRowsMsg{} }
+func ListNotValidConstraintsCmd() tea.Msg { return ListNotValidConstraintsMsg{} }
+func ValidateConstraintCmd() tea.Msg      { return ValidateConstraintMsg{} }
+func IndexUsageCmd() tea.Msg              { return IndexUsageMsg{} }
+func ReviewDropIndexesCmd() tea.Msg       { return ReviewDropIndexesMsg{} }
+func DatabaseHealthCmd() tea.Msg          { return DatabaseHealthMsg{} }
+func WALStatsCmd() tea.Msg                { return WALStatsMsg{} }
+func BuildQueryCmd() tea.Msg              { return BuildQueryMsg{} }
 
 // History actions
 type (
@@ -556,12 +1033,41 @@ func SnippetEditorCmd() tea.Msg { return SnippetEditorMsg{} }
 
 // Config actions
 type (
-	SetEditorMsg    struct{}
-	ChangeLeaderMsg struct{}
+	SetEditorMsg               struct{}
+	ChangeLeaderMsg            struct{}
+	ToggleUppercaseKeywordsMsg struct{}
+	StorageUsageMsg            struct{}
 )
 
-func SetEditorCmd() tea.Msg    { return SetEditorMsg{} }
-func ChangeLeaderCmd() tea.Msg { return ChangeLeaderMsg{} }
+func SetEditorCmd() tea.Msg               { return SetEditorMsg{} }
+func ChangeLeaderCmd() tea.Msg            { return ChangeLeaderMsg{} }
+func ToggleUppercaseKeywordsCmd() tea.Msg { return ToggleUppercaseKeywordsMsg{} }
+func StorageUsageCmd() tea.Msg            { return StorageUsageMsg{} }
+
+// Workspace actions
+type (
+	SaveWorkspaceMsg   struct{}
+	DeleteWorkspaceMsg struct{}
+	SwitchWorkspaceMsg struct{ Name string }
+)
+
+func SaveWorkspaceCmd() tea.Msg   { return SaveWorkspaceMsg{} }
+func DeleteWorkspaceCmd() tea.Msg { return DeleteWorkspaceMsg{} }
+
+func SwitchWorkspaceCmd(name string) func() tea.Msg {
+	return func() tea.Msg { return SwitchWorkspaceMsg{Name: name} }
+}
+
+// FindMsg opens the finder searching the stored snippets, exports, servers
+// and history
+type FindMsg struct{}
+
+func FindCmd() tea.Msg { return FindMsg{} }
+
+// SearchFilesMsg asks for a text to search in the exports and snippets
+type SearchFilesMsg struct{}
+
+func SearchFilesCmd() tea.Msg { return SearchFilesMsg{} }
 
 // Window actions
 type (
@@ -576,9 +1082,13 @@ func QuitCmd() tea.Msg             { return QuitMsg{} }
 
 // Update actions
 type (
-	OpenReleaseMsg   struct{}
-	DismissUpdateMsg struct{}
+	OpenReleaseMsg      struct{}
+	ShowReleaseNotesMsg struct{}
+	DismissUpdateMsg    struct{}
+	InstallUpdateMsg    struct{}
 )
 
-func OpenReleaseCmd() tea.Msg   { return OpenReleaseMsg{} }
-func DismissUpdateCmd() tea.Msg { return DismissUpdateMsg{} }
+func OpenReleaseCmd() tea.Msg      { return OpenReleaseMsg{} }
+func ShowReleaseNotesCmd() tea.Msg { return ShowReleaseNotesMsg{} }
+func DismissUpdateCmd() tea.Msg    { return DismissUpdateMsg{} }
+func InstallUpdateCmd() tea.Msg    { return InstallUpdateMsg{} }
@@ -0,0 +1,200 @@
+package whichkey
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// usageFileName stores the number of runs of the root menu actions
+const usageFileName = "menu_usage.json"
+
+// frequentCount is the number of most used actions listed first in the root menu
+const frequentCount = 3
+
+// untracked lists the key paths not counted: quitting is done once a session
+// and would always be among the most used actions
+var untracked = []string{"q"}
+
+// frequentAction is one of the most used actions of the root menu
+type frequentAction struct {
+	keys []string
+	item MenuItem
+}
+
+// LoadUsage reads the number of runs of the root menu actions, by key path,
+// e.g. "d t". A missing file is not an error and returns no usage.
+func LoadUsage(storage string) (map[string]int, error) {
+	usage := map[string]int{}
+
+	data, err := os.ReadFile(filepath.Join(storage, usageFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	}
+	if err != nil {
+		return usage, fmt.Errorf("failed to read the menu usage: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return map[string]int{}, fmt.Errorf("failed to parse the menu usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// SaveUsage stores the number of runs of the root menu actions
+func SaveUsage(storage string, usage map[string]int) error {
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the menu usage: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(storage, usageFileName), data, 0o644); err != nil {
+		return fmt.Errorf("failed to save the menu usage: %w", err)
+	}
+
+	return nil
+}
+
+// SetUsage replaces the counted runs of the root menu actions
+func (r *Registry) SetUsage(usage map[string]int) {
+	if usage == nil {
+		usage = map[string]int{}
+	}
+	r.usage = usage
+}
+
+// Usage returns the counted runs of the root menu actions, by key path
+func (r *Registry) Usage() map[string]int {
+	return r.usage
+}
+
+// RecordUse counts a run of the action reached with keys from menu. Only the
+// actions of the root menu in the main view are counted, including the ones
+// the results menu shares with it. A number key of a frequent action counts
+// the action it stands for. It reports whether the run was counted.
+func (r *Registry) RecordUse(menu *Menu, keys []string) bool {
+	if len(keys) == 0 || !r.context.InMainView {
+		return false
+	}
+
+	switch menu {
+	case r.rootMenu:
+	case r.resultsMenu:
+		if hasKey(r.resultsItems(), keys[0]) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if len(keys) == 1 {
+		for _, action := range r.frequentActions() {
+			if action.item.Key == keys[0] {
+				keys = action.keys
+				break
+			}
+		}
+	}
+
+	path := strings.Join(keys, " ")
+	if slices.Contains(untracked, path) {
+		return false
+	}
+
+	if _, ok := r.resolve(keys); !ok {
+		return false
+	}
+
+	r.usage[path]++
+	return true
+}
+
+// frequentItems returns the most used actions available in the context, to
+// be run with the number keys
+func (r *Registry) frequentItems() []MenuItem {
+	actions := r.frequentActions()
+
+	items := make([]MenuItem, 0, len(actions))
+	for _, action := range actions {
+		items = append(items, action.item)
+	}
+
+	return items
+}
+
+// frequentActions returns the most used actions that can run in the context,
+// numbered from 1. Actions used as often are sorted by key path.
+func (r *Registry) frequentActions() []frequentAction {
+	paths := make([]string, 0, len(r.usage))
+	for path, count := range r.usage {
+		if count > 0 {
+			paths = append(paths, path)
+		}
+	}
+
+	slices.SortFunc(paths, func(a, b string) int {
+		return cmp.Or(cmp.Compare(r.usage[b], r.usage[a]), cmp.Compare(a, b))
+	})
+
+	var actions []frequentAction
+	for _, path := range paths {
+		if len(actions) == frequentCount {
+			break
+		}
+
+		keys := strings.Fields(path)
+		item, ok := r.resolve(keys)
+		if !ok || !item.Action.CanExecute(r.context) {
+			continue
+		}
+
+		item.Key = strconv.Itoa(len(actions) + 1)
+		actions = append(actions, frequentAction{keys: keys, item: item})
+	}
+
+	return actions
+}
+
+// resolve returns the action reached with keys from the root menu. Actions of
+// submenus are described with the submenus leading to them, e.g. "Database".
+func (r *Registry) resolve(keys []string) (MenuItem, bool) {
+	items := r.rootItems()
+
+	var labels []string
+	for i, key := range keys {
+		index := slices.IndexFunc(items, func(item MenuItem) bool { return item.Key == key })
+		if index < 0 {
+			return MenuItem{}, false
+		}
+
+		item := items[index]
+		submenu, isSubmenu := item.Action.(SubmenuAction)
+
+		if i == len(keys)-1 {
+			if isSubmenu {
+				return MenuItem{}, false
+			}
+
+			if len(labels) > 0 {
+				item.Description = strings.Join(labels, " ▸ ")
+			}
+			return item, true
+		}
+
+		if !isSubmenu {
+			return MenuItem{}, false
+		}
+
+		labels = append(labels, item.Label)
+		items = submenu.Menu.GetItems()
+	}
+
+	return MenuItem{}, false
+}
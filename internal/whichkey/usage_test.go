@@ -0,0 +1,134 @@
+package whichkey
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUsageRegistry returns a registry in the main view of a connected server
+func newUsageRegistry(usage map[string]int) *Registry {
+	r := NewRegistry()
+	r.UpdateContext(&MenuContext{InMainView: true, IsConnected: true})
+	r.SetUsage(usage)
+
+	return r
+}
+
+// frequentPaths describes the actions as number key:key path
+func frequentPaths(actions []frequentAction) []string {
+	paths := make([]string, len(actions))
+	for i, action := range actions {
+		paths[i] = action.item.Key + ":" + strings.Join(action.keys, " ")
+	}
+
+	return paths
+}
+
+func TestRecordUse(t *testing.T) {
+	t.Parallel()
+
+	r := newUsageRegistry(nil)
+
+	assert.True(t, r.RecordUse(r.rootMenu, []string{"d", "t"}))
+	assert.True(t, r.RecordUse(r.rootMenu, []string{"f"}))
+	assert.True(t, r.RecordUse(r.resultsMenu, []string{"d", "t"}), "a root action run from the results menu")
+
+	assert.False(t, r.RecordUse(r.rootMenu, nil))
+	assert.False(t, r.RecordUse(r.rootMenu, []string{"d"}), "a submenu is not an action")
+	assert.False(t, r.RecordUse(r.rootMenu, []string{"z"}), "unknown key")
+	assert.False(t, r.RecordUse(r.rootMenu, []string{"f", "x"}), "keys past an action")
+	assert.False(t, r.RecordUse(r.rootMenu, []string{"q"}), "quitting is not counted")
+	assert.False(t, r.RecordUse(r.resultsMenu, []string{"p"}), "actions on the results are not counted")
+	assert.False(t, r.RecordUse(r.databaseMenu, []string{"t"}), "only the root and results menus are counted")
+
+	assert.Equal(t, map[string]int{"d t": 2, "f": 1}, r.Usage())
+
+	assert.True(t, r.RecordUse(r.rootMenu, []string{"1"}), "the number key of the most used action")
+	assert.Equal(t, map[string]int{"d t": 3, "f": 1}, r.Usage())
+
+	r.UpdateContext(&MenuContext{InHistoryView: true, IsConnected: true})
+	assert.False(t, r.RecordUse(r.rootMenu, []string{"d", "t"}), "only the main view is counted")
+	assert.Equal(t, 3, r.Usage()["d t"])
+}
+
+func TestFrequentActions(t *testing.T) {
+	t.Parallel()
+
+	usage := map[string]int{
+		"d t": 3,
+		"d s": 3,
+		"f":   5,
+		"/":   1,
+		"d i": 0,
+		"z z": 10,
+		"d":   8,
+	}
+
+	r := newUsageRegistry(usage)
+
+	actions := r.frequentActions()
+	assert.Equal(t, []string{"1:f", "2:d s", "3:d t"}, frequentPaths(actions),
+		"sorted by use, ties by key path, unknown actions and submenus skipped")
+	assert.Equal(t, "List tables", actions[2].item.Label)
+	assert.Equal(t, "Database", actions[2].item.Description)
+
+	r.UpdateContext(&MenuContext{InMainView: true})
+	assert.Equal(t, []string{"1:f", "2:/"}, frequentPaths(r.frequentActions()),
+		"actions that can't run in the context are skipped")
+
+	assert.Empty(t, newUsageRegistry(nil).frequentActions())
+
+	items := newUsageRegistry(usage).GetRootMenu().GetItems()
+	require.GreaterOrEqual(t, len(items), 3)
+	assert.Equal(t, []string{"1", "2", "3"}, []string{items[0].Key, items[1].Key, items[2].Key},
+		"the root menu lists the frequent actions first")
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	r := newUsageRegistry(nil)
+
+	item, ok := r.resolve([]string{"d", "t"})
+	require.True(t, ok)
+	assert.Equal(t, "List tables", item.Label)
+	assert.Equal(t, "Database", item.Description, "described with the submenu leading to it")
+
+	item, ok = r.resolve([]string{"f"})
+	require.True(t, ok)
+	assert.Equal(t, "Find", item.Label)
+	assert.Equal(t, "Search snippets, exports, servers and history", item.Description)
+
+	for _, keys := range [][]string{nil, {"d"}, {"d", "z"}, {"z"}, {"f", "x"}} {
+		_, ok := r.resolve(keys)
+		assert.False(t, ok, keys)
+	}
+}
+
+func TestLoadAndSaveUsage(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+
+	usage, err := LoadUsage(storage)
+	require.NoError(t, err, "a missing file is no usage")
+	assert.Empty(t, usage)
+
+	require.NoError(t, SaveUsage(storage, map[string]int{"d t": 3, "f": 1}))
+
+	usage, err = LoadUsage(storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"d t": 3, "f": 1}, usage)
+
+	require.NoError(t, os.WriteFile(filepath.Join(storage, usageFileName), []byte("{not json"), 0o644))
+
+	usage, err = LoadUsage(storage)
+	require.Error(t, err)
+	assert.NotNil(t, usage)
+	assert.Empty(t, usage, "a corrupt file is no usage")
+}
@@ -0,0 +1,251 @@
+package advisor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LargeRowEstimate is the planner row estimate above which scans and joins
+// are reported as potentially expensive.
+const LargeRowEstimate = 10_000
+
+// Hint is a single optimisation suggestion produced by the rule engine
+type Hint struct {
+	Title  string
+	Detail string
+}
+
+// queryRule inspects the normalised (upper-cased, single-line) query text
+type queryRule struct {
+	pattern *regexp.Regexp
+	hint    Hint
+}
+
+var queryRules = []queryRule{
+	{
+		pattern: regexp.MustCompile(`SELECT\s+(DISTINCT\s+)?\*`),
+		hint: Hint{
+			Title:  "Avoid SELECT *",
+			Detail: "Select only the columns you need to reduce I/O and allow index-only scans.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`LIKE\s+'%`),
+		hint: Hint{
+			Title:  "Leading wildcard in LIKE",
+			Detail: "Patterns starting with % cannot use a B-tree index. Consider a pg_trgm GIN index.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`NOT\s+IN\s*\(\s*SELECT`),
+		hint: Hint{
+			Title:  "NOT IN with a subquery",
+			Detail: "NOT IN handles NULLs poorly and often plans badly. Prefer NOT EXISTS.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`OFFSET\s+\d{4,}`),
+		hint: Hint{
+			Title:  "Large OFFSET",
+			Detail: "Rows before the offset are still read and discarded. Consider keyset pagination.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`WHERE\s+.*\b(LOWER|UPPER|DATE|DATE_TRUNC|COALESCE|CAST)\s*\(`),
+		hint: Hint{
+			Title:  "Function applied to a column in WHERE",
+			Detail: "Wrapping a column in a function prevents plain index use. Consider an expression index.",
+		},
+	},
+}
+
+var (
+	seqScanRe    = regexp.MustCompile(`Seq Scan on (\S+)`)
+	nestedLoopRe = regexp.MustCompile(`Nested Loop`)
+	sortRe       = regexp.MustCompile(`->\s+Sort\b|^Sort\b`)
+	rowsRe       = regexp.MustCompile(`rows=(\d+)`)
+	whereRe      = regexp.MustCompile(`\bWHERE\b`)
+	mutationRe   = regexp.MustCompile(`^(UPDATE|DELETE)\b`)
+)
+
+// AnalyseQuery applies the query text rules and returns the matching hints
+func AnalyseQuery(query string) []Hint {
+	normalised := normalise(query)
+	var hints []Hint
+
+	if mutationRe.MatchString(normalised) && !whereRe.MatchString(normalised) {
+		hints = append(hints, Hint{
+			Title:  "UPDATE/DELETE without WHERE",
+			Detail: "This statement affects every row in the table.",
+		})
+	}
+
+	for _, rule := range queryRules {
+		if rule.pattern.MatchString(normalised) {
+			hints = append(hints, rule.hint)
+		}
+	}
+
+	return hints
+}
+
+// AnalysePlan inspects the text output of EXPLAIN and returns the matching hints
+func AnalysePlan(plan []string) []Hint {
+	var hints []Hint
+	seen := make(map[string]bool)
+
+	for i, line := range plan {
+		rows := rowEstimate(line)
+
+		if match := seqScanRe.FindStringSubmatch(line); match != nil {
+			table := match[1]
+			if seen["seq:"+table] {
+				continue
+			}
+
+			if hasFilter(plan, i) {
+				seen["seq:"+table] = true
+				hints = append(hints, Hint{
+					Title:  "Sequential scan with filter on " + table,
+					Detail: "Rows are filtered after reading the whole table. An index on the filtered columns may help.",
+				})
+			} else if rows >= LargeRowEstimate {
+				seen["seq:"+table] = true
+				hints = append(hints, Hint{
+					Title:  "Sequential scan on " + table,
+					Detail: fmt.Sprintf("The planner expects to read about %d rows.", rows),
+				})
+			}
+		}
+
+		if nestedLoopRe.MatchString(line) && rows >= LargeRowEstimate && !seen["nested"] {
+			seen["nested"] = true
+			hints = append(hints, Hint{
+				Title:  "Nested loop over many rows",
+				Detail: "Check that the join columns are indexed and that table statistics are up to date (ANALYZE).",
+			})
+		}
+
+		if sortRe.MatchString(strings.TrimSpace(line)) && rows >= LargeRowEstimate && !seen["sort"] {
+			seen["sort"] = true
+			hints = append(hints, Hint{
+				Title:  "Large sort",
+				Detail: "An index matching the ORDER BY columns, or a LIMIT, can avoid sorting the full result.",
+			})
+		}
+	}
+
+	return hints
+}
+
+// NodeTypes returns the plan node types in the order they appear
+func NodeTypes(plan []string) []string {
+	var nodes []string
+	for i, line := range plan {
+		trimmed := strings.TrimSpace(line)
+		trimmed = strings.TrimPrefix(trimmed, "->")
+		trimmed = strings.TrimSpace(trimmed)
+
+		// The root node has no arrow, every other node starts with "->"
+		if i != 0 && !strings.HasPrefix(strings.TrimSpace(line), "->") {
+			continue
+		}
+
+		if idx := strings.Index(trimmed, "  ("); idx != -1 {
+			trimmed = trimmed[:idx]
+		} else if idx := strings.Index(trimmed, " ("); idx != -1 {
+			trimmed = trimmed[:idx]
+		}
+
+		if trimmed != "" {
+			nodes = append(nodes, trimmed)
+		}
+	}
+	return nodes
+}
+
+// EstimatedRows returns the number of rows the planner expects the root node
+// of the plan to return
+func EstimatedRows(plan []string) (int, bool) {
+	if len(plan) == 0 || rowsRe.FindStringSubmatch(plan[0]) == nil {
+		return 0, false
+	}
+	return rowEstimate(plan[0]), true
+}
+
+// PlanNode returns the plan node that contains the given line of the EXPLAIN
+// output, together with its detail lines (e.g. filters and join conditions).
+// Child nodes are not included.
+func PlanNode(plan []string, line int) string {
+	if line < 0 || line >= len(plan) {
+		return ""
+	}
+
+	// Detail lines belong to the closest node above them
+	start := line
+	for start > 0 && !isPlanNode(plan[start]) {
+		start--
+	}
+
+	indent := indentation(plan[start])
+	node := []string{strings.TrimSpace(plan[start])}
+
+	for _, detail := range plan[start+1:] {
+		if isPlanNode(detail) || indentation(detail) <= indent {
+			break
+		}
+		node = append(node, strings.TrimSpace(detail))
+	}
+
+	return strings.Join(node, "\n")
+}
+
+func isPlanNode(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "->")
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// hasFilter reports whether the node at index i has a "Filter:" detail line
+func hasFilter(plan []string, i int) bool {
+	for _, line := range plan[i+1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "->") {
+			return false
+		}
+
+		if strings.HasPrefix(trimmed, "Filter:") {
+			return true
+		}
+	}
+	return false
+}
+
+func rowEstimate(line string) int {
+	match := rowsRe.FindStringSubmatch(line)
+	if match == nil {
+		return 0
+	}
+
+	rows, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return rows
+}
+
+func normalise(query string) string {
+	var lines []string
+	for line := range strings.SplitSeq(query, "\n") {
+		if idx := strings.Index(line, "--"); idx != -1 {
+			line = line[:idx]
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.ToUpper(strings.Join(strings.Fields(strings.Join(lines, " ")), " ")))
+}
@@ -0,0 +1,206 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hintTitles(hints []Hint) []string {
+	titles := make([]string, len(hints))
+	for i, h := range hints {
+		titles[i] = h.Title
+	}
+	return titles
+}
+
+func TestAnalyseQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "select star",
+			query:    "SELECT * FROM users WHERE id = 1",
+			expected: []string{"Avoid SELECT *"},
+		},
+		{
+			name:     "delete without where",
+			query:    "DELETE FROM users",
+			expected: []string{"UPDATE/DELETE without WHERE"},
+		},
+		{
+			name:     "update with where is fine",
+			query:    "UPDATE users SET active = false WHERE id = 1",
+			expected: nil,
+		},
+		{
+			name:     "leading wildcard",
+			query:    "SELECT id FROM users WHERE email LIKE '%@example.com'",
+			expected: []string{"Leading wildcard in LIKE"},
+		},
+		{
+			name:     "not in subquery",
+			query:    "SELECT id FROM users WHERE id NOT IN (SELECT user_id FROM orders)",
+			expected: []string{"NOT IN with a subquery"},
+		},
+		{
+			name:     "large offset",
+			query:    "SELECT id FROM users ORDER BY id LIMIT 10 OFFSET 50000",
+			expected: []string{"Large OFFSET"},
+		},
+		{
+			name:     "function on column",
+			query:    "SELECT id FROM users WHERE lower(email) = 'a@b.c'",
+			expected: []string{"Function applied to a column in WHERE"},
+		},
+		{
+			name:     "comments are ignored",
+			query:    "-- SELECT * FROM users\nSELECT id FROM users WHERE id = 1",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, nilIfEmpty(hintTitles(AnalyseQuery(tt.query))))
+		})
+	}
+}
+
+func TestAnalysePlan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		plan     []string
+		expected []string
+	}{
+		{
+			name: "seq scan with filter",
+			plan: []string{
+				"Seq Scan on users  (cost=0.00..35.50 rows=10 width=36)",
+				"  Filter: (email = 'a@b.c'::text)",
+			},
+			expected: []string{"Sequential scan with filter on users"},
+		},
+		{
+			name: "large seq scan without filter",
+			plan: []string{
+				"Seq Scan on events  (cost=0.00..2500.00 rows=150000 width=36)",
+			},
+			expected: []string{"Sequential scan on events"},
+		},
+		{
+			name: "small seq scan is fine",
+			plan: []string{
+				"Seq Scan on roles  (cost=0.00..1.05 rows=5 width=36)",
+			},
+			expected: nil,
+		},
+		{
+			name: "large sort and nested loop",
+			plan: []string{
+				"Sort  (cost=9000.00..9100.00 rows=40000 width=8)",
+				"  Sort Key: o.created_at",
+				"  ->  Nested Loop  (cost=0.29..8000.00 rows=40000 width=8)",
+				"        ->  Index Scan using users_pkey on users u  (cost=0.29..8.30 rows=1 width=4)",
+				"        ->  Index Scan using orders_user_id_idx on orders o  (cost=0.29..40.00 rows=40 width=8)",
+			},
+			expected: []string{"Large sort", "Nested loop over many rows"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, nilIfEmpty(hintTitles(AnalysePlan(tt.plan))))
+		})
+	}
+}
+
+func TestNodeTypes(t *testing.T) {
+	t.Parallel()
+
+	plan := []string{
+		"Hash Join  (cost=1.09..2.20 rows=5 width=72)",
+		"  Hash Cond: (o.user_id = u.id)",
+		"  ->  Seq Scan on orders o  (cost=0.00..1.05 rows=5 width=40)",
+		"  ->  Hash  (cost=1.04..1.04 rows=4 width=36)",
+		"        ->  Seq Scan on users u  (cost=0.00..1.04 rows=4 width=36)",
+	}
+
+	expected := []string{"Hash Join", "Seq Scan on orders o", "Hash", "Seq Scan on users u"}
+	assert.Equal(t, expected, NodeTypes(plan))
+}
+
+func TestEstimatedRows(t *testing.T) {
+	t.Parallel()
+
+	rows, ok := EstimatedRows([]string{
+		"Limit  (cost=0.00..0.25 rows=10 width=36)",
+		"  ->  Seq Scan on users  (cost=0.00..25.00 rows=1000 width=36)",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 10, rows)
+
+	_, ok = EstimatedRows(nil)
+	assert.False(t, ok)
+
+	_, ok = EstimatedRows([]string{"Result"})
+	assert.False(t, ok)
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func TestPlanNode(t *testing.T) {
+	t.Parallel()
+
+	plan := []string{
+		"Hash Join  (cost=1.09..2.20 rows=5 width=72)",
+		"  Hash Cond: (o.user_id = u.id)",
+		"  ->  Seq Scan on orders o  (cost=0.00..1.05 rows=5 width=40)",
+		"        Filter: (status = 'failed'::text)",
+		"  ->  Hash  (cost=1.04..1.04 rows=4 width=36)",
+		"        ->  Seq Scan on users u  (cost=0.00..1.04 rows=4 width=36)",
+	}
+
+	tests := []struct {
+		name     string
+		line     int
+		expected string
+	}{
+		{
+			name:     "root node excludes children",
+			line:     0,
+			expected: "Hash Join  (cost=1.09..2.20 rows=5 width=72)\nHash Cond: (o.user_id = u.id)",
+		},
+		{
+			name:     "detail line selects its node",
+			line:     3,
+			expected: "->  Seq Scan on orders o  (cost=0.00..1.05 rows=5 width=40)\nFilter: (status = 'failed'::text)",
+		},
+		{
+			name:     "node without details",
+			line:     4,
+			expected: "->  Hash  (cost=1.04..1.04 rows=4 width=36)",
+		},
+		{name: "out of range", line: 10, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, PlanNode(plan, tt.line))
+		})
+	}
+}
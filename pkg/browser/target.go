@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var openableSchemes = []string{"http", "https", "ftp", "mailto", "file"}
+
+// Target returns what the system opener should open for a cell value: a URL
+// with a known scheme or the absolute path of an existing file or directory.
+func Target(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, "\n\t") {
+		return "", false
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" {
+		for _, scheme := range openableSchemes {
+			if strings.EqualFold(u.Scheme, scheme) && (u.Host != "" || u.Opaque != "" || u.Path != "") {
+				return value, true
+			}
+		}
+	}
+
+	path := value
+	if rest, ok := strings.CutPrefix(path, "~"+string(filepath.Separator)); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	if !filepath.IsAbs(path) {
+		return "", false
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+
+	return path, true
+}
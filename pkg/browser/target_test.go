@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarget(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "invoice.pdf")
+	require.NoError(t, os.WriteFile(file, []byte("pdf"), 0o644))
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{name: "https url", value: "https://example.com/docs?id=1", expected: "https://example.com/docs?id=1", ok: true},
+		{name: "mailto", value: "mailto:user@example.com", expected: "mailto:user@example.com", ok: true},
+		{name: "surrounding spaces", value: "  http://example.com  ", expected: "http://example.com", ok: true},
+		{name: "existing file", value: file, expected: file, ok: true},
+		{name: "missing file", value: filepath.Join(dir, "missing.pdf")},
+		{name: "relative path", value: "invoice.pdf"},
+		{name: "unknown scheme", value: "javascript:alert(1)"},
+		{name: "plain text", value: "hello world"},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, ok := Target(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
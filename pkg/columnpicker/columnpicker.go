@@ -0,0 +1,305 @@
+// Package columnpicker suggests the columns of the tables referenced by the
+// statement being edited, e.g. in its SELECT list or WHERE clause.
+package columnpicker
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ionut-t/perp/pkg/schemaindex"
+)
+
+// Table is a table referenced by a statement
+type Table struct {
+	Name  string // the name without the public schema
+	Alias string
+}
+
+// Suggestion is a column of one of the referenced tables
+type Suggestion struct {
+	Table  Table
+	Column schemaindex.Column
+}
+
+// Source returns the name the table is referred to by in the statement
+func (s Suggestion) Source() string {
+	if s.Table.Alias != "" {
+		return s.Table.Alias
+	}
+	return s.Table.Name
+}
+
+// Text returns the column name to insert, quoted unless it is lower case
+func (s Suggestion) Text() string {
+	return QuoteName(s.Column.Name)
+}
+
+// QuoteName quotes name as an identifier, unless it is lower case
+func QuoteName(name string) string {
+	if plainName.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+var plainName = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// Offset converts a row and a column, in runes, into an offset in content
+func Offset(content string, row, col int) int {
+	offset := 0
+	for range row {
+		n := strings.IndexByte(content[offset:], '\n')
+		if n < 0 {
+			return len(content)
+		}
+		offset += n + 1
+	}
+
+	for ; col > 0 && offset < len(content) && content[offset] != '\n'; col-- {
+		_, size := utf8.DecodeRuneInString(content[offset:])
+		offset += size
+	}
+
+	return offset
+}
+
+// Suggest returns the columns of the tables referenced by the statement at
+// offset, read with columns. After a qualifier, e.g. "o." or "o.tot", only the
+// columns of that table are suggested. Nothing is suggested where a table
+// name is expected, like after FROM or JOIN, or in a string.
+func Suggest(content string, offset int, columns func(table string) []schemaindex.Column) []Suggestion {
+	statement, offset := statementAt(content, offset)
+	if _, inString := openString(statement[:offset]); inString {
+		return nil
+	}
+
+	tokens := tokenize(statement)
+
+	var before []token
+	for _, t := range tokens {
+		if t.end > offset {
+			break
+		}
+		before = append(before, t)
+	}
+
+	qualifier, qualified := qualifierAt(statement, offset, before)
+	if !qualified && expectsTable(before, offset) {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	for _, table := range referencedTables(tokens) {
+		if qualified && qualifier != table.Alias && (table.Alias != "" || qualifier != table.Name) {
+			continue
+		}
+
+		for _, column := range columns(table.Name) {
+			suggestions = append(suggestions, Suggestion{Table: table, Column: column})
+		}
+	}
+
+	return suggestions
+}
+
+// statementAt returns the statement around offset, ended by semicolons, and
+// the offset in it
+func statementAt(content string, offset int) (string, int) {
+	start, end := StatementBounds(content, offset)
+	return content[start:end], offset - start
+}
+
+// StatementBounds returns the start and end offsets of the statement around
+// offset, ended by semicolons
+func StatementBounds(content string, offset int) (int, int) {
+	start := 0
+	for _, t := range tokenize(content) {
+		if t.text != ";" {
+			continue
+		}
+
+		if t.end <= offset {
+			start = t.end
+			continue
+		}
+
+		return start, t.start
+	}
+
+	return start, len(content)
+}
+
+// qualifierAt returns the table name or alias before the dot right before
+// offset, ignoring the part of the column name already typed
+func qualifierAt(statement string, offset int, before []token) (string, bool) {
+	if len(before) == 0 {
+		return "", false
+	}
+
+	last := before[len(before)-1]
+	if last.end != offset || !last.ident {
+		return "", false
+	}
+
+	parts := last.parts
+	if !strings.HasSuffix(statement[last.start:last.end], ".") {
+		parts = parts[:len(parts)-1]
+	}
+
+	if len(parts) == 0 {
+		return "", false
+	}
+
+	return parts[len(parts)-1], true
+}
+
+var tableKeywords = map[string]bool{
+	"from": true, "join": true, "update": true, "into": true, "table": true,
+	"only": true, "lateral": true,
+}
+
+// expectsTable reports whether a table name is being typed at offset: the
+// last keyword is FROM, JOIN, UPDATE or INTO, or the list after FROM goes on
+func expectsTable(before []token, offset int) bool {
+	// the word being typed is not part of the context
+	if n := len(before); n > 0 && before[n-1].end == offset && before[n-1].ident {
+		before = before[:n-1]
+	}
+
+	depth := 0
+	for i := len(before) - 1; i >= 0; i-- {
+		t := before[i]
+
+		switch {
+		case t.text == ")":
+			depth++
+		case t.text == "(":
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case depth > 0:
+			continue
+		case t.ident && tableKeywords[t.keyword()]:
+			return true
+		case t.ident && clauseKeywords[t.keyword()]:
+			return false
+		}
+	}
+
+	return false
+}
+
+// clauseKeywords end a list of tables
+var clauseKeywords = map[string]bool{
+	"select": true, "where": true, "on": true, "using": true, "set": true,
+	"group": true, "order": true, "having": true, "returning": true,
+	"values": true, "and": true, "or": true, "as": true, "limit": true,
+	"window": true, "with": true,
+}
+
+// aliasStop lists the keywords that can follow a table in place of an alias
+var aliasStop = map[string]bool{
+	"where": true, "join": true, "inner": true, "left": true, "right": true,
+	"full": true, "cross": true, "natural": true, "on": true, "using": true,
+	"group": true, "order": true, "having": true, "limit": true, "offset": true,
+	"set": true, "returning": true, "values": true, "union": true,
+	"intersect": true, "except": true, "window": true, "for": true,
+	"tablesample": true, "default": true, "select": true, "outer": true,
+}
+
+// referencedTables returns the tables following FROM, JOIN, UPDATE and INTO,
+// with their aliases. Subqueries and functions in FROM are skipped.
+func referencedTables(tokens []token) []Table {
+	var tables []Table
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].ident {
+			continue
+		}
+
+		keyword := tokens[i].keyword()
+		switch keyword {
+		case "from", "join", "update", "into":
+		default:
+			continue
+		}
+
+		for {
+			i++
+			for i < len(tokens) && (tokens[i].keyword() == "only" || tokens[i].keyword() == "lateral") {
+				i++
+			}
+
+			if i >= len(tokens) || (!tokens[i].ident && tokens[i].text != "(") || aliasStop[tokens[i].keyword()] {
+				i--
+				break
+			}
+
+			var table *Table
+
+			switch {
+			case tokens[i].text == "(":
+				// a subquery
+				i = closingParen(tokens, i) + 1
+
+			case keyword != "into" && i+1 < len(tokens) && tokens[i+1].text == "(":
+				// a function, unlike the columns of INSERT INTO t (a, b)
+				i = closingParen(tokens, i+1) + 1
+
+			default:
+				table = &Table{Name: tableName(tokens[i].parts)}
+				i++
+			}
+
+			if i < len(tokens) && tokens[i].keyword() == "as" {
+				i++
+			}
+
+			if i < len(tokens) && tokens[i].ident && len(tokens[i].parts) == 1 && !aliasStop[tokens[i].keyword()] {
+				if table != nil {
+					table.Alias = tokens[i].parts[0]
+				}
+				i++
+			}
+
+			if table != nil {
+				tables = append(tables, *table)
+			}
+
+			if i >= len(tokens) || tokens[i].text != "," {
+				i--
+				break
+			}
+		}
+	}
+
+	return tables
+}
+
+// closingParen returns the index of the parenthesis closing the one at i, or
+// the last token when it is not closed
+func closingParen(tokens []token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// tableName drops the public schema, the only one indexed
+func tableName(parts []string) string {
+	if len(parts) == 2 && parts[0] == "public" {
+		return parts[1]
+	}
+	return strings.Join(parts, ".")
+}
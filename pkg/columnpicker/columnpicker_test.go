@@ -0,0 +1,177 @@
+package columnpicker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/schemaindex"
+	"github.com/stretchr/testify/assert"
+)
+
+func testColumns(table string) []schemaindex.Column {
+	columns := map[string][]string{
+		"users":       {"id", "email"},
+		"orders":      {"id", "user_id", "total"},
+		"sales.plans": {"id", "name"},
+	}
+
+	var result []schemaindex.Column
+	for _, name := range columns[table] {
+		result = append(result, schemaindex.Column{Name: name})
+	}
+	return result
+}
+
+// suggest runs Suggest with the offset at the | in sql
+func suggest(sql string) []string {
+	offset := strings.Index(sql, "|")
+	sql = strings.Replace(sql, "|", "", 1)
+
+	var names []string
+	for _, s := range Suggest(sql, offset, testColumns) {
+		names = append(names, s.Source()+"."+s.Column.Name)
+	}
+	return names
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "select list",
+			sql:  "SELECT | FROM users",
+			want: []string{"users.id", "users.email"},
+		},
+		{
+			name: "where clause with aliases",
+			sql:  "SELECT * FROM users u JOIN public.orders AS o ON o.user_id = u.id WHERE |",
+			want: []string{"u.id", "u.email", "o.id", "o.user_id", "o.total"},
+		},
+		{
+			name: "qualified",
+			sql:  "SELECT * FROM users u, orders o WHERE o.|",
+			want: []string{"o.id", "o.user_id", "o.total"},
+		},
+		{
+			name: "qualified while typing",
+			sql:  "SELECT u.em| FROM users u JOIN orders o USING (id)",
+			want: []string{"u.id", "u.email"},
+		},
+		{
+			name: "schema and quoted names",
+			sql:  `SELECT | FROM "sales"."plans" p`,
+			want: []string{"p.id", "p.name"},
+		},
+		{
+			name: "table expected",
+			sql:  "SELECT * FROM users, |",
+		},
+		{
+			name: "table being typed",
+			sql:  "SELECT * FROM users JOIN ord|",
+		},
+		{
+			name: "current statement only",
+			sql:  "SELECT * FROM orders; SELECT | FROM users; SELECT * FROM orders",
+			want: []string{"users.id", "users.email"},
+		},
+		{
+			name: "update",
+			sql:  "UPDATE orders SET total = 0 WHERE |",
+			want: []string{"orders.id", "orders.user_id", "orders.total"},
+		},
+		{
+			name: "insert columns",
+			sql:  "INSERT INTO users (|",
+			want: []string{"users.id", "users.email"},
+		},
+		{
+			name: "functions and strings are skipped",
+			sql:  "SELECT | FROM generate_series(1, 3) g, users WHERE email = 'from orders'",
+			want: []string{"users.id", "users.email"},
+		},
+		{
+			name: "unknown qualifier",
+			sql:  "SELECT x.| FROM users",
+		},
+		{
+			name: "in a string",
+			sql:  "SELECT * FROM users WHERE email = 'ann|",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, suggest(tt.sql))
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	t.Parallel()
+
+	content := "SELECT 'é'\nFROM users\n"
+	assert.Equal(t, 0, Offset(content, 0, 0))
+	assert.Equal(t, 11, Offset(content, 0, 10))
+	assert.Equal(t, 12, Offset(content, 1, 0))
+	assert.Equal(t, 16, Offset(content, 1, 4))
+	assert.Equal(t, 22, Offset(content, 1, 40))
+	assert.Equal(t, len(content), Offset(content, 5, 0))
+}
+
+func TestSuggestionText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "created_at", Suggestion{Column: schemaindex.Column{Name: "created_at"}}.Text())
+	assert.Equal(t, `"CreatedAt"`, Suggestion{Column: schemaindex.Column{Name: "CreatedAt"}}.Text())
+	assert.Equal(t, `"a ""b"""`, Suggestion{Column: schemaindex.Column{Name: `a "b"`}}.Text())
+}
+
+func TestTables(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Table{{Name: "users", Alias: "u"}, {Name: "sales.plans"}},
+		Tables("SELECT * FROM public.users u JOIN sales.plans ON plans.user_id = u.id"))
+	assert.Empty(t, Tables("SELECT 1"))
+}
+
+func TestFromEnd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{statement: "SELECT * FROM users u WHERE u.id = 1", expected: "SELECT * FROM users u"},
+		{statement: "SELECT * FROM users u\nJOIN orders o ON o.user_id = u.id\nORDER BY 1", expected: "SELECT * FROM users u\nJOIN orders o ON o.user_id = u.id"},
+		{statement: "SELECT * FROM users", expected: "SELECT * FROM users"},
+		{statement: "SELECT * FROM (SELECT * FROM users WHERE id = 1) u LIMIT 1", expected: "SELECT * FROM (SELECT * FROM users WHERE id = 1) u"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			t.Parallel()
+
+			end, ok := FromEnd(tt.statement)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, tt.statement[:end])
+		})
+	}
+
+	_, ok := FromEnd("SELECT (SELECT 1 FROM users)")
+	assert.False(t, ok)
+}
+
+func TestStatementBounds(t *testing.T) {
+	t.Parallel()
+
+	content := "SELECT 1; SELECT ';' FROM users;\n"
+	start, end := StatementBounds(content, 12)
+	assert.Equal(t, " SELECT ';' FROM users", content[start:end])
+}
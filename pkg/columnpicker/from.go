@@ -0,0 +1,42 @@
+package columnpicker
+
+// Tables returns the tables referenced by statement after FROM, JOIN, UPDATE
+// and INTO, in order
+func Tables(statement string) []Table {
+	return referencedTables(tokenize(statement))
+}
+
+// fromEndKeywords are the clauses that can follow the FROM clause
+var fromEndKeywords = map[string]bool{
+	"where": true, "group": true, "having": true, "order": true, "limit": true,
+	"offset": true, "window": true, "union": true, "intersect": true,
+	"except": true, "for": true, "returning": true, "fetch": true,
+}
+
+// FromEnd returns the offset following the FROM clause of statement and its
+// joins, where another join goes. It reports false when the statement has no
+// FROM clause outside subqueries.
+func FromEnd(statement string) (int, bool) {
+	tokens := tokenize(statement)
+
+	depth := 0
+	from := -1
+	for i, t := range tokens {
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth == 0 && from == -1 && t.keyword() == "from":
+			from = i
+		case depth == 0 && from != -1 && (fromEndKeywords[t.keyword()] || t.text == ";"):
+			return tokens[i-1].end, true
+		}
+	}
+
+	if from == -1 {
+		return 0, false
+	}
+
+	return tokens[len(tokens)-1].end, true
+}
@@ -0,0 +1,129 @@
+package columnpicker
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// token is a name, possibly qualified like "public"."orders", a string or a
+// number, or a single character of punctuation. Comments are skipped.
+type token struct {
+	text       string   // the text of the token as written
+	parts      []string // the parts of a name, unquoted and folded to lower case
+	ident      bool
+	quoted     bool // the name ends with a quoted part
+	start, end int
+}
+
+// keyword returns the name in lower case when it is a single unquoted word,
+// which could be a keyword
+func (t token) keyword() string {
+	if !t.ident || t.quoted || len(t.parts) != 1 {
+		return ""
+	}
+	return t.parts[0]
+}
+
+func tokenize(sql string) []token {
+	var tokens []token
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'':
+			end := skipPast(sql, i+1, "'")
+			tokens = append(tokens, token{text: sql[i:end], start: i, end: end})
+			i = end
+
+		case strings.HasPrefix(sql[i:], "--"):
+			i = skipPast(sql, i+2, "\n")
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipPast(sql, i+2, "*/")
+
+		case c == '$' && i+1 < len(sql) && sql[i+1] == '$':
+			i = skipPast(sql, i+2, "$$")
+
+		case c == '"' || isNameStart(sql[i:]):
+			t := readName(sql, i)
+			tokens = append(tokens, t)
+			i = t.end
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(sql) && (isNamePart(sql[i]) || sql[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{text: sql[start:i], start: start, end: i})
+
+		default:
+			_, size := utf8.DecodeRuneInString(sql[i:])
+			tokens = append(tokens, token{text: sql[i : i+size], start: i, end: i + size})
+			i += size
+		}
+	}
+
+	return tokens
+}
+
+// readName reads a name at i, joining its dot separated parts. A trailing
+// dot, as in "o." while typing a column, is part of the name.
+func readName(sql string, i int) token {
+	t := token{ident: true, start: i}
+
+	for {
+		if i < len(sql) && sql[i] == '"' {
+			end := skipPast(sql, i+1, `"`)
+			t.parts = append(t.parts, strings.ReplaceAll(strings.TrimSuffix(sql[i+1:end], `"`), `""`, `"`))
+			t.quoted = true
+			i = end
+		} else if isNameStart(sql[i:]) {
+			start := i
+			for i < len(sql) && (isNamePart(sql[i]) || sql[i] >= utf8.RuneSelf) {
+				i++
+			}
+			t.parts = append(t.parts, strings.ToLower(sql[start:i]))
+			t.quoted = false
+		}
+
+		if i >= len(sql) || sql[i] != '.' {
+			break
+		}
+		i++
+
+		if i >= len(sql) || (sql[i] != '"' && !isNameStart(sql[i:])) {
+			break
+		}
+	}
+
+	t.text = sql[t.start:i]
+	t.end = i
+
+	return t
+}
+
+// literal reports whether the token is a string or a number
+func (t token) literal() bool {
+	return !t.ident && t.text != "" && (t.text[0] == '\'' || (t.text[0] >= '0' && t.text[0] <= '9'))
+}
+
+func isNameStart(s string) bool {
+	return s != "" && (s[0] == '_' || (s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z') || s[0] >= utf8.RuneSelf)
+}
+
+func isNamePart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// skipPast returns the offset following the first end at or after i, or the
+// end of sql
+func skipPast(sql string, i int, end string) int {
+	if n := strings.Index(sql[i:], end); n >= 0 {
+		return i + n + len(end)
+	}
+	return len(sql)
+}
@@ -0,0 +1,178 @@
+package columnpicker
+
+import (
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/schemaindex"
+)
+
+// Value is a value of the column compared at the cursor
+type Value struct {
+	Column Suggestion
+	Label  string // the value as stored
+	Text   string // the literal to insert, completing the string already opened
+}
+
+// SuggestValues returns the values of the enum or boolean column compared at
+// offset, as in status = 'ac, status <> or status IN ('active', . Labels
+// returns the labels of an enum type, named as in the column type.
+func SuggestValues(content string, offset int, columns func(table string) []schemaindex.Column, labels func(typ string) []string) []Value {
+	statement, offset := statementAt(content, offset)
+
+	end := offset
+	quote, inString := openString(statement[:offset])
+	if inString {
+		end = quote
+	}
+
+	before := tokenize(statement[:end])
+	if n := len(before); !inString && n > 0 && before[n-1].end == offset && before[n-1].ident {
+		// the value being typed
+		before = before[:n-1]
+	}
+
+	column, ok := comparedColumn(before)
+	if !ok {
+		return nil
+	}
+
+	suggestion, ok := findColumn(column, referencedTables(tokenize(statement)), columns)
+	if !ok {
+		return nil
+	}
+
+	var values []Value
+
+	if suggestion.Column.Type == "boolean" {
+		if inString {
+			return nil
+		}
+
+		for _, label := range []string{"true", "false"} {
+			values = append(values, Value{Column: suggestion, Label: label, Text: label})
+		}
+
+		return values
+	}
+
+	for _, label := range labels(suggestion.Column.Type) {
+		text := strings.ReplaceAll(label, "'", "''") + "'"
+		if !inString {
+			text = "'" + text
+		}
+
+		values = append(values, Value{Column: suggestion, Label: label, Text: text})
+	}
+
+	return values
+}
+
+// comparedColumn returns the column before =, <> or != ending tokens, or
+// before the IN list they end in
+func comparedColumn(tokens []token) (token, bool) {
+	i := len(tokens) - 1
+	if i < 0 {
+		return token{}, false
+	}
+
+	switch tokens[i].text {
+	case "=":
+		i--
+		if i >= 0 && tokens[i].text == "!" {
+			i--
+		}
+
+	case ">":
+		i--
+		if i < 0 || tokens[i].text != "<" {
+			return token{}, false
+		}
+		i--
+
+	case ",", "(":
+		for i >= 0 && (tokens[i].text == "," || tokens[i].literal()) {
+			i--
+		}
+
+		if i < 0 || tokens[i].text != "(" {
+			return token{}, false
+		}
+		i--
+
+		if i < 0 || tokens[i].keyword() != "in" {
+			return token{}, false
+		}
+		i--
+
+		if i >= 0 && tokens[i].keyword() == "not" {
+			i--
+		}
+
+	default:
+		return token{}, false
+	}
+
+	if i < 0 || !tokens[i].ident {
+		return token{}, false
+	}
+
+	return tokens[i], true
+}
+
+// findColumn finds the column named by t among the columns of tables, in the
+// table its qualifier names when it has one
+func findColumn(t token, tables []Table, columns func(table string) []schemaindex.Column) (Suggestion, bool) {
+	name := t.parts[len(t.parts)-1]
+
+	var qualifier string
+	if len(t.parts) > 1 {
+		qualifier = t.parts[len(t.parts)-2]
+	}
+
+	for _, table := range tables {
+		if qualifier != "" && qualifier != table.Alias && (table.Alias != "" || qualifier != lastPart(table.Name)) {
+			continue
+		}
+
+		for _, column := range columns(table.Name) {
+			if column.Name == name {
+				return Suggestion{Table: table, Column: column}, true
+			}
+		}
+	}
+
+	return Suggestion{}, false
+}
+
+func lastPart(name string) string {
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// openString returns the offset of the quote opening the string sql ends in,
+// if it ends in one
+func openString(sql string) (int, bool) {
+	for i := 0; i < len(sql); {
+		switch {
+		case sql[i] == '\'':
+			end := strings.IndexByte(sql[i+1:], '\'')
+			if end < 0 {
+				return i, true
+			}
+			i += end + 2
+
+		case strings.HasPrefix(sql[i:], "--"):
+			i = skipPast(sql, i+2, "\n")
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipPast(sql, i+2, "*/")
+
+		case sql[i] == '"':
+			i = skipPast(sql, i+1, `"`)
+
+		default:
+			i++
+		}
+	}
+
+	return 0, false
+}
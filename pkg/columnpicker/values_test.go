@@ -0,0 +1,97 @@
+package columnpicker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/schemaindex"
+	"github.com/stretchr/testify/assert"
+)
+
+func typedColumns(table string) []schemaindex.Column {
+	switch table {
+	case "orders":
+		return []schemaindex.Column{{Name: "id", Type: "bigint"}, {Name: "status", Type: "order_status"}, {Name: "paid", Type: "boolean"}}
+	case "users":
+		return []schemaindex.Column{{Name: "id", Type: "bigint"}, {Name: "status", Type: "user_status"}}
+	}
+	return nil
+}
+
+func testLabels(typ string) []string {
+	switch typ {
+	case "order_status":
+		return []string{"new", "shipped", "won't ship"}
+	case "user_status":
+		return []string{"active", "banned"}
+	}
+	return nil
+}
+
+// suggestValues runs SuggestValues with the offset at the | in sql
+func suggestValues(sql string) []string {
+	offset := strings.Index(sql, "|")
+	sql = strings.Replace(sql, "|", "", 1)
+
+	var texts []string
+	for _, v := range SuggestValues(sql, offset, typedColumns, testLabels) {
+		texts = append(texts, v.Text)
+	}
+	return texts
+}
+
+func TestSuggestValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "equals",
+			sql:  "SELECT * FROM orders WHERE status = |",
+			want: []string{"'new'", "'shipped'", "'won''t ship'"},
+		},
+		{
+			name: "open string",
+			sql:  "SELECT * FROM orders WHERE status = 'sh|",
+			want: []string{"new'", "shipped'", "won''t ship'"},
+		},
+		{
+			name: "qualified with alias",
+			sql:  "SELECT * FROM orders o JOIN users u ON u.id = o.id WHERE u.status <> |",
+			want: []string{"'active'", "'banned'"},
+		},
+		{
+			name: "in list",
+			sql:  "SELECT * FROM users WHERE status NOT IN ('active', |",
+			want: []string{"'active'", "'banned'"},
+		},
+		{
+			name: "boolean",
+			sql:  "UPDATE orders SET paid = tr|",
+			want: []string{"true", "false"},
+		},
+		{
+			name: "other column",
+			sql:  "SELECT * FROM orders WHERE id = |",
+		},
+		{
+			name: "not a comparison",
+			sql:  "SELECT * FROM orders WHERE status |",
+		},
+		{
+			name: "closed string",
+			sql:  "SELECT * FROM orders WHERE status = 'new' |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, suggestValues(tt.sql))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package colwidth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const fileName = ".column_widths.json"
+
+var mu sync.Mutex
+
+// Fingerprint identifies a query regardless of whitespace, letter case and a
+// trailing semicolon, so widths set for it apply when it is run again.
+func Fingerprint(query string) string {
+	normalised := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	normalised = strings.TrimSpace(strings.TrimSuffix(normalised, ";"))
+
+	sum := sha256.Sum256([]byte(normalised))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Load returns the column widths saved for a query fingerprint
+func Load(storage, fingerprint string) (map[string]int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	all, err := read(filepath.Join(storage, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	return all[fingerprint], nil
+}
+
+// Save stores the column widths of a query fingerprint. Empty widths remove it.
+func Save(storage, fingerprint string, widths map[string]int) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := filepath.Join(storage, fileName)
+
+	all, err := read(path)
+	if err != nil {
+		return err
+	}
+
+	if len(widths) == 0 {
+		delete(all, fingerprint)
+	} else {
+		all[fingerprint] = maps.Clone(widths)
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func read(path string) (map[string]map[string]int, error) {
+	all := make(map[string]map[string]int)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
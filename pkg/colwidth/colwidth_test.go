@@ -0,0 +1,58 @@
+package colwidth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := Fingerprint("SELECT id, name FROM users")
+
+	assert.Equal(t, base, Fingerprint("select id,  name\n  from users;"))
+	assert.NotEqual(t, base, Fingerprint("SELECT id FROM users"))
+	assert.Len(t, base, 16)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+	fingerprint := Fingerprint("SELECT * FROM users")
+
+	widths, err := Load(storage, fingerprint)
+	require.NoError(t, err)
+	assert.Empty(t, widths)
+
+	require.NoError(t, Save(storage, fingerprint, map[string]int{"email": 12}))
+	require.NoError(t, Save(storage, "other", map[string]int{"id": 5}))
+
+	widths, err = Load(storage, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"email": 12}, widths)
+
+	require.NoError(t, Save(storage, fingerprint, nil))
+
+	widths, err = Load(storage, fingerprint)
+	require.NoError(t, err)
+	assert.Empty(t, widths)
+
+	widths, err = Load(storage, "other")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"id": 5}, widths)
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(storage, fileName), []byte("{"), 0o644))
+
+	_, err := Load(storage, "x")
+	assert.Error(t, err)
+}
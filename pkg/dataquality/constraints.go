@@ -0,0 +1,99 @@
+package dataquality
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// Columns of the NOT VALID constraints report
+const (
+	ConstraintTableColumn   = "Table"
+	ConstraintNameColumn    = "Constraint"
+	ConstraintTypeColumn    = "Type"
+	ValidateStatementColumn = "Validate Statement"
+)
+
+const foreignKeyConstraint = "foreign key"
+
+// NotValidConstraintsSQL lists the constraints added with NOT VALID, which
+// are enforced for new rows only, with the statement validating each of them
+var NotValidConstraintsSQL = fmt.Sprintf(`SELECT
+	c.conrelid::pg_catalog.regclass::text AS %[1]q,
+	c.conname AS %[2]q,
+	CASE c.contype
+		WHEN 'f' THEN '%[5]s'
+		WHEN 'c' THEN 'check'
+		WHEN 'n' THEN 'not null'
+		ELSE c.contype::text
+	END AS %[3]q,
+	pg_catalog.pg_get_constraintdef(c.oid, true) AS "Definition",
+	pg_catalog.format('ALTER TABLE %%s VALIDATE CONSTRAINT %%I', c.conrelid::pg_catalog.regclass, c.conname) AS %[4]q
+FROM pg_catalog.pg_constraint c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.connamespace
+WHERE NOT c.convalidated
+	AND c.conrelid <> 0
+	AND n.nspname <> 'pg_catalog'
+	AND n.nspname <> 'information_schema'
+ORDER BY 1, 2`,
+	ConstraintTableColumn,
+	ConstraintNameColumn,
+	ConstraintTypeColumn,
+	ValidateStatementColumn,
+	foreignKeyConstraint,
+)
+
+// Validation is the validation of a NOT VALID constraint
+type Validation struct {
+	Table      string
+	Constraint string
+	Type       string
+	Statement  string
+}
+
+// conflictingLocksQuery counts the other sessions holding or waiting for a
+// lock on the table that conflicts with SHARE UPDATE EXCLUSIVE
+const conflictingLocksQuery = `
+	SELECT count(DISTINCT l.pid)
+	FROM pg_catalog.pg_locks l
+	WHERE l.locktype = 'relation'
+		AND l.relation = pg_catalog.to_regclass($1)
+		AND l.pid <> pg_catalog.pg_backend_pid()
+		AND l.mode IN (
+			'ShareUpdateExclusiveLock',
+			'ShareLock',
+			'ShareRowExclusiveLock',
+			'ExclusiveLock',
+			'AccessExclusiveLock'
+		)`
+
+// ConflictingLocks returns the number of other sessions whose locks on the
+// table would make the validation wait
+func (v Validation) ConflictingLocks(ctx context.Context, database db.Database) (int64, error) {
+	count, err := countRows(ctx, database, conflictingLocksQuery, v.Table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check the locks on %s: %w", v.Table, err)
+	}
+
+	return count, nil
+}
+
+// LockWarning describes the locks taken by the validation and, when other
+// sessions hold conflicting ones, that it will wait for them
+func (v Validation) LockWarning(conflicts int64) string {
+	warning := fmt.Sprintf("Takes a SHARE UPDATE EXCLUSIVE lock on %s", v.Table)
+	if v.Type == foreignKeyConstraint {
+		warning += " and a ROW SHARE lock on the referenced table"
+	}
+	warning += ": reads and writes go on, DDL and VACUUM wait"
+
+	switch conflicts {
+	case 0:
+		return warning
+	case 1:
+		return warning + ". 1 session holds a conflicting lock, the validation will wait for it"
+	default:
+		return fmt.Sprintf("%s. %d sessions hold conflicting locks, the validation will wait for them", warning, conflicts)
+	}
+}
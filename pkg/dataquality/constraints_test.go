@@ -0,0 +1,25 @@
+package dataquality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockWarning(t *testing.T) {
+	t.Parallel()
+
+	check := Validation{Table: "public.users", Constraint: "users_age_check", Type: "check"}
+	fk := Validation{Table: "public.orders", Constraint: "orders_user_id_fkey", Type: foreignKeyConstraint}
+
+	assert.Equal(t,
+		"Takes a SHARE UPDATE EXCLUSIVE lock on public.users: reads and writes go on, DDL and VACUUM wait",
+		check.LockWarning(0))
+
+	assert.Equal(t,
+		"Takes a SHARE UPDATE EXCLUSIVE lock on public.orders and a ROW SHARE lock on the referenced table: "+
+			"reads and writes go on, DDL and VACUUM wait. 2 sessions hold conflicting locks, the validation will wait for them",
+		fk.LockWarning(2))
+
+	assert.Contains(t, check.LockWarning(1), "1 session holds a conflicting lock")
+}
@@ -0,0 +1,7 @@
+// Package dataquality generates the queries of the data quality checks, such
+// as finding duplicate rows or rows referencing missing parents.
+package dataquality
+
+// RowsQueryColumn holds the query listing the rows behind each result of a
+// check, used to drill down into them
+const RowsQueryColumn = "Rows Query"
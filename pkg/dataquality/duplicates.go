@@ -0,0 +1,74 @@
+package dataquality
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// DuplicatesCountColumn holds the number of rows in a duplicate group
+const DuplicatesCountColumn = "Duplicates"
+
+// Duplicates finds the rows of Table sharing the same values in Columns
+type Duplicates struct {
+	Table   string
+	Columns []string
+}
+
+// ParseDuplicates parses a table followed by its columns, separated by
+// spaces or commas, e.g. "users email, name" or "users(email, name)"
+func ParseDuplicates(input string) (Duplicates, error) {
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ",", " ").Replace(input))
+	if len(fields) < 2 {
+		return Duplicates{}, errors.New("expected a table followed by the columns to compare, e.g. users email")
+	}
+
+	table, err := psql.SanitiseIdentifier(fields[0])
+	if err != nil {
+		return Duplicates{}, err
+	}
+
+	d := Duplicates{Table: table}
+	for _, column := range fields[1:] {
+		if strings.Contains(column, ".") {
+			return Duplicates{}, fmt.Errorf("invalid column: %q", column)
+		}
+
+		column, err := psql.SanitiseIdentifier(column)
+		if err != nil {
+			return Duplicates{}, err
+		}
+
+		d.Columns = append(d.Columns, column)
+	}
+
+	return d, nil
+}
+
+// SQL returns the query listing each group of duplicates with its number of
+// rows, the most repeated first. Each group also has the query listing its
+// rows, built by the server so every value is quoted as a literal.
+func (d Duplicates) SQL() string {
+	columns := strings.Join(d.Columns, ", ")
+
+	conditions := make([]string, len(d.Columns))
+	for i, column := range d.Columns {
+		conditions[i] = column + " IS NOT DISTINCT FROM %L"
+	}
+
+	rowsQuery := fmt.Sprintf("SELECT * FROM %s WHERE %s", d.Table, strings.Join(conditions, " AND "))
+
+	return fmt.Sprintf(`SELECT %s, count(*) AS %q,
+	format('%s', %s) AS %q
+FROM %s
+GROUP BY %s
+HAVING count(*) > 1
+ORDER BY count(*) DESC`,
+		columns, DuplicatesCountColumn,
+		rowsQuery, columns, RowsQueryColumn,
+		d.Table,
+		columns,
+	)
+}
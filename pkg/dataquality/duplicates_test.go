@@ -0,0 +1,55 @@
+package dataquality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuplicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected Duplicates
+		wantErr  bool
+	}{
+		{input: "users email", expected: Duplicates{Table: "users", Columns: []string{"email"}}},
+		{input: "public.users email, name", expected: Duplicates{Table: "public.users", Columns: []string{"email", "name"}}},
+		{input: "users(email,name)", expected: Duplicates{Table: "users", Columns: []string{"email", "name"}}},
+		{input: "users", wantErr: true},
+		{input: "users email;drop", wantErr: true},
+		{input: "users u.email", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := ParseDuplicates(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestDuplicatesSQL(t *testing.T) {
+	t.Parallel()
+
+	d := Duplicates{Table: "users", Columns: []string{"email", "name"}}
+
+	expected := `SELECT email, name, count(*) AS "Duplicates",
+	format('SELECT * FROM users WHERE email IS NOT DISTINCT FROM %L AND name IS NOT DISTINCT FROM %L', email, name) AS "Rows Query"
+FROM users
+GROUP BY email, name
+HAVING count(*) > 1
+ORDER BY count(*) DESC`
+
+	assert.Equal(t, expected, d.SQL())
+}
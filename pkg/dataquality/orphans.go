@@ -0,0 +1,204 @@
+package dataquality
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// Columns of the orphaned rows report
+const (
+	OrphansConstraintColumn = "Constraint"
+	OrphansColumnsColumn    = "Columns"
+	OrphansReferenceColumn  = "References"
+	OrphansValidatedColumn  = "Validated"
+	OrphansCountColumn      = "Orphans"
+)
+
+// ForeignKey is a foreign key of a table. Table and column names are quoted
+// by the server so they can be used in queries as they are.
+type ForeignKey struct {
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	Validated  bool // false for constraints added with NOT VALID
+}
+
+const foreignKeysQuery = `
+	SELECT
+		con.conname,
+		pg_catalog.format('%I.%I', cn.nspname, cl.relname),
+		ARRAY(
+			SELECT pg_catalog.quote_ident(a.attname)
+			FROM pg_catalog.unnest(con.conkey) WITH ORDINALITY AS k(attnum, position)
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			ORDER BY k.position
+		),
+		pg_catalog.format('%I.%I', rn.nspname, rl.relname),
+		ARRAY(
+			SELECT pg_catalog.quote_ident(a.attname)
+			FROM pg_catalog.unnest(con.confkey) WITH ORDINALITY AS k(attnum, position)
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+			ORDER BY k.position
+		),
+		con.convalidated
+	FROM pg_catalog.pg_constraint con
+	JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+	JOIN pg_catalog.pg_namespace cn ON cn.oid = cl.relnamespace
+	JOIN pg_catalog.pg_class rl ON rl.oid = con.confrelid
+	JOIN pg_catalog.pg_namespace rn ON rn.oid = rl.relnamespace
+	WHERE con.contype = 'f' AND con.conrelid = pg_catalog.to_regclass($1)
+	ORDER BY con.conname`
+
+// ForeignKeys returns the foreign keys of table
+func ForeignKeys(ctx context.Context, database db.Database, table string) ([]ForeignKey, error) {
+	table, err := psql.SanitiseIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRelation(ctx, database, table); err != nil {
+		return nil, err
+	}
+
+	result, err := database.Query(ctx, foreignKeysQuery, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the foreign keys of %s: %w", table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var keys []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		if err := rows.Scan(&fk.Name, &fk.Table, &fk.Columns, &fk.RefTable, &fk.RefColumns, &fk.Validated); err != nil {
+			return nil, fmt.Errorf("failed to read the foreign keys of %s: %w", table, err)
+		}
+		keys = append(keys, fk)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list the foreign keys of %s: %w", table, err)
+	}
+
+	return keys, nil
+}
+
+// CountSQL returns the query counting the rows whose parent is missing
+func (fk ForeignKey) CountSQL() string {
+	return fmt.Sprintf("SELECT count(*) FROM %s c WHERE %s", fk.Table, fk.orphanCondition())
+}
+
+// RowsSQL returns the query listing the rows whose parent is missing
+func (fk ForeignKey) RowsSQL() string {
+	return fmt.Sprintf("SELECT c.* FROM %s c WHERE %s", fk.Table, fk.orphanCondition())
+}
+
+// orphanCondition matches the rows referencing a missing parent. Like the
+// default MATCH SIMPLE, rows with a NULL in the key reference nothing.
+func (fk ForeignKey) orphanCondition() string {
+	conditions := make([]string, 0, len(fk.Columns)+1)
+	joins := make([]string, len(fk.Columns))
+
+	for i, column := range fk.Columns {
+		conditions = append(conditions, "c."+column+" IS NOT NULL")
+		joins[i] = "p." + fk.RefColumns[i] + " = c." + column
+	}
+
+	conditions = append(conditions, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s p WHERE %s)",
+		fk.RefTable, strings.Join(joins, " AND ")))
+
+	return strings.Join(conditions, " AND ")
+}
+
+// Orphans checks each foreign key of table for rows whose parent is missing,
+// which constraints added with NOT VALID or disabled triggers let through.
+// Each key reports its number of orphaned rows and the query listing them.
+func Orphans(ctx context.Context, database db.Database, table string) (*psql.Result, error) {
+	keys, err := ForeignKeys(ctx, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &psql.Result{
+		Columns: []string{
+			OrphansConstraintColumn,
+			OrphansColumnsColumn,
+			OrphansReferenceColumn,
+			OrphansValidatedColumn,
+			OrphansCountColumn,
+			RowsQueryColumn,
+		},
+		Rows: make([]map[string]any, 0, len(keys)),
+	}
+
+	for _, fk := range keys {
+		count, err := countRows(ctx, database, fk.CountSQL())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", fk.Name, err)
+		}
+
+		result.Rows = append(result.Rows, map[string]any{
+			OrphansConstraintColumn: fk.Name,
+			OrphansColumnsColumn:    strings.Join(fk.Columns, ", "),
+			OrphansReferenceColumn:  fmt.Sprintf("%s(%s)", fk.RefTable, strings.Join(fk.RefColumns, ", ")),
+			OrphansValidatedColumn:  fk.Validated,
+			OrphansCountColumn:      count,
+			RowsQueryColumn:         fk.RowsSQL(),
+		})
+	}
+
+	return result, nil
+}
+
+func checkRelation(ctx context.Context, database db.Database, table string) error {
+	result, err := database.Query(ctx, "SELECT pg_catalog.to_regclass($1) IS NOT NULL", table)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var exists bool
+	if rows.Next() {
+		if err := rows.Scan(&exists); err != nil {
+			return fmt.Errorf("failed to look up %s: %w", table, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to look up %s: %w", table, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("relation %q does not exist", table)
+	}
+
+	return nil
+}
+
+func countRows(ctx context.Context, database db.Database, query string, args ...any) (int64, error) {
+	result, err := database.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var count int64
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, rows.Err()
+}
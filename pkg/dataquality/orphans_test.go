@@ -0,0 +1,25 @@
+package dataquality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForeignKeyQueries(t *testing.T) {
+	t.Parallel()
+
+	fk := ForeignKey{
+		Name:       "order_items_order_fkey",
+		Table:      "public.order_items",
+		Columns:    []string{"order_id", `"Line"`},
+		RefTable:   "public.orders",
+		RefColumns: []string{"id", `"Line"`},
+	}
+
+	condition := `c.order_id IS NOT NULL AND c."Line" IS NOT NULL AND ` +
+		`NOT EXISTS (SELECT 1 FROM public.orders p WHERE p.id = c.order_id AND p."Line" = c."Line")`
+
+	assert.Equal(t, "SELECT count(*) FROM public.order_items c WHERE "+condition, fk.CountSQL())
+	assert.Equal(t, "SELECT c.* FROM public.order_items c WHERE "+condition, fk.RowsSQL())
+}
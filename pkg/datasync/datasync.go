@@ -0,0 +1,346 @@
+// Package datasync compares the rows of a table on two servers by primary key
+// and generates the statements bringing the target in line with the source.
+package datasync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/jackc/pgx/v5"
+)
+
+// MaxRows is the number of rows read from each server at most. The rows are
+// compared in memory, so larger tables are refused.
+const MaxRows = 50000
+
+// Statuses of a row differing between the servers
+const (
+	Missing = "missing" // only on the source
+	Extra   = "extra"   // only on the target
+	Changed = "changed" // on both, with different values
+)
+
+// Columns of the preview
+const (
+	KeyColumn     = "Key"
+	StatusColumn  = "Status"
+	ChangedColumn = "Changed columns"
+)
+
+// Row is a row of the table, read as a JSON object
+type Row struct {
+	Key    string // the primary key values, for display
+	Values map[string]any
+	JSON   string
+	match  string // the primary key values encoded as JSON, used to match the rows
+}
+
+// Difference is a row differing between the servers
+type Difference struct {
+	Status  string
+	Row     Row      // the source row, or the target one when extra
+	Columns []string // the changed columns
+}
+
+// Report lists the differences of a table between the source and the target
+type Report struct {
+	Table       string
+	Key         []string // the primary key columns
+	SourceRows  int
+	TargetRows  int
+	Differences []Difference
+}
+
+const primaryKeyQuery = `
+	SELECT a.attname
+	FROM pg_catalog.pg_index i
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+	WHERE i.indrelid = pg_catalog.to_regclass($1) AND i.indisprimary
+	ORDER BY pg_catalog.array_position(i.indkey::int2[], a.attnum)`
+
+// PrimaryKey returns the primary key columns of table
+func PrimaryKey(ctx context.Context, database db.Database, table string) ([]string, error) {
+	result, err := database.Query(ctx, primaryKeyQuery, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the primary key of %s: %w", table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var key []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to read the primary key of %s: %w", table, err)
+		}
+		key = append(key, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to look up the primary key of %s: %w", table, err)
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("relation %q does not exist or has no primary key", table)
+	}
+
+	return key, nil
+}
+
+// Compare reads table on both servers and matches its rows by the primary key
+// of the target
+func Compare(ctx context.Context, source, target db.Database, table string) (*Report, error) {
+	table, err := psql.SanitiseIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := PrimaryKey(ctx, target, table)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceRows, err := readRows(ctx, source, table, key)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+
+	targetRows, err := readRows(ctx, target, table, key)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	return &Report{
+		Table:       table,
+		Key:         key,
+		SourceRows:  len(sourceRows),
+		TargetRows:  len(targetRows),
+		Differences: diffRows(sourceRows, targetRows),
+	}, nil
+}
+
+// readRows reads the rows of table ordered by key, failing when there are
+// more than MaxRows
+func readRows(ctx context.Context, database db.Database, table string, key []string) ([]Row, error) {
+	query := fmt.Sprintf("SELECT pg_catalog.to_jsonb(t)::text FROM %s t ORDER BY %s LIMIT %d",
+		table, strings.Join(quoteIdents(key), ", "), MaxRows+1)
+
+	result, err := database.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var read []Row
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+
+		row, err := parseRow(text, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+
+		if len(read) == MaxRows {
+			return nil, fmt.Errorf("%s has more than %d rows, too many to compare", table, MaxRows)
+		}
+		read = append(read, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	return read, nil
+}
+
+// parseRow parses a row read with to_jsonb. Numbers are kept as written so
+// large integers and numerics compare exactly.
+func parseRow(text string, key []string) (Row, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	row := Row{JSON: text}
+	if err := decoder.Decode(&row.Values); err != nil {
+		return Row{}, err
+	}
+
+	values := make([]any, len(key))
+	display := make([]string, len(key))
+	for i, column := range key {
+		value, ok := row.Values[column]
+		if !ok {
+			return Row{}, fmt.Errorf("column %q does not exist", column)
+		}
+		values[i] = value
+		display[i] = fmt.Sprint(value)
+	}
+
+	match, err := json.Marshal(values)
+	if err != nil {
+		return Row{}, err
+	}
+
+	row.Key = strings.Join(display, ", ")
+	row.match = string(match)
+
+	return row, nil
+}
+
+// diffRows matches the rows by key. The missing and changed rows follow the
+// order of the source, then come the extra rows in the order of the target.
+func diffRows(source, target []Row) []Difference {
+	targetByKey := make(map[string]Row, len(target))
+	for _, row := range target {
+		targetByKey[row.match] = row
+	}
+
+	var differences []Difference
+	seen := make(map[string]bool, len(source))
+
+	for _, row := range source {
+		seen[row.match] = true
+
+		other, ok := targetByKey[row.match]
+		if !ok {
+			differences = append(differences, Difference{Status: Missing, Row: row})
+			continue
+		}
+
+		if columns := changedColumns(row.Values, other.Values); len(columns) > 0 {
+			differences = append(differences, Difference{Status: Changed, Row: row, Columns: columns})
+		}
+	}
+
+	for _, row := range target {
+		if !seen[row.match] {
+			differences = append(differences, Difference{Status: Extra, Row: row})
+		}
+	}
+
+	return differences
+}
+
+// changedColumns returns the columns whose values differ, sorted by name
+func changedColumns(source, target map[string]any) []string {
+	var columns []string
+
+	for column, value := range source {
+		if other, ok := target[column]; !ok || !reflect.DeepEqual(value, other) {
+			columns = append(columns, column)
+		}
+	}
+
+	for column := range target {
+		if _, ok := source[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+
+	sort.Strings(columns)
+
+	return columns
+}
+
+// Count returns the number of differences with status
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, d := range r.Differences {
+		if d.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Summary describes the differences in one line
+func (r *Report) Summary() string {
+	return fmt.Sprintf("%s: %d missing, %d extra, %d changed (%d source rows, %d target rows)",
+		r.Table, r.Count(Missing), r.Count(Extra), r.Count(Changed), r.SourceRows, r.TargetRows)
+}
+
+// Result lists the differing rows by key
+func (r *Report) Result() *psql.Result {
+	result := &psql.Result{
+		Columns: []string{KeyColumn, StatusColumn, ChangedColumn},
+		Rows:    make([]map[string]any, len(r.Differences)),
+		Message: r.Summary(),
+	}
+
+	for i, d := range r.Differences {
+		result.Rows[i] = map[string]any{
+			KeyColumn:     d.Row.Key,
+			StatusColumn:  d.Status,
+			ChangedColumn: strings.Join(d.Columns, ", "),
+		}
+	}
+
+	return result
+}
+
+// Statements returns the statements reconciling the target: an INSERT for
+// each missing row and an UPDATE of the changed columns of each changed row.
+// The values are read by the server from the JSON of the source rows, so
+// they keep their types. Extra rows are only deleted by the commented out
+// statements, to be enabled after review.
+func (r *Report) Statements() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "-- %s\n", r.Summary())
+
+	record := func(row Row) string {
+		return fmt.Sprintf("pg_catalog.jsonb_populate_record(NULL::%s, %s) r", r.Table, psql.QuoteLiteral(row.JSON))
+	}
+
+	match := make([]string, len(r.Key))
+	for i, column := range quoteIdents(r.Key) {
+		match[i] = "t." + column + " = r." + column
+	}
+	where := strings.Join(match, " AND ")
+
+	for _, d := range r.Differences {
+		switch d.Status {
+		case Missing:
+			fmt.Fprintf(&b, "INSERT INTO %s SELECT r.* FROM %s;\n", r.Table, record(d.Row))
+
+		case Changed:
+			set := make([]string, 0, len(d.Columns))
+			for _, column := range quoteIdents(d.Columns) {
+				set = append(set, column+" = r."+column)
+			}
+			fmt.Fprintf(&b, "UPDATE %s t SET %s FROM %s WHERE %s;\n",
+				r.Table, strings.Join(set, ", "), record(d.Row), where)
+		}
+	}
+
+	if r.Count(Extra) > 0 {
+		b.WriteString("-- Rows only on the target:\n")
+		for _, d := range r.Differences {
+			if d.Status == Extra {
+				fmt.Fprintf(&b, "-- DELETE FROM %s t USING %s WHERE %s;\n", r.Table, record(d.Row), where)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func quoteIdents(names []string) []string {
+	quoted := slices.Clone(names)
+	for i, name := range quoted {
+		quoted[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return quoted
+}
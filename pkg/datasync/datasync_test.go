@@ -0,0 +1,91 @@
+package datasync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseRows(t *testing.T, key []string, texts ...string) []Row {
+	t.Helper()
+
+	rows := make([]Row, len(texts))
+	for i, text := range texts {
+		row, err := parseRow(text, key)
+		require.NoError(t, err)
+		rows[i] = row
+	}
+
+	return rows
+}
+
+func TestParseRow(t *testing.T) {
+	t.Parallel()
+
+	row, err := parseRow(`{"id": 9007199254740993, "region": "eu", "name": "a"}`, []string{"region", "id"})
+	require.NoError(t, err)
+	assert.Equal(t, "eu, 9007199254740993", row.Key)
+
+	_, err = parseRow(`{"name": "a"}`, []string{"id"})
+	require.Error(t, err)
+}
+
+func TestDiffRows(t *testing.T) {
+	t.Parallel()
+
+	key := []string{"id"}
+	source := mustParseRows(t, key,
+		`{"id": 1, "name": "a", "total": 1.50}`,
+		`{"id": 2, "name": "b", "total": 2}`,
+		`{"id": 3, "name": "c", "total": 3}`,
+	)
+	target := mustParseRows(t, key,
+		`{"id": 1, "name": "a", "total": 1.50}`,
+		`{"id": 2, "name": "B", "total": 2.5}`,
+		`{"id": 4, "name": "d", "total": 4}`,
+	)
+
+	differences := diffRows(source, target)
+	require.Len(t, differences, 3)
+
+	assert.Equal(t, Changed, differences[0].Status)
+	assert.Equal(t, "2", differences[0].Row.Key)
+	assert.Equal(t, []string{"name", "total"}, differences[0].Columns)
+
+	assert.Equal(t, Missing, differences[1].Status)
+	assert.Equal(t, "3", differences[1].Row.Key)
+
+	assert.Equal(t, Extra, differences[2].Status)
+	assert.Equal(t, "4", differences[2].Row.Key)
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	key := []string{"id"}
+	source := mustParseRows(t, key, `{"id": 1, "note": "it's"}`, `{"id": 2, "note": "x"}`)
+	target := mustParseRows(t, key, `{"id": 1, "note": "old"}`, `{"id": 3, "note": "y"}`)
+
+	report := &Report{
+		Table:       "public.notes",
+		Key:         key,
+		SourceRows:  len(source),
+		TargetRows:  len(target),
+		Differences: diffRows(source, target),
+	}
+
+	assert.Equal(t, "public.notes: 1 missing, 1 extra, 1 changed (2 source rows, 2 target rows)", report.Summary())
+
+	result := report.Result()
+	assert.Equal(t, []string{KeyColumn, StatusColumn, ChangedColumn}, result.Columns)
+	require.Len(t, result.Rows, 3)
+	assert.Equal(t, "note", result.Rows[0][ChangedColumn])
+
+	assert.Equal(t, `-- public.notes: 1 missing, 1 extra, 1 changed (2 source rows, 2 target rows)
+UPDATE public.notes t SET "note" = r."note" FROM pg_catalog.jsonb_populate_record(NULL::public.notes, '{"id": 1, "note": "it''s"}') r WHERE t."id" = r."id";
+INSERT INTO public.notes SELECT r.* FROM pg_catalog.jsonb_populate_record(NULL::public.notes, '{"id": 2, "note": "x"}') r;
+-- Rows only on the target:
+-- DELETE FROM public.notes t USING pg_catalog.jsonb_populate_record(NULL::public.notes, '{"id": 3, "note": "y"}') r WHERE t."id" = r."id";
+`, report.Statements())
+}
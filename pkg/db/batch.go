@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// batchCloseTimeout bounds rolling back the transaction left open by a batch,
+// which may be closed after the context of its statements was cancelled
+const batchCloseTimeout = 5 * time.Second
+
+// ErrBatchClosed is returned when a statement is run on a closed batch
+var ErrBatchClosed = errors.New("the connection of the statements was released")
+
+// Querier runs queries, on any connection of the pool or on the one of a
+// batch
+type Querier interface {
+	Query(ctx context.Context, query string, args ...any) (QueryResult, error)
+}
+
+// rowsQuerier is what the queries are sent to: the pool, or a connection
+// acquired from it
+type rowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Batch runs statements one after another on the same connection of the
+// pool, so a transaction started by one of them spans the next ones. The pool
+// drops the connections released in a transaction, which would lose it
+// between statements run on their own.
+type Batch struct {
+	d    *database
+	conn *pgxpool.Conn
+}
+
+var _ Querier = (*Batch)(nil)
+
+// Batch acquires the connection the statements of a buffer or a file run on,
+// held until the batch is closed
+func (d *database) Batch(ctx context.Context) (*Batch, error) {
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire a connection: %w", d.recordError(err))
+	}
+
+	return &Batch{d: d, conn: conn}, nil
+}
+
+// Query runs a statement on the connection of the batch
+func (b *Batch) Query(ctx context.Context, query string, args ...any) (QueryResult, error) {
+	if b.conn == nil {
+		return nil, fmt.Errorf("failed to execute query: %w", ErrBatchClosed)
+	}
+
+	return b.d.query(ctx, b.conn, query, args...)
+}
+
+// Close releases the connection. A transaction the statements left open, or
+// aborted by a failure, is rolled back first, since the next queries may run
+// on any connection; Close reports whether there was one. It can be called
+// more than once.
+func (b *Batch) Close() bool {
+	if b.conn == nil {
+		return false
+	}
+
+	defer func() {
+		b.conn.Release()
+		b.conn = nil
+	}()
+
+	if b.conn.Conn().PgConn().TxStatus() == 'I' {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchCloseTimeout)
+	defer cancel()
+
+	// a connection failing to roll back is dropped by the pool once
+	// released, which ends the transaction too
+	_, _ = b.conn.Exec(ctx, "ROLLBACK")
+
+	return true
+}
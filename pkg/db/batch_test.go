@@ -0,0 +1,251 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseEnv names the connection string of a server to run the batch
+// tests against. Without it, they run against fakeServer.
+const testDatabaseEnv = "PERP_TEST_DATABASE_URL"
+
+// fakeServer speaks enough of the protocol for the batch tests: it counts the
+// rows inserted in a table, keeping the ones inserted in a transaction apart
+// until it is committed. Like a server, a transaction belongs to the
+// connection it was started on and ends with it.
+type fakeServer struct {
+	mu       sync.Mutex
+	rows     int
+	listener net.Listener
+}
+
+func startFakeServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &fakeServer{listener: listener}
+	go s.serve()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	return fmt.Sprintf("postgres://perp@%s:%s/perp?sslmode=disable&default_query_exec_mode=simple_protocol", host, port)
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	inTx, pending := false, 0
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			return
+		}
+
+		statement := strings.ToUpper(strings.TrimSpace(query.String))
+		switch {
+		case statement == "BEGIN":
+			inTx = true
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("BEGIN")})
+
+		case statement == "COMMIT", statement == "ROLLBACK":
+			if statement == "COMMIT" {
+				s.mu.Lock()
+				s.rows += pending
+				s.mu.Unlock()
+			}
+			inTx, pending = false, 0
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(statement)})
+
+		case strings.HasPrefix(statement, "INSERT"):
+			if inTx {
+				pending++
+			} else {
+				s.mu.Lock()
+				s.rows++
+				s.mu.Unlock()
+			}
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("INSERT 0 1")})
+
+		case strings.HasPrefix(statement, "SELECT COUNT(*)"):
+			s.mu.Lock()
+			rows := s.rows
+			s.mu.Unlock()
+
+			backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{
+				{Name: []byte("count"), DataTypeOID: 20, DataTypeSize: 8, TypeModifier: -1},
+			}})
+			backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte(strconv.Itoa(rows))}})
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+
+		default:
+			// the pings and the table being created or dropped
+			backend.Send(&pgproto3.EmptyQueryResponse{})
+		}
+
+		txStatus := byte('I')
+		if inTx {
+			txStatus = 'T'
+		}
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// testTable connects to the test server and creates a table dropped once the
+// test ends
+func testTable(t *testing.T) (*database, string) {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		dsn = startFakeServer(t)
+	}
+
+	conn, err := New(dsn, "", PoolSettings{})
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	d := conn.(*database)
+	table := fmt.Sprintf("perp_batch_test_%d", time.Now().UnixNano())
+
+	_, err = d.pool.Exec(context.Background(), "CREATE TABLE "+table+" (id int)")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = d.pool.Exec(context.Background(), "DROP TABLE IF EXISTS "+table)
+	})
+
+	return d, table
+}
+
+// runBatch runs the statements on a batch, reading their rows
+func runBatch(t *testing.T, batch *Batch, statements ...string) {
+	t.Helper()
+
+	for _, statement := range statements {
+		result, err := batch.Query(context.Background(), statement)
+		require.NoError(t, err, statement)
+
+		rows := result.Rows()
+		rows.Close()
+		require.NoError(t, rows.Err(), statement)
+	}
+}
+
+// countRows counts the rows of table from another connection than the ones
+// of the batches
+func countRows(t *testing.T, d *database, table string) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, d.pool.QueryRow(context.Background(), "SELECT count(*) FROM "+table).Scan(&count))
+
+	return count
+}
+
+func TestBatchTransaction(t *testing.T) {
+	t.Parallel()
+
+	d, table := testTable(t)
+
+	batch, err := d.Batch(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { batch.Close() })
+
+	runBatch(t, batch,
+		"BEGIN",
+		"INSERT INTO "+table+" VALUES (1)",
+		"ROLLBACK",
+		"BEGIN",
+		"INSERT INTO "+table+" VALUES (2)",
+		"COMMIT",
+	)
+
+	assert.False(t, batch.Close(), "no transaction is left open")
+	assert.Equal(t, 1, countRows(t, d, table), "the first insert is rolled back, the second one committed")
+}
+
+func TestBatchRollsBackOpenTransaction(t *testing.T) {
+	t.Parallel()
+
+	d, table := testTable(t)
+
+	batch, err := d.Batch(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { batch.Close() })
+
+	runBatch(t, batch, "BEGIN", "INSERT INTO "+table+" VALUES (1)")
+
+	assert.True(t, batch.Close())
+	assert.False(t, batch.Close(), "closing again does nothing")
+	assert.Zero(t, countRows(t, d, table))
+
+	_, err = batch.Query(context.Background(), "SELECT 1")
+	require.ErrorIs(t, err, ErrBatchClosed)
+}
+
+func TestStatementsOnTheirOwnLoseTheTransaction(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(testDatabaseEnv) != "" {
+		t.Skip("the pool may reuse the connection of the transaction")
+	}
+
+	d, table := testTable(t)
+
+	// each statement on its own connection of the pool: the one of the
+	// transaction is dropped once released, so the insert is committed and
+	// the rollback has nothing to undo
+	for _, statement := range []string{"BEGIN", "INSERT INTO " + table + " VALUES (1)", "ROLLBACK"} {
+		result, err := d.Query(context.Background(), statement)
+		require.NoError(t, err)
+		result.Rows().Close()
+	}
+
+	assert.Equal(t, 1, countRows(t, d, table))
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+)
+
+// cancelDeadlineDelay is how long the server has to answer a cancel request
+// before the connection is closed instead
+const cancelDeadlineDelay = 3 * time.Second
+
+// queryCanceledCode is the SQLSTATE of a statement cancelled on the server
+const queryCanceledCode = "57014"
+
+// cancelOnServer makes the cancellation of the context of a query send a
+// cancel request, so the server stops the statement, rather than only close
+// the connection and leave the statement running
+func cancelOnServer(conn *pgconn.PgConn) ctxwatch.Handler {
+	return &pgconn.CancelRequestContextWatcherHandler{
+		Conn:          conn,
+		DeadlineDelay: cancelDeadlineDelay,
+	}
+}
+
+// IsCancelled reports whether err is the failure of a query whose context
+// was cancelled, or which the server cancelled
+func IsCancelled(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == queryCanceledCode
+}
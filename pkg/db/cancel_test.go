@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"context cancelled", fmt.Errorf("failed to execute query: %w", context.Canceled), true},
+		{"cancelled on the server", fmt.Errorf("failed to execute query: %w", &pgconn.PgError{Code: "57014"}), true},
+		{"timed out", context.DeadlineExceeded, false},
+		{"other server error", &pgconn.PgError{Code: "42P01"}, false},
+		{"other error", errors.New("connection refused"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsCancelled(tt.err))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// CopyFrom streams r to the server with a COPY ... FROM STDIN statement and
+// returns the number of rows copied
+func (d *database) CopyFrom(ctx context.Context, r io.Reader, sql string) (int64, error) {
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	tag, err := conn.Conn().PgConn().CopyFrom(ctx, r, sql)
+	if err != nil {
+		return 0, copyError(d.recordError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CopyTo streams the output of a COPY ... TO STDOUT statement to w and
+// returns the number of rows copied
+func (d *database) CopyTo(ctx context.Context, w io.Writer, sql string) (int64, error) {
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	tag, err := conn.Conn().PgConn().CopyTo(ctx, w, sql)
+	if err != nil {
+		return 0, copyError(d.recordError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// copyError adds the line and column the server stopped at to a COPY error,
+// e.g. COPY users, line 3, column email: "n/a"
+func copyError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Where != "" {
+		return fmt.Errorf("%w\n%s", err, pgErr.Where)
+	}
+
+	return err
+}
@@ -3,11 +3,14 @@ package db
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -26,6 +29,24 @@ type Database interface {
 	Query(ctx context.Context, query string, args ...any) (QueryResult, error)
 	// Generate a human-readable schema of the database
 	GenerateSchema() (string, error)
+	// Return the output of the first run of the on connect snippet
+	OnConnect(ctx context.Context) ([]StatementResult, error)
+	// Stream r to a COPY ... FROM STDIN statement and return the rows copied
+	CopyFrom(ctx context.Context, r io.Reader, sql string) (int64, error)
+	// Stream a COPY ... TO STDOUT statement to w and return the rows copied
+	CopyTo(ctx context.Context, w io.Writer, sql string) (int64, error)
+	// Copy a result set into a temporary table available to later queries
+	Materialize(ctx context.Context, table ScratchTable) error
+	// Fetch the rows of a query in batches through a server-side cursor
+	Stream(ctx context.Context, query string, batch int) (*Stream, error)
+	// Acquire a connection to run several statements on, one after another
+	Batch(ctx context.Context) (*Batch, error)
+	// Describe the columns of the results of a statement without running it
+	Describe(ctx context.Context, sql string) ([]ResultColumn, error)
+	// Return the last error reported by the server, or nil
+	LastError() *pgconn.PgError
+	// Replace the connections of the pool after the connection was lost
+	Reconnect(ctx context.Context) error
 	// Close the database connection
 	Close()
 }
@@ -47,17 +68,36 @@ type ColumnInfo struct {
 	ColumnDefault string
 }
 
-// New creates a new database pool based on the provided DSN
-func New(dbDSN string) (Database, error) {
+// New creates a new database pool based on the provided DSN, sized by pool.
+// The onConnect snippet, when set, runs on every new connection of the pool
+// so session settings such as search_path apply to all queries.
+func New(dbDSN, onConnect string, pool PoolSettings) (Database, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	pool, err := pgxpool.New(ctx, dbDSN)
+	config, err := pgxpool.ParseConfig(dbDSN)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
 	}
 
-	return &database{pool: pool}, nil
+	d := &database{onConnect: strings.TrimSpace(onConnect)}
+	if d.onConnect != "" {
+		config.AfterConnect = d.runOnConnect
+	}
+
+	config.ConnConfig.BuildContextWatcherHandler = cancelOnServer
+	config.PrepareConn = d.prepareConn
+	config.BeforeClose = d.forgetConn
+	pool.apply(config)
+
+	connPool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to database: %w", err)
+	}
+
+	d.pool = connPool
+
+	return d, nil
 }
 
 // isDDLQuery reports whether the query is a DDL statement that modifies the schema.
@@ -83,7 +123,26 @@ func isDDLQuery(query string) bool {
 
 // database encapsulates the pgx database connection pool
 type database struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	onConnect string
+
+	mu               sync.Mutex
+	onConnectResults []StatementResult
+
+	errMu     sync.Mutex
+	lastError *pgconn.PgError
+
+	// settings changed with SET and the version of them each connection has
+	settingsMu      sync.Mutex
+	settings        []sessionSetting
+	settingsVersion int
+	settingsConns   map[*pgx.Conn]int
+
+	// scratch tables and the version of them each connection has
+	scratchMu      sync.Mutex
+	scratch        []scratchTable
+	scratchVersion int
+	scratchConns   map[*pgx.Conn]int
 }
 
 var _ Database = (*database)(nil)
@@ -123,14 +182,24 @@ func (d *database) Close() {
 }
 
 func (d *database) Query(ctx context.Context, query string, args ...any) (QueryResult, error) {
+	return d.query(ctx, d.pool, query, args...)
+}
+
+// query runs query on q, the pool or a connection acquired from it
+func (d *database) query(ctx context.Context, q rowsQuerier, query string, args ...any) (QueryResult, error) {
 	startTime := time.Now()
-	rows, err := d.pool.Query(ctx, query, args...)
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", d.recordError(err))
+	}
+
+	recorded := recordedRows{Rows: rows, d: d}
+	if setting, ok := parseSessionSetting(query); ok {
+		recorded.setting = &setting
 	}
 
 	result := queryResult{
-		rows:      rows,
+		rows:      recorded,
 		query:     query,
 		startTime: startTime,
 		endTime:   time.Now(),
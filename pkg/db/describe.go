@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResultColumn is a column of the results of a statement
+type ResultColumn struct {
+	Name string
+	Type string
+}
+
+// Describe prepares sql as an unnamed statement, which parses and plans it
+// without running it, and returns the columns of its results with their
+// types as format_type writes them
+func (d *database) Describe(ctx context.Context, sql string) ([]ResultColumn, error) {
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	description, err := conn.Conn().PgConn().Prepare(ctx, "", sql, nil)
+	if err != nil {
+		return nil, d.recordError(err)
+	}
+
+	if len(description.Fields) == 0 {
+		return nil, nil
+	}
+
+	oids := make([]uint32, len(description.Fields))
+	modifiers := make([]int32, len(description.Fields))
+	for i, field := range description.Fields {
+		oids[i], modifiers[i] = field.DataTypeOID, field.TypeModifier
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT pg_catalog.format_type(t.oid, t.modifier)
+		FROM pg_catalog.unnest($1::oid[], $2::int4[]) WITH ORDINALITY AS t(oid, modifier, position)
+		ORDER BY t.position`, oids, modifiers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the column types: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make([]ResultColumn, 0, len(description.Fields))
+	for rows.Next() {
+		var typ string
+		if err := rows.Scan(&typ); err != nil {
+			return nil, fmt.Errorf("failed to read the column types: %w", err)
+		}
+		columns = append(columns, ResultColumn{Name: description.Fields[len(columns)].Name, Type: typ})
+	}
+
+	return columns, rows.Err()
+}
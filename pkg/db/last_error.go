@@ -0,0 +1,49 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// LastError returns the last error reported by the server, with all of its
+// fields, or nil when there was none. Later successful queries keep it.
+func (d *database) LastError() *pgconn.PgError {
+	d.errMu.Lock()
+	defer d.errMu.Unlock()
+
+	return d.lastError
+}
+
+// recordError keeps err when it was reported by the server and returns it
+func (d *database) recordError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		d.errMu.Lock()
+		d.lastError = pgErr
+		d.errMu.Unlock()
+	}
+
+	return err
+}
+
+// recordedRows records the error of a query failing while its rows are
+// read, and the setting changed by a successful SET
+type recordedRows struct {
+	pgx.Rows
+	d       *database
+	setting *sessionSetting
+}
+
+func (r recordedRows) Err() error {
+	return r.d.recordError(r.Rows.Err())
+}
+
+func (r recordedRows) Close() {
+	r.Rows.Close()
+
+	if r.setting != nil && r.Rows.Err() == nil {
+		r.d.recordSetting(*r.setting)
+	}
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// StatementResult is the output of a statement run with the simple protocol,
+// with every value in its text representation
+type StatementResult struct {
+	Columns    []string
+	Rows       [][]string
+	CommandTag string
+}
+
+// runOnConnect runs the on connect snippet on a new connection of the pool.
+// The output of the first run is kept to be shown to the user.
+func (d *database) runOnConnect(ctx context.Context, conn *pgx.Conn) error {
+	results, err := conn.PgConn().Exec(ctx, d.onConnect).ReadAll()
+	if err != nil {
+		return fmt.Errorf("on connect snippet failed: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.onConnectResults != nil {
+		return nil
+	}
+
+	d.onConnectResults = make([]StatementResult, 0, len(results))
+	for _, result := range results {
+		statement := StatementResult{CommandTag: result.CommandTag.String()}
+
+		for _, field := range result.FieldDescriptions {
+			statement.Columns = append(statement.Columns, field.Name)
+		}
+
+		for _, row := range result.Rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				if value == nil {
+					values[i] = "NULL"
+				} else {
+					values[i] = string(value)
+				}
+			}
+			statement.Rows = append(statement.Rows, values)
+		}
+
+		d.onConnectResults = append(d.onConnectResults, statement)
+	}
+
+	return nil
+}
+
+// OnConnect makes sure the pool has a connection, which runs the on connect
+// snippet, and returns the output of its first run. It returns nil when the
+// server has no snippet.
+func (d *database) OnConnect(ctx context.Context) ([]StatementResult, error) {
+	if d.onConnect == "" {
+		return nil, nil
+	}
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn.Release()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.onConnectResults, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	PoolMaxConns          = "max_conns"
+	PoolMaxConnIdleTime   = "max_conn_idle_time"
+	PoolHealthCheckPeriod = "health_check_period"
+)
+
+// PoolSettingNames lists the settings of the connection pool that can be configured
+var PoolSettingNames = [...]string{
+	PoolMaxConns,
+	PoolMaxConnIdleTime,
+	PoolHealthCheckPeriod,
+}
+
+// PoolSettings sizes the connection pool shared by the queries, the schema
+// fetches and the other background work. Zero values leave the pgx defaults
+// in place: the larger of 4 and the number of CPUs for the connections, 30
+// minutes of idle time and a health check every minute.
+type PoolSettings struct {
+	MaxConns          int32
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+// Set validates and applies a setting by name. An empty value resets it.
+func (s *PoolSettings) Set(name, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch name {
+	case PoolMaxConns:
+		if value == "" || value == "0" {
+			s.MaxConns = 0
+			return nil
+		}
+
+		maxConns, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || maxConns < 0 {
+			return fmt.Errorf("invalid maximum connections %q: expected a positive integer", value)
+		}
+
+		s.MaxConns = int32(maxConns)
+
+	case PoolMaxConnIdleTime, PoolHealthCheckPeriod:
+		duration, err := parsePoolDuration(value)
+		if err != nil {
+			return err
+		}
+
+		if name == PoolMaxConnIdleTime {
+			s.MaxConnIdleTime = duration
+		} else {
+			s.HealthCheckPeriod = duration
+		}
+
+	default:
+		return fmt.Errorf("unknown pool setting %q", name)
+	}
+
+	return nil
+}
+
+// parsePoolDuration parses a duration such as 30s or 5m, zero when empty
+func parsePoolDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q: expected a duration such as 30s or 5m", value)
+	}
+
+	return duration, nil
+}
+
+// apply sets the settings other than zero on the pool config
+func (s PoolSettings) apply(config *pgxpool.Config) {
+	if s.MaxConns > 0 {
+		config.MaxConns = s.MaxConns
+	}
+
+	if s.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = s.MaxConnIdleTime
+	}
+
+	if s.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = s.HealthCheckPeriod
+	}
+}
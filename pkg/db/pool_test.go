@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolSettingsSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		setting string
+		value   string
+		want    PoolSettings
+		wantErr bool
+	}{
+		{"max connections", PoolMaxConns, "8", PoolSettings{MaxConns: 8}, false},
+		{"zero max connections", PoolMaxConns, "0", PoolSettings{}, false},
+		{"empty max connections", PoolMaxConns, " ", PoolSettings{}, false},
+		{"negative max connections", PoolMaxConns, "-1", PoolSettings{}, true},
+		{"invalid max connections", PoolMaxConns, "many", PoolSettings{}, true},
+		{"idle time", PoolMaxConnIdleTime, "5m", PoolSettings{MaxConnIdleTime: 5 * time.Minute}, false},
+		{"health check period", PoolHealthCheckPeriod, "30s", PoolSettings{HealthCheckPeriod: 30 * time.Second}, false},
+		{"empty duration", PoolHealthCheckPeriod, "", PoolSettings{}, false},
+		{"invalid duration", PoolMaxConnIdleTime, "5", PoolSettings{}, true},
+		{"unknown setting", "min_conns", "2", PoolSettings{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var settings PoolSettings
+			err := settings.Set(tt.setting, tt.value)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, settings)
+		})
+	}
+}
+
+func TestPoolSettingsApply(t *testing.T) {
+	t.Parallel()
+
+	config, err := pgxpool.ParseConfig("postgres://app@localhost:5432/app")
+	require.NoError(t, err)
+
+	defaultIdleTime := config.MaxConnIdleTime
+	PoolSettings{MaxConns: 2, HealthCheckPeriod: 10 * time.Second}.apply(config)
+
+	assert.Equal(t, int32(2), config.MaxConns)
+	assert.Equal(t, 10*time.Second, config.HealthCheckPeriod)
+	assert.Equal(t, defaultIdleTime, config.MaxConnIdleTime, "zero values keep the defaults")
+}
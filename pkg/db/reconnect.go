@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Backoff spaces the attempts to reconnect to a server that went away
+type Backoff struct {
+	Initial  time.Duration // the wait before the first attempt
+	Max      time.Duration
+	Attempts int // the attempts before giving up
+}
+
+// DefaultBackoff waits 1s before the first attempt, doubling the wait up to
+// 30s, and gives up after about 3 minutes
+var DefaultBackoff = Backoff{Initial: time.Second, Max: 30 * time.Second, Attempts: 10}
+
+// Delay returns the wait before an attempt, counted from 1
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.Max)
+}
+
+// IsConnectionLost reports whether err means the connection to the server
+// was lost, or can't be opened again, rather than the query failing: the
+// server was restarted, the network dropped or the session was terminated.
+// Cancelled queries and query timeouts are not a lost connection.
+func IsConnectionLost(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", // admin_shutdown, e.g. pg_terminate_backend
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now, e.g. while the server starts
+			return true
+		}
+
+		// connection exceptions
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, pgconn.ErrConnClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// Reconnect closes the connections of the pool, which may be broken, and
+// checks a new one can be opened. The on connect snippet, the settings
+// changed with SET and the scratch tables are applied to the new
+// connections as they are opened.
+func (d *database) Reconnect(ctx context.Context) error {
+	d.pool.Reset()
+
+	return d.pool.Ping(ctx)
+}
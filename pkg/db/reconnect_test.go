@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	backoff := Backoff{Initial: time.Second, Max: 10 * time.Second}
+
+	assert.Equal(t, time.Second, backoff.Delay(1))
+	assert.Equal(t, 2*time.Second, backoff.Delay(2))
+	assert.Equal(t, 8*time.Second, backoff.Delay(4))
+	assert.Equal(t, 10*time.Second, backoff.Delay(5))
+	assert.Equal(t, 10*time.Second, backoff.Delay(50))
+}
+
+func TestIsConnectionLost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"terminated", fmt.Errorf("failed to execute query: %w", &pgconn.PgError{Code: "57P01"}), true},
+		{"server starting", &pgconn.PgError{Code: "57P03"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"closed connection", fmt.Errorf("failed to execute query: %w", pgconn.ErrConnClosed), true},
+		{"unexpected EOF", fmt.Errorf("failed to receive message: %w", io.ErrUnexpectedEOF), true},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"connection refused", syscall.ECONNREFUSED, true},
+		{"cancelled", fmt.Errorf("failed to execute query: %w", context.Canceled), false},
+		{"cancelled on the server", &pgconn.PgError{Code: "57014"}, false},
+		{"timed out", context.DeadlineExceeded, false},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, false},
+		{"other error", errors.New("no rows"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsConnectionLost(tt.err))
+		})
+	}
+}
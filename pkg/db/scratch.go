@@ -0,0 +1,225 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ScratchTable is a copy of a result set kept in a temporary table, so later
+// queries can join against it. Rows hold the values in the order of Columns.
+type ScratchTable struct {
+	Name    string
+	Columns []string
+	Types   []uint32
+	Rows    [][]any
+}
+
+// scratchTable is a scratch table ready to be created on a connection
+type scratchTable struct {
+	name   string
+	create string
+	copy   string
+	data   []byte // the rows in the text format of COPY
+}
+
+// Materialize creates the scratch table on every connection of the pool.
+// Temporary tables only live in the session that created them, so the table
+// is created on this connection now and on each other one before it is next
+// used. It is dropped with the connections on disconnect. A scratch table
+// with the same name is replaced.
+func (d *database) Materialize(ctx context.Context, table ScratchTable) error {
+	types, err := d.typeNames(ctx, table.Types)
+	if err != nil {
+		return err
+	}
+
+	data, err := copyData(table)
+	if err != nil {
+		return err
+	}
+
+	name := pgx.Identifier{table.Name}.Sanitize()
+	columns := make([]string, len(table.Columns))
+	definitions := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = pgx.Identifier{column}.Sanitize()
+		definitions[i] = columns[i] + " " + types[i]
+	}
+
+	scratch := scratchTable{
+		name:   table.Name,
+		create: fmt.Sprintf("CREATE TEMPORARY TABLE %s (%s)", name, strings.Join(definitions, ", ")),
+		copy:   fmt.Sprintf("COPY %s (%s) FROM STDIN", name, strings.Join(columns, ", ")),
+		data:   data,
+	}
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if err := scratch.createOn(ctx, conn.Conn()); err != nil {
+		return fmt.Errorf("failed to create %s: %w", table.Name, err)
+	}
+
+	d.scratchMu.Lock()
+	defer d.scratchMu.Unlock()
+
+	replaced := false
+	for i, existing := range d.scratch {
+		if existing.name == scratch.name {
+			d.scratch[i] = scratch
+			replaced = true
+		}
+	}
+	if !replaced {
+		d.scratch = append(d.scratch, scratch)
+	}
+
+	d.scratchVersion++
+	if d.scratchConns == nil {
+		d.scratchConns = make(map[*pgx.Conn]int)
+	}
+	d.scratchConns[conn.Conn()] = d.scratchVersion
+
+	return nil
+}
+
+// prepareScratch brings the scratch tables of a connection up to date
+// before it is acquired. A table that can't be created there is skipped
+// rather than failing the query the connection is acquired for.
+func (d *database) prepareScratch(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	d.scratchMu.Lock()
+	defer d.scratchMu.Unlock()
+
+	if d.scratchConns[conn] == d.scratchVersion {
+		return true, nil
+	}
+
+	for _, scratch := range d.scratch {
+		_ = scratch.createOn(ctx, conn)
+	}
+
+	if d.scratchConns == nil {
+		d.scratchConns = make(map[*pgx.Conn]int)
+	}
+	d.scratchConns[conn] = d.scratchVersion
+
+	return true, nil
+}
+
+// forgetScratch drops the closed connection from the scratch bookkeeping
+func (d *database) forgetScratch(conn *pgx.Conn) {
+	d.scratchMu.Lock()
+	defer d.scratchMu.Unlock()
+
+	delete(d.scratchConns, conn)
+}
+
+// createOn replaces the table on the connection and copies its rows
+func (s scratchTable) createOn(ctx context.Context, conn *pgx.Conn) error {
+	drop := "DROP TABLE IF EXISTS pg_temp." + pgx.Identifier{s.name}.Sanitize()
+	if _, err := conn.Exec(ctx, drop); err != nil {
+		return err
+	}
+
+	if _, err := conn.Exec(ctx, s.create); err != nil {
+		return err
+	}
+
+	if _, err := conn.PgConn().CopyFrom(ctx, bytes.NewReader(s.data), s.copy); err != nil {
+		return copyError(err)
+	}
+
+	return nil
+}
+
+// typeNames returns the SQL names of the types
+func (d *database) typeNames(ctx context.Context, oids []uint32) ([]string, error) {
+	rows, err := d.pool.Query(ctx, `
+		SELECT pg_catalog.format_type(t.oid, NULL)
+		FROM pg_catalog.unnest($1::pg_catalog.oid[]) WITH ORDINALITY AS t(oid, position)
+		ORDER BY t.position`, oids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the column types: %w", err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the column types: %w", err)
+	}
+
+	if len(names) != len(oids) {
+		return nil, fmt.Errorf("failed to look up the column types")
+	}
+
+	return names, nil
+}
+
+// copyData renders the rows in the text format of COPY, with each value in
+// the text representation of its type
+func copyData(table ScratchTable) ([]byte, error) {
+	typeMap := pgtype.NewMap()
+
+	var data bytes.Buffer
+	for _, row := range table.Rows {
+		for i, value := range row {
+			if i > 0 {
+				data.WriteByte('\t')
+			}
+
+			text, err := encodeText(typeMap, table.Types[i], value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to copy column %s: %w", table.Columns[i], err)
+			}
+
+			if text == nil {
+				data.WriteString(`\N`)
+			} else {
+				data.WriteString(escapeCopyText(string(text)))
+			}
+		}
+		data.WriteByte('\n')
+	}
+
+	return data.Bytes(), nil
+}
+
+// encodeText returns the text representation of a value, or nil for NULL
+func encodeText(typeMap *pgtype.Map, oid uint32, value any) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	text, err := typeMap.Encode(oid, pgtype.TextFormatCode, value, make([]byte, 0, 16))
+	if err == nil {
+		return text, nil
+	}
+
+	// values of types unknown to pgx are read as text
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+
+	return nil, err
+}
+
+var copyTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+func escapeCopyText(text string) string {
+	return copyTextEscaper.Replace(text)
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyData(t *testing.T) {
+	t.Parallel()
+
+	table := ScratchTable{
+		Name:    "tmp_results",
+		Columns: []string{"id", "name", "tags"},
+		Types:   []uint32{pgtype.Int4OID, pgtype.TextOID, pgtype.TextArrayOID},
+		Rows: [][]any{
+			{int32(1), "tab\there", []any{"a", "b"}},
+			{int32(2), "", nil},
+			{int32(3), `back\slash` + "\nline", []any{}},
+		},
+	}
+
+	data, err := copyData(table)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1\ttab\\there\t{a,b}\n"+
+		"2\t\t\\N\n"+
+		"3\tback\\\\slash\\nline\t{}\n", string(data))
+}
+
+func TestEncodeTextUnknownType(t *testing.T) {
+	t.Parallel()
+
+	text, err := encodeText(pgtype.NewMap(), 999999, "(1,2)")
+	require.NoError(t, err)
+	assert.Equal(t, "(1,2)", string(text))
+
+	text, err = encodeText(pgtype.NewMap(), pgtype.TextOID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, text)
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionSetting is a setting changed with SET, such as search_path, or
+// reset, with the statement applying it to a connection
+type sessionSetting struct {
+	name      string // empty when every setting is reset
+	statement string
+}
+
+// parseSessionSetting returns the setting changed by the statement: SET and
+// SET SESSION change one, RESET restores one, or every one with RESET ALL
+// and DISCARD ALL. SET LOCAL only lasts until the end of the transaction.
+func parseSessionSetting(statement string) (sessionSetting, bool) {
+	statement = strings.TrimSpace(stripSQLComments(statement))
+	statement = strings.TrimSpace(strings.TrimSuffix(statement, ";"))
+	if strings.Contains(statement, ";") {
+		return sessionSetting{}, false
+	}
+
+	fields := strings.Fields(strings.ToLower(statement))
+	if len(fields) < 2 {
+		return sessionSetting{}, false
+	}
+
+	switch fields[0] {
+	case "discard", "reset":
+		if len(fields) != 2 {
+			return sessionSetting{}, false
+		}
+
+		if fields[1] == "all" {
+			// DISCARD ALL drops the temporary tables too, the scratch ones
+			// are kept on the other connections
+			return sessionSetting{statement: "RESET ALL"}, true
+		}
+
+		return sessionSetting{name: fields[1], statement: statement}, fields[0] == "reset"
+
+	case "set":
+		fields = fields[1:]
+		if fields[0] == "session" {
+			fields = fields[1:]
+		}
+
+		if len(fields) == 0 {
+			return sessionSetting{}, false
+		}
+
+		switch fields[0] {
+		case "local", "transaction", "constraints", "characteristics":
+			return sessionSetting{}, false
+		}
+
+		name, _, _ := strings.Cut(fields[0], "=")
+		if name == "time" && len(fields) > 1 && fields[1] == "zone" {
+			name = "timezone"
+		}
+
+		return sessionSetting{name: name, statement: statement}, name != ""
+	}
+
+	return sessionSetting{}, false
+}
+
+// recordSetting keeps a setting changed by a successful statement, to apply
+// it to every connection of the pool
+func (d *database) recordSetting(setting sessionSetting) {
+	d.settingsMu.Lock()
+	defer d.settingsMu.Unlock()
+
+	// the rows of the statement may be closed more than once
+	if len(d.settings) > 0 && d.settings[len(d.settings)-1] == setting {
+		return
+	}
+
+	if setting.name == "" {
+		d.settings = []sessionSetting{setting}
+	} else {
+		d.settings = slices.DeleteFunc(d.settings, func(s sessionSetting) bool {
+			return s.name == setting.name
+		})
+		d.settings = append(d.settings, setting)
+	}
+
+	d.settingsVersion++
+}
+
+// prepareSettings applies the settings changed with SET to a connection
+// before it is acquired, so they hold whichever connection runs the next
+// query, including the new ones opened after a reconnect. A setting that
+// can't be applied is skipped rather than failing the query.
+func (d *database) prepareSettings(ctx context.Context, conn *pgx.Conn) {
+	d.settingsMu.Lock()
+	defer d.settingsMu.Unlock()
+
+	if d.settingsConns[conn] == d.settingsVersion {
+		return
+	}
+
+	for _, setting := range d.settings {
+		_, _ = conn.Exec(ctx, setting.statement)
+	}
+
+	if d.settingsConns == nil {
+		d.settingsConns = make(map[*pgx.Conn]int)
+	}
+	d.settingsConns[conn] = d.settingsVersion
+}
+
+// prepareConn brings a connection up to date before it is acquired
+func (d *database) prepareConn(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	d.prepareSettings(ctx, conn)
+
+	return d.prepareScratch(ctx, conn)
+}
+
+// forgetConn drops the closed connection from the bookkeeping
+func (d *database) forgetConn(conn *pgx.Conn) {
+	d.settingsMu.Lock()
+	delete(d.settingsConns, conn)
+	d.settingsMu.Unlock()
+
+	d.forgetScratch(conn)
+}
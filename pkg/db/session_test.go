@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSessionSetting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		statement string
+		want      sessionSetting
+		ok        bool
+	}{
+		{"SET search_path TO app, public;", sessionSetting{"search_path", "SET search_path TO app, public"}, true},
+		{"set session statement_timeout = '5s'", sessionSetting{"statement_timeout", "set session statement_timeout = '5s'"}, true},
+		{"SET work_mem='64MB'", sessionSetting{"work_mem", "SET work_mem='64MB'"}, true},
+		{"SET TIME ZONE 'UTC'", sessionSetting{"timezone", "SET TIME ZONE 'UTC'"}, true},
+		{"-- schema\nSET search_path TO app", sessionSetting{"search_path", "SET search_path TO app"}, true},
+		{"RESET search_path", sessionSetting{"search_path", "RESET search_path"}, true},
+		{"RESET ALL", sessionSetting{"", "RESET ALL"}, true},
+		{"DISCARD ALL", sessionSetting{"", "RESET ALL"}, true},
+		{"DISCARD PLANS", sessionSetting{}, false},
+		{"SET LOCAL search_path TO app", sessionSetting{}, false},
+		{"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE", sessionSetting{}, false},
+		{"SET search_path TO app; SELECT 1", sessionSetting{}, false},
+		{"SELECT set_config('search_path', 'app', false)", sessionSetting{}, false},
+		{"SET", sessionSetting{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			t.Parallel()
+
+			setting, ok := parseSessionSetting(tt.statement)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, setting)
+			}
+		})
+	}
+}
+
+func TestRecordSetting(t *testing.T) {
+	t.Parallel()
+
+	d := &database{}
+
+	d.recordSetting(sessionSetting{"search_path", "SET search_path TO app"})
+	d.recordSetting(sessionSetting{"work_mem", "SET work_mem = '64MB'"})
+	d.recordSetting(sessionSetting{"search_path", "SET search_path TO reporting"})
+	d.recordSetting(sessionSetting{"search_path", "SET search_path TO reporting"})
+
+	assert.Equal(t, []sessionSetting{
+		{"work_mem", "SET work_mem = '64MB'"},
+		{"search_path", "SET search_path TO reporting"},
+	}, d.settings)
+	assert.Equal(t, 3, d.settingsVersion, "repeating the last setting keeps the version")
+
+	d.recordSetting(sessionSetting{"", "RESET ALL"})
+	assert.Equal(t, []sessionSetting{{"", "RESET ALL"}}, d.settings)
+}
@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// streamCursor is the name of the cursor the rows of a stream are fetched from
+const streamCursor = "perp_stream"
+
+// streamCloseTimeout bounds ending the transaction of a stream, which may be
+// closed after the context of its query was cancelled
+const streamCloseTimeout = 5 * time.Second
+
+// ErrStreamClosed is returned when rows are fetched from a closed stream
+var ErrStreamClosed = errors.New("the rows of the query are no longer available")
+
+// modifyingWord matches the statements a cursor can't be declared for when
+// they are in a WITH query
+var modifyingWord = regexp.MustCompile(`(?i)\b(insert|update|delete|merge)\b`)
+
+// Stream reads the rows of a query in batches from a server-side cursor, so
+// a large result is never held whole in memory. It keeps a connection of the
+// pool in a transaction until it is closed.
+type Stream struct {
+	mu      sync.Mutex
+	d       *database
+	conn    *pgxpool.Conn
+	tx      pgx.Tx
+	query   string
+	batch   int
+	columns []string
+	fetched int
+	done    bool
+	failed  bool
+}
+
+// IsStreamable reports whether the rows of the statement can be fetched
+// through a cursor: a SELECT, VALUES or TABLE, or a WITH query not modifying
+// data
+func IsStreamable(statement string) bool {
+	q := strings.TrimSpace(stripSQLComments(statement))
+	if q == "" {
+		return false
+	}
+
+	first := strings.ToLower(strings.Fields(q)[0])
+	switch first {
+	case "select", "values", "table":
+		return true
+	case "with":
+		return !modifyingWord.MatchString(q)
+	}
+
+	return false
+}
+
+// Stream declares a cursor for query in a transaction and returns the stream
+// fetching its rows batch rows at a time
+func (d *database) Stream(ctx context.Context, query string, batch int) (*Stream, error) {
+	if batch <= 0 {
+		return nil, fmt.Errorf("invalid batch size: %d", batch)
+	}
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	body := strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", streamCursor, body)
+
+	if _, err := tx.Exec(ctx, declare); err != nil {
+		_ = (&Stream{conn: conn, tx: tx, failed: true}).Close()
+		return nil, fmt.Errorf("failed to execute query: %w", d.recordError(err))
+	}
+
+	return &Stream{
+		d:     d,
+		conn:  conn,
+		tx:    tx,
+		query: query,
+		batch: batch,
+	}, nil
+}
+
+// Query returns the query the rows are fetched for
+func (s *Stream) Query() string {
+	return s.query
+}
+
+// Columns returns the names of the columns, known once a batch is fetched
+func (s *Stream) Columns() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.columns
+}
+
+// Fetched returns the number of rows fetched so far
+func (s *Stream) Fetched() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fetched
+}
+
+// Done reports whether every row was fetched
+func (s *Stream) Done() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.done
+}
+
+// Next fetches the next batch of rows. A batch shorter than the batch size
+// is the last one. The stream is closed after it, or after a failure.
+func (s *Stream) Next(ctx context.Context) ([]map[string]RowResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return nil, nil
+	}
+
+	if s.conn == nil {
+		return nil, ErrStreamClosed
+	}
+
+	rows, err := s.tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", s.batch, streamCursor))
+	if err != nil {
+		s.failed = true
+		_ = s.close()
+		return nil, fmt.Errorf("failed to fetch rows: %w", s.d.recordError(err))
+	}
+
+	results, columns, err := ExtractResults(recordedRows{Rows: rows, d: s.d})
+	if err != nil {
+		s.failed = true
+		_ = s.close()
+		return nil, err
+	}
+
+	s.columns = columns
+	s.fetched += len(results)
+
+	if len(results) < s.batch {
+		s.done = true
+		return results, s.close()
+	}
+
+	return results, nil
+}
+
+// Close ends the transaction of the cursor and releases the connection,
+// waiting for a batch being fetched. It can be called more than once.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.close()
+}
+
+func (s *Stream) close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		s.conn.Release()
+		s.conn, s.tx = nil, nil
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamCloseTimeout)
+	defer cancel()
+
+	if s.failed {
+		return s.tx.Rollback(ctx)
+	}
+
+	return s.tx.Commit(ctx)
+}
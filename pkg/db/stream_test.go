@@ -0,0 +1,36 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStreamable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"select", "SELECT * FROM events", true},
+		{"trailing semicolon", "select id from events;\n", true},
+		{"leading comment", "-- all events\nSELECT * FROM events", true},
+		{"values", "VALUES (1), (2)", true},
+		{"table", "TABLE events", true},
+		{"with select", "WITH recent AS (SELECT * FROM events) SELECT * FROM recent", true},
+		{"with delete", "WITH gone AS (DELETE FROM events RETURNING *) SELECT * FROM gone", false},
+		{"insert", "INSERT INTO events VALUES (1)", false},
+		{"explain", "EXPLAIN SELECT * FROM events", false},
+		{"semicolon in string", "SELECT ';' AS separator", true},
+		{"empty", "-- nothing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsStreamable(tt.query))
+		})
+	}
+}
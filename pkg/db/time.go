@@ -0,0 +1,128 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TimeDisplay controls how timestamps are shown in results and exports.
+// The zero value keeps the values returned by the server.
+type TimeDisplay struct {
+	// Location is the time zone timestamptz values are converted to
+	Location *time.Location
+	// Layout is a Go time layout used for timestamp and timestamptz values
+	Layout string
+}
+
+// NewTimeDisplay builds a TimeDisplay from a time zone name such as "UTC",
+// "Local" or "Europe/London" and a Go time layout. Empty values are ignored.
+func NewTimeDisplay(zone, layout string) (TimeDisplay, error) {
+	display := TimeDisplay{Layout: layout}
+
+	if zone = strings.TrimSpace(zone); zone != "" {
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			return TimeDisplay{}, fmt.Errorf("invalid time zone %q: %w", zone, err)
+		}
+		display.Location = location
+	}
+
+	return display, nil
+}
+
+// IsZero reports whether the display keeps the server values unchanged
+func (d TimeDisplay) IsZero() bool {
+	return d.Location == nil && d.Layout == ""
+}
+
+// Zone returns the name of the display time zone, or "server" when
+// timestamptz values are not converted
+func (d TimeDisplay) Zone() string {
+	if d.Location == nil {
+		return "server"
+	}
+	return d.Location.String()
+}
+
+// Apply converts a timestamptz value to the display time zone and formats
+// timestamp and timestamptz values with the layout. Other values are returned
+// unchanged.
+func (d TimeDisplay) Apply(value any, oid uint32) any {
+	t, ok := value.(time.Time)
+	if !ok {
+		return value
+	}
+
+	switch oid {
+	case pgtype.TimestamptzOID:
+		if d.Location != nil {
+			t = t.In(d.Location)
+		}
+	case pgtype.TimestampOID:
+	default:
+		return value
+	}
+
+	if d.Layout != "" {
+		return t.Format(d.Layout)
+	}
+
+	return t
+}
+
+// ApplyRows returns rows with Apply used on every column listed in types
+func (d TimeDisplay) ApplyRows(rows []map[string]any, types map[string]uint32) []map[string]any {
+	if d.IsZero() || len(types) == 0 {
+		return rows
+	}
+
+	converted := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		out := make(map[string]any, len(row))
+		for k, v := range row {
+			out[k] = d.Apply(v, types[k])
+		}
+		converted[i] = out
+	}
+
+	return converted
+}
+
+// formatInterval formats an interval the way PostgreSQL does by default,
+// e.g. "1 year 2 mons 3 days 04:05:06"
+func formatInterval(v pgtype.Interval) string {
+	var parts []string
+
+	plural := func(n int64, unit, units string) {
+		if n == 1 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, unit))
+		} else if n != 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, units))
+		}
+	}
+
+	plural(int64(v.Months/12), "year", "years")
+	plural(int64(v.Months%12), "mon", "mons")
+	plural(int64(v.Days), "day", "days")
+
+	if v.Microseconds != 0 || len(parts) == 0 {
+		us := v.Microseconds
+		sign := ""
+		if us < 0 {
+			sign = "-"
+			us = -us
+		}
+
+		clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, us/3_600_000_000, us/60_000_000%60, us/1_000_000%60)
+		if frac := us % 1_000_000; frac != 0 {
+			clock += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+		}
+
+		parts = append(parts, clock)
+	}
+
+	return strings.Join(parts, " ")
+}
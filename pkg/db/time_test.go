@@ -0,0 +1,108 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeDisplayApply(t *testing.T) {
+	t.Parallel()
+
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 7, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		display  TimeDisplay
+		value    any
+		oid      uint32
+		expected any
+	}{
+		{
+			name:     "zero value keeps timestamptz",
+			value:    ts,
+			oid:      pgtype.TimestamptzOID,
+			expected: ts,
+		},
+		{
+			name:     "converts timestamptz to zone",
+			display:  TimeDisplay{Location: london, Layout: time.DateTime + " MST"},
+			value:    ts,
+			oid:      pgtype.TimestamptzOID,
+			expected: "2025-07-01 13:30:00 BST",
+		},
+		{
+			name:     "keeps timestamp wall clock",
+			display:  TimeDisplay{Location: london, Layout: time.DateTime},
+			value:    ts,
+			oid:      pgtype.TimestampOID,
+			expected: "2025-07-01 12:30:00",
+		},
+		{
+			name:     "zone without layout",
+			display:  TimeDisplay{Location: london},
+			value:    ts,
+			oid:      pgtype.TimestamptzOID,
+			expected: ts.In(london),
+		},
+		{
+			name:     "ignores dates",
+			display:  TimeDisplay{Location: london, Layout: time.Kitchen},
+			value:    ts,
+			oid:      pgtype.DateOID,
+			expected: ts,
+		},
+		{
+			name:     "ignores other values",
+			display:  TimeDisplay{Layout: time.Kitchen},
+			value:    "12:30",
+			oid:      pgtype.TimestamptzOID,
+			expected: "12:30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.display.Apply(tt.value, tt.oid))
+		})
+	}
+}
+
+func TestTimeDisplayApplyRows(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []map[string]any{{"id": 1, "created_at": ts}}
+	types := map[string]uint32{"id": pgtype.Int4OID, "created_at": pgtype.TimestamptzOID}
+
+	assert.Equal(t, rows, TimeDisplay{}.ApplyRows(rows, types))
+
+	converted := TimeDisplay{Layout: time.DateOnly}.ApplyRows(rows, types)
+	assert.Equal(t, []map[string]any{{"id": 1, "created_at": "2025-01-02"}}, converted)
+	assert.Equal(t, ts, rows[0]["created_at"], "source rows are not modified")
+}
+
+func TestNewTimeDisplay(t *testing.T) {
+	t.Parallel()
+
+	display, err := NewTimeDisplay("", "")
+	require.NoError(t, err)
+	assert.True(t, display.IsZero())
+	assert.Equal(t, "server", display.Zone())
+
+	display, err = NewTimeDisplay("Europe/London", time.RFC3339)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/London", display.Zone())
+	assert.Equal(t, time.RFC3339, display.Layout)
+
+	_, err = NewTimeDisplay("Mars/Olympus", "")
+	assert.ErrorContains(t, err, "invalid time zone")
+}
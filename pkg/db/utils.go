@@ -101,6 +101,11 @@ func FormatValue(val any, oid uint32) any {
 			return "\\x" + hex.EncodeToString(v)
 		}
 
+	case pgtype.IntervalOID:
+		if v, ok := val.(pgtype.Interval); ok && v.Valid {
+			return formatInterval(v)
+		}
+
 	case pgtype.NumericOID:
 		if v, ok := val.(pgtype.Numeric); ok && v.Valid {
 			str, err := v.Value()
@@ -122,6 +127,18 @@ func FormatValue(val any, oid uint32) any {
 	return val
 }
 
+// typeMap resolves the names of the built-in PostgreSQL types
+var typeMap = pgtype.NewMap()
+
+// TypeName returns the name of a built-in PostgreSQL type, or its OID for
+// types registered at runtime (e.g. enums and extension types).
+func TypeName(oid uint32) string {
+	if t, ok := typeMap.TypeForOID(oid); ok {
+		return t.Name
+	}
+	return fmt.Sprintf("oid %d", oid)
+}
+
 // formatSlice handles the formatting of slice types into a string representation.
 func formatSlice(val any) string {
 	slice := reflect.ValueOf(val)
@@ -132,6 +149,12 @@ func formatSlice(val any) string {
 	return fmt.Sprintf("{%s}", strings.Join(elements, ","))
 }
 
+// IsEmptyQuery reports whether the query has nothing to send to the server,
+// e.g. a buffer holding only comments and semicolons
+func IsEmptyQuery(query string) bool {
+	return strings.Trim(stripSQLComments(query), "; \t\r\n") == ""
+}
+
 // stripSQLComments removes SQL comments from a query string, correctly handling
 // various string literal and comment formats, including PostgreSQL-specific syntax.
 func stripSQLComments(q string) string {
@@ -26,6 +26,9 @@ func TestFormatValue(t *testing.T) {
 		{"string value", "hello", pgtype.TextOID, "hello"},
 		{"integer value", 123, pgtype.Int4OID, "123"},
 		{"float value", 123.45, pgtype.Float8OID, "123.450000"},
+		{"interval value", pgtype.Interval{Months: 14, Days: 3, Microseconds: 14706_500000, Valid: true}, pgtype.IntervalOID, "1 year 2 mons 3 days 04:05:06.5"},
+		{"zero interval", pgtype.Interval{Valid: true}, pgtype.IntervalOID, "00:00:00"},
+		{"negative interval", pgtype.Interval{Days: -1, Microseconds: -60_000_000, Valid: true}, pgtype.IntervalOID, "-1 days -00:01:00"},
 
 		{
 			name:     "json value from bytes",
@@ -72,6 +75,24 @@ func TestFormatValue(t *testing.T) {
 	}
 }
 
+func TestTypeName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "int8", TypeName(pgtype.Int8OID))
+	assert.Equal(t, "timestamptz", TypeName(pgtype.TimestamptzOID))
+	assert.Equal(t, "_text", TypeName(pgtype.TextArrayOID))
+	assert.Equal(t, "oid 16385", TypeName(16385))
+}
+
+func TestIsEmptyQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsEmptyQuery("-- TODO: add the report query"))
+	assert.True(t, IsEmptyQuery("/* draft */\n;\n-- later\n"))
+	assert.False(t, IsEmptyQuery("-- count users\nSELECT count(*) FROM users"))
+	assert.False(t, IsEmptyQuery("SELECT '-- not a comment'"))
+}
+
 func TestStripSQLComments(t *testing.T) {
 	t.Parallel()
 
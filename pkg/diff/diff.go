@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"strings"
+)
+
+// Op describes how a line changed between two texts
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// Line is a single line of a line-based diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines computes a line-based diff between original and updated using the
+// longest common subsequence of their lines.
+func Lines(original, updated string) []Line {
+	a := splitLines(original)
+	b := splitLines(updated)
+
+	// lcs[i][j] holds the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Delete, Text: a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Insert, Text: b[j]})
+	}
+
+	return lines
+}
+
+// HasChanges reports whether the diff contains any inserted or deleted lines
+func HasChanges(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Unified renders the diff using the unified diff line prefixes
+// ("+" for insertions, "-" for deletions and " " for unchanged lines).
+func Unified(lines []Line) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		switch line.Op {
+		case Insert:
+			sb.WriteString("+ ")
+		case Delete:
+			sb.WriteString("- ")
+		default:
+			sb.WriteString("  ")
+		}
+
+		sb.WriteString(line.Text)
+	}
+	return sb.String()
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		original string
+		updated  string
+		expected []Line
+	}{
+		{
+			name:     "identical texts",
+			original: "SELECT *\nFROM users;",
+			updated:  "SELECT *\nFROM users;",
+			expected: []Line{
+				{Op: Equal, Text: "SELECT *"},
+				{Op: Equal, Text: "FROM users;"},
+			},
+		},
+		{
+			name:     "changed line",
+			original: "SELECT *\nFROM users;",
+			updated:  "SELECT id, name\nFROM users;",
+			expected: []Line{
+				{Op: Delete, Text: "SELECT *"},
+				{Op: Insert, Text: "SELECT id, name"},
+				{Op: Equal, Text: "FROM users;"},
+			},
+		},
+		{
+			name:     "appended line",
+			original: "SELECT *\nFROM users",
+			updated:  "SELECT *\nFROM users\nLIMIT 10;",
+			expected: []Line{
+				{Op: Equal, Text: "SELECT *"},
+				{Op: Equal, Text: "FROM users"},
+				{Op: Insert, Text: "LIMIT 10;"},
+			},
+		},
+		{
+			name:     "removed line",
+			original: "SELECT *\nFROM users\nWHERE true;",
+			updated:  "SELECT *\nFROM users;",
+			expected: []Line{
+				{Op: Equal, Text: "SELECT *"},
+				{Op: Delete, Text: "FROM users"},
+				{Op: Delete, Text: "WHERE true;"},
+				{Op: Insert, Text: "FROM users;"},
+			},
+		},
+		{
+			name:     "empty original",
+			original: "",
+			updated:  "SELECT 1;",
+			expected: []Line{
+				{Op: Insert, Text: "SELECT 1;"},
+			},
+		},
+		{
+			name:     "crlf line endings are normalised",
+			original: "SELECT 1\r\nFROM t",
+			updated:  "SELECT 1\nFROM t",
+			expected: []Line{
+				{Op: Equal, Text: "SELECT 1"},
+				{Op: Equal, Text: "FROM t"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, Lines(tt.original, tt.updated))
+		})
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, HasChanges(Lines("SELECT 1;", "SELECT 1;")))
+	assert.True(t, HasChanges(Lines("SELECT 1;", "SELECT 2;")))
+	assert.False(t, HasChanges(nil))
+}
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	lines := []Line{
+		{Op: Delete, Text: "SELECT *"},
+		{Op: Insert, Text: "SELECT id"},
+		{Op: Equal, Text: "FROM users;"},
+	}
+
+	expected := "- SELECT *\n+ SELECT id\n  FROM users;"
+	assert.Equal(t, expected, Unified(lines))
+}
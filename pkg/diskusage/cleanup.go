@@ -0,0 +1,224 @@
+package diskusage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ionut-t/perp/pkg/export"
+)
+
+// timestampLayout names the trash and archive directories after the cleanup
+const timestampLayout = "20060102-150405"
+
+// Cleanup is what an action freeing space did
+type Cleanup struct {
+	Files int   // the exports, not counting their descriptions
+	Size  int64 // of the exports and their descriptions, in bytes
+}
+
+// staleExport is an export, with its description, last modified before a cutoff
+type staleExport struct {
+	server string
+	name   string
+	files  []string // the paths of the export and of its description
+	size   int64
+}
+
+// staleExports lists the exports of every server last modified before cutoff
+func staleExports(root string, cutoff time.Time) ([]staleExport, error) {
+	servers, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var exports []staleExport
+
+	for _, server := range servers {
+		if !server.IsDir() || strings.HasPrefix(server.Name(), ".") || server.Name() == SnippetsDirectory {
+			continue
+		}
+
+		dir := filepath.Join(root, server.Name(), ExportsDirectory)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || export.IsMetadata(entry.Name()) {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+
+			if !info.ModTime().Before(cutoff) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			stale := staleExport{
+				server: server.Name(),
+				name:   entry.Name(),
+				files:  []string{path},
+				size:   info.Size(),
+			}
+
+			if metadata, err := os.Stat(export.MetadataPath(path)); err == nil {
+				stale.files = append(stale.files, export.MetadataPath(path))
+				stale.size += metadata.Size()
+			}
+
+			exports = append(exports, stale)
+		}
+	}
+
+	return exports, nil
+}
+
+// TrashExports moves the exports last modified before cutoff, with their
+// descriptions, to the trash, in a directory named after now. They take
+// space until the trash is emptied.
+func TrashExports(root string, cutoff, now time.Time) (Cleanup, error) {
+	exports, err := staleExports(root, cutoff)
+	if err != nil {
+		return Cleanup{}, err
+	}
+
+	var cleanup Cleanup
+	trash := filepath.Join(root, TrashDirectory, now.Format(timestampLayout))
+
+	for _, stale := range exports {
+		dir := filepath.Join(trash, stale.server)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return cleanup, err
+		}
+
+		for _, file := range stale.files {
+			if err := os.Rename(file, filepath.Join(dir, filepath.Base(file))); err != nil {
+				return cleanup, err
+			}
+		}
+
+		cleanup.Files++
+		cleanup.Size += stale.size
+	}
+
+	return cleanup, nil
+}
+
+// ArchiveExports compresses the exports last modified before cutoff, with
+// their descriptions, into an archive per server named after now, kept in
+// the archives directory of the server, then removes them.
+func ArchiveExports(root string, cutoff, now time.Time) (Cleanup, error) {
+	exports, err := staleExports(root, cutoff)
+	if err != nil {
+		return Cleanup{}, err
+	}
+
+	byServer := map[string][]staleExport{}
+	var servers []string
+	for _, stale := range exports {
+		if _, ok := byServer[stale.server]; !ok {
+			servers = append(servers, stale.server)
+		}
+		byServer[stale.server] = append(byServer[stale.server], stale)
+	}
+
+	var cleanup Cleanup
+
+	for _, server := range servers {
+		dir := filepath.Join(root, server, ArchivesDirectory)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return cleanup, err
+		}
+
+		path := filepath.Join(dir, "exports-"+now.Format(timestampLayout)+".tar.gz")
+		if err := writeArchive(path, byServer[server]); err != nil {
+			_ = os.Remove(path)
+			return cleanup, err
+		}
+
+		// the exports are only removed once their archive is complete
+		for _, stale := range byServer[server] {
+			for _, file := range stale.files {
+				if err := os.Remove(file); err != nil {
+					return cleanup, err
+				}
+			}
+
+			cleanup.Files++
+			cleanup.Size += stale.size
+		}
+	}
+
+	return cleanup, nil
+}
+
+func writeArchive(path string, exports []staleExport) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	archive := tar.NewWriter(compressed)
+
+	for _, stale := range exports {
+		for _, name := range stale.files {
+			if err := addToArchive(archive, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func addToArchive(archive *tar.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(archive, file)
+	return err
+}
@@ -0,0 +1,249 @@
+// Package diskusage reports the space taken by the files kept in the storage
+// directory, per server, and frees it by moving old exports to a trash,
+// archiving them, and emptying the trash.
+package diskusage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/export"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// The layout of the storage directory. Each server has a directory named
+// after it, holding its exports, snippets and archives; the history, the
+// global snippets and the trash are kept at the root.
+const (
+	ExportsDirectory  = "data"
+	SnippetsDirectory = "snippets"
+	ArchivesDirectory = "archives"
+	TrashDirectory    = ".trash"
+)
+
+// historyFiles are the files of the query and command history
+var historyFiles = []string{".history", ".command_history"}
+
+const (
+	ServerColumn   = "Server"
+	HistoryColumn  = "History"
+	ExportsColumn  = "Exports"
+	SnippetsColumn = "Snippets"
+	ArchivesColumn = "Archives"
+	OtherColumn    = "Other"
+	TotalColumn    = "Total"
+
+	// sharedName stands for the files shared by the servers in the report
+	sharedName = "(shared)"
+)
+
+// Usage is the space taken by the files of a server, in bytes
+type Usage struct {
+	Server      string // empty for the files shared by the servers
+	History     int64
+	Exports     int64
+	ExportFiles int // not counting their descriptions
+	Snippets    int64
+	Archives    int64
+	Other       int64 // e.g. the LLM examples, or the config at the root
+}
+
+func (u Usage) Total() int64 {
+	return u.History + u.Exports + u.Snippets + u.Archives + u.Other
+}
+
+// Report is the space taken by the storage directory
+type Report struct {
+	Shared  Usage
+	Servers []Usage // by name
+	Trash   int64
+}
+
+func (r Report) Total() int64 {
+	total := r.Shared.Total() + r.Trash
+	for _, usage := range r.Servers {
+		total += usage.Total()
+	}
+	return total
+}
+
+// Measure walks the storage directory at root, adding up the size of its files
+func Measure(root string) (Report, error) {
+	var report Report
+	servers := map[string]*Usage{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size := info.Size()
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(relative), "/")
+
+		switch {
+		case parts[0] == TrashDirectory:
+			report.Trash += size
+
+		case len(parts) == 1 && slices.Contains(historyFiles, parts[0]):
+			report.Shared.History += size
+
+		case len(parts) == 1:
+			report.Shared.Other += size
+
+		case parts[0] == SnippetsDirectory:
+			report.Shared.Snippets += size
+
+		default:
+			usage, ok := servers[parts[0]]
+			if !ok {
+				usage = &Usage{Server: parts[0]}
+				servers[parts[0]] = usage
+			}
+
+			switch parts[1] {
+			case ExportsDirectory:
+				usage.Exports += size
+				if !export.IsMetadata(d.Name()) {
+					usage.ExportFiles++
+				}
+			case SnippetsDirectory:
+				usage.Snippets += size
+			case ArchivesDirectory:
+				usage.Archives += size
+			default:
+				usage.Other += size
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	for _, usage := range servers {
+		report.Servers = append(report.Servers, *usage)
+	}
+	slices.SortFunc(report.Servers, func(a, b Usage) int {
+		return strings.Compare(a.Server, b.Server)
+	})
+
+	return report, nil
+}
+
+// Result shows the report as a table, a row per server after the shared
+// files, then the trash and the total
+func (r Report) Result() *psql.Result {
+	result := &psql.Result{
+		Columns: []string{ServerColumn, HistoryColumn, ExportsColumn, SnippetsColumn, ArchivesColumn, OtherColumn, TotalColumn},
+		Message: "Storage usage",
+	}
+
+	row := func(name string, usage Usage) map[string]any {
+		exports := FormatSize(usage.Exports)
+		if usage.ExportFiles > 0 {
+			exports += fmt.Sprintf(" (%d files)", usage.ExportFiles)
+		}
+
+		return map[string]any{
+			ServerColumn:   name,
+			HistoryColumn:  FormatSize(usage.History),
+			ExportsColumn:  exports,
+			SnippetsColumn: FormatSize(usage.Snippets),
+			ArchivesColumn: FormatSize(usage.Archives),
+			OtherColumn:    FormatSize(usage.Other),
+			TotalColumn:    FormatSize(usage.Total()),
+		}
+	}
+
+	result.Rows = append(result.Rows, row(sharedName, r.Shared))
+	for _, usage := range r.Servers {
+		result.Rows = append(result.Rows, row(usage.Server, usage))
+	}
+
+	for _, summary := range []struct {
+		name string
+		size int64
+	}{{"(trash)", r.Trash}, {"(total)", r.Total()}} {
+		row := make(map[string]any, len(result.Columns))
+		for _, column := range result.Columns {
+			row[column] = ""
+		}
+		row[ServerColumn], row[TotalColumn] = summary.name, FormatSize(summary.size)
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
+
+// FormatSize formats a number of bytes, e.g. 1.5 MB
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size)
+	for _, suffix := range []string{"KB", "MB", "GB", "TB"} {
+		value /= unit
+		if value < unit || suffix == "TB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+
+	return fmt.Sprintf("%d B", size)
+}
+
+// EmptyTrash deletes the exports moved to the trash, returning the space freed
+func EmptyTrash(root string) (int64, error) {
+	trash := filepath.Join(root, TrashDirectory)
+
+	var size int64
+	err := filepath.WalkDir(trash, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, os.RemoveAll(trash)
+}
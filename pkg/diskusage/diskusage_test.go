@@ -0,0 +1,193 @@
+package diskusage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// writeFile writes size bytes to the file at the path relative to root, last
+// modified at modified
+func writeFile(t *testing.T, root, path string, size int, modified time.Time) {
+	t.Helper()
+
+	path = filepath.Join(root, filepath.FromSlash(path))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644))
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+// newStorage lays out a storage directory with an old and a recent export of
+// the server local
+func newStorage(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	old := now.AddDate(0, 0, -40)
+
+	writeFile(t, root, ".history", 100, now)
+	writeFile(t, root, ".command_history", 20, now)
+	writeFile(t, root, "config.toml", 5, now)
+	writeFile(t, root, "snippets/users.sql", 30, now)
+	writeFile(t, root, "local/data/orders.csv", 1000, old)
+	writeFile(t, root, "local/data/orders.csv.meta.json", 50, old)
+	writeFile(t, root, "local/data/users.json", 200, now)
+	writeFile(t, root, "local/snippets/daily.sql", 10, now)
+	writeFile(t, root, "staging/llm-examples.md", 7, now)
+
+	return root
+}
+
+func TestMeasure(t *testing.T) {
+	t.Parallel()
+
+	root := newStorage(t)
+	writeFile(t, root, ".trash/20260101-000000/local/old.csv", 300, now)
+
+	report, err := Measure(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, Usage{History: 120, Snippets: 30, Other: 5}, report.Shared)
+	assert.Equal(t, []Usage{
+		{Server: "local", Exports: 1250, ExportFiles: 2, Snippets: 10},
+		{Server: "staging", Other: 7},
+	}, report.Servers)
+	assert.Equal(t, int64(300), report.Trash)
+	assert.Equal(t, int64(1722), report.Total())
+}
+
+func TestMeasureMissingStorage(t *testing.T) {
+	t.Parallel()
+
+	report, err := Measure(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Zero(t, report.Total())
+}
+
+func TestReportResult(t *testing.T) {
+	t.Parallel()
+
+	report := Report{
+		Shared:  Usage{History: 2048},
+		Servers: []Usage{{Server: "local", Exports: 512, ExportFiles: 3}},
+		Trash:   10,
+	}
+
+	result := report.Result()
+	require.Len(t, result.Rows, 4)
+
+	assert.Equal(t, sharedName, result.Rows[0][ServerColumn])
+	assert.Equal(t, "2.0 KB", result.Rows[0][HistoryColumn])
+	assert.Equal(t, "512 B (3 files)", result.Rows[1][ExportsColumn])
+	assert.Equal(t, "(trash)", result.Rows[2][ServerColumn])
+	assert.Equal(t, "", result.Rows[2][ExportsColumn])
+	assert.Equal(t, "2.5 KB", result.Rows[3][TotalColumn])
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 << 40, "3.0 TB"},
+		{2048 << 40, "2048.0 TB"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatSize(tt.size))
+	}
+}
+
+func TestTrashExports(t *testing.T) {
+	t.Parallel()
+
+	root := newStorage(t)
+
+	cleanup, err := TrashExports(root, now.AddDate(0, 0, -30), now)
+	require.NoError(t, err)
+	assert.Equal(t, Cleanup{Files: 1, Size: 1050}, cleanup)
+
+	assert.NoFileExists(t, filepath.Join(root, "local", "data", "orders.csv"))
+	assert.NoFileExists(t, filepath.Join(root, "local", "data", "orders.csv.meta.json"))
+	assert.FileExists(t, filepath.Join(root, "local", "data", "users.json"))
+
+	trash := filepath.Join(root, TrashDirectory, "20260310-120000", "local")
+	assert.FileExists(t, filepath.Join(trash, "orders.csv"))
+	assert.FileExists(t, filepath.Join(trash, "orders.csv.meta.json"))
+
+	report, err := Measure(root)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), report.Trash)
+
+	freed, err := EmptyTrash(root)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), freed)
+	assert.NoDirExists(t, filepath.Join(root, TrashDirectory))
+}
+
+func TestTrashExportsNothingOld(t *testing.T) {
+	t.Parallel()
+
+	root := newStorage(t)
+
+	cleanup, err := TrashExports(root, now.AddDate(0, 0, -60), now)
+	require.NoError(t, err)
+	assert.Zero(t, cleanup)
+	assert.NoDirExists(t, filepath.Join(root, TrashDirectory))
+}
+
+func TestEmptyTrashWithoutTrash(t *testing.T) {
+	t.Parallel()
+
+	freed, err := EmptyTrash(t.TempDir())
+	require.NoError(t, err)
+	assert.Zero(t, freed)
+}
+
+func TestArchiveExports(t *testing.T) {
+	t.Parallel()
+
+	root := newStorage(t)
+
+	cleanup, err := ArchiveExports(root, now.AddDate(0, 0, -30), now)
+	require.NoError(t, err)
+	assert.Equal(t, Cleanup{Files: 1, Size: 1050}, cleanup)
+
+	assert.NoFileExists(t, filepath.Join(root, "local", "data", "orders.csv"))
+	assert.NoFileExists(t, filepath.Join(root, "local", "data", "orders.csv.meta.json"))
+	assert.FileExists(t, filepath.Join(root, "local", "data", "users.json"))
+
+	file, err := os.Open(filepath.Join(root, "local", ArchivesDirectory, "exports-20260310-120000.tar.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	compressed, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	archive := tar.NewReader(compressed)
+	sizes := map[string]int64{}
+	for {
+		header, err := archive.Next()
+		if err != nil {
+			break
+		}
+		sizes[header.Name] = header.Size
+	}
+
+	assert.Equal(t, map[string]int64{"orders.csv": 1000, "orders.csv.meta.json": 50}, sizes)
+}
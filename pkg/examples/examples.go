@@ -0,0 +1,170 @@
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	examplesFileName = "llm_examples.json"
+
+	// MaxPromptExamples is the number of examples included in a prompt
+	MaxPromptExamples = 5
+)
+
+// Example is a natural-language question paired with the SQL the user kept for it
+type Example struct {
+	Prompt    string    `json:"prompt"`
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetPath returns the examples file path for a server
+func GetPath(storageRoot, serverName string) string {
+	if serverName == "" {
+		return ""
+	}
+	return filepath.Join(storageRoot, serverName, examplesFileName)
+}
+
+// Load reads the examples from path, newest first.
+// A missing file is not an error and returns no examples.
+func Load(path string) ([]Example, error) {
+	if path == "" {
+		return []Example{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Example{}, nil
+		}
+		return nil, fmt.Errorf("failed to read LLM examples: %w", err)
+	}
+
+	var examples []Example
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM examples: %w", err)
+	}
+
+	sortExamples(examples)
+
+	return examples, nil
+}
+
+// Add stores a new example and returns the updated list. An existing example
+// for the same prompt is replaced so the latest accepted query wins.
+func Add(path, prompt, query string) ([]Example, error) {
+	prompt = strings.TrimSpace(prompt)
+	query = strings.TrimSpace(query)
+
+	if prompt == "" || query == "" {
+		return nil, fmt.Errorf("an example needs both a prompt and a query")
+	}
+
+	examples, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	examples = slices.DeleteFunc(examples, func(e Example) bool {
+		return strings.EqualFold(e.Prompt, prompt)
+	})
+
+	examples = append(examples, Example{
+		Prompt:    prompt,
+		Query:     query,
+		CreatedAt: time.Now(),
+	})
+	sortExamples(examples)
+
+	if err := write(path, examples); err != nil {
+		return nil, err
+	}
+
+	return examples, nil
+}
+
+// Delete removes the example with the given prompt and returns the updated list
+func Delete(path, prompt string) ([]Example, error) {
+	examples, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	examples = slices.DeleteFunc(examples, func(e Example) bool {
+		return e.Prompt == prompt
+	})
+
+	if err := write(path, examples); err != nil {
+		return nil, err
+	}
+
+	return examples, nil
+}
+
+// FormatPrompt prepends up to MaxPromptExamples examples to the prompt so the
+// LLM can follow the conventions of queries accepted on this server.
+func FormatPrompt(examples []Example, prompt string) string {
+	if len(examples) == 0 {
+		return prompt
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Examples of questions and the SQL queries accepted for them on this database:\n\n")
+
+	for i, example := range examples {
+		if i == MaxPromptExamples {
+			break
+		}
+
+		sb.WriteString("Question: ")
+		sb.WriteString(example.Prompt)
+		sb.WriteString("\nSQL:\n```sql\n")
+		sb.WriteString(example.Query)
+		sb.WriteString("\n```\n\n")
+	}
+
+	sb.WriteString(prompt)
+
+	return sb.String()
+}
+
+// write performs an atomic write of the examples file
+func write(path string, examples []Example) error {
+	if path == "" {
+		return fmt.Errorf("no storage path for LLM examples")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(examples, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode LLM examples: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write LLM examples: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to replace LLM examples file: %w", err)
+	}
+
+	return nil
+}
+
+func sortExamples(examples []Example) {
+	slices.SortStableFunc(examples, func(a, b Example) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+}
@@ -0,0 +1,106 @@
+package examples
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := GetPath(t.TempDir(), "local")
+
+	examples, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, examples)
+
+	_, err = Add(path, "count users", "SELECT count(*) FROM users;")
+	require.NoError(t, err)
+
+	examples, err = Add(path, "latest orders", "SELECT * FROM orders ORDER BY created_at DESC;")
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	assert.Equal(t, "latest orders", examples[0].Prompt)
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"latest orders", "count users"}, prompts(loaded))
+}
+
+func TestAddReplacesSamePrompt(t *testing.T) {
+	t.Parallel()
+
+	path := GetPath(t.TempDir(), "local")
+
+	_, err := Add(path, "count users", "SELECT count(*) FROM users;")
+	require.NoError(t, err)
+
+	examples, err := Add(path, "Count users", "SELECT count(id) FROM users;")
+	require.NoError(t, err)
+	require.Len(t, examples, 1)
+	assert.Equal(t, "SELECT count(id) FROM users;", examples[0].Query)
+}
+
+func TestAddRequiresPromptAndQuery(t *testing.T) {
+	t.Parallel()
+
+	path := GetPath(t.TempDir(), "local")
+
+	_, err := Add(path, " ", "SELECT 1;")
+	assert.Error(t, err)
+
+	_, err = Add(path, "one", "")
+	assert.Error(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), examplesFileName)
+
+	_, err := Add(path, "count users", "SELECT count(*) FROM users;")
+	require.NoError(t, err)
+	_, err = Add(path, "latest orders", "SELECT * FROM orders;")
+	require.NoError(t, err)
+
+	examples, err := Delete(path, "count users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"latest orders"}, prompts(examples))
+}
+
+func TestFormatPrompt(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/ask count users", FormatPrompt(nil, "/ask count users"))
+
+	formatted := FormatPrompt([]Example{
+		{Prompt: "latest orders", Query: "SELECT * FROM orders;"},
+	}, "/ask count users")
+
+	assert.Contains(t, formatted, "Question: latest orders\nSQL:\n```sql\nSELECT * FROM orders;\n```")
+	assert.Contains(t, formatted, "\n\n/ask count users")
+}
+
+func TestFormatPromptLimitsExamples(t *testing.T) {
+	t.Parallel()
+
+	var examples []Example
+	for range MaxPromptExamples + 2 {
+		examples = append(examples, Example{Prompt: "q", Query: "SELECT 1;"})
+	}
+
+	formatted := FormatPrompt(examples, "/ask")
+	assert.Equal(t, MaxPromptExamples, strings.Count(formatted, "Question: "))
+}
+
+func prompts(examples []Example) []string {
+	out := make([]string, len(examples))
+	for i, e := range examples {
+		out[i] = e.Prompt
+	}
+	return out
+}
@@ -1,10 +1,12 @@
 package export
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -76,6 +78,26 @@ func AsCsv(storage string, data [][]string, fileName string) (string, error) {
 	return fileName, nil
 }
 
+// AsMarkdown saves the provided Markdown document, keeping the existing ones.
+func AsMarkdown(storage string, text string, fileName string) (string, error) {
+	records, err := load(storage, ".md")
+	if err != nil {
+		return "", err
+	}
+
+	fileName = generateUniqueName(fileName, records)
+
+	if err := os.MkdirAll(storage, 0o755); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(filepath.Join(storage, fileName), []byte(text), 0o644); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
 func load(path string, ext string) ([]string, error) {
 	var records []string
 
@@ -96,7 +118,7 @@ func load(path string, ext string) ([]string, error) {
 			continue
 		}
 
-		if filepath.Ext(file.Name()) != ext {
+		if filepath.Ext(file.Name()) != ext || IsMetadata(file.Name()) {
 			continue
 		}
 
@@ -195,3 +217,80 @@ func toSlice(m map[string]any, header []string) []string {
 
 	return record
 }
+
+// Delimited returns the rows as CSV, or TSV with a tab for comma, with the
+// columns in the given order after a header. NULL values are left empty.
+func Delimited(queryResults []map[string]any, columns []string, comma rune) (string, error) {
+	if len(queryResults) == 0 {
+		return "", errors.New("no query results to copy")
+	}
+
+	var sb strings.Builder
+
+	writer := csv.NewWriter(&sb)
+	writer.Comma = comma
+
+	if err := writer.Write(columns); err != nil {
+		return "", err
+	}
+
+	for _, result := range queryResults {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			if value := result[column]; value != nil {
+				record[i] = fmt.Sprintf("%v", value)
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+
+	return sb.String(), writer.Error()
+}
+
+// OrderedRow is a row marshalled to JSON with its columns in the order of the
+// results, rather than sorted like the keys of a map. Without columns, the
+// keys of the row are sorted.
+type OrderedRow struct {
+	Columns []string
+	Values  map[string]any
+}
+
+// MarshalJSON writes the columns of the row as an object, in order
+func (r OrderedRow) MarshalJSON() ([]byte, error) {
+	columns := r.Columns
+	if columns == nil {
+		columns = slices.Sorted(maps.Keys(r.Values))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(r.Values[column])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", column, err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -1,6 +1,8 @@
 package export
 
 import (
+	"encoding/json"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -309,3 +311,124 @@ func BenchmarkPrepareJSONAll(b *testing.B) {
 		_, _ = PrepareJSON(sampleResults, []int{}, true)
 	}
 }
+
+func TestDelimited(t *testing.T) {
+	t.Parallel()
+
+	results := []map[string]any{
+		{"id": 1, "name": "Ada, Countess", "note": nil},
+		{"id": 2, "name": "Grace", "note": "tab\there"},
+	}
+
+	csv, err := Delimited(results, []string{"name", "id", "note"}, ',')
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "name,id,note\n\"Ada, Countess\",1,\nGrace,2,tab\there\n"; csv != expected {
+		t.Errorf("expected %q, got %q", expected, csv)
+	}
+
+	tsv, err := Delimited(results, []string{"id", "note"}, '\t')
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "id\tnote\n1\t\n2\t\"tab\there\"\n"; tsv != expected {
+		t.Errorf("expected %q, got %q", expected, tsv)
+	}
+
+	if _, err := Delimited(nil, []string{"id"}, ','); err == nil {
+		t.Error("expected an error without results")
+	}
+}
+
+func TestOrderedRow(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{"zip": "1010", "id": 1, "name": nil}
+
+	data, err := json.Marshal(OrderedRow{Columns: []string{"zip", "id", "name"}, Values: values})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"zip":"1010","id":1,"name":null}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	data, err = json.Marshal(OrderedRow{Values: values})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"id":1,"name":null,"zip":"1010"}`; string(data) != expected {
+		t.Errorf("expected the keys sorted without columns, got %s", data)
+	}
+}
+
+func TestNewMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want Metadata
+	}{
+		{
+			name: "JSON rows",
+			data: []map[string]any{{"id": 1, "email": "a@b.c"}, {"id": 2, "email": "d@e.f"}},
+			want: Metadata{Query: "SELECT id, email FROM users", Server: "local", Columns: []string{"email", "id"}, RowCount: 2},
+		},
+		{
+			name: "JSON row",
+			data: map[string]any{"id": 1},
+			want: Metadata{Query: "SELECT id, email FROM users", Server: "local", Columns: []string{"id"}, RowCount: 1},
+		},
+		{
+			name: "CSV rows after their header",
+			data: [][]string{{"email", "id"}, {"a@b.c", "1"}},
+			want: Metadata{Query: "SELECT id, email FROM users", Server: "local", Columns: []string{"email", "id"}, RowCount: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMetadata(" SELECT id, email FROM users\n", "local", tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	storage := t.TempDir()
+
+	fileName, err := AsCsv(storage, [][]string{{"id"}, {"1"}}, "users.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(storage, fileName)
+
+	if metadata, err := ReadMetadata(path); err != nil || metadata != nil {
+		t.Fatalf("expected no metadata, got %+v, %v", metadata, err)
+	}
+
+	want := Metadata{Query: "SELECT id FROM users", Server: "local", Columns: []string{"id"}, RowCount: 1}
+	if err := WriteMetadata(storage, fileName, want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, err := ReadMetadata(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata == nil || !reflect.DeepEqual(*metadata, want) {
+		t.Errorf("expected %+v, got %+v", want, metadata)
+	}
+
+	// the metadata file doesn't count as an export when naming the next one
+	names, err := load(storage, ".json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no JSON exports, got %v", names)
+	}
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MetadataSuffix ends the name of the file describing an export, written
+// next to it: orders.csv is described by orders.csv.meta.json
+const MetadataSuffix = ".meta.json"
+
+// Metadata describes where the rows of an export came from
+type Metadata struct {
+	Query    string   `json:"query"`
+	Server   string   `json:"server"`
+	Columns  []string `json:"columns"`
+	RowCount int      `json:"row_count"`
+}
+
+// IsMetadata reports whether the file named name describes an export
+func IsMetadata(name string) bool {
+	return strings.HasSuffix(name, MetadataSuffix)
+}
+
+// MetadataPath returns the path of the file describing the export at path
+func MetadataPath(path string) string {
+	return path + MetadataSuffix
+}
+
+// NewMetadata describes the data exported from the results of query on
+// server: the rows prepared by PrepareJSON, or by PrepareCSV after their
+// header
+func NewMetadata(query, server string, data any) Metadata {
+	metadata := Metadata{Query: strings.TrimSpace(query), Server: server}
+
+	switch data := data.(type) {
+	case []map[string]any:
+		metadata.RowCount = len(data)
+		if len(data) > 0 {
+			metadata.Columns = sortedKeys(data[0])
+		}
+
+	case map[string]any:
+		metadata.RowCount = 1
+		metadata.Columns = sortedKeys(data)
+
+	case [][]string:
+		if len(data) > 0 {
+			metadata.Columns = data[0]
+			metadata.RowCount = len(data) - 1
+		}
+	}
+
+	return metadata
+}
+
+func sortedKeys(row map[string]any) []string {
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// WriteMetadata writes the file describing the export named fileName
+func WriteMetadata(storage, fileName string, metadata Metadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(MetadataPath(filepath.Join(storage, fileName)), data, 0o644)
+}
+
+// ReadMetadata reads the file describing the export at path. It returns nil
+// without an error when there is none, e.g. for the exports written before
+// the files were.
+func ReadMetadata(path string) (*Metadata, error) {
+	data, err := os.ReadFile(MetadataPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
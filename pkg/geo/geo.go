@@ -0,0 +1,463 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Geometry is a decoded PostGIS geometry or geography value.
+//
+// Coordinates holds []float64 for a Point, [][]float64 for a LineString or
+// MultiPoint, [][][]float64 for a Polygon or MultiLineString and
+// [][][][]float64 for a MultiPolygon.
+type Geometry struct {
+	Type        string
+	SRID        int
+	HasZ, HasM  bool
+	Coordinates any
+	Geometries  []*Geometry
+}
+
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+var typeNames = map[uint32]string{
+	1: "Point",
+	2: "LineString",
+	3: "Polygon",
+	4: "MultiPoint",
+	5: "MultiLineString",
+	6: "MultiPolygon",
+	7: "GeometryCollection",
+}
+
+// Parse decodes a value returned by PostgreSQL for a geometry or geography
+// column: hex encoded (E)WKB text or raw WKB bytes.
+func Parse(value any) (*Geometry, bool) {
+	var data []byte
+
+	switch v := value.(type) {
+	case string:
+		if len(v) < 10 || len(v)%2 != 0 {
+			return nil, false
+		}
+
+		decoded, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, false
+		}
+		data = decoded
+
+	case []byte:
+		data = v
+
+	default:
+		return nil, false
+	}
+
+	r := &reader{data: data}
+	g, err := r.geometry()
+	if err != nil || r.pos != len(data) {
+		return nil, false
+	}
+
+	return g, true
+}
+
+type reader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+var errTruncated = errors.New("truncated WKB")
+
+func (r *reader) uint32() (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, errTruncated
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *reader) float64() (float64, error) {
+	if r.pos+8 > len(r.data) {
+		return 0, errTruncated
+	}
+	v := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+	return v, nil
+}
+
+func (r *reader) geometry() (*Geometry, error) {
+	if r.pos >= len(r.data) {
+		return nil, errTruncated
+	}
+
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, errors.New("invalid byte order")
+	}
+	r.pos++
+
+	kind, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Geometry{
+		HasZ: kind&ewkbZ != 0,
+		HasM: kind&ewkbM != 0,
+	}
+
+	if kind&ewkbSRID != 0 {
+		srid, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		g.SRID = int(srid)
+	}
+
+	// ISO WKB encodes the dimensions as thousands, e.g. 1001 is a Point Z
+	base := kind &^ (ewkbZ | ewkbM | ewkbSRID)
+	switch base / 1000 {
+	case 1:
+		g.HasZ = true
+	case 2:
+		g.HasM = true
+	case 3:
+		g.HasZ, g.HasM = true, true
+	}
+	base %= 1000
+
+	name, ok := typeNames[base]
+	if !ok {
+		return nil, fmt.Errorf("unsupported geometry type %d", base)
+	}
+	g.Type = name
+
+	dims := 2
+	if g.HasZ {
+		dims++
+	}
+	if g.HasM {
+		dims++
+	}
+
+	switch name {
+	case "Point":
+		g.Coordinates, err = r.point(dims)
+	case "LineString":
+		g.Coordinates, err = r.points(dims)
+	case "Polygon":
+		g.Coordinates, err = r.rings(dims)
+	case "MultiPoint", "MultiLineString", "MultiPolygon", "GeometryCollection":
+		err = r.collection(g)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (r *reader) point(dims int) ([]float64, error) {
+	point := make([]float64, dims)
+	for i := range point {
+		v, err := r.float64()
+		if err != nil {
+			return nil, err
+		}
+		point[i] = v
+	}
+
+	// an empty point is encoded with NaN coordinates
+	if math.IsNaN(point[0]) {
+		return nil, nil
+	}
+
+	return point, nil
+}
+
+func (r *reader) points(dims int) ([][]float64, error) {
+	n, err := r.count(dims * 8)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([][]float64, n)
+	for i := range points {
+		if points[i], err = r.point(dims); err != nil {
+			return nil, err
+		}
+	}
+
+	return points, nil
+}
+
+func (r *reader) rings(dims int) ([][][]float64, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return nil, err
+	}
+
+	rings := make([][][]float64, n)
+	for i := range rings {
+		if rings[i], err = r.points(dims); err != nil {
+			return nil, err
+		}
+	}
+
+	return rings, nil
+}
+
+// count reads an element count, rejecting counts the remaining data cannot hold
+func (r *reader) count(minSize int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+
+	if int(n)*minSize > len(r.data)-r.pos {
+		return 0, errTruncated
+	}
+
+	return int(n), nil
+}
+
+func (r *reader) collection(g *Geometry) error {
+	n, err := r.count(5)
+	if err != nil {
+		return err
+	}
+
+	var (
+		points   [][]float64
+		lines    [][][]float64
+		polygons [][][][]float64
+	)
+
+	for range n {
+		member, err := r.geometry()
+		if err != nil {
+			return err
+		}
+
+		if g.Type != "GeometryCollection" && "Multi"+member.Type != g.Type {
+			return fmt.Errorf("%s cannot contain a %s", g.Type, member.Type)
+		}
+
+		switch g.Type {
+		case "MultiPoint":
+			if p := member.Coordinates.([]float64); p != nil {
+				points = append(points, p)
+			}
+		case "MultiLineString":
+			lines = append(lines, member.Coordinates.([][]float64))
+		case "MultiPolygon":
+			polygons = append(polygons, member.Coordinates.([][][]float64))
+		default:
+			g.Geometries = append(g.Geometries, member)
+		}
+	}
+
+	switch g.Type {
+	case "MultiPoint":
+		g.Coordinates = points
+	case "MultiLineString":
+		g.Coordinates = lines
+	case "MultiPolygon":
+		g.Coordinates = polygons
+	}
+
+	return nil
+}
+
+// WKT returns the well-known text representation, prefixed with the SRID
+// when the value has one (EWKT).
+func (g *Geometry) WKT() string {
+	if g.SRID != 0 {
+		return fmt.Sprintf("SRID=%d;%s", g.SRID, g.wkt())
+	}
+	return g.wkt()
+}
+
+func (g *Geometry) wkt() string {
+	name := strings.ToUpper(g.Type)
+	switch {
+	case g.HasZ && g.HasM:
+		name += " ZM"
+	case g.HasZ:
+		name += " Z"
+	case g.HasM:
+		name += " M"
+	}
+
+	var body string
+	switch c := g.Coordinates.(type) {
+	case []float64:
+		if c != nil {
+			body = "(" + formatPoint(c) + ")"
+		}
+	case [][]float64:
+		if len(c) > 0 {
+			if g.Type == "MultiPoint" {
+				parts := make([]string, len(c))
+				for i, p := range c {
+					parts[i] = "(" + formatPoint(p) + ")"
+				}
+				body = "(" + strings.Join(parts, ",") + ")"
+			} else {
+				body = formatPoints(c)
+			}
+		}
+	case [][][]float64:
+		if len(c) > 0 {
+			body = formatRings(c)
+		}
+	case [][][][]float64:
+		if len(c) > 0 {
+			parts := make([]string, len(c))
+			for i, polygon := range c {
+				parts[i] = formatRings(polygon)
+			}
+			body = "(" + strings.Join(parts, ",") + ")"
+		}
+	default:
+		if len(g.Geometries) > 0 {
+			parts := make([]string, len(g.Geometries))
+			for i, member := range g.Geometries {
+				parts[i] = member.wkt()
+			}
+			body = "(" + strings.Join(parts, ",") + ")"
+		}
+	}
+
+	if body == "" {
+		return name + " EMPTY"
+	}
+
+	if g.HasZ || g.HasM {
+		return name + " " + body
+	}
+
+	return name + body
+}
+
+func formatPoint(p []float64) string {
+	parts := make([]string, len(p))
+	for i, v := range p {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatPoints(points [][]float64) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = formatPoint(p)
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+func formatRings(rings [][][]float64) string {
+	parts := make([]string, len(rings))
+	for i, ring := range rings {
+		parts[i] = formatPoints(ring)
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// GeoJSON returns the geometry as an RFC 7946 GeoJSON geometry object
+func (g *Geometry) GeoJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(g.geoJSON()); err != nil {
+		return nil, fmt.Errorf("failed to encode GeoJSON: %w", err)
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+func (g *Geometry) geoJSON() map[string]any {
+	if g.Type == "GeometryCollection" {
+		geometries := make([]map[string]any, len(g.Geometries))
+		for i, member := range g.Geometries {
+			geometries[i] = member.geoJSON()
+		}
+		return map[string]any{"type": g.Type, "geometries": geometries}
+	}
+
+	coordinates := g.Coordinates
+	if p, ok := coordinates.([]float64); ok && p == nil {
+		coordinates = []float64{}
+	}
+
+	return map[string]any{"type": g.Type, "coordinates": coordinates}
+}
+
+// Points returns every vertex of the geometry
+func (g *Geometry) Points() [][]float64 {
+	var points [][]float64
+
+	switch c := g.Coordinates.(type) {
+	case []float64:
+		if c != nil {
+			points = append(points, c)
+		}
+	case [][]float64:
+		points = append(points, c...)
+	case [][][]float64:
+		for _, ring := range c {
+			points = append(points, ring...)
+		}
+	case [][][][]float64:
+		for _, polygon := range c {
+			for _, ring := range polygon {
+				points = append(points, ring...)
+			}
+		}
+	}
+
+	for _, member := range g.Geometries {
+		points = append(points, member.Points()...)
+	}
+
+	return points
+}
+
+// Bounds returns the bounding box of the geometry, false when it is empty
+func (g *Geometry) Bounds() (minX, minY, maxX, maxY float64, ok bool) {
+	points := g.Points()
+	if len(points) == 0 {
+		return 0, 0, 0, 0, false
+	}
+
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+
+	for _, p := range points {
+		minX, maxX = min(minX, p[0]), max(maxX, p[0])
+		minY, maxY = min(minY, p[1]), max(maxY, p[1])
+	}
+
+	return minX, minY, maxX, maxY, true
+}
@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wkb builds little endian WKB from a type code followed by counts and coordinates
+func wkb(kind uint32, values ...any) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(1)
+	_ = binary.Write(&buf, binary.LittleEndian, kind)
+	for _, v := range values {
+		switch v := v.(type) {
+		case int:
+			_ = binary.Write(&buf, binary.LittleEndian, uint32(v))
+		case float64:
+			_ = binary.Write(&buf, binary.LittleEndian, v)
+		case []byte:
+			buf.Write(v)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestParseWKT(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "ewkb point with srid",
+			value:    "0101000020E6100000000000000000F03F0000000000000040",
+			expected: "SRID=4326;POINT(1 2)",
+		},
+		{
+			name:     "raw wkb point",
+			value:    wkb(1, 1.5, -2.25),
+			expected: "POINT(1.5 -2.25)",
+		},
+		{
+			name:     "empty point",
+			value:    wkb(1, math.NaN(), math.NaN()),
+			expected: "POINT EMPTY",
+		},
+		{
+			name:     "point z",
+			value:    wkb(1|ewkbZ, 1.0, 2.0, 3.0),
+			expected: "POINT Z (1 2 3)",
+		},
+		{
+			name:     "iso point zm",
+			value:    wkb(3001, 1.0, 2.0, 3.0, 4.0),
+			expected: "POINT ZM (1 2 3 4)",
+		},
+		{
+			name:     "linestring",
+			value:    wkb(2, 2, 0.0, 0.0, 1.0, 1.0),
+			expected: "LINESTRING(0 0,1 1)",
+		},
+		{
+			name:     "polygon",
+			value:    wkb(3, 1, 4, 0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 0.0),
+			expected: "POLYGON((0 0,1 0,1 1,0 0))",
+		},
+		{
+			name:     "multipoint",
+			value:    wkb(4, 2, wkb(1, 1.0, 2.0), wkb(1, 3.0, 4.0)),
+			expected: "MULTIPOINT((1 2),(3 4))",
+		},
+		{
+			name:     "multipolygon",
+			value:    wkb(6, 1, wkb(3, 1, 4, 0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 0.0)),
+			expected: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)))",
+		},
+		{
+			name:     "geometry collection",
+			value:    wkb(7, 2, wkb(1, 1.0, 2.0), wkb(2, 2, 0.0, 0.0, 1.0, 1.0)),
+			expected: "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1))",
+		},
+		{
+			name:     "empty collection",
+			value:    wkb(7, 0),
+			expected: "GEOMETRYCOLLECTION EMPTY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, ok := Parse(tt.value)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, g.WKT())
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{name: "plain text", value: "hello world"},
+		{name: "uuid hex", value: "0123456789abcdef0123456789abcdef"},
+		{name: "truncated", value: hex.EncodeToString(wkb(2, 2, 0.0, 0.0))},
+		{name: "trailing bytes", value: append(wkb(1, 1.0, 2.0), 0)},
+		{name: "unknown type", value: wkb(42)},
+		{name: "huge count", value: wkb(2, 1<<30)},
+		{name: "mixed multi", value: wkb(4, 1, wkb(2, 0))},
+		{name: "number", value: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, ok := Parse(tt.value)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	g, ok := Parse(wkb(7, 2, wkb(1, 1.0, 2.0), wkb(4, 1, wkb(1, 3.0, 4.0))))
+	require.True(t, ok)
+
+	data, err := g.GeoJSON()
+	require.NoError(t, err)
+
+	compact := strings.Join(strings.Fields(string(data)), "")
+	assert.Equal(t,
+		`{"geometries":[{"coordinates":[1,2],"type":"Point"},{"coordinates":[[3,4]],"type":"MultiPoint"}],"type":"GeometryCollection"}`,
+		compact,
+	)
+
+	empty, ok := Parse(wkb(1, math.NaN(), math.NaN()))
+	require.True(t, ok)
+
+	data, err = empty.GeoJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"coordinates":[],"type":"Point"}`, strings.Join(strings.Fields(string(data)), ""))
+}
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+
+	g, ok := Parse(wkb(2, 3, -1.0, 5.0, 2.0, -3.0, 0.5, 0.5))
+	require.True(t, ok)
+
+	minX, minY, maxX, maxY, ok := g.Bounds()
+	require.True(t, ok)
+	assert.Equal(t, []float64{-1, -3, 2, 5}, []float64{minX, minY, maxX, maxY})
+
+	empty, _ := Parse(wkb(7, 0))
+	_, _, _, _, ok = empty.Bounds()
+	assert.False(t, ok)
+}
+
+func TestSketch(t *testing.T) {
+	t.Parallel()
+
+	g, ok := Parse(wkb(2, 2, 0.0, 0.0, 4.0, 2.0))
+	require.True(t, ok)
+
+	expected := strings.Join([]string{
+		"2",
+		"+-----+",
+		"|    *|",
+		"|  .. |",
+		"|*.   |",
+		"+-----+",
+		"0",
+		"0     4",
+	}, "\n")
+
+	assert.Equal(t, expected, Sketch(g, 5, 3))
+
+	point, ok := Parse(wkb(1, 1.0, 1.0))
+	require.True(t, ok)
+	assert.Contains(t, Sketch(point, 3, 3), "| * |")
+
+	empty, _ := Parse(wkb(7, 0))
+	assert.Empty(t, Sketch(empty, 5, 3))
+}
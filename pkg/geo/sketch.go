@@ -0,0 +1,112 @@
+package geo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	vertexMark = '*'
+	edgeMark   = '.'
+)
+
+// Sketch draws the vertices and edges of the geometry inside its bounding box
+// on a grid of width×height characters, labelled with the box coordinates.
+func Sketch(g *Geometry, width, height int) string {
+	minX, minY, maxX, maxY, ok := g.Bounds()
+	if !ok || width < 2 || height < 1 {
+		return ""
+	}
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	project := func(p []float64) (int, int) {
+		col, row := (width-1)/2, (height-1)/2
+		if maxX > minX {
+			col = int((p[0] - minX) / (maxX - minX) * float64(width-1))
+		}
+		if maxY > minY {
+			row = int((maxY - p[1]) / (maxY - minY) * float64(height-1))
+		}
+		return col, row
+	}
+
+	for _, path := range g.paths() {
+		for i := 1; i < len(path); i++ {
+			drawLine(grid, project, path[i-1], path[i])
+		}
+	}
+
+	for _, p := range g.Points() {
+		col, row := project(p)
+		grid[row][col] = vertexMark
+	}
+
+	border := "+" + strings.Repeat("-", width) + "+"
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", formatFloat(maxY))
+	sb.WriteString(border + "\n")
+	for _, line := range grid {
+		sb.WriteString("|" + string(line) + "|\n")
+	}
+	sb.WriteString(border + "\n")
+	fmt.Fprintf(&sb, "%s\n", formatFloat(minY))
+
+	left, right := formatFloat(minX), formatFloat(maxX)
+	gap := max(1, width+2-len(left)-len(right))
+	sb.WriteString(left + strings.Repeat(" ", gap) + right)
+
+	return sb.String()
+}
+
+// paths returns the connected vertex sequences: line strings and polygon rings
+func (g *Geometry) paths() [][][]float64 {
+	var paths [][][]float64
+
+	switch c := g.Coordinates.(type) {
+	case [][]float64:
+		if g.Type == "LineString" {
+			paths = append(paths, c)
+		}
+	case [][][]float64:
+		paths = append(paths, c...)
+	case [][][][]float64:
+		for _, polygon := range c {
+			paths = append(paths, polygon...)
+		}
+	}
+
+	for _, member := range g.Geometries {
+		paths = append(paths, member.paths()...)
+	}
+
+	return paths
+}
+
+func drawLine(grid [][]rune, project func([]float64) (int, int), from, to []float64) {
+	x0, y0 := project(from)
+	x1, y1 := project(to)
+
+	steps := max(abs(x1-x0), abs(y1-y0))
+	for i := 1; i < steps; i++ {
+		col := x0 + (x1-x0)*i/steps
+		row := y0 + (y1-y0)*i/steps
+		grid[row][col] = edgeMark
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
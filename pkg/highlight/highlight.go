@@ -0,0 +1,298 @@
+// Package highlight parses the rules used to highlight rows of the results,
+// e.g. "status = 'failed' -> red" or "amount > 1000 -> bold".
+package highlight
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Null is how NULL values are shown in the results
+const Null = "NULL"
+
+// Rule styles the rows where the value of a column matches a condition
+type Rule struct {
+	Column   string
+	Operator string
+	Value    string
+	Style    Style
+}
+
+// Style is how a matching row is shown. Colours are ANSI numbers or hex
+// values, empty when not set.
+type Style struct {
+	Foreground string
+	Background string
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Faint      bool
+}
+
+var operators = []string{"!=", "<>", ">=", "<=", "=", ">", "<"}
+
+var colours = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// ParseRules parses every rule, skipping the invalid ones, which are
+// reported together in the error.
+func ParseRules(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	var errs []error
+
+	for _, rule := range rules {
+		r, err := ParseRule(rule)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parsed = append(parsed, r)
+	}
+
+	return parsed, errors.Join(errs...)
+}
+
+// ParseRule parses a rule written as "<column> <operator> <value> -> <style>".
+// The operator is one of =, !=, <>, >, >=, <, <=, "is null" or "is not null".
+// String values are quoted with single quotes.
+func ParseRule(rule string) (Rule, error) {
+	// the style never contains an arrow, the value of the condition may
+	condition, style, ok := cutLast(rule, "->")
+	if !ok {
+		condition, style, ok = cutLast(rule, "→")
+	}
+	if !ok {
+		return Rule{}, fmt.Errorf("invalid highlight rule %q, expected: <condition> -> <style>", rule)
+	}
+
+	r, err := parseCondition(strings.TrimSpace(condition))
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid highlight rule %q: %w", rule, err)
+	}
+
+	if r.Style, err = ParseStyle(style); err != nil {
+		return Rule{}, fmt.Errorf("invalid highlight rule %q: %w", rule, err)
+	}
+
+	return r, nil
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i == -1 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+func parseCondition(condition string) (Rule, error) {
+	lower := strings.ToLower(condition)
+	for _, suffix := range []string{" is not null", " is null"} {
+		if strings.HasSuffix(lower, suffix) {
+			column := unquoteIdentifier(strings.TrimSpace(condition[:len(condition)-len(suffix)]))
+			if column == "" {
+				return Rule{}, errors.New("missing column")
+			}
+			return Rule{Column: column, Operator: strings.TrimSpace(suffix)}, nil
+		}
+	}
+
+	i, op := findOperator(condition)
+	if op == "" {
+		return Rule{}, errors.New("missing operator")
+	}
+
+	column := unquoteIdentifier(strings.TrimSpace(condition[:i]))
+	if column == "" {
+		return Rule{}, errors.New("missing column")
+	}
+
+	value := strings.TrimSpace(condition[i+len(op):])
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	} else if value == "" {
+		return Rule{}, errors.New("missing value")
+	}
+
+	if op == "<>" {
+		op = "!="
+	}
+
+	return Rule{Column: column, Operator: op, Value: value}, nil
+}
+
+// findOperator returns the first comparison operator outside quotes
+func findOperator(condition string) (int, string) {
+	var quote byte
+	for i := 0; i < len(condition); i++ {
+		c := condition[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		default:
+			for _, op := range operators {
+				if strings.HasPrefix(condition[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+func unquoteIdentifier(column string) string {
+	if len(column) >= 2 && column[0] == '"' && column[len(column)-1] == '"' {
+		return column[1 : len(column)-1]
+	}
+	return column
+}
+
+// ParseStyle parses a space separated list of attributes (bold, italic,
+// underline, faint), a foreground colour and "on <colour>" for the background.
+// Colours are names (red, green...), ANSI numbers or hex values.
+func ParseStyle(spec string) (Style, error) {
+	var style Style
+
+	words := strings.Fields(strings.ToLower(spec))
+	if len(words) == 0 {
+		return style, errors.New("missing style")
+	}
+
+	for i := 0; i < len(words); i++ {
+		switch word := words[i]; word {
+		case "bold":
+			style.Bold = true
+		case "italic":
+			style.Italic = true
+		case "underline":
+			style.Underline = true
+		case "faint":
+			style.Faint = true
+		case "on":
+			if i+1 == len(words) {
+				return style, errors.New("missing background colour after on")
+			}
+			i++
+			colour, err := parseColour(words[i])
+			if err != nil {
+				return style, err
+			}
+			style.Background = colour
+		default:
+			colour, err := parseColour(word)
+			if err != nil {
+				return style, err
+			}
+			style.Foreground = colour
+		}
+	}
+
+	return style, nil
+}
+
+func parseColour(colour string) (string, error) {
+	if ansi, ok := colours[colour]; ok {
+		return ansi, nil
+	}
+
+	if n, err := strconv.Atoi(colour); err == nil && n >= 0 && n <= 255 {
+		return colour, nil
+	}
+
+	if len(colour) == 7 && colour[0] == '#' {
+		if _, err := strconv.ParseUint(colour[1:], 16, 32); err == nil {
+			return colour, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown colour or attribute %q", colour)
+}
+
+// Matches reports whether the value of the rule's column in the row satisfies
+// the condition. Values are compared as numbers when both sides are numbers.
+func (r Rule) Matches(row map[string]string) bool {
+	value, ok := row[r.Column]
+	if !ok {
+		return false
+	}
+
+	switch r.Operator {
+	case "is null":
+		return value == Null
+	case "is not null":
+		return value != Null
+	}
+
+	if value == Null {
+		return false
+	}
+
+	order := strings.Compare(value, r.Value)
+	if a, err := strconv.ParseFloat(value, 64); err == nil {
+		if b, err := strconv.ParseFloat(r.Value, 64); err == nil {
+			order = cmp.Compare(a, b)
+		}
+	}
+
+	switch r.Operator {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	}
+
+	return false
+}
+
+// Match returns the style of the row, combining every matching rule. Later
+// rules take precedence for colours.
+func Match(rules []Rule, row map[string]string) (Style, bool) {
+	var style Style
+	var matched bool
+
+	for _, rule := range rules {
+		if !rule.Matches(row) {
+			continue
+		}
+
+		matched = true
+		style.Bold = style.Bold || rule.Style.Bold
+		style.Italic = style.Italic || rule.Style.Italic
+		style.Underline = style.Underline || rule.Style.Underline
+		style.Faint = style.Faint || rule.Style.Faint
+
+		if rule.Style.Foreground != "" {
+			style.Foreground = rule.Style.Foreground
+		}
+		if rule.Style.Background != "" {
+			style.Background = rule.Style.Background
+		}
+	}
+
+	return style, matched
+}
@@ -0,0 +1,145 @@
+package highlight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        string
+		expected    Rule
+		expectError bool
+	}{
+		{
+			name:     "string value",
+			rule:     "status = 'failed' -> red",
+			expected: Rule{Column: "status", Operator: "=", Value: "failed", Style: Style{Foreground: "1"}},
+		},
+		{
+			name:     "number value",
+			rule:     "amount > 1000 -> bold",
+			expected: Rule{Column: "amount", Operator: ">", Value: "1000", Style: Style{Bold: true}},
+		},
+		{
+			name:     "unicode arrow",
+			rule:     "amount >= 10 → bold yellow on #1e1e2e",
+			expected: Rule{Column: "amount", Operator: ">=", Value: "10", Style: Style{Bold: true, Foreground: "3", Background: "#1e1e2e"}},
+		},
+		{
+			name:     "not equal",
+			rule:     "state <> 'idle' -> 208",
+			expected: Rule{Column: "state", Operator: "!=", Value: "idle", Style: Style{Foreground: "208"}},
+		},
+		{
+			name:     "quoted column and operators in value",
+			rule:     `"Order Total" = 'a=b->c' -> faint`,
+			expected: Rule{Column: "Order Total", Operator: "=", Value: "a=b->c", Style: Style{Faint: true}},
+		},
+		{
+			name:     "escaped quote",
+			rule:     "name = 'o''brien' -> italic underline",
+			expected: Rule{Column: "name", Operator: "=", Value: "o'brien", Style: Style{Italic: true, Underline: true}},
+		},
+		{
+			name:     "is null",
+			rule:     "deleted_at IS NULL -> gray",
+			expected: Rule{Column: "deleted_at", Operator: "is null", Style: Style{Foreground: "8"}},
+		},
+		{
+			name:     "is not null",
+			rule:     "deleted_at is not null -> red",
+			expected: Rule{Column: "deleted_at", Operator: "is not null", Style: Style{Foreground: "1"}},
+		},
+		{name: "missing arrow", rule: "status = 'failed'", expectError: true},
+		{name: "missing operator", rule: "status -> red", expectError: true},
+		{name: "missing value", rule: "status = -> red", expectError: true},
+		{name: "missing style", rule: "status = 'x' ->", expectError: true},
+		{name: "unknown colour", rule: "status = 'x' -> purple", expectError: true},
+		{name: "missing background", rule: "status = 'x' -> red on", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := ParseRule(tt.rule)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule)
+		})
+	}
+}
+
+func TestParseRulesSkipsInvalid(t *testing.T) {
+	t.Parallel()
+
+	rules, err := ParseRules([]string{"status = 'failed' -> red", "oops", "amount > 1 -> bold"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"oops"`)
+	assert.Len(t, rules, 2)
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	row := map[string]string{"status": "failed", "amount": "1200.50", "note": "NULL", "code": "B"}
+
+	tests := []struct {
+		rule     string
+		expected bool
+	}{
+		{rule: "status = 'failed' -> red", expected: true},
+		{rule: "status != 'failed' -> red", expected: false},
+		{rule: "amount > 1000 -> red", expected: true},
+		{rule: "amount > 9 -> red", expected: true},
+		{rule: "amount <= 1200.5 -> red", expected: true},
+		{rule: "amount < 1000 -> red", expected: false},
+		{rule: "code > 'A' -> red", expected: true},
+		{rule: "note is null -> red", expected: true},
+		{rule: "note is not null -> red", expected: false},
+		{rule: "note = 'x' -> red", expected: false},
+		{rule: "missing = 'x' -> red", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := ParseRule(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule.Matches(row))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	rules, err := ParseRules([]string{
+		"status = 'failed' -> red",
+		"amount > 1000 -> bold",
+		"amount > 5000 -> yellow",
+	})
+	require.NoError(t, err)
+
+	style, ok := Match(rules, map[string]string{"status": "failed", "amount": "2000"})
+	assert.True(t, ok)
+	assert.Equal(t, Style{Foreground: "1", Bold: true}, style)
+
+	style, ok = Match(rules, map[string]string{"status": "failed", "amount": "9000"})
+	assert.True(t, ok)
+	assert.Equal(t, Style{Foreground: "3", Bold: true}, style)
+
+	_, ok = Match(rules, map[string]string{"status": "done", "amount": "5"})
+	assert.False(t, ok)
+}
@@ -16,8 +16,9 @@ const (
 )
 
 type Entry struct {
-	Query string
-	Time  time.Time
+	Query    string
+	Time     time.Time
+	Duration time.Duration // execution time, zero when the query was not timed
 }
 
 // Thread-safe history manager
@@ -103,6 +104,87 @@ func Get(storage string) ([]Entry, error) {
 	return getUniqueSortedHistory(history), nil
 }
 
+// RecordDuration stores the execution time of the latest untimed run of the query.
+func RecordDuration(storage, query string, duration time.Duration) error {
+	query = strings.TrimSpace(query)
+	if query == "" || duration <= 0 {
+		return nil
+	}
+
+	manager := getManager(storage)
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	path := filepath.Join(storage, historyFileName)
+
+	history, err := readHistoryLogs(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	latest := -1
+	for i, log := range history {
+		if log.Query != query || log.Duration > 0 {
+			continue
+		}
+		if latest == -1 || log.Time.After(history[latest].Time) {
+			latest = i
+		}
+	}
+
+	if latest == -1 {
+		return nil
+	}
+
+	history[latest].Duration = duration
+
+	return writeHistoryLogs(path, history)
+}
+
+// AverageDuration returns the mean execution time of the last timed runs of
+// the query, up to window of them, and how many runs it is based on.
+func AverageDuration(storage, query string, window int) (time.Duration, int, error) {
+	query = strings.TrimSpace(query)
+
+	manager := getManager(storage)
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	history, err := readHistoryLogs(filepath.Join(storage, historyFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	history = slices.DeleteFunc(history, func(log Entry) bool {
+		return log.Query != query || log.Duration <= 0
+	})
+
+	slices.SortFunc(history, func(a, b Entry) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	if window > 0 && len(history) > window {
+		history = history[:window]
+	}
+
+	if len(history) == 0 {
+		return 0, 0, nil
+	}
+
+	var total time.Duration
+	for _, log := range history {
+		total += log.Duration
+	}
+
+	return total / time.Duration(len(history)), len(history), nil
+}
+
 // writeHistoryLogs performs atomic writes to prevent corruption during concurrent access.
 func writeHistoryLogs(path string, history []Entry) error {
 	dir := filepath.Dir(path)
@@ -121,6 +203,10 @@ func writeHistoryLogs(path string, history []Entry) error {
 
 		buf.WriteString("---\n")
 		buf.WriteString(log.Time.Format(time.RFC3339))
+		if log.Duration > 0 {
+			buf.WriteString(" ")
+			buf.WriteString(log.Duration.String())
+		}
 		buf.WriteString("\n")
 		buf.WriteString(log.Query)
 		buf.WriteString("\n---")
@@ -166,21 +252,24 @@ func readHistoryLogs(path string) ([]Entry, error) {
 			continue
 		}
 
-		// Parse timestamp
-		timeStr := string(bytes.TrimSpace(lines[0]))
+		// Parse timestamp, followed by the execution time for timed queries
+		timeStr, durationStr, _ := strings.Cut(string(bytes.TrimSpace(lines[0])), " ")
 		parsedTime, err := time.Parse(time.RFC3339, timeStr)
 		if err != nil {
 			continue
 		}
 
+		duration, _ := time.ParseDuration(durationStr)
+
 		// Extract query content
 		queryContent := bytes.TrimSpace(lines[1])
 
 		query := string(queryContent)
 		if query != "" {
 			history = append(history, Entry{
-				Query: query,
-				Time:  parsedTime,
+				Query:    query,
+				Time:     parsedTime,
+				Duration: duration,
 			})
 		}
 	}
@@ -307,6 +307,59 @@ func TestWriteHistoryLogs(t *testing.T) {
 	}
 }
 
+func TestDurations(t *testing.T) {
+	tempDir := setupTempDir(t)
+	defer removeTempDir(t, tempDir)
+
+	now := time.Now().Truncate(time.Second)
+	logs := []Entry{
+		{Query: "SELECT * FROM orders", Time: now.Add(-3 * time.Hour), Duration: 2 * time.Second},
+		{Query: "SELECT * FROM orders", Time: now.Add(-2 * time.Hour), Duration: 3 * time.Second},
+		{Query: "SELECT * FROM orders", Time: now.Add(-time.Hour)},
+		{Query: "SELECT 1", Time: now, Duration: time.Millisecond},
+	}
+
+	if err := writeHistoryLogs(filepath.Join(tempDir, historyFileName), logs); err != nil {
+		t.Fatalf("Failed to setup existing history: %v", err)
+	}
+
+	average, runs, err := AverageDuration(tempDir, "SELECT * FROM orders", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if average != 2500*time.Millisecond || runs != 2 {
+		t.Errorf("Expected an average of 2.5s over 2 runs, got %s over %d", average, runs)
+	}
+
+	if err := RecordDuration(tempDir, "  SELECT * FROM orders  ", 4*time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	average, runs, err = AverageDuration(tempDir, "SELECT * FROM orders", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if average != 3500*time.Millisecond || runs != 2 {
+		t.Errorf("Expected an average of the last 2 runs of 3.5s, got %s over %d", average, runs)
+	}
+
+	// the duration is kept when the file is read back
+	readLogs, err := readHistoryLogs(filepath.Join(tempDir, historyFileName))
+	if err != nil {
+		t.Fatalf("Failed to read back history logs: %v", err)
+	}
+	for _, log := range readLogs {
+		if log.Query == "SELECT 1" && log.Duration != time.Millisecond {
+			t.Errorf("Expected a duration of 1ms, got %s", log.Duration)
+		}
+	}
+
+	_, runs, err = AverageDuration(tempDir, "SELECT 2", 10)
+	if err != nil || runs != 0 {
+		t.Errorf("Expected no runs for an unknown query, got %d (%v)", runs, err)
+	}
+}
+
 func TestGetUniqueSortedHistory(t *testing.T) {
 	now := time.Now()
 	logs := []Entry{
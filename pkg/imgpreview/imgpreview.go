@@ -0,0 +1,206 @@
+package imgpreview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol
+type Protocol int
+
+const (
+	None Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+// ProtocolEnv overrides the detected protocol: kitty, iterm2, sixel or none
+const ProtocolEnv = "PERP_IMAGE_PROTOCOL"
+
+const (
+	// approximate size of a terminal cell in pixels, used to size sixel images
+	cellWidth  = 10
+	cellHeight = 20
+
+	kittyChunkSize = 4096
+	maxPixels      = 1024
+)
+
+// ClearKitty deletes all images placed with the kitty graphics protocol
+const ClearKitty = "\x1b_Ga=d,q=2\x1b\\"
+
+// DetectProtocol returns the graphics protocol supported by the terminal
+func DetectProtocol(getenv func(string) string) Protocol {
+	switch strings.ToLower(getenv(ProtocolEnv)) {
+	case "kitty":
+		return Kitty
+	case "iterm2":
+		return ITerm2
+	case "sixel":
+		return Sixel
+	case "none":
+		return None
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty") || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm":
+		return ITerm2
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm"):
+		return Sixel
+	default:
+		return None
+	}
+}
+
+// Info describes an image stored in a bytea value
+type Info struct {
+	Format        string
+	Width, Height int
+	Size          int
+}
+
+// Inspect reports whether data is a PNG, JPEG or GIF image and describes it
+func Inspect(data []byte) (Info, bool) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Info{}, false
+	}
+
+	return Info{
+		Format: strings.ToUpper(format),
+		Width:  config.Width,
+		Height: config.Height,
+		Size:   len(data),
+	}, true
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("%s image, %d×%d px, %s", i.Format, i.Width, i.Height, FormatSize(i.Size))
+}
+
+// FormatSize formats a byte count for display
+func FormatSize(size int) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// Render returns the escape sequence drawing data as a thumbnail that fits in
+// cols×rows terminal cells at the cursor position.
+func Render(protocol Protocol, data []byte, cols, rows int) (string, error) {
+	if cols <= 0 || rows <= 0 {
+		return "", errors.New("no room to render the image")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	switch protocol {
+	case Kitty:
+		encoded, err := encodePNG(thumbnail(img, maxPixels, maxPixels))
+		if err != nil {
+			return "", err
+		}
+		return kitty(encoded, cols, rows), nil
+
+	case ITerm2:
+		encoded, err := encodePNG(thumbnail(img, maxPixels, maxPixels))
+		if err != nil {
+			return "", err
+		}
+		return iterm2(encoded, cols, rows), nil
+
+	case Sixel:
+		return sixel(thumbnail(img, cols*cellWidth, rows*cellHeight)), nil
+
+	default:
+		return "", errors.New("the terminal does not support inline images")
+	}
+}
+
+// Place wraps seq so it is drawn at the zero-based row and column of the
+// screen and the cursor is restored afterwards.
+func Place(seq string, row, col int) string {
+	return fmt.Sprintf("\x1b7\x1b[%d;%dH%s\x1b8", row+1, col+1, seq)
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// kitty transmits and places a PNG in chunks, scaled to cols×rows cells
+func kitty(data []byte, cols, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var sb strings.Builder
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(kittyChunkSize, len(payload))]
+		payload = payload[len(chunk):]
+
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	return sb.String()
+}
+
+// iterm2 draws an inline image with the iTerm2 protocol, also supported by WezTerm
+func iterm2(data []byte, cols, rows int) string {
+	return fmt.Sprintf(
+		"\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data),
+	)
+}
+
+// thumbnail scales img down with nearest-neighbour sampling to fit maxW×maxH
+func thumbnail(img image.Image, maxW, maxH int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxW && h <= maxH {
+		return img
+	}
+
+	scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	tw, th := max(1, int(float64(w)*scale)), max(1, int(float64(h)*scale))
+
+	out := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := range th {
+		for x := range tw {
+			out.Set(x, y, img.At(bounds.Min.X+x*w/tw, bounds.Min.Y+y*h/th))
+		}
+	}
+
+	return out
+}
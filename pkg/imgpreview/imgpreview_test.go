@@ -0,0 +1,157 @@
+package imgpreview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDetectProtocol(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Protocol
+	}{
+		{name: "kitty window", env: map[string]string{"KITTY_WINDOW_ID": "1"}, expected: Kitty},
+		{name: "kitty term", env: map[string]string{"TERM": "xterm-kitty"}, expected: Kitty},
+		{name: "ghostty", env: map[string]string{"TERM_PROGRAM": "ghostty"}, expected: Kitty},
+		{name: "iterm2", env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, expected: ITerm2},
+		{name: "wezterm", env: map[string]string{"TERM_PROGRAM": "WezTerm"}, expected: ITerm2},
+		{name: "foot", env: map[string]string{"TERM": "foot"}, expected: Sixel},
+		{name: "override", env: map[string]string{"TERM_PROGRAM": "iTerm.app", ProtocolEnv: "sixel"}, expected: Sixel},
+		{name: "disabled", env: map[string]string{"KITTY_WINDOW_ID": "1", ProtocolEnv: "none"}, expected: None},
+		{name: "unsupported", env: map[string]string{"TERM": "xterm-256color"}, expected: None},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.expected, DetectProtocol(getenv))
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	data := testPNG(t, 40, 20)
+
+	info, ok := Inspect(data)
+	require.True(t, ok)
+	assert.Equal(t, Info{Format: "PNG", Width: 40, Height: 20, Size: len(data)}, info)
+	assert.Contains(t, info.String(), "PNG image, 40×20 px")
+
+	_, ok = Inspect([]byte("not an image"))
+	assert.False(t, ok)
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "2.0 MB", FormatSize(2<<20))
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	data := testPNG(t, 40, 20)
+
+	tests := []struct {
+		name     string
+		protocol Protocol
+		prefix   string
+		suffix   string
+	}{
+		{name: "kitty", protocol: Kitty, prefix: "\x1b_Ga=T,f=100,q=2,C=1,c=10,r=5,m=0;", suffix: "\x1b\\"},
+		{name: "iterm2", protocol: ITerm2, prefix: "\x1b]1337;File=inline=1;", suffix: "\a"},
+		{name: "sixel", protocol: Sixel, prefix: "\x1bPq\"1;1;40;20", suffix: "-\x1b\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			seq, err := Render(tt.protocol, data, 10, 5)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(seq, tt.prefix), seq[:min(len(seq), 60)])
+			assert.True(t, strings.HasSuffix(seq, tt.suffix))
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Render(None, testPNG(t, 4, 4), 10, 5)
+	assert.ErrorContains(t, err, "does not support")
+
+	_, err = Render(Kitty, []byte("nope"), 10, 5)
+	assert.ErrorContains(t, err, "failed to decode")
+
+	_, err = Render(Kitty, testPNG(t, 4, 4), 0, 5)
+	assert.Error(t, err)
+}
+
+func TestKittyChunks(t *testing.T) {
+	t.Parallel()
+
+	seq := kitty(bytes.Repeat([]byte{1}, kittyChunkSize), 4, 2)
+	assert.Equal(t, 2, strings.Count(seq, "\x1b_G"))
+	assert.Contains(t, seq, ",m=1;")
+	assert.Contains(t, seq, "\x1b_Gm=0;")
+}
+
+func TestSixelRuns(t *testing.T) {
+	t.Parallel()
+
+	// a 10×6 solid red image is one band with a single repeated sixel
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 6))
+	for y := range 6 {
+		for x := range 10 {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	assert.Contains(t, sixel(img), "#180!10~-")
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumbnail(img, 100, 100).Bounds())
+	assert.Equal(t, img, thumbnail(img, 500, 500))
+}
+
+func TestPlace(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "\x1b7\x1b[2;3Hx\x1b8", Place("x", 1, 2))
+}
@@ -0,0 +1,95 @@
+package imgpreview
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixel encodes img with a fixed 6×6×6 colour cube palette
+func sixel(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// palette index per pixel, -1 for transparent pixels
+	pixels := make([]int, w*h)
+	for y := range h {
+		for x := range w {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if a < 0x8000 {
+				pixels[y*w+x] = -1
+				continue
+			}
+			pixels[y*w+x] = int(r>>8*6/256)*36 + int(g>>8*6/256)*6 + int(b>>8*6/256)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\x1bPq\"1;1;%d;%d", w, h)
+
+	for i := range 216 {
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	for top := 0; top < h; top += 6 {
+		colours := make(map[int]bool)
+		for y := top; y < min(top+6, h); y++ {
+			for x := range w {
+				if c := pixels[y*w+x]; c >= 0 {
+					colours[c] = true
+				}
+			}
+		}
+
+		first := true
+		for c := range 216 {
+			if !colours[c] {
+				continue
+			}
+
+			if !first {
+				sb.WriteByte('$')
+			}
+			first = false
+
+			fmt.Fprintf(&sb, "#%d", c)
+
+			row := make([]byte, w)
+			for x := range w {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < h; dy++ {
+					if pixels[(top+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+
+			writeRuns(&sb, row)
+		}
+
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString("\x1b\\")
+
+	return sb.String()
+}
+
+// writeRuns writes sixel characters using the !<count><char> repeat introducer
+func writeRuns(sb *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+
+		if count := j - i; count > 3 {
+			fmt.Fprintf(sb, "!%d%c", count, row[i])
+		} else {
+			sb.WriteString(strings.Repeat(string(row[i]), count))
+		}
+
+		i = j
+	}
+}
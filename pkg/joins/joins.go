@@ -0,0 +1,167 @@
+// Package joins writes the JOIN clause adding a table to a query from the
+// foreign keys between that table and the ones the query already references.
+package joins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/columnpicker"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// Join is a foreign key between the joined table and a table of the query
+type Join struct {
+	Constraint string
+	Table      string             // the joined table
+	To         columnpicker.Table // the table of the query it is joined to
+	Columns    []string           // the columns of the joined table, quoted
+	ToColumns  []string           // the matching columns of To, quoted
+}
+
+// Clause returns the JOIN ... ON clause
+func (j Join) Clause() string {
+	to := j.To.Alias
+	if to == "" {
+		to = quoteTable(j.To.Name)
+	}
+
+	conditions := make([]string, len(j.Columns))
+	for i, column := range j.Columns {
+		conditions[i] = fmt.Sprintf("%s.%s = %s.%s", j.Table, column, to, j.ToColumns[i])
+	}
+
+	return fmt.Sprintf("JOIN %s ON %s", j.Table, strings.Join(conditions, " AND "))
+}
+
+// foreignKeysQuery finds the foreign keys in either direction between $1 and
+// the tables in $2, the closest to the end of the query first
+const foreignKeysQuery = `
+	SELECT
+		con.conname,
+		r.position::int,
+		con.conrelid = pg_catalog.to_regclass($1),
+		ARRAY(
+			SELECT pg_catalog.quote_ident(a.attname)
+			FROM pg_catalog.unnest(con.conkey) WITH ORDINALITY AS k(attnum, position)
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			ORDER BY k.position
+		),
+		ARRAY(
+			SELECT pg_catalog.quote_ident(a.attname)
+			FROM pg_catalog.unnest(con.confkey) WITH ORDINALITY AS k(attnum, position)
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+			ORDER BY k.position
+		)
+	FROM pg_catalog.unnest($2::text[]) WITH ORDINALITY AS r(name, position)
+	JOIN pg_catalog.pg_constraint con ON con.contype = 'f' AND (
+		(con.conrelid = pg_catalog.to_regclass($1) AND con.confrelid = pg_catalog.to_regclass(r.name))
+		OR (con.confrelid = pg_catalog.to_regclass($1) AND con.conrelid = pg_catalog.to_regclass(r.name)))
+	ORDER BY r.position DESC, con.conname`
+
+// Find returns the foreign keys between table and the tables of the query,
+// the ones of the tables referenced last first
+func Find(ctx context.Context, database db.Database, table string, tables []columnpicker.Table) ([]Join, error) {
+	table, err := psql.SanitiseIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("the query has no table to join %s to", table)
+	}
+
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		if strings.EqualFold(strings.TrimPrefix(table, "public."), t.Name) {
+			return nil, fmt.Errorf("%s is already in the query", table)
+		}
+		names[i] = quoteTable(t.Name)
+	}
+
+	result, err := database.Query(ctx, foreignKeysQuery, table, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the foreign keys of %s: %w", table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var joins []Join
+	for rows.Next() {
+		var (
+			position    int
+			referencing bool
+			columns     []string
+			refColumns  []string
+		)
+
+		j := Join{Table: table}
+		if err := rows.Scan(&j.Constraint, &position, &referencing, &columns, &refColumns); err != nil {
+			return nil, fmt.Errorf("failed to read the foreign keys of %s: %w", table, err)
+		}
+
+		j.To = tables[position-1]
+		if referencing {
+			j.Columns, j.ToColumns = columns, refColumns
+		} else {
+			j.Columns, j.ToColumns = refColumns, columns
+		}
+
+		joins = append(joins, j)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to look up the foreign keys of %s: %w", table, err)
+	}
+
+	if len(joins) == 0 {
+		return nil, fmt.Errorf("no foreign key between %s and the tables of the query", table)
+	}
+
+	return joins, nil
+}
+
+// quoteTable quotes the parts of a table name as read from a query
+func quoteTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = columnpicker.QuoteName(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// LastStatement returns the last statement of content, which the join is
+// added to
+func LastStatement(content string) string {
+	start, end := lastStatement(content)
+	return content[start:end]
+}
+
+func lastStatement(content string) (int, int) {
+	trimmed := strings.TrimRight(content, " \t\n;")
+	return columnpicker.StatementBounds(content, len(trimmed))
+}
+
+// Insert adds clause to the last statement of content, after its FROM
+// clause and joins, on a line of its own when the statement spans lines
+func Insert(content, clause string) (string, error) {
+	start, end := lastStatement(content)
+	statement := content[start:end]
+
+	offset, ok := columnpicker.FromEnd(statement)
+	if !ok {
+		return content, fmt.Errorf("the query has no FROM clause to add the join to")
+	}
+
+	separator := " "
+	if strings.Contains(strings.TrimSpace(statement), "\n") {
+		separator = "\n"
+	}
+
+	offset += start
+
+	return content[:offset] + separator + clause + content[offset:], nil
+}
@@ -0,0 +1,95 @@
+package joins
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/columnpicker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		join     Join
+		expected string
+	}{
+		{
+			name: "alias",
+			join: Join{
+				Table:     "orders",
+				To:        columnpicker.Table{Name: "users", Alias: "u"},
+				Columns:   []string{"user_id"},
+				ToColumns: []string{"id"},
+			},
+			expected: "JOIN orders ON orders.user_id = u.id",
+		},
+		{
+			name: "composite key",
+			join: Join{
+				Table:     "order_items",
+				To:        columnpicker.Table{Name: "sales.Orders"},
+				Columns:   []string{"order_id", `"Line"`},
+				ToColumns: []string{"id", `"Line"`},
+			},
+			expected: `JOIN order_items ON order_items.order_id = sales."Orders".id AND order_items."Line" = sales."Orders"."Line"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.join.Clause())
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	const clause = "JOIN orders ON orders.user_id = u.id"
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "single line",
+			content:  "SELECT * FROM users u WHERE u.id = 1;",
+			expected: "SELECT * FROM users u JOIN orders ON orders.user_id = u.id WHERE u.id = 1;",
+		},
+		{
+			name:     "multiple lines",
+			content:  "SELECT *\nFROM users u\nORDER BY u.id",
+			expected: "SELECT *\nFROM users u\nJOIN orders ON orders.user_id = u.id\nORDER BY u.id",
+		},
+		{
+			name:     "last statement",
+			content:  "SELECT 1;\nSELECT * FROM users u;\n",
+			expected: "SELECT 1;\nSELECT * FROM users u JOIN orders ON orders.user_id = u.id;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := Insert(tt.content, clause)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, content)
+		})
+	}
+
+	_, err := Insert("SELECT 1", clause)
+	require.Error(t, err)
+}
+
+func TestLastStatement(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "\nSELECT * FROM users", LastStatement("SELECT 1;\nSELECT * FROM users;\n"))
+}
@@ -0,0 +1,257 @@
+// Package keywordcase changes the case of the SQL keywords of a query, leaving
+// strings, quoted identifiers and comments as written.
+package keywordcase
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+var keywords = toSet(
+	"add", "all", "alter", "analyze", "and", "any", "array", "as", "asc",
+	"begin", "between", "by", "cascade", "case", "cast", "check", "collate",
+	"column", "commit", "concurrently", "conflict", "constraint", "create",
+	"cross", "current_date", "current_timestamp", "current_user", "database",
+	"default", "delete", "desc", "distinct", "do", "drop", "else", "end",
+	"except", "exists", "explain", "false", "fetch", "filter", "first", "for",
+	"foreign", "from", "full", "function", "grant", "group", "having", "if",
+	"ilike", "in", "index", "inner", "insert", "intersect", "interval", "into",
+	"is", "join", "key", "language", "last", "lateral", "left", "like", "limit",
+	"materialized", "natural", "next", "not", "nothing", "null", "nulls",
+	"offset", "on", "only", "or", "order", "outer", "over", "partition",
+	"primary", "recursive", "references", "refresh", "rename", "replace",
+	"restrict", "returning", "returns", "revoke", "right", "rollback", "rows",
+	"schema", "select", "sequence", "set", "similar", "some", "table", "temp",
+	"temporary", "then", "to", "transaction", "trigger", "true", "truncate",
+	"union", "unique", "update", "using", "vacuum", "values", "view", "when",
+	"where", "window", "with",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// word is the position of a keyword outside strings, quoted identifiers and
+// comments
+type word struct {
+	start, end int
+}
+
+// Apply returns sql with its keywords in upper case, or in lower case when
+// upper is false
+func Apply(sql string, upper bool) string {
+	words := keywordsOf(sql)
+	if len(words) == 0 {
+		return sql
+	}
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	last := 0
+	for _, w := range words {
+		b.WriteString(sql[last:w.start])
+		b.WriteString(changeCase(sql[w.start:w.end], upper))
+		last = w.end
+	}
+	b.WriteString(sql[last:])
+
+	return b.String()
+}
+
+// AfterInsert upper-cases the keyword just typed, for editing as you type.
+// When after is before with a space, newline or punctuation typed right
+// after a keyword, it returns after with that keyword in upper case and the
+// offset, in bytes, following the typed character.
+func AfterInsert(before, after string) (string, int, bool) {
+	if len(after) != len(before)+1 {
+		return after, 0, false
+	}
+
+	i := 0
+	for i < len(before) && before[i] == after[i] {
+		i++
+	}
+
+	if after[i+1:] != before[i:] || !isBoundary(after[i]) {
+		return after, 0, false
+	}
+
+	for _, w := range keywordsOf(after[:i]) {
+		if w.end != i {
+			continue
+		}
+
+		if typed := after[w.start:w.end]; typed != strings.ToUpper(typed) {
+			return after[:w.start] + strings.ToUpper(typed) + after[w.end:], i + 1, true
+		}
+	}
+
+	return after, 0, false
+}
+
+func isBoundary(c byte) bool {
+	return strings.IndexByte(" \t\n;,()", c) >= 0
+}
+
+func changeCase(s string, upper bool) string {
+	if upper {
+		return strings.ToUpper(s)
+	}
+	return strings.ToLower(s)
+}
+
+// keywordsOf returns the keywords of sql in order. Words in strings, quoted
+// identifiers, comments, psql meta-commands and variables, and words
+// qualified with a dot, e.g. the column of t.order, are skipped.
+func keywordsOf(sql string) []word {
+	var words []word
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+
+		switch {
+		case c == '\'':
+			i = skipString(sql, i+1, escapeString(sql, i))
+
+		case c == '"':
+			i = skipPast(sql, i+1, `"`)
+
+		case strings.HasPrefix(sql[i:], "--"):
+			i = skipPast(sql, i+2, "\n")
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipComment(sql, i+2)
+
+		case c == '$':
+			i = skipDollarQuoted(sql, i)
+
+		case c == '\\' && (i == 0 || sql[i-1] == '\n'):
+			i = skipPast(sql, i+1, "\n")
+
+		case isWordStart(c):
+			start := i
+			for i < len(sql) && isWordPart(sql[i]) {
+				i++
+			}
+
+			if !qualified(sql, start) && keywords[strings.ToLower(sql[start:i])] {
+				words = append(words, word{start: start, end: i})
+			}
+
+		case c >= utf8.RuneSelf:
+			// identifiers can hold other letters, skip them whole
+			for i < len(sql) && (sql[i] >= utf8.RuneSelf || isWordPart(sql[i])) {
+				i++
+			}
+
+		default:
+			i++
+		}
+	}
+
+	return words
+}
+
+// escapeString reports whether the string at i is an escape string, E'...'
+func escapeString(sql string, i int) bool {
+	if i == 0 || (sql[i-1] != 'e' && sql[i-1] != 'E') {
+		return false
+	}
+	return i < 2 || !isWordPart(sql[i-2])
+}
+
+// qualified reports whether the word at start follows a dot or is a psql
+// variable, e.g. :name but not the cast ::name
+func qualified(sql string, start int) bool {
+	if start == 0 {
+		return false
+	}
+
+	switch sql[start-1] {
+	case '.':
+		return true
+	case ':':
+		return start < 2 || sql[start-2] != ':'
+	}
+
+	return false
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9') || c == '$'
+}
+
+// skipPast returns the offset following the first end at or after i, or the
+// end of sql
+func skipPast(sql string, i int, end string) int {
+	if n := strings.Index(sql[i:], end); n >= 0 {
+		return i + n + len(end)
+	}
+	return len(sql)
+}
+
+// skipString returns the offset following the string starting at i. Quotes
+// are escaped by doubling them, and with a backslash in escape strings.
+func skipString(sql string, i int, escapes bool) int {
+	for i < len(sql) {
+		switch {
+		case escapes && sql[i] == '\\':
+			i += 2
+		case sql[i] == '\'' && i+1 < len(sql) && sql[i+1] == '\'':
+			i += 2
+		case sql[i] == '\'':
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(sql)
+}
+
+// skipComment returns the offset following the block comment starting at i,
+// which can be nested
+func skipComment(sql string, i int) int {
+	depth := 1
+	for i < len(sql) {
+		switch {
+		case strings.HasPrefix(sql[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(sql[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(sql)
+}
+
+// skipDollarQuoted returns the offset following the $tag$ quoted string at
+// i, or the one following the $ when it does not start one, e.g. in $1
+func skipDollarQuoted(sql string, i int) int {
+	end := i + 1
+	for end < len(sql) && isWordPart(sql[end]) && sql[end] != '$' {
+		end++
+	}
+
+	if end >= len(sql) || sql[end] != '$' || (end > i+1 && !isWordStart(sql[i+1])) {
+		return i + 1
+	}
+
+	tag := sql[i : end+1]
+
+	return skipPast(sql, end+1, tag)
+}
@@ -0,0 +1,159 @@
+package keywordcase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		sql   string
+		upper bool
+		want  string
+	}{
+		{
+			name:  "keywords",
+			sql:   "select id, name from users where id in (1, 2) order by name desc;",
+			upper: true,
+			want:  "SELECT id, name FROM users WHERE id IN (1, 2) ORDER BY name DESC;",
+		},
+		{
+			name:  "lower case",
+			sql:   "SELECT id FROM users WHERE active IS NOT NULL",
+			upper: false,
+			want:  "select id from users where active is not null",
+		},
+		{
+			name:  "strings",
+			sql:   "select 'select from' as x, E'it\\'s from', 'it''s from' from t",
+			upper: true,
+			want:  "SELECT 'select from' AS x, E'it\\'s from', 'it''s from' FROM t",
+		},
+		{
+			name:  "quoted identifiers",
+			sql:   `select "order", "from" from "table"`,
+			upper: true,
+			want:  `SELECT "order", "from" FROM "table"`,
+		},
+		{
+			name:  "comments",
+			sql:   "-- select from\nselect 1 /* from /* nested */ where */ from t",
+			upper: true,
+			want:  "-- select from\nSELECT 1 /* from /* nested */ where */ FROM t",
+		},
+		{
+			name:  "dollar quoted",
+			sql:   "create function f() returns int as $body$ select 1 $body$ language sql; select $1, $$ from $$",
+			upper: true,
+			want:  "CREATE FUNCTION f() RETURNS int AS $body$ select 1 $body$ LANGUAGE sql; SELECT $1, $$ from $$",
+		},
+		{
+			name:  "qualified names and variables",
+			sql:   "select t.order, :limit, x::text from t",
+			upper: true,
+			want:  "SELECT t.order, :limit, x::text FROM t",
+		},
+		{
+			name:  "meta-commands",
+			sql:   "\\d select\nselect 1",
+			upper: true,
+			want:  "\\d select\nSELECT 1",
+		},
+		{
+			name:  "identifiers containing keywords",
+			sql:   "select selected, from_date, café_in from t",
+			upper: true,
+			want:  "SELECT selected, from_date, café_in FROM t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Apply(tt.sql, tt.upper))
+		})
+	}
+}
+
+func TestAfterInsert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+		offset int
+		ok     bool
+	}{
+		{
+			name:   "space after keyword",
+			before: "select",
+			after:  "select ",
+			want:   "SELECT ",
+			offset: 7,
+			ok:     true,
+		},
+		{
+			name:   "typed in the middle",
+			before: "SELECT id from\nWHERE",
+			after:  "SELECT id from \nWHERE",
+			want:   "SELECT id FROM \nWHERE",
+			offset: 15,
+			ok:     true,
+		},
+		{
+			name:   "parenthesis",
+			before: "SELECT 1 WHERE x in",
+			after:  "SELECT 1 WHERE x in(",
+			want:   "SELECT 1 WHERE x IN(",
+			offset: 20,
+			ok:     true,
+		},
+		{
+			name:   "letter",
+			before: "selec",
+			after:  "select",
+			want:   "select",
+		},
+		{
+			name:   "not a keyword",
+			before: "SELECT id",
+			after:  "SELECT id ",
+			want:   "SELECT id ",
+		},
+		{
+			name:   "in a string",
+			before: "SELECT 'from",
+			after:  "SELECT 'from ",
+			want:   "SELECT 'from ",
+		},
+		{
+			name:   "already upper case",
+			before: "SELECT",
+			after:  "SELECT ",
+			want:   "SELECT ",
+		},
+		{
+			name:   "deleted",
+			before: "select ",
+			after:  "select",
+			want:   "select",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, offset, ok := AfterInsert(tt.before, tt.after)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.offset, offset)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
@@ -17,10 +17,11 @@ type GenAI struct {
 	DBSchemaInstructions string
 	Client               *genai.Client
 	Ctx                  context.Context
+	GenerationSettings   llm.Settings
 }
 
 func (g *GenAI) Ask(prompt string, cmd llm.Command, providerName string) (*llm.Response, error) {
-	timeout := 30 * time.Second
+	timeout := g.GenerationSettings.RequestTimeout()
 
 	if g.Model == "" {
 		return nil, fmt.Errorf("no %s model specified", providerName)
@@ -35,7 +36,7 @@ func (g *GenAI) Ask(prompt string, cmd llm.Command, providerName string) (*llm.R
 		ctx,
 		g.Model,
 		genai.Text(instructions),
-		nil,
+		g.generateContentConfig(),
 	)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -66,6 +67,27 @@ func (g *GenAI) Ask(prompt string, cmd llm.Command, providerName string) (*llm.R
 	}, nil
 }
 
+func (g *GenAI) Settings() llm.Settings {
+	return g.GenerationSettings
+}
+
+func (g *GenAI) SetSettings(settings llm.Settings) {
+	g.GenerationSettings = settings
+}
+
+// generateContentConfig returns the generation config, or nil to use the provider defaults
+func (g *GenAI) generateContentConfig() *genai.GenerateContentConfig {
+	s := g.GenerationSettings
+	if s.Temperature == nil && s.MaxTokens == 0 {
+		return nil
+	}
+
+	return &genai.GenerateContentConfig{
+		Temperature:     s.Temperature,
+		MaxOutputTokens: s.MaxTokens,
+	}
+}
+
 func (g *GenAI) AppendInstructions(instructions string) {
 	g.DBSchemaInstructions = instructions
 }
@@ -79,7 +101,7 @@ func (g *GenAI) getInstructions() string {
 }
 
 func (g *GenAI) SetModel(model, providerName string) error {
-	timeout := 30 * time.Second
+	timeout := g.GenerationSettings.RequestTimeout()
 
 	if model == "" {
 		return fmt.Errorf("no %s model specified", providerName)
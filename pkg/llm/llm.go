@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -13,12 +14,14 @@ const (
 	Optimise
 	Fix
 	Info
+	Filter
 )
 
 var LLMKeywords = [...]string{
 	"/ask",
 	"/add",
 	"/remove",
+	"/filter",
 }
 
 type Response struct {
@@ -32,6 +35,8 @@ type LLM interface {
 	AppendInstructions(instructions string)
 	ResetInstructions()
 	SetModel(model string) error
+	Settings() Settings
+	SetSettings(settings Settings)
 }
 
 func ExtractQuery(text string) string {
@@ -82,3 +87,104 @@ func IsFixCommand(text string) bool {
 	text = strings.TrimSpace(strings.ToLower(text))
 	return strings.Contains(text, "-- fix") || strings.Contains(text, "--fix")
 }
+
+func IsFilterCommand(text string) bool {
+	text = strings.TrimSpace(strings.ToLower(text))
+	return strings.HasPrefix(text, "/filter")
+}
+
+// FilterPrompt builds the prompt asking for a WHERE predicate that narrows the
+// results of query according to the natural-language description.
+func FilterPrompt(query string, columns []string, description string) string {
+	return fmt.Sprintf(
+		"/filter %s\n\nQuery:\n```sql\n%s\n```\n\nResult columns: %s",
+		strings.TrimSpace(description),
+		strings.TrimSpace(query),
+		strings.Join(columns, ", "),
+	)
+}
+
+// ExtractPredicate returns the WHERE predicate from a /filter response,
+// without the WHERE keyword or a trailing semicolon.
+func ExtractPredicate(text string) string {
+	predicate := strings.TrimSpace(ExtractQuery(text))
+
+	if len(predicate) >= len("where ") && strings.EqualFold(predicate[:len("where ")], "where ") {
+		predicate = predicate[len("where "):]
+	}
+
+	return strings.TrimSpace(strings.TrimRight(predicate, "; \t\n"))
+}
+
+// ApplyFilter wraps query in a subquery restricted by predicate, so the filter
+// only needs to reference the result columns.
+func ApplyFilter(query, predicate string) string {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	return fmt.Sprintf("SELECT *\nFROM (\n%s\n) AS filtered\nWHERE %s;", query, predicate)
+}
+
+// commandComments are the comment markers that turn a query into an LLM command
+var commandComments = [...]string{
+	"-- explain", "--explain",
+	"-- optimise", "--optimise",
+	"-- optimize", "--optimize",
+	"-- fix", "--fix",
+}
+
+// StripCommandComments removes the LLM command comments (e.g. "-- fix") from
+// the given text, leaving the query they were attached to. Markers are matched
+// anywhere in a line, the same way the Is*Command detectors find them.
+func StripCommandComments(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		idx := indexCommandComment(line)
+		if idx == -1 {
+			kept = append(kept, line)
+			continue
+		}
+
+		// The marker starts a comment, so the rest of the line goes with it
+		if stripped := strings.TrimRight(line[:idx], " \t"); strings.TrimSpace(stripped) != "" {
+			kept = append(kept, stripped)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// CommandCommentWords returns the words of the LLM command comments of text
+// as written, in order, e.g. "fix" for "-- fix" and "--optimise" for
+// "--optimise", for highlighting them
+func CommandCommentWords(text string) []string {
+	var words []string
+
+	for _, line := range strings.Split(text, "\n") {
+		for i := 0; i < len(line); i++ {
+			for _, comment := range commandComments {
+				if len(line)-i >= len(comment) && strings.EqualFold(line[i:i+len(comment)], comment) {
+					fields := strings.Fields(line[i : i+len(comment)])
+					words = append(words, fields[len(fields)-1])
+					i += len(comment) - 1
+					break
+				}
+			}
+		}
+	}
+
+	return words
+}
+
+// indexCommandComment returns the index of the first LLM command comment in
+// line, or -1 if there is none.
+func indexCommandComment(line string) int {
+	for i := range len(line) {
+		for _, comment := range commandComments {
+			if len(line)-i >= len(comment) && strings.EqualFold(line[i:i+len(comment)], comment) {
+				return i
+			}
+		}
+	}
+	return -1
+}
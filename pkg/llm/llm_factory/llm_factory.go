@@ -78,18 +78,40 @@ func (c *providerCredentials) validateProvider(provider string) error {
 	return nil
 }
 
-func New(ctx context.Context, cfg config.Config, instructions string) (llm.LLM, error) {
-	creds := loadCredentials()
-
+// ResolveProvider returns the configured provider, or the one detected from
+// the available credentials when none is configured.
+func ResolveProvider(cfg config.Config) (string, error) {
 	provider, err := cfg.GetLLMProvider()
 	if err != nil || provider == "" {
-		provider, err = creds.detectProvider()
+		provider, err = loadCredentials().detectProvider()
 		if err != nil {
-			return nil, err
+			return "", err
+		}
+	}
+
+	return strings.ToLower(strings.TrimSpace(provider)), nil
+}
+
+// LoadSettings reads the generation settings configured for the provider
+func LoadSettings(cfg config.Config, provider string) (llm.Settings, error) {
+	settings := llm.DefaultSettings()
+
+	for _, name := range llm.SettingNames {
+		if err := settings.Set(name, cfg.GetLLMSetting(provider, name)); err != nil {
+			return settings, fmt.Errorf("%s: %w", provider, err)
 		}
 	}
 
-	provider = strings.ToLower(strings.TrimSpace(provider))
+	return settings, nil
+}
+
+func New(ctx context.Context, cfg config.Config, instructions string) (llm.LLM, error) {
+	creds := loadCredentials()
+
+	provider, err := ResolveProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := creds.validateProvider(provider); err != nil {
 		return nil, err
@@ -100,12 +122,27 @@ func New(ctx context.Context, cfg config.Config, instructions string) (llm.LLM,
 		return nil, err
 	}
 
+	settings, err := LoadSettings(cfg, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var client llm.LLM
+
 	switch provider {
 	case "gemini":
-		return gemini.New(ctx, model, creds.geminiAPIKey, instructions)
+		client, err = gemini.New(ctx, model, creds.geminiAPIKey, instructions)
 	case "vertexai":
-		return vertexai.New(ctx, model, creds.vertexAIProjectID, creds.vertexAILocation, instructions)
+		client, err = vertexai.New(ctx, model, creds.vertexAIProjectID, creds.vertexAILocation, instructions)
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrInvalidProvider, provider)
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetSettings(settings)
+
+	return client, nil
 }
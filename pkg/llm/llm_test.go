@@ -90,3 +90,107 @@ func TestExtractQueryPlainFenceNoDrop(t *testing.T) {
 		assert.Equal(t, q, ExtractQuery(input), "plain fence dropped chars for input %q", input)
 	}
 }
+
+func TestStripCommandComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "fix comment is removed",
+			input:    "-- fix\nSELECT * FROM users",
+			expected: "SELECT * FROM users",
+		},
+		{
+			name:     "optimise comment without space is removed",
+			input:    "SELECT * FROM users\n--optimise",
+			expected: "SELECT * FROM users",
+		},
+		{
+			name:     "comment matching is case insensitive",
+			input:    "-- OPTIMIZE\nSELECT 1",
+			expected: "SELECT 1",
+		},
+		{
+			name:     "other comments are preserved",
+			input:    "-- fetch active users\n-- fix\nSELECT * FROM users",
+			expected: "-- fetch active users\nSELECT * FROM users",
+		},
+		{
+			name:     "text without commands is unchanged",
+			input:    "SELECT 1",
+			expected: "SELECT 1",
+		},
+		{
+			name:     "inline trailing marker is removed",
+			input:    "SELECT * FROM users; -- fix",
+			expected: "SELECT * FROM users;",
+		},
+		{
+			name:     "inline marker on one of several lines",
+			input:    "SELECT *\nFROM users --optimise this\nWHERE id = 1",
+			expected: "SELECT *\nFROM users\nWHERE id = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, StripCommandComments(tt.input))
+		})
+	}
+}
+
+func TestCommandCommentWords(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"fix"}, CommandCommentWords("-- fix\nSELECT 1"))
+	assert.Equal(t, []string{"--OPTIMISE", "explain"}, CommandCommentWords("SELECT 1 --OPTIMISE\n-- explain"))
+	assert.Empty(t, CommandCommentWords("-- fetch the users\nSELECT 1"))
+}
+
+func TestExtractPredicate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "bare predicate", input: "status = 'failed'", expected: "status = 'failed'"},
+		{name: "where keyword", input: "WHERE status = 'failed';", expected: "status = 'failed'"},
+		{name: "lowercase where", input: "where id > 10", expected: "id > 10"},
+		{
+			name:     "fenced",
+			input:    "```sql\nWHERE created_at >= now() - interval '7 days'\n```",
+			expected: "created_at >= now() - interval '7 days'",
+		},
+		{name: "column starting with where", input: "whereabouts IS NULL", expected: "whereabouts IS NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, ExtractPredicate(tt.input))
+		})
+	}
+}
+
+func TestApplyFilter(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"SELECT *\nFROM (\nSELECT * FROM jobs\n) AS filtered\nWHERE status = 'failed';",
+		ApplyFilter("SELECT * FROM jobs;\n", "status = 'failed'"),
+	)
+}
+
+func TestIsFilterCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsFilterCommand(" /FILTER only failed rows"))
+	assert.False(t, IsFilterCommand("SELECT '/filter';"))
+}
@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SettingTemperature = "temperature"
+	SettingMaxTokens   = "max_tokens"
+	SettingTimeout     = "timeout"
+
+	DefaultTimeout = 30 * time.Second
+
+	// defaultValue is displayed for settings left to the provider's default
+	defaultValue = "default"
+)
+
+// SettingNames lists the generation settings that can be configured
+var SettingNames = [...]string{
+	SettingTemperature,
+	SettingMaxTokens,
+	SettingTimeout,
+}
+
+// Settings holds the generation parameters and request timeout sent to the provider.
+// Zero values (nil temperature, zero max tokens) leave the provider defaults in place.
+type Settings struct {
+	Temperature *float32
+	MaxTokens   int32
+	Timeout     time.Duration
+}
+
+// DefaultSettings returns the settings used when nothing is configured
+func DefaultSettings() Settings {
+	return Settings{Timeout: DefaultTimeout}
+}
+
+// Set validates and applies a setting by name. An empty value or "default"
+// resets the setting.
+func (s *Settings) Set(name, value string) error {
+	value = strings.TrimSpace(value)
+	reset := value == "" || strings.EqualFold(value, defaultValue)
+
+	switch name {
+	case SettingTemperature:
+		if reset {
+			s.Temperature = nil
+			return nil
+		}
+
+		temperature, err := strconv.ParseFloat(value, 32)
+		if err != nil || temperature < 0 || temperature > 2 {
+			return fmt.Errorf("invalid temperature %q: expected a number between 0 and 2", value)
+		}
+
+		t := float32(temperature)
+		s.Temperature = &t
+
+	case SettingMaxTokens:
+		if reset {
+			s.MaxTokens = 0
+			return nil
+		}
+
+		maxTokens, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || maxTokens <= 0 {
+			return fmt.Errorf("invalid max tokens %q: expected a positive integer", value)
+		}
+
+		s.MaxTokens = int32(maxTokens)
+
+	case SettingTimeout:
+		if reset {
+			s.Timeout = DefaultTimeout
+			return nil
+		}
+
+		// Plain numbers are treated as seconds
+		if seconds, err := strconv.Atoi(value); err == nil {
+			value = fmt.Sprintf("%ds", seconds)
+		}
+
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q: expected a duration such as 45s or 2m", value)
+		}
+
+		s.Timeout = timeout
+
+	default:
+		return fmt.Errorf("unknown LLM setting %q (supported: %s)", name, strings.Join(SettingNames[:], ", "))
+	}
+
+	return nil
+}
+
+// Get returns the display value of a setting by name
+func (s Settings) Get(name string) string {
+	switch name {
+	case SettingTemperature:
+		if s.Temperature == nil {
+			return defaultValue
+		}
+		return strconv.FormatFloat(float64(*s.Temperature), 'f', -1, 32)
+
+	case SettingMaxTokens:
+		if s.MaxTokens == 0 {
+			return defaultValue
+		}
+		return strconv.Itoa(int(s.MaxTokens))
+
+	case SettingTimeout:
+		return s.RequestTimeout().String()
+	}
+
+	return ""
+}
+
+// RequestTimeout returns the configured timeout, falling back to DefaultTimeout
+func (s Settings) RequestTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return s.Timeout
+}
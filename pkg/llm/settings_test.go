@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		setting     string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{name: "temperature", setting: SettingTemperature, value: "0.2", expected: "0.2"},
+		{name: "temperature reset", setting: SettingTemperature, value: "default", expected: "default"},
+		{name: "temperature out of range", setting: SettingTemperature, value: "3", expectError: true},
+		{name: "temperature not a number", setting: SettingTemperature, value: "hot", expectError: true},
+		{name: "max tokens", setting: SettingMaxTokens, value: "2048", expected: "2048"},
+		{name: "max tokens reset", setting: SettingMaxTokens, value: "", expected: "default"},
+		{name: "max tokens negative", setting: SettingMaxTokens, value: "-1", expectError: true},
+		{name: "timeout duration", setting: SettingTimeout, value: "2m", expected: "2m0s"},
+		{name: "timeout seconds", setting: SettingTimeout, value: "45", expected: "45s"},
+		{name: "timeout reset", setting: SettingTimeout, value: "default", expected: "30s"},
+		{name: "timeout invalid", setting: SettingTimeout, value: "soon", expectError: true},
+		{name: "unknown setting", setting: "top_p", value: "0.9", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := DefaultSettings()
+			err := s.Set(tt.setting, tt.value)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, s.Get(tt.setting))
+		})
+	}
+}
+
+func TestSettingsRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultTimeout, Settings{}.RequestTimeout())
+	assert.Equal(t, time.Minute, Settings{Timeout: time.Minute}.RequestTimeout())
+}
@@ -0,0 +1,103 @@
+package numfmt
+
+import (
+	"strings"
+)
+
+// Separators are the digit group and decimal separators of a locale
+type Separators struct {
+	Group   string
+	Decimal string
+}
+
+var (
+	english = Separators{Group: ",", Decimal: "."}
+	// most of continental Europe and Latin America
+	european = Separators{Group: ".", Decimal: ","}
+	// French, Nordic and Slavic locales group digits with a space. A plain
+	// space is used rather than a no-break space because the table measures
+	// cells in bytes.
+	spaced = Separators{Group: " ", Decimal: ","}
+	swiss  = Separators{Group: "'", Decimal: "."}
+)
+
+var languages = map[string]Separators{
+	"de": european, "es": european, "it": european, "nl": european, "pt": european,
+	"da": european, "id": european, "tr": european, "el": european, "ro": european,
+	"fr": spaced, "ru": spaced, "pl": spaced, "cs": spaced, "sk": spaced,
+	"sv": spaced, "fi": spaced, "nb": spaced, "no": spaced, "uk": spaced, "hu": spaced,
+}
+
+// FromEnv returns the separators of the locale in LC_ALL, LC_NUMERIC or LANG,
+// falling back to English separators.
+func FromEnv(getenv func(string) string) Separators {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale := getenv(key); locale != "" {
+			return ForLocale(locale)
+		}
+	}
+
+	return english
+}
+
+// ForLocale returns the separators of a POSIX locale name such as de_DE.UTF-8
+func ForLocale(locale string) Separators {
+	name, _, _ := strings.Cut(locale, ".")
+	language, region, _ := strings.Cut(name, "_")
+
+	if region == "CH" && (language == "de" || language == "it") {
+		return swiss
+	}
+
+	if separators, ok := languages[strings.ToLower(language)]; ok {
+		return separators
+	}
+
+	return english
+}
+
+// Format groups the integer digits of a plain decimal number such as
+// "-1234567.891" and replaces its decimal point. Anything else, including
+// numbers in exponent notation, NaN and Infinity, is returned unchanged.
+func (s Separators) Format(value string) string {
+	sign, digits := "", value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(digits, ".")
+	if !isDigits(integer) || (hasFraction && !isDigits(fraction)) {
+		return value
+	}
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(s.Group)
+		}
+		sb.WriteRune(digit)
+	}
+
+	if hasFraction {
+		sb.WriteString(s.Decimal)
+		sb.WriteString(fraction)
+	}
+
+	return sb.String()
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
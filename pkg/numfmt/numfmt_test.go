@@ -0,0 +1,64 @@
+package numfmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		separators Separators
+		value      string
+		expected   string
+	}{
+		{name: "small integer", separators: english, value: "999", expected: "999"},
+		{name: "thousands", separators: english, value: "1000", expected: "1,000"},
+		{name: "millions", separators: english, value: "1234567", expected: "1,234,567"},
+		{name: "negative", separators: english, value: "-1234567.5", expected: "-1,234,567.5"},
+		{name: "keeps scale", separators: english, value: "12345.00", expected: "12,345.00"},
+		{name: "european", separators: european, value: "1234567.89", expected: "1.234.567,89"},
+		{name: "spaced", separators: spaced, value: "1234.5", expected: "1 234,5"},
+		{name: "exponent", separators: english, value: "1.5e+10", expected: "1.5e+10"},
+		{name: "nan", separators: english, value: "NaN", expected: "NaN"},
+		{name: "text", separators: english, value: "12 apples", expected: "12 apples"},
+		{name: "empty", separators: english, value: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.separators.Format(tt.value))
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Separators
+	}{
+		{name: "no locale", env: map[string]string{}, expected: english},
+		{name: "posix", env: map[string]string{"LANG": "C"}, expected: english},
+		{name: "german", env: map[string]string{"LANG": "de_DE.UTF-8"}, expected: european},
+		{name: "swiss german", env: map[string]string{"LANG": "de_CH.UTF-8"}, expected: swiss},
+		{name: "lc_numeric wins over lang", env: map[string]string{"LANG": "en_US.UTF-8", "LC_NUMERIC": "fr_FR.UTF-8"}, expected: spaced},
+		{name: "lc_all wins", env: map[string]string{"LC_ALL": "en_GB.UTF-8", "LC_NUMERIC": "fr_FR.UTF-8"}, expected: english},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.expected, FromEnv(getenv))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package pager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DefaultPager is used when $PAGER is not set. -R keeps the colours of the
+// output.
+const DefaultPager = "less -R"
+
+// FromEnv returns the pager set in $PAGER, or DefaultPager
+func FromEnv(getenv func(string) string) string {
+	if pager := strings.TrimSpace(getenv("PAGER")); pager != "" {
+		return pager
+	}
+
+	return DefaultPager
+}
+
+// Command writes text to a temporary file and returns the command showing it
+// in pager, run with sh -c, and the path of the file to remove once the pager
+// exits.
+func Command(pager, text string) (*exec.Cmd, string, error) {
+	file, err := os.CreateTemp("", "perp-*.txt")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create pager file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(text); err != nil {
+		_ = os.Remove(file.Name())
+		return nil, "", fmt.Errorf("failed to write pager file: %w", err)
+	}
+
+	return exec.Command("sh", "-c", pager+` "$1"`, "sh", file.Name()), file.Name(), nil
+}
+
+// Exceeds reports whether text has more lines than height
+func Exceeds(text string, height int) bool {
+	return strings.Count(strings.TrimRight(text, "\n"), "\n")+1 > height
+}
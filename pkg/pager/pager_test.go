@@ -0,0 +1,35 @@
+package pager
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultPager, FromEnv(func(string) string { return "" }))
+	assert.Equal(t, "most", FromEnv(func(string) string { return " most " }))
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd, path, err := Command("cat", "CREATE TABLE users (id int);\n")
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE users (id int);\n", string(output))
+}
+
+func TestExceeds(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, Exceeds("a\nb\n", 2))
+	assert.True(t, Exceeds("a\nb\nc", 2))
+}
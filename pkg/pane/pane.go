@@ -0,0 +1,94 @@
+package pane
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	// TmuxTemplate opens the viewer in a tmux split to the right
+	TmuxTemplate = `tmux split-window -h "{{ .Viewer }}"`
+
+	// WeztermTemplate opens the viewer in a wezterm split to the right
+	WeztermTemplate = `wezterm cli split-pane --right -- sh -c "{{ .Viewer }}"`
+)
+
+// Data is passed to the command template
+type Data struct {
+	// File is the shell-quoted path of the file to view
+	File string
+	// Format is the file format, "json" or "csv"
+	Format string
+	// Viewer is the default command for viewing the file, e.g. jq piped to less
+	Viewer string
+}
+
+// Detect returns the command template for the terminal multiplexer perp runs
+// in, or an empty string when none is detected.
+func Detect(getenv func(string) string) string {
+	switch {
+	case getenv("TMUX") != "":
+		return TmuxTemplate
+	case getenv("WEZTERM_PANE") != "":
+		return WeztermTemplate
+	default:
+		return ""
+	}
+}
+
+// Command renders the command template for the given file
+func Command(tmpl, file string) (string, error) {
+	t, err := template.New("pane").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid pane command template: %w", err)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	quoted := quote(file)
+
+	viewer := "less -S " + quoted
+	if format == "json" {
+		viewer = fmt.Sprintf("jq -C . %s | less -R", quoted)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, Data{File: quoted, Format: format, Viewer: viewer}); err != nil {
+		return "", fmt.Errorf("invalid pane command template: %w", err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Open runs the command template for file. An empty template falls back to
+// the detected multiplexer.
+func Open(tmpl, file string) error {
+	if tmpl == "" {
+		tmpl = Detect(os.Getenv)
+	}
+
+	if tmpl == "" {
+		return errors.New("not running inside tmux or wezterm; set pane_command in the config to open results in a pane")
+	}
+
+	command, err := Command(tmpl, file)
+	if err != nil {
+		return err
+	}
+
+	if out, err := exec.Command("sh", "-c", command).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to open pane: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// quote wraps s in single quotes for POSIX shells
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,79 @@
+package pane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{name: "tmux", env: map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}, expected: TmuxTemplate},
+		{name: "wezterm", env: map[string]string{"WEZTERM_PANE": "3"}, expected: WeztermTemplate},
+		{name: "tmux inside wezterm", env: map[string]string{"TMUX": "x", "WEZTERM_PANE": "3"}, expected: TmuxTemplate},
+		{name: "none", env: map[string]string{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.expected, Detect(getenv))
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		file     string
+		expected string
+	}{
+		{
+			name:     "tmux with json",
+			tmpl:     TmuxTemplate,
+			file:     "/tmp/results.json",
+			expected: `tmux split-window -h "jq -C . '/tmp/results.json' | less -R"`,
+		},
+		{
+			name:     "wezterm with csv",
+			tmpl:     WeztermTemplate,
+			file:     "/tmp/results.csv",
+			expected: `wezterm cli split-pane --right -- sh -c "less -S '/tmp/results.csv'"`,
+		},
+		{
+			name:     "custom template",
+			tmpl:     `tmux new-window "vd {{ .File }}" # {{ .Format }}`,
+			file:     "/tmp/it's.csv",
+			expected: `tmux new-window "vd '/tmp/it'\''s.csv'" # csv`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			command, err := Command(tt.tmpl, tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, command)
+		})
+	}
+}
+
+func TestCommandInvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	_, err := Command("tmux {{ .File", "/tmp/results.json")
+	assert.ErrorContains(t, err, "invalid pane command template")
+}
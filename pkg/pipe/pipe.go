@@ -0,0 +1,112 @@
+package pipe
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ionut-t/perp/pkg/export"
+)
+
+// Format is the serialisation of the results written to the command's stdin
+type Format string
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+)
+
+const (
+	// Timeout stops commands that never finish, e.g. an interactive pager
+	Timeout = 30 * time.Second
+
+	maxOutputSize = 10 << 20
+)
+
+// Encode serialises query results in the given format
+func Encode(results []map[string]any, format Format) ([]byte, error) {
+	switch format {
+	case CSV:
+		data, err := export.PrepareCSV(results, nil, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := csv.NewWriter(&buf).WriteAll(data); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+
+	case JSON:
+		if len(results) == 0 {
+			return nil, errors.New("no query results to pipe")
+		}
+
+		return json.MarshalIndent(results, "", "  ")
+
+	default:
+		return nil, fmt.Errorf("unsupported pipe format: %s", format)
+	}
+}
+
+// Run streams input through a shell command and returns its standard output.
+// The command is cancelled when ctx is done or after Timeout.
+func Run(ctx context.Context, command string, input []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedWriter{w: &stdout, remaining: maxOutputSize}
+	cmd.Stderr = &limitedWriter{w: &stderr, remaining: maxOutputSize}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("%s timed out after %s", command, Timeout)
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", err, msg)
+		}
+
+		return "", err
+	}
+
+	return stdout.String(), nil
+}
+
+// limitedWriter drops everything written after remaining bytes
+type limitedWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if l.remaining <= 0 {
+		return n, nil
+	}
+
+	if len(p) > l.remaining {
+		p = p[:l.remaining]
+	}
+
+	written, err := l.w.Write(p)
+	l.remaining -= written
+	if err != nil {
+		return written, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,90 @@
+package pipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var results = []map[string]any{
+	{"id": 1, "name": "alice"},
+	{"id": 2, "name": "bob"},
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   Format
+		expected string
+	}{
+		{
+			name:     "csv",
+			format:   CSV,
+			expected: "id,name\n1,alice\n2,bob\n",
+		},
+		{
+			name:     "json",
+			format:   JSON,
+			expected: "[\n  {\n    \"id\": 1,\n    \"name\": \"alice\"\n  },\n  {\n    \"id\": 2,\n    \"name\": \"bob\"\n  }\n]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Encode(results, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Encode(nil, JSON)
+	assert.Error(t, err)
+
+	_, err = Encode(nil, CSV)
+	assert.Error(t, err)
+
+	_, err = Encode(results, Format("xml"))
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	out, err := Run(context.Background(), "tr a-z A-Z", []byte("id,name\n1,alice\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "ID,NAME\n1,ALICE\n", out)
+
+	_, err = Run(context.Background(), "echo broken >&2; exit 3", nil)
+	assert.ErrorContains(t, err, "broken")
+}
+
+func TestLimitedWriter(t *testing.T) {
+	t.Parallel()
+
+	var out []byte
+	w := &limitedWriter{w: writerFunc(func(p []byte) (int, error) {
+		out = append(out, p...)
+		return len(p), nil
+	}), remaining: 4}
+
+	n, err := w.Write([]byte("abcdef"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	_, _ = w.Write([]byte("gh"))
+	assert.Equal(t, "abcd", string(out))
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
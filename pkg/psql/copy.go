@@ -0,0 +1,136 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PSQL_Copy copies data between a client file and a table
+const PSQL_Copy = "\\copy"
+
+// CopyCommand is a parsed \copy command
+type CopyCommand struct {
+	Source  string // table with an optional column list, or a (query) to export
+	From    bool   // import the file into the table
+	File    string
+	Options string // COPY options as written, e.g. WITH (FORMAT csv, HEADER)
+}
+
+// ParseCopy parses \copy table [(columns)] from|to 'file' [options] and
+// \copy (query) to 'file' [options]. Only files are supported, not stdin,
+// stdout or programs.
+func ParseCopy(cmd *Command) (*CopyCommand, error) {
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	args = strings.TrimSpace(strings.TrimPrefix(args, PSQL_Copy))
+
+	source, direction, rest, ok := cutDirection(args)
+	if !ok || source == "" {
+		return nil, fmt.Errorf("\\copy: expected table from|to 'file'")
+	}
+
+	file, options, err := cutFile(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	if file == "" {
+		return nil, fmt.Errorf("\\copy: expected a file name after %s", direction)
+	}
+
+	switch strings.ToLower(file) {
+	case "stdin", "stdout", "pstdin", "pstdout", "program":
+		return nil, fmt.Errorf("\\copy: %s is not supported, use a file", file)
+	}
+
+	c := &CopyCommand{
+		Source:  source,
+		From:    direction == "from",
+		File:    file,
+		Options: options,
+	}
+
+	if c.From && strings.HasPrefix(source, "(") {
+		return nil, fmt.Errorf("\\copy: a query can only be copied to a file")
+	}
+
+	return c, nil
+}
+
+// SQL returns the COPY statement run on the server, streaming the data
+// through the client instead of a server-side file
+func (c CopyCommand) SQL() string {
+	sql := "COPY " + c.Source + " TO STDOUT"
+	if c.From {
+		sql = "COPY " + c.Source + " FROM STDIN"
+	}
+
+	if c.Options != "" {
+		sql += " " + c.Options
+	}
+
+	return sql
+}
+
+// cutDirection splits args at the first from or to keyword that is not
+// quoted or inside parentheses
+func cutDirection(args string) (source, direction, rest string, ok bool) {
+	var quote byte
+	depth := 0
+
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+
+		case c == '(':
+			depth++
+
+		case c == ')':
+			depth--
+
+		case depth == 0 && (i == 0 || isSpace(args[i-1])):
+			for _, keyword := range []string{"from", "to"} {
+				end := i + len(keyword)
+				if end < len(args) && isSpace(args[end]) && strings.EqualFold(args[i:end], keyword) {
+					return strings.TrimSpace(args[:i]), keyword, strings.TrimSpace(args[end:]), true
+				}
+			}
+		}
+	}
+
+	return "", "", "", false
+}
+
+// cutFile returns the file name at the start of rest, quoted with single
+// quotes or up to the first space, and what follows it
+func cutFile(rest string) (file, options string, err error) {
+	if !strings.HasPrefix(rest, "'") {
+		file, options, _ = strings.Cut(rest, " ")
+		return file, strings.TrimSpace(options), nil
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(rest); i++ {
+		if rest[i] != '\'' {
+			sb.WriteByte(rest[i])
+			continue
+		}
+
+		if i+1 < len(rest) && rest[i+1] == '\'' {
+			sb.WriteByte('\'')
+			i++
+			continue
+		}
+
+		return sb.String(), strings.TrimSpace(rest[i+1:]), nil
+	}
+
+	return "", "", fmt.Errorf("\\copy: unterminated quoted file name")
+}
@@ -0,0 +1,71 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCopy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected *CopyCommand
+		sql      string
+	}{
+		{
+			input:    "\\copy users from 'users.csv' with (format csv, header)",
+			expected: &CopyCommand{Source: "users", From: true, File: "users.csv", Options: "with (format csv, header)"},
+			sql:      "COPY users FROM STDIN with (format csv, header)",
+		},
+		{
+			input:    "\\copy users (id, email) TO /tmp/users.txt;",
+			expected: &CopyCommand{Source: "users (id, email)", File: "/tmp/users.txt"},
+			sql:      "COPY users (id, email) TO STDOUT",
+		},
+		{
+			input:    "\\copy (SELECT * FROM orders WHERE status = 'to ship') to 'my orders.csv' csv header",
+			expected: &CopyCommand{Source: "(SELECT * FROM orders WHERE status = 'to ship')", File: "my orders.csv", Options: "csv header"},
+			sql:      "COPY (SELECT * FROM orders WHERE status = 'to ship') TO STDOUT csv header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdCopy, cmd.Type)
+
+			c, err := ParseCopy(cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c)
+			assert.Equal(t, tt.sql, c.SQL())
+		})
+	}
+}
+
+func TestParseCopyErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"\\copy users",
+		"\\copy users from",
+		"\\copy users from stdin",
+		"\\copy (SELECT 1) from 'one.csv'",
+		"\\copy users to 'users.csv",
+	} {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(input)
+			require.NoError(t, err)
+
+			_, err = ParseCopy(cmd)
+			require.Error(t, err)
+		})
+	}
+}
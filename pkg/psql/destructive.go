@@ -0,0 +1,220 @@
+package psql
+
+import (
+	"slices"
+	"strings"
+)
+
+// Destructive is a statement dropping objects or data: DROP, TRUNCATE, ALTER
+// TABLE ... DROP COLUMN, or DELETE and UPDATE changing every row of a table
+type Destructive struct {
+	Statement string
+	Action    string // e.g. "DROP TABLE", "TRUNCATE", "DELETE"
+	Object    string // the first object it applies to, as written
+}
+
+// Description tells what the statement does, e.g. "drops table users"
+func (d Destructive) Description() string {
+	switch d.Action {
+	case "TRUNCATE":
+		return "empties " + d.Object
+	case "DELETE":
+		return "deletes every row of " + d.Object
+	case "UPDATE":
+		return "updates every row of " + d.Object
+	case "DROP COLUMN":
+		return "drops a column of " + d.Object
+	default:
+		return "drops " + strings.ToLower(strings.TrimPrefix(d.Action, "DROP ")) + " " + d.Object
+	}
+}
+
+// dropKinds are the words naming the kind of object after DROP, e.g. DROP
+// MATERIALIZED VIEW
+var dropKinds = []string{
+	"ACCESS", "AGGREGATE", "CAST", "COLLATION", "CONVERSION", "DATA", "DATABASE",
+	"DOMAIN", "EVENT", "EXTENSION", "FOREIGN", "FUNCTION", "GROUP", "INDEX",
+	"LANGUAGE", "MATERIALIZED", "METHOD", "OPERATOR", "OWNED", "POLICY",
+	"PROCEDURE", "PUBLICATION", "ROLE", "ROUTINE", "RULE", "SCHEMA", "SEQUENCE",
+	"SERVER", "STATISTICS", "SUBSCRIPTION", "TABLE", "TABLESPACE", "TRIGGER",
+	"TYPE", "USER", "VIEW", "WRAPPER",
+}
+
+// keptByDrop are the words following DROP in ALTER TABLE when it removes no
+// column or data, e.g. DROP CONSTRAINT or ALTER COLUMN ... DROP DEFAULT
+var keptByDrop = []string{"CONSTRAINT", "DEFAULT", "NOT", "IDENTITY", "EXPRESSION"}
+
+// DestructiveStatements returns the destructive statements of script, in
+// order. DELETE and UPDATE are destructive without a WHERE clause.
+func DestructiveStatements(script string) []Destructive {
+	var found []Destructive
+
+	for _, statement := range SplitStatements(script) {
+		if strings.HasPrefix(statement, "\\") {
+			continue
+		}
+
+		if d, ok := destructive(sqlWords(statement)); ok {
+			d.Statement = statement
+			found = append(found, d)
+		}
+	}
+
+	return found
+}
+
+func destructive(words []sqlWord) (Destructive, bool) {
+	start := commandStart(words)
+	if start < 0 {
+		return Destructive{}, false
+	}
+
+	rest := words[start+1:]
+
+	switch words[start].upper() {
+	case "DROP":
+		var kind []string
+		for len(rest) > 0 && len(kind) < 3 && slices.Contains(dropKinds, rest[0].upper()) {
+			kind = append(kind, rest[0].upper())
+			rest = rest[1:]
+		}
+
+		if len(kind) == 0 {
+			return Destructive{}, false
+		}
+
+		return Destructive{Action: "DROP " + strings.Join(kind, " "), Object: objectName(rest, "IF", "EXISTS", "CONCURRENTLY", "BY")}, true
+
+	case "TRUNCATE":
+		return Destructive{Action: "TRUNCATE", Object: objectName(rest, "TABLE", "ONLY")}, true
+
+	case "ALTER":
+		if len(rest) == 0 || rest[0].upper() != "TABLE" {
+			return Destructive{}, false
+		}
+
+		for i, w := range rest {
+			if w.depth != 0 || w.upper() != "DROP" {
+				continue
+			}
+
+			if i+1 < len(rest) && slices.Contains(keptByDrop, rest[i+1].upper()) {
+				continue
+			}
+
+			return Destructive{Action: "DROP COLUMN", Object: objectName(rest[1:], "IF", "EXISTS", "ONLY")}, true
+		}
+
+	case "DELETE", "UPDATE":
+		if slices.ContainsFunc(rest, func(w sqlWord) bool { return w.depth == 0 && w.upper() == "WHERE" }) {
+			return Destructive{}, false
+		}
+
+		return Destructive{Action: words[start].upper(), Object: objectName(rest, "FROM", "ONLY")}, true
+	}
+
+	return Destructive{}, false
+}
+
+// commandStart returns the index of the word starting the command, after
+// the common table expressions of WITH, or -1 without words
+func commandStart(words []sqlWord) int {
+	if len(words) == 0 {
+		return -1
+	}
+
+	if words[0].upper() != "WITH" {
+		return 0
+	}
+
+	for i, w := range words {
+		switch w.upper() {
+		case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE":
+			if w.depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// objectName returns the first word of words that is not one of skipped
+func objectName(words []sqlWord, skipped ...string) string {
+	for _, w := range words {
+		if !slices.Contains(skipped, w.upper()) {
+			return w.text
+		}
+	}
+	return ""
+}
+
+// sqlWord is a word of a statement, with the depth of the parentheses
+// around it
+type sqlWord struct {
+	text  string
+	depth int
+}
+
+func (w sqlWord) upper() string {
+	return strings.ToUpper(w.text)
+}
+
+// sqlWords returns the names and keywords of statement, qualified names and
+// quoted identifiers included, outside strings, dollar quoted bodies and
+// comments
+func sqlWords(statement string) []sqlWord {
+	var words []sqlWord
+	depth := 0
+
+	for i := 0; i < len(statement); {
+		c := statement[i]
+
+		switch {
+		case c == '(':
+			depth++
+			i++
+
+		case c == ')':
+			depth--
+			i++
+
+		case c == '\'':
+			i = skipQuoted(statement, i+1, '\'', isEscapeString(statement, i))
+
+		case strings.HasPrefix(statement[i:], "--"):
+			if n := strings.IndexByte(statement[i:], '\n'); n != -1 {
+				i += n
+			} else {
+				i = len(statement)
+			}
+
+		case strings.HasPrefix(statement[i:], "/*"):
+			i = skipBlockComment(statement, i+2)
+
+		case c == '$':
+			i = skipDollarQuote(statement, i)
+
+		case c == '"' || isNameChar(c, true):
+			start := i
+			for i < len(statement) {
+				if statement[i] == '"' {
+					i = skipQuoted(statement, i+1, '"', false)
+					continue
+				}
+
+				if statement[i] != '.' && !isNameChar(statement[i], false) {
+					break
+				}
+				i++
+			}
+
+			words = append(words, sqlWord{text: statement[start:i], depth: depth})
+
+		default:
+			i++
+		}
+	}
+
+	return words
+}
@@ -0,0 +1,106 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestructiveStatements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		script   string
+		expected []Destructive
+	}{
+		{
+			name:   "drop table",
+			script: "DROP TABLE IF EXISTS public.users CASCADE;",
+			expected: []Destructive{
+				{Statement: "DROP TABLE IF EXISTS public.users CASCADE", Action: "DROP TABLE", Object: "public.users"},
+			},
+		},
+		{
+			name:   "drop materialized view",
+			script: `drop materialized view "Monthly Sales"`,
+			expected: []Destructive{
+				{Statement: `drop materialized view "Monthly Sales"`, Action: "DROP MATERIALIZED VIEW", Object: `"Monthly Sales"`},
+			},
+		},
+		{
+			name:   "drop database",
+			script: "DROP DATABASE shop",
+			expected: []Destructive{
+				{Statement: "DROP DATABASE shop", Action: "DROP DATABASE", Object: "shop"},
+			},
+		},
+		{
+			name:   "truncate",
+			script: "TRUNCATE TABLE ONLY orders RESTART IDENTITY",
+			expected: []Destructive{
+				{Statement: "TRUNCATE TABLE ONLY orders RESTART IDENTITY", Action: "TRUNCATE", Object: "orders"},
+			},
+		},
+		{
+			name:   "delete without where",
+			script: "DELETE FROM orders",
+			expected: []Destructive{
+				{Statement: "DELETE FROM orders", Action: "DELETE", Object: "orders"},
+			},
+		},
+		{
+			name:   "update with a where clause in a subquery only",
+			script: "UPDATE users SET plan = (SELECT id FROM plans WHERE name = 'free')",
+			expected: []Destructive{
+				{Statement: "UPDATE users SET plan = (SELECT id FROM plans WHERE name = 'free')", Action: "UPDATE", Object: "users"},
+			},
+		},
+		{
+			name:   "delete after common table expressions",
+			script: "WITH old AS (SELECT id FROM users WHERE active) DELETE FROM sessions",
+			expected: []Destructive{
+				{Statement: "WITH old AS (SELECT id FROM users WHERE active) DELETE FROM sessions", Action: "DELETE", Object: "sessions"},
+			},
+		},
+		{
+			name:   "drop column",
+			script: "ALTER TABLE users DROP COLUMN email",
+			expected: []Destructive{
+				{Statement: "ALTER TABLE users DROP COLUMN email", Action: "DROP COLUMN", Object: "users"},
+			},
+		},
+		{
+			name:   "several statements",
+			script: "SELECT 1; DELETE FROM a; UPDATE b SET x = 1 WHERE id = 2; TRUNCATE c;",
+			expected: []Destructive{
+				{Statement: "DELETE FROM a", Action: "DELETE", Object: "a"},
+				{Statement: "TRUNCATE c", Action: "TRUNCATE", Object: "c"},
+			},
+		},
+		{name: "delete with a where clause", script: "DELETE FROM orders WHERE id = 1"},
+		{name: "drop constraint", script: "ALTER TABLE users DROP CONSTRAINT users_email_key"},
+		{name: "drop default", script: "ALTER TABLE users ALTER COLUMN plan DROP DEFAULT"},
+		{name: "keywords in strings and comments", script: "SELECT 'DROP TABLE users' -- TRUNCATE orders"},
+		{name: "function bodies", script: "CREATE FUNCTION f() RETURNS void AS $$ DELETE FROM logs $$ LANGUAGE sql"},
+		{name: "meta-commands", script: "\\dt users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, DestructiveStatements(tt.script))
+		})
+	}
+}
+
+func TestDestructiveDescription(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "drops materialized view sales", Destructive{Action: "DROP MATERIALIZED VIEW", Object: "sales"}.Description())
+	assert.Equal(t, "empties orders", Destructive{Action: "TRUNCATE", Object: "orders"}.Description())
+	assert.Equal(t, "deletes every row of orders", Destructive{Action: "DELETE", Object: "orders"}.Description())
+	assert.Equal(t, "updates every row of users", Destructive{Action: "UPDATE", Object: "users"}.Description())
+	assert.Equal(t, "drops a column of users", Destructive{Action: "DROP COLUMN", Object: "users"}.Description())
+}
@@ -0,0 +1,31 @@
+package psql
+
+import (
+	"strings"
+)
+
+// Commands printing a message
+const (
+	PSQL_Echo  = "\\echo"
+	PSQL_Qecho = "\\qecho"
+)
+
+// EchoText returns the message of \echo or \qecho. Words quoted with single
+// quotes keep their spaces, a doubled quote stands for a quote and -n is
+// ignored as the message is not followed by other output.
+func EchoText(cmd *Command) string {
+	raw := strings.TrimSpace(cmd.Raw)
+	if len(cmd.Arguments) == 0 {
+		return ""
+	}
+
+	_, text, _ := strings.Cut(raw, " ")
+	text = strings.TrimSpace(text)
+
+	if rest, ok := strings.CutPrefix(text, "-n"); ok && (rest == "" || isSpace(rest[0])) {
+		text = strings.TrimSpace(rest)
+	}
+
+	// words are separated by a single space, as in psql
+	return strings.TrimSpace(joinArguments(text, " "))
+}
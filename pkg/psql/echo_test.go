@@ -0,0 +1,33 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEchoText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "\\echo", expected: ""},
+		{input: "\\echo Loading   users", expected: "Loading users"},
+		{input: "\\echo 'Step  1:' done", expected: "Step  1: done"},
+		{input: "\\qecho 'it''s done'", expected: "it's done"},
+		{input: "\\echo -n no newline", expected: "no newline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, EchoText(cmd))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package psql
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PSQL_ErrVerbose shows the most recent error reported by the server
+const PSQL_ErrVerbose = "\\errverbose"
+
+// ErrorDetails lists the fields of err reported by the server, skipping the
+// empty ones, one row per field, or only says there is no error
+func ErrorDetails(err *pgconn.PgError) *Result {
+	if err == nil {
+		return &Result{Message: "There is no previous error"}
+	}
+
+	result := &Result{Columns: []string{"Field", "Value"}}
+
+	add := func(field, value string) {
+		if value != "" {
+			result.Rows = append(result.Rows, map[string]any{"Field": field, "Value": value})
+		}
+	}
+
+	add("Severity", err.Severity)
+	add("SQLSTATE", err.Code)
+	add("Message", err.Message)
+	add("Detail", err.Detail)
+	add("Hint", err.Hint)
+	add("Position", position(err.Position))
+	add("Internal query", err.InternalQuery)
+	add("Internal position", position(err.InternalPosition))
+	add("Context", err.Where)
+	add("Schema", err.SchemaName)
+	add("Table", err.TableName)
+	add("Column", err.ColumnName)
+	add("Data type", err.DataTypeName)
+	add("Constraint", err.ConstraintName)
+
+	if err.File != "" {
+		add("Location", fmt.Sprintf("%s, %s:%d", err.Routine, err.File, err.Line))
+	}
+
+	return result
+}
+
+// position returns the character position of an error, counted from 1, or an
+// empty string when it is not set
+func position(p int32) string {
+	if p == 0 {
+		return ""
+	}
+	return fmt.Sprint(p)
+}
@@ -0,0 +1,36 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	result := ErrorDetails(nil)
+	assert.Empty(t, result.Rows)
+	assert.Equal(t, "There is no previous error", result.Message)
+
+	result = ErrorDetails(&pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "42703",
+		Message:  `column "nme" does not exist`,
+		Hint:     `Perhaps you meant to reference the column "users.name".`,
+		Position: 8,
+		File:     "parse_relation.c",
+		Line:     3722,
+		Routine:  "errorMissingColumn",
+	})
+
+	assert.Equal(t, []string{"Field", "Value"}, result.Columns)
+
+	require.Len(t, result.Rows, 6)
+	assert.Equal(t, map[string]any{"Field": "SQLSTATE", "Value": "42703"}, result.Rows[1])
+	assert.Equal(t, map[string]any{"Field": "Hint", "Value": `Perhaps you meant to reference the column "users.name".`}, result.Rows[3])
+	assert.Equal(t, map[string]any{"Field": "Position", "Value": "8"}, result.Rows[4])
+	assert.Equal(t, map[string]any{"Field": "Location", "Value": "errorMissingColumn, parse_relation.c:3722"}, result.Rows[5])
+}
@@ -3,6 +3,7 @@ package psql
 import (
 	"context"
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -52,38 +53,8 @@ type Result struct {
 
 // Execute executes a psql command with optional pattern matching
 func (e *executor) Execute(ctx context.Context, cmd *Command) (*Result, error) {
-	pattern := ""
-	if len(cmd.Arguments) > 0 {
-		pattern = cmd.Arguments[0]
-	}
-
-	start := time.Now()
-	var result *Result
-	var err error
-
-	switch cmd.Type {
-	case CmdListTables:
-		if pattern != "" {
-			result, err = e.listTablesWithPattern(ctx, pattern)
-		} else if cmd.IsExtended() {
-			result, err = e.listTablesExtended(ctx)
-		} else {
-			result, err = e.listTables(ctx)
-		}
-
-	default:
-		return e.execute(ctx, cmd)
-	}
-
-	if result != nil {
-		result.ExecutionTime = time.Since(start)
-	}
-
-	return result, err
-}
+	patterns := cmd.Arguments
 
-// execute runs a parsed psql command
-func (e *executor) execute(ctx context.Context, cmd *Command) (*Result, error) {
 	start := time.Now()
 
 	var result *Result
@@ -103,74 +74,86 @@ func (e *executor) execute(ctx context.Context, cmd *Command) (*Result, error) {
 		result, err = e.describeTable(ctx, cmd.Arguments[0])
 	case CmdListTables:
 		if cmd.IsExtended() {
-			result, err = e.listTablesExtended(ctx)
+			result, err = e.listTablesExtended(ctx, patterns)
 		} else {
-			result, err = e.listTables(ctx)
+			result, err = e.listTables(ctx, patterns)
 		}
 	case CmdListViews:
 		if cmd.IsExtended() {
-			result, err = e.listViewsExtended(ctx)
+			result, err = e.listViewsExtended(ctx, patterns)
 		} else {
-			result, err = e.listViews(ctx)
+			result, err = e.listViews(ctx, patterns)
 		}
 	case CmdListIndexes:
 		if cmd.IsExtended() {
-			result, err = e.listIndexesExtended(ctx)
+			result, err = e.listIndexesExtended(ctx, patterns)
 		} else {
-			result, err = e.listIndexes(ctx)
+			result, err = e.listIndexes(ctx, patterns)
 		}
 	case CmdListDatabases:
-		if cmd.IsExtended() {
-			result, err = e.listDatabasesExtended(ctx)
-		} else {
-			result, err = e.listDatabases(ctx)
-		}
+		result, err = e.listDatabases(ctx, patterns, cmd.IsExtended())
 	case CmdListSchemas:
 		if cmd.IsExtended() {
-			result, err = e.listSchemasExtended(ctx)
+			result, err = e.listSchemasExtended(ctx, patterns)
 		} else {
-			result, err = e.listSchemas(ctx)
+			result, err = e.listSchemas(ctx, patterns)
 		}
 	case CmdListSequences:
 		if cmd.IsExtended() {
-			result, err = e.listSequencesExtended(ctx)
+			result, err = e.listSequencesExtended(ctx, patterns)
 		} else {
-			result, err = e.listSequences(ctx)
+			result, err = e.listSequences(ctx, patterns)
 		}
 	case CmdListUsers:
 		if cmd.IsExtended() {
-			result, err = e.listUsersExtended(ctx)
+			result, err = e.listUsersExtended(ctx, patterns)
 		} else {
-			result, err = e.listUsers(ctx)
+			result, err = e.listUsers(ctx, patterns)
 		}
 	case CmdListFunctions:
 		if cmd.IsExtended() {
-			result, err = e.listFunctionsExtended(ctx)
+			result, err = e.listFunctionsExtended(ctx, patterns)
 		} else {
-			result, err = e.listFunctions(ctx)
+			result, err = e.listFunctions(ctx, patterns)
 		}
 	case CmdListForeignTables:
 		if cmd.IsExtended() {
-			result, err = e.listForeignTablesExtended(ctx)
+			result, err = e.listForeignTablesExtended(ctx, patterns)
 		} else {
-			result, err = e.listForeignTables(ctx)
+			result, err = e.listForeignTables(ctx, patterns)
 		}
 	case CmdListMaterializedViews:
 		if cmd.IsExtended() {
-			result, err = e.listMaterializedViewsExtended(ctx)
+			result, err = e.listMaterializedViewsExtended(ctx, patterns)
 		} else {
-			result, err = e.listMaterializedViews(ctx)
+			result, err = e.listMaterializedViews(ctx, patterns)
 		}
 	case CmdListExtensions:
-		if cmd.IsExtended() {
-			result, err = e.listExtensionsExtended(ctx)
-		} else {
-			result, err = e.listExtensions(ctx)
-		}
+		result, err = e.listExtensions(ctx, patterns, cmd.IsExtended())
+	case CmdListTypes:
+		result, err = e.listTypes(ctx, patterns, cmd.IsExtended())
+	case CmdListTablespaces:
+		result, err = e.listTablespaces(ctx, patterns, cmd.IsExtended())
+	case CmdListConfig:
+		result, err = e.listConfig(ctx, patterns, cmd.IsExtended())
+	case CmdListDomains:
+		result, err = e.listDomains(ctx, patterns, cmd.IsExtended())
+	case CmdListForeignServers:
+		result, err = e.listForeignServers(ctx, patterns, cmd.IsExtended())
+	case CmdListForeignTablesByServer:
+		result, err = e.listForeignTablesByServer(ctx, patterns, cmd.IsExtended())
+	case CmdListPublications:
+		result, err = e.listPublications(ctx, patterns)
+	case CmdListSubscriptions:
+		result, err = e.listSubscriptions(ctx, patterns)
 	case CmdListPrivileges:
-		result, err = e.listPrivileges(ctx)
+		result, err = e.listPrivileges(ctx, patterns)
 	case CmdConnInfo:
 		result, err = e.connectionInfo(ctx)
+	case CmdErrVerbose:
+		result = ErrorDetails(e.db.LastError())
+	case CmdCopy:
+		result, err = e.copy(ctx, cmd)
 	default:
 		return nil, fmt.Errorf("command not implemented: %s", cmd.Raw)
 	}
@@ -250,6 +233,14 @@ func (e *executor) describeTable(ctx context.Context, tableName string) (*Result
 		return nil, err
 	}
 
+	// Sequences and indexes have their own description
+	switch kind, _ := e.relationKind(ctx, safeName); kind {
+	case "S":
+		return e.describeSequence(ctx, safeName, tableName)
+	case "i", "I":
+		return e.describeIndex(ctx, safeName, tableName)
+	}
+
 	// First, get the table columns
 	columnsQuery := `
 		SELECT 
@@ -281,6 +272,22 @@ func (e *executor) describeTable(ctx context.Context, tableName string) (*Result
 		return nil, err
 	}
 
+	message := fmt.Sprintf("Table \"%s\"", tableName)
+
+	// Get the definition of views
+	view, err := e.getViewInfo(ctx, safeName)
+	if err == nil && view != nil {
+		rows = append(rows, view.rows()...)
+		message = view.title(tableName)
+	}
+
+	// Get the partition key and partitions of partitioned tables
+	partitioning, err := e.getPartitionInfo(ctx, safeName)
+	if err == nil && partitioning != nil {
+		rows = append(rows, partitioning.rows()...)
+		message = partitioning.title(tableName)
+	}
+
 	// Get indexes
 	indexRows, err := e.getTableIndexes(ctx, safeName)
 	if err == nil && len(indexRows) > 0 {
@@ -361,10 +368,31 @@ func (e *executor) describeTable(ctx context.Context, tableName string) (*Result
 	return &Result{
 		Columns: columns,
 		Rows:    rows,
-		Message: fmt.Sprintf("Table \"%s\"", tableName),
+		Message: message,
 	}, nil
 }
 
+// relationKind returns the pg_class.relkind of the relation, e.g. "r" for a
+// table or "S" for a sequence
+func (e *executor) relationKind(ctx context.Context, name string) (string, error) {
+	result, err := e.db.Query(ctx, "SELECT c.relkind::text FROM pg_catalog.pg_class c WHERE c.oid = $1::regclass", name)
+	if err != nil {
+		return "", err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var kind string
+	if rows.Next() {
+		if err := rows.Scan(&kind); err != nil {
+			return "", err
+		}
+	}
+
+	return kind, rows.Err()
+}
+
 // getTableIndexes retrieves indexes for a table
 func (e *executor) getTableIndexes(ctx context.Context, tableName string) ([]map[string]any, error) {
 	query := `
@@ -450,7 +478,7 @@ func (e *executor) getTableReferencedBy(ctx context.Context, tableName string) (
 }
 
 // listTables implements \dt command
-func (e *executor) listTables(ctx context.Context) (*Result, error) {
+func (e *executor) listTables(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -466,28 +494,14 @@ func (e *executor) listTables(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tables: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list tables")
 }
 
 // listTablesExtended implements \dt+ command
-func (e *executor) listTablesExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listTablesExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -510,28 +524,14 @@ func (e *executor) listTablesExtended(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tables: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list tables")
 }
 
 // listViews implements \dv command
-func (e *executor) listViews(ctx context.Context) (*Result, error) {
+func (e *executor) listViews(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -546,28 +546,14 @@ func (e *executor) listViews(ctx context.Context) (*Result, error) {
 		WHERE c.relkind IN ('v', 'm')
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list views: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list views")
 }
 
 // listViewsExtended implements \dv+ command
-func (e *executor) listViewsExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listViewsExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -589,28 +575,14 @@ func (e *executor) listViewsExtended(ctx context.Context) (*Result, error) {
 		WHERE c.relkind IN ('v', 'm')
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list views: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list views")
 }
 
 // listIndexes implements \di command
-func (e *executor) listIndexes(ctx context.Context) (*Result, error) {
+func (e *executor) listIndexes(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -627,28 +599,14 @@ func (e *executor) listIndexes(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list indexes: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list indexes")
 }
 
 // listIndexesExtended implements \di+ command
-func (e *executor) listIndexesExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listIndexesExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -672,123 +630,191 @@ func (e *executor) listIndexesExtended(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list indexes")
+}
+
+// listDatabases implements \l and \l+ commands
+func (e *executor) listDatabases(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := databasesQuery(patterns, extended)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list indexes: %w", err)
+		return nil, err
 	}
 
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	result, err := e.execAndExtract(ctx, query, "list databases")
 	if err != nil {
 		return nil, err
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	result.Message = "List of databases"
+
+	return result, nil
 }
 
-// listDatabases implements \l command
-func (e *executor) listDatabases(ctx context.Context) (*Result, error) {
-	query := `
+func databasesQuery(patterns []string, extended bool) (string, error) {
+	var extendedColumns, tablespaceJoin string
+	if extended {
+		extendedColumns = `,
+			CASE 
+				WHEN pg_catalog.has_database_privilege(d.datname, 'CONNECT')
+				THEN pg_catalog.pg_size_pretty(pg_catalog.pg_database_size(d.oid))
+				ELSE 'No Access'
+			END as "Size",
+			t.spcname as "Tablespace",
+			pg_catalog.shobj_description(d.oid, 'pg_database') as "Description"`
+		tablespaceJoin = `
+		JOIN pg_catalog.pg_tablespace t on d.dattablespace = t.oid`
+	}
+
+	query := fmt.Sprintf(`
 		SELECT 
 			d.datname as "Name",
 			pg_catalog.pg_get_userbyid(d.datdba) as "Owner",
 			pg_catalog.pg_encoding_to_char(d.encoding) as "Encoding",
 			d.datcollate as "Collate",
 			d.datctype as "Ctype",
-			pg_catalog.array_to_string(d.datacl, E'\n') AS "Access privileges"
-		FROM pg_catalog.pg_database d
-		ORDER BY 1;`
+			pg_catalog.array_to_string(d.datacl, E'\n') AS "Access privileges"%s
+		FROM pg_catalog.pg_database d%s
+		WHERE true
+		%%s
+		ORDER BY 1;`, extendedColumns, tablespaceJoin)
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list databases: %w", err)
-	}
+	return patternedQuery(query, patterns, databaseColumns)
+}
 
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+// listTablespaces implements \db and \db+ commands
+func (e *executor) listTablespaces(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := tablespacesQuery(patterns, extended)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of databases",
-	}, nil
+	if extended {
+		return e.execAndExtract(ctx, query, "list tablespaces (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list tablespaces")
 }
 
-// listDatabasesExtended implements \l+ command
-func (e *executor) listDatabasesExtended(ctx context.Context) (*Result, error) {
-	query := `
-		SELECT 
-			d.datname as "Name",
-			pg_catalog.pg_get_userbyid(d.datdba) as "Owner",
-			pg_catalog.pg_encoding_to_char(d.encoding) as "Encoding",
-			d.datcollate as "Collate",
-			d.datctype as "Ctype",
-			pg_catalog.array_to_string(d.datacl, E'\n') AS "Access privileges",
-			CASE 
-				WHEN pg_catalog.has_database_privilege(d.datname, 'CONNECT')
-				THEN pg_catalog.pg_size_pretty(pg_catalog.pg_database_size(d.oid))
+// tablespacesQuery builds the \db query. Like \l+ does for databases, the
+// size is only read where pg_tablespace_size is allowed: with the CREATE
+// privilege, pg_read_all_stats or for the default tablespace of the database.
+func tablespacesQuery(patterns []string, extended bool) (string, error) {
+	var alternatives []string
+	for _, pattern := range patterns {
+		if err := validatePattern(pattern); err != nil {
+			return "", err
+		}
+		alternatives = append(alternatives, fmt.Sprintf("t.spcname LIKE '%s' ESCAPE '\\'", patternToLike(pattern)))
+	}
+
+	var condition string
+	if len(alternatives) > 0 {
+		condition = "WHERE " + anyOf(alternatives)
+	}
+
+	var extendedColumns string
+	if extended {
+		extendedColumns = `,
+			pg_catalog.array_to_string(t.spcacl, E'\n') as "Access privileges",
+			pg_catalog.array_to_string(t.spcoptions, ', ') as "Options",
+			CASE
+				WHEN pg_catalog.has_tablespace_privilege(t.oid, 'CREATE')
+					OR pg_catalog.pg_has_role('pg_read_all_stats', 'USAGE')
+					OR t.oid = (
+						SELECT d.dattablespace FROM pg_catalog.pg_database d
+						WHERE d.datname = pg_catalog.current_database()
+					)
+				THEN pg_catalog.pg_size_pretty(pg_catalog.pg_tablespace_size(t.oid))
 				ELSE 'No Access'
 			END as "Size",
-			t.spcname as "Tablespace",
-			pg_catalog.shobj_description(d.oid, 'pg_database') as "Description"
-		FROM pg_catalog.pg_database d
-		JOIN pg_catalog.pg_tablespace t on d.dattablespace = t.oid
-		ORDER BY 1;`
-
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list databases: %w", err)
+			pg_catalog.shobj_description(t.oid, 'pg_tablespace') as "Description"`
 	}
 
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	return fmt.Sprintf(`
+		SELECT
+			t.spcname as "Name",
+			pg_catalog.pg_get_userbyid(t.spcowner) as "Owner",
+			pg_catalog.pg_tablespace_location(t.oid) as "Location"%s
+		FROM pg_catalog.pg_tablespace t
+		%s
+		ORDER BY 1;`, extendedColumns, condition), nil
+}
+
+// listConfig implements \dconfig and \dconfig+ commands
+func (e *executor) listConfig(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := configQuery(patterns, extended)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of databases",
-	}, nil
+	if extended {
+		return e.execAndExtract(ctx, query, "list settings (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list settings")
+}
+
+// configQuery builds the \dconfig query on pg_settings. Like psql, the
+// settings changed from their default are listed when there is no pattern.
+// Setting names are lower case and may hold dots, so the whole pattern is
+// matched against the name.
+func configQuery(patterns []string, extended bool) (string, error) {
+	var alternatives []string
+	for _, pattern := range patterns {
+		if err := validatePattern(pattern); err != nil {
+			return "", err
+		}
+		alternatives = append(alternatives, fmt.Sprintf("s.name LIKE '%s' ESCAPE '\\'", patternToLike(strings.ToLower(pattern))))
+	}
+
+	condition := "s.source <> 'default' AND s.source <> 'override'"
+	if len(alternatives) > 0 {
+		condition = anyOf(alternatives)
+	}
+
+	var extendedColumns string
+	if extended {
+		extendedColumns = `,
+			s.vartype as "Type",
+			s.context as "Context",
+			CASE
+				WHEN s.enumvals IS NOT NULL THEN pg_catalog.array_to_string(s.enumvals, ', ')
+				WHEN s.min_val IS NOT NULL THEN s.min_val || ' .. ' || s.max_val
+			END as "Allowed values",
+			s.boot_val as "Default",
+			s.short_desc as "Description"`
+	}
+
+	return fmt.Sprintf(`
+		SELECT
+			s.name as "Parameter",
+			s.setting as "Setting",
+			s.unit as "Unit",
+			s.source as "Source"%s
+		FROM pg_catalog.pg_settings s
+		WHERE %s
+		ORDER BY 1;`, extendedColumns, condition), nil
 }
 
 // listSchemas implements \dn command
-func (e *executor) listSchemas(ctx context.Context) (*Result, error) {
+func (e *executor) listSchemas(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname AS "Name",
 			pg_catalog.pg_get_userbyid(n.nspowner) AS "Owner"
 		FROM pg_catalog.pg_namespace n
 		WHERE n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+		%s
 		ORDER BY 1;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list schemas: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of schemas",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, schemaColumns, "List of schemas", "list schemas")
 }
 
 // listSchemasExtended implements \dn+ command
-func (e *executor) listSchemasExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listSchemasExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname AS "Name",
@@ -797,27 +823,14 @@ func (e *executor) listSchemasExtended(ctx context.Context) (*Result, error) {
 			pg_catalog.obj_description(n.oid, 'pg_namespace') AS "Description"
 		FROM pg_catalog.pg_namespace n
 		WHERE n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+		%s
 		ORDER BY 1;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list schemas: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of schemas",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, schemaColumns, "List of schemas", "list schemas")
 }
 
 // listSequences implements \ds command
-func (e *executor) listSequences(ctx context.Context) (*Result, error) {
+func (e *executor) listSequences(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -830,28 +843,14 @@ func (e *executor) listSequences(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list sequences: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list sequences")
 }
 
 // listSequencesExtended implements \ds+ command
-func (e *executor) listSequencesExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listSequencesExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -871,28 +870,14 @@ func (e *executor) listSequencesExtended(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list sequences: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
-}
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list sequences")
+}
 
 // listUsers implements \du command
-func (e *executor) listUsers(ctx context.Context) (*Result, error) {
+func (e *executor) listUsers(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			r.rolname as "Role name",
@@ -904,20 +889,16 @@ func (e *executor) listUsers(ctx context.Context) (*Result, error) {
 			) as "Member of"
 		FROM pg_catalog.pg_roles r
 		WHERE r.rolname !~ '^pg_'
+		%s
 		ORDER BY 1;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	result, err := e.listPatterned(ctx, query, patterns, roleColumns, "List of roles", "list users")
 	if err != nil {
 		return nil, err
 	}
 
 	// Format the "Member of" column to show array values properly
-	for _, row := range rows {
+	for _, row := range result.Rows {
 		if memberOf, ok := row["Member of"]; ok {
 			if arr, ok := memberOf.([]interface{}); ok {
 				members := make([]string, len(arr))
@@ -929,15 +910,11 @@ func (e *executor) listUsers(ctx context.Context) (*Result, error) {
 		}
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of roles",
-	}, nil
+	return result, nil
 }
 
 // listUsersExtended implements \du+ command
-func (e *executor) listUsersExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listUsersExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			r.rolname as "Role name",
@@ -963,20 +940,16 @@ func (e *executor) listUsersExtended(ctx context.Context) (*Result, error) {
 			pg_catalog.shobj_description(r.oid, 'pg_authid') as "Description"
 		FROM pg_catalog.pg_roles r
 		WHERE r.rolname !~ '^pg_'
+		%s
 		ORDER BY 1;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	result, err := e.listPatterned(ctx, query, patterns, roleColumns, "List of roles", "list users")
 	if err != nil {
 		return nil, err
 	}
 
 	// Format boolean values to be more readable
-	for _, row := range rows {
+	for _, row := range result.Rows {
 		for _, col := range []string{"Superuser", "Inherit", "Create role", "Create DB", "Can login", "Replication"} {
 			if val, ok := row[col]; ok {
 				if b, ok := val.(bool); ok {
@@ -990,15 +963,11 @@ func (e *executor) listUsersExtended(ctx context.Context) (*Result, error) {
 		}
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of roles",
-	}, nil
+	return result, nil
 }
 
 // listFunctions implements \df command
-func (e *executor) listFunctions(ctx context.Context) (*Result, error) {
+func (e *executor) listFunctions(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -1013,30 +982,16 @@ func (e *executor) listFunctions(ctx context.Context) (*Result, error) {
 			END as "Type"
 		FROM pg_catalog.pg_proc p
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
-		WHERE pg_catalog.pg_function_is_visible(p.oid)
-		AND n.nspname <> 'pg_catalog'
+		WHERE n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
+		%s
 		ORDER BY 1, 2, 4;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list functions: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of functions",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, functionColumns, "List of functions", "list functions")
 }
 
 // listFunctionsExtended implements \df+ command
-func (e *executor) listFunctionsExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listFunctionsExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -1071,30 +1026,16 @@ func (e *executor) listFunctionsExtended(ctx context.Context) (*Result, error) {
 		FROM pg_catalog.pg_proc p
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
 		LEFT JOIN pg_catalog.pg_language l ON l.oid = p.prolang
-		WHERE pg_catalog.pg_function_is_visible(p.oid)
-		AND n.nspname <> 'pg_catalog'
+		WHERE n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
+		%s
 		ORDER BY 1, 2, 4;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list functions: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of functions",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, functionColumns, "List of functions", "list functions")
 }
 
 // listForeignTables implements \dE command
-func (e *executor) listForeignTables(ctx context.Context) (*Result, error) {
+func (e *executor) listForeignTables(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -1109,28 +1050,14 @@ func (e *executor) listForeignTables(ctx context.Context) (*Result, error) {
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list foreign tables: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list foreign tables")
 }
 
 // listForeignTablesExtended implements \dE+ command
-func (e *executor) listForeignTablesExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listForeignTablesExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT 
 			n.nspname as "Schema",
@@ -1150,47 +1077,35 @@ func (e *executor) listForeignTablesExtended(ctx context.Context) (*Result, erro
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
 		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	result, err := e.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list foreign tables: %w", err)
-	}
-
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
-	if err != nil {
-		return nil, err
-	}
-
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list foreign tables")
 }
 
-// listMaterializedViews implements \dm command
-func (e *executor) listMaterializedViews(ctx context.Context) (*Result, error) {
+// listMaterializedViews implements \dm command, with the refresh status of
+// each materialized view
+func (e *executor) listMaterializedViews(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT
 			n.nspname as "Schema",
 			c.relname as "Name",
 			'materialized view' as "Type",
-			pg_catalog.pg_get_userbyid(c.relowner) as "Owner"
+			pg_catalog.pg_get_userbyid(c.relowner) as "Owner",
+			` + materializedViewRefreshColumns + `
 		FROM pg_catalog.pg_class c
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
 		WHERE c.relkind = 'm'
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	return e.execAndExtract(ctx, query, "list materialized views")
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list materialized views")
 }
 
 // listMaterializedViewsExtended implements \dm+ command
-func (e *executor) listMaterializedViewsExtended(ctx context.Context) (*Result, error) {
+func (e *executor) listMaterializedViewsExtended(ctx context.Context, patterns []string) (*Result, error) {
 	query := `
 		SELECT
 			n.nspname as "Schema",
@@ -1202,21 +1117,36 @@ func (e *executor) listMaterializedViewsExtended(ctx context.Context) (*Result,
 				WHEN 't' THEN 'temporary'
 				WHEN 'u' THEN 'unlogged'
 			END as "Persistence",
-			pg_catalog.pg_size_pretty(pg_catalog.pg_table_size(c.oid)) as "Size",
+			` + materializedViewRefreshColumns + `,
 			obj_description(c.oid, 'pg_class') as "Description"
 		FROM pg_catalog.pg_class c
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
 		WHERE c.relkind = 'm'
 		AND n.nspname <> 'pg_catalog'
 		AND n.nspname <> 'information_schema'
-		AND pg_catalog.pg_table_is_visible(c.oid)
+		%s
 		ORDER BY 1,2;`
 
-	return e.execAndExtract(ctx, query, "list materialized views (extended)")
+	return e.listPatterned(ctx, query, patterns, relationColumns, "List of relations", "list materialized views (extended)")
+}
+
+// listExtensions implements \dx and \dx+ commands. Besides the details of
+// each extension, \dx+ shows the version available to update to and the
+// objects the extension owns.
+func (e *executor) listExtensions(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := extensionsQuery(patterns, extended)
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		return e.execAndExtract(ctx, query, "list extensions (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list extensions")
 }
 
-// listExtensions implements \dx command
-func (e *executor) listExtensions(ctx context.Context) (*Result, error) {
+func extensionsQuery(patterns []string, extended bool) (string, error) {
 	query := `
 		SELECT
 			e.extname as "Name",
@@ -1227,40 +1157,383 @@ func (e *executor) listExtensions(ctx context.Context) (*Result, error) {
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
 		LEFT JOIN pg_catalog.pg_description c ON c.objoid = e.oid
 			AND c.classoid = 'pg_catalog.pg_extension'::pg_catalog.regclass
+		WHERE true
+		%s
 		ORDER BY 1;`
 
-	return e.execAndExtract(ctx, query, "list extensions")
-}
-
-// listExtensionsExtended implements \dx+ command
-func (e *executor) listExtensionsExtended(ctx context.Context) (*Result, error) {
-	query := `
+	if extended {
+		query = `
 		SELECT
 			e.extname as "Name",
 			e.extversion as "Version",
+			CASE
+				WHEN a.default_version IS DISTINCT FROM e.extversion THEN a.default_version
+			END as "Update Available",
 			n.nspname as "Schema",
 			c.description as "Description",
 			pg_catalog.pg_get_userbyid(e.extowner) as "Owner",
 			CASE
 				WHEN e.extrelocatable THEN 'relocatable'
 				ELSE 'not relocatable'
-			END as "Relocatable"
+			END as "Relocatable",
+			pg_catalog.array_to_string(ARRAY(
+				SELECT pg_catalog.pg_describe_object(d.classid, d.objid, 0)
+				FROM pg_catalog.pg_depend d
+				WHERE d.refclassid = 'pg_catalog.pg_extension'::pg_catalog.regclass
+					AND d.refobjid = e.oid
+					AND d.deptype = 'e'
+				ORDER BY 1
+			), E'\n') as "Objects"
 		FROM pg_catalog.pg_extension e
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
 		LEFT JOIN pg_catalog.pg_description c ON c.objoid = e.oid
 			AND c.classoid = 'pg_catalog.pg_extension'::pg_catalog.regclass
+		LEFT JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname
+		WHERE true
+		%s
 		ORDER BY 1;`
+	}
+
+	return patternedQuery(query, patterns, extensionColumns)
+}
+
+// userTypesCondition keeps the types created by users, leaving out the array
+// types and the row types of tables, views and other relations
+const userTypesCondition = `
+		(t.typrelid = 0 OR (
+			SELECT c.relkind = 'c' FROM pg_catalog.pg_class c WHERE c.oid = t.typrelid
+		))
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_catalog.pg_type el WHERE el.oid = t.typelem AND el.typarray = t.oid
+		)
+		AND n.nspname <> 'pg_catalog'
+		AND n.nspname <> 'information_schema'
+		AND n.nspname !~ '^pg_toast'`
+
+// typeKindColumn names the kind of a type
+const typeKindColumn = `
+			CASE t.typtype
+				WHEN 'b' THEN 'base'
+				WHEN 'c' THEN 'composite'
+				WHEN 'd' THEN 'domain'
+				WHEN 'e' THEN 'enum'
+				WHEN 'r' THEN 'range'
+				WHEN 'm' THEN 'multirange'
+				WHEN 'p' THEN 'pseudo'
+			END as "Kind"`
+
+// listTypes implements \dT and \dT+ commands. With \dT+, enums list their
+// labels, domains their base type and composite types their attributes.
+func (e *executor) listTypes(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := typesQuery(patterns, extended)
+	if err != nil {
+		return nil, err
+	}
 
-	return e.execAndExtract(ctx, query, "list extensions (extended)")
+	if extended {
+		return e.execAndExtract(ctx, query, "list types (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list types")
 }
 
-// listPrivileges implements \dp and \z commands
-func (e *executor) listPrivileges(ctx context.Context) (*Result, error) {
+func typesQuery(patterns []string, extended bool) (string, error) {
+	columns := `
+			n.nspname as "Schema",
+			pg_catalog.format_type(t.oid, NULL) as "Name",` + typeKindColumn + `,
+			pg_catalog.obj_description(t.oid, 'pg_type') as "Description"`
+
+	if extended {
+		columns = `
+			n.nspname as "Schema",
+			pg_catalog.format_type(t.oid, NULL) as "Name",
+			t.typname as "Internal Name",` + typeKindColumn + `,
+			CASE t.typtype
+				WHEN 'e' THEN pg_catalog.array_to_string(ARRAY(
+					SELECT el.enumlabel
+					FROM pg_catalog.pg_enum el
+					WHERE el.enumtypid = t.oid
+					ORDER BY el.enumsortorder
+				), E'\n')
+				WHEN 'd' THEN pg_catalog.format_type(t.typbasetype, t.typtypmod)
+					|| CASE WHEN t.typnotnull THEN ' not null' ELSE '' END
+				WHEN 'c' THEN pg_catalog.array_to_string(ARRAY(
+					SELECT a.attname || ' ' || pg_catalog.format_type(a.atttypid, a.atttypmod)
+					FROM pg_catalog.pg_attribute a
+					WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+					ORDER BY a.attnum
+				), E'\n')
+				WHEN 'r' THEN pg_catalog.format_type(
+					(SELECT r.rngsubtype FROM pg_catalog.pg_range r WHERE r.rngtypid = t.oid), NULL)
+			END as "Elements",
+			CASE
+				WHEN t.typrelid != 0 THEN 'tuple'
+				WHEN t.typlen < 0 THEN 'var'
+				ELSE t.typlen::pg_catalog.text
+			END as "Size",
+			pg_catalog.pg_get_userbyid(t.typowner) as "Owner",
+			pg_catalog.array_to_string(t.typacl, E'\n') as "Access privileges",
+			pg_catalog.obj_description(t.oid, 'pg_type') as "Description"`
+	}
+
 	query := `
+		SELECT` + columns + `
+		FROM pg_catalog.pg_type t
+		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+		WHERE` + userTypesCondition + `
+		%s
+		ORDER BY 1, 2;`
+
+	return patternedQuery(query, patterns, typeColumns)
+}
+
+// listDomains implements \dD and \dD+ commands
+func (e *executor) listDomains(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := domainsQuery(patterns, extended)
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		return e.execAndExtract(ctx, query, "list domains (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list domains")
+}
+
+func domainsQuery(patterns []string, extended bool) (string, error) {
+	var extendedColumns string
+	if extended {
+		extendedColumns = `,
+			pg_catalog.pg_get_userbyid(t.typowner) as "Owner",
+			pg_catalog.array_to_string(t.typacl, E'\n') as "Access privileges",
+			pg_catalog.obj_description(t.oid, 'pg_type') as "Description"`
+	}
+
+	query := fmt.Sprintf(`
 		SELECT
 			n.nspname as "Schema",
-			c.relname as "Name",
-			CASE c.relkind
+			t.typname as "Name",
+			pg_catalog.format_type(t.typbasetype, t.typtypmod) as "Type",
+			(
+				SELECT c.collname FROM pg_catalog.pg_collation c, pg_catalog.pg_type bt
+				WHERE c.oid = t.typcollation AND bt.oid = t.typbasetype
+				AND t.typcollation <> bt.typcollation
+			) as "Collation",
+			CASE WHEN t.typnotnull THEN 'not null' END as "Nullable",
+			t.typdefault as "Default",
+			pg_catalog.array_to_string(ARRAY(
+				SELECT pg_catalog.pg_get_constraintdef(r.oid, true)
+				FROM pg_catalog.pg_constraint r
+				WHERE t.oid = r.contypid AND r.contype = 'c'
+				ORDER BY r.conname
+			), ' ') as "Check"%s
+		FROM pg_catalog.pg_type t
+		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+		WHERE t.typtype = 'd'
+		AND n.nspname <> 'pg_catalog'
+		AND n.nspname <> 'information_schema'
+		%%s
+		ORDER BY 1, 2;`, extendedColumns)
+
+	return patternedQuery(query, patterns, typeColumns)
+}
+
+// listForeignServers implements \des and \des+ commands
+func (e *executor) listForeignServers(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := foreignServersQuery(patterns, extended)
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		return e.execAndExtract(ctx, query, "list foreign servers (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list foreign servers")
+}
+
+func foreignServersQuery(patterns []string, extended bool) (string, error) {
+	var extendedColumns string
+	if extended {
+		extendedColumns = `,
+			pg_catalog.array_to_string(s.srvacl, E'\n') as "Access privileges",
+			s.srvtype as "Type",
+			s.srvversion as "Version",
+			pg_catalog.array_to_string(s.srvoptions, ', ') as "FDW options",
+			pg_catalog.obj_description(s.oid, 'pg_foreign_server') as "Description"`
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			s.srvname as "Name",
+			pg_catalog.pg_get_userbyid(s.srvowner) as "Owner",
+			f.fdwname as "Foreign-data wrapper"%s
+		FROM pg_catalog.pg_foreign_server s
+		JOIN pg_catalog.pg_foreign_data_wrapper f ON f.oid = s.srvfdw
+		WHERE true
+		%%s
+		ORDER BY 1;`, extendedColumns)
+
+	return patternedQuery(query, patterns, foreignServerColumns)
+}
+
+// listForeignTablesByServer implements \det and \det+ commands. Like \dE,
+// the pattern matches the schema and name of the foreign tables.
+func (e *executor) listForeignTablesByServer(ctx context.Context, patterns []string, extended bool) (*Result, error) {
+	query, err := foreignTablesByServerQuery(patterns, extended)
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		return e.execAndExtract(ctx, query, "list foreign tables by server (extended)")
+	}
+
+	return e.execAndExtract(ctx, query, "list foreign tables by server")
+}
+
+func foreignTablesByServerQuery(patterns []string, extended bool) (string, error) {
+	var extendedColumns string
+	if extended {
+		extendedColumns = `,
+			pg_catalog.array_to_string(ft.ftoptions, ', ') as "FDW options",
+			pg_catalog.obj_description(c.oid, 'pg_class') as "Description"`
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			n.nspname as "Schema",
+			c.relname as "Table",
+			s.srvname as "Server"%s
+		FROM pg_catalog.pg_foreign_table ft
+		JOIN pg_catalog.pg_class c ON c.oid = ft.ftrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_foreign_server s ON s.oid = ft.ftserver
+		WHERE true
+		%%s
+		ORDER BY 3, 1, 2;`, extendedColumns)
+
+	return patternedQuery(query, patterns, relationColumns)
+}
+
+// listPublications implements \dRp command. Columns missing on older
+// servers, such as pubviaroot before PostgreSQL 13, are read through jsonb
+// and left empty.
+func (e *executor) listPublications(ctx context.Context, patterns []string) (*Result, error) {
+	query, err := publicationsQuery(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.execAndExtract(ctx, query, "list publications")
+}
+
+func publicationsQuery(patterns []string) (string, error) {
+	return patternedQuery(`
+		SELECT
+			p.pubname as "Name",
+			pg_catalog.pg_get_userbyid(p.pubowner) as "Owner",
+			p.puballtables as "All tables",
+			p.pubinsert as "Inserts",
+			p.pubupdate as "Updates",
+			p.pubdelete as "Deletes",
+			(pg_catalog.to_jsonb(p) ->> 'pubtruncate')::boolean as "Truncates",
+			(pg_catalog.to_jsonb(p) ->> 'pubviaroot')::boolean as "Via root",
+			pg_catalog.array_to_string(ARRAY(
+				SELECT pg_catalog.format('%%I.%%I', pt.schemaname, pt.tablename)
+				FROM pg_catalog.pg_publication_tables pt
+				WHERE pt.pubname = p.pubname
+				ORDER BY 1
+			), E'\n') as "Tables"
+		FROM pg_catalog.pg_publication p
+		WHERE true
+		%s
+		ORDER BY 1;`, patterns, publicationColumns)
+}
+
+// listSubscriptions implements \dRs command. The status comes from the apply
+// worker of the subscription, when it runs.
+func (e *executor) listSubscriptions(ctx context.Context, patterns []string) (*Result, error) {
+	query, err := subscriptionsQuery(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.execAndExtract(ctx, query, "list subscriptions")
+}
+
+func subscriptionsQuery(patterns []string) (string, error) {
+	return patternedQuery(`
+		SELECT
+			s.subname as "Name",
+			pg_catalog.pg_get_userbyid(s.subowner) as "Owner",
+			s.subenabled as "Enabled",
+			pg_catalog.array_to_string(s.subpublication, ', ') as "Publication",
+			s.subslotname as "Slot",
+			CASE
+				WHEN NOT s.subenabled THEN 'disabled'
+				WHEN w.pid IS NULL THEN 'not running'
+				ELSE 'running'
+			END as "Status",
+			w.received_lsn::text as "Received LSN",
+			w.last_msg_receipt_time as "Last message received",
+			w.latest_end_time as "Latest end time"
+		FROM pg_catalog.pg_subscription s
+		LEFT JOIN LATERAL (
+			SELECT st.pid, st.received_lsn, st.last_msg_receipt_time, st.latest_end_time
+			FROM pg_catalog.pg_stat_subscription st
+			WHERE st.subid = s.oid AND st.relid IS NULL
+			ORDER BY st.pid
+			LIMIT 1
+		) w ON true
+		WHERE s.subdbid = (SELECT d.oid FROM pg_catalog.pg_database d WHERE d.datname = pg_catalog.current_database())
+		%s
+		ORDER BY 1;`, patterns, subscriptionColumns)
+}
+
+// listPrivileges implements \dp and \z commands. Each row is the
+// privileges a grantee received from a grantor on a relation, or on one of
+// its columns. Relations without an ACL show the default privileges of their
+// owner. With a schema in the pattern, relations outside the search path are
+// listed too.
+func (e *executor) listPrivileges(ctx context.Context, patterns []string) (*Result, error) {
+	query, err := privilegesQuery(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.execAndExtract(ctx, query, "list privileges")
+}
+
+func privilegesQuery(patterns []string) (string, error) {
+	return patternedQuery(`
+		WITH relations AS (
+			SELECT c.oid, n.nspname, c.relname, c.relkind, c.relowner, c.relacl
+			FROM pg_catalog.pg_class c
+			LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relkind IN ('r', 'v', 'm', 'S', 'f', 'p')
+			AND n.nspname <> 'pg_catalog'
+			AND n.nspname <> 'information_schema'
+			%s
+		),
+		acls AS (
+			SELECT r.nspname, r.relname, r.relkind, NULL::name AS attname, a.*
+			FROM relations r
+			CROSS JOIN LATERAL pg_catalog.aclexplode(COALESCE(r.relacl, pg_catalog.acldefault(
+				CASE WHEN r.relkind = 'S' THEN 's' ELSE 'r' END::"char", r.relowner
+			))) a
+			UNION ALL
+			SELECT r.nspname, r.relname, r.relkind, att.attname, a.*
+			FROM relations r
+			JOIN pg_catalog.pg_attribute att
+				ON att.attrelid = r.oid AND NOT att.attisdropped AND att.attacl IS NOT NULL
+			CROSS JOIN LATERAL pg_catalog.aclexplode(att.attacl) a
+		)
+		SELECT
+			nspname as "Schema",
+			relname as "Name",
+			CASE relkind
 				WHEN 'r' THEN 'table'
 				WHEN 'v' THEN 'view'
 				WHEN 'm' THEN 'materialized view'
@@ -1268,37 +1541,101 @@ func (e *executor) listPrivileges(ctx context.Context) (*Result, error) {
 				WHEN 'f' THEN 'foreign table'
 				WHEN 'p' THEN 'partitioned table'
 			END as "Type",
-			pg_catalog.array_to_string(c.relacl, E'\n') AS "Access privileges",
-			pg_catalog.array_to_string(ARRAY(
-				SELECT attname || ' (' ||
-					pg_catalog.array_to_string(attacl, E', ') || ')'
-				FROM pg_catalog.pg_attribute a
-				WHERE attrelid = c.oid AND NOT attisdropped AND attacl IS NOT NULL
-			), E'\n') AS "Column privileges"
-		FROM pg_catalog.pg_class c
-		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
-		WHERE c.relkind IN ('r', 'v', 'm', 'S', 'f', 'p')
-		AND n.nspname <> 'pg_catalog'
-		AND n.nspname <> 'information_schema'
-		AND pg_catalog.pg_table_is_visible(c.oid)
-		ORDER BY 1, 2;`
-
-	return e.execAndExtract(ctx, query, "list privileges")
+			COALESCE(attname::text, '') as "Column",
+			CASE WHEN grantee = 0 THEN 'PUBLIC' ELSE pg_catalog.pg_get_userbyid(grantee) END as "Grantee",
+			pg_catalog.string_agg(
+				privilege_type || CASE WHEN is_grantable THEN ' (grant option)' ELSE '' END,
+				', ' ORDER BY privilege_type
+			) as "Privileges",
+			pg_catalog.pg_get_userbyid(grantor) as "Grantor"
+		FROM acls
+		GROUP BY nspname, relname, relkind, attname, grantee, grantor
+		ORDER BY 1, 2, 4, 5;`, patterns, relationColumns)
 }
 
-// connectionInfo implements \conninfo command
+// connectionInfo implements \conninfo command. It returns a single row with
+// the server, the SSL state, the age of the backend serving the session and
+// the settings changed with SET in the session.
 func (e *executor) connectionInfo(ctx context.Context) (*Result, error) {
 	query := `
 		SELECT
 			current_database() as "Database",
 			current_user as "User",
+			session_user as "Session User",
 			COALESCE(inet_server_addr()::text, 'localhost') as "Host",
 			COALESCE(inet_server_port()::text, 'socket') as "Port",
-			version() as "Server Version"`
+			current_setting('server_version') as "Server Version",
+			COALESCE((
+				SELECT CASE WHEN ssl THEN version || ' (' || cipher || ', ' || bits || ' bits)' END
+				FROM pg_stat_ssl WHERE pid = pg_backend_pid()
+			), 'off') as "SSL",
+			pg_backend_pid() as "Backend PID",
+			current_setting('search_path') as "Search Path",
+			(
+				SELECT date_trunc('second', now() - backend_start)::text
+				FROM pg_stat_activity WHERE pid = pg_backend_pid()
+			) as "Connected For",
+			COALESCE((
+				SELECT string_agg(name || ' = ' || current_setting(name), ', ' ORDER BY name)
+				FROM pg_catalog.pg_settings WHERE source = 'session'
+			), 'none') as "Session Settings"`
 
 	return e.execAndExtract(ctx, query, "get connection info")
 }
 
+// copy implements \copy, streaming the file through the client so the server
+// does not need access to it
+func (e *executor) copy(ctx context.Context, cmd *Command) (*Result, error) {
+	c, err := ParseCopy(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var rows int64
+
+	if c.From {
+		file, err := os.Open(c.File)
+		if err != nil {
+			return nil, fmt.Errorf("\\copy: %w", err)
+		}
+		defer file.Close()
+
+		rows, err = e.db.CopyFrom(ctx, file, c.SQL())
+		if err != nil {
+			return nil, fmt.Errorf("\\copy from %s failed: %w", c.File, err)
+		}
+	} else {
+		file, err := os.Create(c.File)
+		if err != nil {
+			return nil, fmt.Errorf("\\copy: %w", err)
+		}
+
+		rows, err = e.db.CopyTo(ctx, file, c.SQL())
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("\\copy to %s failed: %w", c.File, err)
+		}
+	}
+
+	direction := "to"
+	if c.From {
+		direction = "from"
+	}
+
+	return &Result{
+		Columns: []string{"Copy", "File", "Rows", "Time"},
+		Rows: []map[string]any{{
+			"Copy": c.Source + " " + direction,
+			"File": c.File,
+			"Rows": rows,
+			"Time": time.Since(start).Round(time.Millisecond).String(),
+		}},
+	}, nil
+}
+
 // validatePattern checks if a pattern contains only safe characters
 // Returns an error if the pattern contains potentially dangerous characters
 func validatePattern(pattern string) error {
@@ -1376,47 +1713,92 @@ func buildPatternCondition(pattern string, schemaCol, nameCol string) string {
 	return ""
 }
 
-// listTablesWithPattern lists tables matching the given pattern
-func (e *executor) listTablesWithPattern(ctx context.Context, pattern string) (*Result, error) {
-	// Validate pattern to prevent SQL injection
-	if err := validatePattern(pattern); err != nil {
-		return nil, err
+// patternColumns are the columns a pattern is matched against
+type patternColumns struct {
+	schema  string // empty for objects outside schemas, like roles
+	name    string
+	visible string // the check that an object is in the search path
+}
+
+var (
+	relationColumns = patternColumns{schema: "n.nspname", name: "c.relname", visible: "pg_catalog.pg_table_is_visible(c.oid)"}
+	functionColumns = patternColumns{schema: "n.nspname", name: "p.proname", visible: "pg_catalog.pg_function_is_visible(p.oid)"}
+	typeColumns     = patternColumns{schema: "n.nspname", name: "t.typname", visible: "pg_catalog.pg_type_is_visible(t.oid)"}
+	schemaColumns   = patternColumns{name: "n.nspname"}
+	roleColumns     = patternColumns{name: "r.rolname"}
+
+	databaseColumns      = patternColumns{name: "d.datname"}
+	extensionColumns     = patternColumns{name: "e.extname"}
+	foreignServerColumns = patternColumns{name: "s.srvname"}
+	publicationColumns   = patternColumns{name: "p.pubname"}
+	subscriptionColumns  = patternColumns{name: "s.subname"}
+)
+
+// patternedQuery fills the %s following the conditions of query with the
+// ones matching patterns, split into schema and name. As in psql, without a
+// schema only the objects in the search path are listed, and objects matching
+// any of the patterns are listed. The pattern of objects outside schemas
+// matches their whole name.
+func patternedQuery(query string, patterns []string, columns patternColumns) (string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{""}
 	}
 
-	patternCondition := buildPatternCondition(pattern, "n.nspname", "c.relname")
+	var alternatives []string
+	for _, pattern := range patterns {
+		if err := validatePattern(pattern); err != nil {
+			return "", err
+		}
 
-	query := fmt.Sprintf(`
-		SELECT 
-			n.nspname as "Schema",
-			c.relname as "Name",
-			CASE c.relkind 
-				WHEN 'r' THEN 'table'
-				WHEN 'p' THEN 'partitioned table'
-			END as "Type",
-			pg_catalog.pg_get_userbyid(c.relowner) as "Owner"
-		FROM pg_catalog.pg_class c
-		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
-		WHERE c.relkind IN ('r','p')
-		AND n.nspname <> 'pg_catalog'
-		AND n.nspname <> 'information_schema'
-		AND n.nspname !~ '^pg_toast'
-		AND pg_catalog.pg_table_is_visible(c.oid)
-		%s
-		ORDER BY 1,2;`, patternCondition)
+		var condition string
+		switch {
+		case columns.schema == "":
+			if pattern != "" {
+				condition = fmt.Sprintf("%s LIKE '%s' ESCAPE '\\'", columns.name, patternToLike(pattern))
+			}
+		default:
+			condition = strings.TrimPrefix(buildPatternCondition(pattern, columns.schema, columns.name), " AND ")
+			if schema, _ := parseSchemaAndTable(pattern); schema == "" {
+				condition = strings.TrimPrefix(condition+" AND "+columns.visible, " AND ")
+			}
+		}
 
-	result, err := e.db.Query(ctx, query)
+		if condition != "" {
+			alternatives = append(alternatives, condition)
+		}
+	}
+
+	var conditions string
+	if len(alternatives) > 0 {
+		conditions = " AND " + anyOf(alternatives)
+	}
+
+	return fmt.Sprintf(query, conditions), nil
+}
+
+// anyOf joins alternative conditions with OR, each in parentheses when there
+// are several
+func anyOf(conditions []string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "((" + strings.Join(conditions, ") OR (") + "))"
+}
+
+// listPatterned runs a list query whose objects match pattern, see
+// patternedQuery
+func (e *executor) listPatterned(ctx context.Context, query string, patterns []string, columns patternColumns, message, errCtxMsg string) (*Result, error) {
+	query, err := patternedQuery(query, patterns, columns)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tables: %w", err)
+		return nil, err
 	}
 
-	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	result, err := e.execAndExtract(ctx, query, errCtxMsg)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Result{
-		Columns: columns,
-		Rows:    rows,
-		Message: "List of relations",
-	}, nil
+	result.Message = message
+
+	return result, nil
 }
@@ -1,10 +1,28 @@
 package psql
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/ionut-t/perp/pkg/db"
 )
 
+var errQueryRecorded = errors.New("query recorded")
+
+// recordingDatabase records the queries sent by the executor and fails them,
+// so a command is checked without a server
+type recordingDatabase struct {
+	db.Database
+	queries []string
+}
+
+func (d *recordingDatabase) Query(_ context.Context, query string, _ ...any) (db.QueryResult, error) {
+	d.queries = append(d.queries, query)
+	return nil, errQueryRecorded
+}
+
 func TestValidatePattern(t *testing.T) {
 	t.Parallel()
 
@@ -286,6 +304,79 @@ func TestBuildPatternCondition(t *testing.T) {
 	}
 }
 
+func TestPatternedQuery(t *testing.T) {
+	t.Parallel()
+
+	const query = "SELECT 1 WHERE true%s ORDER BY 1"
+
+	tests := []struct {
+		name     string
+		pattern  string
+		columns  patternColumns
+		expected string
+	}{
+		{
+			name:     "no pattern",
+			pattern:  "",
+			columns:  relationColumns,
+			expected: "SELECT 1 WHERE true AND pg_catalog.pg_table_is_visible(c.oid) ORDER BY 1",
+		},
+		{
+			name:     "name only is limited to visible objects",
+			pattern:  "user*",
+			columns:  relationColumns,
+			expected: "SELECT 1 WHERE true AND c.relname LIKE 'user%' ESCAPE '\\' AND pg_catalog.pg_table_is_visible(c.oid) ORDER BY 1",
+		},
+		{
+			name:     "schema and name",
+			pattern:  "audit.*_log",
+			columns:  functionColumns,
+			expected: "SELECT 1 WHERE true AND n.nspname LIKE 'audit' ESCAPE '\\' AND p.proname LIKE '%\\_log' ESCAPE '\\' ORDER BY 1",
+		},
+		{
+			name:     "roles match the whole name",
+			pattern:  "app.reader",
+			columns:  roleColumns,
+			expected: "SELECT 1 WHERE true AND r.rolname LIKE 'app.reader' ESCAPE '\\' ORDER BY 1",
+		},
+		{
+			name:     "schemas without a pattern",
+			pattern:  "",
+			columns:  schemaColumns,
+			expected: "SELECT 1 WHERE true ORDER BY 1",
+		},
+		{
+			name:     "several patterns match any of them",
+			pattern:  "users* audit.*_log",
+			columns:  relationColumns,
+			expected: "SELECT 1 WHERE true AND ((c.relname LIKE 'users%' ESCAPE '\\' AND pg_catalog.pg_table_is_visible(c.oid)) OR (n.nspname LIKE 'audit' ESCAPE '\\' AND c.relname LIKE '%\\_log' ESCAPE '\\')) ORDER BY 1",
+		},
+		{
+			name:     "several roles",
+			pattern:  "app* admin",
+			columns:  roleColumns,
+			expected: "SELECT 1 WHERE true AND ((r.rolname LIKE 'app%' ESCAPE '\\') OR (r.rolname LIKE 'admin' ESCAPE '\\')) ORDER BY 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := patternedQuery(query, strings.Fields(tt.pattern), tt.columns)
+			if err != nil {
+				t.Fatalf("patternedQuery() unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("patternedQuery() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := patternedQuery(query, []string{"users*", "users'; DROP TABLE users; --"}, relationColumns); err == nil {
+		t.Error("patternedQuery() expected an error for an invalid pattern")
+	}
+}
+
 func TestSQLInjectionPrevention(t *testing.T) {
 	t.Parallel()
 
@@ -357,6 +448,384 @@ func TestPatternEscaping(t *testing.T) {
 	}
 }
 
+func TestPrivilegesQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		pattern     string
+		contains    []string
+		notContains []string
+		expectError bool
+	}{
+		{
+			name:     "no pattern lists the visible relations",
+			pattern:  "",
+			contains: []string{"pg_catalog.pg_table_is_visible(c.oid)"},
+		},
+		{
+			name:    "table pattern",
+			pattern: "users*",
+			contains: []string{
+				"c.relname LIKE 'users%' ESCAPE '\\'",
+				"pg_catalog.pg_table_is_visible(c.oid)",
+			},
+		},
+		{
+			name:    "schema pattern includes relations outside the search path",
+			pattern: "public.users*",
+			contains: []string{
+				"n.nspname LIKE 'public' ESCAPE '\\'",
+				"c.relname LIKE 'users%' ESCAPE '\\'",
+			},
+			notContains: []string{"pg_table_is_visible"},
+		},
+		{
+			name:        "invalid pattern",
+			pattern:     "users'; DROP TABLE users",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := privilegesQuery(strings.Fields(tt.pattern))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("privilegesQuery(%q) expected an error", tt.pattern)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("privilegesQuery(%q) unexpected error: %v", tt.pattern, err)
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(query, s) {
+					t.Errorf("privilegesQuery(%q) expected to contain %q", tt.pattern, s)
+				}
+			}
+
+			for _, s := range tt.notContains {
+				if strings.Contains(query, s) {
+					t.Errorf("privilegesQuery(%q) expected not to contain %q", tt.pattern, s)
+				}
+			}
+		})
+	}
+}
+
+func TestTablespacesQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := tablespacesQuery(nil, false)
+	if err != nil {
+		t.Fatalf("tablespacesQuery unexpected error: %v", err)
+	}
+
+	for _, s := range []string{"WHERE", `"Size"`, `"Options"`} {
+		if strings.Contains(query, s) {
+			t.Errorf("tablespacesQuery without a pattern expected not to contain %q", s)
+		}
+	}
+
+	query, err = tablespacesQuery([]string{"fast_*"}, true)
+	if err != nil {
+		t.Fatalf("tablespacesQuery unexpected error: %v", err)
+	}
+
+	for _, s := range []string{
+		"t.spcname LIKE 'fast\\_%' ESCAPE '\\'",
+		"pg_catalog.has_tablespace_privilege(t.oid, 'CREATE')",
+		"pg_catalog.pg_tablespace_size(t.oid)",
+		`"Options"`,
+	} {
+		if !strings.Contains(query, s) {
+			t.Errorf("tablespacesQuery expected to contain %q", s)
+		}
+	}
+
+	query, err = tablespacesQuery([]string{"fast_*", "archive"}, false)
+	if err != nil {
+		t.Fatalf("tablespacesQuery unexpected error: %v", err)
+	}
+
+	if !strings.Contains(query, "WHERE ((t.spcname LIKE 'fast\\_%' ESCAPE '\\') OR (t.spcname LIKE 'archive' ESCAPE '\\'))") {
+		t.Errorf("tablespacesQuery with several patterns expected to match any of them, got %q", query)
+	}
+
+	if _, err := tablespacesQuery([]string{"fast'; DROP"}, false); err == nil {
+		t.Errorf("tablespacesQuery expected an error for an invalid pattern")
+	}
+}
+
+func TestConfigQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := configQuery(nil, false)
+	if err != nil {
+		t.Fatalf("configQuery unexpected error: %v", err)
+	}
+
+	if !strings.Contains(query, "s.source <> 'default'") {
+		t.Errorf("configQuery without a pattern expected to list the changed settings")
+	}
+
+	if strings.Contains(query, `"Context"`) {
+		t.Errorf("configQuery expected the context in the extended variant only")
+	}
+
+	query, err = configQuery([]string{"Auto_Explain.*"}, true)
+	if err != nil {
+		t.Fatalf("configQuery unexpected error: %v", err)
+	}
+
+	for _, s := range []string{
+		"s.name LIKE 'auto\\_explain.%' ESCAPE '\\'",
+		`"Context"`,
+		`"Allowed values"`,
+	} {
+		if !strings.Contains(query, s) {
+			t.Errorf("configQuery expected to contain %q", s)
+		}
+	}
+
+	if strings.Contains(query, "s.source <> 'default'") {
+		t.Errorf("configQuery with a pattern expected to list the matching settings whatever their source")
+	}
+
+	query, err = configQuery([]string{"work_mem", "Shared_*"}, false)
+	if err != nil {
+		t.Fatalf("configQuery unexpected error: %v", err)
+	}
+
+	if !strings.Contains(query, "WHERE ((s.name LIKE 'work\\_mem' ESCAPE '\\') OR (s.name LIKE 'shared\\_%' ESCAPE '\\'))") {
+		t.Errorf("configQuery with several patterns expected to match any of them, got %q", query)
+	}
+}
+
+func TestDomainsQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := domainsQuery(nil, false)
+	if err != nil {
+		t.Fatalf("domainsQuery unexpected error: %v", err)
+	}
+
+	if !strings.Contains(query, "pg_catalog.pg_type_is_visible(t.oid)") {
+		t.Errorf("domainsQuery without a pattern expected to list the visible domains")
+	}
+
+	if strings.Contains(query, `"Owner"`) {
+		t.Errorf("domainsQuery expected the owner in the extended variant only")
+	}
+
+	query, err = domainsQuery([]string{"app.email*"}, true)
+	if err != nil {
+		t.Fatalf("domainsQuery unexpected error: %v", err)
+	}
+
+	for _, s := range []string{
+		"n.nspname LIKE 'app' ESCAPE '\\'",
+		"t.typname LIKE 'email%' ESCAPE '\\'",
+		`"Owner"`,
+		`"Description"`,
+	} {
+		if !strings.Contains(query, s) {
+			t.Errorf("domainsQuery expected to contain %q", s)
+		}
+	}
+
+	if _, err := domainsQuery([]string{"email; DROP"}, false); err == nil {
+		t.Errorf("domainsQuery expected an error for an invalid pattern")
+	}
+}
+
+func TestExecuteExtendedWithPatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input       string
+		contains    []string
+		notContains []string
+	}{
+		{
+			input:    "\\dD+ mydomain",
+			contains: []string{"t.typname LIKE 'mydomain' ESCAPE '\\'", `"Owner"`, `"Description"`},
+		},
+		{
+			input:       "\\dD mydomain",
+			contains:    []string{"t.typname LIKE 'mydomain' ESCAPE '\\'"},
+			notContains: []string{`"Owner"`},
+		},
+		{
+			input:    "\\dconfig+ work_mem",
+			contains: []string{"s.name LIKE 'work\\_mem' ESCAPE '\\'", `"Context"`, `"Allowed values"`},
+		},
+		{
+			input:       "\\dconfig work_mem",
+			contains:    []string{"s.name LIKE 'work\\_mem' ESCAPE '\\'"},
+			notContains: []string{`"Context"`},
+		},
+		{
+			input:    "\\db+ fast_*",
+			contains: []string{"t.spcname LIKE 'fast\\_%' ESCAPE '\\'", "pg_catalog.pg_tablespace_size(t.oid)", `"Options"`},
+		},
+		{
+			input:       "\\db fast_*",
+			contains:    []string{"t.spcname LIKE 'fast\\_%' ESCAPE '\\'"},
+			notContains: []string{`"Options"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+
+			database := &recordingDatabase{}
+			if _, err := New(database).Execute(context.Background(), cmd); !errors.Is(err, errQueryRecorded) {
+				t.Fatalf("Execute(%q) expected the recorded query error, got %v", tt.input, err)
+			}
+
+			if len(database.queries) != 1 {
+				t.Fatalf("Execute(%q) expected one query, got %d", tt.input, len(database.queries))
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(database.queries[0], s) {
+					t.Errorf("Execute(%q) expected the query to contain %q", tt.input, s)
+				}
+			}
+
+			for _, s := range tt.notContains {
+				if strings.Contains(database.queries[0], s) {
+					t.Errorf("Execute(%q) expected the query not to contain %q", tt.input, s)
+				}
+			}
+		})
+	}
+}
+
+func TestListQueriesMatchPatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    func(patterns []string) (string, error)
+		pattern  string
+		expected []string
+	}{
+		{
+			name:     "types",
+			query:    func(patterns []string) (string, error) { return typesQuery(patterns, false) },
+			pattern:  "app.mood*",
+			expected: []string{"n.nspname LIKE 'app' ESCAPE '\\'", "t.typname LIKE 'mood%' ESCAPE '\\'"},
+		},
+		{
+			name:     "types extended",
+			query:    func(patterns []string) (string, error) { return typesQuery(patterns, true) },
+			pattern:  "mood",
+			expected: []string{"t.typname LIKE 'mood' ESCAPE '\\'", "pg_catalog.pg_type_is_visible(t.oid)", `"Elements"`},
+		},
+		{
+			name:     "extensions",
+			query:    func(patterns []string) (string, error) { return extensionsQuery(patterns, false) },
+			pattern:  "post*",
+			expected: []string{"e.extname LIKE 'post%' ESCAPE '\\'"},
+		},
+		{
+			name:     "extensions extended",
+			query:    func(patterns []string) (string, error) { return extensionsQuery(patterns, true) },
+			pattern:  "post* hstore",
+			expected: []string{"e.extname LIKE 'post%' ESCAPE '\\'", "e.extname LIKE 'hstore' ESCAPE '\\'", `"Objects"`},
+		},
+		{
+			name:     "foreign servers",
+			query:    func(patterns []string) (string, error) { return foreignServersQuery(patterns, false) },
+			pattern:  "remote*",
+			expected: []string{"s.srvname LIKE 'remote%' ESCAPE '\\'"},
+		},
+		{
+			name:     "foreign servers extended",
+			query:    func(patterns []string) (string, error) { return foreignServersQuery(patterns, true) },
+			pattern:  "remote*",
+			expected: []string{"s.srvname LIKE 'remote%' ESCAPE '\\'", `"FDW options"`},
+		},
+		{
+			name:     "foreign tables by server",
+			query:    func(patterns []string) (string, error) { return foreignTablesByServerQuery(patterns, false) },
+			pattern:  "remote.orders",
+			expected: []string{"n.nspname LIKE 'remote' ESCAPE '\\'", "c.relname LIKE 'orders' ESCAPE '\\'"},
+		},
+		{
+			name:     "foreign tables by server extended",
+			query:    func(patterns []string) (string, error) { return foreignTablesByServerQuery(patterns, true) },
+			pattern:  "orders",
+			expected: []string{"c.relname LIKE 'orders' ESCAPE '\\'", "pg_catalog.pg_table_is_visible(c.oid)", `"FDW options"`},
+		},
+		{
+			name:     "publications",
+			query:    publicationsQuery,
+			pattern:  "orders*",
+			expected: []string{"p.pubname LIKE 'orders%' ESCAPE '\\'", "pg_catalog.format('%I.%I'"},
+		},
+		{
+			name:     "subscriptions",
+			query:    subscriptionsQuery,
+			pattern:  "orders*",
+			expected: []string{"s.subname LIKE 'orders%' ESCAPE '\\'", "s.subdbid ="},
+		},
+		{
+			name:     "databases",
+			query:    func(patterns []string) (string, error) { return databasesQuery(patterns, false) },
+			pattern:  "app*",
+			expected: []string{"d.datname LIKE 'app%' ESCAPE '\\'"},
+		},
+		{
+			name:     "databases extended",
+			query:    func(patterns []string) (string, error) { return databasesQuery(patterns, true) },
+			pattern:  "app*",
+			expected: []string{"d.datname LIKE 'app%' ESCAPE '\\'", `"Tablespace"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.query(nil)
+			if err != nil {
+				t.Fatalf("unexpected error without a pattern: %v", err)
+			}
+
+			if strings.Contains(query, "LIKE") || strings.Contains(query, "%!") {
+				t.Errorf("query without a pattern expected no condition on the name, got %q", query)
+			}
+
+			query, err = tt.query(strings.Fields(tt.pattern))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, s := range tt.expected {
+				if !strings.Contains(query, s) {
+					t.Errorf("query expected to contain %q, got %q", s, query)
+				}
+			}
+
+			if _, err := tt.query([]string{"orders; DROP"}); err == nil {
+				t.Errorf("expected an error for an invalid pattern")
+			}
+		})
+	}
+}
+
 func TestCommandTypeString(t *testing.T) {
 	t.Parallel()
 
@@ -366,9 +835,23 @@ func TestCommandTypeString(t *testing.T) {
 	}{
 		{CmdListMaterializedViews, "list-materialized-views"},
 		{CmdListExtensions, "list-extensions"},
+		{CmdListTypes, "list-types"},
+		{CmdListTablespaces, "list-tablespaces"},
+		{CmdListConfig, "list-config"},
+		{CmdListDomains, "list-domains"},
+		{CmdListForeignServers, "list-foreign-servers"},
+		{CmdListForeignTablesByServer, "list-foreign-tables-by-server"},
+		{CmdListPublications, "list-publications"},
+		{CmdListSubscriptions, "list-subscriptions"},
 		{CmdListPrivileges, "list-privileges"},
 		{CmdConnInfo, "connection-info"},
 		{CmdToggleExpanded, "toggle-expanded"},
+		{CmdSet, "set"},
+		{CmdUnset, "unset"},
+		{CmdExecuteFile, "execute-file"},
+		{CmdGx, "gx"},
+		{CmdGdesc, "gdesc"},
+		{CmdErrVerbose, "errverbose"},
 		{CmdUnknown, "unknown"},
 	}
 
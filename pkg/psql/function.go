@@ -0,0 +1,60 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// PSQL_EditFunction edits the definition of a function in the external editor
+const PSQL_EditFunction = "\\ef"
+
+// FunctionSignature returns the function named by \ef, with its argument
+// types when given, e.g. "public.add(integer, integer)"
+func FunctionSignature(cmd *Command) (string, error) {
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	args = strings.TrimSpace(strings.TrimPrefix(args, PSQL_EditFunction))
+
+	if args == "" {
+		return "", fmt.Errorf("\\ef requires a function name")
+	}
+
+	return args, nil
+}
+
+// FunctionDefinition returns the CREATE OR REPLACE FUNCTION statement of the
+// function. Without argument types, the name must not be overloaded.
+func FunctionDefinition(ctx context.Context, database db.Database, signature string) (string, error) {
+	cast := "regproc"
+	if strings.Contains(signature, "(") {
+		cast = "regprocedure"
+	}
+
+	query := fmt.Sprintf("SELECT pg_catalog.pg_get_functiondef($1::pg_catalog.%s::pg_catalog.oid)", cast)
+
+	result, err := database.Query(ctx, query, signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", signature, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var definition string
+	if rows.Next() {
+		if err := rows.Scan(&definition); err != nil {
+			return "", fmt.Errorf("failed to read the definition of %s: %w", signature, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		if strings.Contains(err.Error(), "more than one function") {
+			return "", fmt.Errorf("%s is overloaded, give its argument types, e.g. %s name(integer)", signature, PSQL_EditFunction)
+		}
+		return "", fmt.Errorf("failed to look up %s: %w", signature, err)
+	}
+
+	return definition, nil
+}
@@ -0,0 +1,41 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "\\ef add", expected: "add"},
+		{input: "\\ef public.add(integer, integer);", expected: "public.add(integer, integer)"},
+		{input: "\\ef \"Add Totals\"()", expected: "\"Add Totals\"()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdEditFunction, cmd.Type)
+
+			signature, err := FunctionSignature(cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, signature)
+		})
+	}
+
+	cmd, err := Parse("\\ef")
+	require.NoError(t, err)
+
+	_, err = FunctionSignature(cmd)
+	require.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package psql
+
+import (
+	"strings"
+)
+
+// PSQL_Gexec ends a query whose cells are executed as statements
+const PSQL_Gexec = "\\gexec"
+
+// CutGexec returns the query of a buffer ending with \gexec and reports
+// whether it does
+func CutGexec(buffer string) (string, bool) {
+	return cutQueryCommand(buffer, PSQL_Gexec)
+}
+
+// cutQueryCommand returns the query of a buffer ending with command, after
+// a space or a semicolon, and reports whether it does
+func cutQueryCommand(buffer, command string) (string, bool) {
+	query, ok := strings.CutSuffix(strings.TrimSpace(buffer), command)
+	if !ok {
+		return buffer, false
+	}
+
+	if query != "" && !isSpace(query[len(query)-1]) && query[len(query)-1] != ';' {
+		return buffer, false
+	}
+
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if query == "" {
+		return buffer, false
+	}
+
+	return query, true
+}
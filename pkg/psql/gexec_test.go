@@ -0,0 +1,33 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCutGexec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		buffer   string
+		expected string
+		ok       bool
+	}{
+		{buffer: "SELECT format('DROP TABLE %I', tablename) FROM pg_tables \\gexec", expected: "SELECT format('DROP TABLE %I', tablename) FROM pg_tables", ok: true},
+		{buffer: "SELECT 'VACUUM users';\n\\gexec\n", expected: "SELECT 'VACUUM users'", ok: true},
+		{buffer: "SELECT 1", expected: "SELECT 1"},
+		{buffer: "\\gexec", expected: "\\gexec"},
+		{buffer: "SELECT x\\gexec", expected: "SELECT x\\gexec"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.buffer, func(t *testing.T) {
+			t.Parallel()
+
+			query, ok := CutGexec(tt.buffer)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
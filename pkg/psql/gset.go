@@ -0,0 +1,189 @@
+package psql
+
+import (
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// PSQL_Gset ends a query whose single row is stored in variables
+const PSQL_Gset = "\\gset"
+
+// Step is a statement of a buffer split at \gset
+type Step struct {
+	Query  string
+	Gset   bool   // store the row returned by Query in variables
+	Prefix string // prepended to the column names to name the variables
+}
+
+// SplitGset splits a buffer into the statements ending with \gset and the
+// statement that follows them, e.g.
+//
+//	SELECT id FROM users WHERE email = 'ana@example.com' \gset
+//	SELECT * FROM orders WHERE user_id = :id;
+//
+// It reports false when the buffer has no \gset.
+func SplitGset(buffer string) ([]Step, bool) {
+	var steps []Step
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(buffer); i++ {
+		c := buffer[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+
+		case strings.HasPrefix(buffer[i:], "--"):
+			// skip to the end of the line comment
+			end := strings.IndexByte(buffer[i:], '\n')
+			if end == -1 {
+				i = len(buffer)
+			} else {
+				i += end
+			}
+
+		case strings.HasPrefix(buffer[i:], PSQL_Gset):
+			rest := buffer[i+len(PSQL_Gset):]
+			if rest != "" && !isSpace(rest[0]) {
+				continue
+			}
+
+			end := strings.IndexByte(rest, '\n')
+			if end == -1 {
+				end = len(rest)
+			}
+
+			prefix := strings.TrimSpace(rest[:end])
+			if fields := strings.Fields(prefix); len(fields) > 0 {
+				prefix = fields[0]
+			}
+
+			steps = append(steps, Step{
+				Query:  strings.TrimSpace(buffer[start:i]),
+				Gset:   true,
+				Prefix: prefix,
+			})
+
+			i += len(PSQL_Gset) + end
+			start = i + 1
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, false
+	}
+
+	if start < len(buffer) {
+		if query := strings.TrimSpace(buffer[start:]); !db.IsEmptyQuery(query) {
+			steps = append(steps, Step{Query: query})
+		}
+	}
+
+	return steps, true
+}
+
+// Interpolate replaces references to variables set with \set or \gset: :name is
+// replaced by the value as is, :'name' by the value quoted as a literal and
+// :"name" by the value quoted as an identifier. References to variables that
+// are not set are left unchanged, as psql does, so casts (::) and array
+// slices keep working.
+func Interpolate(query string, variables map[string]string) string {
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	var quote byte
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end == -1 {
+				end = len(query) - i
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			sb.WriteString("::")
+			i++
+			continue
+
+		case c == ':':
+			if value, n, ok := variableReference(query[i+1:], variables); ok {
+				sb.WriteString(value)
+				i += n
+				continue
+			}
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+// variableReference resolves the reference at the start of s, the text after
+// a colon, and returns its value and length
+func variableReference(s string, variables map[string]string) (string, int, bool) {
+	if s == "" {
+		return "", 0, false
+	}
+
+	if quote := s[0]; quote == '\'' || quote == '"' {
+		end := strings.IndexByte(s[1:], quote)
+		if end == -1 {
+			return "", 0, false
+		}
+
+		value, ok := variables[s[1:end+1]]
+		if !ok {
+			return "", 0, false
+		}
+
+		escaped := strings.ReplaceAll(value, string(quote), string(quote)+string(quote))
+		return string(quote) + escaped + string(quote), end + 2, true
+	}
+
+	n := 0
+	for n < len(s) && isNameChar(s[n], n == 0) {
+		n++
+	}
+	if n == 0 {
+		return "", 0, false
+	}
+
+	value, ok := variables[s[:n]]
+	return value, n, ok
+}
+
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,104 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitGset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		buffer   string
+		expected []Step
+		ok       bool
+	}{
+		{
+			name:   "no gset",
+			buffer: "SELECT 1;",
+		},
+		{
+			name:   "gset then query",
+			buffer: "SELECT id FROM users WHERE email = 'ana@example.com' \\gset\nSELECT * FROM orders WHERE user_id = :id;",
+			expected: []Step{
+				{Query: "SELECT id FROM users WHERE email = 'ana@example.com'", Gset: true},
+				{Query: "SELECT * FROM orders WHERE user_id = :id;"},
+			},
+			ok: true,
+		},
+		{
+			name:   "prefix and several steps",
+			buffer: "SELECT 1 AS a \\gset x_\nSELECT :x_a + 1 AS b \\gset\nSELECT :b",
+			expected: []Step{
+				{Query: "SELECT 1 AS a", Gset: true, Prefix: "x_"},
+				{Query: "SELECT :x_a + 1 AS b", Gset: true},
+				{Query: "SELECT :b"},
+			},
+			ok: true,
+		},
+		{
+			name:   "ends with gset",
+			buffer: "SELECT now() AS started \\gset\n",
+			expected: []Step{
+				{Query: "SELECT now() AS started", Gset: true},
+			},
+			ok: true,
+		},
+		{
+			name:   "ends with a comment",
+			buffer: "SELECT now() AS started \\gset\n-- then run the report\n",
+			expected: []Step{
+				{Query: "SELECT now() AS started", Gset: true},
+			},
+			ok: true,
+		},
+		{
+			name:   "gset in a string or comment",
+			buffer: "SELECT '\\gset' -- \\gset\n",
+		},
+		{
+			name:   "not a gset command",
+			buffer: "SELECT 1 \\gsetx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			steps, ok := SplitGset(tt.buffer)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, steps)
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Parallel()
+
+	variables := map[string]string{"id": "42", "name": "o'brien", "table": `my"table`}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "SELECT * FROM orders WHERE user_id = :id", expected: "SELECT * FROM orders WHERE user_id = 42"},
+		{query: "SELECT :'name'", expected: "SELECT 'o''brien'"},
+		{query: `SELECT * FROM :"table"`, expected: `SELECT * FROM "my""table"`},
+		{query: "SELECT :id::text", expected: "SELECT 42::text"},
+		{query: "SELECT '2024-01-01'::date", expected: "SELECT '2024-01-01'::date"},
+		{query: "SELECT ':id', \":id\"", expected: "SELECT ':id', \":id\""},
+		{query: "SELECT arr[1:n], :missing", expected: "SELECT arr[1:n], :missing"},
+		{query: "SELECT 1 -- :id\n, :id", expected: "SELECT 1 -- :id\n, 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, Interpolate(tt.query, variables))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package psql
+
+import "github.com/ionut-t/perp/pkg/db"
+
+// Query buffer commands changing how the results of a query are shown
+const (
+	PSQL_Gx    = "\\gx"    // run the query with expanded output
+	PSQL_Gdesc = "\\gdesc" // describe the columns of the results without running the query
+)
+
+// CutGx returns the query of a buffer ending with \gx and reports whether it
+// does. Alone, \gx runs the last query again.
+func CutGx(buffer string) (string, bool) {
+	return cutQueryCommand(buffer, PSQL_Gx)
+}
+
+// CutGdesc returns the query of a buffer ending with \gdesc and reports
+// whether it does. Alone, \gdesc describes the last query.
+func CutGdesc(buffer string) (string, bool) {
+	return cutQueryCommand(buffer, PSQL_Gdesc)
+}
+
+// DescribeResult lists the name and type of each column of the results, or
+// only says there are none
+func DescribeResult(columns []db.ResultColumn) *Result {
+	if len(columns) == 0 {
+		return &Result{Message: "The command has no result, or the result has no columns"}
+	}
+
+	result := &Result{
+		Columns: []string{"Column", "Type"},
+		Rows:    make([]map[string]any, len(columns)),
+	}
+
+	for i, column := range columns {
+		result.Rows[i] = map[string]any{"Column": column.Name, "Type": column.Type}
+	}
+
+	return result
+}
@@ -0,0 +1,61 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCutGx(t *testing.T) {
+	t.Parallel()
+
+	query, ok := CutGx("SELECT * FROM users WHERE id = 1 \\gx")
+	assert.True(t, ok)
+	assert.Equal(t, "SELECT * FROM users WHERE id = 1", query)
+
+	_, ok = CutGx("\\gx")
+	assert.False(t, ok, "alone, \\gx is a meta-command")
+
+	_, ok = CutGx("SELECT 1 \\gexec")
+	assert.False(t, ok)
+}
+
+func TestCutGdesc(t *testing.T) {
+	t.Parallel()
+
+	query, ok := CutGdesc("SELECT id, email\nFROM users;\n\\gdesc\n")
+	assert.True(t, ok)
+	assert.Equal(t, "SELECT id, email\nFROM users", query)
+
+	_, ok = CutGdesc("\\gdesc")
+	assert.False(t, ok)
+}
+
+func TestParseGxAndGdesc(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := Parse("\\gx")
+	require.NoError(t, err)
+	assert.Equal(t, CmdGx, cmd.Type)
+
+	cmd, err = Parse("\\gdesc")
+	require.NoError(t, err)
+	assert.Equal(t, CmdGdesc, cmd.Type)
+}
+
+func TestDescribeResult(t *testing.T) {
+	t.Parallel()
+
+	result := DescribeResult([]db.ResultColumn{{Name: "id", Type: "bigint"}, {Name: "total", Type: "numeric(10,2)"}})
+	assert.Equal(t, []string{"Column", "Type"}, result.Columns)
+	assert.Equal(t, []map[string]any{
+		{"Column": "id", "Type": "bigint"},
+		{"Column": "total", "Type": "numeric(10,2)"},
+	}, result.Rows)
+
+	result = DescribeResult(nil)
+	assert.Empty(t, result.Columns)
+	assert.Equal(t, "The command has no result, or the result has no columns", result.Message)
+}
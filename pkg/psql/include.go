@@ -0,0 +1,194 @@
+package psql
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// PSQL_Include is the long form of \i
+const PSQL_Include = "\\include"
+
+// IncludePath returns the file run by \i path. A leading ~ is expanded to the
+// home directory and a relative path is resolved from dir, when set, which
+// may start with ~ too. The path may be quoted with single quotes to keep its
+// spaces.
+func IncludePath(cmd *Command, dir string) (string, error) {
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	if _, rest, ok := strings.Cut(args, " "); ok {
+		args = strings.TrimSpace(rest)
+	} else {
+		args = ""
+	}
+
+	path := args
+	if len(path) >= 2 && path[0] == '\'' && path[len(path)-1] == '\'' {
+		path = strings.ReplaceAll(path[1:len(path)-1], "''", "'")
+	}
+
+	if path == "" {
+		return "", fmt.Errorf("\\i: missing required argument")
+	}
+
+	path, err := expandHome(path)
+	if err != nil {
+		return "", fmt.Errorf("\\i: %w", err)
+	}
+
+	if !filepath.IsAbs(path) && dir != "" {
+		if dir, err = expandHome(dir); err != nil {
+			return "", fmt.Errorf("\\i: %w", err)
+		}
+		path = filepath.Join(dir, path)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// SplitStatements splits a script into its statements, ended by semicolons
+// outside strings, quoted identifiers, dollar quoted bodies and comments.
+// Lines starting with a backslash are meta-commands, returned on their own.
+// Statements holding only comments are dropped.
+func SplitStatements(script string) []string {
+	var statements []string
+	start := 0
+
+	add := func(end int) {
+		if statement := strings.TrimSpace(script[start:end]); !db.IsEmptyQuery(statement) {
+			statements = append(statements, statement)
+		}
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+
+		switch {
+		case c == '\\' && strings.TrimSpace(script[start:i]) == "":
+			end := strings.IndexByte(script[i:], '\n')
+			if end == -1 {
+				end = len(script) - i
+			}
+			start = i
+			i += end
+			add(i)
+			start = i
+
+		case c == ';':
+			add(i)
+			i++
+			start = i
+
+		case c == '\'':
+			i = skipQuoted(script, i+1, '\'', isEscapeString(script, i))
+
+		case c == '"':
+			i = skipQuoted(script, i+1, '"', false)
+
+		case strings.HasPrefix(script[i:], "--"):
+			if end := strings.IndexByte(script[i:], '\n'); end != -1 {
+				i += end
+			} else {
+				i = len(script)
+			}
+
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i+2)
+
+		case c == '$':
+			i = skipDollarQuote(script, i)
+
+		default:
+			i++
+		}
+	}
+
+	add(len(script))
+
+	return statements
+}
+
+// isEscapeString reports whether the string starting at i is an escape
+// string, E'...', where backslashes escape quotes
+func isEscapeString(script string, i int) bool {
+	if i == 0 || (script[i-1] != 'e' && script[i-1] != 'E') {
+		return false
+	}
+	return i < 2 || !isNameChar(script[i-2], false)
+}
+
+// skipQuoted returns the offset following the quote closing the string or
+// identifier starting at i. Doubled quotes stand for a quote.
+func skipQuoted(script string, i int, quote byte, escapes bool) int {
+	for i < len(script) {
+		switch {
+		case escapes && script[i] == '\\':
+			i += 2
+		case script[i] == quote && i+1 < len(script) && script[i+1] == quote:
+			i += 2
+		case script[i] == quote:
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(script)
+}
+
+// skipBlockComment returns the offset following the comment starting at i,
+// which can be nested
+func skipBlockComment(script string, i int) int {
+	depth := 1
+	for i < len(script) {
+		switch {
+		case strings.HasPrefix(script[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(script[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(script)
+}
+
+// skipDollarQuote returns the offset following the $tag$ quoted body starting
+// at i, or the one following the $ when it starts none, as in $1
+func skipDollarQuote(script string, i int) int {
+	end := i + 1
+	for end < len(script) && isNameChar(script[end], end == i+1) {
+		end++
+	}
+
+	if end >= len(script) || script[end] != '$' {
+		return i + 1
+	}
+
+	tag := script[i : end+1]
+	if n := strings.Index(script[end+1:], tag); n != -1 {
+		return end + 1 + n + len(tag)
+	}
+
+	return len(script)
+}
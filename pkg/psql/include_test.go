@@ -0,0 +1,78 @@
+package psql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	script := `-- seed the tables
+CREATE TABLE notes (id int, body text);
+INSERT INTO notes VALUES (1, 'a; b'), (2, E'it\'s; here');
+SELECT "semi;colon" FROM notes /* ; /* nested ; */ */;
+\echo done
+CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;
+SELECT $1;
+-- only a comment;
+SELECT 2`
+
+	assert.Equal(t, []string{
+		"-- seed the tables\nCREATE TABLE notes (id int, body text)",
+		"INSERT INTO notes VALUES (1, 'a; b'), (2, E'it\\'s; here')",
+		`SELECT "semi;colon" FROM notes /* ; /* nested ; */ */`,
+		`\echo done`,
+		"CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql",
+		"SELECT $1",
+		"-- only a comment;\nSELECT 2",
+	}, SplitStatements(script))
+
+	assert.Empty(t, SplitStatements("  -- nothing\n;\n"))
+}
+
+func TestIncludePath(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	tests := []struct {
+		input       string
+		dir         string
+		expected    string
+		expectError bool
+	}{
+		{input: "\\i seed.sql", dir: "/work", expected: "/work/seed.sql"},
+		{input: "\\i ../seed.sql", dir: "/work/sql", expected: "/work/seed.sql"},
+		{input: "\\include /tmp/seed.sql", dir: "/work", expected: "/tmp/seed.sql"},
+		{input: "\\i 'my scripts/seed.sql';", dir: "/work", expected: "/work/my scripts/seed.sql"},
+		{input: "\\i ~/seed.sql", dir: "/work", expected: filepath.Join(home, "seed.sql")},
+		{input: "\\i seed.sql", dir: "~/sql", expected: filepath.Join(home, "sql", "seed.sql")},
+		{input: "\\i seed.sql", expected: "seed.sql"},
+		{input: "\\i", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdExecuteFile, cmd.Type)
+
+			path, err := IncludePath(cmd, tt.dir)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// indexInfo describes an index for \d
+type indexInfo struct {
+	table     string
+	method    string
+	unique    bool
+	primary   bool
+	valid     bool
+	predicate string // the WHERE clause of a partial index
+	included  string // the INCLUDE columns, comma separated
+}
+
+// describeIndex implements \d index_name: the columns of the index, keys or
+// included, followed by its table, access method and predicate
+func (e *executor) describeIndex(ctx context.Context, safeName, name string) (*Result, error) {
+	columnsQuery := `
+		SELECT
+			a.attname AS "Column",
+			pg_catalog.format_type(a.atttypid, a.atttypmod) AS "Type",
+			CASE WHEN a.attnum <= i.indnkeyatts THEN 'yes' ELSE 'no' END AS "Key?",
+			pg_catalog.pg_get_indexdef(a.attrelid, a.attnum, true) AS "Definition"
+		FROM pg_catalog.pg_attribute a
+		JOIN pg_catalog.pg_index i ON i.indexrelid = a.attrelid
+		WHERE a.attrelid = $1::regclass
+		AND a.attnum > 0
+		AND NOT a.attisdropped
+		ORDER BY a.attnum;`
+
+	result, err := e.db.Query(ctx, columnsQuery, safeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe index: %w", err)
+	}
+
+	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := e.getIndexInfo(ctx, safeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe index: %w", err)
+	}
+
+	return &Result{
+		Columns: columns,
+		Rows:    append(rows, info.rows()...),
+		Message: fmt.Sprintf("Index \"%s\"", name),
+	}, nil
+}
+
+// getIndexInfo returns the properties of the index
+func (e *executor) getIndexInfo(ctx context.Context, name string) (*indexInfo, error) {
+	query := `
+		SELECT
+			i.indrelid::regclass::text,
+			am.amname,
+			i.indisunique,
+			i.indisprimary,
+			i.indisvalid,
+			COALESCE(pg_catalog.pg_get_expr(i.indpred, i.indrelid, true), ''),
+			COALESCE((
+				SELECT string_agg(pg_catalog.quote_ident(a.attname), ', ' ORDER BY a.attnum)
+				FROM pg_catalog.pg_attribute a
+				WHERE a.attrelid = i.indexrelid
+				AND a.attnum > i.indnkeyatts
+			), '')
+		FROM pg_catalog.pg_index i
+		JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+		JOIN pg_catalog.pg_am am ON am.oid = c.relam
+		WHERE i.indexrelid = $1::regclass;`
+
+	result, err := e.db.Query(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var info indexInfo
+	if rows.Next() {
+		if err := rows.Scan(&info.table, &info.method, &info.unique, &info.primary, &info.valid, &info.predicate, &info.included); err != nil {
+			return nil, err
+		}
+	}
+
+	return &info, rows.Err()
+}
+
+// rows lists the properties of the index below its columns, like the
+// sections of \d table_name
+func (info *indexInfo) rows() []map[string]any {
+	var rows []map[string]any
+
+	add := func(column, value string) {
+		rows = append(rows, map[string]any{
+			"Column":     column,
+			"Type":       value,
+			"Key?":       "",
+			"Definition": "",
+		})
+	}
+
+	var kind []string
+	switch {
+	case info.primary:
+		kind = append(kind, "primary key")
+	case info.unique:
+		kind = append(kind, "unique")
+	}
+	kind = append(kind, info.method)
+
+	add("", "Properties:")
+	add("    Table", info.table)
+	add("    Kind", strings.Join(kind, ", "))
+
+	if info.included != "" {
+		add("    Included columns", info.included)
+	}
+
+	if info.predicate != "" {
+		add("    Predicate", info.predicate)
+	}
+
+	if !info.valid {
+		add("    Status", "invalid, rebuild it with REINDEX")
+	}
+
+	return rows
+}
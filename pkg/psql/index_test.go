@@ -0,0 +1,67 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexInfoRows(t *testing.T) {
+	t.Parallel()
+
+	values := func(rows []map[string]any) []string {
+		var out []string
+		for _, row := range rows {
+			out = append(out, row["Column"].(string)+": "+row["Type"].(string))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		info     indexInfo
+		expected []string
+	}{
+		{
+			name: "primary key",
+			info: indexInfo{table: "public.users", method: "btree", unique: true, primary: true, valid: true},
+			expected: []string{
+				": Properties:",
+				"    Table: public.users",
+				"    Kind: primary key, btree",
+			},
+		},
+		{
+			name: "partial unique index with included columns",
+			info: indexInfo{
+				table: "orders", method: "btree", unique: true, valid: true,
+				predicate: "deleted_at IS NULL", included: "total, status",
+			},
+			expected: []string{
+				": Properties:",
+				"    Table: orders",
+				"    Kind: unique, btree",
+				"    Included columns: total, status",
+				"    Predicate: deleted_at IS NULL",
+			},
+		},
+		{
+			name: "invalid index",
+			info: indexInfo{table: "docs", method: "gin"},
+			expected: []string{
+				": Properties:",
+				"    Table: docs",
+				"    Kind: gin",
+				"    Status: invalid, rebuild it with REINDEX",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, values(tt.info.rows()))
+		})
+	}
+}
@@ -18,6 +18,8 @@ const (
 	CmdListFunctions
 	CmdListSchemas
 	CmdListDatabases
+	CmdListTablespaces
+	CmdListConfig
 	CmdConnect
 	CmdToggleExpanded
 	CmdToggleTiming
@@ -27,8 +29,28 @@ const (
 	CmdListForeignTables
 	CmdConnInfo
 	CmdListExtensions
+	CmdListTypes
+	CmdListDomains
+	CmdListForeignServers
+	CmdListForeignTablesByServer
+	CmdListPublications
+	CmdListSubscriptions
 	CmdListPrivileges
 	CmdListMaterializedViews
+	CmdPset
+	CmdOutput
+	CmdEcho
+	CmdQecho
+	CmdSet
+	CmdUnset
+	CmdExecuteFile
+	CmdCopy
+	CmdGx
+	CmdGdesc
+	CmdErrVerbose
+	CmdPassword
+	CmdSample
+	CmdEditFunction
 	CmdQuit
 )
 
@@ -62,11 +84,25 @@ const (
 	PSQL_ListMaterializedViewsPlus = "\\dm+"
 	PSQL_ListExtensions            = "\\dx"
 	PSQL_ListExtensionsPlus        = "\\dx+"
+	PSQL_ListTypes                 = "\\dT"
+	PSQL_ListTypesPlus             = "\\dT+"
+	PSQL_ListDomains               = "\\dD"
+	PSQL_ListDomainsPlus           = "\\dD+"
+	PSQL_ListForeignServers        = "\\des"
+	PSQL_ListForeignServersPlus    = "\\des+"
+	PSQL_ListServerTables          = "\\det"
+	PSQL_ListServerTablesPlus      = "\\det+"
+	PSQL_ListPublications          = "\\dRp"
+	PSQL_ListSubscriptions         = "\\dRs"
 	PSQL_ListPrivileges            = "\\dp"
 	PSQL_ListPrivilegesAlt         = "\\z"
 	PSQL_ListDatabases             = "\\l"
 	PSQL_ListDatabasesPlus         = "\\l+"
 	PSQL_ListDatabasesAlt          = "\\list"
+	PSQL_ListTablespaces           = "\\db"
+	PSQL_ListTablespacesPlus       = "\\db+"
+	PSQL_ListConfig                = "\\dconfig"
+	PSQL_ListConfigPlus            = "\\dconfig+"
 	PSQL_Connect                   = "\\c"
 	PSQL_ConnectAlt                = "\\connect"
 	PSQL_ConnInfo                  = "\\conninfo"
@@ -76,6 +112,8 @@ const (
 	PSQL_HelpAlt                   = "\\help"
 	PSQL_HelpPsql                  = "\\?"
 	PSQL_ExecuteFile               = "\\i"
+	PSQL_Output                    = "\\o"
+	PSQL_OutputAlt                 = "\\out"
 	PSQL_Quit                      = "\\q"
 )
 
@@ -102,6 +140,16 @@ var PSQL_COMMANDS = map[string]CommandType{
 	PSQL_ListMaterializedViewsPlus: CmdListMaterializedViews,
 	PSQL_ListExtensions:            CmdListExtensions,
 	PSQL_ListExtensionsPlus:        CmdListExtensions,
+	PSQL_ListTypes:                 CmdListTypes,
+	PSQL_ListTypesPlus:             CmdListTypes,
+	PSQL_ListDomains:               CmdListDomains,
+	PSQL_ListDomainsPlus:           CmdListDomains,
+	PSQL_ListForeignServers:        CmdListForeignServers,
+	PSQL_ListForeignServersPlus:    CmdListForeignServers,
+	PSQL_ListServerTables:          CmdListForeignTablesByServer,
+	PSQL_ListServerTablesPlus:      CmdListForeignTablesByServer,
+	PSQL_ListPublications:          CmdListPublications,
+	PSQL_ListSubscriptions:         CmdListSubscriptions,
 	PSQL_ListPrivileges:            CmdListPrivileges,
 	PSQL_ListPrivilegesAlt:         CmdListPrivileges,
 
@@ -110,15 +158,61 @@ var PSQL_COMMANDS = map[string]CommandType{
 	PSQL_ListDatabasesPlus: CmdListDatabases,
 	PSQL_ListDatabasesAlt:  CmdListDatabases,
 
+	// Tablespace listing
+	PSQL_ListTablespaces:     CmdListTablespaces,
+	PSQL_ListTablespacesPlus: CmdListTablespaces,
+
+	// Server settings
+	PSQL_ListConfig:     CmdListConfig,
+	PSQL_ListConfigPlus: CmdListConfig,
+
 	// Connection
 	PSQL_Connect:    CmdConnect,
 	PSQL_ConnectAlt: CmdConnect,
 	PSQL_ConnInfo:   CmdConnInfo,
 
+	// Errors
+	PSQL_ErrVerbose: CmdErrVerbose,
+
 	// Toggle commands
 	PSQL_ToggleExpanded: CmdToggleExpanded,
 	PSQL_ToggleTiming:   CmdToggleTiming,
 
+	// Display options
+	PSQL_Pset: CmdPset,
+
+	// Output redirection
+	PSQL_Output:    CmdOutput,
+	PSQL_OutputAlt: CmdOutput,
+
+	// Messages
+	PSQL_Echo:  CmdEcho,
+	PSQL_Qecho: CmdQecho,
+
+	// Variables
+	PSQL_Set:   CmdSet,
+	PSQL_Unset: CmdUnset,
+
+	// File execution
+	PSQL_ExecuteFile: CmdExecuteFile,
+	PSQL_Include:     CmdExecuteFile,
+
+	// Client-side copy
+	PSQL_Copy: CmdCopy,
+
+	// Query buffer
+	PSQL_Gx:    CmdGx,
+	PSQL_Gdesc: CmdGdesc,
+
+	// Roles
+	PSQL_Password: CmdPassword,
+
+	// Sampling
+	PSQL_Sample: CmdSample,
+
+	// Functions
+	PSQL_EditFunction: CmdEditFunction,
+
 	// Help commands
 	PSQL_Help:     CmdHelp,
 	PSQL_HelpAlt:  CmdHelp,
@@ -141,9 +235,10 @@ var CommandDescriptions = []struct {
 	{PSQL_Describe, "List tables, views, and sequences"},
 	{PSQL_ListTables, "List tables"},
 	{PSQL_ListTablesPlus, "List tables with additional information"},
+	{PSQL_ListTables + " pattern", "List the tables matching the pattern, e.g. \\dt public.user* or \\dt *_log. \\dv, \\dm, \\di, \\df, \\dn, \\ds, \\dE, \\du, \\dT, \\dx, \\des, \\det, \\dRp, \\dRs and \\l take patterns too"},
 	{PSQL_ListViews, "List views"},
 	{PSQL_ListViewsPlus, "List views with additional information"},
-	{PSQL_ListMaterializedViews, "List materialized views"},
+	{PSQL_ListMaterializedViews, "List materialized views with their refresh status"},
 	{PSQL_ListMaterializedViewsPlus, "List materialized views with additional information"},
 	{PSQL_ListIndexes, "List indexes"},
 	{PSQL_ListIndexesPlus, "List indexes with additional information"},
@@ -156,31 +251,90 @@ var CommandDescriptions = []struct {
 	{PSQL_ListForeignTables, "List foreign tables"},
 	{PSQL_ListForeignTablesPlus, "List foreign tables with additional information"},
 	{PSQL_ListExtensions, "List installed extensions"},
-	{PSQL_ListExtensionsPlus, "List installed extensions with additional information"},
-	{PSQL_ListPrivileges, "List access privileges for tables, views, and sequences"},
+	{PSQL_ListExtensionsPlus, "List installed extensions with available updates and the objects they own"},
+	{PSQL_ListTypes, "List data types: base, composite, domain, enum and range types"},
+	{PSQL_ListTypesPlus, "List data types with enum labels, size, owner and privileges"},
+	{PSQL_ListDomains + " [pattern]", "List domains with their base type, default, nullability and checks"},
+	{PSQL_ListDomainsPlus + " [pattern]", "List domains with their owner and description"},
+	{PSQL_ListForeignServers, "List foreign servers with their foreign-data wrapper"},
+	{PSQL_ListForeignServersPlus, "List foreign servers with their options, version and privileges"},
+	{PSQL_ListServerTables, "List foreign tables by foreign server"},
+	{PSQL_ListServerTablesPlus, "List foreign tables by foreign server with their options"},
+	{PSQL_ListPublications, "List publications with the operations and tables they publish"},
+	{PSQL_ListSubscriptions, "List subscriptions with their status, slot and last message received"},
+	{PSQL_ListPrivileges, "List the privileges of each grantee on tables, views, and sequences"},
+	{PSQL_ListPrivileges + " pattern", "List the privileges on the matching relations, e.g. \\dp public.users*"},
 	{PSQL_ListPrivilegesAlt, "List access privileges (alternative syntax)"},
 	{PSQL_ListUsers, "List users and roles"},
 	{PSQL_ListUsersPlus, "List users and roles with additional information"},
 	{PSQL_ListDatabases, "List databases"},
 	{PSQL_ListDatabasesPlus, "List databases with additional information"},
 	{PSQL_ListDatabasesAlt, "List databases (alternative syntax)"},
+	{PSQL_ListTablespaces + " [pattern]", "List tablespaces with their owner and location"},
+	{PSQL_ListTablespacesPlus + " [pattern]", "List tablespaces with their privileges, options and size"},
+	{PSQL_ListConfig + " [pattern]", "List server settings matching the pattern, or the ones changed from their default"},
+	{PSQL_ListConfigPlus + " [pattern]", "List server settings with their type, context, allowed values and description"},
 
 	// Connection commands
 	{PSQL_Connect, "Connect to database"},
 	{PSQL_ConnectAlt, "Connect to a database (alternative syntax)"},
 	{PSQL_ConnInfo, "Display current connection information"},
 
+	// Errors
+	{PSQL_ErrVerbose, "Show the most recent error with all of its details"},
+
 	// Toggle commands
 	{PSQL_ToggleExpanded, "Toggle expanded output"},
+	{PSQL_ToggleExpanded + " on|off|auto", "Set expanded output, auto expanding only the results wider than the screen"},
 	{PSQL_ToggleTiming, "Toggle timing of commands"},
 
+	// Display options
+	{PSQL_Pset, "Show the display options"},
+	{PSQL_Pset + " border 0|1|2", "Dim, keep or bold the table lines"},
+	{PSQL_Pset + " null [text]", "Set the text shown for NULL values"},
+	{PSQL_Pset + " expanded [on|off|auto]", "Set or toggle expanded output"},
+	{PSQL_Pset + " footer [on|off]", "Set or toggle the row count below the table"},
+	{PSQL_Pset + " pager [on|off|always]", "Set when long outputs are shown in the pager"},
+
+	// Output redirection
+	{PSQL_Output + " [file]", "Also write query results to a .json or .csv file, or stop with no file"},
+	{PSQL_OutputAlt + " [file]", "Also write query results to a file (alternative syntax)"},
+
+	// Client-side copy
+	{PSQL_Copy + " table [(columns)] from 'file' [options]", "Import a client file into a table"},
+	{PSQL_Copy + " table|(query) to 'file' [options]", "Export a table or query to a client file"},
+
+	// Sampling
+	{PSQL_Sample + " table [percent%] [system|bernoulli]", "Show a random sample of a table, 1% by default, with TABLESAMPLE or random()"},
+
+	// Functions
+	{PSQL_EditFunction + " name[(argtypes)]", "Edit a function in the external editor, then run the changed definition after confirmation"},
+
+	// Roles
+	{PSQL_Password + " [role]", "Change the password of a role, the connected user by default"},
+
+	// Messages
+	{PSQL_Echo + " [text]", "Show a message"},
+	{PSQL_Qecho + " [text]", "Write a message to the file set with \\o, or show it"},
+
+	// Variables
+	{PSQL_Set, "List the variables"},
+	{PSQL_Set + " name [value]", "Set a variable used by the next statements as :name, :'name' or :\"name\""},
+	{PSQL_Unset + " name", "Remove a variable"},
+
 	// Help commands
 	{PSQL_Help, "Show help"},
 	{PSQL_HelpAlt, "Show help (alternative syntax)"},
 	{PSQL_HelpPsql, "Show psql help"},
 
 	// File execution
-	// {PSQL_ExecuteFile, "Execute commands from a file"},
+	{PSQL_ExecuteFile + " file", "Execute the statements of a file one by one, relative paths starting from working_directory"},
+
+	// Query buffer
+	{PSQL_Gexec, "End a query and execute each cell of its results as a statement, after confirmation"},
+	{PSQL_Gset + " [prefix]", "End a query and store its row in variables used by the next statements as :name, :'name' or :\"name\""},
+	{PSQL_Gx, "End a query to show its results expanded, or run the last query again expanded"},
+	{PSQL_Gdesc, "End a query to list the names and types of its result columns without running it, or describe the last query"},
 
 	// Quit command
 	{PSQL_Quit, "Quit"},
@@ -205,6 +359,10 @@ func (c CommandType) String() string {
 		return "list-schemas"
 	case CmdListDatabases:
 		return "list-databases"
+	case CmdListTablespaces:
+		return "list-tablespaces"
+	case CmdListConfig:
+		return "list-config"
 	case CmdConnect:
 		return "connect"
 	case CmdConnInfo:
@@ -225,8 +383,48 @@ func (c CommandType) String() string {
 		return "list-materialized-views"
 	case CmdListExtensions:
 		return "list-extensions"
+	case CmdListTypes:
+		return "list-types"
+	case CmdListDomains:
+		return "list-domains"
+	case CmdListForeignServers:
+		return "list-foreign-servers"
+	case CmdListForeignTablesByServer:
+		return "list-foreign-tables-by-server"
+	case CmdListPublications:
+		return "list-publications"
+	case CmdListSubscriptions:
+		return "list-subscriptions"
 	case CmdListPrivileges:
 		return "list-privileges"
+	case CmdPset:
+		return "pset"
+	case CmdOutput:
+		return "output"
+	case CmdEcho:
+		return "echo"
+	case CmdQecho:
+		return "qecho"
+	case CmdSet:
+		return "set"
+	case CmdUnset:
+		return "unset"
+	case CmdExecuteFile:
+		return "execute-file"
+	case CmdCopy:
+		return "copy"
+	case CmdGx:
+		return "gx"
+	case CmdGdesc:
+		return "gdesc"
+	case CmdErrVerbose:
+		return "errverbose"
+	case CmdPassword:
+		return "password"
+	case CmdSample:
+		return "sample"
+	case CmdEditFunction:
+		return "edit-function"
 	case CmdQuit:
 		return "quit"
 	default:
@@ -234,12 +432,12 @@ func (c CommandType) String() string {
 	}
 }
 
-// IsExtended returns true if the command includes the + modifier
+// IsExtended returns true if the command includes the + modifier. It ends
+// the command word, e.g. \dt+ users*, not the arguments.
 func (c *Command) IsExtended() bool {
-	cmd := strings.Trim(c.Raw, ";")
-	cmd = strings.TrimSpace(cmd)
+	parts := strings.Fields(strings.TrimSuffix(strings.TrimSpace(c.Raw), ";"))
 
-	return strings.HasSuffix(cmd, "+")
+	return len(parts) > 0 && strings.HasSuffix(parts[0], "+")
 }
 
 // Parse parses a psql command string
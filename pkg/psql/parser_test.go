@@ -41,6 +41,90 @@ func TestParseNewCommands(t *testing.T) {
 			expectError: false,
 		},
 
+		// Types
+		{
+			name:        "parse \\dT",
+			input:       "\\dT",
+			expectedCmd: CmdListTypes,
+			expectError: false,
+		},
+		{
+			name:        "parse \\dT+",
+			input:       "\\dT+",
+			expectedCmd: CmdListTypes,
+			expectError: false,
+		},
+
+		// Tablespaces
+		{
+			name:        "parse \\db",
+			input:       "\\db",
+			expectedCmd: CmdListTablespaces,
+			expectError: false,
+		},
+		{
+			name:        "parse \\db+ with pattern",
+			input:       "\\db+ pg_*",
+			expectedCmd: CmdListTablespaces,
+			expectError: false,
+		},
+
+		// Server settings
+		{
+			name:        "parse \\dconfig",
+			input:       "\\dconfig",
+			expectedCmd: CmdListConfig,
+			expectError: false,
+		},
+		{
+			name:        "parse \\dconfig+ with pattern",
+			input:       "\\dconfig+ work_mem",
+			expectedCmd: CmdListConfig,
+			expectError: false,
+		},
+
+		// Domains
+		{
+			name:        "parse \\dD",
+			input:       "\\dD",
+			expectedCmd: CmdListDomains,
+			expectError: false,
+		},
+		{
+			name:        "parse \\dD+ with pattern",
+			input:       "\\dD+ public.*",
+			expectedCmd: CmdListDomains,
+			expectError: false,
+		},
+
+		// Foreign servers
+		{
+			name:        "parse \\des+",
+			input:       "\\des+",
+			expectedCmd: CmdListForeignServers,
+			expectError: false,
+		},
+		{
+			name:        "parse \\det",
+			input:       "\\det",
+			expectedCmd: CmdListForeignTablesByServer,
+			expectError: false,
+		},
+
+		// Logical replication
+		{
+			name:        "parse \\dRp",
+			input:       "\\dRp",
+			expectedCmd: CmdListPublications,
+			expectError: false,
+		},
+		{
+			name:        "parse \\dRs",
+			input:       "\\dRs",
+			expectedCmd: CmdListSubscriptions,
+			expectError: false,
+		},
+
 		// Privileges
 		{
 			name:        "parse \\dp",
@@ -70,6 +154,20 @@ func TestParseNewCommands(t *testing.T) {
 			expectedCmd: CmdToggleExpanded,
 			expectError: false,
 		},
+
+		// Output redirection
+		{
+			name:        "parse \\o with a file",
+			input:       "\\o results.csv",
+			expectedCmd: CmdOutput,
+			expectError: false,
+		},
+		{
+			name:        "parse \\o without a file",
+			input:       "\\o",
+			expectedCmd: CmdOutput,
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -131,6 +229,36 @@ func TestIsExtended(t *testing.T) {
 			raw:      "\\dm+;",
 			expected: true,
 		},
+		{
+			name:     "\\dD with plus and pattern",
+			raw:      "\\dD+ mydomain",
+			expected: true,
+		},
+		{
+			name:     "\\dt with plus and pattern",
+			raw:      "\\dt+ users*",
+			expected: true,
+		},
+		{
+			name:     "\\dconfig with plus and pattern",
+			raw:      "\\dconfig+ work_mem",
+			expected: true,
+		},
+		{
+			name:     "\\db with plus, pattern and semicolon",
+			raw:      "\\db+ pg_*;",
+			expected: true,
+		},
+		{
+			name:     "\\dt with pattern without plus",
+			raw:      "\\dt users*",
+			expected: false,
+		},
+		{
+			name:     "pattern ending with plus",
+			raw:      "\\dt users+",
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,6 +282,8 @@ func TestCommandDescriptions(t *testing.T) {
 		PSQL_ListMaterializedViewsPlus: false,
 		PSQL_ListExtensions:            false,
 		PSQL_ListExtensionsPlus:        false,
+		PSQL_ListTypes:                 false,
+		PSQL_ListTypesPlus:             false,
 		PSQL_ListPrivileges:            false,
 		PSQL_ListPrivilegesAlt:         false,
 		PSQL_ConnInfo:                  false,
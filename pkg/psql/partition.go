@@ -0,0 +1,156 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+)
+
+// partitionInfo describes the partitioning of a table for \d
+type partitionInfo struct {
+	// partitioned tables only
+	key        string // e.g. "RANGE (created_at)"
+	partitions []partition
+
+	// partitions only
+	parent string
+	bound  string // e.g. "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')"
+}
+
+// partition is a partition of a partitioned table
+type partition struct {
+	name        string
+	bound       string
+	partitioned bool // it is partitioned in turn
+}
+
+// getPartitionInfo returns the partition key and partitions of a partitioned
+// table, the parent and bound of a partition, both for a partition that is
+// partitioned in turn, and nil for other relations
+func (e *executor) getPartitionInfo(ctx context.Context, name string) (*partitionInfo, error) {
+	query := `
+		SELECT
+			COALESCE(pg_catalog.pg_get_partkeydef(c.oid), ''),
+			COALESCE((
+				SELECT i.inhparent::regclass::text
+				FROM pg_catalog.pg_inherits i
+				WHERE i.inhrelid = c.oid
+				AND c.relispartition
+			), ''),
+			COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid, true), '')
+		FROM pg_catalog.pg_class c
+		WHERE c.oid = $1::regclass
+		AND (c.relkind = 'p' OR c.relispartition);`
+
+	result, err := e.db.Query(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	var info partitionInfo
+	if err := rows.Scan(&info.key, &info.parent, &info.bound); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if info.key == "" {
+		return &info, rows.Err()
+	}
+
+	info.partitions, err = e.getPartitions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// getPartitions returns the partitions of a partitioned table, the default
+// partition last
+func (e *executor) getPartitions(ctx context.Context, name string) ([]partition, error) {
+	query := `
+		SELECT
+			c.oid::regclass::text,
+			COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid, true), ''),
+			c.relkind = 'p'
+		FROM pg_catalog.pg_inherits i
+		JOIN pg_catalog.pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass
+		ORDER BY pg_catalog.pg_get_expr(c.relpartbound, c.oid) = 'DEFAULT', c.oid::regclass::text;`
+
+	result, err := e.db.Query(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var partitions []partition
+	for rows.Next() {
+		var p partition
+		if err := rows.Scan(&p.name, &p.bound, &p.partitioned); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, p)
+	}
+
+	return partitions, rows.Err()
+}
+
+// title names the table like psql, e.g. Partitioned table "public.events"
+func (info *partitionInfo) title(name string) string {
+	if info.key != "" {
+		return fmt.Sprintf("Partitioned table \"%s\"", name)
+	}
+	return fmt.Sprintf("Table \"%s\"", name)
+}
+
+// rows lists the parent and bound of a partition, then the partition key and
+// the partitions of a partitioned table, following the columns of \d
+func (info *partitionInfo) rows() []map[string]any {
+	var rows []map[string]any
+
+	add := func(column, value string) {
+		rows = append(rows, map[string]any{
+			"Column":    column,
+			"Type":      value,
+			"Modifiers": "",
+			"Default":   "",
+		})
+	}
+
+	if info.parent != "" {
+		add("", "Partition of:")
+		add("    "+info.parent, info.bound)
+	}
+
+	if info.key == "" {
+		return rows
+	}
+
+	add("", "Partition key:")
+	add("", "    "+info.key)
+
+	if len(info.partitions) == 0 {
+		add("", "Partitions: none")
+		return rows
+	}
+
+	add("", "Partitions:")
+	for _, p := range info.partitions {
+		bound := p.bound
+		if p.partitioned {
+			bound += ", PARTITIONED"
+		}
+		add("    "+p.name, bound)
+	}
+
+	return rows
+}
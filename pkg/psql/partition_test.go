@@ -0,0 +1,53 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionInfoRows(t *testing.T) {
+	t.Parallel()
+
+	partitioned := &partitionInfo{
+		key: "RANGE (created_at)",
+		partitions: []partition{
+			{name: "events_2024", bound: "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')"},
+			{name: "events_2025", bound: "FOR VALUES FROM ('2025-01-01') TO ('2026-01-01')", partitioned: true},
+			{name: "events_default", bound: "DEFAULT"},
+		},
+	}
+
+	assert.Equal(t, `Partitioned table "events"`, partitioned.title("events"))
+
+	rows := partitioned.rows()
+	require.Len(t, rows, 6)
+	assert.Equal(t, "Partition key:", rows[0]["Type"])
+	assert.Equal(t, "    RANGE (created_at)", rows[1]["Type"])
+	assert.Equal(t, "Partitions:", rows[2]["Type"])
+	assert.Equal(t, map[string]any{"Column": "    events_2024", "Type": "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", "Modifiers": "", "Default": ""}, rows[3])
+	assert.Equal(t, "FOR VALUES FROM ('2025-01-01') TO ('2026-01-01'), PARTITIONED", rows[4]["Type"])
+	assert.Equal(t, "DEFAULT", rows[5]["Type"])
+
+	empty := &partitionInfo{key: "LIST (region)"}
+
+	rows = empty.rows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Partitions: none", rows[2]["Type"])
+
+	leaf := &partitionInfo{parent: "events", bound: "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')"}
+
+	assert.Equal(t, `Table "events_2024"`, leaf.title("events_2024"))
+
+	rows = leaf.rows()
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Partition of:", rows[0]["Type"])
+	assert.Equal(t, "    events", rows[1]["Column"])
+	assert.Equal(t, "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", rows[1]["Type"])
+
+	nested := &partitionInfo{parent: "events", bound: "FOR VALUES IN ('eu')", key: "HASH (id)"}
+
+	assert.Equal(t, `Partitioned table "events_eu"`, nested.title("events_eu"))
+	assert.Len(t, nested.rows(), 5)
+}
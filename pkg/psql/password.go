@@ -0,0 +1,163 @@
+package psql
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// PSQL_Password changes the password of a role
+const PSQL_Password = "\\password"
+
+const (
+	scramIterations = 4096
+	scramSaltLength = 16
+)
+
+// Password encryption methods, as set in password_encryption
+const (
+	PasswordSCRAM = "scram-sha-256"
+	PasswordMD5   = "md5"
+)
+
+// PasswordRole returns the role named by \password, or an empty string when
+// none is given. Like psql, an unquoted name is lower-cased and a name quoted
+// with double quotes is kept as written.
+func PasswordRole(cmd *Command) string {
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	args = strings.TrimSpace(strings.TrimPrefix(args, PSQL_Password))
+
+	if len(args) >= 2 && args[0] == '"' && args[len(args)-1] == '"' {
+		return strings.ReplaceAll(args[1:len(args)-1], `""`, `"`)
+	}
+
+	return strings.ToLower(args)
+}
+
+// AlterPasswordSQL returns the statement setting the password of role. The
+// password is sent encrypted with method, as psql does, so the clear text
+// never reaches the server or its logs.
+func AlterPasswordSQL(role, password, method string) (string, error) {
+	if role == "" {
+		return "", fmt.Errorf("\\password: no role to change the password for")
+	}
+
+	var verifier string
+	var err error
+
+	switch method {
+	case PasswordSCRAM:
+		verifier, err = EncryptPassword(password)
+	case PasswordMD5:
+		verifier = EncryptPasswordMD5(password, role)
+	default:
+		return "", fmt.Errorf("\\password: unsupported password_encryption %q", method)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "ALTER ROLE " + quoteIdent(role) + " PASSWORD " + QuoteLiteral(verifier), nil
+}
+
+// PasswordEncryption returns the password_encryption setting of the server,
+// used by psql to pick how \password encrypts the new password. Servers
+// before PostgreSQL 10 report on or off, which both mean md5.
+func PasswordEncryption(ctx context.Context, database db.Database) (string, error) {
+	result, err := database.Query(ctx, "SHOW password_encryption")
+	if err != nil {
+		return "", fmt.Errorf("failed to read password_encryption: %w", err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var method string
+	if rows.Next() {
+		if err := rows.Scan(&method); err != nil {
+			return "", fmt.Errorf("failed to read password_encryption: %w", err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read password_encryption: %w", err)
+	}
+
+	switch method {
+	case "on", "off":
+		return PasswordMD5, nil
+	}
+
+	return method, nil
+}
+
+// EncryptPassword returns the SCRAM-SHA-256 verifier stored by the server for
+// password, in the SCRAM-SHA-256$iterations:salt$StoredKey:ServerKey format
+func EncryptPassword(password string) (string, error) {
+	salt := make([]byte, scramSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return scramVerifier(password, salt, scramIterations)
+}
+
+// EncryptPasswordMD5 returns the md5 verifier stored by the server for the
+// password of role: md5 followed by the hex MD5 of the password and role
+func EncryptPasswordMD5(password, role string) string {
+	sum := md5.Sum([]byte(password + role))
+	return "md5" + hex.EncodeToString(sum[:])
+}
+
+func scramVerifier(password string, salt []byte, iterations int) (string, error) {
+	salted, err := pbkdf2.Key(sha256.New, password, salt, iterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt password: %w", err)
+	}
+
+	clientKey := scramHMAC(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	serverKey := scramHMAC(salted, "Server Key")
+
+	encode := base64.StdEncoding.EncodeToString
+
+	return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s",
+		iterations,
+		encode(salt),
+		encode(storedKey[:]),
+		encode(serverKey),
+	), nil
+}
+
+func scramHMAC(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// quoteIdent quotes name as an SQL identifier, doubling embedded double quotes
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteLiteral quotes value as an SQL string literal, doubling embedded
+// quotes. With backslashes, an escape string literal is used and they are
+// doubled so they stay literal whatever standard_conforming_strings is set to.
+func QuoteLiteral(value string) string {
+	quoted := "'" + strings.ReplaceAll(value, "'", "''") + "'"
+
+	if strings.Contains(value, `\`) {
+		return "E" + strings.ReplaceAll(quoted, `\`, `\\`)
+	}
+
+	return quoted
+}
@@ -0,0 +1,89 @@
+package psql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "\\password", expected: ""},
+		{input: "\\password;", expected: ""},
+		{input: "\\password App_User", expected: "app_user"},
+		{input: "\\password \"App User\";", expected: "App User"},
+		{input: "\\password \"say \"\"hi\"\"\"", expected: "say \"hi\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdPassword, cmd.Type)
+			assert.Equal(t, tt.expected, PasswordRole(cmd))
+		})
+	}
+}
+
+func TestAlterPasswordSQL(t *testing.T) {
+	t.Parallel()
+
+	sql, err := AlterPasswordSQL(`app "user"`, "it's secret", PasswordSCRAM)
+	require.NoError(t, err)
+
+	prefix := `ALTER ROLE "app ""user""" PASSWORD 'SCRAM-SHA-256$4096:`
+	assert.True(t, strings.HasPrefix(sql, prefix), sql)
+	assert.NotContains(t, sql, "secret", "the clear text password is not sent")
+
+	sql, err = AlterPasswordSQL("postgres", "secret", PasswordMD5)
+	require.NoError(t, err)
+	assert.Equal(t, `ALTER ROLE "postgres" PASSWORD 'md553f48b7c4b76a86ce72276c5755f217d'`, sql)
+
+	_, err = AlterPasswordSQL("", "secret", PasswordSCRAM)
+	require.Error(t, err)
+
+	_, err = AlterPasswordSQL("postgres", "secret", "plain")
+	require.Error(t, err)
+}
+
+func TestScramVerifier(t *testing.T) {
+	t.Parallel()
+
+	verifier, err := scramVerifier("pencil", []byte("0123456789abcdef"), 4096)
+	require.NoError(t, err)
+
+	parts := strings.Split(verifier, "$")
+	require.Len(t, parts, 3)
+	assert.Equal(t, "SCRAM-SHA-256", parts[0])
+	assert.Equal(t, "4096:MDEyMzQ1Njc4OWFiY2RlZg==", parts[1])
+
+	keys := strings.Split(parts[2], ":")
+	require.Len(t, keys, 2)
+	assert.Len(t, keys[0], 44)
+	assert.Len(t, keys[1], 44)
+
+	again, err := scramVerifier("pencil", []byte("0123456789abcdef"), 4096)
+	require.NoError(t, err)
+	assert.Equal(t, verifier, again)
+
+	other, err := EncryptPassword("pencil")
+	require.NoError(t, err)
+	assert.NotEqual(t, verifier, other, "a random salt is used")
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "'plain'", QuoteLiteral("plain"))
+	assert.Equal(t, "'it''s'", QuoteLiteral("it's"))
+	assert.Equal(t, `E'a\\b''c'`, QuoteLiteral(`a\b'c`))
+}
@@ -0,0 +1,222 @@
+package psql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PSQL_Pset shows or changes the options used to display results
+const PSQL_Pset = "\\pset"
+
+// Display options changed with \pset
+const (
+	PsetBorder   = "border"
+	PsetNull     = "null"
+	PsetExpanded = "expanded"
+	PsetFooter   = "footer"
+	PsetPager    = "pager"
+)
+
+// PsetOptions lists the display options in the order they are shown
+var PsetOptions = []string{PsetBorder, PsetNull, PsetExpanded, PsetFooter, PsetPager}
+
+// Pager usage set with \pset pager
+const (
+	PagerOn     = "on"
+	PagerOff    = "off"
+	PagerAlways = "always"
+)
+
+// ExpandedAuto is the value of \pset expanded, or \x, choosing the expanded
+// display for each result that is wider than the screen
+const ExpandedAuto = "auto"
+
+// DisplayOptions control how results are shown in the table
+type DisplayOptions struct {
+	Border     int    // 0 dims the table lines, 1 is the theme default and 2 makes them bold
+	Null       string // text shown for NULL values
+	Expanded   bool
+	AutoExpand bool   // expand only the results wider than the screen, instead of Expanded
+	Footer     bool   // show the number of rows below the table
+	Pager      string // on, off or always
+}
+
+// DefaultDisplayOptions returns the options used when none are set, matching psql
+func DefaultDisplayOptions() DisplayOptions {
+	return DisplayOptions{
+		Border:     1,
+		Null:       "NULL",
+		Expanded:   false,
+		AutoExpand: false,
+		Footer:     true,
+		Pager:      PagerOn,
+	}
+}
+
+// LoadDisplayOptions builds the options from their stored values. Empty values
+// keep the defaults and invalid ones are dropped and reported with the error.
+func LoadDisplayOptions(get func(option string) string) (DisplayOptions, error) {
+	options := DefaultDisplayOptions()
+
+	var errs []error
+	for _, option := range PsetOptions {
+		value := get(option)
+		if value == "" {
+			continue
+		}
+
+		if _, err := options.Set(option, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return options, errors.Join(errs...)
+}
+
+// ParsePset returns the option and value of a \pset command. The value may be
+// quoted with single quotes to keep its spaces, e.g. \pset null '(no value)'.
+func ParsePset(cmd *Command) (option, value string) {
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	args = strings.TrimSpace(strings.TrimPrefix(args, PSQL_Pset))
+
+	option, value, _ = strings.Cut(args, " ")
+	value = strings.TrimSpace(value)
+
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	return strings.ToLower(option), value
+}
+
+// Set changes option to value and returns the value to store. Like psql, an
+// empty value toggles expanded and footer, and expanded also accepts auto.
+func (o *DisplayOptions) Set(option, value string) (string, error) {
+	switch option {
+	case PsetBorder:
+		border, err := strconv.Atoi(value)
+		if err != nil || border < 0 || border > 2 {
+			return "", fmt.Errorf("\\pset: border must be 0, 1 or 2")
+		}
+		o.Border = border
+
+	case PsetNull:
+		o.Null = value
+
+	case PsetExpanded:
+		if strings.EqualFold(value, ExpandedAuto) {
+			o.Expanded, o.AutoExpand = false, true
+			break
+		}
+
+		// like psql, toggling from auto turns the expanded display on
+		expanded, err := parseToggle(value, o.Expanded && !o.AutoExpand)
+		if err != nil {
+			return "", fmt.Errorf("\\pset: expanded must be on, off or auto")
+		}
+		o.Expanded, o.AutoExpand = expanded, false
+
+	case PsetFooter:
+		footer, err := parseToggle(value, o.Footer)
+		if err != nil {
+			return "", fmt.Errorf("\\pset: footer %w", err)
+		}
+		o.Footer = footer
+
+	case PsetPager:
+		switch strings.ToLower(value) {
+		case PagerOn, PagerOff, PagerAlways:
+			o.Pager = strings.ToLower(value)
+		case "":
+			if o.Pager == PagerOff {
+				o.Pager = PagerOn
+			} else {
+				o.Pager = PagerOff
+			}
+		default:
+			return "", fmt.Errorf("\\pset: pager must be on, off or always")
+		}
+
+	default:
+		return "", fmt.Errorf("\\pset: unknown option %q, expected one of %s", option, strings.Join(PsetOptions, ", "))
+	}
+
+	return o.Get(option), nil
+}
+
+// Get returns the value of option as it is stored
+func (o DisplayOptions) Get(option string) string {
+	switch option {
+	case PsetBorder:
+		return strconv.Itoa(o.Border)
+	case PsetNull:
+		return o.Null
+	case PsetExpanded:
+		if o.AutoExpand {
+			return ExpandedAuto
+		}
+		return toggleValue(o.Expanded)
+	case PsetFooter:
+		return toggleValue(o.Footer)
+	case PsetPager:
+		return o.Pager
+	}
+
+	return ""
+}
+
+// Describe returns the message psql prints after option is changed
+func (o DisplayOptions) Describe(option string) string {
+	switch option {
+	case PsetBorder:
+		return fmt.Sprintf("Border style is %d", o.Border)
+	case PsetNull:
+		return fmt.Sprintf("Null display is %q", o.Null)
+	case PsetExpanded:
+		if o.AutoExpand {
+			return "Expanded display is used automatically"
+		}
+		return fmt.Sprintf("Expanded display is %s", toggleValue(o.Expanded))
+	case PsetFooter:
+		return fmt.Sprintf("Default footer is %s", toggleValue(o.Footer))
+	case PsetPager:
+		if o.Pager == PagerAlways {
+			return "Pager is always used"
+		}
+		return fmt.Sprintf("Pager usage is %s", o.Pager)
+	}
+
+	return ""
+}
+
+// Result returns the options as a psql result, shown by \pset without arguments
+func (o DisplayOptions) Result() *Result {
+	rows := make([]map[string]any, 0, len(PsetOptions))
+	for _, option := range PsetOptions {
+		rows = append(rows, map[string]any{"Option": option, "Value": o.Get(option)})
+	}
+
+	return &Result{Columns: []string{"Option", "Value"}, Rows: rows}
+}
+
+func parseToggle(value string, current bool) (bool, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return !current, nil
+	case "on", "true", "1":
+		return true, nil
+	case "off", "false", "0":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("must be on or off")
+}
+
+func toggleValue(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
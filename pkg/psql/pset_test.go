@@ -0,0 +1,100 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input  string
+		option string
+		value  string
+	}{
+		{input: "\\pset", option: "", value: ""},
+		{input: "\\pset border 2", option: PsetBorder, value: "2"},
+		{input: "\\pset NULL '(no value)';", option: PsetNull, value: "(no value)"},
+		{input: "\\pset null 'it''s null'", option: PsetNull, value: "it's null"},
+		{input: "\\pset footer", option: PsetFooter, value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdPset, cmd.Type)
+
+			option, value := ParsePset(cmd)
+			assert.Equal(t, tt.option, option)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestDisplayOptionsSet(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultDisplayOptions()
+
+	value, err := options.Set(PsetBorder, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "2", value)
+	assert.Equal(t, 2, options.Border)
+
+	value, err = options.Set(PsetFooter, "")
+	require.NoError(t, err)
+	assert.Equal(t, "off", value, "an empty value toggles")
+	assert.Equal(t, "Default footer is off", options.Describe(PsetFooter))
+
+	value, err = options.Set(PsetPager, "Always")
+	require.NoError(t, err)
+	assert.Equal(t, PagerAlways, value)
+
+	value, err = options.Set(PsetExpanded, "AUTO")
+	require.NoError(t, err)
+	assert.Equal(t, ExpandedAuto, value)
+	assert.True(t, options.AutoExpand)
+	assert.Equal(t, "Expanded display is used automatically", options.Describe(PsetExpanded))
+
+	value, err = options.Set(PsetExpanded, "")
+	require.NoError(t, err)
+	assert.Equal(t, "on", value, "toggling from auto turns it on")
+	assert.False(t, options.AutoExpand)
+
+	_, err = options.Set(PsetBorder, "3")
+	require.Error(t, err)
+	assert.Equal(t, 2, options.Border, "invalid values are not applied")
+
+	_, err = options.Set("format", "csv")
+	require.Error(t, err)
+}
+
+func TestLoadDisplayOptions(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]string{
+		PsetNull:     "(null)",
+		PsetExpanded: "on",
+		PsetBorder:   "wide",
+	}
+
+	options, err := LoadDisplayOptions(func(option string) string {
+		return stored[option]
+	})
+	require.Error(t, err)
+
+	expected := DefaultDisplayOptions()
+	expected.Null = "(null)"
+	expected.Expanded = true
+	assert.Equal(t, expected, options)
+
+	result := options.Result()
+	assert.Equal(t, []string{"Option", "Value"}, result.Columns)
+	assert.Len(t, result.Rows, len(PsetOptions))
+}
@@ -0,0 +1,105 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// PSQL_Sample shows a random subset of the rows of a table
+const PSQL_Sample = "\\sample"
+
+// Sampling methods of TABLESAMPLE
+const (
+	SampleSystem    = "system"    // random pages, the fastest
+	SampleBernoulli = "bernoulli" // random rows, reads the whole table
+)
+
+const defaultSamplePercent = 1.0
+
+// SampleCommand is a parsed \sample command
+type SampleCommand struct {
+	Table   string
+	Percent float64 // share of the rows to return, from 0 to 100
+	Method  string
+}
+
+// ParseSample parses \sample table [percent[%]] [system|bernoulli]. The
+// percent defaults to 1 and the method to system.
+func ParseSample(cmd *Command) (*SampleCommand, error) {
+	if len(cmd.Arguments) == 0 {
+		return nil, fmt.Errorf("\\sample requires a table name")
+	}
+
+	table, err := SanitiseIdentifier(cmd.Arguments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SampleCommand{Table: table, Percent: defaultSamplePercent, Method: SampleSystem}
+
+	for _, arg := range cmd.Arguments[1:] {
+		switch method := strings.ToLower(arg); method {
+		case SampleSystem, SampleBernoulli:
+			s.Method = method
+			continue
+		}
+
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("\\sample: expected a percent between 0 and 100 or a method, got %q", arg)
+		}
+		s.Percent = percent
+	}
+
+	return s, nil
+}
+
+// SQL returns the sampling query. TABLESAMPLE only applies to tables and
+// materialized views, so other relations keep each row with a random() filter.
+func (s SampleCommand) SQL(tablesample bool) string {
+	if tablesample {
+		return fmt.Sprintf("SELECT * FROM %s TABLESAMPLE %s (%s)",
+			s.Table, strings.ToUpper(s.Method), strconv.FormatFloat(s.Percent, 'f', -1, 64))
+	}
+
+	return fmt.Sprintf("SELECT * FROM %s WHERE random() < %s",
+		s.Table, strconv.FormatFloat(s.Percent/100, 'f', -1, 64))
+}
+
+// SampleSQL returns the sampling query for the table, using TABLESAMPLE when
+// the kind of the relation supports it
+func SampleSQL(ctx context.Context, database db.Database, s *SampleCommand) (string, error) {
+	result, err := database.Query(ctx, `
+		SELECT c.relkind::text
+		FROM pg_catalog.pg_class c
+		WHERE c.oid = pg_catalog.to_regclass($1)`, s.Table)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", s.Table, err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("failed to look up %s: %w", s.Table, err)
+		}
+		return "", fmt.Errorf("relation %q does not exist", s.Table)
+	}
+
+	var kind string
+	if err := rows.Scan(&kind); err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", s.Table, err)
+	}
+
+	switch kind {
+	case "r", "m", "p":
+		return s.SQL(true), nil
+	}
+
+	return s.SQL(false), nil
+}
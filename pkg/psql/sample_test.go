@@ -0,0 +1,78 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSample(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input       string
+		expected    *SampleCommand
+		tablesample string
+		random      string
+	}{
+		{
+			input:       "\\sample users",
+			expected:    &SampleCommand{Table: "users", Percent: 1, Method: SampleSystem},
+			tablesample: "SELECT * FROM users TABLESAMPLE SYSTEM (1)",
+			random:      "SELECT * FROM users WHERE random() < 0.01",
+		},
+		{
+			input:       "\\sample public.orders 2.5% bernoulli;",
+			expected:    &SampleCommand{Table: "public.orders", Percent: 2.5, Method: SampleBernoulli},
+			tablesample: "SELECT * FROM public.orders TABLESAMPLE BERNOULLI (2.5)",
+			random:      "SELECT * FROM public.orders WHERE random() < 0.025",
+		},
+		{
+			input:       "\\sample events SYSTEM 10",
+			expected:    &SampleCommand{Table: "events", Percent: 10, Method: SampleSystem},
+			tablesample: "SELECT * FROM events TABLESAMPLE SYSTEM (10)",
+			random:      "SELECT * FROM events WHERE random() < 0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdSample, cmd.Type)
+
+			sample, err := ParseSample(cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sample)
+			assert.Equal(t, tt.tablesample, sample.SQL(true))
+			assert.Equal(t, tt.random, sample.SQL(false))
+		})
+	}
+}
+
+func TestParseSampleErrors(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"\\sample",
+		"\\sample users; DROP TABLE users",
+		"\\sample users 0%",
+		"\\sample users 150",
+		"\\sample users half",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(input)
+			require.NoError(t, err)
+
+			_, err = ParseSample(cmd)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// describeSequence implements \d sequence_name, with the parameters of the
+// sequence, its current value and the column owning it
+func (e *executor) describeSequence(ctx context.Context, safeName, name string) (*Result, error) {
+	query := `
+		SELECT
+			pg_catalog.format_type(s.seqtypid, NULL) AS "Type",
+			s.seqstart AS "Start",
+			s.seqmin AS "Minimum",
+			s.seqmax AS "Maximum",
+			s.seqincrement AS "Increment",
+			CASE WHEN s.seqcycle THEN 'yes' ELSE 'no' END AS "Cycles?",
+			s.seqcache AS "Cache",
+			CASE
+				WHEN pg_catalog.has_sequence_privilege(c.oid, 'SELECT,USAGE')
+				THEN pg_catalog.pg_sequence_last_value(c.oid)
+			END AS "Current value",
+			COALESCE((
+				SELECT d.refobjid::regclass::text || '.' || pg_catalog.quote_ident(a.attname)
+				FROM pg_catalog.pg_depend d
+				JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+				WHERE d.classid = 'pg_catalog.pg_class'::regclass
+				AND d.refclassid = 'pg_catalog.pg_class'::regclass
+				AND d.objid = c.oid
+				AND d.deptype IN ('a', 'i')
+				LIMIT 1
+			), '') AS "Owned by"
+		FROM pg_catalog.pg_sequence s
+		JOIN pg_catalog.pg_class c ON c.oid = s.seqrelid
+		WHERE c.oid = $1::regclass;`
+
+	result, err := e.db.Query(ctx, query, safeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe sequence: %w", err)
+	}
+
+	rows, columns, err := db.ExtractPsqlResults(result.Rows())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Columns: columns,
+		Rows:    rows,
+		Message: fmt.Sprintf("Sequence \"%s\"", name),
+	}, nil
+}
@@ -0,0 +1,136 @@
+package psql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Commands setting the variables referenced by statements
+const (
+	PSQL_Set   = "\\set"
+	PSQL_Unset = "\\unset"
+)
+
+// FetchCountVariable is the variable setting how many rows of a query are
+// fetched at a time, as in psql
+const FetchCountVariable = "FETCH_COUNT"
+
+// OnErrorStopVariable is the variable stopping the statements run by \i and
+// \gexec at the first failure, as in psql
+const OnErrorStopVariable = "ON_ERROR_STOP"
+
+// Variables are the variables set with \set, referenced by the following
+// statements and meta-commands as :name, :'name' or :"name"
+type Variables map[string]string
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseSet returns the name and value of \set name [value ...]. Like psql,
+// the values are joined without spaces and quoting them with single quotes
+// keeps their spaces, e.g. \set greeting 'hello world'. Without arguments,
+// the name is empty.
+func ParseSet(cmd *Command) (name, value string, err error) {
+	if len(cmd.Arguments) == 0 {
+		return "", "", nil
+	}
+
+	name = cmd.Arguments[0]
+	if !variableNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("\\set: invalid variable name: %q", name)
+	}
+
+	args := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(cmd.Raw), ";"))
+	args = strings.TrimSpace(strings.TrimPrefix(args, PSQL_Set))
+	args = strings.TrimSpace(strings.TrimPrefix(args, name))
+
+	return name, joinArguments(args, ""), nil
+}
+
+// ParseUnset returns the name of the variable removed by \unset
+func ParseUnset(cmd *Command) (string, error) {
+	if len(cmd.Arguments) == 0 {
+		return "", fmt.Errorf("\\unset: missing required argument")
+	}
+
+	name := cmd.Arguments[0]
+	if !variableNamePattern.MatchString(name) {
+		return "", fmt.Errorf("\\unset: invalid variable name: %q", name)
+	}
+
+	return name, nil
+}
+
+// FetchCount returns the number of rows to fetch at a time set with
+// \set FETCH_COUNT, or 0 to fetch all the rows at once
+func (v Variables) FetchCount() int {
+	count, err := strconv.Atoi(strings.TrimSpace(v[FetchCountVariable]))
+	if err != nil || count < 0 {
+		return 0
+	}
+
+	return count
+}
+
+// OnErrorStop reports whether \set ON_ERROR_STOP on stops the statements run
+// by \i and \gexec at the first failure. Unset or empty, it is off.
+func (v Variables) OnErrorStop() bool {
+	value := strings.TrimSpace(v[OnErrorStopVariable])
+	if value == "" {
+		return false
+	}
+
+	on, err := parseToggle(value, false)
+	return err == nil && on
+}
+
+// Result lists the variables by name, as \set without arguments does
+func (v Variables) Result() *Result {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &Result{
+		Columns: []string{"Name", "Value"},
+		Rows:    make([]map[string]any, len(names)),
+		Message: "List of variables",
+	}
+
+	for i, name := range names {
+		result.Rows[i] = map[string]any{"Name": name, "Value": v[name]}
+	}
+
+	return result
+}
+
+// joinArguments joins the words of text with sep. Words quoted with single
+// quotes keep their spaces and a doubled quote stands for a quote.
+func joinArguments(text, sep string) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	quoted := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == '\'' && quoted && i+1 < len(text) && text[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && isSpace(c):
+			if sb.Len() > 0 && i > 0 && !isSpace(text[i-1]) {
+				sb.WriteString(sep)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input       string
+		name        string
+		value       string
+		expectError bool
+	}{
+		{input: "\\set", name: "", value: ""},
+		{input: "\\set id 42", name: "id", value: "42"},
+		{input: "\\set id", name: "id", value: ""},
+		{input: "\\set greeting 'hello  world'", name: "greeting", value: "hello  world"},
+		{input: "\\set name 'it''s' here;", name: "name", value: "it'shere"},
+		{input: "\\set 1id 42", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, CmdSet, cmd.Type)
+
+			name, value, err := ParseSet(cmd)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestParseUnset(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := Parse("\\unset id")
+	require.NoError(t, err)
+	assert.Equal(t, CmdUnset, cmd.Type)
+
+	name, err := ParseUnset(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "id", name)
+
+	cmd, err = Parse("\\unset")
+	require.NoError(t, err)
+	_, err = ParseUnset(cmd)
+	require.Error(t, err)
+}
+
+func TestVariablesResult(t *testing.T) {
+	t.Parallel()
+
+	result := Variables{"limit": "10", "id": "42"}.Result()
+	assert.Equal(t, []string{"Name", "Value"}, result.Columns)
+	assert.Equal(t, []map[string]any{
+		{"Name": "id", "Value": "42"},
+		{"Name": "limit", "Value": "10"},
+	}, result.Rows)
+}
+
+func TestVariablesFetchCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 0},
+		{"1000", 1000},
+		{" 50 ", 50},
+		{"0", 0},
+		{"-1", 0},
+		{"many", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			variables := Variables{}
+			if tt.value != "" {
+				variables[FetchCountVariable] = tt.value
+			}
+
+			assert.Equal(t, tt.want, variables.FetchCount())
+		})
+	}
+}
+
+func TestVariablesOnErrorStop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"on", true},
+		{"ON", true},
+		{"true", true},
+		{"1", true},
+		{"off", false},
+		{"0", false},
+		{"maybe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			variables := Variables{}
+			if tt.value != "" {
+				variables[OnErrorStopVariable] = tt.value
+			}
+
+			assert.Equal(t, tt.want, variables.OnErrorStop())
+		})
+	}
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	t.Parallel()
+
+	variables := Variables{"id": "42", "name": "O'Brien"}
+	assert.Equal(t,
+		"SELECT * FROM users WHERE id = 42 AND name = 'O''Brien' AND x::int = :missing",
+		Interpolate("SELECT * FROM users WHERE id = :id AND name = :'name' AND x::int = :missing", variables))
+	assert.Equal(t, "\\echo 42", Interpolate("\\echo :id", variables))
+}
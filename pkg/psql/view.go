@@ -0,0 +1,124 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// materializedViewRefreshColumns are the columns of \dm telling whether a
+// materialized view is populated, its size and when it was last refreshed.
+// PostgreSQL doesn't record refreshes: the last change of the file holding
+// the rows stands for it, and is only readable with access to pg_stat_file.
+const materializedViewRefreshColumns = `CASE WHEN c.relispopulated THEN 'yes' ELSE 'no' END as "Populated",
+			pg_catalog.pg_size_pretty(pg_catalog.pg_total_relation_size(c.oid)) as "Size",
+			CASE
+				WHEN c.relispopulated
+				AND pg_catalog.has_function_privilege('pg_catalog.pg_stat_file(text, boolean)', 'EXECUTE')
+				THEN pg_catalog.to_char(
+					(pg_catalog.pg_stat_file(pg_catalog.pg_relation_filepath(c.oid), true)).modification,
+					'YYYY-MM-DD HH24:MI:SS'
+				)
+			END as "Last refresh"`
+
+// viewInfo describes a view or materialized view for \d
+type viewInfo struct {
+	materialized bool
+	definition   string
+
+	// materialized views only
+	populated  bool
+	size       string
+	concurrent bool // it has a unique index REFRESH ... CONCURRENTLY can use
+}
+
+// getViewInfo returns the definition of the relation when it is a view or a
+// materialized view, and nil for other relations
+func (e *executor) getViewInfo(ctx context.Context, name string) (*viewInfo, error) {
+	query := `
+		SELECT
+			c.relkind = 'm',
+			pg_catalog.pg_get_viewdef(c.oid, true),
+			c.relispopulated,
+			pg_catalog.pg_size_pretty(pg_catalog.pg_total_relation_size(c.oid)),
+			EXISTS (
+				SELECT 1
+				FROM pg_catalog.pg_index i
+				WHERE i.indrelid = c.oid
+				AND i.indisunique
+				AND i.indisvalid
+				AND i.indpred IS NULL
+				AND i.indexprs IS NULL
+			)
+		FROM pg_catalog.pg_class c
+		WHERE c.oid = $1::regclass
+		AND c.relkind IN ('v', 'm');`
+
+	result, err := e.db.Query(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	var info viewInfo
+	if err := rows.Scan(&info.materialized, &info.definition, &info.populated, &info.size, &info.concurrent); err != nil {
+		return nil, err
+	}
+
+	return &info, rows.Err()
+}
+
+// title names the relation like psql, e.g. View "public.active_users"
+func (info *viewInfo) title(name string) string {
+	if info.materialized {
+		return fmt.Sprintf("Materialized view \"%s\"", name)
+	}
+	return fmt.Sprintf("View \"%s\"", name)
+}
+
+// rows lists the definition, one row per line, and the refresh status of
+// materialized views, following the columns of \d
+func (info *viewInfo) rows() []map[string]any {
+	var rows []map[string]any
+
+	add := func(column, value string) {
+		rows = append(rows, map[string]any{
+			"Column":    column,
+			"Type":      value,
+			"Modifiers": "",
+			"Default":   "",
+		})
+	}
+
+	add("", "View definition:")
+	for line := range strings.SplitSeq(strings.TrimRight(info.definition, "\n"), "\n") {
+		add("", "    "+strings.TrimRight(line, " "))
+	}
+
+	if !info.materialized {
+		return rows
+	}
+
+	populated := "yes"
+	if !info.populated {
+		populated = "no, REFRESH MATERIALIZED VIEW fills it"
+	}
+
+	concurrent := "yes"
+	if !info.concurrent {
+		concurrent = "no, it needs a unique index on plain columns"
+	}
+
+	add("", "Refresh status:")
+	add("    Populated", populated)
+	add("    Size", info.size)
+	add("    Concurrent refresh", concurrent)
+
+	return rows
+}
@@ -0,0 +1,33 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewInfoRows(t *testing.T) {
+	t.Parallel()
+
+	view := &viewInfo{definition: " SELECT id,\n    name\n   FROM users\n  WHERE active;"}
+
+	assert.Equal(t, `View "active_users"`, view.title("active_users"))
+
+	rows := view.rows()
+	require.Len(t, rows, 5)
+	assert.Equal(t, "View definition:", rows[0]["Type"])
+	assert.Equal(t, "     SELECT id,", rows[1]["Type"])
+	assert.Equal(t, "      WHERE active;", rows[4]["Type"])
+
+	matview := &viewInfo{materialized: true, definition: " SELECT 1;", size: "16 kB", concurrent: true}
+
+	assert.Equal(t, `Materialized view "public.totals"`, matview.title("public.totals"))
+
+	rows = matview.rows()
+	require.Len(t, rows, 6)
+	assert.Equal(t, "Refresh status:", rows[2]["Type"])
+	assert.Equal(t, map[string]any{"Column": "    Populated", "Type": "no, REFRESH MATERIALIZED VIEW fills it", "Modifiers": "", "Default": ""}, rows[3])
+	assert.Equal(t, "16 kB", rows[4]["Type"])
+	assert.Equal(t, "yes", rows[5]["Type"])
+}
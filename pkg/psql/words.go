@@ -0,0 +1,68 @@
+package psql
+
+import "strings"
+
+// MetaCommands returns the supported meta-commands, including the ones
+// ending a query like \gexec
+func MetaCommands() []string {
+	commands := make([]string, 0, len(PSQL_COMMANDS)+2)
+	for command := range PSQL_COMMANDS {
+		commands = append(commands, command)
+	}
+
+	return append(commands, PSQL_Gexec, PSQL_Gset)
+}
+
+// MetaCommandWords returns the meta-commands of content as written, in
+// order: the words starting with a backslash outside strings, quoted
+// identifiers, dollar quoted bodies and comments. The rest of the line after
+// a meta-command holds its arguments and is skipped.
+func MetaCommandWords(content string) []string {
+	var words []string
+
+	for i := 0; i < len(content); {
+		c := content[i]
+
+		switch {
+		case c == '\\':
+			end := i + 1
+			for end < len(content) && !isSpace(content[end]) {
+				end++
+			}
+
+			if end > i+1 {
+				words = append(words, content[i:end])
+			}
+
+			if n := strings.IndexByte(content[end:], '\n'); n != -1 {
+				i = end + n
+			} else {
+				i = len(content)
+			}
+
+		case c == '\'':
+			i = skipQuoted(content, i+1, '\'', isEscapeString(content, i))
+
+		case c == '"':
+			i = skipQuoted(content, i+1, '"', false)
+
+		case strings.HasPrefix(content[i:], "--"):
+			if n := strings.IndexByte(content[i:], '\n'); n != -1 {
+				i += n
+			} else {
+				i = len(content)
+			}
+
+		case strings.HasPrefix(content[i:], "/*"):
+			i = skipBlockComment(content, i+2)
+
+		case c == '$':
+			i = skipDollarQuote(content, i)
+
+		default:
+			i++
+		}
+	}
+
+	return words
+}
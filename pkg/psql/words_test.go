@@ -0,0 +1,44 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaCommands(t *testing.T) {
+	t.Parallel()
+
+	commands := MetaCommands()
+	for _, command := range []string{"\\dt", "\\dt+", "\\d", "\\gexec", "\\gset", "\\gx", "\\x"} {
+		assert.Contains(t, commands, command)
+	}
+	assert.NotContains(t, commands, "\\dtx")
+}
+
+func TestMetaCommandWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"command with arguments", "\\dt+ users", []string{"\\dt+"}},
+		{"arguments skipped", "\\echo \\not a command", []string{"\\echo"}},
+		{"ending a query", "SELECT 1 \\gx", []string{"\\gx"}},
+		{"one per line", "\\x on\nSELECT 1\n\\dtt", []string{"\\x", "\\dtt"}},
+		{"in strings", "SELECT '\\dt', E'\\n', \"\\a\" \\gexec", []string{"\\gexec"}},
+		{"in comments", "-- \\dt\n/* \\l */ SELECT 1", nil},
+		{"in dollar quotes", "SELECT $$ \\dt $$", nil},
+		{"alone", "SELECT 1 \\", nil},
+		{"sql", "SELECT 1", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, MetaCommandWords(tt.content))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package querybuilder writes simple SELECT statements from the choices made
+// in the query builder: a table, its columns, filters, an order and a limit.
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/columnpicker"
+)
+
+// Operators of the filters, longest first so that >= is not read as >
+var Operators = []string{
+	"is not null", "is null", "not ilike", "not like", "ilike", "like",
+	"not in", "in", "<>", "!=", ">=", "<=", "=", ">", "<",
+}
+
+// Filter is a condition on a column, e.g. status = active
+type Filter struct {
+	Column   string
+	Operator string
+	Value    string // unquoted; a comma separated list for in and not in
+}
+
+// Query is a SELECT of a single table
+type Query struct {
+	Table      string
+	Columns    []string // all the columns when empty
+	Filters    []Filter
+	OrderBy    string
+	Descending bool
+	Limit      int // no limit when 0
+}
+
+// ParseFilters reads one filter per line, written as column, operator and
+// value, e.g. "created_at >= 2024-01-01" or "deleted_at is null". Empty
+// lines are skipped.
+func ParseFilters(text string) ([]Filter, error) {
+	var filters []Filter
+
+	for line := range strings.SplitSeq(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		filter, err := parseFilter(line)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func parseFilter(line string) (Filter, error) {
+	column, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	// symbols can follow the column without a space, e.g. id>10
+	if i := strings.IndexAny(column, "<>=!"); i > 0 {
+		column, rest = column[:i], column[i:]+" "+rest
+	}
+
+	lower := strings.ToLower(rest)
+	for _, operator := range Operators {
+		if !strings.HasPrefix(lower, operator) {
+			continue
+		}
+
+		value := strings.TrimSpace(rest[len(operator):])
+		if isWordOperator(operator) && value != "" && rest[len(operator)] != ' ' {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(operator, "null") && value != "":
+			return Filter{}, fmt.Errorf("%s takes no value: %s", operator, line)
+		case !strings.HasSuffix(operator, "null") && value == "":
+			return Filter{}, fmt.Errorf("missing value: %s", line)
+		}
+
+		return Filter{Column: column, Operator: operator, Value: value}, nil
+	}
+
+	return Filter{}, fmt.Errorf("expected column, operator and value: %s", line)
+}
+
+func isWordOperator(operator string) bool {
+	return operator[0] >= 'a' && operator[0] <= 'z'
+}
+
+// SQL returns the SELECT statement, quoting identifiers when needed and the
+// filter values as literals
+func (q Query) SQL() (string, error) {
+	if q.Table == "" {
+		return "", errors.New("no table selected")
+	}
+
+	columns := "*"
+	if len(q.Columns) > 0 {
+		quoted := make([]string, len(q.Columns))
+		for i, column := range q.Columns {
+			quoted[i] = columnpicker.QuoteName(column)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT %s\nFROM %s", columns, quoteTable(q.Table))
+
+	for i, filter := range q.Filters {
+		if i == 0 {
+			sb.WriteString("\nWHERE ")
+		} else {
+			sb.WriteString("\n  AND ")
+		}
+		sb.WriteString(filter.condition())
+	}
+
+	if q.OrderBy != "" {
+		fmt.Fprintf(&sb, "\nORDER BY %s", columnpicker.QuoteName(q.OrderBy))
+		if q.Descending {
+			sb.WriteString(" DESC")
+		}
+	}
+
+	if q.Limit > 0 {
+		fmt.Fprintf(&sb, "\nLIMIT %d", q.Limit)
+	}
+
+	sb.WriteString(";")
+
+	return sb.String(), nil
+}
+
+func (f Filter) condition() string {
+	column := columnpicker.QuoteName(f.Column)
+	operator := strings.ToUpper(f.Operator)
+
+	switch f.Operator {
+	case "is null", "is not null":
+		return column + " " + operator
+
+	case "in", "not in":
+		values := strings.Split(f.Value, ",")
+		for i, value := range values {
+			values[i] = quoteLiteral(strings.TrimSpace(value))
+		}
+		return fmt.Sprintf("%s %s (%s)", column, operator, strings.Join(values, ", "))
+	}
+
+	return fmt.Sprintf("%s %s %s", column, operator, quoteLiteral(f.Value))
+}
+
+// quoteTable quotes the parts of a schema qualified table name
+func quoteTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = columnpicker.QuoteName(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteLiteral quotes value as a string literal, which the server casts to
+// the type of the column, so numbers and dates need no special handling
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,78 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilters(t *testing.T) {
+	t.Parallel()
+
+	filters, err := ParseFilters("status = active\n\ncreated_at>=2024-01-01\ndeleted_at IS NULL\nname ilike %ana%\nid in 1, 2")
+	require.NoError(t, err)
+	assert.Equal(t, []Filter{
+		{Column: "status", Operator: "=", Value: "active"},
+		{Column: "created_at", Operator: ">=", Value: "2024-01-01"},
+		{Column: "deleted_at", Operator: "is null"},
+		{Column: "name", Operator: "ilike", Value: "%ana%"},
+		{Column: "id", Operator: "in", Value: "1, 2"},
+	}, filters)
+
+	for _, line := range []string{"status", "status = ", "deleted_at is null now", "name likes a"} {
+		_, err := ParseFilters(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected string
+	}{
+		{
+			name:     "all columns",
+			query:    Query{Table: "users"},
+			expected: "SELECT *\nFROM users;",
+		},
+		{
+			name: "filters, order and limit",
+			query: Query{
+				Table:   "sales.Orders",
+				Columns: []string{"id", "Total"},
+				Filters: []Filter{
+					{Column: "note", Operator: "=", Value: "it's"},
+					{Column: "shipped_at", Operator: "is not null"},
+					{Column: "status", Operator: "not in", Value: "lost, void"},
+				},
+				OrderBy:    "Total",
+				Descending: true,
+				Limit:      10,
+			},
+			expected: `SELECT id, "Total"
+FROM sales."Orders"
+WHERE note = 'it''s'
+  AND shipped_at IS NOT NULL
+  AND status NOT IN ('lost', 'void')
+ORDER BY "Total" DESC
+LIMIT 10;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sql, err := tt.query.SQL()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+
+	_, err := Query{}.SQL()
+	require.Error(t, err)
+}
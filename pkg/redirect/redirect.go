@@ -0,0 +1,193 @@
+package redirect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/export"
+	"github.com/ionut-t/perp/pkg/pipe"
+)
+
+// Target is a file query results are written to, in addition to the results
+// view, from \o filename until \o is issued again. The file stays open
+// until the target is closed.
+type Target struct {
+	Path   string
+	Format pipe.Format
+
+	out *output
+}
+
+// output is the open file of a target, shared by its copies
+type output struct {
+	file    *os.File
+	written int // results and messages written so far
+}
+
+// Start truncates the file at path, creating it if needed, and returns the
+// target writing to it in the export format matching its extension. A bare
+// file name is put in storage, the exports directory of the server, so it is
+// listed with the other exports; other paths are relative to the working
+// directory. A JSON file holds an array, whose elements are written as they
+// come and which is ended by Close.
+func Start(path, storage string) (Target, error) {
+	var format pipe.Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		format = pipe.CSV
+	case ".json":
+		format = pipe.JSON
+	default:
+		return Target{}, fmt.Errorf("invalid file extension: %s. Supported extensions are .json and .csv", path)
+	}
+
+	if filepath.Base(path) == path {
+		if err := os.MkdirAll(storage, 0o755); err != nil {
+			return Target{}, fmt.Errorf("failed to create %s: %w", storage, err)
+		}
+		path = filepath.Join(storage, path)
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return Target{}, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Target{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	if format == pipe.JSON {
+		if _, err := file.WriteString("["); err != nil {
+			_ = file.Close()
+			return Target{}, fmt.Errorf("failed to write to %s: %w", path, err)
+		}
+	}
+
+	return Target{Path: path, Format: format, out: &output{file: file}}, nil
+}
+
+// Active reports whether results are being redirected
+func (t Target) Active() bool {
+	return t.Path != ""
+}
+
+// Close ends the array of a JSON file and closes the file. It can be called
+// more than once, and on a target that isn't active.
+func (t Target) Close() error {
+	if t.out == nil || t.out.file == nil {
+		return nil
+	}
+
+	file := t.out.file
+	t.out.file = nil
+
+	if t.Format == pipe.JSON {
+		end := "\n]\n"
+		if t.out.written == 0 {
+			end = "]\n"
+		}
+
+		if _, err := file.WriteString(end); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to write to %s: %w", t.Path, err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", t.Path, err)
+	}
+
+	return nil
+}
+
+// Write appends results to the file, separated from the previous ones by an
+// empty line, with their columns in order. In a JSON file, the rows are added
+// to the array holding the rows of the previous results. Results without rows
+// are skipped.
+func (t Target) Write(results []map[string]any, columns []string) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	if t.Format == pipe.JSON {
+		values := make([]any, len(results))
+		for i, row := range results {
+			values[i] = export.OrderedRow{Columns: columns, Values: row}
+		}
+		return t.appendJSON(values)
+	}
+
+	if columns == nil {
+		columns = slices.Sorted(maps.Keys(results[0]))
+	}
+
+	data, err := export.Delimited(results, columns, ',')
+	if err != nil {
+		return err
+	}
+
+	return t.write([]byte(data))
+}
+
+// WriteText appends a line of text to the file, e.g. a message of \qecho. In
+// a JSON file, it is added to the array as a string.
+func (t Target) WriteText(text string) error {
+	if t.Format == pipe.JSON {
+		return t.appendJSON([]any{text})
+	}
+
+	return t.write([]byte(text))
+}
+
+// appendJSON adds values to the array of the JSON file, one per line
+func (t Target) appendJSON(values []any) error {
+	var buf bytes.Buffer
+	for _, value := range values {
+		item, err := json.MarshalIndent(value, "  ", "  ")
+		if err != nil {
+			return err
+		}
+
+		if (t.out != nil && t.out.written > 0) || buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("\n  ")
+		buf.Write(item)
+	}
+
+	return t.write(buf.Bytes())
+}
+
+// write appends data to the file. Outside a JSON array, it is separated from
+// the previous output by an empty line and ends with a newline.
+func (t Target) write(data []byte) error {
+	if t.out == nil || t.out.file == nil {
+		return fmt.Errorf("%s is closed", t.Path)
+	}
+
+	if t.Format != pipe.JSON {
+		if t.out.written > 0 {
+			data = append([]byte("\n"), data...)
+		}
+
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			data = append(data, '\n')
+		}
+	}
+
+	if _, err := t.out.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write to %s: %w", t.Path, err)
+	}
+
+	t.out.written++
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package redirect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/pipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.csv")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+
+	target, err := Start(path, filepath.Join(dir, "exports"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = target.Close() })
+	assert.True(t, target.Active())
+	assert.Equal(t, path, target.Path)
+	assert.Equal(t, pipe.CSV, target.Format)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data, "the file is truncated")
+
+	_, err = Start(filepath.Join(dir, "out.txt"), dir)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "out.txt"))
+
+	storage := filepath.Join(dir, "exports")
+	report, err := Start("report.json", storage)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(storage, "report.json"), report.Path)
+	assert.FileExists(t, report.Path)
+
+	require.NoError(t, report.Close())
+	data, err = os.ReadFile(report.Path)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(data), "a JSON file closed without results holds an empty array")
+
+	assert.False(t, Target{}.Active())
+	assert.NoError(t, Target{}.Close())
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.csv")
+	target, err := Start(path, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, target.WriteText("Users"))
+	require.NoError(t, target.Write([]map[string]any{{"id": 1, "name": "alice"}}, []string{"name", "id"}))
+	require.NoError(t, target.Write(nil, nil))
+	require.NoError(t, target.Write([]map[string]any{{"total": 2}}, nil))
+	require.NoError(t, target.Close())
+	require.NoError(t, target.Close(), "closing again does nothing")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Users\n\nname,id\nalice,1\n\ntotal\n2\n", string(data))
+
+	assert.Error(t, target.WriteText("late"), "a closed target is not written to")
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	target, err := Start(path, dir)
+	require.NoError(t, err)
+
+	// a copy of the target, as kept by the model, writes to the same file
+	copied := target
+
+	require.NoError(t, target.Write([]map[string]any{{"name": "alice", "id": 1}, {"name": "bob", "id": 2}}, []string{"name", "id"}))
+	require.NoError(t, copied.Write(nil, nil))
+	require.NoError(t, copied.WriteText("Totals"))
+	require.NoError(t, target.Write([]map[string]any{{"total": 2}}, []string{"total"}))
+	require.NoError(t, copied.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "name": "alice",
+    "id": 1
+  },
+  {
+    "name": "bob",
+    "id": 2
+  },
+  "Totals",
+  {
+    "total": 2
+  }
+]
+`, string(data), "the rows keep the order of their columns")
+}
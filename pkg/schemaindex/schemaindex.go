@@ -0,0 +1,281 @@
+package schemaindex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// DefaultTopK is the number of tables attached to a prompt by default
+const DefaultTopK = 5
+
+const (
+	nameWeight        = 3.0
+	columnWeight      = 1.0
+	descriptionWeight = 0.5
+)
+
+// Table describes a table and its columns for indexing
+type Table struct {
+	Name        string
+	Description string
+	Columns     []Column
+	Rows        int64    // the estimated number of rows, set by MajorTables
+	References  []string // the tables referenced by foreign keys, set by MajorTables
+}
+
+// Column describes a single table column
+type Column struct {
+	Name        string
+	Type        string // set by Load and MajorTables
+	Description string
+}
+
+// Result is a table matched by a search, ordered by score
+type Result struct {
+	Table string
+	Score float64
+}
+
+// Index is a keyword index over table names, column names and descriptions.
+// Tables are ranked with a TF-IDF score where matches on the table name
+// weigh more than matches on columns or descriptions.
+type Index struct {
+	terms   map[string]map[string]float64 // term -> table -> weighted frequency
+	idf     map[string]float64
+	size    int
+	columns map[string][]Column // table -> columns
+	enums   map[string][]string // enum type -> labels, set by Load
+}
+
+// New builds an index from the given tables
+func New(tables []Table) *Index {
+	idx := &Index{
+		terms:   make(map[string]map[string]float64),
+		idf:     make(map[string]float64),
+		size:    len(tables),
+		columns: make(map[string][]Column, len(tables)),
+	}
+
+	for _, table := range tables {
+		idx.columns[table.Name] = table.Columns
+
+		idx.add(table.Name, table.Name, nameWeight)
+		idx.add(table.Name, table.Description, descriptionWeight)
+
+		for _, column := range table.Columns {
+			idx.add(table.Name, column.Name, columnWeight)
+			idx.add(table.Name, column.Description, descriptionWeight)
+		}
+	}
+
+	for term, tables := range idx.terms {
+		idx.idf[term] = math.Log(1 + float64(idx.size)/float64(len(tables)))
+	}
+
+	return idx
+}
+
+// Size returns the number of indexed tables
+func (idx *Index) Size() int {
+	return idx.size
+}
+
+// Columns returns the columns of table, in the order they are defined
+func (idx *Index) Columns(table string) []Column {
+	if idx == nil {
+		return nil
+	}
+	return idx.columns[table]
+}
+
+// Tables returns the names of the indexed tables, sorted
+func (idx *Index) Tables() []string {
+	if idx == nil {
+		return nil
+	}
+
+	tables := make([]string, 0, len(idx.columns))
+	for table := range idx.columns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	return tables
+}
+
+// EnumLabels returns the labels of the enum type named as in the type of its
+// columns, in their sort order
+func (idx *Index) EnumLabels(typ string) []string {
+	if idx == nil {
+		return nil
+	}
+	return idx.enums[typ]
+}
+
+// Search returns up to k tables relevant to the prompt, best match first
+func (idx *Index) Search(prompt string, k int) []Result {
+	if idx == nil || k <= 0 {
+		return nil
+	}
+
+	scores := make(map[string]float64)
+	for _, term := range tokenize(prompt) {
+		for table, frequency := range idx.terms[term] {
+			scores[table] += frequency * idx.idf[term]
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for table, score := range scores {
+		results = append(results, Result{Table: table, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Table < results[j].Table
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	return results
+}
+
+func (idx *Index) add(table, text string, weight float64) {
+	for _, term := range tokenize(text) {
+		if idx.terms[term] == nil {
+			idx.terms[term] = make(map[string]float64)
+		}
+		idx.terms[term][table] += weight
+	}
+}
+
+// Load reads the tables and columns of the public schema, including their
+// comments, and the labels of the enum types
+func Load(ctx context.Context, database db.Database) (*Index, error) {
+	result, err := database.Query(ctx, `
+		SELECT
+			c.relname AS table_name,
+			COALESCE(obj_description(c.oid, 'pg_class'), '') AS table_description,
+			a.attname AS column_name,
+			format_type(a.atttypid, a.atttypmod) AS column_type,
+			COALESCE(col_description(c.oid, a.attnum), '') AS column_description
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+		WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND n.nspname = 'public'
+		ORDER BY c.relname, a.attnum`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema for indexing: %w", err)
+	}
+
+	rows, _, err := db.ExtractPsqlResults(result.Rows())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema for indexing: %w", err)
+	}
+
+	var tables []Table
+	for _, row := range rows {
+		name := fmt.Sprint(row["table_name"])
+
+		if len(tables) == 0 || tables[len(tables)-1].Name != name {
+			tables = append(tables, Table{
+				Name:        name,
+				Description: fmt.Sprint(row["table_description"]),
+			})
+		}
+
+		table := &tables[len(tables)-1]
+		table.Columns = append(table.Columns, Column{
+			Name:        fmt.Sprint(row["column_name"]),
+			Type:        fmt.Sprint(row["column_type"]),
+			Description: fmt.Sprint(row["column_description"]),
+		})
+	}
+
+	enums, err := loadEnums(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := New(tables)
+	idx.enums = enums
+
+	return idx, nil
+}
+
+// loadEnums reads the labels of the enum types, keyed by the type name as
+// format_type writes it for their columns
+func loadEnums(ctx context.Context, database db.Database) (map[string][]string, error) {
+	result, err := database.Query(ctx, `
+		SELECT
+			format_type(t.oid, NULL) AS type_name,
+			e.enumlabel AS label
+		FROM pg_enum e
+		JOIN pg_type t ON t.oid = e.enumtypid
+		ORDER BY t.oid, e.enumsortorder`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load enum types for indexing: %w", err)
+	}
+
+	rows, _, err := db.ExtractPsqlResults(result.Rows())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enum types for indexing: %w", err)
+	}
+
+	enums := make(map[string][]string)
+	for _, row := range rows {
+		typ := fmt.Sprint(row["type_name"])
+		enums[typ] = append(enums[typ], fmt.Sprint(row["label"]))
+	}
+
+	return enums, nil
+}
+
+// tokenize splits text into lower-cased terms on non-alphanumeric characters
+// (including underscores) and reduces simple plurals to their singular form.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 || stopWords[field] {
+			continue
+		}
+		terms = append(terms, singular(field))
+	}
+	return terms
+}
+
+func singular(term string) string {
+	switch {
+	case strings.HasSuffix(term, "ies") && len(term) > 4:
+		return strings.TrimSuffix(term, "ies") + "y"
+	case strings.HasSuffix(term, "ses") || strings.HasSuffix(term, "xes"):
+		return strings.TrimSuffix(term, "es")
+	case strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && len(term) > 3:
+		return strings.TrimSuffix(term, "s")
+	}
+	return term
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"for": true, "by": true, "with": true, "on": true, "at": true, "from": true,
+	"all": true, "me": true, "show": true, "get": true, "list": true, "find": true,
+	"what": true, "which": true, "who": true, "how": true, "many": true,
+	"is": true, "are": true, "was": true, "were": true, "an": true, "each": true,
+	"select": true, "where": true, "their": true, "that": true, "have": true,
+}
@@ -0,0 +1,167 @@
+package schemaindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTables() []Table {
+	return []Table{
+		{
+			Name: "users",
+			Columns: []Column{
+				{Name: "id"}, {Name: "email"}, {Name: "created_at"},
+			},
+		},
+		{
+			Name:        "orders",
+			Description: "Customer purchases",
+			Columns: []Column{
+				{Name: "id"}, {Name: "user_id"}, {Name: "total_amount"}, {Name: "status"},
+			},
+		},
+		{
+			Name: "order_items",
+			Columns: []Column{
+				{Name: "id"}, {Name: "order_id"}, {Name: "product_id"}, {Name: "quantity"},
+			},
+		},
+		{
+			Name: "categories",
+			Columns: []Column{
+				{Name: "id"}, {Name: "name"},
+			},
+		},
+	}
+}
+
+func resultTables(results []Result) []string {
+	tables := make([]string, len(results))
+	for i, r := range results {
+		tables[i] = r.Table
+	}
+	return tables
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	idx := New(testTables())
+
+	tests := []struct {
+		name     string
+		prompt   string
+		k        int
+		expected []string
+	}{
+		{
+			name:     "table name match ranks first",
+			prompt:   "show all users",
+			k:        1,
+			expected: []string{"users"},
+		},
+		{
+			name:     "column matches across tables",
+			prompt:   "total amount per user",
+			k:        2,
+			expected: []string{"orders", "users"},
+		},
+		{
+			name:     "description match",
+			prompt:   "latest customer purchases",
+			k:        1,
+			expected: []string{"orders"},
+		},
+		{
+			name:     "ies plural",
+			prompt:   "list category names",
+			k:        1,
+			expected: []string{"categories"},
+		},
+		{
+			name:     "no match",
+			prompt:   "weather forecast",
+			k:        3,
+			expected: []string{},
+		},
+		{
+			name:     "non-positive k",
+			prompt:   "users",
+			k:        0,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, resultTables(idx.Search(tt.prompt, tt.k)))
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"order", "item", "quantity"}, tokenize("order_items.quantity"))
+	assert.Equal(t, []string{"category", "address"}, tokenize("Categories, addresses"))
+}
+
+func TestNilIndexSearch(t *testing.T) {
+	t.Parallel()
+
+	var idx *Index
+	assert.Empty(t, idx.Search("users", 3))
+	assert.Empty(t, idx.Columns("users"))
+	assert.Empty(t, idx.Tables())
+	assert.Empty(t, idx.EnumLabels("mood"))
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	idx := New(testTables())
+	assert.Equal(t, []Column{{Name: "id"}, {Name: "name"}}, idx.Columns("categories"))
+	assert.Empty(t, idx.Columns("missing"))
+}
+
+func TestTables(t *testing.T) {
+	t.Parallel()
+
+	idx := New(testTables())
+	assert.Equal(t, []string{"categories", "order_items", "orders", "users"}, idx.Tables())
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	tables := []Table{
+		{
+			Name:        "public.orders",
+			Description: "Customer purchases",
+			Rows:        1200,
+			References:  []string{"users"},
+			Columns: []Column{
+				{Name: "id", Type: "bigint"},
+				{Name: "user_id", Type: "bigint", Description: "The buyer"},
+			},
+		},
+		{
+			Name:    "public.users",
+			Rows:    40,
+			Columns: []Column{{Name: "id", Type: "bigint"}},
+		},
+	}
+
+	expected := `Table public.orders (~1200 rows): Customer purchases
+References: users
+- id bigint
+- user_id bigint: The buyer
+
+Table public.users (~40 rows)
+- id bigint
+`
+
+	assert.Equal(t, expected, Describe(tables))
+}
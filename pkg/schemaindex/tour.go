@@ -0,0 +1,110 @@
+package schemaindex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+const majorTablesQuery = `
+	WITH major AS (
+		SELECT
+			c.oid,
+			pg_catalog.format('%I.%I', n.nspname, c.relname) AS name,
+			COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '') AS description,
+			GREATEST(c.reltuples, 0)::bigint AS estimated_rows,
+			ARRAY(
+				SELECT DISTINCT con.confrelid::pg_catalog.regclass::text
+				FROM pg_catalog.pg_constraint con
+				WHERE con.conrelid = c.oid AND con.contype = 'f' AND con.confrelid <> c.oid
+				ORDER BY 1
+			) AS refs
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition
+			AND n.nspname <> 'information_schema' AND n.nspname !~ '^pg_'
+		ORDER BY c.reltuples DESC, 2
+		LIMIT $1
+	)
+	SELECT
+		m.name,
+		m.description,
+		m.estimated_rows,
+		m.refs,
+		a.attname,
+		pg_catalog.format_type(a.atttypid, a.atttypmod),
+		COALESCE(pg_catalog.col_description(m.oid, a.attnum), '')
+	FROM major m
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = m.oid AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY m.estimated_rows DESC, m.name, a.attnum`
+
+// MajorTables returns the limit largest tables by estimated rows, with their
+// columns, comments and the tables they reference
+func MajorTables(ctx context.Context, database db.Database, limit int) ([]Table, error) {
+	result, err := database.Query(ctx, majorTablesQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the major tables: %w", err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var table Table
+		var column Column
+
+		if err := rows.Scan(
+			&table.Name, &table.Description, &table.Rows, &table.References,
+			&column.Name, &column.Type, &column.Description,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read the major tables: %w", err)
+		}
+
+		if len(tables) == 0 || tables[len(tables)-1].Name != table.Name {
+			tables = append(tables, table)
+		}
+
+		last := &tables[len(tables)-1]
+		last.Columns = append(last.Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load the major tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// Describe writes the tables as compact text for an LLM prompt
+func Describe(tables []Table) string {
+	var sb strings.Builder
+
+	for i, table := range tables {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "Table %s (~%d rows)", table.Name, table.Rows)
+		if table.Description != "" {
+			sb.WriteString(": " + table.Description)
+		}
+		sb.WriteString("\n")
+
+		if len(table.References) > 0 {
+			sb.WriteString("References: " + strings.Join(table.References, ", ") + "\n")
+		}
+
+		for _, column := range table.Columns {
+			fmt.Fprintf(&sb, "- %s %s", column.Name, column.Type)
+			if column.Description != "" {
+				sb.WriteString(": " + column.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
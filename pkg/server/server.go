@@ -27,6 +27,7 @@ type Server struct {
 	UpdatedAt              time.Time `json:"updatedAt"`
 	ShareDatabaseSchemaLLM bool      `json:"shareDatabaseSchemaLLM"`
 	TimingEnabled          bool      `json:"timingEnabled"`
+	OnConnect              string    `json:"onConnect,omitempty"` // SQL run after connecting
 }
 
 type CreateServer struct {
@@ -37,6 +38,7 @@ type CreateServer struct {
 	Password               string
 	Database               string
 	ShareDatabaseSchemaLLM bool
+	OnConnect              string
 }
 
 // New creates a new server instance and saves it to the storage file.
@@ -55,6 +57,7 @@ func New(server CreateServer, storage string) (*Server, error) {
 		Password:               server.Password,
 		Database:               server.Database,
 		ShareDatabaseSchemaLLM: server.ShareDatabaseSchemaLLM,
+		OnConnect:              server.OnConnect,
 		CreatedAt:              time.Now().In(time.UTC),
 		UpdatedAt:              time.Now().In(time.UTC),
 	}
@@ -85,6 +88,22 @@ func Load(storage string) ([]Server, error) {
 	return servers, nil
 }
 
+// FindByName returns the saved server with the given name.
+func FindByName(storage, name string) (*Server, error) {
+	servers, err := Load(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, srv := range servers {
+		if srv.Name == name {
+			return &srv, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no saved server named '%s'", name)
+}
+
 func save(server *Server, storage string) error {
 	path := filepath.Join(storage, "servers.json")
 
@@ -141,6 +160,7 @@ func (s *Server) Update(server CreateServer, storage string) error {
 	s.Password = server.Password
 	s.Database = server.Database
 	s.ShareDatabaseSchemaLLM = server.ShareDatabaseSchemaLLM
+	s.OnConnect = server.OnConnect
 	s.UpdatedAt = time.Now().In(time.UTC)
 
 	if err := save(s, storage); err != nil {
@@ -165,6 +185,19 @@ func (s *Server) EnableDatabaseSchemaLLM(enabled bool, storage string) error {
 	return nil
 }
 
+// SetPassword saves the password used to connect, e.g. after it was changed
+// with \password
+func (s *Server) SetPassword(password string, storage string) error {
+	s.Password = password
+	s.UpdatedAt = time.Now().In(time.UTC)
+
+	if err := save(s, storage); err != nil {
+		return fmt.Errorf("failed to update server: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Server) ToggleTiming(storage string) error {
 	s.TimingEnabled = !s.TimingEnabled
 	s.UpdatedAt = time.Now().In(time.UTC)
@@ -293,12 +293,16 @@ func TestUpdate(t *testing.T) {
 				Password:               "pass2",
 				Database:               "db2",
 				ShareDatabaseSchemaLLM: true,
+				OnConnect:              "SET search_path TO app",
 			},
 			expectError: false,
 			validate: func(t *testing.T, srv *Server) {
 				if srv.Name != "Updated" {
 					t.Errorf("Expected name 'Updated', got '%s'", srv.Name)
 				}
+				if srv.OnConnect != "SET search_path TO app" {
+					t.Errorf("Expected OnConnect to be updated, got '%s'", srv.OnConnect)
+				}
 				if srv.Port != 5433 {
 					t.Errorf("Expected port 5433, got %d", srv.Port)
 				}
@@ -468,6 +472,44 @@ func TestEnableDatabaseSchemaLLM(t *testing.T) {
 	}
 }
 
+func TestSetPassword(t *testing.T) {
+	t.Parallel()
+
+	tempDir := setupTempDir(t)
+	defer removeTempDir(t, tempDir)
+
+	srv := Server{
+		ID:        uuid.New(),
+		Name:      "Test Server",
+		Address:   "localhost",
+		Port:      5432,
+		Username:  "user",
+		Password:  "pass",
+		Database:  "db",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UpdatedAt: time.Now().Add(-time.Hour),
+	}
+
+	if err := saveServers(tempDir, []Server{srv}); err != nil {
+		t.Fatalf("Failed to save server: %v", err)
+	}
+
+	if err := srv.SetPassword("new pass", tempDir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	servers, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to load servers: %v", err)
+	}
+	if len(servers) != 1 {
+		t.Fatalf("Expected 1 server, got %d", len(servers))
+	}
+	if servers[0].Password != "new pass" {
+		t.Errorf("Expected password 'new pass', got '%s'", servers[0].Password)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	t.Parallel()
 
@@ -849,6 +891,23 @@ func TestConcurrentOperations(t *testing.T) {
 	}
 }
 
+func TestFindByName(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+	require.NoError(t, saveServers(storage, []Server{
+		{ID: uuid.New(), Name: "local", Address: "localhost", Port: 5432},
+		{ID: uuid.New(), Name: "staging", Address: "staging.example.com", Port: 5432},
+	}))
+
+	srv, err := FindByName(storage, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "staging.example.com", srv.Address)
+
+	_, err = FindByName(storage, "production")
+	assert.ErrorContains(t, err, "no saved server named 'production'")
+}
+
 // Helper functions
 
 func removeTempDir(t *testing.T, dir string) {
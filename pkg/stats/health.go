@@ -0,0 +1,220 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// Columns of the health summary
+const (
+	HealthMetricColumn = "Metric"
+	HealthValueColumn  = "Value"
+	HealthStatusColumn = "Status"
+)
+
+// Statuses of the health metrics
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+)
+
+// Thresholds above or below which a metric gets a warning
+const (
+	minCacheHitRatio   = 0.99
+	maxConnectionRatio = 0.8
+	maxReplicationLag  = 60 * time.Second
+)
+
+// Health is a snapshot of the health metrics of the connected database
+type Health struct {
+	BlocksHit      int64
+	BlocksRead     int64
+	Deadlocks      int64
+	TempFiles      int64
+	TempBytes      int64
+	StatsReset     *time.Time
+	Connections    int64
+	MaxConnections int64
+	InRecovery     bool
+
+	// On a standby, the time since the last replayed transaction
+	ReplayDelay *time.Duration
+
+	// On a primary, the number of streaming replicas and the lag of the
+	// slowest one
+	Replicas       int64
+	ReplicationLag *time.Duration
+	LagBytes       *int64
+}
+
+const healthQuery = `
+	SELECT
+		d.blks_hit,
+		d.blks_read,
+		d.deadlocks,
+		d.temp_files,
+		d.temp_bytes,
+		d.stats_reset,
+		(SELECT count(*) FROM pg_catalog.pg_stat_activity WHERE backend_type = 'client backend'),
+		pg_catalog.current_setting('max_connections')::bigint,
+		pg_catalog.pg_is_in_recovery(),
+		CASE WHEN pg_catalog.pg_is_in_recovery()
+			THEN EXTRACT(EPOCH FROM now() - pg_catalog.pg_last_xact_replay_timestamp())::float8
+		END,
+		(SELECT count(*) FROM pg_catalog.pg_stat_replication),
+		(SELECT EXTRACT(EPOCH FROM max(r.replay_lag))::float8 FROM pg_catalog.pg_stat_replication r),
+		CASE WHEN NOT pg_catalog.pg_is_in_recovery() THEN (
+			SELECT max(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), r.replay_lsn))::bigint
+			FROM pg_catalog.pg_stat_replication r
+		) END
+	FROM pg_catalog.pg_stat_database d
+	WHERE d.datname = pg_catalog.current_database()`
+
+// GetHealth collects the health metrics of the connected database
+func GetHealth(ctx context.Context, database db.Database) (*Health, error) {
+	result, err := database.Query(ctx, healthQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect the health metrics: %w", err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to collect the health metrics: %w", err)
+		}
+		return nil, fmt.Errorf("no statistics for the current database")
+	}
+
+	var (
+		h                         Health
+		replayDelay, replicaDelay *float64
+	)
+
+	if err := rows.Scan(
+		&h.BlocksHit,
+		&h.BlocksRead,
+		&h.Deadlocks,
+		&h.TempFiles,
+		&h.TempBytes,
+		&h.StatsReset,
+		&h.Connections,
+		&h.MaxConnections,
+		&h.InRecovery,
+		&replayDelay,
+		&h.Replicas,
+		&replicaDelay,
+		&h.LagBytes,
+	); err != nil {
+		return nil, fmt.Errorf("failed to read the health metrics: %w", err)
+	}
+
+	h.ReplayDelay = seconds(replayDelay)
+	h.ReplicationLag = seconds(replicaDelay)
+
+	return &h, nil
+}
+
+// CacheHitRatio returns the share of the blocks read from the shared buffers
+// rather than from disk, and false before any block is read
+func (h Health) CacheHitRatio() (float64, bool) {
+	total := h.BlocksHit + h.BlocksRead
+	if total == 0 {
+		return 0, false
+	}
+
+	return float64(h.BlocksHit) / float64(total), true
+}
+
+// Result returns the health summary with one row per metric
+func (h Health) Result() *psql.Result {
+	result := &psql.Result{
+		Columns: []string{HealthMetricColumn, HealthValueColumn, HealthStatusColumn},
+		Message: "Database health",
+	}
+
+	add := func(metric, value string, ok bool) {
+		status := StatusOK
+		if !ok {
+			status = StatusWarning
+		}
+
+		result.Rows = append(result.Rows, map[string]any{
+			HealthMetricColumn: metric,
+			HealthValueColumn:  value,
+			HealthStatusColumn: status,
+		})
+	}
+
+	if ratio, ok := h.CacheHitRatio(); ok {
+		add("Cache hit ratio", fmt.Sprintf("%.2f%%", ratio*100), ratio >= minCacheHitRatio)
+	} else {
+		add("Cache hit ratio", "no blocks read yet", true)
+	}
+
+	connections := fmt.Sprintf("%d of %d", h.Connections, h.MaxConnections)
+	add("Connections", connections, float64(h.Connections) <= maxConnectionRatio*float64(h.MaxConnections))
+
+	add("Deadlocks", fmt.Sprint(h.Deadlocks), h.Deadlocks == 0)
+
+	// temporary files are written by sorts and hashes larger than work_mem
+	add("Temporary files", fmt.Sprintf("%d (%s)", h.TempFiles, formatBytes(h.TempBytes)), h.TempFiles == 0)
+
+	switch {
+	case h.InRecovery && h.ReplayDelay != nil:
+		add("Replay delay", h.ReplayDelay.Round(time.Millisecond).String(), *h.ReplayDelay <= maxReplicationLag)
+	case h.InRecovery:
+		add("Replay delay", "nothing replayed yet", true)
+	case h.Replicas == 0:
+		add("Replication", "no streaming replicas", true)
+	default:
+		lag := "unknown"
+		ok := true
+		if h.ReplicationLag != nil {
+			lag = h.ReplicationLag.Round(time.Millisecond).String()
+			ok = *h.ReplicationLag <= maxReplicationLag
+		}
+		if h.LagBytes != nil {
+			lag += fmt.Sprintf(" (%s behind)", formatBytes(*h.LagBytes))
+		}
+		add(fmt.Sprintf("Replication lag (%d replicas)", h.Replicas), lag, ok)
+	}
+
+	if h.StatsReset != nil {
+		add("Statistics since", h.StatsReset.Format(time.DateTime), true)
+	}
+
+	return result
+}
+
+func seconds(value *float64) *time.Duration {
+	if value == nil {
+		return nil
+	}
+
+	d := time.Duration(*value * float64(time.Second))
+	return &d
+}
+
+// formatBytes formats a byte count for display
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size)
+	for _, suffix := range []string{"KB", "MB", "GB", "TB"} {
+		value /= unit
+		if value < unit || suffix == "TB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthResult(t *testing.T) {
+	t.Parallel()
+
+	lag := 90 * time.Second
+	lagBytes := int64(3 << 20)
+
+	h := Health{
+		BlocksHit:      980,
+		BlocksRead:     20,
+		Deadlocks:      0,
+		TempFiles:      2,
+		TempBytes:      1536,
+		Connections:    90,
+		MaxConnections: 100,
+		Replicas:       1,
+		ReplicationLag: &lag,
+		LagBytes:       &lagBytes,
+	}
+
+	result := h.Result()
+	require.Len(t, result.Rows, 5)
+
+	expected := []struct {
+		metric, value, status string
+	}{
+		{"Cache hit ratio", "98.00%", StatusWarning},
+		{"Connections", "90 of 100", StatusWarning},
+		{"Deadlocks", "0", StatusOK},
+		{"Temporary files", "2 (1.5 KB)", StatusWarning},
+		{"Replication lag (1 replicas)", "1m30s (3.0 MB behind)", StatusWarning},
+	}
+
+	for i, row := range result.Rows {
+		assert.Equal(t, expected[i].metric, row[HealthMetricColumn])
+		assert.Equal(t, expected[i].value, row[HealthValueColumn])
+		assert.Equal(t, expected[i].status, row[HealthStatusColumn])
+	}
+}
+
+func TestHealthStandby(t *testing.T) {
+	t.Parallel()
+
+	delay := 1500 * time.Millisecond
+	h := Health{InRecovery: true, ReplayDelay: &delay, MaxConnections: 100}
+
+	result := h.Result()
+	require.Len(t, result.Rows, 5)
+
+	assert.Equal(t, "no blocks read yet", result.Rows[0][HealthValueColumn])
+	assert.Equal(t, "Replay delay", result.Rows[4][HealthMetricColumn])
+	assert.Equal(t, "1.5s", result.Rows[4][HealthValueColumn])
+	assert.Equal(t, StatusOK, result.Rows[4][HealthStatusColumn])
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "2.0 KB", formatBytes(2048))
+	assert.Equal(t, "1.5 GB", formatBytes(3<<29))
+}
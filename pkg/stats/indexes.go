@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Columns of the index usage report
+const (
+	IndexUnusedColumn = "Unused"
+	DropIndexColumn   = "Drop Statement"
+)
+
+// IndexUsageSQL lists the user indexes with their size and number of scans
+// since the statistics were last reset, the unused ones first. Indexes
+// backing a primary key, unique or exclusion constraint are never reported
+// unused, since they enforce it even when no query reads them. The unused
+// ones get the DROP INDEX statement to review.
+var IndexUsageSQL = fmt.Sprintf(`SELECT
+	s.schemaname AS "Schema",
+	s.relname AS "Table",
+	s.indexrelname AS "Index",
+	pg_catalog.pg_size_pretty(pg_catalog.pg_relation_size(s.indexrelid)) AS "Size",
+	s.idx_scan AS "Scans",
+	s.idx_tup_read AS "Tuples Read",
+	s.idx_tup_fetch AS "Tuples Fetched",
+	u.unused AS %[1]q,
+	CASE WHEN u.unused
+		THEN pg_catalog.format('DROP INDEX CONCURRENTLY %%I.%%I;', s.schemaname, s.indexrelname)
+	END AS %[2]q
+FROM pg_catalog.pg_stat_user_indexes s
+JOIN pg_catalog.pg_index i ON i.indexrelid = s.indexrelid
+CROSS JOIN LATERAL (
+	SELECT s.idx_scan = 0
+		AND NOT i.indisunique
+		AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint c WHERE c.conindid = s.indexrelid) AS unused
+) u
+ORDER BY u.unused DESC, pg_catalog.pg_relation_size(s.indexrelid) DESC, 1, 2, 3`,
+	IndexUnusedColumn,
+	DropIndexColumn,
+)
+
+// DropStatements returns the DROP INDEX statements suggested by the rows of
+// the index usage report
+func DropStatements(rows []map[string]any) []string {
+	var statements []string
+
+	for _, row := range rows {
+		statement, ok := row[DropIndexColumn]
+		if !ok || statement == nil {
+			continue
+		}
+
+		if s := strings.TrimSpace(fmt.Sprint(statement)); s != "" {
+			statements = append(statements, s)
+		}
+	}
+
+	return statements
+}
@@ -0,0 +1,25 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropStatements(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]any{
+		{"Index": "users_email_idx", IndexUnusedColumn: true, DropIndexColumn: "DROP INDEX CONCURRENTLY public.users_email_idx;"},
+		{"Index": "users_pkey", IndexUnusedColumn: false, DropIndexColumn: nil},
+		{"Index": "orders_created_idx", IndexUnusedColumn: true, DropIndexColumn: "DROP INDEX CONCURRENTLY public.orders_created_idx;"},
+		{"Index": "unrelated"},
+	}
+
+	assert.Equal(t, []string{
+		"DROP INDEX CONCURRENTLY public.users_email_idx;",
+		"DROP INDEX CONCURRENTLY public.orders_created_idx;",
+	}, DropStatements(rows))
+
+	assert.Empty(t, DropStatements(nil))
+}
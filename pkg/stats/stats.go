@@ -0,0 +1,3 @@
+// Package stats generates the reports built on the statistics collected by
+// the server, such as the usage of the indexes.
+package stats
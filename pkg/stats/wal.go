@@ -0,0 +1,174 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// Columns of the WAL and checkpoint statistics
+const (
+	WALMetricColumn    = "Metric"
+	WALTotalColumn     = "Total"
+	WALDeltaColumn     = "Delta"
+	WALPerSecondColumn = "Per Second"
+)
+
+// WALMetric is a cumulative counter of the WAL or checkpoint statistics
+type WALMetric struct {
+	Name  string
+	Value int64
+	Bytes bool // the counter is a number of bytes
+}
+
+// WALStats is a snapshot of the WAL and checkpoint statistics of the server
+type WALStats struct {
+	TakenAt time.Time
+	Metrics []WALMetric
+}
+
+// walStatsQuery returns the query reading the counters available on the
+// server version. PostgreSQL 17 moved the checkpoint counters from
+// pg_stat_bgwriter to pg_stat_checkpointer and pg_stat_wal exists since 14.
+func walStatsQuery(version int) string {
+	checkpoints := `
+		SELECT 'Checkpoints (timed)', checkpoints_timed FROM pg_catalog.pg_stat_bgwriter
+		UNION ALL SELECT 'Checkpoints (requested)', checkpoints_req FROM pg_catalog.pg_stat_bgwriter
+		UNION ALL SELECT 'Buffers written by checkpoints', buffers_checkpoint FROM pg_catalog.pg_stat_bgwriter
+		UNION ALL SELECT 'Buffers written by backends', buffers_backend FROM pg_catalog.pg_stat_bgwriter`
+
+	if version >= 170000 {
+		checkpoints = `
+		SELECT 'Checkpoints (timed)', num_timed FROM pg_catalog.pg_stat_checkpointer
+		UNION ALL SELECT 'Checkpoints (requested)', num_requested FROM pg_catalog.pg_stat_checkpointer
+		UNION ALL SELECT 'Buffers written by checkpoints', buffers_written FROM pg_catalog.pg_stat_checkpointer`
+	}
+
+	query := checkpoints + `
+		UNION ALL SELECT 'Buffers written by the background writer', buffers_clean FROM pg_catalog.pg_stat_bgwriter`
+
+	if version >= 140000 {
+		query += `
+		UNION ALL SELECT 'WAL records', wal_records FROM pg_catalog.pg_stat_wal
+		UNION ALL SELECT 'WAL full page images', wal_fpi FROM pg_catalog.pg_stat_wal
+		UNION ALL SELECT 'WAL bytes', wal_bytes::bigint FROM pg_catalog.pg_stat_wal
+		UNION ALL SELECT 'WAL buffers full', wal_buffers_full FROM pg_catalog.pg_stat_wal`
+	}
+
+	return query
+}
+
+// GetWALStats reads the WAL and checkpoint counters of the server
+func GetWALStats(ctx context.Context, database db.Database) (*WALStats, error) {
+	version, err := serverVersion(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := database.Query(ctx, walStatsQuery(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the WAL statistics: %w", err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	stats := &WALStats{TakenAt: time.Now()}
+	for rows.Next() {
+		var metric WALMetric
+		if err := rows.Scan(&metric.Name, &metric.Value); err != nil {
+			return nil, fmt.Errorf("failed to read the WAL statistics: %w", err)
+		}
+		metric.Bytes = metric.Name == "WAL bytes"
+		stats.Metrics = append(stats.Metrics, metric)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the WAL statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Result returns the counters with, when previous is given, how much each
+// one grew since then in total and per second. A counter lower than before
+// was reset in between and has no delta.
+func (s WALStats) Result(previous *WALStats) *psql.Result {
+	result := &psql.Result{
+		Columns: []string{WALMetricColumn, WALTotalColumn, WALDeltaColumn, WALPerSecondColumn},
+		Rows:    make([]map[string]any, 0, len(s.Metrics)),
+		Message: "WAL and checkpoint statistics",
+	}
+
+	var elapsed time.Duration
+	before := map[string]int64{}
+	if previous != nil {
+		elapsed = s.TakenAt.Sub(previous.TakenAt)
+		for _, metric := range previous.Metrics {
+			before[metric.Name] = metric.Value
+		}
+	}
+
+	for _, metric := range s.Metrics {
+		row := map[string]any{
+			WALMetricColumn:    metric.Name,
+			WALTotalColumn:     metric.format(metric.Value),
+			WALDeltaColumn:     "",
+			WALPerSecondColumn: "",
+		}
+
+		if value, ok := before[metric.Name]; ok {
+			if delta := metric.Value - value; delta >= 0 {
+				row[WALDeltaColumn] = "+" + metric.format(delta)
+				if elapsed > 0 {
+					row[WALPerSecondColumn] = metric.formatRate(float64(delta) / elapsed.Seconds())
+				}
+			} else {
+				row[WALDeltaColumn] = "reset"
+			}
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
+
+func (m WALMetric) format(value int64) string {
+	if m.Bytes {
+		return formatBytes(value)
+	}
+
+	return strconv.FormatInt(value, 10)
+}
+
+func (m WALMetric) formatRate(rate float64) string {
+	if m.Bytes {
+		return formatBytes(int64(rate)) + "/s"
+	}
+
+	return strconv.FormatFloat(rate, 'f', 1, 64) + "/s"
+}
+
+func serverVersion(ctx context.Context, database db.Database) (int, error) {
+	result, err := database.Query(ctx, "SELECT pg_catalog.current_setting('server_version_num')::int")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the server version: %w", err)
+	}
+
+	rows := result.Rows()
+	defer rows.Close()
+
+	var version int
+	if rows.Next() {
+		if err := rows.Scan(&version); err != nil {
+			return 0, fmt.Errorf("failed to read the server version: %w", err)
+		}
+	}
+
+	return version, rows.Err()
+}
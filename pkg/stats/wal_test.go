@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWALStatsQuery(t *testing.T) {
+	t.Parallel()
+
+	pg13 := walStatsQuery(130000)
+	assert.Contains(t, pg13, "checkpoints_timed")
+	assert.NotContains(t, pg13, "pg_stat_wal")
+
+	pg16 := walStatsQuery(160000)
+	assert.Contains(t, pg16, "buffers_backend")
+	assert.Contains(t, pg16, "pg_stat_wal")
+
+	pg17 := walStatsQuery(170000)
+	assert.Contains(t, pg17, "pg_stat_checkpointer")
+	assert.NotContains(t, pg17, "buffers_backend")
+	assert.False(t, strings.HasPrefix(strings.TrimSpace(pg17), "UNION"))
+}
+
+func TestWALStatsResult(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	previous := &WALStats{
+		TakenAt: start,
+		Metrics: []WALMetric{
+			{Name: "Checkpoints (timed)", Value: 10},
+			{Name: "WAL bytes", Value: 1 << 20, Bytes: true},
+			{Name: "WAL records", Value: 500},
+		},
+	}
+
+	current := WALStats{
+		TakenAt: start.Add(10 * time.Second),
+		Metrics: []WALMetric{
+			{Name: "Checkpoints (timed)", Value: 12},
+			{Name: "WAL bytes", Value: 11 << 20, Bytes: true},
+			{Name: "WAL records", Value: 20},
+		},
+	}
+
+	first := current.Result(nil)
+	require.Len(t, first.Rows, 3)
+	assert.Equal(t, "12", first.Rows[0][WALTotalColumn])
+	assert.Empty(t, first.Rows[0][WALDeltaColumn])
+
+	result := current.Result(previous)
+	require.Len(t, result.Rows, 3)
+
+	assert.Equal(t, "+2", result.Rows[0][WALDeltaColumn])
+	assert.Equal(t, "0.2/s", result.Rows[0][WALPerSecondColumn])
+
+	assert.Equal(t, "11.0 MB", result.Rows[1][WALTotalColumn])
+	assert.Equal(t, "+10.0 MB", result.Rows[1][WALDeltaColumn])
+	assert.Equal(t, "1.0 MB/s", result.Rows[1][WALPerSecondColumn])
+
+	assert.Equal(t, "reset", result.Rows[2][WALDeltaColumn])
+	assert.Empty(t, result.Rows[2][WALPerSecondColumn])
+}
@@ -0,0 +1,72 @@
+// Package textsearch finds the lines of a file containing a text, the way
+// grep -i -F does, to search the stored exports and snippets.
+package textsearch
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// excerptWidth is the most characters of a line shown around a match, so a
+// long line such as a JSON export written on one line shows the match
+const excerptWidth = 120
+
+// Match is a line containing the searched text
+type Match struct {
+	Line int    // counted from 1
+	Text string // the line, or the part of it around the match
+}
+
+// Lines returns the lines of content containing text, ignoring case. At most
+// limit lines are returned when limit is positive.
+func Lines(content, text string, limit int) []Match {
+	text = strings.ToLower(text)
+	if text == "" {
+		return nil
+	}
+
+	var matches []Match
+
+	for i, line := range strings.Split(content, "\n") {
+		index := strings.Index(strings.ToLower(line), text)
+		if index < 0 {
+			continue
+		}
+
+		matches = append(matches, Match{Line: i + 1, Text: excerpt(line, index)})
+
+		if limit > 0 && len(matches) == limit {
+			break
+		}
+	}
+
+	return matches
+}
+
+// excerpt returns the line without its indentation, cut to the characters
+// around the byte index of the match when it is too long
+func excerpt(line string, index int) string {
+	line = strings.TrimRight(line, " \t\r")
+	trimmed := strings.TrimLeft(line, " \t")
+	index -= len(line) - len(trimmed)
+
+	if utf8.RuneCountInString(trimmed) <= excerptWidth {
+		return trimmed
+	}
+
+	// start a third of the width before the match
+	runes := []rune(trimmed)
+	start := max(0, utf8.RuneCountInString(trimmed[:min(max(0, index), len(trimmed))])-excerptWidth/3)
+	end := min(len(runes), start+excerptWidth)
+	start = max(0, end-excerptWidth)
+
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+
+	return result
+}
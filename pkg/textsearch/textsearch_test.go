@@ -0,0 +1,65 @@
+package textsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	content := `[
+  {"id": 1, "invoice": "INV-1234"},
+  {"id": 2, "invoice": "INV-5678"},
+  {"id": 3, "note": "see inv-1234"}
+]`
+
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []Match
+	}{
+		{
+			name: "ignores case",
+			text: "inv-1234",
+			want: []Match{
+				{Line: 2, Text: `{"id": 1, "invoice": "INV-1234"},`},
+				{Line: 4, Text: `{"id": 3, "note": "see inv-1234"}`},
+			},
+		},
+		{
+			name:  "stops at the limit",
+			text:  "invoice",
+			limit: 1,
+			want:  []Match{{Line: 2, Text: `{"id": 1, "invoice": "INV-1234"},`}},
+		},
+		{name: "no match", text: "9999", want: nil},
+		{name: "empty text", text: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Lines(content, tt.text, tt.limit))
+		})
+	}
+}
+
+func TestLinesLongLine(t *testing.T) {
+	t.Parallel()
+
+	line := strings.Repeat("a", 300) + "needle" + strings.Repeat("b", 300)
+
+	matches := Lines(line, "NEEDLE", 0)
+
+	if assert.Len(t, matches, 1) {
+		text := matches[0].Text
+		assert.Contains(t, text, "needle")
+		assert.True(t, strings.HasPrefix(text, "…"))
+		assert.True(t, strings.HasSuffix(text, "…"))
+		assert.Equal(t, excerptWidth+2, len([]rune(text)))
+	}
+}
@@ -0,0 +1,83 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const githubReleaseTagURL = "https://api.github.com/repos/ionut-t/perp/releases/tags/"
+
+// ReleaseNotes is the changelog of a single release
+type ReleaseNotes struct {
+	Version     string
+	Name        string
+	URL         string
+	PublishedAt time.Time
+	Body        string
+}
+
+// FetchReleaseNotes fetches the changelog for the given version.
+// Development builds get the notes of the latest release.
+func FetchReleaseNotes(ctx context.Context, version string) (*ReleaseNotes, error) {
+	checker := &Checker{httpClient: &http.Client{Timeout: 10 * time.Second}}
+
+	return checker.fetchReleaseNotes(ctx, releaseNotesURL(version))
+}
+
+func releaseNotesURL(version string) string {
+	if version == "" || version == "dev" {
+		return githubAPIURL
+	}
+
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	return githubReleaseTagURL + url.PathEscape(version)
+}
+
+func (c *Checker) fetchReleaseNotes(ctx context.Context, url string) (*ReleaseNotes, error) {
+	release, err := c.fetchRelease(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release notes: %w", err)
+	}
+
+	return &ReleaseNotes{
+		Version:     release.TagName,
+		Name:        release.Name,
+		URL:         release.ReleaseURL,
+		PublishedAt: release.PublishedAt,
+		Body:        release.Body,
+	}, nil
+}
+
+// Markdown renders the release notes as a markdown document
+func (n ReleaseNotes) Markdown() string {
+	var sb strings.Builder
+
+	title := n.Name
+	if title == "" {
+		title = n.Version
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+
+	if !n.PublishedAt.IsZero() {
+		fmt.Fprintf(&sb, "_Released %s_\n\n", n.PublishedAt.Format("02/01/2006"))
+	}
+
+	body := strings.TrimSpace(n.Body)
+	if body == "" {
+		body = "No changelog was published for this release."
+	}
+	sb.WriteString(body)
+
+	if n.URL != "" {
+		fmt.Fprintf(&sb, "\n\n---\n\n%s", n.URL)
+	}
+
+	return sb.String()
+}
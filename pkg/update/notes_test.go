@@ -0,0 +1,81 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseNotesURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{version: "dev", expected: githubAPIURL},
+		{version: "", expected: githubAPIURL},
+		{version: "v1.2.0", expected: githubReleaseTagURL + "v1.2.0"},
+		{version: "1.2.0", expected: githubReleaseTagURL + "v1.2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, releaseNotesURL(tt.version))
+		})
+	}
+}
+
+func TestFetchReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tags/v1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(release{
+			TagName:    "v1.2.0",
+			Name:       "perp v1.2.0",
+			ReleaseURL: "https://github.com/ionut-t/perp/releases/tag/v1.2.0",
+			Body:       "## Features\n\n- Release notes viewer",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Checker{httpClient: server.Client()}
+
+	notes, err := c.fetchReleaseNotes(context.Background(), server.URL+"/tags/v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", notes.Version)
+	assert.Contains(t, notes.Body, "Release notes viewer")
+
+	_, err = c.fetchReleaseNotes(context.Background(), server.URL+"/tags/v9.9.9")
+	assert.ErrorContains(t, err, "failed to fetch release notes")
+}
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	t.Parallel()
+
+	notes := ReleaseNotes{
+		Version:     "v1.2.0",
+		URL:         "https://github.com/ionut-t/perp/releases/tag/v1.2.0",
+		PublishedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Body:        "- Release notes viewer\n",
+	}
+
+	md := notes.Markdown()
+	assert.Contains(t, md, "# v1.2.0\n\n_Released 01/03/2026_")
+	assert.Contains(t, md, "- Release notes viewer")
+	assert.Contains(t, md, notes.URL)
+
+	assert.Contains(t, ReleaseNotes{Name: "perp v1.0.0"}.Markdown(), "No changelog was published")
+}
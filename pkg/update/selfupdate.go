@@ -0,0 +1,446 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	binaryName     = "perp"
+	backupSuffix   = ".old"
+	checksumsName  = "checksums.txt"
+	maxAssetSize   = 200 << 20
+	downloadPeriod = 5 * time.Minute
+)
+
+// asset is a file attached to a GitHub release
+type asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Updater downloads and installs the latest release over the running binary
+type Updater struct {
+	currentVersion string
+	executable     string
+	goos, goarch   string
+	httpClient     *http.Client
+	releaseURL     string
+}
+
+// InstallResult describes a successful self-update
+type InstallResult struct {
+	Version    string
+	Executable string
+	Backup     string
+}
+
+// NewUpdater creates an updater for the running executable
+func NewUpdater(currentVersion string) (*Updater, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the running executable: %w", err)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	return &Updater{
+		currentVersion: currentVersion,
+		executable:     executable,
+		goos:           runtime.GOOS,
+		goarch:         runtime.GOARCH,
+		httpClient:     &http.Client{Timeout: downloadPeriod},
+		releaseURL:     githubAPIURL,
+	}, nil
+}
+
+// Install downloads the latest release, verifies it and atomically replaces
+// the running executable. The previous binary is kept as a backup and
+// restored if the new one cannot be installed or fails to start.
+func (u *Updater) Install(ctx context.Context) (*InstallResult, error) {
+	if u.currentVersion == "dev" {
+		return nil, errors.New("development builds cannot be updated; install a release instead")
+	}
+
+	checker := &Checker{currentVersion: u.currentVersion, httpClient: u.httpClient}
+	release, err := checker.fetchRelease(ctx, u.releaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !checker.compareVersions(release.TagName) {
+		return nil, fmt.Errorf("perp %s is already the latest version", u.currentVersion)
+	}
+
+	archive, err := selectAsset(release.Assets, u.goos, u.goarch)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := u.download(ctx, archive.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.verify(ctx, release.Assets, archive.Name, data); err != nil {
+		return nil, err
+	}
+
+	binary, err := extractBinary(archive.Name, data, u.goos)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := replaceExecutable(u.executable, binary)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := smokeTest(ctx, u.executable); err != nil {
+		if rollbackErr := restoreBackup(u.executable, backup); rollbackErr != nil {
+			return nil, fmt.Errorf("new version failed to start (%w) and rollback failed: %v", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("new version failed to start, previous version restored: %w", err)
+	}
+
+	return &InstallResult{
+		Version:    release.TagName,
+		Executable: u.executable,
+		Backup:     backup,
+	}, nil
+}
+
+// Rollback restores the binary that was replaced by the last update
+func (u *Updater) Rollback() error {
+	backup := u.executable + backupSuffix
+	if _, err := os.Stat(backup); err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no previous version to roll back to")
+		}
+		return err
+	}
+
+	return restoreBackup(u.executable, backup)
+}
+
+func (u *Updater) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "perp-updater")
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s returned status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	return data, nil
+}
+
+// verify checks the SHA-256 checksum of the archive against the checksums
+// file of the release. The file is not signed, so this catches corrupted
+// downloads but not a tampered release.
+func (u *Updater) verify(ctx context.Context, assets []asset, name string, data []byte) error {
+	checksumsAsset, ok := findAsset(assets, func(n string) bool { return strings.HasSuffix(n, checksumsName) })
+	if !ok {
+		return errors.New("release has no checksums file; refusing to install an unverified binary")
+	}
+
+	checksums, err := u.download(ctx, checksumsAsset.DownloadURL)
+	if err != nil {
+		return err
+	}
+
+	return verifyChecksum(checksums, name, data)
+}
+
+// selectAsset picks the archive built for the given platform
+func selectAsset(assets []asset, goos, goarch string) (asset, error) {
+	osNames := []string{goos}
+	if goos == "darwin" {
+		osNames = append(osNames, "macos")
+	}
+
+	archNames := []string{goarch}
+	switch goarch {
+	case "amd64":
+		archNames = append(archNames, "x86_64")
+	case "arm64":
+		archNames = append(archNames, "aarch64")
+	case "386":
+		archNames = append(archNames, "i386")
+	}
+
+	matches := func(name string, candidates []string) bool {
+		for _, candidate := range candidates {
+			if strings.Contains(name, strings.ToLower(candidate)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, a := range assets {
+		name := strings.ToLower(a.Name)
+		if !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".zip") {
+			continue
+		}
+
+		if matches(name, osNames) && matches(name, archNames) {
+			return a, nil
+		}
+	}
+
+	return asset{}, fmt.Errorf("no release asset found for %s/%s", goos, goarch)
+}
+
+func findAsset(assets []asset, match func(name string) bool) (asset, bool) {
+	for _, a := range assets {
+		if match(a.Name) {
+			return a, true
+		}
+	}
+	return asset{}, false
+}
+
+// verifyChecksum compares data against its entry in a sha256sum-style checksums file
+func verifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		expected, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return fmt.Errorf("invalid checksum for %s: %w", name, err)
+		}
+
+		actual := sha256.Sum256(data)
+		if !bytes.Equal(expected, actual[:]) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no checksum found for %s", name)
+}
+
+// extractBinary returns the perp executable from a .tar.gz or .zip archive
+func extractBinary(archiveName string, data []byte, goos string) ([]byte, error) {
+	name := binaryName
+	if goos == "windows" {
+		name += ".exe"
+	}
+
+	if strings.HasSuffix(strings.ToLower(archiveName), ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+		}
+
+		for _, file := range reader.File {
+			if filepath.Base(file.Name) != name || file.FileInfo().IsDir() {
+				continue
+			}
+
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			//nolint:errcheck
+			defer rc.Close()
+
+			return io.ReadAll(io.LimitReader(rc, maxAssetSize))
+		}
+
+		return nil, fmt.Errorf("%s not found in %s", name, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+	}
+
+	//nolint:errcheck
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archiveName, err)
+		}
+
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == name {
+			return io.ReadAll(io.LimitReader(tr, maxAssetSize))
+		}
+	}
+
+	return nil, fmt.Errorf("%s not found in %s", name, archiveName)
+}
+
+// replaceExecutable writes binary next to executable and renames it over
+// the executable in one step, so the path always holds a complete binary. The
+// previous binary is kept as a backup: a hard link to it, or a copy when the
+// file system has no hard links. Windows refuses to replace a running
+// executable but lets it be renamed, so there it is moved to the backup first.
+func replaceExecutable(executable string, binary []byte) (string, error) {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", executable, err)
+	}
+
+	dir := filepath.Dir(executable)
+	tmp, err := os.CreateTemp(dir, "."+binaryName+"-update-*")
+	if err != nil {
+		return "", fmt.Errorf("cannot write to %s (try running with the permissions used to install perp): %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+
+	backup := executable + backupSuffix
+	_ = os.Remove(backup)
+
+	keepBackup := linkOrCopy
+	if runtime.GOOS == "windows" {
+		keepBackup = os.Rename
+	}
+
+	if err := keepBackup(executable, backup); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to back up current binary: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, executable); err != nil {
+		_ = os.Remove(tmpPath)
+
+		// the executable is untouched unless it was moved to the backup
+		if _, statErr := os.Stat(executable); statErr == nil {
+			_ = os.Remove(backup)
+		} else if restoreErr := os.Rename(backup, executable); restoreErr != nil {
+			return "", fmt.Errorf("failed to install new binary (%w) and to restore the previous one: %v", err, restoreErr)
+		}
+
+		return "", fmt.Errorf("failed to install new binary: %w", err)
+	}
+
+	return backup, nil
+}
+
+// linkOrCopy makes dst a hard link to src, or a copy of it with the same
+// permissions when hard links are not supported
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+
+	return nil
+}
+
+// restoreBackup moves the backup over the executable
+func restoreBackup(executable, backup string) error {
+	if err := os.Rename(backup, executable); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", backup, err)
+	}
+	return nil
+}
+
+// smokeTest checks that the installed binary starts
+func smokeTest(ctx context.Context, executable string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if out, err := exec.CommandContext(ctx, executable, "--version").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
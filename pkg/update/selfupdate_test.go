@@ -0,0 +1,278 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectAsset(t *testing.T) {
+	t.Parallel()
+
+	assets := []asset{
+		{Name: "checksums.txt"},
+		{Name: "perp_1.2.0_Linux_x86_64.tar.gz"},
+		{Name: "perp_1.2.0_Darwin_arm64.tar.gz"},
+		{Name: "perp_1.2.0_Windows_x86_64.zip"},
+	}
+
+	tests := []struct {
+		goos, goarch string
+		expected     string
+		expectError  bool
+	}{
+		{goos: "linux", goarch: "amd64", expected: "perp_1.2.0_Linux_x86_64.tar.gz"},
+		{goos: "darwin", goarch: "arm64", expected: "perp_1.2.0_Darwin_arm64.tar.gz"},
+		{goos: "windows", goarch: "amd64", expected: "perp_1.2.0_Windows_x86_64.zip"},
+		{goos: "linux", goarch: "riscv64", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := selectAsset(assets, tt.goos, tt.goarch)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, a.Name)
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("binary")
+	checksums := []byte(checksumLine("perp_linux_amd64.tar.gz", data) + "\n")
+
+	assert.NoError(t, verifyChecksum(checksums, "perp_linux_amd64.tar.gz", data))
+	assert.ErrorContains(t, verifyChecksum(checksums, "perp_linux_amd64.tar.gz", []byte("tampered")), "mismatch")
+	assert.ErrorContains(t, verifyChecksum(checksums, "perp_darwin_arm64.tar.gz", data), "no checksum")
+}
+
+func TestExtractBinaryTarGz(t *testing.T) {
+	t.Parallel()
+
+	archive := tarGz(t, map[string]string{
+		"README.md": "docs",
+		"perp":      "binary",
+	})
+
+	binary, err := extractBinary("perp_linux_amd64.tar.gz", archive, "linux")
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(binary))
+
+	_, err = extractBinary("perp_linux_amd64.tar.gz", tarGz(t, map[string]string{"other": "x"}), "linux")
+	assert.Error(t, err)
+}
+
+func TestReplaceExecutableAndRestore(t *testing.T) {
+	t.Parallel()
+
+	executable := filepath.Join(t.TempDir(), "perp")
+	require.NoError(t, os.WriteFile(executable, []byte("old"), 0o755))
+
+	backup, err := replaceExecutable(executable, []byte("new"))
+	require.NoError(t, err)
+
+	assertFile(t, executable, "new")
+	assertFile(t, backup, "old")
+
+	require.NoError(t, restoreBackup(executable, backup))
+	assertFile(t, executable, "old")
+	assert.NoFileExists(t, backup)
+}
+
+func TestReplaceExecutableLinksBackup(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the running executable is moved to the backup on Windows")
+	}
+
+	executable := filepath.Join(t.TempDir(), "perp")
+	require.NoError(t, os.WriteFile(executable, []byte("old"), 0o755))
+
+	previous, err := os.Stat(executable)
+	require.NoError(t, err)
+
+	backup, err := replaceExecutable(executable, []byte("new"))
+	require.NoError(t, err)
+
+	kept, err := os.Stat(backup)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(previous, kept), "the backup is a hard link to the previous binary")
+
+	installed, err := os.Stat(executable)
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(previous, installed), "the new binary is renamed over the executable")
+	assert.NotZero(t, installed.Mode().Perm()&0o111)
+
+	entries, err := os.ReadDir(filepath.Dir(executable))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary file is left behind")
+}
+
+func TestInstall(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test binary is a shell script")
+	}
+
+	tests := []struct {
+		name        string
+		binary      string
+		checksum    []byte
+		expectError string
+		expected    string
+	}{
+		{
+			name:     "installs verified release",
+			binary:   "#!/bin/sh\nexit 0\n",
+			expected: "#!/bin/sh\nexit 0\n",
+		},
+		{
+			name:        "rejects checksum mismatch",
+			binary:      "#!/bin/sh\nexit 0\n",
+			checksum:    []byte("something else"),
+			expectError: "checksum mismatch",
+			expected:    "old",
+		},
+		{
+			name:        "rolls back when the new binary fails to start",
+			binary:      "#!/bin/sh\nexit 1\n",
+			expectError: "previous version restored",
+			expected:    "old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assetName := fmt.Sprintf("perp_1.2.0_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+			archive := tarGz(t, map[string]string{"perp": tt.binary})
+
+			checksummed := archive
+			if tt.checksum != nil {
+				checksummed = tt.checksum
+			}
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/release":
+					_ = json.NewEncoder(w).Encode(release{
+						TagName: "v1.2.0",
+						Assets: []asset{
+							{Name: assetName, DownloadURL: server.URL + "/archive"},
+							{Name: "checksums.txt", DownloadURL: server.URL + "/checksums"},
+						},
+					})
+				case "/archive":
+					_, _ = w.Write(archive)
+				case "/checksums":
+					_, _ = w.Write([]byte(checksumLine(assetName, checksummed)))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			old := "old"
+			executable := filepath.Join(t.TempDir(), "perp")
+			require.NoError(t, os.WriteFile(executable, []byte(old), 0o755))
+
+			u := &Updater{
+				currentVersion: "v1.0.0",
+				executable:     executable,
+				goos:           runtime.GOOS,
+				goarch:         runtime.GOARCH,
+				httpClient:     server.Client(),
+				releaseURL:     server.URL + "/release",
+			}
+
+			result, err := u.Install(context.Background())
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "v1.2.0", result.Version)
+				assertFile(t, result.Backup, old)
+			}
+
+			assertFile(t, executable, tt.expected)
+		})
+	}
+}
+
+func TestInstallRejectsDevBuild(t *testing.T) {
+	t.Parallel()
+
+	u := &Updater{currentVersion: "dev"}
+	_, err := u.Install(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRollbackWithoutBackup(t *testing.T) {
+	t.Parallel()
+
+	u := &Updater{executable: filepath.Join(t.TempDir(), "perp")}
+	assert.ErrorContains(t, u.Rollback(), "no previous version")
+}
+
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + name
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
@@ -1,6 +1,7 @@
 package update
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ type release struct {
 	PublishedAt time.Time `json:"published_at"`
 	ReleaseURL  string    `json:"html_url"`
 	Body        string    `json:"body"`
+	Assets      []asset   `json:"assets"`
 }
 
 // updateCheck represents the last update check information
@@ -157,7 +159,12 @@ func (c *Checker) shouldCheckForUpdate() bool {
 
 // getLatestRelease fetches the latest release information from GitHub
 func (c *Checker) getLatestRelease() (*release, error) {
-	req, err := http.NewRequest("GET", githubAPIURL, nil)
+	return c.fetchRelease(context.Background(), githubAPIURL)
+}
+
+// fetchRelease fetches release information from the GitHub API url
+func (c *Checker) fetchRelease(ctx context.Context, url string) (*release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -0,0 +1,198 @@
+// Package workspace stores named snapshots of a session: the connected
+// server, the editor buffer, the layout and the last executed query.
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const fileName = "workspaces.json"
+
+// Focus values of a layout
+const (
+	FocusEditor  = "editor"
+	FocusResults = "results"
+)
+
+// Layout is how the main view was arranged when the workspace was saved
+type Layout struct {
+	FullScreen bool   `json:"fullScreen"`
+	Focus      string `json:"focus"`
+}
+
+// Workspace is a named snapshot of a session
+type Workspace struct {
+	Name        string    `json:"name"`
+	Server      string    `json:"server"`
+	Buffer      string    `json:"buffer"`
+	Layout      Layout    `json:"layout"`
+	LastQuery   string    `json:"lastQuery,omitempty"` // query of the last results
+	LastQueryAt time.Time `json:"lastQueryAt,omitzero"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type workspaces struct {
+	Active     string      `json:"active"`
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// Load returns the saved workspaces sorted by name and the name of the
+// active one. A missing file is not an error and returns no workspaces.
+func Load(storage string) ([]Workspace, string, error) {
+	w, err := read(storage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return w.Workspaces, w.Active, nil
+}
+
+// Save stores the workspace, replacing the one with the same name, makes it
+// the active workspace and returns the updated list.
+func Save(storage string, workspace Workspace) ([]Workspace, error) {
+	workspace.Name = strings.TrimSpace(workspace.Name)
+	if workspace.Name == "" {
+		return nil, fmt.Errorf("a workspace needs a name")
+	}
+
+	w, err := read(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace.UpdatedAt = time.Now()
+
+	w.Workspaces = slices.DeleteFunc(w.Workspaces, func(ws Workspace) bool {
+		return ws.Name == workspace.Name
+	})
+	w.Workspaces = append(w.Workspaces, workspace)
+	w.Active = workspace.Name
+
+	if err := write(storage, w); err != nil {
+		return nil, err
+	}
+
+	return w.Workspaces, nil
+}
+
+// SetActive marks the workspace restored on the next start. An empty name
+// clears it.
+func SetActive(storage, name string) error {
+	w, err := read(storage)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		if _, ok := Find(w.Workspaces, name); !ok {
+			return fmt.Errorf("workspace %q not found", name)
+		}
+	}
+
+	w.Active = name
+
+	return write(storage, w)
+}
+
+// Delete removes the workspace and returns the updated list
+func Delete(storage, name string) ([]Workspace, error) {
+	w, err := read(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := Find(w.Workspaces, name); !ok {
+		return nil, fmt.Errorf("workspace %q not found", name)
+	}
+
+	w.Workspaces = slices.DeleteFunc(w.Workspaces, func(ws Workspace) bool {
+		return ws.Name == name
+	})
+	if w.Active == name {
+		w.Active = ""
+	}
+
+	if err := write(storage, w); err != nil {
+		return nil, err
+	}
+
+	return w.Workspaces, nil
+}
+
+// Find returns the workspace with the given name
+func Find(workspaces []Workspace, name string) (Workspace, bool) {
+	i := slices.IndexFunc(workspaces, func(ws Workspace) bool {
+		return ws.Name == name
+	})
+	if i == -1 {
+		return Workspace{}, false
+	}
+
+	return workspaces[i], true
+}
+
+// Names returns the names of the workspaces
+func Names(workspaces []Workspace) []string {
+	names := make([]string, len(workspaces))
+	for i, ws := range workspaces {
+		names[i] = ws.Name
+	}
+	return names
+}
+
+func read(storage string) (workspaces, error) {
+	w := workspaces{Workspaces: []Workspace{}}
+
+	data, err := os.ReadFile(filepath.Join(storage, fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return w, nil
+		}
+		return w, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &w); err != nil {
+		return w, fmt.Errorf("failed to parse workspaces: %w", err)
+	}
+
+	slices.SortFunc(w.Workspaces, func(a, b Workspace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return w, nil
+}
+
+// write performs an atomic write of the workspaces file
+func write(storage string, w workspaces) error {
+	if err := os.MkdirAll(storage, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	slices.SortFunc(w.Workspaces, func(a, b Workspace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode workspaces: %w", err)
+	}
+
+	path := filepath.Join(storage, fileName)
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write workspaces: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to replace workspaces file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+
+	workspaces, active, err := Load(storage)
+	require.NoError(t, err)
+	assert.Empty(t, workspaces)
+	assert.Empty(t, active)
+
+	_, err = Save(storage, Workspace{Name: "reports", Server: "staging", Buffer: "SELECT 1;"})
+	require.NoError(t, err)
+
+	_, err = Save(storage, Workspace{
+		Name:      "billing",
+		Server:    "production",
+		Layout:    Layout{FullScreen: true, Focus: FocusResults},
+		LastQuery: "SELECT * FROM invoices",
+	})
+	require.NoError(t, err)
+
+	workspaces, active, err = Load(storage)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing", "reports"}, Names(workspaces))
+	assert.Equal(t, "billing", active)
+
+	billing, ok := Find(workspaces, "billing")
+	require.True(t, ok)
+	assert.Equal(t, Layout{FullScreen: true, Focus: FocusResults}, billing.Layout)
+	assert.Equal(t, "SELECT * FROM invoices", billing.LastQuery)
+	assert.False(t, billing.UpdatedAt.IsZero())
+}
+
+func TestSaveReplacesSameName(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+
+	_, err := Save(storage, Workspace{Name: "reports", Buffer: "SELECT 1;"})
+	require.NoError(t, err)
+
+	workspaces, err := Save(storage, Workspace{Name: " reports ", Buffer: "SELECT 2;"})
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	assert.Equal(t, "SELECT 2;", workspaces[0].Buffer)
+
+	_, err = Save(storage, Workspace{Name: "  "})
+	assert.Error(t, err)
+}
+
+func TestSetActiveAndDelete(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+
+	_, err := Save(storage, Workspace{Name: "reports"})
+	require.NoError(t, err)
+	_, err = Save(storage, Workspace{Name: "billing"})
+	require.NoError(t, err)
+
+	require.NoError(t, SetActive(storage, "reports"))
+	assert.Error(t, SetActive(storage, "missing"))
+
+	_, active, err := Load(storage)
+	require.NoError(t, err)
+	assert.Equal(t, "reports", active)
+
+	workspaces, err := Delete(storage, "reports")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing"}, Names(workspaces))
+
+	_, active, err = Load(storage)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	_, err = Delete(storage, "reports")
+	assert.Error(t, err)
+}
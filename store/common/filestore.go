@@ -30,6 +30,9 @@ type FileStore[T FileItem] struct {
 	LoadFromFile   LoadFileFunc[T]
 	ValidateName   ValidateNameFunc
 	GenerateUnique func(existingNames []string, name, oldName string) string
+	// Ignore, when set, skips the files it reports by name, e.g. files
+	// describing the items
+	Ignore func(name string) bool
 
 	// Mutable fields (protected by mu)
 	mu       sync.RWMutex
@@ -211,7 +214,7 @@ func (s *FileStore[T]) load() error {
 			return err
 		}
 
-		if d.IsDir() {
+		if d.IsDir() || s.Ignore != nil && s.Ignore(d.Name()) {
 			return nil
 		}
 
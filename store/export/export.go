@@ -2,11 +2,13 @@ package export
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	pkgExport "github.com/ionut-t/perp/pkg/export"
 	"github.com/ionut-t/perp/pkg/utils"
 	"github.com/ionut-t/perp/store/common"
 )
@@ -15,6 +17,7 @@ type Record struct {
 	Name      string
 	Content   string
 	UpdatedAt time.Time
+	Metadata  *pkgExport.Metadata // where the rows came from, nil for older exports
 }
 
 // Implement FileItem interface
@@ -34,15 +37,16 @@ type Store interface {
 }
 
 func New(storage, editor string) *store {
-	return &store{
-		FileStore: common.NewFileStore(
-			storage,
-			editor,
-			loadRecordFromFile,
-			validateRecordName,
-			utils.GenerateUniqueName,
-		),
-	}
+	fileStore := common.NewFileStore(
+		storage,
+		editor,
+		loadRecordFromFile,
+		validateRecordName,
+		utils.GenerateUniqueName,
+	)
+	fileStore.Ignore = pkgExport.IsMetadata
+
+	return &store{FileStore: fileStore}
 }
 
 type store struct {
@@ -71,6 +75,11 @@ func (s *store) Delete(record Record) error {
 		return err
 	}
 
+	metadataPath := pkgExport.MetadataPath(s.GetPath(record))
+	if err := os.Remove(metadataPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
 	// Update current record if we just deleted it
 	itemsMap := s.GetItemsMap()
 	if s.currentRecordName == record.Name {
@@ -97,10 +106,26 @@ func (s *store) Delete(record Record) error {
 // Rename changes the record name and updates current if needed
 func (s *store) Rename(record *Record, newName string) error {
 	oldName := record.Name
+	oldMetadataPath := pkgExport.MetadataPath(s.GetPath(*record))
+
 	if err := s.FileStore.Rename(record, newName); err != nil {
 		return err
 	}
 
+	// the description follows the export, read again once it is moved
+	err := os.Rename(oldMetadataPath, pkgExport.MetadataPath(s.GetPath(*record)))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// exported before the descriptions were written
+	case err != nil:
+		return err
+	default:
+		if _, err := s.FileStore.Load(); err != nil {
+			return err
+		}
+		*record = s.GetItemsMap()[record.Name]
+	}
+
 	// Update current record name if we renamed the current record
 	if s.currentRecordName == oldName {
 		s.currentRecordName = record.Name
@@ -144,15 +169,23 @@ func loadRecordFromFile(path string) (Record, error) {
 		return Record{}, err
 	}
 
+	// an unreadable description leaves the record without one
+	metadata, _ := pkgExport.ReadMetadata(path)
+
 	return Record{
 		Name:      filepath.Base(path),
 		Content:   content,
 		UpdatedAt: fileInfo.ModTime(),
+		Metadata:  metadata,
 	}, nil
 }
 
 // validateRecordName validates and ensures proper extension for record names
 func validateRecordName(oldName, newName string) (string, error) {
+	if pkgExport.IsMetadata(newName) {
+		return "", fmt.Errorf("record names cannot end with %s", pkgExport.MetadataSuffix)
+	}
+
 	ext := filepath.Ext(newName)
 
 	if ext == "" {
@@ -485,6 +485,54 @@ func TestStore_Rename_ErrorOnInvalidNewName(t *testing.T) {
 	}
 }
 
+func TestStore_Metadata(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := setupTestDir(t)
+	defer cleanup()
+	writeTestFile(t, storage, "users.csv", "id\n1", time.Now())
+	writeTestFile(t, storage, "users.csv.meta.json", `{"query": "SELECT id FROM users", "server": "local", "columns": ["id"], "row_count": 1}`, time.Now())
+
+	s := New(storage, "vim")
+	records, err := s.Load()
+	if err != nil {
+		t.Fatalf("unexpected error loading records: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected the description not to be a record, got %d records", len(records))
+	}
+
+	rec := &records[0]
+	if rec.Metadata == nil || rec.Metadata.Query != "SELECT id FROM users" || rec.Metadata.RowCount != 1 {
+		t.Fatalf("expected the description of the export, got %+v", rec.Metadata)
+	}
+
+	if err := s.Rename(rec, "people.csv"); err != nil {
+		t.Fatalf("Rename returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storage, "people.csv.meta.json")); err != nil {
+		t.Errorf("expected the description to be renamed, got err: %v", err)
+	}
+	if rec.Metadata == nil || rec.Metadata.Server != "local" {
+		t.Errorf("expected the renamed record to keep its description, got %+v", rec.Metadata)
+	}
+
+	if err := s.Delete(*rec); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storage, "people.csv.meta.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the description to be deleted, got err: %v", err)
+	}
+}
+
+func TestStore_Rename_RejectsMetadataName(t *testing.T) {
+	t.Parallel()
+
+	if _, err := validateRecordName("users.json", "users.meta.json"); err == nil {
+		t.Error("expected an error for a name ending with .meta.json")
+	}
+}
+
 func TestStore_GetCurrentRecord(t *testing.T) {
 	t.Parallel()
 
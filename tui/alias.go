@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/ionut-t/perp/internal/config"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// commandAliasesFromConfig returns the valid command aliases set in the
+// config file. Invalid aliases are dropped and reported with the error.
+func commandAliasesFromConfig(cfg config.Config) (map[string]string, error) {
+	aliases, err := command.ParseAliases(cfg.CommandAliases())
+	if err != nil {
+		return aliases, fmt.Errorf("%s: %w", config.CommandAliasesKey, err)
+	}
+
+	return aliases, nil
+}
@@ -2,7 +2,9 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -18,42 +20,68 @@ import (
 	"github.com/ionut-t/perp/internal/leader"
 	"github.com/ionut-t/perp/internal/whichkey"
 	"github.com/ionut-t/perp/pkg/clipboard"
+	"github.com/ionut-t/perp/pkg/dataquality"
 	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/examples"
 	"github.com/ionut-t/perp/pkg/history"
 	"github.com/ionut-t/perp/pkg/llm"
 	llmFactory "github.com/ionut-t/perp/pkg/llm/llm_factory"
 	"github.com/ionut-t/perp/pkg/lsp"
+	"github.com/ionut-t/perp/pkg/numfmt"
 	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/pkg/redirect"
+	"github.com/ionut-t/perp/pkg/schemaindex"
 	"github.com/ionut-t/perp/pkg/server"
 	pkgSnippets "github.com/ionut-t/perp/pkg/snippets"
+	"github.com/ionut-t/perp/pkg/stats"
 	"github.com/ionut-t/perp/pkg/update"
 	"github.com/ionut-t/perp/pkg/utils"
+	"github.com/ionut-t/perp/pkg/workspace"
 	exportStore "github.com/ionut-t/perp/store/export"
 	snippetsStore "github.com/ionut-t/perp/store/snippets"
 	"github.com/ionut-t/perp/tui/command"
 	"github.com/ionut-t/perp/tui/content"
+	examplesView "github.com/ionut-t/perp/tui/examples"
 	exportData "github.com/ionut-t/perp/tui/export_data"
+	"github.com/ionut-t/perp/tui/finder"
 	historyView "github.com/ionut-t/perp/tui/history"
 	"github.com/ionut-t/perp/tui/menu"
 	"github.com/ionut-t/perp/tui/prompt"
+	"github.com/ionut-t/perp/tui/querybuilder"
 	"github.com/ionut-t/perp/tui/servers"
 	snippetsView "github.com/ionut-t/perp/tui/snippets"
+	"github.com/ionut-t/perp/ui/confirm"
 	"github.com/ionut-t/perp/ui/help"
 )
 
 type model struct {
-	config          config.Config
-	connectURL      string
-	width, height   int
-	view            view
-	focused         focused
-	serverSelection servers.Model
-	server          server.Server
-	db              db.Database
-	error           error
-	llm             llm.LLM
-	llmError        error
-	editor          editor.Model
+	config           config.Config
+	connectURL       string
+	connectServer    string
+	executeOnConnect bool
+	pinned           *content.Model          // results pinned next to the results pane
+	gexecStatements  []string                // statements generated by \gexec waiting for confirmation
+	editedFunction   string                  // definition changed with \ef waiting for confirmation
+	validation       *dataquality.Validation // NOT VALID constraint waiting for confirmation
+	destructiveQuery string                  // query with destructive statements waiting for confirmation
+	running          *runningQuery           // query run from the editor, cancelled with esc or ctrl+c
+	walStats         *stats.WALStats         // last WAL statistics, the base of the deltas
+	passwordRole     string                  // role whose password is asked by \password
+	workspaces       []workspace.Workspace
+	workspace        string // name of the active workspace
+	restoreQuery     string // query of the last results of a restored workspace
+	width, height    int
+	view             view
+	focused          focused
+	serverSelection  servers.Model
+	server           server.Server
+	db               db.Database
+	stream           *db.Stream // the rows of the shown results left to fetch, with \set FETCH_COUNT
+	reconnecting     bool       // after the connection to the server was lost
+	error            error
+	llm              llm.LLM
+	llmError         error
+	editor           editor.Model
 
 	fullScreen bool
 
@@ -66,6 +94,9 @@ type model struct {
 	content               content.Model
 	help                  help.Model
 	llmSharedTablesSchema []string
+	lastQuery             string   // last query that returned rows, used by /filter
+	lastQueryColumns      []string // columns returned by lastQuery
+	schemaIndex           *schemaindex.Index
 
 	// styles
 	llmKeywords  map[string]lipgloss.Style
@@ -73,6 +104,9 @@ type model struct {
 
 	// commands
 	expandedDisplay bool
+	displayOptions  psql.DisplayOptions
+	variables       psql.Variables  // set with \set and referenced by queries as :name
+	output          redirect.Target // file set with \o the query results are also written to
 
 	// history management
 	historyLogs           []history.Entry
@@ -87,6 +121,12 @@ type model struct {
 	snippets      snippetsView.Model
 	snippetsStore snippetsStore.Store
 
+	// LLM few-shot examples
+	llmExamples         []examples.Example
+	llmExamplesView     examplesView.Model
+	llmAskPrompt        string            // question of the last /ask, used to build example candidates
+	llmExampleCandidate *examples.Example // last query that ran successfully after an /ask
+
 	// navigation components
 	leaderMgr    *leader.Manager
 	whichKeyMenu menu.Model
@@ -95,6 +135,14 @@ type model struct {
 	prompt         prompt.Model
 	isPromptActive bool
 
+	confirm confirm.Model // dialog confirming a destructive query or emptying the trash
+
+	queryBuilder         querybuilder.Model
+	isQueryBuilderActive bool
+
+	finder         finder.Model
+	isFinderActive bool
+
 	styles styles.Styles
 	isDark bool
 
@@ -103,13 +151,14 @@ type model struct {
 	lspCompletionCancel context.CancelFunc // cancels the previous in-flight LSP completion call
 }
 
-func New(config config.Config, url string) model {
+func New(config config.Config, opts Options) model {
 	textEditor := editor.New(80, 10, editor.WithClipboard(&clipboard.Clipboard{}))
 
 	llmKeywordsMap := make(map[string]lipgloss.Style, len(llm.LLMKeywords))
-	psqlCommands := make(map[string]lipgloss.Style, len(psql.PSQL_COMMANDS))
+	psqlCommands := make(map[string]lipgloss.Style, len(psql.PSQL_COMMANDS)+2)
 
 	textEditor.SetPlaceholder("Type your SQL query here...")
+	textEditor.SetContent(opts.Query)
 
 	textEditor.Focus()
 	textEditor.DisableCommandMode(true)
@@ -124,39 +173,72 @@ func New(config config.Config, url string) model {
 		historyLogs = []history.Entry{}
 	}
 
+	workspaces, activeWorkspace, err := workspace.Load(config.Storage())
+	if err != nil {
+		workspaces = []workspace.Workspace{}
+	}
+
+	// connecting from the command line starts outside of any workspace
+	if opts.URL != "" || opts.ServerName != "" {
+		activeWorkspace = ""
+	}
+
 	llm, err := llmFactory.New(context.Background(), config, config.GetLLMInstructions())
 
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 
 	menuRegistry := whichkey.NewRegistry()
+	if usage, err := whichkey.LoadUsage(config.Storage()); err == nil {
+		menuRegistry.SetUsage(usage)
+	}
 
 	globalSnippetsPath := pkgSnippets.GetGlobalSnippetsPath(config.Storage())
 	snippetsStoreInstance := snippetsStore.New(globalSnippetsPath, "", config.Editor())
 
 	m := model{
-		config:          config,
-		connectURL:      url,
-		llm:             llm,
-		editor:          textEditor,
-		llmKeywords:     llmKeywordsMap,
-		psqlCommands:    psqlCommands,
-		command:         command.New(),
-		serverSelection: servers.New(config.Storage()),
-		historyLogs:     historyLogs,
-		content:         content.New(0, 0),
-		help:            help.New(),
-		llmError:        err,
-		spinner:         sp,
-		leaderMgr:       leader.NewManager(LeaderKeyTimeout, config.GetLeaderKey()),
-		whichKeyMenu:    menu.New(menuRegistry.GetRootMenu()),
-		menuRegistry:    menuRegistry,
-		prompt:          prompt.New(),
-		snippetsStore:   snippetsStoreInstance,
+		config:           config,
+		connectURL:       opts.URL,
+		connectServer:    opts.ServerName,
+		executeOnConnect: opts.Execute && opts.Query != "",
+		workspaces:       workspaces,
+		workspace:        activeWorkspace,
+		llm:              llm,
+		editor:           textEditor,
+		llmKeywords:      llmKeywordsMap,
+		psqlCommands:     psqlCommands,
+		command:          command.New(config.Storage()),
+		serverSelection:  servers.New(config.Storage()),
+		historyLogs:      historyLogs,
+		content:          content.New(0, 0),
+		help:             help.New(),
+		llmError:         err,
+		spinner:          sp,
+		leaderMgr:        leader.NewManager(LeaderKeyTimeout, config.GetLeaderKey()),
+		whichKeyMenu:     menu.New(menuRegistry.GetRootMenu()),
+		menuRegistry:     menuRegistry,
+		prompt:           prompt.New(),
+		snippetsStore:    snippetsStoreInstance,
+		variables:        make(psql.Variables),
+		running:          &runningQuery{},
 	}
 
 	m.setStyles(true)
 
+	timeDisplay, _ := timeDisplayFromConfig(config)
+	m.content.SetTimeDisplay(timeDisplay)
+	m.content.SetNumberSeparators(numfmt.FromEnv(os.Getenv))
+
+	highlightRules, _ := highlightRulesFromConfig(config)
+	m.content.SetHighlightRules(highlightRules)
+
+	aliases, _ := commandAliasesFromConfig(config)
+	m.command.SetAliases(aliases)
+
+	m.displayOptions, _ = displayOptionsFromConfig(config)
+	m.expandedDisplay = m.displayOptions.Expanded
+	m.content.SetDisplayOptions(m.displayOptions)
+
 	return m
 }
 
@@ -167,7 +249,39 @@ func (m model) Init() tea.Cmd {
 		m.checkForUpdates(),
 	}
 
-	if m.connectURL != "" {
+	if _, err := timeDisplayFromConfig(m.config); err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return notificationErrorMsg{err: err}
+		})
+	}
+
+	if _, err := highlightRulesFromConfig(m.config); err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return notificationErrorMsg{err: err}
+		})
+	}
+
+	if _, err := commandAliasesFromConfig(m.config); err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return notificationErrorMsg{err: err}
+		})
+	}
+
+	if _, err := displayOptionsFromConfig(m.config); err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return notificationErrorMsg{err: err}
+		})
+	}
+
+	if m.connectServer != "" {
+		cmds = append(cmds, func() tea.Msg {
+			srv, err := server.FindByName(m.config.Storage(), m.connectServer)
+			if err != nil {
+				return notificationErrorMsg{err: err}
+			}
+			return servers.SelectedServerMsg{Server: *srv}
+		})
+	} else if m.connectURL != "" {
 		parsed, err := server.ParseConnectionURI(m.connectURL)
 		if err != nil {
 			cmds = append(cmds, func() tea.Msg {
@@ -186,6 +300,8 @@ func (m model) Init() tea.Cmd {
 				return servers.SelectedServerMsg{Server: srv}
 			})
 		}
+	} else if m.workspace != "" {
+		cmds = append(cmds, whichkey.SwitchWorkspaceCmd(m.workspace))
 	}
 
 	return tea.Batch(cmds...)
@@ -212,7 +328,7 @@ func (m *model) updateSize() {
 			return
 		}
 
-		m.content.SetSize(width, height+1)
+		m.setResultsSize(width, height+1)
 		return
 	}
 
@@ -222,7 +338,7 @@ func (m *model) updateSize() {
 
 	contentHeight := height - editorHeight - commandLineHeight
 
-	m.content.SetSize(width, contentHeight)
+	m.setResultsSize(width, contentHeight)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -251,6 +367,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.snippets.SetSize(width, height)
 		}
 
+		if m.view == viewLLMExamples {
+			m.llmExamplesView.SetSize(width, height)
+		}
+
+	case tea.ResumeMsg:
+		// The terminal may have been resized or re-themed while perp was suspended
+		return m, tea.Batch(tea.RequestWindowSize, tea.RequestBackgroundColor, m.editor.CursorBlink())
+
 	case spinner.TickMsg:
 		if !m.loading {
 			return m, nil
@@ -261,6 +385,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
+		// Any key dismisses the image preview, which is drawn outside the view
+		if m.content.IsImagePreview() && !(msg.Key().Mod == tea.ModCtrl && msg.Key().Code == 'c') {
+			return m, m.closeImagePreview()
+		}
+
+		// Esc and ctrl+c cancel the query being run rather than quitting
+		if m.loading && key.Matches(msg, cancelQuery) && m.running.stop() {
+			return m, nil
+		}
+
+		// The confirmation dialog takes the keys until it is answered
+		if m.confirm.IsActive() {
+			var cmd tea.Cmd
+			m.confirm, cmd = m.confirm.Update(msg)
+			return m, cmd
+		}
+
 		// Priority 1: Which-key menu is showing - let it handle all keys
 		if m.whichKeyMenu.IsVisible() {
 			return m.handleWhichKeyPress(msg)
@@ -286,10 +427,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if msg.Key().Mod == tea.ModCtrl && msg.Key().Code == 'c' {
-			m.closeDbConnection()
 			return m, tea.Quit
 		}
 
+		if key.Matches(msg, keymap.Suspend) {
+			return m, tea.Suspend
+		}
+
 		// In insert mode, submit on enter if query ends with ; or is a psql command
 		if m.editor.IsInsertMode() && key.Matches(msg, keymap.Submit) {
 			content := strings.TrimSpace(m.editor.GetCurrentContent())
@@ -304,7 +448,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.view == viewExportData ||
 			m.view == viewHistory ||
 			m.view == viewSnippets ||
+			m.view == viewLLMExamples ||
 			m.isPromptActive ||
+			m.isQueryBuilderActive ||
+			m.isFinderActive ||
 			!m.editor.IsNormalMode() && m.focused == focusedEditor {
 			break
 		}
@@ -326,7 +473,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.errorNotification(msg.err)
 
 	case editor.CompletionRequestMsg:
+		columns := m.columnCompletions(msg.Context)
+
 		if m.lspClient == nil || !isSQLContent(m.editor.GetCurrentContent()) {
+			if len(columns) > 0 {
+				m.editor.SetCompletions(columns, msg.Context)
+			}
 			return m, nil
 		}
 
@@ -346,6 +498,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			completions, err := client.Completion(ctx, completionCtx.Position.Row, completionCtx.Position.Col)
 			return lspCompletionResultMsg{
 				completions: completions,
+				columns:     columns,
 				context:     completionCtx,
 				err:         err,
 			}
@@ -354,10 +507,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case lspCompletionResultMsg:
 		if msg.err != nil {
 			debug.Printf("LSP completion error: %v", msg.err)
+			if len(msg.columns) > 0 && !errors.Is(msg.err, context.Canceled) {
+				m.editor.SetCompletions(msg.columns, msg.context)
+			}
 			return m, nil
 		}
 
-		m.editor.SetCompletions(msg.completions, msg.context)
+		m.editor.SetCompletions(mergeCompletions(msg.columns, msg.completions), msg.context)
 
 		return m, nil
 
@@ -377,22 +533,83 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case whichkey.DismissUpdateMsg:
 		return m, m.dismissUpdate()
 
+	case whichkey.InstallUpdateMsg:
+		m.loading = true
+		return m, tea.Batch(m.installUpdate(), m.spinner.Tick)
+
+	case whichkey.ShowReleaseNotesMsg:
+		return m.showReleaseNotes()
+
+	case releaseNotesMsg:
+		m.loading = false
+		m.content.SetReleaseNotes(msg.notes.Markdown())
+		if m.view != viewServers {
+			m.view = viewMain
+			m.focused = focusedContent
+			m.editor.Blur()
+		}
+
+	case updateInstalledMsg:
+		m.loading = false
+		m.latestRelease = nil
+		m.content.SetLatestReleaseInfo(nil)
+		return m, tea.Batch(
+			m.successNotification(fmt.Sprintf("perp updated to %s. Restart to use the new version.", msg.version)),
+			m.fetchReleaseNotes(msg.version),
+		)
+
 	case schemaFetchedMsg:
 		schema := string(msg)
 		m.loading = false
 
 		m.content.SetSchema(schema)
 
+		updated, cmd := m.runStartupQuery()
+		return updated, tea.Batch(m.loadSchemaIndex(), cmd)
+
+	case schemaIndexLoadedMsg:
+		m.schemaIndex = msg.index
+
+	case llmRelevantTablesMsg:
+		return m.askWithRelevantTables(msg)
+
 	case schemaFailureMsg:
 		m.loading = false
 		m.content.SetError(msg.err)
 
+		return m.runStartupQuery()
+
 	case executeQueryMsg:
 		return m.handleQueryResult(msg)
 
+	case queryTimingMsg:
+		return m.showQueryTiming(msg)
+
+	case executeStatementsMsg:
+		return m.handleStatementsResult(msg)
+
+	case streamQueryMsg:
+		return m.handleStreamQuery(msg)
+
+	case streamRowsMsg:
+		return m.handleStreamRows(msg)
+
+	case streamIdleMsg:
+		return m.handleStreamIdle(msg)
+
+	case content.FetchMoreRowsMsg:
+		return m.fetchMoreRows()
+
 	case queryFailureMsg:
 		m.loading = false
+		closeCmd := m.closeStream()
+		if m.running.cancelled(msg.err) {
+			return m, tea.Batch(closeCmd, m.warningNotification("Query cancelled"))
+		}
 		m.content.SetError(msg.err)
+		if cmd := tea.Batch(closeCmd, m.reconnectIfLost(msg.err)); cmd != nil {
+			return m, cmd
+		}
 
 	case psqlCommandMsg:
 		m.loading = true
@@ -401,12 +618,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.spinner.Tick,
 		)
 
+	case gexecStatementsMsg:
+		return m.handleGexecStatements(msg)
+
+	case destructiveQueryMsg:
+		return m.confirmDestructiveQuery(msg)
+
+	case confirm.ConfirmedMsg:
+		if msg.ID == destructiveQueryDialog {
+			return m.runDestructiveQuery()
+		}
+
+		if msg.ID == emptyTrashDialog {
+			return m.emptyTrash()
+		}
+
+	case confirm.CancelledMsg:
+		if msg.ID == destructiveQueryDialog {
+			m.destructiveQuery = ""
+			return m, nil
+		}
+
+	case gexecResultMsg:
+		return m.handleGexecResult(msg)
+
+	case command.GexecMsg:
+		return m.runGexec()
+
+	case includeStatementsMsg:
+		return m.runInclude(msg)
+
+	case editFunctionMsg:
+		return m.openFunctionEditor(msg)
+
+	case functionEditedMsg:
+		return m.handleFunctionEdited(msg)
+
+	case command.RunEditedFunctionMsg:
+		return m.runEditedFunction()
+
 	case psqlResultMsg:
 		return m.handlePsqlResult(msg)
 
+	case reconnectMsg:
+		return m.reconnect(msg)
+
+	case reconnectedMsg:
+		return m.handleReconnected(msg)
+
 	case psqlErrorMsg:
 		m.loading = false
+		if m.running.cancelled(msg.err) {
+			return m, m.warningNotification("Command cancelled")
+		}
 		m.content.SetError(msg.err)
+		if cmd := m.reconnectIfLost(msg.err); cmd != nil {
+			return m, cmd
+		}
 
 	case toggleExpandedMsg:
 		return m.toggleExpandedDisplay()
@@ -414,15 +682,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case toggleTimingMsg:
 		return m.toggleQueryTiming()
 
+	case psetMsg:
+		return m.setDisplayOption(msg)
+
+	case variableMsg:
+		return m.setVariable(msg)
+	case passwordMsg:
+		return m.askPassword(msg)
+
+	case command.PasswordMsg:
+		return m.changePassword(msg)
+
+	case passwordChangedMsg:
+		return m.handlePasswordChanged(msg)
+
+	case command.ServerPasswordMsg:
+		return m.saveServerPassword(msg)
+
+	case outputMsg:
+		return m.setOutput(msg)
+
+	case echoMsg:
+		return m.echo(msg)
+
 	case showPsqlHelpMsg:
 		m.loading = false
 		m.content.ShowPsqlHelp()
 		m.focused = focusedContent
-		return m, m.resetEditor()
+		return m, tea.Batch(m.resetEditor(), m.pageLongOutput())
 
 	case llmResponseMsg:
 		m.handleLLMResponse(msg)
 
+	case offlineAnalysisMsg:
+		m.handleOfflineAnalysis(msg)
+
+	case whichkey.ExplainPlanNodeMsg:
+		return m.explainPlanNode()
+
+	case planNodeExplanationMsg:
+		m.handlePlanNodeExplanation(msg)
+
+	case whichkey.SchemaTourMsg:
+		return m.startSchemaTour()
+
+	case schemaTourMsg:
+		return m.handleSchemaTour(msg)
+
 	case llmFailureMsg:
 		m.loading = false
 		m.content.SetError(msg.err)
@@ -437,8 +743,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case content.LLMResponseSelectedMsg:
 		return m.applyLLMResponse(msg)
 
+	case content.OpenCellMsg:
+		return m, m.openCell(msg.Value)
+
+	case content.OpenPagerMsg:
+		return m, m.openPager(msg.Text)
+
+	case content.PreviewCellMsg:
+		return m.previewCell(msg.Value)
+
+	case content.YankGeoJSONMsg:
+		return m, m.yankGeoJSON(msg.Value)
+
+	case content.YankQueryMsg:
+		return m, m.yankQuery(msg.Query)
+
+	case content.YankCodeBlockMsg:
+		return m, m.yankCodeBlock(msg.Code)
+
+	case content.OpenLinkMsg:
+		return m, m.openLink(msg.URL)
+
+	case content.ShowResultInfoMsg:
+		return m, m.showResultInfo(msg.Info)
+
+	case resultInfoMsg:
+		m.handleResultInfo(msg)
+
+	case onConnectMsg:
+		return m, m.handleOnConnect(msg)
+
+	case content.ColumnWidthsChangedMsg:
+		return m, m.saveColumnWidths(msg)
+
+	case content.NumberFormatToggledMsg:
+		if msg.Raw {
+			return m, m.successNotification("Showing raw numbers")
+		}
+		return m, m.successNotification("Showing formatted numbers")
+
+	case imagePreviewMsg:
+		return m.drawImagePreview(msg)
+
 	case command.QuitMsg, psqlQuitMsg:
-		m.closeDbConnection()
 		return m, tea.Quit
 
 	case command.CancelMsg:
@@ -447,6 +794,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case command.ExportMsg:
 		return m.exportQueryData(msg)
 
+	case content.PinResultsMsg:
+		return m.togglePinnedResults()
+
+	case content.ExportSelectionMsg:
+		return m.exportSelection()
+
 	case command.EditorChangedMsg:
 		err := m.config.SetEditor(msg.Editor)
 		if err != nil {
@@ -464,13 +817,69 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case command.LLMModelChangedMsg:
 		return m.updateLLMModel(msg)
 
+	case command.LLMSettingChangedMsg:
+		return m.updateLLMSetting(msg)
+
 	case command.LeaderKeyChangedMsg:
 		return m.updateLeaderKey(msg)
 
 	case command.SaveSnippetMsg:
 		return m.saveSnippet(msg.Name)
 
+	case command.SaveWorkspaceMsg:
+		return m.saveWorkspace(msg.Name)
+
+	case command.PipeMsg:
+		return m.pipeResults(msg)
+
+	case command.MaterializeMsg:
+		return m.materialize(msg)
+
+	case materializedMsg:
+		return m.handleMaterialized(msg)
+
+	case command.ConnectMsg:
+		return m.connectToServer(msg)
+
+	case command.CompareMsg:
+		return m.compareServers(msg)
+
+	case serversComparedMsg:
+		return m.handleServersCompared(msg)
+
+	case command.SyncPreviewMsg:
+		return m.syncPreview(msg)
+
+	case syncPreviewMsg:
+		return m.handleSyncPreview(msg)
+
+	case command.ResetSessionMsg:
+		return m.resetSession()
+
+	case sessionResetMsg:
+		return m.handleSessionReset(msg)
+
+	case command.TimeZoneMsg:
+		return m.setTimeZone(msg)
+
+	case command.KeywordCaseMsg:
+		return m.changeKeywordCase(msg)
+
+	case command.JoinMsg:
+		return m.addJoin(msg)
+
+	case joinFoundMsg:
+		return m.handleJoinFound(msg)
+
+	case pipeOutputMsg:
+		return m.handlePipeOutput(msg)
+
 	case command.ErrorMsg:
+		if m.focused == focusedCommand {
+			m.command.SetError(msg.Err)
+			m.updateSize()
+			return m, nil
+		}
 		return m, m.errorNotification(msg.Err)
 
 	case historyView.SelectedMsg:
@@ -487,7 +896,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case whichkey.ExecuteAndCloseMsg:
 		m.leaderMgr.Reset()
-		return m, utils.Dispatch(msg.ActionMsg)
+		return m, tea.Batch(m.recordMenuUse(msg.Menu, msg.Keys), utils.Dispatch(msg.ActionMsg))
 
 	// Which-key menu action handlers
 	case whichkey.ShowServersViewMsg:
@@ -514,7 +923,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.content.ShowDBSchema()
 			contentModel, cmd := m.content.Update(nil)
 			m.content = contentModel
-			return m, cmd
+			return m, tea.Batch(cmd, m.pageLongOutput())
 		}
 		return m, nil
 
@@ -547,7 +956,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case whichkey.ToggleFullscreenMsg:
-		if m.editor.IsNormalMode() || m.focused == focusedContent {
+		if m.editor.IsNormalMode() || m.focused == focusedContent || m.focused == focusedPinned {
 			m.fullScreen = !m.fullScreen
 			m.updateSize()
 			contentModel, cmd := m.content.Update(content.ResizeMsg{})
@@ -559,6 +968,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case whichkey.ToggleHelpMsg:
 		m.handleHelpToggle()
 
+	case whichkey.OpenInPaneMsg:
+		return m, m.openInPane()
+
+	case whichkey.ExportSelectionMsg:
+		return m.exportSelection()
+
+	case whichkey.YankResultsMsg:
+		return m, m.yankResults(msg.Format)
+
+	case whichkey.YankResultsQueryMsg:
+		if query, _ := m.content.ExecutedQuery(); query != "" {
+			return m, m.yankQuery(query)
+		}
+		return m, nil
+
+	case whichkey.ShowResultInfoMsg:
+		if info, ok := m.content.ResultInfo(); ok {
+			return m, m.showResultInfo(info)
+		}
+		return m, nil
+
+	case whichkey.PinResultsMsg:
+		return m.togglePinnedResults()
+
+	case whichkey.ToggleRawNumbersMsg:
+		raw := m.content.ToggleRawNumbers()
+		return m, func() tea.Msg {
+			return content.NumberFormatToggledMsg{Raw: raw}
+		}
+
 	case whichkey.ExportJSONMsg:
 		m.isPromptActive = true
 		m.prompt.SetAction(prompt.ExportAllAsJSONAction)
@@ -587,6 +1026,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.isPromptActive = true
 		m.prompt.SetAction(prompt.SaveSnippetAction)
 
+	case whichkey.SaveWorkspaceMsg:
+		m.isPromptActive = true
+		m.prompt.SetAction(prompt.SaveWorkspaceAction)
+		m.prompt.SetInitialValue(m.workspace)
+
+	case whichkey.SwitchWorkspaceMsg:
+		return m.switchWorkspace(msg.Name)
+
+	case whichkey.DeleteWorkspaceMsg:
+		return m.deleteWorkspace()
+
 	case whichkey.CloseSnippetsMsg:
 		m.view = viewMain
 		m.focusEditor()
@@ -595,6 +1045,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case snippetsView.SelectedMsg:
 		return m.applySnippet(msg)
 
+	case whichkey.ListLLMExamplesMsg:
+		return m.listLLMExamples()
+
+	case whichkey.SaveLLMExampleMsg:
+		return m.saveLLMExample()
+
+	case whichkey.CloseLLMExamplesMsg:
+		m.view = viewMain
+		m.focusEditor()
+		return m, nil
+
+	case examplesView.SelectedMsg:
+		m.view = viewMain
+		m.focusEditor()
+		return m, m.applyQueryToEditor(msg.Query)
+
+	case examplesView.DeleteMsg:
+		return m.deleteLLMExample(msg)
+
 	// Database schema actions
 	case whichkey.ListTablesMsg:
 		return m, m.executePsqlCommand("\\dt")
@@ -605,6 +1074,75 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case whichkey.ViewConstraintsMsg:
 		return m, m.executeQuery("SELECT * FROM information_schema.table_constraints;")
 
+	case whichkey.FindDuplicatesMsg:
+		m.isPromptActive = true
+		m.prompt.SetAction(prompt.FindDuplicatesAction)
+
+	case command.FindDuplicatesMsg:
+		return m.findDuplicates(msg)
+
+	case whichkey.FindOrphansMsg:
+		m.isPromptActive = true
+		m.prompt.SetAction(prompt.FindOrphansAction)
+
+	case whichkey.BuildQueryMsg:
+		return m.openQueryBuilder()
+
+	case whichkey.FindMsg:
+		return m.openFinder()
+
+	case whichkey.SearchFilesMsg:
+		m.isPromptActive = true
+		m.prompt.SetAction(prompt.GrepAction)
+
+	case command.GrepMsg:
+		return m.grep(msg.Text)
+
+	case command.StorageMsg:
+		return m.handleStorage(msg)
+
+	case storageCleanedMsg:
+		return m.handleStorageCleaned(msg)
+
+	case querybuilder.BuiltMsg:
+		return m, m.applyQueryToEditor(msg.Query)
+
+	case command.FindOrphansMsg:
+		return m.findOrphans(msg)
+
+	case whichkey.ShowRowsMsg:
+		return m.showRows()
+
+	case whichkey.ListNotValidConstraintsMsg:
+		return m.runDataQualityQuery(dataquality.NotValidConstraintsSQL)
+
+	case whichkey.ValidateConstraintMsg:
+		return m.checkValidationLocks()
+
+	case validationLocksMsg:
+		return m.confirmValidation(msg)
+
+	case command.ValidateConstraintMsg:
+		return m.validateConstraint()
+
+	case constraintValidatedMsg:
+		return m.handleConstraintValidated(msg)
+
+	case whichkey.IndexUsageMsg:
+		return m.runDataQualityQuery(stats.IndexUsageSQL)
+
+	case whichkey.ReviewDropIndexesMsg:
+		return m.reviewDropIndexes()
+
+	case whichkey.DatabaseHealthMsg:
+		return m.showHealth()
+
+	case whichkey.WALStatsMsg:
+		return m.loadWALStats()
+
+	case walStatsMsg:
+		return m.showWALStats(msg)
+
 	// History actions
 	case whichkey.ClearHistoryMsg:
 		// TODO: Create a state machine for handling cleaning history only for current session
@@ -629,6 +1167,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, _ := m.config.GetLLMModel()
 		m.prompt.SetInitialValue(model)
 
+	case whichkey.SetLLMTemperatureMsg:
+		m.openLLMSettingPrompt(prompt.LLMTemperatureAction, llm.SettingTemperature)
+
+	case whichkey.SetLLMMaxTokensMsg:
+		m.openLLMSettingPrompt(prompt.LLMMaxTokensAction, llm.SettingMaxTokens)
+
+	case whichkey.SetLLMTimeoutMsg:
+		m.openLLMSettingPrompt(prompt.LLMTimeoutAction, llm.SettingTimeout)
+
 	case whichkey.SetEditorMsg:
 		m.isPromptActive = true
 		m.prompt.SetAction(prompt.EditorAction)
@@ -638,13 +1185,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.isPromptActive = true
 		m.prompt.SetAction(prompt.ChangeLeaderKeyAction)
 
+	case whichkey.ToggleUppercaseKeywordsMsg:
+		return m.toggleUppercaseKeywords()
+
+	case whichkey.StorageUsageMsg:
+		return m.handleStorage(command.StorageMsg{Action: command.StorageUsage})
+
 	// Application control
 	case whichkey.QuitMsg:
-		m.closeDbConnection()
 		return m, tea.Quit
 
 	case prompt.CancelMsg:
 		m.isPromptActive = false
+
+	case querybuilder.CancelMsg:
+		m.isQueryBuilderActive = false
+
+	case finder.SelectedMsg:
+		return m.handleFinderSelection(msg.Entry)
+
+	case finder.CancelMsg:
+		m.isFinderActive = false
 	}
 
 	if m.isPromptActive {
@@ -653,6 +1214,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.isQueryBuilderActive {
+		builder, cmd := m.queryBuilder.Update(msg)
+		m.queryBuilder = builder
+		return m, cmd
+	}
+
+	if m.isFinderActive {
+		finderModel, cmd := m.finder.Update(msg)
+		m.finder = finderModel
+		return m, cmd
+	}
+
 	var cmds []tea.Cmd
 
 	if m.view == viewMain && m.focused == focusedEditor {
@@ -664,10 +1237,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.editor.SetLanguage(lang, styles.EditorLanguageTheme(m.isDark))
 
+		before := m.editor.GetCurrentContent()
 		textEditor, cmd := m.editor.Update(msg)
 		m.editor = textEditor
 		cmds = append(cmds, cmd)
 
+		m.capitaliseTypedKeyword(before)
+
 		// Proactively sync document to LSP in insert mode whenever content changes,
 		// so the server has the latest state before the completion debounce fires.
 		if m.lspClient != nil && m.editor.IsInsertMode() {
@@ -694,6 +1270,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	if m.focused == focusedPinned && m.pinned != nil {
+		var cmd tea.Cmd
+		m, cmd = m.updatePinned(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	if m.view == viewExportData {
 		exportDataModel, cmd := m.exportData.Update(msg)
 		m.exportData = exportDataModel
@@ -701,9 +1283,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if m.focused == focusedCommand {
+		height := lipgloss.Height(m.command.View())
 		cmdModel, cmd := m.command.Update(msg)
 		m.command = cmdModel
 		cmds = append(cmds, cmd)
+
+		// errors and completions are listed below the command
+		if lipgloss.Height(m.command.View()) != height {
+			m.updateSize()
+		}
 	}
 
 	if m.view == viewHelp {
@@ -724,6 +1312,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	if m.view == viewLLMExamples {
+		examplesModel, cmd := m.llmExamplesView.Update(msg)
+		m.llmExamplesView = examplesModel
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -746,10 +1340,19 @@ func (m *model) handleHelpToggle() {
 func (m model) View() tea.View {
 	view := tea.NewView(m.getView())
 	view.AltScreen = true
+	view.WindowTitle = m.windowTitle()
 
 	return view
 }
 
+func (m model) windowTitle() string {
+	if m.server.Name == "" {
+		return "perp"
+	}
+
+	return "perp - " + m.server.Name
+}
+
 func (m model) getView() string {
 	width, height := m.getAvailableSizes()
 
@@ -763,10 +1366,22 @@ func (m model) getView() string {
 		return m.overlayMenu(view)
 	}
 
+	if m.confirm.IsActive() {
+		return m.overlay(view, m.confirm.View())
+	}
+
 	if m.isPromptActive {
 		return m.overlayPrompt(view)
 	}
 
+	if m.isQueryBuilderActive {
+		return m.overlay(view, m.queryBuilder.View())
+	}
+
+	if m.isFinderActive {
+		return m.overlay(view, m.finder.View())
+	}
+
 	return view
 }
 
@@ -790,6 +1405,9 @@ func (m model) renderMainView(width, height int) string {
 	case viewSnippets:
 		return m.snippets.View()
 
+	case viewLLMExamples:
+		return m.llmExamplesView.View()
+
 	default:
 		return ""
 	}
@@ -809,14 +1427,16 @@ func (m model) overlayMenu(background string) string {
 }
 
 func (m model) overlayPrompt(background string) string {
-	promptBox := m.prompt.View()
-	promptW := lipgloss.Width(promptBox)
-	promptH := lipgloss.Height(promptBox)
-	x := max(0, (m.width-promptW)/2)
-	y := max(0, (m.height-promptH)/2)
+	return m.overlay(background, m.prompt.View())
+}
+
+// overlay centres box over the background
+func (m model) overlay(background, box string) string {
+	x := max(0, (m.width-lipgloss.Width(box))/2)
+	y := max(0, (m.height-lipgloss.Height(box))/2)
 
 	bg := lipgloss.NewLayer(background)
-	overlay := lipgloss.NewLayer(promptBox).X(x).Y(y).Z(1)
+	overlay := lipgloss.NewLayer(box).X(x).Y(y).Z(1)
 
 	return lipgloss.NewCompositor(bg, overlay).Render()
 }
@@ -826,9 +1446,9 @@ func (m model) showLeaderMenu() (tea.Model, tea.Cmd) {
 		// Update context before showing menu
 		m.updateMenuContext()
 
-		// Always show the root menu initially after leader timeout.
-		// The root menu is context-aware and will return appropriate items.
-		menu := m.menuRegistry.GetRootMenu()
+		// Show the results menu while the results are focused, the root
+		// menu otherwise. Both are context-aware and return appropriate items.
+		menu := m.menuRegistry.GetContextMenu()
 		m.whichKeyMenu.SetMenu(menu)
 		m.whichKeyMenu.Show()
 	}
@@ -854,15 +1474,16 @@ func (m model) applyHistoryQuery(msg historyView.SelectedMsg) (tea.Model, tea.Cm
 }
 
 func (m *model) setStyles(isDark bool) {
+	m.styles = styles.New(isDark)
+
 	for _, keyword := range llm.LLMKeywords {
 		m.llmKeywords[keyword] = m.styles.Accent.Bold(true)
 	}
 
-	for cmd := range psql.PSQL_COMMANDS {
+	for _, cmd := range psql.MetaCommands() {
 		m.psqlCommands[cmd] = m.styles.Primary.Bold(true)
 	}
 
-	m.styles = styles.New(isDark)
 	m.serverSelection.SetStyles(m.styles, isDark)
 	m.isDark = isDark
 	m.editor.WithTheme(styles.EditorTheme(m.styles))
@@ -871,6 +1492,9 @@ func (m *model) setStyles(isDark bool) {
 	m.prompt.SetStyles(m.styles)
 	m.spinner.Style = m.styles.Primary
 	m.content.SetStyles(m.styles, m.isDark)
+	if m.pinned != nil {
+		m.pinned.SetStyles(m.styles, m.isDark)
+	}
 	m.help.SetStyles(m.styles)
 	m.whichKeyMenu.SetStyles(m.styles)
 	m.history.SetStyles(m.styles, isDark)
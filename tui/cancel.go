@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// runningQuery holds the cancellation of the query run from the editor. It
+// is shared by the copies of the model, the query running in a command.
+type runningQuery struct {
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	cancelling bool
+	generation uint64 // counts the queries, telling the one cancel belongs to
+}
+
+// queryContext returns the context of a query run from the editor, which
+// esc or ctrl+c cancel while it runs. Once a query is done, its cancellation
+// is forgotten unless a later query replaced it.
+func (m model) queryContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+	if m.running == nil {
+		return ctx, cancel
+	}
+
+	r := m.running
+	r.mu.Lock()
+	r.generation++
+	generation := r.generation
+	r.cancel = cancel
+	r.cancelling = false
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.mu.Lock()
+		if r.generation == generation {
+			r.cancel = nil
+		}
+		r.mu.Unlock()
+
+		cancel()
+	}
+}
+
+// stop cancels the running query and reports whether there was one
+func (r *runningQuery) stop() bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel == nil {
+		return false
+	}
+
+	r.cancel()
+	r.cancel = nil
+	r.cancelling = true
+
+	return true
+}
+
+// status describes the running query below the spinner, or is empty when
+// none runs, e.g. while waiting for the LLM
+func (r *runningQuery) status() string {
+	if r == nil {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.cancelling:
+		return "Cancelling…"
+	case r.cancel != nil:
+		return "Running… press esc to cancel"
+	default:
+		return ""
+	}
+}
+
+// cancelled reports whether err is the failure of a query cancelled with
+// esc or ctrl+c, and forgets the cancellation
+func (r *runningQuery) cancelled(err error) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cancelling := r.cancelling
+	r.cancelling = false
+
+	return cancelling && db.IsCancelled(err)
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunningQueryIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	m := model{running: &runningQuery{}}
+
+	assert.False(t, m.running.stop(), "nothing runs")
+	assert.Empty(t, m.running.status())
+
+	ctx, cancel := m.queryContext()
+	assert.Equal(t, "Running… press esc to cancel", m.running.status())
+
+	assert.True(t, m.running.stop())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.Equal(t, "Cancelling…", m.running.status())
+
+	cancel()
+	assert.True(t, m.running.cancelled(ctx.Err()))
+	assert.False(t, m.running.cancelled(ctx.Err()), "the cancellation is reported once")
+	assert.Empty(t, m.running.status())
+}
+
+func TestFinishedQueryIsNotCancelled(t *testing.T) {
+	t.Parallel()
+
+	m := model{running: &runningQuery{}}
+
+	_, cancel := m.queryContext()
+	cancel()
+
+	assert.False(t, m.running.stop())
+	assert.False(t, m.running.cancelled(errors.New("relation \"users\" does not exist")))
+	assert.Empty(t, m.running.status())
+}
+
+func TestFinishedQueryKeepsTheNextOneCancellable(t *testing.T) {
+	t.Parallel()
+
+	m := model{running: &runningQuery{}}
+
+	_, cancelFirst := m.queryContext()
+	second, cancelSecond := m.queryContext()
+	defer cancelSecond()
+
+	cancelFirst()
+	assert.Equal(t, "Running… press esc to cancel", m.running.status(), "the second query still runs")
+
+	assert.True(t, m.running.stop())
+	assert.ErrorIs(t, second.Err(), context.Canceled)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"github.com/ionut-t/goeditor/core"
+	"github.com/ionut-t/perp/pkg/columnpicker"
+)
+
+// columnCompletions lists the columns of the tables referenced by the
+// statement at the cursor, read from the schema index loaded on connect.
+// After a comparison with an enum or boolean column, its values are listed
+// instead.
+func (m model) columnCompletions(ctx core.CompletionContext) []core.Completion {
+	content := m.editor.GetCurrentContent()
+	if m.schemaIndex == nil || !isSQLContent(content) {
+		return nil
+	}
+
+	offset := columnpicker.Offset(content, ctx.Position.Row, ctx.Position.Col)
+
+	if values := columnpicker.SuggestValues(content, offset, m.schemaIndex.Columns, m.schemaIndex.EnumLabels); len(values) > 0 {
+		completions := make([]core.Completion, len(values))
+		for i, v := range values {
+			completions[i] = core.Completion{
+				Text:        v.Text,
+				Label:       v.Label,
+				Description: v.Column.Source() + "." + v.Column.Column.Name + " " + v.Column.Column.Type,
+				Type:        "value",
+			}
+		}
+		return completions
+	}
+
+	suggestions := columnpicker.Suggest(content, offset, m.schemaIndex.Columns)
+
+	completions := make([]core.Completion, 0, len(suggestions))
+	for _, s := range suggestions {
+		description := s.Source()
+		if s.Column.Type != "" {
+			description += " " + s.Column.Type
+		}
+
+		completions = append(completions, core.Completion{
+			Text:        s.Text(),
+			Label:       s.Column.Name,
+			Description: description,
+			Type:        "column",
+		})
+	}
+
+	return completions
+}
+
+// mergeCompletions puts the columns of the referenced tables first, dropping
+// the completions of the language server with the same label
+func mergeCompletions(columns, completions []core.Completion) []core.Completion {
+	if len(columns) == 0 {
+		return completions
+	}
+
+	seen := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		seen[c.Label] = true
+	}
+
+	merged := columns
+	for _, c := range completions {
+		if !seen[c.Label] {
+			merged = append(merged, c)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/colwidth"
+	"github.com/ionut-t/perp/tui/content"
+)
+
+// restoreColumnWidths applies the column widths saved for a query
+func (m *model) restoreColumnWidths(query string) {
+	widths, err := colwidth.Load(m.config.Storage(), colwidth.Fingerprint(query))
+	if err != nil || len(widths) == 0 {
+		return
+	}
+
+	m.content.SetColumnWidths(widths)
+}
+
+// saveColumnWidths persists the column widths of a query
+func (m model) saveColumnWidths(msg content.ColumnWidthsChangedMsg) tea.Cmd {
+	storage := m.config.Storage()
+
+	return func() tea.Msg {
+		if err := colwidth.Save(storage, colwidth.Fingerprint(msg.Query), msg.Widths); err != nil {
+			return notificationErrorMsg{err: err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseAliases parses the aliases set in the config, written as
+// "<name> = <command>", e.g. "x = export * out.json". Invalid aliases, or
+// ones named after a command, are dropped and reported with the error.
+func ParseAliases(definitions []string) (map[string]string, error) {
+	aliases := make(map[string]string, len(definitions))
+
+	var errs []error
+	for _, definition := range definitions {
+		name, value, found := strings.Cut(definition, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), ":")
+		value = strings.TrimPrefix(strings.TrimSpace(value), ":")
+
+		switch {
+		case !found || name == "" || value == "":
+			errs = append(errs, fmt.Errorf("invalid alias %q, expected format: <name> = <command>", definition))
+		case strings.ContainsAny(name, " \t"):
+			errs = append(errs, fmt.Errorf("invalid alias %q, the name must be a single word", definition))
+		case slices.Contains(commands, name):
+			errs = append(errs, fmt.Errorf("invalid alias %q, %s is a command", definition, name))
+		default:
+			aliases[name] = value
+		}
+	}
+
+	return aliases, errors.Join(errs...)
+}
+
+// expandAlias replaces the alias the command starts with by its command,
+// followed by the arguments typed after the alias. Aliases can start with
+// other aliases, expanded in turn until a command is reached.
+func expandAlias(aliases map[string]string, value string) (string, error) {
+	var expanded []string
+
+	for {
+		name, args, _ := strings.Cut(value, " ")
+
+		command, ok := aliases[name]
+		if !ok {
+			return value, nil
+		}
+
+		if slices.Contains(expanded, name) {
+			return "", fmt.Errorf("alias cycle: %s -> %s", strings.Join(expanded, " -> "), name)
+		}
+		expanded = append(expanded, name)
+
+		value = strings.TrimSpace(command + " " + args)
+	}
+}
+
+// aliasNames returns the names of the aliases, to be completed with tab
+func aliasNames(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
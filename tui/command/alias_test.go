@@ -0,0 +1,73 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases, err := ParseAliases([]string{
+		"x = export * out.json",
+		":prod=connect production",
+		"broken",
+		"two words = q",
+		"export = export 1",
+		"empty =",
+	})
+
+	assert.Equal(t, map[string]string{"x": "export * out.json", "prod": "connect production"}, aliases)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"broken"`)
+	assert.Contains(t, err.Error(), "single word")
+	assert.Contains(t, err.Error(), "export is a command")
+	assert.Contains(t, err.Error(), `"empty ="`)
+}
+
+func TestExpandAlias(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string]string{
+		"x":     "export * out.json",
+		"prod":  "connect production",
+		"p":     "prod",
+		"a":     "b one",
+		"b":     "c two",
+		"c":     "a",
+		"self":  "self again",
+		"quote": "pipe jq",
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectError string
+	}{
+		{name: "command", value: "export 1,2 rows.csv", expected: "export 1,2 rows.csv"},
+		{name: "alias", value: "x", expected: "export * out.json"},
+		{name: "alias with arguments", value: "quote '.[] | .id'", expected: "pipe jq '.[] | .id'"},
+		{name: "alias of an alias", value: "p", expected: "connect production"},
+		{name: "prefix of an alias", value: "pro", expected: "pro"},
+		{name: "cycle", value: "a", expectError: "alias cycle: a -> b -> c -> a"},
+		{name: "alias of itself", value: "self", expectError: "alias cycle: self -> self"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expanded, err := expandAlias(aliases, tt.value)
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expanded)
+		})
+	}
+}
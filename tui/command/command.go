@@ -3,18 +3,23 @@ package command
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
 	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/pipe"
 	"github.com/ionut-t/perp/pkg/utils"
 )
 
 type ExportMsg struct {
-	Rows     []int
-	All      bool
-	Filename string
+	Rows      []int
+	All       bool
+	Selection bool // the cells selected in visual mode
+	Filename  string
 }
 
 type EditorChangedMsg struct {
@@ -29,6 +34,11 @@ type LLMModelChangedMsg struct {
 	Model string
 }
 
+type LLMSettingChangedMsg struct {
+	Name  string
+	Value string
+}
+
 type LeaderKeyChangedMsg struct {
 	Key string
 }
@@ -37,6 +47,108 @@ type SaveSnippetMsg struct {
 	Name string
 }
 
+type SaveWorkspaceMsg struct {
+	Name string
+}
+
+// GexecMsg confirms running the statements generated by \gexec
+type GexecMsg struct{}
+
+// PasswordMsg sets the role password entered and confirmed for \password
+type PasswordMsg struct {
+	Password string
+}
+
+// ServerPasswordMsg saves the changed password in the connected server
+type ServerPasswordMsg struct {
+	Password string
+}
+
+// FindDuplicatesMsg runs the duplicates finder on a table and its columns
+type FindDuplicatesMsg struct {
+	Input string
+}
+
+// FindOrphansMsg checks the foreign keys of a table for rows whose parent is missing
+type FindOrphansMsg struct {
+	Table string
+}
+
+// ValidateConstraintMsg validates the confirmed NOT VALID constraint
+type ValidateConstraintMsg struct{}
+
+// RunEditedFunctionMsg runs the function definition changed with \ef
+type RunEditedFunctionMsg struct{}
+
+type PipeMsg struct {
+	Command string
+	Format  pipe.Format
+}
+
+// TimeZoneMsg overrides the display time zone for the session; an empty Zone
+// restores the configured one
+type TimeZoneMsg struct {
+	Zone string
+}
+
+// ResetSessionMsg discards the session state on the server and reconnects
+type ResetSessionMsg struct{}
+
+// MaterializeMsg copies the current results into a temporary table
+type MaterializeMsg struct {
+	Table string
+}
+
+// GrepMsg lists the lines of the exports and snippets containing a text
+type GrepMsg struct {
+	Text string
+}
+
+// ConnectMsg connects to another saved server
+type ConnectMsg struct {
+	Server string
+}
+
+// CompareMsg runs the buffer on another saved server too, to compare the results
+type CompareMsg struct {
+	Server string
+}
+
+// SyncPreviewMsg compares the rows of a table on another saved server with
+// the connected one
+type SyncPreviewMsg struct {
+	Table  string
+	Server string
+	SQL    bool // put the reconciling statements in the editor instead
+}
+
+// KeywordCaseMsg changes the case of the SQL keywords in the editor
+type KeywordCaseMsg struct {
+	Upper bool
+}
+
+// JoinMsg adds a JOIN of a table to the query in the editor, from the
+// foreign keys between it and the tables of the query
+type JoinMsg struct {
+	Table string
+}
+
+// StorageAction is what the storage command does
+type StorageAction int
+
+const (
+	StorageUsage      StorageAction = iota // show the space taken per server
+	StorageTrash                           // move the old exports to the trash
+	StorageArchive                         // compress the old exports
+	StorageEmptyTrash                      // delete the exports in the trash
+)
+
+// StorageMsg reports the disk usage of the storage directory, or frees space
+type StorageMsg struct {
+	Action StorageAction
+	Days   int // the age of the exports to trash or archive
+}
+
 type CancelMsg struct{}
 
 type QuitMsg struct{}
@@ -46,24 +158,51 @@ type ErrorMsg struct {
 }
 
 type Model struct {
-	input *huh.Input
+	input   *huh.Input
+	history *history
+	aliases map[string]string
+	styles  styles.Styles
+
+	err        error    // the error of the last command entered, shown below it
+	candidates []string // the completions listed after tab
 }
 
-func New() Model {
+// New creates the command bar. The commands entered are kept in storage,
+// to be browsed with up and down in the next sessions too.
+func New(storage string) Model {
 	cmdInput := huh.NewInput().Prompt(": ")
 
 	return Model{
-		input: cmdInput,
+		input:   cmdInput,
+		history: loadHistory(storage),
 	}
 }
 
 func (c *Model) SetStyles(s styles.Styles) {
+	c.styles = s
 	c.input.WithTheme(styles.HuhThemeCatppuccin{Styles: s})
 }
 
-func (c Model) Reset() {
-	empty := ""
-	c.input.Value(&empty)
+func (c *Model) Reset() {
+	c.SetValue("")
+}
+
+// SetValue pre-fills the command, e.g. to let the user complete it
+func (c *Model) SetValue(value string) {
+	c.setValue(value)
+	c.err, c.candidates = nil, nil
+	c.history.reset()
+}
+
+// SetAliases sets the aliases expanded in the commands entered, see
+// ParseAliases
+func (c *Model) SetAliases(aliases map[string]string) {
+	c.aliases = aliases
+}
+
+// SetError shows the error of the command entered below it, to be fixed
+func (c *Model) SetError(err error) {
+	c.err = err
 }
 
 func (c Model) Init() tea.Cmd {
@@ -86,6 +225,13 @@ func (c Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (c Model) View() string {
+	switch {
+	case c.err != nil:
+		return lipgloss.JoinVertical(lipgloss.Left, c.input.View(), c.styles.Error.Render(c.err.Error()))
+	case len(c.candidates) > 0:
+		return lipgloss.JoinVertical(lipgloss.Left, c.input.View(), c.styles.Subtext0.Render(strings.Join(c.candidates, "  ")))
+	}
+
 	return c.input.View()
 }
 
@@ -96,50 +242,52 @@ func (c Model) Focus() tea.Cmd {
 
 func (c Model) handleCmdRunner(msg tea.KeyMsg) (Model, tea.Cmd) {
 	c.input.Focus()
+	c.err, c.candidates = nil, nil
 
 	switch msg.Key().Code {
 	case tea.KeyEsc:
-		empty := ""
-		c.input.Value(&empty)
+		c.Reset()
 		return c, utils.Dispatch(CancelMsg{})
 
-	case tea.KeyEnter:
-		cmdValue := c.input.GetValue().(string)
-		cmdValue = strings.TrimSpace(cmdValue)
-
-		if cmdValue == "" {
-			return c, nil
+	case tea.KeyUp:
+		if value, ok := c.history.previous(c.input.GetValue().(string)); ok {
+			c.setValue(value)
 		}
+		return c, nil
 
-		if cmdValue == "q" {
-			return c, utils.Dispatch(QuitMsg{})
+	case tea.KeyDown:
+		if value, ok := c.history.next(); ok {
+			c.setValue(value)
 		}
+		return c, nil
 
-		if strings.HasPrefix(cmdValue, "export") {
-			return c.handleExport()
-		}
+	case tea.KeyTab:
+		value, candidates := complete(c.input.GetValue().(string), aliasNames(c.aliases)...)
+		c.setValue(value)
+		c.candidates = candidates
+		return c, nil
 
-		if strings.HasPrefix(cmdValue, "set-editor") {
-			return c.handleEditorSetCmd(cmdValue)
-		}
+	case tea.KeyEnter:
+		cmdValue := c.input.GetValue().(string)
+		cmdValue = strings.TrimSpace(cmdValue)
 
-		if strings.HasPrefix(cmdValue, "llm-db-schema-enable") || strings.HasPrefix(cmdValue, "llm-db-schema-disable") {
-			return c.handleLLMDatabaseSchema(cmdValue)
+		if cmdValue == "" {
+			return c, nil
 		}
 
-		if strings.HasPrefix(cmdValue, "llm-model") {
-			return c.handleLLMMModelChanged(cmdValue)
-		}
+		err := c.history.add(cmdValue)
 
-		if strings.HasPrefix(cmdValue, "set-leader-key") {
-			return c.handleLeaderKeyChanged(cmdValue)
+		expanded, aliasErr := expandAlias(c.aliases, cmdValue)
+		if aliasErr != nil {
+			return c, utils.Dispatch(ErrorMsg{Err: aliasErr})
 		}
 
-		if strings.HasPrefix(cmdValue, "snippet") {
-			return c.handleSnippet(cmdValue)
+		c, cmd := c.run(expanded)
+		if err != nil {
+			cmd = tea.Batch(cmd, utils.Dispatch(ErrorMsg{Err: fmt.Errorf("failed to save the command history: %w", err)}))
 		}
 
-		return c, utils.Dispatch(ErrorMsg{Err: fmt.Errorf("unknown command: %s", cmdValue)})
+		return c, cmd
 	}
 
 	cmdModel, cmd := c.input.Update(msg)
@@ -148,23 +296,108 @@ func (c Model) handleCmdRunner(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return c, cmd
 }
 
-func (c Model) handleExport() (Model, tea.Cmd) {
-	value := c.input.GetValue().(string)
+// setValue replaces the command typed, keeping the one browsed in the history
+func (c *Model) setValue(value string) {
+	// the cursor moves to the end of a value set on an empty input
+	empty := ""
+	c.input.Value(&empty)
+	c.input.Value(&value)
+}
+
+// run runs the command entered
+func (c Model) run(cmdValue string) (Model, tea.Cmd) {
+	if cmdValue == "q" {
+		return c, utils.Dispatch(QuitMsg{})
+	}
+
+	if strings.HasPrefix(cmdValue, "export") {
+		return c.handleExport(cmdValue)
+	}
+
+	if strings.HasPrefix(cmdValue, "set-editor") {
+		return c.handleEditorSetCmd(cmdValue)
+	}
+
+	if strings.HasPrefix(cmdValue, "llm-db-schema-enable") || strings.HasPrefix(cmdValue, "llm-db-schema-disable") {
+		return c.handleLLMDatabaseSchema(cmdValue)
+	}
+
+	if strings.HasPrefix(cmdValue, "llm-model") {
+		return c.handleLLMMModelChanged(cmdValue)
+	}
+
+	if strings.HasPrefix(cmdValue, "llm-set") {
+		return c.handleLLMSettingChanged(cmdValue)
+	}
 
-	rows, all, fileName, err := parseExportCommand(value)
+	if strings.HasPrefix(cmdValue, "set-leader-key") {
+		return c.handleLeaderKeyChanged(cmdValue)
+	}
+
+	if cmdValue == "pipe" || strings.HasPrefix(cmdValue, "pipe ") {
+		return c.handlePipe(cmdValue)
+	}
+
+	if cmdValue == "tz" || strings.HasPrefix(cmdValue, "tz ") {
+		return c.handleTimeZone(cmdValue)
+	}
+
+	if cmdValue == "reset-session" {
+		empty := ""
+		c.input.Value(&empty)
+		return c, utils.Dispatch(ResetSessionMsg{})
+	}
+
+	if cmdValue == "materialize" || strings.HasPrefix(cmdValue, "materialize ") {
+		return c.handleMaterialize(cmdValue)
+	}
+
+	if cmdValue == "connect" || strings.HasPrefix(cmdValue, "connect ") {
+		return c.handleConnect(cmdValue)
+	}
+
+	if cmdValue == "grep" || strings.HasPrefix(cmdValue, "grep ") {
+		return c.handleGrep(cmdValue)
+	}
+
+	if cmdValue == "compare" || strings.HasPrefix(cmdValue, "compare ") {
+		return c.handleCompare(cmdValue)
+	}
+
+	if cmdValue == "sync-preview" || strings.HasPrefix(cmdValue, "sync-preview ") {
+		return c.handleSyncPreview(cmdValue)
+	}
+
+	if cmdValue == "keywords" || strings.HasPrefix(cmdValue, "keywords ") {
+		return c.handleKeywordCase(cmdValue)
+	}
+
+	if cmdValue == "join" || strings.HasPrefix(cmdValue, "join ") {
+		return c.handleJoin(cmdValue)
+	}
+
+	if cmdValue == "storage" || strings.HasPrefix(cmdValue, "storage ") {
+		return c.handleStorage(cmdValue)
+	}
+
+	if strings.HasPrefix(cmdValue, "snippet") {
+		return c.handleSnippet(cmdValue)
+	}
+
+	return c, utils.Dispatch(ErrorMsg{Err: fmt.Errorf("unknown command: %s", cmdValue)})
+}
+
+func (c Model) handleExport(cmdValue string) (Model, tea.Cmd) {
+	msg, err := parseExportCommand(cmdValue)
 	if err != nil {
 		return c, utils.Dispatch(ErrorMsg{Err: err})
 	}
 
-	if len(rows) == 0 && !all {
+	if len(msg.Rows) == 0 && !msg.All && !msg.Selection {
 		return c, utils.Dispatch(ErrorMsg{Err: fmt.Errorf("no rows specified")})
 	}
 
-	return c, utils.Dispatch(ExportMsg{
-		Rows:     rows,
-		All:      all,
-		Filename: fileName,
-	})
+	return c, utils.Dispatch(msg)
 }
 
 func (c Model) handleEditorSetCmd(cmdValue string) (Model, tea.Cmd) {
@@ -217,6 +450,19 @@ func (c Model) handleLLMMModelChanged(cmdValue string) (Model, tea.Cmd) {
 	return c, utils.Dispatch(LLMModelChangedMsg{Model: model})
 }
 
+func (c Model) handleLLMSettingChanged(cmdValue string) (Model, tea.Cmd) {
+	parts := strings.Fields(strings.TrimPrefix(cmdValue, "llm-set"))
+
+	if len(parts) != 2 {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("invalid llm-set format, expected: llm-set <setting> <value>")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(LLMSettingChangedMsg{Name: parts[0], Value: parts[1]})
+}
+
 func (c Model) handleLeaderKeyChanged(cmdValue string) (Model, tea.Cmd) {
 	leaderKey := strings.TrimLeft(strings.TrimPrefix(cmdValue, "set-leader-key"), " ")
 
@@ -242,37 +488,254 @@ func (c Model) handleSnippet(cmdValue string) (Model, tea.Cmd) {
 	return c, utils.Dispatch(SaveSnippetMsg{Name: snipetName})
 }
 
-func parseExportCommand(value string) ([]int, bool, string, error) {
-	var rows []int
-	var all bool
-	var fileName string
+func (c Model) handlePipe(cmdValue string) (Model, tea.Cmd) {
+	shellCmd, format, err := parsePipeCommand(cmdValue)
+	if err != nil {
+		return c, utils.Dispatch(ErrorMsg{Err: err})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(PipeMsg{Command: shellCmd, Format: format})
+}
+
+func (c Model) handleTimeZone(cmdValue string) (Model, tea.Cmd) {
+	zone := strings.TrimSpace(strings.TrimPrefix(cmdValue, "tz"))
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(TimeZoneMsg{Zone: zone})
+}
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (c Model) handleMaterialize(cmdValue string) (Model, tea.Cmd) {
+	table := strings.TrimSpace(strings.TrimPrefix(cmdValue, "materialize"))
+
+	if table == "" {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("no table name specified, expected format: materialize <table>")})
+	}
+
+	if !tableNamePattern.MatchString(table) {
+		return c, utils.Dispatch(ErrorMsg{Err: fmt.Errorf("invalid table name: %s", table)})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(MaterializeMsg{Table: table})
+}
+
+func (c Model) handleCompare(cmdValue string) (Model, tea.Cmd) {
+	name := strings.TrimSpace(strings.TrimPrefix(cmdValue, "compare"))
+
+	if name == "" {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("no server specified, expected format: compare <server>")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(CompareMsg{Server: name})
+}
+
+func (c Model) handleGrep(cmdValue string) (Model, tea.Cmd) {
+	text := strings.TrimSpace(strings.TrimPrefix(cmdValue, "grep"))
+
+	if text == "" {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("no text specified, expected format: grep <text>")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(GrepMsg{Text: text})
+}
+
+func (c Model) handleConnect(cmdValue string) (Model, tea.Cmd) {
+	name := strings.TrimSpace(strings.TrimPrefix(cmdValue, "connect"))
+
+	if name == "" {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("no server specified, expected format: connect <server>")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(ConnectMsg{Server: name})
+}
+
+func (c Model) handleKeywordCase(cmdValue string) (Model, tea.Cmd) {
+	var upper bool
+	switch strings.TrimSpace(strings.TrimPrefix(cmdValue, "keywords")) {
+	case "upper":
+		upper = true
+	case "lower":
+	default:
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("expected format: keywords upper|lower")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(KeywordCaseMsg{Upper: upper})
+}
+
+func (c Model) handleJoin(cmdValue string) (Model, tea.Cmd) {
+	table := strings.TrimSpace(strings.TrimPrefix(cmdValue, "join"))
+
+	if table == "" {
+		return c, utils.Dispatch(ErrorMsg{Err: errors.New("no table specified, expected format: join <table>")})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(JoinMsg{Table: table})
+}
+
+func (c Model) handleSyncPreview(cmdValue string) (Model, tea.Cmd) {
+	msg, err := parseSyncPreviewCommand(cmdValue)
+	if err != nil {
+		return c, utils.Dispatch(ErrorMsg{Err: err})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(msg)
+}
+
+// parseSyncPreviewCommand parses sync-preview [--sql] <table> <server>, the
+// server name being the rest of the command
+func parseSyncPreviewCommand(value string) (SyncPreviewMsg, error) {
+	var msg SyncPreviewMsg
+	var args []string
+
+	for _, field := range strings.Fields(strings.TrimPrefix(value, "sync-preview")) {
+		if field == "--sql" {
+			msg.SQL = true
+			continue
+		}
+		args = append(args, field)
+	}
+
+	if len(args) < 2 {
+		return msg, errors.New("expected format: sync-preview [--sql] <table> <server>")
+	}
+
+	msg.Table, msg.Server = args[0], strings.Join(args[1:], " ")
+
+	return msg, nil
+}
+
+func (c Model) handleStorage(cmdValue string) (Model, tea.Cmd) {
+	msg, err := parseStorageCommand(cmdValue)
+	if err != nil {
+		return c, utils.Dispatch(ErrorMsg{Err: err})
+	}
+
+	empty := ""
+	c.input.Value(&empty)
+
+	return c, utils.Dispatch(msg)
+}
+
+// parseStorageCommand parses storage [trash <days>|archive <days>|empty-trash]
+func parseStorageCommand(value string) (StorageMsg, error) {
+	const format = "expected format: storage [trash <days>|archive <days>|empty-trash]"
+
+	args := strings.Fields(strings.TrimPrefix(value, "storage"))
+	if len(args) == 0 {
+		return StorageMsg{Action: StorageUsage}, nil
+	}
+
+	var msg StorageMsg
+	switch args[0] {
+	case "trash":
+		msg.Action = StorageTrash
+	case "archive":
+		msg.Action = StorageArchive
+	case "empty-trash":
+		if len(args) != 1 {
+			return msg, errors.New(format)
+		}
+		return StorageMsg{Action: StorageEmptyTrash}, nil
+	default:
+		return msg, errors.New(format)
+	}
+
+	if len(args) != 2 {
+		return msg, errors.New(format)
+	}
+
+	days, err := strconv.Atoi(args[1])
+	if err != nil || days < 0 {
+		return msg, fmt.Errorf("invalid number of days %q, %s", args[1], format)
+	}
+	msg.Days = days
+
+	return msg, nil
+}
+
+func parsePipeCommand(value string) (string, pipe.Format, error) {
+	shellCmd := strings.TrimSpace(strings.TrimPrefix(value, "pipe"))
+	format := pipe.JSON
+
+	switch flag, rest, _ := strings.Cut(shellCmd, " "); flag {
+	case "--csv":
+		shellCmd = strings.TrimSpace(rest)
+		format = pipe.CSV
+	case "--json":
+		shellCmd = strings.TrimSpace(rest)
+	}
+
+	if shellCmd == "" {
+		return "", "", errors.New("no command specified, expected format: pipe [--csv] <command>")
+	}
+
+	return shellCmd, format, nil
+}
+
+func parseExportCommand(value string) (ExportMsg, error) {
+	var msg ExportMsg
 
 	parts := strings.Fields(value)
 
 	helper := "export row1,row2 filename"
 
 	if len(parts) < 2 {
-		return nil, false, "", fmt.Errorf("invalid export command format, expected: %s", helper)
+		return msg, fmt.Errorf("invalid export command format, expected: %s", helper)
 	}
 
-	if parts[1] == "*" {
-		all = true
-		fileName = strings.Join(parts[2:], " ")
-	} else {
-		all = false
+	switch parts[1] {
+	case "*":
+		msg.All = true
+		msg.Filename = strings.Join(parts[2:], " ")
+
+	case "selection":
+		msg.Selection = true
+		msg.Filename = strings.Join(parts[2:], " ")
+		if msg.Filename == "" {
+			return msg, fmt.Errorf("file name cannot be empty, expected format: export selection filename")
+		}
+
+	default:
 		for part := range strings.SplitSeq(parts[1], ",") {
 			var row int
 			_, err := fmt.Sscanf(part, "%d", &row)
 			if err != nil {
-				return nil, false, "", fmt.Errorf("invalid row number: %s, expected format: %s", part, helper)
+				return msg, fmt.Errorf("invalid row number: %s, expected format: %s", part, helper)
 			}
-			rows = append(rows, row)
+			msg.Rows = append(msg.Rows, row)
 		}
-		fileName = strings.Join(parts[2:], " ")
-		if fileName == "" {
-			return nil, false, "", fmt.Errorf("file name cannot be empty, expected format: %s", helper)
+		msg.Filename = strings.Join(parts[2:], " ")
+		if msg.Filename == "" {
+			return msg, fmt.Errorf("file name cannot be empty, expected format: %s", helper)
 		}
 	}
 
-	return rows, all, fileName, nil
+	return msg, nil
 }
@@ -0,0 +1,266 @@
+package command
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/pipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePipeCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		value          string
+		expectedCmd    string
+		expectedFormat pipe.Format
+		expectError    bool
+	}{
+		{name: "json by default", value: "pipe jq '.[] | .id'", expectedCmd: "jq '.[] | .id'", expectedFormat: pipe.JSON},
+		{name: "explicit json", value: "pipe --json jq length", expectedCmd: "jq length", expectedFormat: pipe.JSON},
+		{name: "csv", value: "pipe --csv cut -d, -f1", expectedCmd: "cut -d, -f1", expectedFormat: pipe.CSV},
+		{name: "missing command", value: "pipe ", expectError: true},
+		{name: "flag without command", value: "pipe --csv", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shellCmd, format, err := parsePipeCommand(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCmd, shellCmd)
+			assert.Equal(t, tt.expectedFormat, format)
+		})
+	}
+}
+
+func TestHandleTimeZone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "zone", value: "tz Europe/London", expected: "Europe/London"},
+		{name: "extra spaces", value: "tz   UTC ", expected: "UTC"},
+		{name: "reset", value: "tz", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, cmd := New("").handleTimeZone(tt.value)
+			require.NotNil(t, cmd)
+			assert.Equal(t, TimeZoneMsg{Zone: tt.expected}, cmd())
+		})
+	}
+}
+
+func TestResetSessionCommand(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := New("").handleCmdRunner(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Nil(t, cmd, "an empty command does nothing")
+
+	c := New("")
+	c.SetValue("reset-session")
+
+	_, cmd = c.handleCmdRunner(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, ResetSessionMsg{}, cmd())
+}
+
+func TestMaterializeCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    MaterializeMsg
+		expectError bool
+	}{
+		{value: "materialize tmp_results", expected: MaterializeMsg{Table: "tmp_results"}},
+		{value: "materialize  Orders2 ", expected: MaterializeMsg{Table: "Orders2"}},
+		{value: "materialize", expectError: true},
+		{value: "materialize tmp; DROP TABLE users", expectError: true},
+		{value: "materialize public.tmp", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			_, cmd := New("").handleMaterialize(tt.value)
+			require.NotNil(t, cmd)
+
+			msg := cmd()
+			if tt.expectError {
+				assert.IsType(t, ErrorMsg{}, msg)
+				return
+			}
+
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestCompareCommand(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := New("").handleCompare("compare  staging db ")
+	require.NotNil(t, cmd)
+	assert.Equal(t, CompareMsg{Server: "staging db"}, cmd())
+
+	_, cmd = New("").handleCompare("compare")
+	require.NotNil(t, cmd)
+	assert.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestGrepCommand(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := New("").handleGrep("grep  invoice 1234 ")
+	require.NotNil(t, cmd)
+	assert.Equal(t, GrepMsg{Text: "invoice 1234"}, cmd())
+
+	_, cmd = New("").handleGrep("grep")
+	require.NotNil(t, cmd)
+	assert.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestKeywordCaseCommand(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := New("").handleKeywordCase("keywords upper")
+	require.NotNil(t, cmd)
+	assert.Equal(t, KeywordCaseMsg{Upper: true}, cmd())
+
+	_, cmd = New("").handleKeywordCase("keywords  lower ")
+	require.NotNil(t, cmd)
+	assert.Equal(t, KeywordCaseMsg{Upper: false}, cmd())
+
+	_, cmd = New("").handleKeywordCase("keywords")
+	require.NotNil(t, cmd)
+	assert.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestJoinCommand(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := New("").handleJoin("join sales.orders ")
+	require.NotNil(t, cmd)
+	assert.Equal(t, JoinMsg{Table: "sales.orders"}, cmd())
+
+	_, cmd = New("").handleJoin("join")
+	require.NotNil(t, cmd)
+	assert.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestParseSyncPreviewCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    SyncPreviewMsg
+		expectError bool
+	}{
+		{value: "sync-preview users staging", expected: SyncPreviewMsg{Table: "users", Server: "staging"}},
+		{value: "sync-preview --sql public.users prod eu", expected: SyncPreviewMsg{Table: "public.users", Server: "prod eu", SQL: true}},
+		{value: "sync-preview users --sql staging", expected: SyncPreviewMsg{Table: "users", Server: "staging", SQL: true}},
+		{value: "sync-preview users", expectError: true},
+		{value: "sync-preview", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := parseSyncPreviewCommand(tt.value)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestParseStorageCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value       string
+		expected    StorageMsg
+		expectError bool
+	}{
+		{value: "storage", expected: StorageMsg{Action: StorageUsage}},
+		{value: "storage trash 30", expected: StorageMsg{Action: StorageTrash, Days: 30}},
+		{value: "storage archive 0", expected: StorageMsg{Action: StorageArchive}},
+		{value: "storage empty-trash", expected: StorageMsg{Action: StorageEmptyTrash}},
+		{value: "storage trash", expectError: true},
+		{value: "storage archive -1", expectError: true},
+		{value: "storage archive old", expectError: true},
+		{value: "storage empty-trash now", expectError: true},
+		{value: "storage compress 30", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := parseStorageCommand(tt.value)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestParseExportCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    ExportMsg
+		expectError bool
+	}{
+		{name: "all", value: "export * data.json", expected: ExportMsg{All: true, Filename: "data.json"}},
+		{name: "rows", value: "export 1,3 data.csv", expected: ExportMsg{Rows: []int{1, 3}, Filename: "data.csv"}},
+		{name: "selection", value: "export selection range.csv", expected: ExportMsg{Selection: true, Filename: "range.csv"}},
+		{name: "selection without file", value: "export selection", expectError: true},
+		{name: "invalid row", value: "export a data.csv", expectError: true},
+		{name: "rows without file", value: "export 1,2", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := parseExportCommand(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
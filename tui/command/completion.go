@@ -0,0 +1,122 @@
+package command
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// commands are the names completed with tab
+var commands = []string{
+	"compare", "connect", "export", "grep", "join", "keywords",
+	"llm-db-schema-disable", "llm-db-schema-enable", "llm-model", "llm-set",
+	"materialize", "pipe", "q", "reset-session", "set-editor", "set-leader-key",
+	"snippet", "storage", "sync-preview", "tz",
+}
+
+// withoutArguments are the commands not followed by a space once completed
+var withoutArguments = map[string]bool{
+	"llm-db-schema-disable": true,
+	"llm-db-schema-enable":  true,
+	"q":                     true,
+	"reset-session":         true,
+}
+
+// complete completes the command or alias name being typed, or the file path
+// ending the arguments. When several match, value is completed up to their
+// common prefix and they are returned to be listed.
+func complete(value string, aliases ...string) (string, []string) {
+	start := strings.LastIndexByte(value, ' ') + 1
+	if start == 0 {
+		matches := filterPrefix(slices.Concat(commands, aliases), value)
+		if len(matches) == 1 && !withoutArguments[matches[0]] && !slices.Contains(aliases, matches[0]) {
+			return matches[0] + " ", nil
+		}
+		return completeWith(value, matches, matches)
+	}
+
+	paths, names := completePath(value[start:])
+	completed, candidates := completeWith(value[start:], paths, names)
+
+	return value[:start] + completed, candidates
+}
+
+// completeWith returns the single match of word, or the prefix common to
+// the matches, all starting with word, with their names
+func completeWith(word string, matches, names []string) (string, []string) {
+	switch len(matches) {
+	case 0:
+		return word, nil
+	case 1:
+		return matches[0], nil
+	}
+
+	return commonPrefix(matches), names
+}
+
+// completePath returns the files and directories starting with path, the
+// directories ending with a separator, and their names. Hidden files are
+// only listed when the name typed starts with a dot.
+func completePath(path string) ([]string, []string) {
+	dir, base := filepath.Split(path)
+
+	entries, err := os.ReadDir(expandHome(cmp.Or(dir, ".")))
+	if err != nil {
+		return nil, nil
+	}
+
+	var paths, names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		}
+
+		paths = append(paths, dir+name)
+		names = append(names, name)
+	}
+
+	return paths, names
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[1:])
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matches = append(matches, value)
+		}
+	}
+	return matches
+}
+
+func commonPrefix(values []string) string {
+	prefix := slices.Min(values)
+	last := slices.Max(values)
+
+	i := 0
+	for i < len(prefix) && prefix[i] == last[i] {
+		i++
+	}
+
+	return prefix[:i]
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value      string
+		expected   string
+		candidates []string
+	}{
+		{value: "comp", expected: "compare "},
+		{value: "reset", expected: "reset-session"},
+		{value: "llm-m", expected: "llm-model "},
+		{value: "llm-db", expected: "llm-db-schema-", candidates: []string{"llm-db-schema-disable", "llm-db-schema-enable"}},
+		{value: "unknown", expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			value, candidates := complete(tt.value)
+			assert.Equal(t, tt.expected, value)
+			assert.Equal(t, tt.candidates, candidates)
+		})
+	}
+}
+
+func TestCompleteAlias(t *testing.T) {
+	t.Parallel()
+
+	value, candidates := complete("pr", "prod", "x")
+	assert.Equal(t, "prod", value)
+	assert.Nil(t, candidates)
+
+	value, candidates = complete("co", "cols")
+	assert.Equal(t, "co", value)
+	assert.Equal(t, []string{"compare", "connect", "cols"}, candidates)
+}
+
+func TestCompletePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.csv"), nil, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "results.json"), nil, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "reports"), 0o755))
+
+	prefix := "export * " + dir + string(filepath.Separator)
+
+	value, candidates := complete(prefix + "res")
+	assert.Equal(t, prefix+"results.json", value)
+	assert.Nil(t, candidates)
+
+	value, candidates = complete(prefix + "rep")
+	assert.Equal(t, prefix+"report", value)
+	assert.Equal(t, []string{"report.csv", "reports" + string(filepath.Separator)}, candidates)
+
+	value, candidates = complete(prefix + "reports")
+	assert.Equal(t, prefix+"reports"+string(filepath.Separator), value)
+	assert.Nil(t, candidates)
+
+	_, candidates = complete(prefix)
+	assert.NotContains(t, candidates, ".hidden")
+
+	value, _ = complete(prefix + ".h")
+	assert.Equal(t, prefix+".hidden", value)
+}
@@ -0,0 +1,100 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	historyFileName   = ".command_history"
+	maxHistoryEntries = 200
+)
+
+// history holds the commands entered, the oldest first, and the one browsed
+// with up and down
+type history struct {
+	path    string
+	entries []string
+	index   int    // len(entries) when not browsing
+	draft   string // the command being typed when browsing started
+}
+
+// loadHistory reads the commands entered in the previous sessions from
+// storage. A history that cannot be read starts empty, and without a storage
+// it only lasts for the session.
+func loadHistory(storage string) *history {
+	h := &history{}
+
+	if storage != "" {
+		h.path = filepath.Join(storage, historyFileName)
+
+		if data, err := os.ReadFile(h.path); err == nil {
+			h.entries = strings.FieldsFunc(string(data), func(r rune) bool { return r == '\n' })
+		}
+	}
+
+	h.reset()
+
+	return h
+}
+
+// add appends command, moving it to the end when it was entered before, and
+// saves the history
+func (h *history) add(command string) error {
+	h.entries = slices.DeleteFunc(h.entries, func(entry string) bool { return entry == command })
+	h.entries = append(h.entries, command)
+
+	if len(h.entries) > maxHistoryEntries {
+		h.entries = h.entries[len(h.entries)-maxHistoryEntries:]
+	}
+
+	h.reset()
+
+	if h.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+}
+
+// previous returns the command entered before the one browsed. When browsing
+// starts, current is kept to be restored past the last command.
+func (h *history) previous(current string) (string, bool) {
+	if h.index == 0 {
+		return "", false
+	}
+
+	if h.index == len(h.entries) {
+		h.draft = current
+	}
+
+	h.index--
+
+	return h.entries[h.index], true
+}
+
+// next returns the command entered after the one browsed, or the command
+// being typed when browsing started past the last one
+func (h *history) next() (string, bool) {
+	if h.index >= len(h.entries) {
+		return "", false
+	}
+
+	h.index++
+	if h.index == len(h.entries) {
+		return h.draft, true
+	}
+
+	return h.entries[h.index], true
+}
+
+// reset stops browsing
+func (h *history) reset() {
+	h.index, h.draft = len(h.entries), ""
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	storage := t.TempDir()
+
+	h := loadHistory(storage)
+	require.NoError(t, h.add("tz UTC"))
+	require.NoError(t, h.add("compare staging"))
+	require.NoError(t, h.add("tz UTC"))
+
+	h = loadHistory(storage)
+	assert.Equal(t, []string{"compare staging", "tz UTC"}, h.entries)
+
+	value, ok := h.previous("join ord")
+	require.True(t, ok)
+	assert.Equal(t, "tz UTC", value)
+
+	value, ok = h.previous(value)
+	require.True(t, ok)
+	assert.Equal(t, "compare staging", value)
+
+	_, ok = h.previous(value)
+	assert.False(t, ok, "the first command has none before it")
+
+	value, ok = h.next()
+	require.True(t, ok)
+	assert.Equal(t, "tz UTC", value)
+
+	value, ok = h.next()
+	require.True(t, ok)
+	assert.Equal(t, "join ord", value, "the command being typed is restored")
+
+	_, ok = h.next()
+	assert.False(t, ok)
+}
+
+func TestHistoryWithoutStorage(t *testing.T) {
+	t.Parallel()
+
+	h := loadHistory("")
+	require.NoError(t, h.add("q"))
+
+	value, ok := h.previous("")
+	require.True(t, ok)
+	assert.Equal(t, "q", value)
+}
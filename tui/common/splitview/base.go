@@ -12,6 +12,7 @@ import (
 	editor "github.com/ionut-t/goeditor"
 	"github.com/ionut-t/perp/internal/keymap"
 	"github.com/ionut-t/perp/pkg/clipboard"
+	"github.com/ionut-t/perp/ui/confirm"
 	"github.com/ionut-t/perp/ui/help"
 )
 
@@ -21,6 +22,9 @@ var (
 	minListWidth            = 50
 )
 
+// deleteDialog is the ID of the dialog confirming the deletion of an item
+const deleteDialog = "splitview-delete"
+
 // ChangeFocused is the key binding for switching focus between list and editor
 var ChangeFocused = key.NewBinding(
 	key.WithKeys("tab"),
@@ -71,6 +75,7 @@ type Config struct {
 	PlaceholderTitle    string // Title shown when no items exist
 	PlaceholderSubtitle string // Subtitle shown when no items exist
 	SuccessDeleteMsg    string // Message shown on successful delete
+	DeleteTitle         string // Title of the dialog confirming a deletion
 	SuccessRenameMsg    string // Message shown on successful rename
 }
 
@@ -88,6 +93,7 @@ type Model[T Item, S Store[T]] struct {
 	editor         editor.Model
 	successMessage string
 	help           help.Model
+	confirm        confirm.Model
 
 	// Callbacks for custom behavior
 	ProcessItems    func([]T) []list.Item          // Convert items to list items
@@ -169,6 +175,13 @@ func (m Model[T, S]) Init() tea.Cmd {
 
 // Update implements tea.Model
 func (m Model[T, S]) Update(msg tea.Msg) (Model[T, S], tea.Cmd) {
+	// the confirmation dialog takes the keys until it is answered
+	if _, ok := msg.(tea.KeyMsg); ok && m.confirm.IsActive() {
+		var cmd tea.Cmd
+		m.confirm, cmd = m.confirm.Update(msg)
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.handleWindowSize(msg.Width, msg.Height)
@@ -229,7 +242,23 @@ func (m Model[T, S]) Update(msg tea.Msg) (Model[T, S], tea.Cmd) {
 		}
 
 	case editor.DeleteFileMsg:
-		return m.handleDelete()
+		m.confirm = confirm.NewYesNo(
+			deleteDialog,
+			m.config.DeleteTitle,
+			fmt.Sprintf("Delete %s? It can't be undone.", m.store.GetCurrent().GetName()),
+		)
+		m.confirm.SetStyles(m.Styles)
+		return m, nil
+
+	case confirm.ConfirmedMsg:
+		if msg.ID == deleteDialog {
+			return m.handleDelete()
+		}
+
+	case confirm.CancelledMsg:
+		if msg.ID == deleteDialog {
+			return m, nil
+		}
 
 	case editor.RenameMsg:
 		return m.handleRename(msg)
@@ -278,6 +307,15 @@ func (m *Model[T, S]) View() string {
 		return m.Styles.Error.Render(m.error.Error())
 	}
 
+	if m.confirm.IsActive() {
+		return confirm.Overlay(m.render(), m.confirm.View(), m.width, m.height)
+	}
+
+	return m.render()
+}
+
+// render renders the current view
+func (m *Model[T, S]) render() string {
 	availableWidth, _ := m.getAvailableSizes()
 
 	switch m.view {
@@ -472,7 +510,7 @@ func (m Model[T, S]) handleSave(msg editor.SaveMsg) (Model[T, S], tea.Cmd) {
 	return m, nil
 }
 
-// handleDelete handles file deletion
+// handleDelete deletes the current item once the deletion is confirmed
 func (m Model[T, S]) handleDelete() (Model[T, S], tea.Cmd) {
 	current := m.store.GetCurrent()
 
@@ -549,7 +587,7 @@ func (m Model[T, S]) handleRename(msg editor.RenameMsg) (Model[T, S], tea.Cmd) {
 
 // CanTriggerLeaderKey returns whether the leader key can be triggered
 func (m *Model[T, S]) CanTriggerLeaderKey() bool {
-	return m.editor.IsNormalMode() && m.list.FilterState() != list.Filtering
+	return m.editor.IsNormalMode() && m.list.FilterState() != list.Filtering && !m.confirm.IsActive()
 }
 
 // HandleHelpToggle toggles the help view
@@ -569,6 +607,41 @@ func (m *Model[T, S]) HandleHelpToggle() {
 	}
 }
 
+// Select selects the item named name, reporting whether there is one
+func (m *Model[T, S]) Select(name string) bool {
+	items, err := m.store.Load()
+	if err != nil {
+		return false
+	}
+
+	for i, item := range items {
+		if item.GetName() != name {
+			continue
+		}
+
+		m.list.Select(i)
+		if m.OnListSelection != nil {
+			m.OnListSelection(m, m.list.SelectedItem())
+		}
+		return true
+	}
+
+	return false
+}
+
+// FocusLine focuses the editor of the selected item in normal mode, with the
+// cursor at the start of row, counted from 0
+func (m *Model[T, S]) FocusLine(row int) {
+	if m.view != ViewSplit {
+		return
+	}
+
+	m.focusedView = FocusedViewDetail
+	m.editor.Focus()
+	m.editor.SetNormalMode()
+	_ = m.editor.SetCursorPosition(row, 0)
+}
+
 // GetList returns the list model (useful for external access)
 func (m *Model[T, S]) GetList() *list.Model {
 	return &m.list
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/tui/content"
+)
+
+// readOnlySession makes the sessions of the other server read only, so
+// comparing it with the connected one cannot change it
+const readOnlySession = "SET default_transaction_read_only = on"
+
+// compareServers runs the query in the editor on the connected server and, on
+// a short lived connection, on another saved server
+func (m model) compareServers(msg command.CompareMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	query := strings.TrimSpace(m.editor.GetCurrentContent())
+	if query == "" || strings.HasPrefix(query, "\\") || strings.HasPrefix(query, "/") || db.IsEmptyQuery(query) {
+		return m, m.errorNotification(errors.New("compare runs the SQL query in the editor, write one first"))
+	}
+
+	other, err := m.otherServer(msg.Server)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		compared := serversComparedMsg{server: other.Name}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			compared.other, compared.otherErr = m.queryOtherServer(ctx, other, query)
+		}()
+
+		compared.current, compared.currentErr = runQuery(ctx, m.db, query)
+		wg.Wait()
+
+		return compared
+	})
+}
+
+// queryOtherServer runs the query on srv and closes the connection
+func (m model) queryOtherServer(ctx context.Context, srv *server.Server, query string) (content.ParsedQueryResult, error) {
+	database, err := m.connectReadOnly(srv)
+	if err != nil {
+		return content.ParsedQueryResult{}, err
+	}
+	defer database.Close()
+
+	return runQuery(ctx, database, query)
+}
+
+// connectReadOnly connects to srv in read only sessions, after its on
+// connect snippet
+func (m model) connectReadOnly(srv *server.Server) (db.Database, error) {
+	onConnect := readOnlySession
+	if snippet := strings.TrimSpace(srv.OnConnect); snippet != "" {
+		onConnect = strings.TrimSuffix(snippet, ";") + ";\n" + readOnlySession
+	}
+
+	return m.connect(srv.String(), onConnect)
+}
+
+// otherServer finds the saved server named name, other than the connected one
+func (m model) otherServer(name string) (*server.Server, error) {
+	other, err := server.FindByName(m.config.Storage(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	if other.ID == m.server.ID {
+		return nil, fmt.Errorf("already connected to %s, pick another server", other.Name)
+	}
+
+	return other, nil
+}
+
+// handleServersCompared shows the results of the connected server in the
+// results pane and the ones of the other server pinned next to them
+func (m model) handleServersCompared(msg serversComparedMsg) (tea.Model, tea.Cmd) {
+	closeCmd := m.finishQueryExecution()
+
+	if msg.currentErr != nil {
+		return m, tea.Batch(closeCmd, m.errorNotification(fmt.Errorf("%s: %w", m.server.Name, msg.currentErr)))
+	}
+
+	if msg.otherErr != nil {
+		return m, tea.Batch(closeCmd, m.errorNotification(fmt.Errorf("%s: %w", msg.server, msg.otherErr)))
+	}
+
+	if err := m.content.SetQueryResults(msg.current); err != nil {
+		return m, tea.Batch(closeCmd, m.errorNotification(err))
+	}
+	m.content.SetSource(m.server.Name)
+
+	other := m.content.Pinned()
+	if err := other.SetQueryResults(msg.other); err != nil {
+		return m, tea.Batch(closeCmd, m.errorNotification(err))
+	}
+	other.SetSource(msg.server)
+
+	m.pinned = &other
+	m.updateSize()
+
+	contentModel, cmd := m.content.Update(content.ResizeMsg{})
+	m.content = contentModel
+
+	return m, tea.Batch(cmd, closeCmd, m.successNotification(fmt.Sprintf(
+		"%s: %d rows, %s: %d rows",
+		m.server.Name, len(msg.current.Rows), msg.server, len(msg.other.Rows),
+	)))
+}
@@ -12,6 +12,7 @@ const (
 	viewHelp
 	viewHistory
 	viewSnippets
+	viewLLMExamples
 )
 
 // focused represents which component currently has focus
@@ -24,6 +25,8 @@ const (
 	focusedCommand
 	focusedHistory
 	focusedSnippets
+	focusedLLMExamples
+	focusedPinned
 )
 
 // Layout constants
@@ -35,9 +38,17 @@ const (
 // Timeout and duration constants
 const (
 	DatabaseQueryTimeout = 5 * time.Second
+	SyncPreviewTimeout   = 30 * time.Second
 	LeaderKeyTimeout     = 500 * time.Millisecond
 	NotificationDuration = 2 * time.Second
 )
 
+// streamIdleTimeout is how long the rows left to fetch of a query stay
+// available without a fetch, holding a connection in a transaction
+const streamIdleTimeout = 5 * time.Minute
+
+// timingWindow is how many of the last timed runs of a query are averaged
+const timingWindow = 10
+
 // Directory constants
 const exportDataDirectory = "data"
@@ -3,6 +3,9 @@ package content
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,9 +16,14 @@ import (
 	table "github.com/ionut-t/gotable"
 	"github.com/ionut-t/perp/internal/constants"
 	"github.com/ionut-t/perp/internal/version"
+	"github.com/ionut-t/perp/pkg/advisor"
 	"github.com/ionut-t/perp/pkg/clipboard"
 	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/diff"
+	"github.com/ionut-t/perp/pkg/geo"
+	"github.com/ionut-t/perp/pkg/highlight"
 	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/numfmt"
 	"github.com/ionut-t/perp/pkg/psql"
 	"github.com/ionut-t/perp/pkg/server"
 	"github.com/ionut-t/perp/pkg/update"
@@ -26,6 +34,10 @@ import (
 
 var padding = lipgloss.NewStyle().Padding(0, 1)
 
+// tableColumnChrome is the width the table adds to each column: a space on
+// each side of the value and the border
+const tableColumnChrome = 3
+
 type ParsedQueryResult struct {
 	Query         string
 	IsDDL         bool // Data Definition Language (CREATE/DROP/ALTER TABLE) — triggers schema refresh
@@ -34,14 +46,106 @@ type ParsedQueryResult struct {
 	Rows          []map[string]db.RowResult
 	PsqlRows      []map[string]any // For psql results
 	ExecutionTime time.Duration
+	LimitInjected bool // a row limit was added to the query before it was run
+	Cached        bool // the result was served from cache instead of the database
+	Expanded      bool // shown expanded whatever the display options, as with \gx
+}
+
+// displayMode is whether results are shown expanded
+type displayMode struct {
+	expanded, auto bool
+}
+
+// ResultInfo describes how the current result was produced
+type ResultInfo struct {
+	Query         string
+	Psql          bool // the result of a psql command rather than a SQL query
+	ExecutedAt    time.Time
+	ExecutionTime time.Duration
+	Rows          int
+	Columns       []ResultColumn
+	LimitInjected bool
+	Cached        bool
+}
+
+// ResultColumn is a column of the result and the name of its type, empty when unknown
+type ResultColumn struct {
+	Name string
+	Type string
 }
 
 type LLMResponseSelectedMsg struct {
 	Response string
 }
 
+// OpenCellMsg asks to open the selected cell value with the system opener
+type OpenCellMsg struct {
+	Value string
+}
+
+// PreviewCellMsg asks to preview the raw value of the selected cell
+type PreviewCellMsg struct {
+	Value any
+}
+
+// YankGeoJSONMsg asks to copy the selected geometry cell as GeoJSON
+type YankGeoJSONMsg struct {
+	Value any
+}
+
+// YankCodeBlockMsg asks to copy a code block of the LLM explanation
+type YankCodeBlockMsg struct {
+	Code string
+}
+
+// OpenLinkMsg asks to open a link of the LLM explanation with the system opener
+type OpenLinkMsg struct {
+	URL string
+}
+
+// YankQueryMsg asks to copy the query that produced the results
+type YankQueryMsg struct {
+	Query string
+}
+
+// ShowResultInfoMsg asks to show the metadata of the current result
+type ShowResultInfoMsg struct {
+	Info ResultInfo
+}
+
+// OpenPagerMsg asks to show the output, or the selected cell of the results,
+// in the pager
+type OpenPagerMsg struct {
+	Text string
+}
+
+// FetchMoreRowsMsg asks for the next rows of results fetched in batches,
+// sent when the last row fetched is selected
+type FetchMoreRowsMsg struct{}
+
+// ExportSelectionMsg asks to export the cells selected in visual mode
+type ExportSelectionMsg struct{}
+
+// PinResultsMsg asks to pin the results next to the ones of the next query,
+// or to unpin them
+type PinResultsMsg struct{}
+
+// ColumnWidthsChangedMsg is sent when a column of the results is resized
+type ColumnWidthsChangedMsg struct {
+	Query  string
+	Widths map[string]int
+}
+
+// NumberFormatToggledMsg is sent when numbers are switched between raw and formatted
+type NumberFormatToggledMsg struct {
+	Raw bool
+}
+
 type ResizeMsg struct{}
 
+// queryPlanColumn is the single column returned by EXPLAIN
+const queryPlanColumn = "QUERY PLAN"
+
 type clearYankMsg struct{}
 
 type view int
@@ -55,8 +159,28 @@ const (
 	viewLLMSharedSchema
 	viewError
 	viewPSQLHelp
+	viewReleaseNotes
+	viewPipeOutput
+	viewImagePreview
+	viewGeometryPreview
+	viewResultInfo
+)
+
+const (
+	maxSketchWidth  = 60
+	maxSketchHeight = 15
 )
 
+const (
+	// columnWidthStep is how much < and > narrow or widen a column
+	columnWidthStep = 4
+	minColumnWidth  = 4
+	truncationMark  = "..."
+)
+
+// imagePreviewHeaderHeight is the number of lines above the image in the preview
+const imagePreviewHeaderHeight = 4
+
 type Model struct {
 	width, height     int
 	view              view
@@ -64,6 +188,10 @@ type Model struct {
 	dbSchema          string
 	llmSharedSchema   string
 	queryResults      []map[string]any
+	resultColumns     []string
+	resultRows        []map[string]db.RowResult
+	columnTypes       map[string]uint32
+	timeDisplay       db.TimeDisplay
 	viewport          viewport.Model
 	table             table.Model
 	server            server.Server
@@ -71,9 +199,38 @@ type Model struct {
 	markdown          markdown.Model
 	latestReleaseInfo *update.LatestReleaseInfo
 	expandedDisplay   bool
+	autoExpand        bool         // expandedDisplay is chosen for each result by its width
+	expandedOnce      *displayMode // the display mode to restore after a result shown expanded with \gx
 	tableRows         [][]string
+	rawTableRows      [][]string
+	fullTableRows     [][]string
+	columnWidths      map[string]int
+	query             string
+	executedQuery     string
+	executedAt        time.Time
+	source            string // the server the results come from, when comparing servers
+	resultInfo        ResultInfo
+	visual            bool
+	visualRow         int
+	visualColumn      int
+	highlightedRows   [2]int
+	highlightRules    []highlight.Rule
+	onConnectOutput   string
+	sessionInfo       string
+	separators        numfmt.Separators
+	rawNumbers        bool
 	tableHeaders      []string
+	nullDisplay       string
+	border            int
+	showFooter        bool
 	styles            styles.Styles
+	llmSuggestion     string
+	llmDocument       markdown.Document
+	pendingNumber     string              // y or o, waiting for the number of a code block or link
+	pages             []ParsedQueryResult // the results of each statement of a buffer
+	page              int
+	moreRows          bool // the results are fetched in batches and more rows are left
+	fetchingRows      bool // the next batch of rows was asked for
 }
 
 func New(width, height int) Model {
@@ -87,13 +244,17 @@ func New(width, height int) Model {
 		viewport:        viewport.New(viewport.WithWidth(width), viewport.WithHeight(height)),
 		table:           t,
 		llmSharedSchema: "No schema shared with LLM.",
+		nullDisplay:     "NULL",
+		border:          1,
 	}
 }
 
 func (m *Model) SetStyles(s styles.Styles, isDark bool) {
 	m.styles = s
-	m.table.SetTheme(styles.TableTheme(s))
+	m.table.SetTheme(m.tableTheme())
 	m.markdown = markdown.New(isDark)
+	m.markdown.SetWidth(m.width)
+	m.applyHighlightRules()
 }
 
 func (m *Model) SetSize(width, height int) {
@@ -103,7 +264,8 @@ func (m *Model) SetSize(width, height int) {
 	m.viewport.SetWidth(width)
 	m.viewport.SetHeight(height)
 
-	m.table.SetSize(width-1, height)
+	m.table.SetSize(width-1, m.tableHeight())
+	m.markdown.SetWidth(width)
 
 	switch m.view {
 	case viewInfo, viewDBSchema, viewLLMSharedSchema:
@@ -115,12 +277,46 @@ func (m *Model) SetSize(width, height int) {
 		m.viewport.SetWidth(width - lipgloss.Width(m.renderLogo()))
 
 	case viewTable:
-		m.table.SetSize(width-1, height)
+		m.table.SetSize(width-1, m.tableHeight())
 	}
 }
 
+// Height returns the number of lines the content is shown on
+func (m *Model) Height() int {
+	return m.height
+}
+
 func (m *Model) SetConnectionInfo(s server.Server) {
 	m.server = s
+	m.onConnectOutput, m.sessionInfo = "", ""
+	m.view = viewConnectionInfo
+	m.setViewportContent()
+}
+
+// SetOnConnectOutput shows the output of the server's on connect snippet
+// below the connection info
+func (m *Model) SetOnConnectOutput(output string) {
+	m.onConnectOutput = output
+	m.setViewportContent()
+}
+
+// SetSessionInfo shows the details of the session returned by \conninfo in
+// the connection info
+func (m *Model) SetSessionInfo(result *psql.Result) {
+	m.sessionInfo = ""
+
+	if len(result.Rows) > 0 {
+		lines := make([]string, 0, len(result.Columns))
+		for _, column := range result.Columns {
+			value := m.nullDisplay
+			if v := result.Rows[0][column]; v != nil {
+				value = fmt.Sprint(v)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", column, lipgloss.NewStyle().Bold(true).Render(value)))
+		}
+		m.sessionInfo = lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
 	m.view = viewConnectionInfo
 	m.setViewportContent()
 }
@@ -156,6 +352,25 @@ func (m *Model) SetLLMSharedSchema(schema string) {
 	m.setViewportContent()
 }
 
+// SetLLMRelevantSchema shows the tables selected automatically for the last prompt
+func (m *Model) SetLLMRelevantSchema(tables []string, schema string) {
+	if len(tables) == 0 {
+		m.llmSharedSchema = "No relevant tables found for the last prompt."
+		m.setViewportContent()
+		return
+	}
+
+	title := fmt.Sprintf("Tables selected automatically for the last prompt: %s", strings.Join(tables, ", "))
+
+	m.llmSharedSchema = lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render(title),
+		"\n",
+		schema,
+	)
+	m.setViewportContent()
+}
+
 func (m *Model) ShowConnectionInfo() {
 	m.view = viewConnectionInfo
 	m.setViewportContent()
@@ -175,7 +390,65 @@ func (m *Model) ShowPsqlHelp() {
 }
 
 func (m *Model) SetExpandedDisplay(expanded bool) {
+	m.ExitVisualMode()
 	m.expandedDisplay = expanded
+	m.autoExpand = false
+	m.expandedOnce = nil
+}
+
+// SetAutoExpand shows the next results expanded only when their table is
+// wider than the screen, like \x auto
+func (m *Model) SetAutoExpand() {
+	m.ExitVisualMode()
+	m.autoExpand = true
+	m.expandedOnce = nil
+}
+
+// setExpandedOnce shows the next result expanded when expanded is true, and
+// restores the display mode of the results shown before a \gx result
+func (m *Model) setExpandedOnce(expanded bool) {
+	if m.expandedOnce != nil {
+		m.expandedDisplay, m.autoExpand = m.expandedOnce.expanded, m.expandedOnce.auto
+		m.expandedOnce = nil
+	}
+
+	if expanded {
+		m.expandedOnce = &displayMode{expanded: m.expandedDisplay, auto: m.autoExpand}
+		m.expandedDisplay, m.autoExpand = true, false
+	}
+}
+
+// SetDisplayOptions applies the options set with \pset and redraws the results table
+func (m *Model) SetDisplayOptions(options psql.DisplayOptions) {
+	if options.AutoExpand {
+		m.SetAutoExpand()
+	} else {
+		m.SetExpandedDisplay(options.Expanded)
+	}
+	m.nullDisplay = options.Null
+	m.border = options.Border
+	m.showFooter = options.Footer
+	m.table.SetTheme(m.tableTheme())
+
+	if m.resultRows != nil {
+		row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+		m.setTableRows(m.buildQueryResultsTable(m.resultColumns, m.resultRows))
+		m.table.SetSelectedCell(row, column)
+	}
+}
+
+// tableTheme returns the table theme of the styles with the border set by \pset border
+func (m *Model) tableTheme() table.Theme {
+	theme := styles.TableTheme(m.styles)
+
+	switch m.border {
+	case 0:
+		theme.Border = theme.Border.Faint(true)
+	case 2:
+		theme.Border = theme.Border.Bold(true)
+	}
+
+	return theme
 }
 
 func (m *Model) IsViewChangeRequired() bool {
@@ -205,13 +478,69 @@ func (m *Model) GetError() error {
 	return m.error
 }
 
+// HasPendingSuggestion reports whether an LLM suggested query is waiting to be applied
+func (m *Model) HasPendingSuggestion() bool {
+	return m.view == viewLLMExplanation && m.llmSuggestion != ""
+}
+
 func (m *Model) SetQueryResults(result ParsedQueryResult) error {
+	m.pages, m.page = nil, 0
+	return m.setQueryResults(result)
+}
+
+// SetQueryResultPages shows the results of the statements of a buffer, one
+// page per statement, starting with the last one. [ and ] page through them.
+func (m *Model) SetQueryResultPages(results []ParsedQueryResult) error {
+	if len(results) == 0 {
+		return m.SetQueryResults(ParsedQueryResult{})
+	}
+
+	m.pages = results
+	return m.showPage(len(results) - 1)
+}
+
+// showPage shows the results of the statement at index
+func (m *Model) showPage(index int) error {
+	m.page = index
+	return m.setQueryResults(m.pages[index])
+}
+
+// isPaging reports whether the results of several statements are shown
+func (m *Model) isPaging() bool {
+	return len(m.pages) > 1 && (m.view == viewTable || m.view == viewInfo)
+}
+
+// renderPage shows which statement the results belong to, e.g. "[2/3]"
+func (m *Model) renderPage() string {
+	if len(m.pages) < 2 {
+		return ""
+	}
+
+	return m.styles.Accent.Render(fmt.Sprintf("[%d/%d]", m.page+1, len(m.pages))) + "  "
+}
+
+func (m *Model) setQueryResults(result ParsedQueryResult) error {
+	m.ExitVisualMode()
+	m.moreRows, m.fetchingRows = false, false
+	m.setExpandedOnce(result.Expanded)
 	m.queryResults = nil
+	m.resultColumns, m.resultRows, m.columnTypes = nil, nil, nil
+	m.query, m.columnWidths, m.source = result.Query, nil, ""
+	m.executedQuery, m.executedAt = strings.TrimSpace(result.Query), time.Now()
+	m.resultInfo = ResultInfo{
+		Query:         m.executedQuery,
+		ExecutedAt:    m.executedAt,
+		ExecutionTime: result.ExecutionTime,
+		Rows:          len(result.Rows),
+		Columns:       resultColumns(result.Columns, result.Rows),
+		LimitInjected: result.LimitInjected,
+		Cached:        result.Cached,
+	}
 
 	if len(result.Columns) == 0 {
 		content := lipgloss.JoinVertical(
 			lipgloss.Left,
-			result.Query,
+			m.renderPage()+result.Query,
 			"\n",
 			lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render(
 				fmt.Sprintf("Query executed successfully. Affected rows: %d", result.AffectedRows),
@@ -226,66 +555,517 @@ func (m *Model) SetQueryResults(result ParsedQueryResult) error {
 
 	m.queryResults = make([]map[string]any, len(result.Rows))
 	for i, row := range result.Rows {
-		converted := make(map[string]any, len(row))
-		for k, v := range row {
-			if v.Type == pgtype.UUIDOID {
-				converted[k] = db.FormatValue(v.Value, v.Type)
-			} else {
-				converted[k] = v.Value
-			}
-		}
-		m.queryResults[i] = converted
+		m.queryResults[i] = convertRow(row)
 	}
 
 	if len(result.Rows) == 0 {
-		m.table.SetHeaders([]string{})
-		m.table.SetRows([][]string{})
+		m.setTableRows([][]string{}, []string{})
 		m.table.SetSelectedCell(0, 0)
-		m.viewport.SetContent("No results found.")
+		m.viewport.SetContent(m.renderPage() + "No results found.")
 		m.view = viewInfo
 		return nil
 	}
 
-	m.tableRows, m.tableHeaders = m.buildQueryResultsTable(result.Columns, result.Rows)
+	m.resultColumns, m.resultRows = result.Columns, result.Rows
+	m.columnTypes = make(map[string]uint32, len(result.Columns))
+	for k, v := range result.Rows[0] {
+		m.columnTypes[k] = v.Type
+	}
 
-	m.table.SetHeaders(m.tableHeaders)
-	m.table.SetRows(m.tableRows)
+	m.setTableRows(m.buildQueryResultsTable(result.Columns, result.Rows))
 	m.table.SetSelectedCell(0, 0)
 	m.view = viewTable
 
 	return nil
 }
 
+// SetMoreRows tells whether more rows of the shown results are left to be
+// fetched. Selecting the last row then asks for them with FetchMoreRowsMsg.
+func (m *Model) SetMoreRows(more bool) {
+	m.moreRows, m.fetchingRows = more, false
+	m.table.SetSize(m.width-1, m.tableHeight())
+}
+
+// AppendQueryRows adds the next batch of rows to the shown results, keeping
+// the selection, and tells whether more are left
+func (m *Model) AppendQueryRows(rows []map[string]db.RowResult, more bool) {
+	m.SetMoreRows(more)
+
+	if len(rows) == 0 || m.resultColumns == nil {
+		return
+	}
+
+	for _, row := range rows {
+		m.queryResults = append(m.queryResults, convertRow(row))
+	}
+
+	m.resultRows = append(m.resultRows, rows...)
+	m.resultInfo.Rows = len(m.resultRows)
+
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+	m.setTableRows(m.buildQueryResultsTable(m.resultColumns, m.resultRows))
+	m.table.SetSelectedCell(row, column)
+}
+
+// convertRow returns the values of row, with UUIDs and intervals formatted
+func convertRow(row map[string]db.RowResult) map[string]any {
+	converted := make(map[string]any, len(row))
+	for k, v := range row {
+		if v.Type == pgtype.UUIDOID || v.Type == pgtype.IntervalOID {
+			converted[k] = db.FormatValue(v.Value, v.Type)
+		} else {
+			converted[k] = v.Value
+		}
+	}
+	return converted
+}
+
+// ResultSet returns the rows of the shown query results as read from the
+// database, with their types. It reports false without rows or for the
+// output of psql commands.
+func (m *Model) ResultSet() ([]string, []map[string]db.RowResult, bool) {
+	if len(m.resultRows) == 0 {
+		return nil, nil, false
+	}
+
+	return m.resultColumns, m.resultRows, true
+}
+
+// GetQueryResults returns the results with timestamps in the display time zone and format
 func (m *Model) GetQueryResults() []map[string]any {
-	return m.queryResults
+	return m.timeDisplay.ApplyRows(m.queryResults, m.columnTypes)
+}
+
+// ResultInfo returns the details of the shown results, reporting false when
+// the results table is not shown
+func (m *Model) ResultInfo() (ResultInfo, bool) {
+	return m.resultInfo, m.view == viewTable && m.executedQuery != ""
+}
+
+// SetTimeDisplay changes how timestamps are shown and redraws the results table
+// ExecutedQuery returns the query of the shown results and when it ran
+func (m *Model) ExecutedQuery() (string, time.Time) {
+	return m.executedQuery, m.executedAt
+}
+
+func (m *Model) SetTimeDisplay(display db.TimeDisplay) {
+	m.timeDisplay = display
+
+	if m.resultRows == nil {
+		return
+	}
+
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+
+	m.setTableRows(m.buildQueryResultsTable(m.resultColumns, m.resultRows))
+	m.table.SetSelectedCell(row, column)
+}
+
+// SetNumberSeparators sets the locale separators used to format numbers in the table
+func (m *Model) SetNumberSeparators(separators numfmt.Separators) {
+	m.separators = separators
+}
+
+// ToggleRawNumbers switches the table between formatted and raw numbers and
+// reports whether raw numbers are now shown
+func (m *Model) ToggleRawNumbers() bool {
+	m.rawNumbers = !m.rawNumbers
+
+	if m.rawTableRows != nil {
+		row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+		m.setTableRows(m.rawTableRows, m.tableHeaders)
+		m.table.SetSelectedCell(row, column)
+	}
+
+	return m.rawNumbers
+}
+
+// setTableRows shows rows in the table, with numbers formatted for display
+// and cells cut to the column widths set by the user
+func (m *Model) setTableRows(rows [][]string, headers []string) {
+	m.rawTableRows, m.tableHeaders = rows, headers
+	m.fullTableRows = m.formatNumbers(rows)
+	m.tableRows = m.fitColumns(m.fullTableRows)
+
+	m.table.SetSize(m.width-1, m.tableHeight())
+	m.table.SetHeaders(m.fitColumns([][]string{headers})[0])
+	m.table.SetRows(m.tableRows)
+	m.applyHighlightRules()
+}
+
+// SetHighlightRules sets the rules used to style rows of the results
+func (m *Model) SetHighlightRules(rules []highlight.Rule) {
+	m.highlightRules = rules
+	m.applyHighlightRules()
+}
+
+// applyHighlightRules styles every row of the table with the rules it matches
+func (m *Model) applyHighlightRules() {
+	if len(m.highlightRules) == 0 {
+		return
+	}
+
+	for row := range m.rawTableRows {
+		m.table.SetRowStyle(row, m.rowStyle(row))
+	}
+}
+
+// rowStyle returns the style of a row that is not selected, from the
+// highlight rules it matches. Rules are not applied to the expanded display,
+// where each row is a field of a record.
+func (m *Model) rowStyle(row int) lipgloss.Style {
+	style := styles.TableTheme(m.styles).Cell
+	if m.expandedDisplay || len(m.highlightRules) == 0 || row < 0 || row >= len(m.rawTableRows) {
+		return style
+	}
+
+	values := make(map[string]string, len(m.tableHeaders))
+	for i, header := range m.tableHeaders {
+		if i > 0 && i < len(m.rawTableRows[row]) {
+			values[header] = m.rawTableRows[row][i]
+		}
+	}
+
+	match, ok := highlight.Match(m.highlightRules, values)
+	if !ok {
+		return style
+	}
+
+	if match.Foreground != "" {
+		style = style.Foreground(lipgloss.Color(match.Foreground))
+	}
+	if match.Background != "" {
+		style = style.Background(lipgloss.Color(match.Background))
+	}
+
+	return style.Bold(match.Bold).Italic(match.Italic).Underline(match.Underline).Faint(match.Faint)
+}
+
+// SetColumnWidths applies widths saved for the current query
+func (m *Model) SetColumnWidths(widths map[string]int) {
+	m.columnWidths = maps.Clone(widths)
+
+	if m.rawTableRows != nil {
+		m.refreshTable()
+	}
+}
+
+// Pinned returns a copy of the results with its own table, so it keeps
+// showing them while the results of m are replaced by the next query
+func (m Model) Pinned() Model {
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+
+	t := table.New()
+	t.SetSelectionMode(table.SelectionCell | table.SelectionRow)
+	t.SetTheme(m.tableTheme())
+
+	m.table = t
+	m.visual = false
+	m.columnWidths = maps.Clone(m.columnWidths)
+	m.setTableRows(m.rawTableRows, m.tableHeaders)
+	m.table.SetSelectedCell(row, column)
+
+	return m
+}
+
+// refreshTable redraws the current rows, keeping the selection
+func (m *Model) refreshTable() {
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+	m.setTableRows(m.rawTableRows, m.tableHeaders)
+	m.table.SetSelectedCell(row, column)
+}
+
+// resizeSelectedColumn narrows or widens the selected column by delta characters.
+// A column widened back to its natural width is no longer truncated.
+func (m Model) resizeSelectedColumn(delta int) (Model, tea.Cmd) {
+	column := m.table.GetSelectedColumn()
+	if m.expandedDisplay || m.query == "" || column <= 0 || column >= len(m.tableHeaders) {
+		return m, nil
+	}
+
+	name := m.tableHeaders[column]
+	natural := len([]rune(name))
+	for _, row := range m.fullTableRows {
+		if column < len(row) {
+			natural = max(natural, len([]rune(row[column])))
+		}
+	}
+
+	width, ok := m.columnWidths[name]
+	if !ok {
+		width = natural
+	}
+
+	width = max(minColumnWidth, width+delta)
+
+	if m.columnWidths == nil {
+		m.columnWidths = make(map[string]int)
+	}
+
+	if width >= natural {
+		delete(m.columnWidths, name)
+	} else {
+		m.columnWidths[name] = width
+	}
+
+	m.refreshTable()
+
+	query, widths := m.query, maps.Clone(m.columnWidths)
+	return m, func() tea.Msg {
+		return ColumnWidthsChangedMsg{Query: query, Widths: widths}
+	}
+}
+
+// fitColumns cuts cells longer than their column width and marks them as truncated
+func (m *Model) fitColumns(rows [][]string) [][]string {
+	if m.expandedDisplay || len(m.columnWidths) == 0 {
+		return rows
+	}
+
+	fitted := make([][]string, len(rows))
+	for i, row := range rows {
+		fitted[i] = slices.Clone(row)
+
+		for j, header := range m.tableHeaders {
+			if width, ok := m.columnWidths[header]; ok && j > 0 && j < len(row) {
+				fitted[i][j] = truncate(row[j], width)
+			}
+		}
+	}
+
+	return fitted
+}
+
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+
+	return string(runes[:max(0, width-len(truncationMark))]) + truncationMark
+}
+
+// tableHeight leaves a line above the table for the executed query and one
+// below it for the visual selection, the full value of truncated cells or the row count
+func (m *Model) tableHeight() int {
+	height := m.height
+	if m.executedQuery != "" {
+		height--
+	}
+	if m.hasFooter() {
+		height--
+	}
+	return height
+}
+
+func (m *Model) hasFooter() bool {
+	return m.visual || m.showFooter || m.moreRows || len(m.columnWidths) > 0 && !m.expandedDisplay
+}
+
+// renderFooter shows the full value of the selected cell when it is truncated,
+// or else the number of rows like the psql footer, noting when more are left
+// to fetch
+func (m *Model) renderFooter() string {
+	if preview := m.renderTruncatedPreview(); preview != "" {
+		return preview
+	}
+
+	if m.moreRows {
+		return m.styles.Subtext1.Render(fmt.Sprintf("(%d rows so far, more are fetched when the last one is selected)", m.resultInfo.Rows))
+	}
+
+	if !m.showFooter {
+		return ""
+	}
+
+	if m.resultInfo.Rows == 1 {
+		return m.styles.Subtext1.Render("(1 row)")
+	}
+
+	return m.styles.Subtext1.Render(fmt.Sprintf("(%d rows)", m.resultInfo.Rows))
+}
+
+// SetSource names the server the results come from in the header. It is
+// cleared by the next results.
+func (m *Model) SetSource(server string) {
+	m.source = server
+}
+
+// renderQueryHeader shows the statement the results belong to when there are
+// several, when the results were fetched, and from which server when
+// comparing servers, and the query, on one line
+func (m *Model) renderQueryHeader() string {
+	executedAt := m.styles.Subtext1.Render(m.executedAt.Format(time.TimeOnly))
+	if m.source != "" {
+		executedAt += "  " + m.styles.Accent.Render("["+m.source+"]")
+	}
+	page := m.renderPage()
+	width := max(0, m.width-lipgloss.Width(page)-lipgloss.Width(executedAt)-4)
+	query := truncate(strings.Join(strings.Fields(m.executedQuery), " "), width)
+
+	return padding.Render(page + executedAt + "  " + m.styles.Text.Bold(true).Render(query))
+}
+
+// renderTruncatedPreview shows the full value of the selected cell when it is truncated
+func (m *Model) renderTruncatedPreview() string {
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+	if row < 0 || row >= len(m.tableRows) || column < 0 || column >= len(m.tableRows[row]) ||
+		m.tableRows[row][column] == m.fullTableRows[row][column] {
+		return ""
+	}
+
+	preview := m.tableHeaders[column] + ": " + m.fullTableRows[row][column]
+	return m.styles.Subtext1.Render(truncate(preview, max(0, m.width-2)))
+}
+
+// formatNumbers returns a copy of rows with digit group separators in numeric
+// columns. Yank and export keep using the raw values.
+func (m *Model) formatNumbers(rows [][]string) [][]string {
+	if m.rawNumbers || len(m.columnTypes) == 0 {
+		return rows
+	}
+
+	formatted := make([][]string, len(rows))
+	for i, row := range rows {
+		formatted[i] = slices.Clone(row)
+
+		if m.expandedDisplay {
+			if len(row) == 2 && isNumericType(m.columnTypes[row[0]]) {
+				formatted[i][1] = m.separators.Format(row[1])
+			}
+			continue
+		}
+
+		for j, header := range m.tableHeaders {
+			if j > 0 && j < len(row) && isNumericType(m.columnTypes[header]) {
+				formatted[i][j] = m.separators.Format(row[j])
+			}
+		}
+	}
+
+	return formatted
+}
+
+func isNumericType(oid uint32) bool {
+	switch oid {
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID, pgtype.Float4OID, pgtype.Float8OID, pgtype.NumericOID:
+		return true
+	}
+	return false
+}
+
+// selectedCell returns the raw value of the selected cell, before number formatting
+func (m *Model) selectedCell() (string, bool) {
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+	if row >= 0 && row < len(m.rawTableRows) && column >= 0 && column < len(m.rawTableRows[row]) {
+		return m.rawTableRows[row][column], true
+	}
+
+	return m.table.GetSelectedCell()
+}
+
+// SelectedValue returns the value of column in the selected row of the
+// results. It reports false when the results have no such column.
+func (m *Model) SelectedValue(column string) (any, bool) {
+	row := m.table.GetSelectedRow()
+	if m.view != viewTable || m.expandedDisplay || row < 0 || row >= len(m.queryResults) {
+		return nil, false
+	}
+
+	value, ok := m.queryResults[row][column]
+	return value, ok
+}
+
+// SelectedPlanNode returns the EXPLAIN plan node at the selected table row and
+// the full plan. It reports false when the results are not a query plan.
+func (m *Model) SelectedPlanNode() (string, []string, bool) {
+	if m.view != viewTable || m.expandedDisplay || len(m.queryResults) == 0 {
+		return "", nil, false
+	}
+
+	plan := make([]string, 0, len(m.queryResults))
+	for _, row := range m.queryResults {
+		line, exists := row[queryPlanColumn]
+		if !exists || len(row) != 1 {
+			return "", nil, false
+		}
+		plan = append(plan, fmt.Sprint(line))
+	}
+
+	node := advisor.PlanNode(plan, m.table.GetSelectedRow())
+	return node, plan, node != ""
 }
 
-func (m *Model) SetPsqlResult(result *psql.Result) {
+func (m *Model) SetPsqlResult(command string, result *psql.Result) {
+	m.pages, m.page = nil, 0
+	m.moreRows, m.fetchingRows = false, false
+	m.ExitVisualMode()
+	m.setExpandedOnce(false)
 	m.queryResults = result.Rows
+	m.resultColumns, m.resultRows, m.columnTypes = nil, nil, nil
+	m.query, m.columnWidths, m.source = "", nil, ""
+	m.executedQuery, m.executedAt = strings.TrimSpace(command), time.Now()
+	m.resultInfo = ResultInfo{
+		Query:         m.executedQuery,
+		Psql:          true,
+		ExecutedAt:    m.executedAt,
+		ExecutionTime: result.ExecutionTime,
+		Rows:          len(result.Rows),
+		Columns:       resultColumns(result.Columns, nil),
+	}
 
 	if len(result.Rows) == 0 {
-		m.table.SetHeaders([]string{})
-		m.table.SetRows([][]string{})
+		message := "No results found."
+		if len(result.Columns) == 0 && result.Message != "" {
+			// a message instead of a table, like psql prints
+			message = result.Message
+		}
+
+		m.setTableRows([][]string{}, []string{})
 		m.table.SetSelectedCell(0, 0)
-		m.viewport.SetContent("No results found.")
+		m.viewport.SetContent(message)
 		m.view = viewInfo
 		return
 	}
 
-	m.tableRows, m.tableHeaders = m.buildPsqlCommandTable(result.Columns, result.Rows)
-
-	m.table.SetHeaders(m.tableHeaders)
-	m.table.SetRows(m.tableRows)
+	m.setTableRows(m.buildPsqlCommandTable(result.Columns, result.Rows))
 	m.table.SetSelectedCell(0, 0)
 	m.view = viewTable
 }
 
 func (m *Model) SetLLMResponse(response llm.Response, query string) {
 	content := response.Response
+	m.llmSuggestion = ""
 
-	if response.Command == llm.Ask {
+	switch response.Command {
+	case llm.Ask:
 		query = strings.TrimPrefix(query, "/ask")
 		content = fmt.Sprintf("> %s\n\n%s", query, content)
+
+	case llm.Filter:
+		query = strings.TrimPrefix(query, "/filter")
+		content = fmt.Sprintf("> %s\n\n%s\n\n_Press enter in the editor to run the filtered query._", query, content)
+
+	case llm.Fix, llm.Optimise:
+		suggestion := llm.ExtractQuery(response.Response)
+		changes := diff.Lines(llm.StripCommandComments(query), suggestion)
+
+		if diff.HasChanges(changes) {
+			m.llmSuggestion = suggestion
+			content = fmt.Sprintf(
+				"%s\n\n## Changes\n\n```diff\n%s\n```\n\n_Press enter to apply the suggested query to the editor._",
+				content,
+				diff.Unified(changes),
+			)
+		}
+	}
+
+	m.llmDocument = markdown.Prepare(content)
+	m.pendingNumber = ""
+
+	content = m.llmDocument.Markdown
+	if hint := numberedActionsHint(m.llmDocument); hint != "" {
+		content += "\n\n_" + hint + "_"
 	}
 
 	if out, err := m.markdown.Render(content); err != nil {
@@ -298,6 +1078,148 @@ func (m *Model) SetLLMResponse(response llm.Response, query string) {
 	}
 }
 
+// SetReleaseNotes renders a release changelog written in markdown
+func (m *Model) SetReleaseNotes(notes string) {
+	if out, err := m.markdown.Render(notes); err != nil {
+		m.error = fmt.Errorf("failed to render release notes: %w", err)
+		m.view = viewError
+	} else {
+		m.viewport.SetContent(out)
+		m.viewport.SetYOffset(0)
+		m.view = viewReleaseNotes
+	}
+}
+
+// SetPipeOutput shows the output of a shell command the results were piped through
+func (m *Model) SetPipeOutput(command, output string) {
+	if strings.TrimSpace(output) == "" {
+		output = "(no output)"
+	}
+
+	header := m.styles.Primary.Render("$ " + command)
+	m.viewport.SetContent(padding.Render(header + "\n\n" + strings.TrimRight(output, "\n")))
+	m.viewport.SetYOffset(0)
+	m.view = viewPipeOutput
+}
+
+// SetImagePreview shows the metadata of a bytea value and leaves room below it
+// for an inline image drawn by the terminal.
+func (m *Model) SetImagePreview(title, details string) {
+	header := lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render(title),
+		details,
+		m.styles.Subtext1.Render("Press any key to close the preview."),
+	)
+
+	m.viewport.SetContent(padding.Render(header))
+	m.viewport.SetYOffset(0)
+	m.view = viewImagePreview
+}
+
+// SetGeometryPreview shows a spatial value as WKT with a sketch of its bounding box
+func (m *Model) SetGeometryPreview(g *geo.Geometry) {
+	title := g.Type
+	if g.SRID != 0 {
+		title = fmt.Sprintf("%s (SRID %d)", g.Type, g.SRID)
+	}
+
+	width := max(1, m.width-2*padding.GetPaddingLeft())
+
+	parts := []string{
+		lipgloss.NewStyle().Bold(true).Render(title),
+		lipgloss.NewStyle().Width(width).Render(g.WKT()),
+	}
+
+	sketchWidth := min(maxSketchWidth, width-2)
+	sketchHeight := min(maxSketchHeight, m.height-lipgloss.Height(parts[1])-8)
+	if sketch := geo.Sketch(g, sketchWidth, sketchHeight); sketch != "" {
+		parts = append(parts, "", m.styles.Subtext1.Render(sketch))
+	}
+
+	m.viewport.SetContent(padding.Render(lipgloss.JoinVertical(lipgloss.Left, parts...)))
+	m.viewport.SetYOffset(0)
+	m.view = viewGeometryPreview
+}
+
+// SetResultInfo shows the rendered metadata of the current result
+func (m *Model) SetResultInfo(info string) {
+	if out, err := m.markdown.Render(info); err != nil {
+		m.error = fmt.Errorf("failed to render result info: %w", err)
+		m.view = viewError
+	} else {
+		m.viewport.SetContent(out)
+		m.viewport.SetYOffset(0)
+		m.view = viewResultInfo
+	}
+}
+
+// resultColumns pairs the columns with the names of their types, which are
+// only known when at least one row was returned
+func resultColumns(columns []string, rows []map[string]db.RowResult) []ResultColumn {
+	out := make([]ResultColumn, len(columns))
+	for i, column := range columns {
+		out[i].Name = column
+		if len(rows) > 0 {
+			out[i].Type = db.TypeName(rows[0][column].Type)
+		}
+	}
+	return out
+}
+
+// IsImagePreview reports whether the image preview is visible
+func (m *Model) IsImagePreview() bool {
+	return m.view == viewImagePreview
+}
+
+// CloseImagePreview returns to the results table
+func (m *Model) CloseImagePreview() {
+	if m.view == viewImagePreview {
+		m.view = viewTable
+	}
+}
+
+// ImagePreviewArea returns the offset and size in cells, relative to the
+// content, where the previewed image is drawn.
+func (m *Model) ImagePreviewArea() (top, left, cols, rows int) {
+	left = padding.GetPaddingLeft()
+	return imagePreviewHeaderHeight, left, m.width - 2*left, m.height - imagePreviewHeaderHeight
+}
+
+// selectedRawValue returns the unformatted value of the selected cell
+func (m *Model) selectedRawValue() (any, bool) {
+	if m.expandedDisplay {
+		return nil, false
+	}
+
+	row, column := m.table.GetSelectedRow(), m.table.GetSelectedColumn()
+	if row < 0 || row >= len(m.queryResults) || column <= 0 || column >= len(m.tableHeaders) {
+		return nil, false
+	}
+
+	value, ok := m.queryResults[row][m.tableHeaders[column]]
+	return value, ok
+}
+
+// PagerText returns the text shown in the pager: the full value of the
+// selected cell of the results, or the output of the other views
+func (m *Model) PagerText() (string, bool) {
+	switch m.view {
+	case viewTable:
+		// the table shows multi-line values on one line, e.g. the source of \df+
+		if value, ok := m.selectedRawValue(); ok && value != nil {
+			return fmt.Sprint(value), true
+		}
+		return m.selectedCell()
+	case viewError:
+		return m.error.Error(), true
+	case viewConnectionInfo, viewImagePreview:
+		return "", false
+	}
+
+	return m.viewport.GetContent(), true
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
@@ -305,27 +1227,164 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case clearYankMsg:
-		m.table.SetTheme(styles.TableTheme(m.styles))
+		m.table.SetTheme(m.tableTheme())
 
 	case ResizeMsg:
 		if m.view == viewTable {
-			m.table.SetTheme(styles.TableTheme(m.styles))
-			m.table.SetSize(m.width-1, m.height)
-			m.table.SetHeaders(m.tableHeaders)
+			m.table.SetTheme(m.tableTheme())
+			m.table.SetSize(m.width-1, m.tableHeight())
+			m.table.SetHeaders(m.fitColumns([][]string{m.tableHeaders})[0])
 			m.table.SetRows(m.tableRows)
 		}
 
 	case tea.KeyMsg:
+		if m.pendingNumber != "" {
+			action := m.pendingNumber
+			m.pendingNumber = ""
+
+			if n, err := strconv.Atoi(msg.String()); err == nil {
+				return m.pickNumbered(action, n)
+			}
+		}
+
 		switch msg.String() {
+		case "v":
+			if m.view == viewTable {
+				m.toggleVisualMode()
+				return m, nil
+			}
+
+		case "esc":
+			if m.visual {
+				m.ExitVisualMode()
+				return m, nil
+			}
+
+		case "e":
+			if m.visual {
+				return m, func() tea.Msg {
+					return ExportSelectionMsg{}
+				}
+			}
+
 		case "y":
+			if m.visual {
+				return m.yankVisualSelection()
+			}
 			if m.view == viewTable {
 				return m.yankSelectedCell()
 			}
+			if m.view == viewLLMExplanation {
+				return m.startNumbered("y")
+			}
 
 		case "Y":
 			if m.view == viewTable {
 				return m.yankSelectedRow()
 			}
+
+		case "o":
+			if m.view == viewTable {
+				if cell, ok := m.selectedCell(); ok {
+					return m, func() tea.Msg {
+						return OpenCellMsg{Value: cell}
+					}
+				}
+			}
+			if m.view == viewLLMExplanation {
+				return m.startNumbered("o")
+			}
+
+		case "O":
+			if text, ok := m.PagerText(); ok {
+				return m, func() tea.Msg {
+					return OpenPagerMsg{Text: text}
+				}
+			}
+
+		case "P":
+			if m.view == viewTable {
+				if value, ok := m.selectedRawValue(); ok {
+					return m, func() tea.Msg {
+						return PreviewCellMsg{Value: value}
+					}
+				}
+			}
+
+		case "J":
+			if m.view == viewTable {
+				if value, ok := m.selectedRawValue(); ok {
+					return m, func() tea.Msg {
+						return YankGeoJSONMsg{Value: value}
+					}
+				}
+			}
+
+		case "R":
+			if m.view == viewTable {
+				raw := m.ToggleRawNumbers()
+				return m, func() tea.Msg {
+					return NumberFormatToggledMsg{Raw: raw}
+				}
+			}
+
+		case "Q":
+			if m.view == viewTable && m.executedQuery != "" {
+				query := m.executedQuery
+				return m, func() tea.Msg {
+					return YankQueryMsg{Query: query}
+				}
+			}
+
+		case "I":
+			switch {
+			case m.view == viewTable && m.executedQuery != "":
+				info := m.resultInfo
+				return m, func() tea.Msg {
+					return ShowResultInfoMsg{Info: info}
+				}
+			case m.view == viewResultInfo:
+				m.view = viewTable
+				return m, nil
+			}
+
+		case "p":
+			if m.view == viewTable {
+				return m, func() tea.Msg {
+					return PinResultsMsg{}
+				}
+			}
+
+		case "[", "]":
+			if m.isPaging() {
+				page := m.page + 1
+				if msg.String() == "[" {
+					page = m.page - 1
+				}
+
+				if page >= 0 && page < len(m.pages) {
+					_ = m.showPage(page)
+				}
+				return m, nil
+			}
+
+		case "<", ">":
+			if m.view == viewTable {
+				delta := columnWidthStep
+				if msg.String() == "<" {
+					delta = -columnWidthStep
+				}
+				return m.resizeSelectedColumn(delta)
+			}
+
+		case "enter":
+			if m.HasPendingSuggestion() {
+				suggestion := m.llmSuggestion
+				m.llmSuggestion = ""
+				return m, func() tea.Msg {
+					return LLMResponseSelectedMsg{Response: suggestion}
+				}
+			}
 		}
 	}
 
@@ -337,6 +1396,17 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.table = t
 		cmds = append(cmds, cmd)
 
+		if m.visual {
+			m.highlightVisualSelection()
+		}
+
+		if m.moreRows && !m.fetchingRows && m.table.GetSelectedRow() >= len(m.tableRows)-1 {
+			m.fetchingRows = true
+			cmds = append(cmds, func() tea.Msg {
+				return FetchMoreRowsMsg{}
+			})
+		}
+
 	default:
 		m.setViewportContent()
 
@@ -351,7 +1421,20 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m Model) View() string {
 	switch m.view {
 	case viewTable:
-		return lipgloss.NewStyle().Height(m.height).Render(m.table.View())
+		var parts []string
+		if m.executedQuery != "" {
+			parts = append(parts, m.renderQueryHeader())
+		}
+
+		parts = append(parts, lipgloss.NewStyle().Height(m.tableHeight()).Render(m.table.View()))
+
+		if m.visual {
+			parts = append(parts, padding.Render(m.renderVisualSelection()))
+		} else if m.hasFooter() {
+			parts = append(parts, padding.Render(m.renderFooter()))
+		}
+
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
 
 	case viewError:
 		return m.renderError(m.width, m.height)
@@ -377,10 +1460,22 @@ func (m *Model) renderError(width, height int) string {
 }
 
 func (m *Model) buildQueryResultsTable(headers []string, results []map[string]db.RowResult) ([][]string, []string) {
+	if m.autoExpand {
+		rows, tableHeaders := m.buildQueryResultsRows(headers, results)
+		if m.expandedDisplay = m.exceedsWidth(rows, tableHeaders); !m.expandedDisplay {
+			return rows, tableHeaders
+		}
+	}
+
 	if m.expandedDisplay {
 		return m.buildExpandedQueryResultsTable(headers, results)
 	}
 
+	return m.buildQueryResultsRows(headers, results)
+}
+
+// buildQueryResultsRows returns the rows of the results with a row number column
+func (m *Model) buildQueryResultsRows(headers []string, results []map[string]db.RowResult) ([][]string, []string) {
 	rows := [][]string{}
 
 	headers = append([]string{"#"}, headers...)
@@ -392,9 +1487,9 @@ func (m *Model) buildQueryResultsTable(headers []string, results []map[string]db
 
 				var value string
 				if val.Value == nil {
-					value = "NULL"
+					value = m.nullDisplay
 				} else {
-					value = fmt.Sprintf("%v", db.FormatValue(val.Value, val.Type))
+					value = fmt.Sprintf("%v", db.FormatValue(m.timeDisplay.Apply(val.Value, val.Type), val.Type))
 				}
 
 				rowData[j] = strings.ReplaceAll(value, "\n", " ")
@@ -402,7 +1497,7 @@ func (m *Model) buildQueryResultsTable(headers []string, results []map[string]db
 				if header == "#" {
 					rowData[j] = fmt.Sprintf("%d", i+1)
 				} else {
-					rowData[j] = "NULL"
+					rowData[j] = m.nullDisplay
 				}
 			}
 		}
@@ -411,6 +1506,27 @@ func (m *Model) buildQueryResultsTable(headers []string, results []map[string]db
 	return rows, headers
 }
 
+// exceedsWidth reports whether the table of rows is wider than the results
+// pane, with each column as wide as its longest value plus its padding and border
+func (m *Model) exceedsWidth(rows [][]string, headers []string) bool {
+	width := 1
+	for j, header := range headers {
+		column := lipgloss.Width(header)
+		for _, row := range rows {
+			if j < len(row) {
+				column = max(column, lipgloss.Width(row[j]))
+			}
+		}
+
+		width += column + tableColumnChrome
+		if width > m.width-1 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // buildExpandedTable is a generic helper for creating expanded display tables
 // valueExtractor is a function that extracts the value for a given row index and header
 func (m *Model) buildExpandedTable(headers []string, rowCount int, valueExtractor func(rowIndex int, header string) string) ([][]string, []string) {
@@ -436,19 +1552,31 @@ func (m *Model) buildExpandedQueryResultsTable(headers []string, results []map[s
 		row := results[rowIndex]
 		if val, ok := row[header]; ok {
 			if val.Value == nil {
-				return "NULL"
+				return m.nullDisplay
 			}
-			return fmt.Sprintf("%v", db.FormatValue(val.Value, val.Type))
+			return fmt.Sprintf("%v", db.FormatValue(m.timeDisplay.Apply(val.Value, val.Type), val.Type))
 		}
-		return "NULL"
+		return m.nullDisplay
 	})
 }
 
 func (m *Model) buildPsqlCommandTable(headers []string, results []map[string]any) ([][]string, []string) {
+	if m.autoExpand {
+		rows, tableHeaders := m.buildPsqlCommandRows(headers, results)
+		if m.expandedDisplay = m.exceedsWidth(rows, tableHeaders); !m.expandedDisplay {
+			return rows, tableHeaders
+		}
+	}
+
 	if m.expandedDisplay {
 		return m.buildExpandedPsqlCommandTable(headers, results)
 	}
 
+	return m.buildPsqlCommandRows(headers, results)
+}
+
+// buildPsqlCommandRows returns the rows of a psql result with a row number column
+func (m *Model) buildPsqlCommandRows(headers []string, results []map[string]any) ([][]string, []string) {
 	rows := [][]string{}
 
 	headers = append([]string{"#"}, headers...)
@@ -460,7 +1588,7 @@ func (m *Model) buildPsqlCommandTable(headers []string, results []map[string]any
 
 				var value string
 				if val == nil {
-					value = "NULL"
+					value = m.nullDisplay
 				} else {
 					value = fmt.Sprintf("%v", val)
 				}
@@ -470,7 +1598,7 @@ func (m *Model) buildPsqlCommandTable(headers []string, results []map[string]any
 				if header == "#" {
 					rowData[j] = fmt.Sprintf("%d", i+1)
 				} else {
-					rowData[j] = "NULL"
+					rowData[j] = m.nullDisplay
 				}
 			}
 		}
@@ -484,11 +1612,11 @@ func (m *Model) buildExpandedPsqlCommandTable(headers []string, results []map[st
 		row := results[rowIndex]
 		if val, ok := row[header]; ok {
 			if val == nil {
-				return "NULL"
+				return m.nullDisplay
 			}
 			return fmt.Sprintf("%v", val)
 		}
-		return "NULL"
+		return m.nullDisplay
 	})
 }
 
@@ -517,6 +1645,26 @@ func (m *Model) setViewportContent() {
 			lipgloss.NewStyle().Render(fmt.Sprintf("Tables shared with LLM: %s", lipgloss.NewStyle().Bold(true).Render(m.renderSharedTablesList()))),
 		)
 
+		if m.onConnectOutput != "" {
+			content = lipgloss.JoinVertical(
+				lipgloss.Left,
+				content,
+				"",
+				lipgloss.NewStyle().Bold(true).Render("On connect:"),
+				m.onConnectOutput,
+			)
+		}
+
+		if m.sessionInfo != "" {
+			content = lipgloss.JoinVertical(
+				lipgloss.Left,
+				content,
+				"",
+				lipgloss.NewStyle().Bold(true).Render("Session:"),
+				m.sessionInfo,
+			)
+		}
+
 		m.viewport.SetContent(padding.Render(content))
 
 	case viewDBSchema:
@@ -527,14 +1675,85 @@ func (m *Model) setViewportContent() {
 	}
 }
 
+// startNumbered yanks the only code block, or opens the only link, of the LLM
+// explanation, or waits for the number of the one to pick
+func (m Model) startNumbered(action string) (Model, tea.Cmd) {
+	count := len(m.llmDocument.CodeBlocks)
+	if action == "o" {
+		count = len(m.llmDocument.Links)
+	}
+
+	switch count {
+	case 0:
+		return m, nil
+	case 1:
+		return m.pickNumbered(action, 1)
+	}
+
+	m.pendingNumber = action
+	return m, nil
+}
+
+// pickNumbered yanks the code block, or opens the link, numbered n in the LLM
+// explanation
+func (m Model) pickNumbered(action string, n int) (Model, tea.Cmd) {
+	if action == "o" {
+		if n < 1 || n > len(m.llmDocument.Links) {
+			return m, nil
+		}
+
+		link := m.llmDocument.Links[n-1]
+		return m, func() tea.Msg {
+			return OpenLinkMsg{URL: link}
+		}
+	}
+
+	if n < 1 || n > len(m.llmDocument.CodeBlocks) {
+		return m, nil
+	}
+
+	code := m.llmDocument.CodeBlocks[n-1].Code
+	return m, func() tea.Msg {
+		return YankCodeBlockMsg{Code: code}
+	}
+}
+
+// numberedActionsHint tells how to yank the code blocks and open the links
+// of doc, empty without any
+func numberedActionsHint(doc markdown.Document) string {
+	var actions []string
+
+	switch len(doc.CodeBlocks) {
+	case 0:
+	case 1:
+		actions = append(actions, "y to copy the code block")
+	default:
+		actions = append(actions, "y and its number to copy a code block")
+	}
+
+	switch len(doc.Links) {
+	case 0:
+	case 1:
+		actions = append(actions, "o to open the link")
+	default:
+		actions = append(actions, "o and its number to open a link")
+	}
+
+	if len(actions) == 0 {
+		return ""
+	}
+
+	return "Press " + strings.Join(actions, ", ") + "."
+}
+
 func (m Model) yankSelectedCell() (Model, tea.Cmd) {
-	if cell, ok := m.table.GetSelectedCell(); ok {
+	if cell, ok := m.selectedCell(); ok {
 
 		if err := clipboard.Write(cell); err != nil {
 			return m, nil
 		}
 
-		defaultTheme := styles.TableTheme(m.styles)
+		defaultTheme := m.tableTheme()
 		theme := table.Theme{
 			Header:      defaultTheme.Header,
 			Border:      defaultTheme.Border,
@@ -553,10 +1772,140 @@ func (m Model) yankSelectedCell() (Model, tea.Cmd) {
 	return m, nil
 }
 
+// toggleVisualMode starts or ends a rectangular selection anchored at the selected cell
+func (m *Model) toggleVisualMode() {
+	if m.visual {
+		m.ExitVisualMode()
+		return
+	}
+
+	if m.expandedDisplay || len(m.rawTableRows) == 0 || len(m.tableHeaders) < 2 {
+		return
+	}
+
+	m.visual = true
+	m.visualRow = m.table.GetSelectedRow()
+	m.visualColumn = max(1, m.table.GetSelectedColumn())
+	m.table.SetSize(m.width-1, m.tableHeight())
+	m.highlightVisualSelection()
+}
+
+// ExitVisualMode ends the visual selection
+func (m *Model) ExitVisualMode() {
+	if !m.visual {
+		return
+	}
+
+	m.visual = false
+	m.highlightVisualSelection()
+	m.table.SetSize(m.width-1, m.tableHeight())
+}
+
+// IsVisualMode reports whether cells are being selected in visual mode
+func (m *Model) IsVisualMode() bool {
+	return m.visual
+}
+
+// visualSelection returns the first and last row and column of the selection.
+// The row number column is never part of it.
+func (m *Model) visualSelection() (top, bottom, left, right int) {
+	row, column := m.table.GetSelectedRow(), max(1, m.table.GetSelectedColumn())
+	return min(row, m.visualRow), max(row, m.visualRow), min(column, m.visualColumn), max(column, m.visualColumn)
+}
+
+// highlightVisualSelection highlights the rows of the selection. The table
+// only styles whole rows, the selected columns are shown below it.
+func (m *Model) highlightVisualSelection() {
+	theme := styles.TableTheme(m.styles)
+
+	for row := m.highlightedRows[0]; row <= m.highlightedRows[1]; row++ {
+		m.table.SetRowStyle(row, m.rowStyle(row))
+	}
+	m.highlightedRows = [2]int{0, -1}
+
+	if !m.visual {
+		return
+	}
+
+	top, bottom, _, _ := m.visualSelection()
+	for row := top; row <= bottom; row++ {
+		m.table.SetRowStyle(row, theme.SelectedRow)
+	}
+	m.highlightedRows = [2]int{top, bottom}
+}
+
+// renderVisualSelection describes the selected range and the actions available on it
+func (m *Model) renderVisualSelection() string {
+	top, bottom, left, right := m.visualSelection()
+
+	columns := m.tableHeaders[left]
+	if right > left {
+		columns += ".." + m.tableHeaders[right]
+	}
+
+	selection := fmt.Sprintf("-- VISUAL -- rows %d-%d, columns %s (%dx%d)  y yank as TSV, e export, esc cancel",
+		top+1, bottom+1, columns, bottom-top+1, right-left+1)
+
+	return m.styles.Subtext1.Render(truncate(selection, max(0, m.width-2)))
+}
+
+// SelectedRange returns the 1-based rows and the columns selected in visual mode
+func (m *Model) SelectedRange() ([]int, []string, bool) {
+	if !m.visual {
+		return nil, nil, false
+	}
+
+	top, bottom, left, right := m.visualSelection()
+
+	rows := make([]int, 0, bottom-top+1)
+	for row := top; row <= bottom; row++ {
+		rows = append(rows, row+1)
+	}
+
+	return rows, slices.Clone(m.tableHeaders[left : right+1]), true
+}
+
+// tsvEscaper keeps every value on a single TSV field
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+// visualSelectionTSV returns the selected cells with their column names as
+// tab-separated values. Numbers are not formatted and truncated cells are
+// copied in full.
+func (m *Model) visualSelectionTSV() string {
+	top, bottom, left, right := m.visualSelection()
+
+	lines := make([]string, 0, bottom-top+2)
+	lines = append(lines, strings.Join(m.tableHeaders[left:right+1], "\t"))
+
+	for _, row := range m.rawTableRows[top:min(bottom+1, len(m.rawTableRows))] {
+		fields := make([]string, 0, right-left+1)
+		for column := left; column <= right; column++ {
+			var value string
+			if column < len(row) {
+				value = tsvEscaper.Replace(row[column])
+			}
+			fields = append(fields, value)
+		}
+		lines = append(lines, strings.Join(fields, "\t"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) yankVisualSelection() (Model, tea.Cmd) {
+	if err := clipboard.Write(m.visualSelectionTSV()); err != nil {
+		return m, nil
+	}
+
+	m.ExitVisualMode()
+
+	return m, nil
+}
+
 func (m Model) yankSelectedRow() (Model, tea.Cmd) {
 	row := m.table.GetSelectedRow()
 
-	data := m.queryResults[row]
+	data := m.timeDisplay.ApplyRows(m.queryResults[row:row+1], m.columnTypes)[0]
 
 	var jsonData []byte
 	var err error
@@ -574,7 +1923,7 @@ func (m Model) yankSelectedRow() (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	defaultTheme := styles.TableTheme(m.styles)
+	defaultTheme := m.tableTheme()
 	selectedRow := defaultTheme.SelectedRow.
 		Background(defaultTheme.SelectedRow.GetForeground()).
 		Foreground(defaultTheme.SelectedRow.GetBackground())
@@ -0,0 +1,753 @@
+package content
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/geo"
+	"github.com/ionut-t/perp/pkg/highlight"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/numfmt"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModel() Model {
+	m := New(80, 40)
+	m.SetStyles(styles.New(true), true)
+	return m
+}
+
+func TestSetLLMResponseSuggestion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		command            llm.Command
+		query              string
+		response           string
+		expectedSuggestion string
+	}{
+		{
+			name:               "fix with changes stores suggestion",
+			command:            llm.Fix,
+			query:              "-- fix\nSELECT * FROM usr",
+			response:           "Typo in table name.\n```sql\nSELECT * FROM users\n```",
+			expectedSuggestion: "SELECT * FROM users",
+		},
+		{
+			name:               "optimise with changes stores suggestion",
+			command:            llm.Optimise,
+			query:              "SELECT * FROM users; -- optimise",
+			response:           "```sql\nSELECT id FROM users;\n```",
+			expectedSuggestion: "SELECT id FROM users;",
+		},
+		{
+			name:               "fix without changes stores nothing",
+			command:            llm.Fix,
+			query:              "SELECT * FROM users; -- fix",
+			response:           "Looks fine.\n```sql\nSELECT * FROM users;\n```",
+			expectedSuggestion: "",
+		},
+		{
+			name:               "explain never stores a suggestion",
+			command:            llm.Explain,
+			query:              "-- explain\nSELECT 1",
+			response:           "```sql\nSELECT 2\n```",
+			expectedSuggestion: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestModel()
+			m.SetLLMResponse(llm.Response{Response: tt.response, Command: tt.command}, tt.query)
+
+			assert.Equal(t, viewLLMExplanation, m.view)
+			assert.Equal(t, tt.expectedSuggestion, m.llmSuggestion)
+			assert.Equal(t, tt.expectedSuggestion != "", m.HasPendingSuggestion())
+
+			rendered := m.viewport.GetContent()
+			assert.Equal(t, tt.expectedSuggestion != "", strings.Contains(rendered, "Changes"))
+		})
+	}
+}
+
+func TestSetLLMResponseClearsPreviousSuggestion(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: "```sql\nSELECT id FROM users\n```", Command: llm.Fix}, "SELECT * FROM users")
+	require.True(t, m.HasPendingSuggestion())
+
+	m.SetLLMResponse(llm.Response{Response: "Answer", Command: llm.Ask}, "/ask question")
+	assert.False(t, m.HasPendingSuggestion())
+}
+
+func TestEnterAppliesPendingSuggestion(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: "```sql\nSELECT id FROM users\n```", Command: llm.Optimise}, "SELECT * FROM users")
+	require.True(t, m.HasPendingSuggestion())
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+
+	msg, ok := cmd().(LLMResponseSelectedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "SELECT id FROM users", msg.Response)
+	assert.False(t, m.HasPendingSuggestion())
+}
+
+func TestEnterWithoutSuggestionDoesNotApply(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: "Answer", Command: llm.Explain}, "-- explain\nSELECT 1")
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil {
+		_, ok := cmd().(LLMResponseSelectedMsg)
+		assert.False(t, ok)
+	}
+}
+
+func TestLLMResponseCodeBlocksAndLinks(t *testing.T) {
+	t.Parallel()
+
+	response := strings.Join([]string{
+		"Create the index, see [the docs](https://www.postgresql.org/docs/current/sql-createindex.html):",
+		"",
+		"```sql",
+		"CREATE INDEX ON orders (user_id);",
+		"```",
+		"",
+		"Then check https://wiki.postgresql.org/wiki/Index_Maintenance.",
+		"",
+		"~~~",
+		"ANALYZE orders;",
+		"~~~",
+		"",
+		"| table | rows | note |",
+		"| --- | --- | :-: |",
+		"| orders | 1,024 | 12 |",
+	}, "\n")
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: response, Command: llm.Ask}, "/ask index orders")
+
+	require.Len(t, m.llmDocument.CodeBlocks, 2)
+	assert.Equal(t, "sql", m.llmDocument.CodeBlocks[0].Language)
+	assert.Equal(t, "ANALYZE orders;", m.llmDocument.CodeBlocks[1].Code)
+	assert.Equal(t, []string{
+		"https://www.postgresql.org/docs/current/sql-createindex.html",
+		"https://wiki.postgresql.org/wiki/Index_Maintenance",
+	}, m.llmDocument.Links)
+
+	assert.Contains(t, m.llmDocument.Markdown, "_[1] sql_")
+	assert.Contains(t, m.llmDocument.Markdown, "_[2]_")
+	assert.Contains(t, m.llmDocument.Markdown, "| --- | ---: | :-: |", "numbers are aligned right unless the alignment is set")
+	assert.Contains(t, m.llmDocument.Markdown, "2. https://wiki.postgresql.org/wiki/Index_Maintenance")
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	assert.Nil(t, cmd, "several code blocks wait for a number")
+
+	m, cmd = m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, YankCodeBlockMsg{Code: "ANALYZE orders;"}, cmd())
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: '1', Text: "1"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, OpenLinkMsg{URL: "https://www.postgresql.org/docs/current/sql-createindex.html"}, cmd())
+}
+
+func TestLLMResponseSingleCodeBlock(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: "```sql\nSELECT 1;\n```", Command: llm.Ask}, "/ask one")
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, YankCodeBlockMsg{Code: "SELECT 1;"}, cmd())
+
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	assert.Nil(t, cmd, "there is no link to open")
+}
+
+func TestAppendQueryRows(t *testing.T) {
+	t.Parallel()
+
+	row := func(id int64) map[string]db.RowResult {
+		return map[string]db.RowResult{"id": {Value: id, Type: pgtype.Int8OID}}
+	}
+
+	// asksForRows reports whether cmd sends FetchMoreRowsMsg, alone or batched
+	asksForRows := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if c == nil {
+					continue
+				}
+				if _, ok := c().(FetchMoreRowsMsg); ok {
+					return true
+				}
+			}
+			return false
+		}
+
+		_, ok := msg.(FetchMoreRowsMsg)
+		return ok
+	}
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id FROM events",
+		Columns: []string{"id"},
+		Rows:    []map[string]db.RowResult{row(1), row(2)},
+	}))
+	m.SetMoreRows(true)
+	assert.Contains(t, m.renderFooter(), "2 rows so far")
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	require.True(t, asksForRows(cmd), "selecting the last row asks for more")
+
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	assert.False(t, asksForRows(cmd), "the rows are asked for once")
+
+	m.AppendQueryRows([]map[string]db.RowResult{row(3)}, false)
+
+	assert.Equal(t, 1, m.table.GetSelectedRow(), "the selection is kept")
+	assert.Equal(t, 3, m.resultInfo.Rows)
+	assert.Len(t, m.GetQueryResults(), 3)
+	assert.NotContains(t, m.renderFooter(), "so far")
+
+	m.table.SetSelectedCell(2, 0)
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	assert.False(t, asksForRows(cmd), "every row was fetched")
+}
+
+func TestSelectedPlanNode(t *testing.T) {
+	t.Parallel()
+
+	plan := []string{
+		"Seq Scan on jobs  (cost=0.00..35.50 rows=10 width=40)",
+		"  Filter: (status = 'failed'::text)",
+	}
+
+	rows := make([]map[string]db.RowResult, len(plan))
+	for i, line := range plan {
+		rows[i] = map[string]db.RowResult{queryPlanColumn: {Value: line}}
+	}
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "EXPLAIN SELECT * FROM jobs WHERE status = 'failed'",
+		Columns: []string{queryPlanColumn},
+		Rows:    rows,
+	}))
+
+	node, fullPlan, ok := m.SelectedPlanNode()
+	require.True(t, ok)
+	assert.Equal(t, plan, fullPlan)
+	assert.Equal(t, "Seq Scan on jobs  (cost=0.00..35.50 rows=10 width=40)\nFilter: (status = 'failed'::text)", node)
+}
+
+func TestSelectedPlanNodeRequiresPlan(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id FROM jobs",
+		Columns: []string{"id"},
+		Rows:    []map[string]db.RowResult{{"id": {Value: 1}}},
+	}))
+
+	_, _, ok := m.SelectedPlanNode()
+	assert.False(t, ok)
+}
+
+func TestSetReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetLLMResponse(llm.Response{Response: "```sql\nSELECT id FROM users\n```", Command: llm.Fix}, "SELECT * FROM users")
+
+	m.SetReleaseNotes("# v1.2.0\n\n- Release notes viewer")
+
+	assert.Equal(t, viewReleaseNotes, m.view)
+	assert.False(t, m.HasPendingSuggestion())
+	assert.Contains(t, m.viewport.GetContent(), "viewer")
+}
+
+func TestSetPipeOutput(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetPipeOutput("jq length", "2\n")
+
+	assert.Equal(t, viewPipeOutput, m.view)
+	assert.Contains(t, m.viewport.GetContent(), "jq length")
+	assert.Contains(t, m.viewport.GetContent(), "2")
+
+	m.SetPipeOutput("true", "")
+	assert.Contains(t, m.viewport.GetContent(), "(no output)")
+}
+
+func TestImagePreview(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetSize(80, 20)
+	m.view = viewTable
+
+	m.SetImagePreview("PNG image, 40×20 px, 1.2 KB", "")
+	assert.True(t, m.IsImagePreview())
+	assert.Contains(t, m.viewport.GetContent(), "PNG image")
+
+	top, left, cols, rows := m.ImagePreviewArea()
+	assert.Equal(t, imagePreviewHeaderHeight, top)
+	assert.Equal(t, 1, left)
+	assert.Equal(t, 78, cols)
+	assert.Equal(t, 20-imagePreviewHeaderHeight, rows)
+
+	m.CloseImagePreview()
+	assert.False(t, m.IsImagePreview())
+	assert.Equal(t, viewTable, m.view)
+}
+
+func TestGeometryPreview(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetSize(80, 30)
+
+	g, ok := geo.Parse("0102000020E610000002000000000000000000000000000000000000000000000000001040000000000000F03F")
+	require.True(t, ok)
+
+	m.SetGeometryPreview(g)
+
+	assert.Equal(t, viewGeometryPreview, m.view)
+	content := m.viewport.GetContent()
+	assert.Contains(t, content, "LineString (SRID 4326)")
+	assert.Contains(t, content, "SRID=4326;LINESTRING(0 0,4 1)")
+	assert.Contains(t, content, "*")
+}
+
+func TestSetTimeDisplay(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2025, 7, 1, 12, 30, 0, 0, time.UTC)
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id, created_at FROM jobs",
+		Columns: []string{"id", "created_at"},
+		Rows: []map[string]db.RowResult{{
+			"id":         {Value: 1, Type: pgtype.Int4OID},
+			"created_at": {Value: ts, Type: pgtype.TimestamptzOID},
+		}},
+	}))
+
+	assert.Equal(t, ts, m.GetQueryResults()[0]["created_at"])
+
+	display, err := db.NewTimeDisplay("Europe/London", time.DateTime)
+	require.NoError(t, err)
+	m.SetTimeDisplay(display)
+
+	assert.Equal(t, "2025-07-01 13:30:00", m.tableRows[0][2])
+	assert.Equal(t, "2025-07-01 13:30:00", m.GetQueryResults()[0]["created_at"])
+	assert.Equal(t, ts, m.queryResults[0]["created_at"], "raw results are kept")
+}
+
+func TestFormatNumbers(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetNumberSeparators(numfmt.ForLocale("de_DE.UTF-8"))
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id, price, zip FROM products",
+		Columns: []string{"id", "price", "zip"},
+		Rows: []map[string]db.RowResult{{
+			"id":    {Value: int64(1234567), Type: pgtype.Int8OID},
+			"price": {Value: "12345.50", Type: pgtype.NumericOID},
+			"zip":   {Value: "10115", Type: pgtype.TextOID},
+		}},
+	}))
+
+	assert.Equal(t, []string{"1", "1.234.567", "12.345,50", "10115"}, m.tableRows[0])
+
+	m.table.SetSelectedCell(0, 1)
+	cell, ok := m.selectedCell()
+	require.True(t, ok)
+	assert.Equal(t, "1234567", cell, "yank uses the raw value")
+
+	assert.True(t, m.ToggleRawNumbers())
+	assert.Equal(t, []string{"1", "1234567", "12345.50", "10115"}, m.tableRows[0])
+
+	assert.False(t, m.ToggleRawNumbers())
+	assert.Equal(t, "1.234.567", m.tableRows[0][1])
+}
+
+func TestResizeSelectedColumn(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id, email FROM users",
+		Columns: []string{"id", "email"},
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}, "email": {Value: "alice@example.com", Type: pgtype.TextOID}},
+			{"id": {Value: int64(2), Type: pgtype.Int8OID}, "email": {Value: "bob@x.io", Type: pgtype.TextOID}},
+		},
+	}))
+
+	m.table.SetSelectedCell(0, 2)
+
+	m, cmd := m.resizeSelectedColumn(-columnWidthStep)
+	require.NotNil(t, cmd)
+	assert.Equal(t, ColumnWidthsChangedMsg{Query: "SELECT id, email FROM users", Widths: map[string]int{"email": 13}}, cmd())
+
+	assert.Equal(t, "alice@exam...", m.tableRows[0][2])
+	assert.Equal(t, "bob@x.io", m.tableRows[1][2])
+	assert.Contains(t, m.renderTruncatedPreview(), "email: alice@example.com")
+	assert.Equal(t, m.height-2, m.tableHeight(), "query header and preview footer")
+
+	cell, ok := m.selectedCell()
+	require.True(t, ok)
+	assert.Equal(t, "alice@example.com", cell, "yank uses the full value")
+
+	m, _ = m.resizeSelectedColumn(columnWidthStep)
+	assert.Empty(t, m.columnWidths)
+	assert.Equal(t, "alice@example.com", m.tableRows[0][2])
+	assert.Equal(t, m.height-1, m.tableHeight())
+
+	m.SetColumnWidths(map[string]int{"email": 1})
+	assert.Equal(t, "...", m.tableRows[1][2])
+}
+
+func TestQueryHeader(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	assert.Equal(t, m.height, m.tableHeight())
+
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "  SELECT id\n  FROM users;  ",
+		Columns: []string{"id"},
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}},
+		},
+	}))
+
+	assert.Equal(t, "SELECT id\n  FROM users;", m.executedQuery)
+	assert.Equal(t, m.height-1, m.tableHeight())
+	assert.Contains(t, m.renderQueryHeader(), "SELECT id FROM users;")
+	assert.Contains(t, m.View(), "SELECT id FROM users;")
+
+	m.SetSource("staging")
+	assert.Contains(t, m.renderQueryHeader(), "[staging]")
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'Q', Text: "Q"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, YankQueryMsg{Query: "SELECT id\n  FROM users;"}, cmd())
+}
+
+func TestQueryResultPages(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResultPages([]ParsedQueryResult{
+		{Query: "UPDATE users SET active = true", AffectedRows: 3},
+		{
+			Query:   "SELECT id FROM users",
+			Columns: []string{"id"},
+			Rows: []map[string]db.RowResult{
+				{"id": {Value: int64(1), Type: pgtype.Int8OID}},
+			},
+		},
+	}))
+
+	assert.Equal(t, viewTable, m.view, "the last result is shown first")
+	assert.Equal(t, "SELECT id FROM users", m.executedQuery)
+	assert.Contains(t, m.renderQueryHeader(), "[2/2]")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: ']', Text: "]"})
+	assert.Equal(t, 1, m.page, "there is no next page")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '[', Text: "["})
+	assert.Equal(t, viewInfo, m.view)
+	assert.Equal(t, "UPDATE users SET active = true", m.executedQuery)
+	assert.Contains(t, m.viewport.View(), "[1/2]")
+	assert.Contains(t, m.viewport.View(), "Affected rows: 3")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: ']', Text: "]"})
+	assert.Equal(t, "SELECT id FROM users", m.executedQuery)
+
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{Query: "DELETE FROM sessions"}))
+	assert.NotContains(t, m.viewport.View(), "[1/1]")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '[', Text: "["})
+	assert.Equal(t, "DELETE FROM sessions", m.executedQuery, "a single result has no pages")
+}
+
+func TestResultInfo(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:         "SELECT id, name FROM users",
+		Columns:       []string{"id", "name"},
+		ExecutionTime: 5 * time.Millisecond,
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}, "name": {Value: "alice", Type: pgtype.TextOID}},
+		},
+	}))
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: 'I', Text: "I"})
+	require.NotNil(t, cmd)
+
+	msg, ok := cmd().(ShowResultInfoMsg)
+	require.True(t, ok)
+	assert.Equal(t, "SELECT id, name FROM users", msg.Info.Query)
+	assert.Equal(t, 1, msg.Info.Rows)
+	assert.Equal(t, 5*time.Millisecond, msg.Info.ExecutionTime)
+	assert.Equal(t, []ResultColumn{{Name: "id", Type: "int8"}, {Name: "name", Type: "text"}}, msg.Info.Columns)
+	assert.False(t, msg.Info.Psql)
+
+	m.SetResultInfo("# Result info")
+	assert.Equal(t, viewResultInfo, m.view)
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'I', Text: "I"})
+	assert.Equal(t, viewTable, m.view)
+}
+
+func TestVisualSelection(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id, name, total FROM orders",
+		Columns: []string{"id", "name", "total"},
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}, "name": {Value: "alice\tsmith", Type: pgtype.TextOID}, "total": {Value: int64(1200), Type: pgtype.Int8OID}},
+			{"id": {Value: int64(2), Type: pgtype.Int8OID}, "name": {Value: "bob", Type: pgtype.TextOID}, "total": {Value: int64(35), Type: pgtype.Int8OID}},
+			{"id": {Value: int64(3), Type: pgtype.Int8OID}, "name": {Value: "carol", Type: pgtype.TextOID}, "total": {Value: int64(7), Type: pgtype.Int8OID}},
+		},
+	}))
+
+	m.table.SetSelectedCell(0, 2)
+	height := m.tableHeight()
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	require.True(t, m.IsVisualMode())
+	assert.Equal(t, height-1, m.tableHeight(), "the selection is described below the table")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'l', Text: "l"})
+
+	rows, columns, ok := m.SelectedRange()
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 2}, rows)
+	assert.Equal(t, []string{"name", "total"}, columns)
+	assert.Equal(t, [2]int{0, 1}, m.highlightedRows)
+	assert.Contains(t, m.renderVisualSelection(), "rows 1-2, columns name..total (2x2)")
+	assert.Equal(t, "name\ttotal\nalice smith\t1200\nbob\t35", m.visualSelectionTSV())
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, ExportSelectionMsg{}, cmd())
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.False(t, m.IsVisualMode())
+	assert.Equal(t, height, m.tableHeight())
+
+	_, _, ok = m.SelectedRange()
+	assert.False(t, ok)
+}
+
+func TestVisualSelectionSkipsRowNumbers(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id FROM users",
+		Columns: []string{"id"},
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}},
+		},
+	}))
+
+	m.table.SetSelectedCell(0, 0)
+	m.toggleVisualMode()
+
+	_, columns, ok := m.SelectedRange()
+	require.True(t, ok)
+	assert.Equal(t, []string{"id"}, columns)
+
+	m.SetExpandedDisplay(true)
+	assert.False(t, m.IsVisualMode())
+}
+
+func TestHighlightRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := highlight.ParseRules([]string{"status = 'failed' -> red", "total > 1000 -> bold"})
+	require.NoError(t, err)
+
+	m := newTestModel()
+	m.SetHighlightRules(rules)
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT status, total FROM orders",
+		Columns: []string{"status", "total"},
+		Rows: []map[string]db.RowResult{
+			{"status": {Value: "failed", Type: pgtype.TextOID}, "total": {Value: int64(1500), Type: pgtype.Int8OID}},
+			{"status": {Value: "done", Type: pgtype.TextOID}, "total": {Value: int64(20), Type: pgtype.Int8OID}},
+		},
+	}))
+
+	failed := m.rowStyle(0)
+	assert.Equal(t, lipgloss.Color("1"), failed.GetForeground())
+	assert.True(t, failed.GetBold(), "numbers are compared before being formatted")
+
+	assert.Equal(t, styles.TableTheme(m.styles).Cell, m.rowStyle(1))
+
+	m.SetExpandedDisplay(true)
+	assert.Equal(t, styles.TableTheme(m.styles).Cell, m.rowStyle(0))
+}
+
+func TestSetDisplayOptions(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT id, email FROM users",
+		Columns: []string{"id", "email"},
+		Rows: []map[string]db.RowResult{
+			{"id": {Value: int64(1), Type: pgtype.Int8OID}, "email": {Value: nil, Type: pgtype.TextOID}},
+			{"id": {Value: int64(2), Type: pgtype.Int8OID}, "email": {Value: "bob@x.io", Type: pgtype.TextOID}},
+		},
+	}))
+
+	assert.Equal(t, "NULL", m.tableRows[0][2])
+	assert.Equal(t, m.height-1, m.tableHeight())
+
+	options := psql.DefaultDisplayOptions()
+	options.Null = "(null)"
+	options.Border = 2
+	m.SetDisplayOptions(options)
+
+	assert.Equal(t, "(null)", m.tableRows[0][2])
+	assert.True(t, m.tableTheme().Border.GetBold())
+	assert.Equal(t, m.height-2, m.tableHeight(), "query header and row count footer")
+	assert.Contains(t, m.View(), "(2 rows)")
+
+	options.Expanded = true
+	m.SetDisplayOptions(options)
+	assert.Equal(t, []string{"email", "(null)"}, m.tableRows[2])
+}
+
+func TestAutoExpand(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	options := psql.DefaultDisplayOptions()
+	options.AutoExpand = true
+	m.SetDisplayOptions(options)
+
+	m.SetPsqlResult("\\dt", &psql.Result{
+		Columns: []string{"Schema", "Name"},
+		Rows:    []map[string]any{{"Schema": "public", "Name": "users"}},
+	})
+	assert.False(t, m.expandedDisplay, "a narrow result keeps the table")
+	assert.Equal(t, []string{"#", "Schema", "Name"}, m.tableHeaders)
+
+	m.SetPsqlResult("\\dt+", &psql.Result{
+		Columns: []string{"Schema", "Name", "Description"},
+		Rows:    []map[string]any{{"Schema": "public", "Name": "users", "Description": strings.Repeat("x", 80)}},
+	})
+	assert.True(t, m.expandedDisplay, "a result wider than the screen is expanded")
+	assert.Equal(t, []string{"Field", "Value"}, m.tableHeaders)
+
+	options.AutoExpand = false
+	m.SetDisplayOptions(options)
+	assert.False(t, m.expandedDisplay)
+}
+
+func TestSetSessionInfo(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	m.SetConnectionInfo(server.Server{Name: "local", Database: "shop"})
+	m.SetSessionInfo(&psql.Result{
+		Columns: []string{"Server Version", "SSL", "Backend PID", "Search Path"},
+		Rows: []map[string]any{{
+			"Server Version": "16.4",
+			"SSL":            "off",
+			"Backend PID":    int32(4242),
+			"Search Path":    nil,
+		}},
+	})
+
+	assert.Equal(t, viewConnectionInfo, m.view)
+
+	rendered := m.viewport.GetContent()
+	assert.Contains(t, rendered, "Session:")
+	assert.Contains(t, rendered, "16.4")
+	assert.Contains(t, rendered, "4242")
+	assert.Contains(t, rendered, "Search Path")
+
+	m.SetConnectionInfo(server.Server{Name: "local", Database: "shop"})
+	assert.NotContains(t, m.viewport.GetContent(), "Session:", "a new connection clears the session info")
+}
+
+func TestPinned(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel()
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT name FROM users",
+		Columns: []string{"name"},
+		Rows: []map[string]db.RowResult{
+			{"name": {Value: "alice", Type: pgtype.TextOID}},
+		},
+	}))
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, PinResultsMsg{}, cmd())
+
+	pinned := m.Pinned()
+
+	require.NoError(t, m.SetQueryResults(ParsedQueryResult{
+		Query:   "SELECT name FROM customers",
+		Columns: []string{"name"},
+		Rows: []map[string]db.RowResult{
+			{"name": {Value: "bob", Type: pgtype.TextOID}},
+		},
+	}))
+
+	assert.Contains(t, pinned.View(), "alice")
+	assert.NotContains(t, pinned.View(), "bob")
+	assert.Contains(t, m.View(), "bob")
+
+	query, _ := pinned.ExecutedQuery()
+	assert.Equal(t, "SELECT name FROM users", query)
+}
@@ -1,14 +1,26 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	tea "charm.land/bubbletea/v2"
 	"github.com/ionut-t/perp/pkg/db"
 	"github.com/ionut-t/perp/pkg/lsp"
+	"github.com/ionut-t/perp/pkg/schemaindex"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/tui/command"
 	"github.com/ionut-t/perp/tui/servers"
 )
 
 // closeDbConnection safely closes the database connection and LSP client.
 func (m model) closeDbConnection() {
+	// the pool waits for the connection held by the stream to be released
+	if m.stream != nil {
+		_ = m.stream.Close()
+	}
+
 	if m.db != nil {
 		m.db.Close()
 	}
@@ -30,15 +42,52 @@ func (m model) generateSchema() tea.Cmd {
 	}
 }
 
+// loadSchemaIndex builds the keyword index used to pick relevant tables for LLM prompts
+func (m model) loadSchemaIndex() tea.Cmd {
+	if m.db == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		index, err := schemaindex.Load(ctx, m.db)
+		if err != nil {
+			return notificationErrorMsg{err: err}
+		}
+
+		return schemaIndexLoadedMsg{index: index}
+	}
+}
+
+// connect opens the connection pool to dsn, sized by the pool settings of
+// the config
+func (m model) connect(dsn, onConnect string) (db.Database, error) {
+	var pool db.PoolSettings
+	for _, setting := range db.PoolSettingNames {
+		if err := pool.Set(setting, m.config.GetPoolSetting(setting)); err != nil {
+			return nil, fmt.Errorf("pool_%s in the config: %w", setting, err)
+		}
+	}
+
+	return db.New(dsn, onConnect, pool)
+}
+
 // handleServerConnection processes server selection and establishes database connection
 func (m *model) handleServerConnection(msg servers.SelectedServerMsg) (tea.Model, tea.Cmd) {
 	m.closeDbConnection()
+	m.stream = nil
 	m.lspClient = nil
 	m.view = viewMain
 	m.focused = focusedEditor
 	m.loading = true
 	m.server = msg.Server
-	m.db, m.error = db.New(m.server.String())
+	m.schemaIndex = nil
+	m.walStats = nil
+	m.reconnecting = false
+	m.loadLLMExamples()
+	m.db, m.error = m.connect(m.server.String(), m.server.OnConnect)
 
 	if m.error == nil {
 		m.content.SetConnectionInfo(m.server)
@@ -49,7 +98,7 @@ func (m *model) handleServerConnection(msg servers.SelectedServerMsg) (tea.Model
 			m.editor.SetPlaceholder("Type your SQL query")
 		}
 
-		return m, tea.Batch(m.generateSchema(), m.startLSP())
+		return m, tea.Batch(m.generateSchema(), m.startLSP(), m.runOnConnect())
 	}
 
 	m.loading = false
@@ -57,6 +106,63 @@ func (m *model) handleServerConnection(msg servers.SelectedServerMsg) (tea.Model
 	return m, m.spinner.Tick
 }
 
+// connectToServer switches the connection to the saved server named in the
+// command
+func (m model) connectToServer(msg command.ConnectMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.loading {
+		return m, nil
+	}
+
+	srv, err := server.FindByName(m.config.Storage(), msg.Server)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	return m.handleServerConnection(servers.SelectedServerMsg{Server: *srv})
+}
+
+// resetSession runs DISCARD ALL and then reconnects, so every connection of
+// the pool starts over without prepared statements, temporary tables or
+// settings changed with SET
+func (m model) resetSession() (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.db == nil {
+		return m, m.errorNotification(errors.New("not connected to a server"))
+	}
+
+	if m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		_, _, err := m.execStatement(ctx, m.db, "DISCARD ALL", nil)
+		return sessionResetMsg{err: err}
+	})
+}
+
+func (m model) handleSessionReset(msg sessionResetMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.loading = false
+		return m, m.errorNotification(fmt.Errorf("failed to reset the session: %w", msg.err))
+	}
+
+	_, cmd := m.handleServerConnection(servers.SelectedServerMsg{Server: m.server})
+
+	if m.error != nil {
+		return m, cmd
+	}
+
+	return m, tea.Batch(cmd, m.successNotification("Session reset and reconnected"))
+}
+
 // startLSP starts the postgres-language-server subprocess asynchronously.
 func (m *model) startLSP() tea.Cmd {
 	return func() tea.Msg {
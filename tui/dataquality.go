@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/dataquality"
+	"github.com/ionut-t/perp/pkg/utils"
+	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/tui/prompt"
+)
+
+// findDuplicates runs the query grouping the rows of the table by the given
+// columns and keeping the groups with more than one row
+func (m model) findDuplicates(msg command.FindDuplicatesMsg) (tea.Model, tea.Cmd) {
+	duplicates, err := dataquality.ParseDuplicates(msg.Input)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	return m.runDataQualityQuery(duplicates.SQL())
+}
+
+// findOrphans checks each foreign key of the table for rows whose parent is missing
+func (m model) findOrphans(msg command.FindOrphansMsg) (tea.Model, tea.Cmd) {
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		result, err := dataquality.Orphans(context.Background(), m.db, msg.Table)
+		if err != nil {
+			return psqlErrorMsg{err: err}
+		}
+
+		return psqlResultMsg{command: "Orphaned rows of " + msg.Table, result: result}
+	})
+}
+
+// hasRowsQuery reports whether a row of a data quality report is selected
+func (m model) hasRowsQuery() bool {
+	_, ok := m.content.SelectedValue(dataquality.RowsQueryColumn)
+	return ok
+}
+
+// showRows drills down into the selected row of a data quality report and
+// lists the table rows behind it
+func (m model) showRows() (tea.Model, tea.Cmd) {
+	query, ok := m.content.SelectedValue(dataquality.RowsQueryColumn)
+	if !ok || query == nil {
+		return m, m.errorNotification(errors.New("select a row of a data quality report first"))
+	}
+
+	return m.runDataQualityQuery(fmt.Sprint(query))
+}
+
+// runDataQualityQuery runs a generated query like one typed in the editor
+func (m model) runDataQualityQuery(query string) (tea.Model, tea.Cmd) {
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.executeQuery(query), m.spinner.Tick)
+}
+
+// selectedValidation returns the constraint selected in the NOT VALID
+// constraints report
+func (m model) selectedValidation() (dataquality.Validation, bool) {
+	statement, ok := m.content.SelectedValue(dataquality.ValidateStatementColumn)
+	if !ok || statement == nil {
+		return dataquality.Validation{}, false
+	}
+
+	table, _ := m.content.SelectedValue(dataquality.ConstraintTableColumn)
+	constraint, _ := m.content.SelectedValue(dataquality.ConstraintNameColumn)
+	kind, _ := m.content.SelectedValue(dataquality.ConstraintTypeColumn)
+
+	return dataquality.Validation{
+		Table:      fmt.Sprint(table),
+		Constraint: fmt.Sprint(constraint),
+		Type:       fmt.Sprint(kind),
+		Statement:  fmt.Sprint(statement),
+	}, true
+}
+
+// hasNotValidConstraint reports whether a row of the NOT VALID constraints report is selected
+func (m model) hasNotValidConstraint() bool {
+	_, ok := m.selectedValidation()
+	return ok
+}
+
+// checkValidationLocks looks for sessions holding locks the validation of
+// the selected constraint would wait for, before asking to run it
+func (m model) checkValidationLocks() (tea.Model, tea.Cmd) {
+	validation, ok := m.selectedValidation()
+	if !ok {
+		return m, m.errorNotification(errors.New("select a constraint of the NOT VALID constraints report first"))
+	}
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		conflicts, err := validation.ConflictingLocks(ctx, m.db)
+		return validationLocksMsg{validation: validation, conflicts: conflicts, err: err}
+	})
+}
+
+// confirmValidation warns about the locks taken by the validation and asks
+// to confirm it
+func (m model) confirmValidation(msg validationLocksMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(msg.err)
+	}
+
+	m.validation = &msg.validation
+	m.isPromptActive = true
+	m.prompt.SetAction(prompt.ValidateConstraintAction)
+
+	return m, m.warningNotification(msg.validation.LockWarning(msg.conflicts))
+}
+
+// validateConstraint runs ALTER TABLE ... VALIDATE CONSTRAINT. It scans the
+// whole table, so it runs without the query timeout.
+func (m model) validateConstraint() (tea.Model, tea.Cmd) {
+	validation := m.validation
+	m.validation = nil
+
+	if validation == nil || m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(
+		m.spinner.Tick,
+		m.successNotification(fmt.Sprintf("Validating %s on %s...", validation.Constraint, validation.Table)),
+		func() tea.Msg {
+			start := time.Now()
+
+			result, err := m.db.Query(context.Background(), validation.Statement)
+			if err == nil {
+				rows := result.Rows()
+				for rows.Next() {
+					continue
+				}
+				rows.Close()
+				err = rows.Err()
+			}
+
+			return constraintValidatedMsg{validation: *validation, elapsed: time.Since(start), err: err}
+		},
+	)
+}
+
+// handleConstraintValidated reports the validation and refreshes the NOT
+// VALID constraints report
+func (m model) handleConstraintValidated(msg constraintValidatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(fmt.Errorf("failed to validate %s: %w", msg.validation.Constraint, msg.err))
+	}
+
+	updated, cmd := m.runDataQualityQuery(dataquality.NotValidConstraintsSQL)
+	m = updated.(model)
+
+	return m, tea.Batch(
+		cmd,
+		m.successNotification(fmt.Sprintf("Validated %s on %s in %s",
+			msg.validation.Constraint, msg.validation.Table, utils.Duration(msg.elapsed))),
+	)
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/datasync"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// syncPreview compares the rows of a table on another saved server, the
+// source, with the connected one, the target
+func (m model) syncPreview(msg command.SyncPreviewMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	other, err := m.otherServer(msg.Server)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), SyncPreviewTimeout)
+		defer cancel()
+
+		source, err := m.connectReadOnly(other)
+		if err != nil {
+			return syncPreviewMsg{server: other.Name, sql: msg.SQL, err: err}
+		}
+		defer source.Close()
+
+		report, err := datasync.Compare(ctx, source, m.db, msg.Table)
+		return syncPreviewMsg{server: other.Name, sql: msg.SQL, report: report, err: err}
+	})
+}
+
+// handleSyncPreview lists the differing rows or puts the statements
+// reconciling them in the editor. The statements are never run for the user.
+func (m model) handleSyncPreview(msg syncPreviewMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(fmt.Errorf("sync preview with %s: %w", msg.server, msg.err))
+	}
+
+	if !msg.sql {
+		return m.handlePsqlResult(psqlResultMsg{
+			command: fmt.Sprintf("Rows of %s differing from %s", msg.report.Table, msg.server),
+			result:  msg.report.Result(),
+		})
+	}
+
+	if len(msg.report.Differences) == 0 {
+		return m, m.successNotification(fmt.Sprintf("%s is in sync with %s", msg.report.Table, msg.server))
+	}
+
+	editorCmd := m.applyQueryToEditor(msg.report.Statements())
+
+	return m, tea.Batch(
+		editorCmd,
+		m.successNotification(fmt.Sprintf("Statements syncing %s from %s added to the editor for review", msg.report.Table, msg.server)),
+	)
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/ui/confirm"
+)
+
+// destructiveQueryDialog is the ID of the dialog confirming a destructive query
+const destructiveQueryDialog = "destructive-query"
+
+// confirmDestructiveQuery asks to confirm the query before it drops objects
+// or data: the name of a dropped database is typed, several destructive
+// statements are ticked one by one, and a single one is answered with yes
+func (m model) confirmDestructiveQuery(msg destructiveQueryMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.destructiveQuery = msg.query
+
+	first := msg.statements[0]
+
+	switch {
+	case len(msg.statements) > 1:
+		items := make([]string, len(msg.statements))
+		for i, statement := range msg.statements {
+			items[i] = describeDestructive(statement)
+		}
+
+		m.confirm = confirm.NewChecklist(
+			destructiveQueryDialog,
+			"Destructive statements",
+			"Tick each statement to run the query",
+			items,
+		)
+
+	case first.Action == "DROP DATABASE":
+		m.confirm = confirm.NewTypeToConfirm(
+			destructiveQueryDialog,
+			"Drop database",
+			fmt.Sprintf("The database %s is removed with all of its data.", first.Object),
+			first.Object,
+		)
+
+	default:
+		m.confirm = confirm.NewYesNo(
+			destructiveQueryDialog,
+			"Destructive statement",
+			fmt.Sprintf("%s. Run it?", describeDestructive(first)),
+		)
+	}
+
+	m.confirm.SetStyles(m.styles)
+
+	return m, nil
+}
+
+// runDestructiveQuery runs the confirmed query
+func (m model) runDestructiveQuery() (tea.Model, tea.Cmd) {
+	query := m.destructiveQuery
+	m.destructiveQuery = ""
+
+	if query == "" || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, m.executeBuffer(query))
+}
+
+// describeDestructive capitalises the description of the statement, e.g.
+// "Deletes every row of orders"
+func describeDestructive(statement psql.Destructive) string {
+	description := statement.Description()
+	return strings.ToUpper(description[:1]) + description[1:]
+}
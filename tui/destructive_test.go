@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmDestructiveQuery(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("DELETE FROM orders;")
+
+	updated, _ := m.confirmDestructiveQuery(destructiveQueryMsg{
+		query:      "DELETE FROM orders;",
+		statements: psql.DestructiveStatements("DELETE FROM orders;"),
+	})
+	confirming := updated.(model)
+
+	assert.True(t, confirming.confirm.IsActive())
+	assert.False(t, confirming.loading)
+	assert.Equal(t, "DELETE FROM orders;", confirming.destructiveQuery)
+
+	updated, _ = confirming.runDestructiveQuery()
+	running := updated.(model)
+
+	assert.True(t, running.loading)
+	assert.Empty(t, running.destructiveQuery)
+
+	updated, cmd := running.runDestructiveQuery()
+	assert.Nil(t, cmd, "a query runs once per confirmation")
+	assert.True(t, updated.(model).loading)
+}
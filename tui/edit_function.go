@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/tui/prompt"
+)
+
+// loadFunction handles \ef by writing the definition of the function to a
+// temporary file for the external editor
+func (m model) loadFunction(cmd *psql.Command) tea.Msg {
+	signature, err := psql.FunctionSignature(cmd)
+	if err != nil {
+		return psqlErrorMsg{err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+	defer cancel()
+
+	definition, err := psql.FunctionDefinition(ctx, m.db, signature)
+	if err != nil {
+		return psqlErrorMsg{err: err}
+	}
+
+	file, err := os.CreateTemp("", "perp-function-*.sql")
+	if err != nil {
+		return psqlErrorMsg{err: fmt.Errorf("failed to create the function file: %w", err)}
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(definition); err != nil {
+		_ = os.Remove(file.Name())
+		return psqlErrorMsg{err: fmt.Errorf("failed to write the function file: %w", err)}
+	}
+
+	return editFunctionMsg{signature: signature, path: file.Name(), definition: definition}
+}
+
+// openFunctionEditor suspends the TUI while the function is edited
+func (m model) openFunctionEditor(msg editFunctionMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	return m, tea.ExecProcess(exec.Command(m.config.Editor(), msg.path), func(err error) tea.Msg {
+		return functionEditedMsg{editFunctionMsg: msg, err: err}
+	})
+}
+
+// handleFunctionEdited puts the changed definition in the editor and asks
+// whether to run it
+func (m model) handleFunctionEdited(msg functionEditedMsg) (tea.Model, tea.Cmd) {
+	defer os.Remove(msg.path)
+
+	if msg.err != nil {
+		return m, m.errorNotification(fmt.Errorf("editor failed: %w", msg.err))
+	}
+
+	content, err := os.ReadFile(msg.path)
+	if err != nil {
+		return m, m.errorNotification(fmt.Errorf("failed to read the function file: %w", err))
+	}
+
+	definition := strings.TrimSpace(string(content))
+	if definition == "" || definition == strings.TrimSpace(msg.definition) {
+		return m, m.successNotification(fmt.Sprintf("No changes to %s", msg.signature))
+	}
+
+	editorCmd := m.applyQueryToEditor(definition)
+
+	m.editedFunction = definition
+	m.isPromptActive = true
+	m.prompt.SetAction(prompt.RunEditedFunctionAction)
+
+	return m, editorCmd
+}
+
+// runEditedFunction runs the confirmed CREATE OR REPLACE statement like a
+// query typed in the editor
+func (m model) runEditedFunction() (tea.Model, tea.Cmd) {
+	definition := m.editedFunction
+	m.editedFunction = ""
+
+	if definition == "" || m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.executeQuery(definition), m.spinner.Tick)
+}
@@ -5,6 +5,8 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/psql"
 )
 
 // resetEditor clears the editor content and resets its state
@@ -33,27 +35,48 @@ func (m *model) focusEditor() {
 	m.editor.Focus()
 }
 
-// finishQueryExecution sets common state after query execution
-func (m *model) finishQueryExecution() {
+// finishQueryExecution sets common state after query execution and returns
+// the command closing the stream of the previous results, if any
+func (m *model) finishQueryExecution() tea.Cmd {
 	m.loading = false
+	closeCmd := m.closeStream()
 	m.focused = focusedContent
 	m.editor.Blur()
 	m.editor.SetNormalMode()
+
+	return closeCmd
 }
 
-// setHighlightedKeywords determines which keywords to highlight based on editor content
+// setHighlightedKeywords styles the LLM commands and comments and the psql
+// meta-commands of the editor content. Unknown commands are underlined.
 func (m model) setHighlightedKeywords() map[string]lipgloss.Style {
 	content := m.editor.GetCurrentContent()
+	unknown := m.styles.Error.Underline(true)
+	words := make(map[string]lipgloss.Style)
 
 	if strings.HasPrefix(content, "/") {
-		return m.llmKeywords
+		command := strings.Fields(content)[0]
+		if style, ok := m.llmKeywords[strings.ToLower(command)]; ok {
+			words[command] = style
+		} else {
+			words[command] = unknown
+		}
+		return words
+	}
+
+	for _, word := range llm.CommandCommentWords(content) {
+		words[word] = m.styles.Accent.Bold(true)
 	}
 
-	if strings.HasPrefix(content, "\\") {
-		return m.psqlCommands
+	for _, word := range psql.MetaCommandWords(content) {
+		if style, ok := m.psqlCommands[word]; ok {
+			words[word] = style
+		} else {
+			words[word] = unknown
+		}
 	}
 
-	return nil
+	return words
 }
 
 // isSQLContent reports whether the content should be sent to the LSP.
@@ -0,0 +1,285 @@
+package examples
+
+import (
+	"fmt"
+	"io"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/internal/keymap"
+	"github.com/ionut-t/perp/internal/whichkey"
+	"github.com/ionut-t/perp/pkg/examples"
+	"github.com/ionut-t/perp/pkg/utils"
+	"github.com/ionut-t/perp/ui/markdown"
+)
+
+var (
+	splitViewSeparator      = " "
+	splitViewSeparatorWidth = lipgloss.Width(splitViewSeparator)
+	minListWidth            = 50
+)
+
+var deleteExample = key.NewBinding(
+	key.WithKeys("d"),
+	key.WithHelp("d", "delete example"),
+)
+
+// SelectedMsg is sent when an example query is applied to the editor
+type SelectedMsg struct {
+	Query string
+}
+
+// DeleteMsg is sent when an example should be removed from the store
+type DeleteMsg struct {
+	Prompt string
+}
+
+type focused int
+
+const (
+	focusedList focused = iota
+	focusedViewport
+)
+
+type Model struct {
+	width, height int
+	list          list.Model
+	err           error
+	viewport      viewport.Model
+	focused       focused
+	markdown      markdown.Model
+	styles        styles.Styles
+}
+
+type item struct {
+	example examples.Example
+}
+
+func (i item) Title() string       { return i.example.Prompt }
+func (i item) Description() string { return "" }
+func (i item) FilterValue() string { return i.example.Prompt + " " + i.example.Query }
+
+type itemDelegate struct {
+	styles list.DefaultItemStyles
+}
+
+func (d itemDelegate) Height() int                             { return 1 }
+func (d itemDelegate) Spacing() int                            { return 1 }
+func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	str := fmt.Sprintf("%d) %s", index+1, i.Title())
+
+	fn := d.styles.NormalTitle.Render
+	if index == m.Index() {
+		fn = func(s ...string) string {
+			return d.styles.SelectedTitle.Render(s...)
+		}
+	}
+
+	_, _ = fmt.Fprint(w, fn(str))
+}
+
+func New(entries []examples.Example, width, height int) Model {
+	ls := list.New(processExamples(entries), list.NewDefaultDelegate(), 0, 0)
+	ls.Title = "LLM examples"
+
+	ls.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "apply query"),
+			),
+			deleteExample,
+		}
+	}
+
+	m := Model{
+		width:    width,
+		height:   height,
+		list:     ls,
+		viewport: viewport.New(),
+	}
+
+	m.SetSize(width, height)
+
+	return m
+}
+
+// SetExamples replaces the listed examples, e.g. after one was deleted
+func (m *Model) SetExamples(entries []examples.Example) {
+	m.list.SetItems(processExamples(entries))
+	m.renderSelected()
+}
+
+func (m *Model) SetStyles(s styles.Styles, isDark bool) {
+	m.styles = s
+	m.list.Styles = styles.ListStyles(s, isDark)
+	m.list.SetDelegate(itemDelegate{
+		styles: styles.ListItemStyles(s, isDark),
+	})
+	m.markdown = markdown.New(isDark)
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	w, h := m.getAvailableSizes()
+
+	horizontalFrameBorderSize := m.styles.ActiveBorder.GetHorizontalFrameSize()
+
+	listWidth := max(minListWidth, w/3)
+	detailWidth := w - listWidth - splitViewSeparatorWidth
+
+	borderV := m.styles.ActiveBorder.GetVerticalFrameSize()
+	paneContentHeight := h - borderV
+
+	m.list.SetSize(listWidth-horizontalFrameBorderSize, paneContentHeight)
+
+	m.viewport.SetWidth(detailWidth - horizontalFrameBorderSize)
+	m.viewport.SetHeight(paneContentHeight)
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && m.list.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, keymap.Submit):
+			if selected, ok := m.list.SelectedItem().(item); ok {
+				return m, utils.Dispatch(SelectedMsg{Query: selected.example.Query})
+			}
+
+		case key.Matches(msg, deleteExample):
+			if selected, ok := m.list.SelectedItem().(item); ok {
+				return m, utils.Dispatch(DeleteMsg{Prompt: selected.example.Prompt})
+			}
+
+		case key.Matches(msg, keymap.Quit) || key.Matches(msg, keymap.Cancel):
+			return m, utils.Dispatch(whichkey.CloseLLMExamplesCmd())
+		}
+
+		if msg.String() == "tab" {
+			if m.focused == focusedList {
+				m.focused = focusedViewport
+			} else {
+				m.focused = focusedList
+			}
+		}
+	}
+
+	switch m.focused {
+	case focusedList:
+		ls, cmd := m.list.Update(msg)
+		m.list = ls
+		cmds = append(cmds, cmd)
+		m.renderSelected()
+	case focusedViewport:
+		vp, cmd := m.viewport.Update(msg)
+		m.viewport = vp
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) View() string {
+	if len(m.list.Items()) == 0 {
+		return styles.ViewPadding.Render(
+			lipgloss.JoinVertical(
+				lipgloss.Left,
+				m.styles.Primary.Render("No LLM examples saved for this server."),
+				"\n",
+				m.styles.Subtext0.Render("Run a query generated with /ask, then press '<leader>la' to keep it as an example."),
+				m.styles.Subtext0.Render("Press 'q' to go back."),
+			),
+		)
+	}
+
+	return m.getSplitView()
+}
+
+func (m *Model) CanTriggerLeaderKey() bool {
+	return m.list.FilterState() != list.Filtering
+}
+
+func (m *Model) renderSelected() {
+	selected, ok := m.list.SelectedItem().(item)
+	if !ok {
+		m.viewport.SetContent("")
+		return
+	}
+
+	text := fmt.Sprintf("**Question:** %s\n\n```sql\n%s\n```\n\n_Saved %s_",
+		selected.example.Prompt,
+		selected.example.Query,
+		selected.example.CreatedAt.Format("02/01/2006 15:04:05"),
+	)
+
+	if out, err := m.markdown.Render(text); err != nil {
+		m.err = err
+	} else {
+		m.viewport.SetContent(out)
+		m.viewport.SetYOffset(0)
+	}
+}
+
+func (m *Model) getSplitView() string {
+	availableWidth, availableHeight := m.getAvailableSizes()
+
+	listWidth := max(minListWidth, availableWidth/3)
+	detailWidth := availableWidth - listWidth - splitViewSeparatorWidth
+
+	borderV := m.styles.ActiveBorder.GetVerticalFrameSize()
+	paneContentHeight := availableHeight - borderV
+
+	listBorder := m.styles.InactiveBorder
+	detailBorder := m.styles.InactiveBorder
+
+	if m.focused == focusedList {
+		listBorder = m.styles.ActiveBorder
+	} else {
+		detailBorder = m.styles.ActiveBorder
+	}
+
+	joinedContent := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		listBorder.
+			Width(listWidth).
+			Height(paneContentHeight).
+			Render(m.list.View()),
+		splitViewSeparator,
+		detailBorder.
+			Width(detailWidth).
+			Height(paneContentHeight).
+			Render(m.viewport.View()),
+	)
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(joinedContent)
+}
+
+func (m *Model) getAvailableSizes() (int, int) {
+	h, v := styles.ViewPadding.GetFrameSize()
+	return m.width - h, m.height - v
+}
+
+func processExamples(entries []examples.Example) []list.Item {
+	items := make([]list.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = item{example: entry}
+	}
+	return items
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -17,17 +18,48 @@ func (m model) exportQueryData(msg command.ExportMsg) (tea.Model, tea.Cmd) {
 		)
 	}
 
+	queryResults := m.content.GetQueryResults()
+
+	if msg.Selection {
+		rows, columns, ok := m.content.SelectedRange()
+		if !ok {
+			m.focusEditor()
+			return m, m.errorNotification(errors.New("select cells with v before exporting the selection"))
+		}
+
+		queryResults, msg.Rows = selectColumns(queryResults, columns), rows
+		m.content.ExitVisualMode()
+	}
+
 	if filepath.Ext(msg.Filename) == ".csv" {
-		return m.exportAsCSV(msg)
+		return m.exportAsCSV(msg, queryResults)
 	}
 
-	return m.exportAsJSON(msg)
+	return m.exportAsJSON(msg, queryResults)
 }
 
-// exportAsJSON exports query results as JSON
-func (m model) exportAsJSON(msg command.ExportMsg) (tea.Model, tea.Cmd) {
-	queryResults := m.content.GetQueryResults()
+// exportSelection opens the command bar to name the file the selected cells are exported to
+func (m model) exportSelection() (tea.Model, tea.Cmd) {
+	m.command.SetValue("export selection ")
+	return m.handleEnterCommandKey()
+}
+
+// selectColumns keeps only the given columns of the results
+func selectColumns(queryResults []map[string]any, columns []string) []map[string]any {
+	selected := make([]map[string]any, len(queryResults))
+	for i, row := range queryResults {
+		selected[i] = make(map[string]any, len(columns))
+		for _, column := range columns {
+			if value, ok := row[column]; ok {
+				selected[i][column] = value
+			}
+		}
+	}
+	return selected
+}
 
+// exportAsJSON exports query results as JSON
+func (m model) exportAsJSON(msg command.ExportMsg, queryResults []map[string]any) (tea.Model, tea.Cmd) {
 	data, err := export.PrepareJSON(queryResults, msg.Rows, msg.All)
 	if err != nil {
 		m.focusEditor()
@@ -43,15 +75,17 @@ func (m model) exportAsJSON(msg command.ExportMsg) (tea.Model, tea.Cmd) {
 	m.focusEditor()
 	m.command.Reset()
 
+	if cmd := m.describeExport(storage, fileName, data); cmd != nil {
+		return m, cmd
+	}
+
 	return m, m.successNotification(
 		fmt.Sprintf("Data exported as JSON to %s", fileName),
 	)
 }
 
 // exportAsCSV exports query results as CSV
-func (m model) exportAsCSV(msg command.ExportMsg) (tea.Model, tea.Cmd) {
-	queryResults := m.content.GetQueryResults()
-
+func (m model) exportAsCSV(msg command.ExportMsg, queryResults []map[string]any) (tea.Model, tea.Cmd) {
 	data, err := export.PrepareCSV(queryResults, msg.Rows, msg.All)
 	if err != nil {
 		m.focusEditor()
@@ -67,7 +101,25 @@ func (m model) exportAsCSV(msg command.ExportMsg) (tea.Model, tea.Cmd) {
 	m.focusEditor()
 	m.command.Reset()
 
+	if cmd := m.describeExport(storage, fileName, data); cmd != nil {
+		return m, cmd
+	}
+
 	return m, m.successNotification(
 		fmt.Sprintf("Data exported successfully as CSV to %s", fileName),
 	)
 }
+
+// describeExport saves the query, server, columns and row count of the
+// export next to it, returning the warning to show when it fails. The
+// export is kept either way.
+func (m model) describeExport(storage, fileName string, data any) tea.Cmd {
+	query, _ := m.content.ExecutedQuery()
+
+	metadata := export.NewMetadata(query, m.server.Name, data)
+	if err := export.WriteMetadata(storage, fileName, metadata); err != nil {
+		return m.warningNotification(fmt.Sprintf("Data exported to %s, but its description wasn't saved: %v", fileName, err))
+	}
+
+	return nil
+}
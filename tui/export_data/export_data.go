@@ -76,11 +76,12 @@ type Model struct {
 
 type item struct {
 	title, desc string
+	metadata    string // the query, server and columns of the export, also filtered on
 }
 
 func (i item) Title() string       { return i.title }
 func (i item) Description() string { return i.desc }
-func (i item) FilterValue() string { return i.title }
+func (i item) FilterValue() string { return strings.TrimSpace(i.title + " " + i.metadata) }
 
 func New(store export.Store, server server.Server, width, height int, s styles.Styles, isDark bool) Model {
 	adapter := &storeAdapter{Store: store}
@@ -90,6 +91,7 @@ func New(store export.Store, server server.Server, width, height int, s styles.S
 		PlaceholderTitle:    "No data exported.",
 		PlaceholderSubtitle: "Press '<leader>c' to go back.",
 		SuccessDeleteMsg:    "Record deleted successfully.",
+		DeleteTitle:         "Delete exported record",
 		SuccessRenameMsg:    "Record renamed successfully.",
 	}
 
@@ -167,9 +169,28 @@ func processRecords(records []recordItem) []list.Item {
 	items := make([]list.Item, 0, len(records))
 
 	for _, record := range records {
+		modified := record.UpdatedAt.Format("02/01/2006 15:04")
+
+		metadata := record.Metadata
+		if metadata == nil {
+			items = append(items, item{
+				title: record.Name,
+				desc:  fmt.Sprintf("Last modified: %s", modified),
+			})
+			continue
+		}
+
+		rows := "rows"
+		if metadata.RowCount == 1 {
+			rows = "row"
+		}
+
+		query := strings.Join(strings.Fields(metadata.Query), " ")
+
 		items = append(items, item{
-			title: record.Name,
-			desc:  fmt.Sprintf("Last modified: %s", record.UpdatedAt.Format("02/01/2006 15:04")),
+			title:    record.Name,
+			desc:     fmt.Sprintf("%s · %d %s · %s", modified, metadata.RowCount, rows, query),
+			metadata: strings.Join(append([]string{query, metadata.Server}, metadata.Columns...), " "),
 		})
 	}
 
@@ -241,6 +262,16 @@ func getLanguageForEditor(path string) string {
 	return lang
 }
 
+// SelectedPath returns the file of the selected record, or an empty string when there is none
+func (m Model) SelectedPath() string {
+	current := m.GetStore().GetCurrent()
+	if current.Name == "" {
+		return ""
+	}
+
+	return m.GetStore().GetPath(current)
+}
+
 func (m Model) CanTriggerLeaderKey() bool {
 	return m.Model.CanTriggerLeaderKey()
 }
@@ -1,9 +1,11 @@
 package export_data
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ionut-t/coffee/styles"
+	pkgExport "github.com/ionut-t/perp/pkg/export"
 	"github.com/ionut-t/perp/pkg/server"
 	"github.com/ionut-t/perp/store/export"
 )
@@ -165,6 +167,34 @@ func TestProcessRecords(t *testing.T) {
 	}
 }
 
+func TestProcessRecords_Metadata(t *testing.T) {
+	t.Parallel()
+
+	records := []recordItem{
+		{Record: &export.Record{
+			Name: "invoices.csv",
+			Metadata: &pkgExport.Metadata{
+				Query:    "SELECT number, total\nFROM invoices",
+				Server:   "production",
+				Columns:  []string{"number", "total"},
+				RowCount: 42,
+			},
+		}},
+	}
+
+	i := processRecords(records)[0].(item)
+
+	if !strings.HasSuffix(i.Description(), " · 42 rows · SELECT number, total FROM invoices") {
+		t.Errorf("expected the description to show the rows and query, got %q", i.Description())
+	}
+
+	for _, term := range []string{"invoices.csv", "production", "total"} {
+		if !strings.Contains(i.FilterValue(), term) {
+			t.Errorf("expected the filter value to contain %q, got %q", term, i.FilterValue())
+		}
+	}
+}
+
 func TestItem_Interface(t *testing.T) {
 	t.Parallel()
 
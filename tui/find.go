@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/history"
+	"github.com/ionut-t/perp/pkg/server"
+	pkgSnippets "github.com/ionut-t/perp/pkg/snippets"
+	exportStore "github.com/ionut-t/perp/store/export"
+	snippetsStore "github.com/ionut-t/perp/store/snippets"
+	exportData "github.com/ionut-t/perp/tui/export_data"
+	"github.com/ionut-t/perp/tui/finder"
+	"github.com/ionut-t/perp/tui/servers"
+)
+
+// openFinder shows the finder searching the snippets, the exports of the
+// connected server, the servers and the query history. Artifacts failing to
+// load are reported, the others are still searched.
+func (m model) openFinder() (tea.Model, tea.Cmd) {
+	entries, err := m.finderEntries()
+
+	m.finder = finder.New(entries, m.height, m.styles)
+	m.isFinderActive = true
+
+	cmd := m.finder.Init()
+	if err != nil {
+		cmd = tea.Batch(cmd, m.errorNotification(err))
+	}
+
+	return m, cmd
+}
+
+func (m model) finderEntries() ([]finder.Entry, error) {
+	var entries []finder.Entry
+	var errs []error
+
+	storage := m.config.Storage()
+
+	serverSnippetsPath := ""
+	if m.server.Name != "" {
+		serverSnippetsPath = pkgSnippets.GetServerSnippetsPath(storage, m.server.Name)
+	}
+
+	snippets, err := snippetsStore.New(pkgSnippets.GetGlobalSnippetsPath(storage), serverSnippetsPath, m.config.Editor()).Load()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, snippet := range snippets {
+		entries = append(entries, finder.Entry{
+			Kind:   finder.Snippet,
+			Title:  snippet.Name,
+			Detail: snippet.Description,
+			Value:  snippet.Query,
+		})
+	}
+
+	if m.server.Name != "" {
+		records, err := exportStore.New(filepath.Join(storage, m.server.Name, exportDataDirectory), m.config.Editor()).Load()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		for _, record := range records {
+			detail := record.UpdatedAt.Format("02/01/2006 15:04")
+			if record.Metadata != nil {
+				detail = strings.Join(strings.Fields(record.Metadata.Query), " ")
+			}
+
+			entries = append(entries, finder.Entry{
+				Kind:   finder.Export,
+				Title:  record.Name,
+				Detail: detail,
+				Value:  record.Name,
+			})
+		}
+	}
+
+	saved, err := server.Load(storage)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, srv := range saved {
+		entries = append(entries, finder.Entry{
+			Kind:   finder.Server,
+			Title:  srv.Name,
+			Detail: srv.DisplayString(),
+			Value:  srv.Name,
+		})
+	}
+
+	queries, err := history.Get(storage)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, entry := range queries {
+		entries = append(entries, finder.Entry{
+			Kind:   finder.History,
+			Title:  entry.Query,
+			Detail: entry.Time.Format(time.DateTime),
+			Value:  entry.Query,
+		})
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+// handleFinderSelection inserts the query of a snippet or history entry in
+// the editor, opens an export, or connects to a server. The line of a file
+// found by a text search is shown in the file instead.
+func (m model) handleFinderSelection(entry finder.Entry) (tea.Model, tea.Cmd) {
+	m.isFinderActive = false
+
+	switch entry.Kind {
+	case finder.Export:
+		m.view = viewExportData
+		storage := filepath.Join(m.config.Storage(), m.server.Name, exportDataDirectory)
+		m.exportData = exportData.New(exportStore.New(storage, m.config.Editor()), m.server, m.width, m.height, m.styles, m.isDark)
+		if m.exportData.Select(entry.Value) && entry.Line > 0 {
+			m.exportData.FocusLine(entry.Line - 1)
+		}
+		exportDataModel, cmd := m.exportData.Update(nil)
+		m.exportData = exportDataModel
+		return m, cmd
+
+	case finder.Snippet:
+		if entry.Line == 0 {
+			break
+		}
+
+		m.listSnippets()
+		if m.snippets.Select(entry.Value) {
+			m.snippets.FocusLine(entry.Line - 1)
+		}
+		snippetsModel, cmd := m.snippets.Update(nil)
+		m.snippets = snippetsModel
+		return m, cmd
+
+	case finder.Server:
+		srv, err := server.FindByName(m.config.Storage(), entry.Value)
+		if err != nil {
+			return m, m.errorNotification(err)
+		}
+
+		return m, func() tea.Msg {
+			return servers.SelectedServerMsg{Server: *srv}
+		}
+	}
+
+	m.view = viewMain
+	m.focusEditor()
+	return m, m.applyQueryToEditor(entry.Value)
+}
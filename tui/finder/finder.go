@@ -0,0 +1,276 @@
+// Package finder is an overlay searching the stored artifacts at once:
+// snippets, exports, servers and the query history. Typing narrows them with
+// fuzzy matching, and enter picks one.
+package finder
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/utils"
+)
+
+// Kind is the type of artifact an entry is
+type Kind int
+
+const (
+	Snippet Kind = iota
+	Export
+	Server
+	History
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Snippet:
+		return "snippet"
+	case Export:
+		return "export"
+	case Server:
+		return "server"
+	default:
+		return "history"
+	}
+}
+
+// badgeWidth fits the longest kind
+const badgeWidth = 7
+
+const (
+	width      = 80
+	maxResults = 12
+)
+
+// Entry is an artifact the finder searches
+type Entry struct {
+	Kind   Kind
+	Title  string // the name, or the query of a history entry
+	Detail string // e.g. the description of a snippet or when a query ran
+	Value  string // what picking it uses: a query, a file or a server name
+	Line   int    // the line of the file found, counted from 1, or 0
+}
+
+// SelectedMsg carries the entry picked
+type SelectedMsg struct {
+	Entry Entry
+}
+
+type CancelMsg struct{}
+
+// match is an entry matching the search, with the positions of the matched
+// characters in its title
+type match struct {
+	entry   Entry
+	indexes []int
+}
+
+type Model struct {
+	title   string
+	input   textinput.Model
+	entries []Entry
+	matches []match
+	cursor  int
+	height  int
+	styles  styles.Styles
+}
+
+// New returns the finder searching entries, listed in order until something
+// is typed
+func New(entries []Entry, height int, s styles.Styles) Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Search snippets, exports, servers and history"
+	input.CharLimit = 256
+	input.SetWidth(width - 8)
+	input.Focus()
+
+	inputStyles := input.Styles()
+	inputStyles.Focused.Prompt = inputStyles.Focused.Prompt.Foreground(s.Primary.GetForeground())
+	inputStyles.Focused.Text = inputStyles.Focused.Text.Foreground(s.Primary.GetForeground())
+	input.SetStyles(inputStyles)
+
+	m := Model{
+		title:   "Find",
+		input:   input,
+		entries: entries,
+		height:  height,
+		styles:  s,
+	}
+	m.search()
+
+	return m
+}
+
+// SetTitle replaces the title and the placeholder of the search, e.g. to
+// narrow the lines of a text search
+func (m *Model) SetTitle(title, placeholder string) {
+	m.title = title
+	m.input.Placeholder = placeholder
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			return m, utils.Dispatch(CancelMsg{})
+
+		case "enter":
+			if m.cursor < len(m.matches) {
+				return m, utils.Dispatch(SelectedMsg{Entry: m.matches[m.cursor].entry})
+			}
+			return m, nil
+
+		case "up", "ctrl+p", "ctrl+k":
+			if len(m.matches) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.matches)) % len(m.matches)
+			}
+			return m, nil
+
+		case "down", "ctrl+n", "ctrl+j", "tab":
+			if len(m.matches) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.matches)
+			}
+			return m, nil
+		}
+	}
+
+	term := m.input.Value()
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	if m.input.Value() != term {
+		m.search()
+	}
+
+	return m, cmd
+}
+
+// search keeps the entries matching the typed term, the best matches first.
+// The kind of an entry is searched too, e.g. "exp orders" finds the exports
+// named after orders.
+func (m *Model) search() {
+	m.cursor = 0
+	term := strings.TrimSpace(m.input.Value())
+
+	if term == "" {
+		m.matches = make([]match, len(m.entries))
+		for i, entry := range m.entries {
+			m.matches[i] = match{entry: entry}
+		}
+		return
+	}
+
+	targets := make([]string, len(m.entries))
+	for i, entry := range m.entries {
+		targets[i] = target(entry)
+	}
+
+	ranks := list.DefaultFilter(term, targets)
+
+	m.matches = make([]match, len(ranks))
+	for i, rank := range ranks {
+		entry := m.entries[rank.Index]
+
+		// the positions in the title, which follows the kind and a space
+		offset := len([]rune(entry.Kind.String())) + 1
+		var indexes []int
+		for _, index := range rank.MatchedIndexes {
+			if index >= offset && index-offset < len([]rune(title(entry))) {
+				indexes = append(indexes, index-offset)
+			}
+		}
+
+		m.matches[i] = match{entry: entry, indexes: indexes}
+	}
+}
+
+// target is the text of an entry searched
+func target(entry Entry) string {
+	return entry.Kind.String() + " " + title(entry) + " " + entry.Detail
+}
+
+// title returns the title of an entry on one line, e.g. a query written on
+// several lines
+func title(entry Entry) string {
+	return strings.Join(strings.Fields(entry.Title), " ")
+}
+
+func (m Model) View() string {
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.Primary.GetForeground()).
+		Padding(1, 2).
+		Width(width)
+
+	lines := []string{
+		m.styles.Primary.Bold(true).Render(truncate(m.title, width-8)),
+		m.input.View(),
+		"",
+	}
+
+	visible := max(1, min(maxResults, m.height-12))
+	start := max(0, m.cursor-visible+1)
+	end := min(len(m.matches), start+visible)
+
+	if len(m.matches) == 0 {
+		lines = append(lines, m.styles.Subtext0.Render("Nothing found."))
+	}
+
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderMatch(m.matches[i], i == m.cursor))
+	}
+
+	lines = append(lines,
+		"",
+		m.styles.Overlay1.Render(fmt.Sprintf("%d/%d  [enter] open  [↑/↓] select  [esc] close", len(m.matches), len(m.entries))),
+	)
+
+	return border.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderMatch shows the kind of the entry, its title with the matched
+// characters highlighted, and its detail
+func (m Model) renderMatch(match match, selected bool) string {
+	badge := m.styles.Accent.Bold(true).Width(badgeWidth).Render(match.entry.Kind.String())
+
+	available := width - 8 - badgeWidth - 3
+	text := truncate(title(match.entry), available)
+
+	style := m.styles.Text
+	if selected {
+		style = m.styles.Primary.Bold(true)
+	}
+
+	rendered := lipgloss.StyleRunes(text, match.indexes, style.Underline(true), style)
+
+	if detail := match.entry.Detail; detail != "" && lipgloss.Width(text)+2 < available {
+		rendered += "  " + m.styles.Subtext0.Render(truncate(detail, available-lipgloss.Width(text)-2))
+	}
+
+	cursor := "  "
+	if selected {
+		cursor = m.styles.Primary.Render("> ")
+	}
+
+	return cursor + badge + " " + rendered
+}
+
+// truncate cuts value to width characters, ending it with "…" when it is cut
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+
+	return string(runes[:max(0, width-1)]) + "…"
+}
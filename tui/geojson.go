@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/clipboard"
+	"github.com/ionut-t/perp/pkg/geo"
+)
+
+// yankGeoJSON copies a geometry or geography cell to the clipboard as GeoJSON
+func (m *model) yankGeoJSON(value any) tea.Cmd {
+	g, ok := geo.Parse(value)
+	if !ok {
+		return m.errorNotification(errors.New("the selected cell does not contain a geometry or geography value"))
+	}
+
+	data, err := g.GeoJSON()
+	if err != nil {
+		return m.errorNotification(err)
+	}
+
+	if err := clipboard.Write(string(data)); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification("GeoJSON copied to clipboard")
+}
@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/tui/prompt"
+)
+
+const (
+	gexecStatementColumn = "statement"
+	gexecResultColumn    = "result"
+)
+
+// generateStatements runs the query of a buffer ending with \gexec and
+// returns the statements held by its cells, row by row and left to right.
+// NULL and empty cells are skipped, as in psql.
+func (m model) generateStatements(query string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		result, err := m.db.Query(ctx, query)
+		if err != nil {
+			return queryFailureMsg{err: err}
+		}
+
+		rows, columns, err := db.ExtractResults(result.Rows())
+		if err != nil {
+			return queryFailureMsg{err: err}
+		}
+
+		var statements []string
+		for _, row := range rows {
+			for _, column := range columns {
+				value := row[column]
+				if value.Value == nil {
+					continue
+				}
+
+				statement := strings.TrimSpace(fmt.Sprint(db.FormatValue(value.Value, value.Type)))
+				if statement != "" {
+					statements = append(statements, statement)
+				}
+			}
+		}
+
+		return gexecStatementsMsg{query: query, statements: statements}
+	}
+}
+
+// handleGexecStatements lists the generated statements and asks to confirm
+// running them
+func (m model) handleGexecStatements(msg gexecStatementsMsg) (tea.Model, tea.Cmd) {
+	if len(msg.statements) == 0 {
+		m.loading = false
+		return m, m.successNotification("The query returned no statements to execute")
+	}
+
+	rows := make([]map[string]any, len(msg.statements))
+	for i, statement := range msg.statements {
+		rows[i] = map[string]any{gexecStatementColumn: statement}
+	}
+
+	m.content.SetPsqlResult(msg.query+" "+psql.PSQL_Gexec, &psql.Result{
+		Columns: []string{gexecStatementColumn},
+		Rows:    rows,
+	})
+	closeCmd := m.finishQueryExecution()
+
+	m.gexecStatements = msg.statements
+	m.isPromptActive = true
+	m.prompt.SetAction(prompt.GexecAction)
+
+	return m, closeCmd
+}
+
+// runGexec executes the confirmed statements one by one. A failed statement
+// stops the next ones when ON_ERROR_STOP is on; otherwise they run and the
+// outcome of each is shown in the results.
+func (m model) runGexec() (tea.Model, tea.Cmd) {
+	statements := m.gexecStatements
+	m.gexecStatements = nil
+
+	if len(statements) == 0 || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return m.execStatements(psql.PSQL_Gexec, statements, nil)
+	})
+}
+
+// execStatements runs statements one by one for command, \gexec or \i, on
+// one connection so a transaction they start spans the next ones. A failed
+// statement stops the next ones when ON_ERROR_STOP is on; otherwise they run
+// and the outcome of each is shown in the results.
+//
+// The statements of \i are given the variables they reference, changed by the
+// \set and \unset of the file; \gexec runs its statements as they are.
+func (m model) execStatements(command string, statements []string, variables psql.Variables) tea.Msg {
+	start := time.Now()
+
+	ctx, cancel := m.queryContext()
+	defer cancel()
+
+	batch, err := m.db.Batch(ctx)
+	if err != nil {
+		return queryFailureMsg{err: err}
+	}
+
+	msg := gexecResultMsg{
+		command: command,
+		result: &psql.Result{
+			Columns: []string{gexecStatementColumn, gexecResultColumn},
+			Rows:    make([]map[string]any, 0, len(statements)),
+		},
+		total:     len(statements),
+		variables: variables,
+	}
+
+	onErrorStop := m.variables.OnErrorStop()
+	for _, statement := range statements {
+		if variables != nil {
+			statement = psql.Interpolate(statement, variables)
+			onErrorStop = variables.OnErrorStop()
+		}
+
+		outcome, ddl, err := m.execStatement(ctx, batch, statement, variables)
+		if err != nil {
+			msg.failed++
+			outcome = "ERROR: " + err.Error()
+		}
+		msg.ddl = msg.ddl || ddl
+
+		msg.result.Rows = append(msg.result.Rows, map[string]any{
+			gexecStatementColumn: statement,
+			gexecResultColumn:    outcome,
+		})
+
+		if err != nil && (onErrorStop || ctx.Err() != nil) {
+			msg.err = err
+			break
+		}
+	}
+
+	msg.rolledBack = batch.Close()
+	msg.result.ExecutionTime = time.Since(start)
+
+	return msg
+}
+
+// execStatement runs a statement generated by \gexec, or read by \i, on
+// querier and returns its command tag. The meta-commands of \i that print a
+// message or change the variables are run too, returning their outcome.
+func (m model) execStatement(ctx context.Context, querier db.Querier, statement string, variables psql.Variables) (string, bool, error) {
+	if strings.HasPrefix(statement, "\\") {
+		if variables == nil {
+			return "", false, errors.New("meta-commands are not supported here")
+		}
+
+		outcome, err := execScriptCommand(statement, variables)
+		return outcome, false, err
+	}
+
+	result, err := querier.Query(ctx, statement)
+	if err != nil {
+		return "", false, err
+	}
+
+	// the command tag is available once every row is read
+	rows := result.Rows()
+	for rows.Next() {
+		continue
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return "", false, err
+	}
+
+	return rows.CommandTag().String(), result.IsDDL(), nil
+}
+
+// execScriptCommand runs a meta-command of a file run by \i: \echo and
+// \qecho return their message, \set and \unset change variables. The
+// others are not supported in files.
+func execScriptCommand(statement string, variables psql.Variables) (string, error) {
+	cmd, err := psql.Parse(statement)
+	if err != nil {
+		return "", err
+	}
+
+	switch cmd.Type {
+	case psql.CmdEcho, psql.CmdQecho:
+		return psql.EchoText(cmd), nil
+
+	case psql.CmdSet:
+		name, value, err := psql.ParseSet(cmd)
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			return "", errors.New("\\set: missing variable name")
+		}
+		variables[name] = value
+		return fmt.Sprintf(":%s = %s", name, value), nil
+
+	case psql.CmdUnset:
+		name, err := psql.ParseUnset(cmd)
+		if err != nil {
+			return "", err
+		}
+		delete(variables, name)
+		return fmt.Sprintf("Unset :%s", name), nil
+	}
+
+	command, _, _ := strings.Cut(strings.TrimSpace(statement), " ")
+	return "", fmt.Errorf("%s is not supported in files run by %s", command, psql.PSQL_ExecuteFile)
+}
+
+func (m model) handleGexecResult(msg gexecResultMsg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.handlePsqlResult(psqlResultMsg{command: msg.command, result: msg.result})
+	m = updated.(model)
+
+	if msg.variables != nil {
+		m.variables = msg.variables
+	}
+
+	run := len(msg.result.Rows)
+
+	var notification tea.Cmd
+	switch {
+	case m.running.cancelled(msg.err):
+		notification = m.warningNotification(fmt.Sprintf("Cancelled after %d of %d statements", run, msg.total))
+	case msg.err != nil:
+		notification = tea.Batch(
+			m.errorNotification(fmt.Errorf("statement %d of %d failed, %s stopped the next ones: %w", run, msg.total, psql.OnErrorStopVariable, msg.err)),
+			m.reconnectIfLost(msg.err),
+		)
+	case msg.failed > 0:
+		notification = m.errorNotification(fmt.Errorf("%d of %d statements failed", msg.failed, msg.total))
+	case msg.rolledBack:
+		notification = m.warningNotification(fmt.Sprintf("%d statements executed. The transaction they left open was rolled back", msg.total))
+	default:
+		notification = m.successNotification(fmt.Sprintf("%d statements executed", msg.total))
+	}
+
+	var schemaCmd tea.Cmd
+	if msg.ddl {
+		schemaCmd = m.generateSchema()
+	}
+
+	return m, tea.Batch(cmd, notification, schemaCmd)
+}
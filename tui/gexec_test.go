@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/psql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleGexecStatements(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("SELECT format('VACUUM %I', tablename) FROM pg_tables \\gexec")
+
+	updated, _ := m.handleGexecStatements(gexecStatementsMsg{
+		query:      "SELECT format('VACUUM %I', tablename) FROM pg_tables",
+		statements: []string{"VACUUM users", "VACUUM orders"},
+	})
+	confirming := updated.(model)
+
+	assert.True(t, confirming.isPromptActive)
+	assert.False(t, confirming.loading)
+	assert.Equal(t, []string{"VACUUM users", "VACUUM orders"}, confirming.gexecStatements)
+	assert.Len(t, confirming.content.GetQueryResults(), 2)
+
+	updated, _ = m.handleGexecStatements(gexecStatementsMsg{query: "SELECT NULL"})
+	empty := updated.(model)
+
+	assert.False(t, empty.isPromptActive)
+	assert.False(t, empty.loading)
+	assert.Empty(t, empty.gexecStatements)
+}
+
+func TestExecScriptCommand(t *testing.T) {
+	t.Parallel()
+
+	variables := psql.Variables{"id": "42"}
+
+	tests := []struct {
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{statement: "\\echo 'seeding users'", want: "seeding users"},
+		{statement: "\\qecho done", want: "done"},
+		{statement: "\\set name 'Ana Lee'", want: ":name = Ana Lee"},
+		{statement: "\\unset id", want: "Unset :id"},
+		{statement: "\\set", wantErr: true},
+		{statement: "\\dt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		outcome, err := execScriptCommand(tt.statement, variables)
+		if tt.wantErr {
+			assert.Error(t, err, tt.statement)
+			continue
+		}
+
+		assert.NoError(t, err, tt.statement)
+		assert.Equal(t, tt.want, outcome, tt.statement)
+	}
+
+	assert.Equal(t, psql.Variables{"name": "Ana Lee"}, variables)
+}
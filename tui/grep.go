@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	tea "charm.land/bubbletea/v2"
+	pkgSnippets "github.com/ionut-t/perp/pkg/snippets"
+	"github.com/ionut-t/perp/pkg/textsearch"
+	exportStore "github.com/ionut-t/perp/store/export"
+	snippetsStore "github.com/ionut-t/perp/store/snippets"
+	"github.com/ionut-t/perp/tui/finder"
+)
+
+// maxGrepMatchesPerFile bounds the lines listed for a file, so a text found
+// on every row of a large export doesn't bury the other files
+const maxGrepMatchesPerFile = 20
+
+// grep lists the lines of the snippets and of the exports of the connected
+// server containing text in the finder. Picking one opens its file with the
+// cursor on the line.
+func (m model) grep(text string) (tea.Model, tea.Cmd) {
+	if m.focused == focusedCommand {
+		m.focusEditor()
+	}
+
+	entries, err := m.grepEntries(text)
+	if len(entries) == 0 {
+		if err != nil {
+			return m, m.errorNotification(err)
+		}
+		return m, m.warningNotification(fmt.Sprintf("No export or snippet contains %q", text))
+	}
+
+	m.finder = finder.New(entries, m.height, m.styles)
+	m.finder.SetTitle(fmt.Sprintf("Lines containing %q", text), "Narrow the lines")
+	m.isFinderActive = true
+
+	cmd := m.finder.Init()
+	if err != nil {
+		cmd = tea.Batch(cmd, m.errorNotification(err))
+	}
+
+	return m, cmd
+}
+
+func (m model) grepEntries(text string) ([]finder.Entry, error) {
+	var entries []finder.Entry
+	var errs []error
+
+	storage := m.config.Storage()
+
+	if m.server.Name != "" {
+		records, err := exportStore.New(filepath.Join(storage, m.server.Name, exportDataDirectory), m.config.Editor()).Load()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		for _, record := range records {
+			entries = append(entries, grepFile(finder.Export, record.Name, record.Content, text)...)
+		}
+	}
+
+	serverSnippetsPath := ""
+	if m.server.Name != "" {
+		serverSnippetsPath = pkgSnippets.GetServerSnippetsPath(storage, m.server.Name)
+	}
+
+	snippets, err := snippetsStore.New(pkgSnippets.GetGlobalSnippetsPath(storage), serverSnippetsPath, m.config.Editor()).Load()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, snippet := range snippets {
+		entries = append(entries, grepFile(finder.Snippet, snippet.Name, snippet.Content, text)...)
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+// grepFile returns an entry for each line of the file containing text
+func grepFile(kind finder.Kind, name, content, text string) []finder.Entry {
+	matches := textsearch.Lines(content, text, maxGrepMatchesPerFile)
+
+	entries := make([]finder.Entry, 0, len(matches))
+	for _, match := range matches {
+		entries = append(entries, finder.Entry{
+			Kind:   kind,
+			Title:  fmt.Sprintf("%s:%d", name, match.Line),
+			Detail: match.Text,
+			Value:  name,
+			Line:   match.Line,
+		})
+	}
+
+	return entries
+}
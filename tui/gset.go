@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// executeChain runs the statements ending with \gset in order, storing the
+// columns of the row each one returns in variables that the next statements
+// reference, and shows the results of the last statement. The variables set
+// with \set are referenced too; the ones stored by \gset last for the chain.
+func (m model) executeChain(steps []psql.Step) tea.Cmd {
+	variables := maps.Clone(m.variables)
+	if variables == nil {
+		variables = make(psql.Variables)
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		for _, step := range steps[:len(steps)-1] {
+			query := psql.Interpolate(step.Query, variables)
+			if err := m.gset(ctx, query, step.Prefix, variables); err != nil {
+				return queryFailureMsg{err: err}
+			}
+		}
+
+		return m.queryResultMsg(ctx, psql.Interpolate(steps[len(steps)-1].Query, variables))
+	}
+}
+
+// gset runs a query that must return one row and stores its columns in
+// variables named after them. NULL values unset the variables, as in psql.
+func (m model) gset(ctx context.Context, query, prefix string, variables map[string]string) error {
+	result, err := m.db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	rows, columns, err := db.ExtractResults(result.Rows())
+	if err != nil {
+		return err
+	}
+
+	switch len(rows) {
+	case 0:
+		return fmt.Errorf("no rows returned for \\gset: %s", query)
+	case 1:
+	default:
+		return fmt.Errorf("more than one row returned for \\gset: %s", query)
+	}
+
+	for _, column := range columns {
+		name := prefix + column
+		value := rows[0][column]
+
+		if value.Value == nil {
+			delete(variables, name)
+			continue
+		}
+
+		variables[name] = fmt.Sprint(db.FormatValue(value.Value, value.Type))
+	}
+
+	return nil
+}
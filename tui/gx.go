@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"context"
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// errNoLastQuery is returned by \gx and \gdesc alone before any query ran
+var errNoLastQuery = errors.New("run a query first, or end one with the command")
+
+// executeExpanded runs the query of a buffer ending with \gx and shows its
+// results expanded, whatever the display options
+func (m model) executeExpanded(query string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		msg := m.queryResultMsg(ctx, query)
+		if result, ok := msg.(executeQueryMsg); ok {
+			result.Expanded = true
+			return result
+		}
+
+		return msg
+	}
+}
+
+// describeQuery lists the columns of the results of the query of a buffer
+// ending with \gdesc. The query is prepared, not run.
+func (m model) describeQuery(query string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		columns, err := m.db.Describe(ctx, query)
+		if err != nil {
+			return psqlErrorMsg{err: err}
+		}
+
+		return psqlResultMsg{
+			command:     query + " " + psql.PSQL_Gdesc,
+			commandType: psql.CmdGdesc,
+			result:      psql.DescribeResult(columns),
+		}
+	}
+}
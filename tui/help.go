@@ -25,6 +25,8 @@ func (m model) renderHelp() string {
 func (m model) renderUsefulHelp() string {
 	bindings := []key.Binding{
 		keymap.ForceQuit,
+		cancelQuery,
+		keymap.Suspend,
 		changeFocused,
 		enterCommand,
 		viewHistoryEntries,
@@ -59,6 +61,10 @@ func (m model) renderLLMHelp() string {
 				 -- FIX
 				 SELECT name, COUNT(*) FROM users JOIN orders ON users.id = orders.user_id;
 				 `},
+		{"/filter", `filters the results of the last query
+				 Example:
+				 /filter only rows where status failed last week
+				 `},
 		{"/add", `adds tables to the LLM instructions
 				 Example:
 				 /add users, orders
@@ -73,8 +79,14 @@ func (m model) renderLLMHelp() string {
 
 	title := m.styles.Text.Bold(true).Render("LLM Commands")
 
-	description := m.styles.Subtext1.Render(
-		styles.Wrap(m.width-1, "These commands are available when the editor is in INSERT mode."),
+	description := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.styles.Subtext1.Render(
+			styles.Wrap(m.width-1, "These commands are available when the editor is in INSERT mode."),
+		),
+		m.styles.Subtext1.Render(
+			styles.Wrap(m.width-1, "In a response, y yanks a code block and o opens a link; when there are several, type the number shown next to it."),
+		),
 	)
 
 	return lipgloss.JoinVertical(
@@ -163,6 +175,19 @@ func (m model) renderTableHelp() string {
 		tableKeyMap.End,
 		yankCell,
 		yankRow,
+		openCell,
+		openPager,
+		previewCell,
+		yankGeoJSON,
+		toggleRawNumbers,
+		narrowColumn,
+		widenColumn,
+		previousResults,
+		nextResults,
+		yankQuery,
+		showResultInfo,
+		visualSelect,
+		pinResults,
 	}
 
 	title := m.styles.Text.Bold(true).Render("Table")
@@ -201,6 +226,11 @@ func (m model) renderCommandHelp() string {
 						 it exports rows 1,2,3 to data.json;
 						 if the file already exists, it will create a new file with unique name derived from the	 input name
 						 `},
+		{"export selection <file>", `export the cells selected in visual mode (v) to a file
+						 Example:
+						 export selection data.csv
+						 it exports the selected rows and columns to data.csv
+						 `},
 		{"set-editor <editor>", `sets the external editor to use for editing configuration or exported data
 						 Example:
 						 set-editor vim
@@ -217,6 +247,66 @@ func (m model) renderCommandHelp() string {
 						Example:
 						llm-model gemini-1.5-flash
 						`},
+		{"pipe [--csv] <command>", `streams the query results as JSON (or CSV) through a shell command and shows its output
+						Example:
+						pipe jq '.[] | .id'
+						`},
+		{"tz [zone]", `shows timestamps in another time zone for the session; without a zone restores the configured one
+						Example:
+						tz Europe/London
+						`},
+		{"reset-session", `discards the session state (DISCARD ALL) and reconnects, dropping prepared statements,
+						 temporary tables and settings changed with SET; the on connect snippet runs again
+						 Example:
+						 reset-session
+						 `},
+		{"materialize <table>", `copies the current results into a temporary table, so later queries can join against them;
+						 it lasts until the connection is closed
+						 Example:
+						 materialize tmp_results
+						 `},
+		{"grep <text>", `lists the lines of the snippets and of the exports of the connected server containing the text,
+						 ignoring case; pick one to open its file on that line
+						 Example:
+						 grep invoice 1234
+						 `},
+		{"connect <server>", `connects to another saved server, closing the current connection
+						 Example:
+						 connect production
+						 `},
+		{"compare <server>", `runs the query in the editor on the connected server and on another saved server,
+						 showing the results of the other server pinned on the left; the connection is not switched
+						 and the other server is queried read only
+						 Example:
+						 compare staging
+						 `},
+		{"sync-preview [--sql] <table> <server>", `compares the rows of a table on another saved server with the connected one by primary key,
+						 listing the missing, extra and changed rows; with --sql, puts the INSERT and UPDATE statements
+						 bringing the connected server in line in the editor for review
+						 Example:
+						 sync-preview --sql public.plans staging
+						 `},
+		{"storage [trash <days>|archive <days>|empty-trash]", `shows the disk usage of the history, exports, snippets and archives per server;
+						 trash moves the exports older than the days given to the trash, archive compresses them
+						 into the archives directory of their server, and empty-trash deletes the trash once confirmed
+						 Example:
+						 storage trash 30
+						 `},
+		{"keywords upper|lower", `changes the case of the SQL keywords in the editor, leaving strings, quoted identifiers
+						 and comments as written; keywords can also be upper-cased as you type from the Configuration menu
+						 Example:
+						 keywords upper
+						 `},
+		{"join <table>", `adds a JOIN of the table to the last query in the editor, after its FROM clause and joins,
+						 matching the columns of a foreign key between the table and one already in the query
+						 Example:
+						 join orders
+						 `},
+		{"llm-set <setting> <value>", `sets an LLM generation setting for the current provider
+						Settings: temperature (0-2), max_tokens, timeout (e.g. 45s); use "default" to reset
+						Example:
+						llm-set temperature 0.2
+						`},
 	}
 
 	title := m.styles.Text.Bold(true).Render("Command Palette")
@@ -230,6 +320,9 @@ func (m model) renderCommandHelp() string {
 			"You can access the command palette by pressing ",
 		)+m.styles.Accent.Render(":")+
 			m.styles.Subtext1.Render(".")),
+		m.styles.Subtext1.Render(
+			styles.Wrap(m.width-1, "Up and down browse the commands entered before, and tab completes command names, aliases and file paths. Aliases are set with command_aliases in the config file."),
+		),
 	)
 
 	return lipgloss.JoinVertical(
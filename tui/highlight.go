@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/ionut-t/perp/internal/config"
+	"github.com/ionut-t/perp/pkg/highlight"
+)
+
+// highlightRulesFromConfig returns the valid highlight rules set in the config
+// file. Invalid rules are dropped and reported with the error.
+func highlightRulesFromConfig(cfg config.Config) ([]highlight.Rule, error) {
+	rules, err := highlight.ParseRules(cfg.HighlightRules())
+	if err != nil {
+		return rules, fmt.Errorf("%s: %w", config.HighlightRulesKey, err)
+	}
+
+	return rules, nil
+}
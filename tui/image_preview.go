@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/geo"
+	"github.com/ionut-t/perp/pkg/imgpreview"
+)
+
+// previewCell shows a geometry cell as WKT, or the metadata of a bytea cell
+// and, when the terminal supports a graphics protocol, an inline thumbnail of
+// the image it holds.
+func (m model) previewCell(value any) (tea.Model, tea.Cmd) {
+	if g, ok := geo.Parse(value); ok {
+		m.content.SetGeometryPreview(g)
+		return m, nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return m, m.errorNotification(errors.New("preview is only available for bytea and geometry cells"))
+	}
+
+	info, isImage := imgpreview.Inspect(data)
+	if !isImage {
+		m.content.SetImagePreview("Binary data", fmt.Sprintf("Not a PNG, JPEG or GIF image, %s", imgpreview.FormatSize(len(data))))
+		return m, nil
+	}
+
+	protocol := imgpreview.DetectProtocol(os.Getenv)
+	if protocol == imgpreview.None {
+		m.content.SetImagePreview(info.String(), "The terminal does not support inline images (kitty, iTerm2 or sixel).")
+		return m, nil
+	}
+
+	m.content.SetImagePreview(info.String(), "")
+
+	top, left, cols, rows := m.content.ImagePreviewArea()
+
+	// the content pane sits below the top border and after the outer padding and left border
+	row, col := top+m.styles.ActiveBorder.GetBorderTopSize(), left+1+m.styles.ActiveBorder.GetBorderLeftSize()
+
+	return m, func() tea.Msg {
+		seq, err := imgpreview.Render(protocol, data, cols, rows)
+		if err != nil {
+			return notificationErrorMsg{err: err}
+		}
+
+		return imagePreviewMsg{sequence: imgpreview.Place(seq, row, col)}
+	}
+}
+
+// drawImagePreview writes the image to the terminal if the preview is still open
+func (m model) drawImagePreview(msg imagePreviewMsg) (tea.Model, tea.Cmd) {
+	if !m.content.IsImagePreview() {
+		return m, nil
+	}
+
+	return m, tea.Raw(msg.sequence)
+}
+
+// closeImagePreview returns to the results and removes the image from the screen
+func (m *model) closeImagePreview() tea.Cmd {
+	m.content.CloseImagePreview()
+	return tea.Batch(tea.Raw(imgpreview.ClearKitty), tea.ClearScreen)
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// includeFile reads the statements of the file run by \i, resolving a
+// relative path from the configured working directory
+func (m model) includeFile(cmd *psql.Command) tea.Msg {
+	path, err := psql.IncludePath(cmd, m.config.WorkingDirectory())
+	if err != nil {
+		return psqlErrorMsg{err: err}
+	}
+
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return psqlErrorMsg{err: fmt.Errorf("\\i: %w", err)}
+	}
+
+	return includeStatementsMsg{path: path, statements: psql.SplitStatements(string(script))}
+}
+
+// runInclude runs the statements read by \i one by one, with the references
+// to variables replaced, and shows the outcome of each. The file works on a
+// copy of the variables, kept once its statements ran.
+func (m model) runInclude(msg includeStatementsMsg) (tea.Model, tea.Cmd) {
+	if len(msg.statements) == 0 {
+		return m, tea.Batch(m.resetEditor(), m.successNotification(fmt.Sprintf("%s has no statements to execute", msg.path)))
+	}
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	variables := maps.Clone(m.variables)
+	if variables == nil {
+		variables = make(psql.Variables)
+	}
+
+	m.loading = true
+	command := psql.PSQL_ExecuteFile + " " + msg.path
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return m.execStatements(command, msg.statements, variables)
+	})
+}
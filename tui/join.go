@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/columnpicker"
+	"github.com/ionut-t/perp/pkg/joins"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// addJoin looks up the foreign keys between the table of :join and the tables
+// of the last query in the editor
+func (m model) addJoin(msg command.JoinMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.db == nil {
+		return m, nil
+	}
+
+	tables := columnpicker.Tables(joins.LastStatement(m.editor.GetCurrentContent()))
+
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		found, err := joins.Find(ctx, m.db, msg.Table, tables)
+		return joinFoundMsg{joins: found, err: err}
+	}
+}
+
+// handleJoinFound adds the JOIN clause of the first foreign key found, the
+// one to the table referenced last
+func (m model) handleJoinFound(msg joinFoundMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.errorNotification(msg.err)
+	}
+
+	join := msg.joins[0]
+
+	query, err := joins.Insert(m.editor.GetCurrentContent(), join.Clause())
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	notification := fmt.Sprintf("Joined %s on %s", join.Table, join.Constraint)
+	if others := len(msg.joins) - 1; others > 0 {
+		notification += fmt.Sprintf(" (%d other foreign keys match)", others)
+	}
+
+	return m, tea.Batch(m.applyQueryToEditor(query), m.successNotification(notification))
+}
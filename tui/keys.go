@@ -23,6 +23,66 @@ var (
 		key.WithHelp("Y", "yank selected row (copies selected row as JSON to clipboard)"),
 	)
 
+	openCell = key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open the URL or file path in the selected cell"),
+	)
+
+	openPager = key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "show the selected cell or the output in the pager ($PAGER, less by default)"),
+	)
+
+	previewCell = key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "preview the selected bytea image or geometry cell"),
+	)
+
+	yankQuery = key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "yank the query shown above the results"),
+	)
+
+	visualSelect = key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "select a range of cells with the movement keys; y yanks it as TSV, e exports it, esc cancels"),
+	)
+
+	showResultInfo = key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "toggle the server, timing, row estimate and column types of the results"),
+	)
+
+	narrowColumn = key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "narrow the selected column; truncated cells end with ... and their full value is shown below the table"),
+	)
+
+	widenColumn = key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "widen the selected column; widths are remembered for the query"),
+	)
+
+	previousResults = key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "show the results of the previous statement of the buffer"),
+	)
+
+	nextResults = key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "show the results of the next statement of the buffer"),
+	)
+
+	toggleRawNumbers = key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "toggle between formatted and raw numbers"),
+	)
+
+	yankGeoJSON = key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "yank selected geometry cell as GeoJSON"),
+	)
+
 	previousCell = key.NewBinding(
 		key.WithKeys("left", "h"),
 		key.WithHelp("← / h", "previous cell"),
@@ -33,9 +93,14 @@ var (
 		key.WithHelp("→ / l", "next cell"),
 	)
 
+	pinResults = key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pin the results to compare them with the next query (p again to unpin)"),
+	)
+
 	changeFocused = key.NewBinding(
 		key.WithKeys("tab"),
-		key.WithHelp("tab", "change focus between editor and main content"),
+		key.WithHelp("tab", "change focus between editor, results and pinned results"),
 	)
 
 	executeQuery = key.NewBinding(
@@ -62,6 +127,11 @@ var (
 		key.WithKeys("ctrl+r"),
 		key.WithHelp("ctrl+r", "view history logs"),
 	)
+
+	cancelQuery = key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "cancel the running query"),
+	)
 )
 
 // tryHandleKeyPress processes keyboard input in the main view
@@ -87,7 +157,7 @@ func (m model) tryHandleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 		updatedModel, cmd = m.handleInsertKey()
 		return updatedModel, cmd, false
 
-	case key.Matches(msg, keymap.Submit):
+	case key.Matches(msg, keymap.Submit) && !m.hasPendingLLMSuggestion():
 		updatedModel, cmd = m.handleSubmitKey()
 		return updatedModel, cmd, true
 
@@ -157,6 +227,13 @@ func (m model) handleChangeFocusKey() (tea.Model, tea.Cmd) {
 			m.focused = focusedContent
 			m.editor.Blur()
 		case focusedContent:
+			if m.pinned != nil {
+				m.focused = focusedPinned
+				break
+			}
+			m.focused = focusedEditor
+			m.editor.Focus()
+		case focusedPinned:
 			m.focused = focusedEditor
 			m.editor.Focus()
 		}
@@ -224,6 +301,12 @@ func (m model) handleSubmitKey() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// hasPendingLLMSuggestion reports whether enter should apply the LLM suggestion
+// shown in the content pane instead of submitting the editor content
+func (m model) hasPendingLLMSuggestion() bool {
+	return m.focused == focusedContent && m.content.HasPendingSuggestion()
+}
+
 // submitQuery executes the current editor content regardless of editor mode
 func (m model) submitQuery() (tea.Model, tea.Cmd) {
 	if m.editor.GetCurrentContent() == "" || m.loading {
@@ -280,6 +363,11 @@ func (m model) handleNextHistoryKey() (tea.Model, tea.Cmd) {
 
 // handleCancelKey cancels current operation
 func (m model) handleCancelKey() (tea.Model, tea.Cmd) {
+	if m.view == viewMain && m.focused == focusedContent && m.content.IsVisualMode() {
+		m.content.ExitVisualMode()
+		return m, nil
+	}
+
 	if m.view == viewMain && m.focused == focusedEditor {
 		m.resetHistory()
 
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/keywordcase"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// toggleUppercaseKeywords turns upper-casing the SQL keywords as they are
+// typed on or off and saves the choice in the config
+func (m model) toggleUppercaseKeywords() (tea.Model, tea.Cmd) {
+	enabled := !m.config.UppercaseKeywords()
+
+	if err := m.config.SetUppercaseKeywords(enabled); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	if enabled {
+		return m, m.successNotification("SQL keywords are upper-cased as you type")
+	}
+
+	return m, m.successNotification("SQL keywords are left as typed")
+}
+
+// changeKeywordCase upper or lower cases the SQL keywords in the editor
+func (m model) changeKeywordCase(msg command.KeywordCaseMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	content := m.editor.GetCurrentContent()
+	if !isSQLContent(content) {
+		return m, nil
+	}
+
+	if converted := keywordcase.Apply(content, msg.Upper); converted != content {
+		m.editor.SetContent(converted)
+	}
+
+	return m, nil
+}
+
+// capitaliseTypedKeyword upper-cases the keyword just completed in the
+// editor, when enabled. The keyword keeps its length, so the cursor is put
+// back right after the typed character.
+func (m *model) capitaliseTypedKeyword(before string) {
+	if !m.config.UppercaseKeywords() || !m.editor.IsInsertMode() {
+		return
+	}
+
+	after := m.editor.GetCurrentContent()
+	if after == before || !isSQLContent(after) {
+		return
+	}
+
+	content, offset, ok := keywordcase.AfterInsert(before, after)
+	if !ok {
+		return
+	}
+
+	m.editor.SetContent(content)
+
+	row := strings.Count(content[:offset], "\n")
+	lineStart := strings.LastIndex(content[:offset], "\n") + 1
+	_ = m.editor.SetCursorPosition(row, utf8.RuneCountInString(content[lineStart:offset]))
+}
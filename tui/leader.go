@@ -4,6 +4,8 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/ionut-t/perp/internal/leader"
 	"github.com/ionut-t/perp/internal/whichkey"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/workspace"
 )
 
 // Leader key and which-key handlers
@@ -16,25 +18,44 @@ func (m *model) updateMenuContext() {
 		ServerName:  m.server.Name,
 
 		// View state
-		InServersView:   m.view == viewServers,
-		InExportView:    m.view == viewExportData,
-		InMainView:      m.view == viewMain,
-		InHistoryView:   m.view == viewHistory,
-		InSnippetsView:  m.view == viewSnippets,
-		FocusedOnTable:  m.focused == focusedContent,
-		FocusedOnEditor: m.focused == focusedEditor,
-		IsFullScreen:    m.fullScreen,
-		IsHelpVisible:   m.view == viewHelp,
+		InServersView:     m.view == viewServers,
+		InExportView:      m.view == viewExportData,
+		InMainView:        m.view == viewMain,
+		InHistoryView:     m.view == viewHistory,
+		InSnippetsView:    m.view == viewSnippets,
+		InLLMExamplesView: m.view == viewLLMExamples,
+		FocusedOnTable:    m.focused == focusedContent,
+		FocusedOnEditor:   m.focused == focusedEditor,
+		IsFullScreen:      m.fullScreen,
+		IsHelpVisible:     m.view == viewHelp,
 
 		// Data state
-		HasQueryResults: len(m.content.GetQueryResults()) > 0,
-		HasHistory:      len(m.historyLogs) > 0,
-		ResultCount:     len(m.content.GetQueryResults()),
-		HistoryCount:    len(m.historyLogs),
+		HasQueryResults:  len(m.content.GetQueryResults()) > 0,
+		HasPinnedResults: m.pinned != nil,
+		InVisualMode:     m.content.IsVisualMode(),
+		HasHistory:       len(m.historyLogs) > 0,
+		ResultCount:      len(m.content.GetQueryResults()),
+		HistoryCount:     len(m.historyLogs),
 
 		// Feature availability
-		LLMEnabled:      m.llm != nil,
-		LLMSchemaShared: m.server.ShareDatabaseSchemaLLM,
+		LLMEnabled:             m.llm != nil,
+		LLMSchemaShared:        m.server.ShareDatabaseSchemaLLM,
+		HasLLMExampleCandidate: m.llmExampleCandidate != nil,
+		HasSelectedPlanNode:    m.focused == focusedContent && m.hasSelectedPlanNode(),
+
+		// Data quality
+		HasRowsQuery:          m.focused == focusedContent && m.hasRowsQuery(),
+		HasNotValidConstraint: m.focused == focusedContent && m.hasNotValidConstraint(),
+
+		// Statistics
+		HasDropSuggestions: m.focused == focusedContent && m.hasDropSuggestions(),
+
+		// Editor settings
+		UppercaseKeywords: m.config.UppercaseKeywords(),
+
+		// Workspaces
+		Workspace:  m.workspace,
+		Workspaces: workspace.Names(m.workspaces),
 
 		// Update availability
 		HasUpdate: func() bool {
@@ -48,6 +69,13 @@ func (m *model) updateMenuContext() {
 		}(),
 	}
 
+	if m.llm != nil {
+		settings := m.llm.Settings()
+		ctx.LLMTemperature = settings.Get(llm.SettingTemperature)
+		ctx.LLMMaxTokens = settings.Get(llm.SettingMaxTokens)
+		ctx.LLMTimeout = settings.Get(llm.SettingTimeout)
+	}
+
 	m.menuRegistry.UpdateContext(ctx)
 	m.whichKeyMenu.SetContext(ctx)
 }
@@ -72,10 +100,11 @@ func (m model) handleLeaderSequence(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// When a leader sequence is active, always start from the root menu.
-	// The root menu is context-aware and will return appropriate items.
+	// When a leader sequence is active, start from the menu of the context:
+	// the results menu while the results are focused, the root menu otherwise.
+	// Both are context-aware and will return appropriate items.
 	// Submenu navigation is handled by the whichKeyMenu component itself.
-	currentMenu := m.menuRegistry.GetRootMenu()
+	currentMenu := m.menuRegistry.GetContextMenu()
 	items := currentMenu.GetItems()
 
 	// Check if the pressed key matches any menu item
@@ -85,12 +114,15 @@ func (m model) handleLeaderSequence(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if item.Action.IsSubmenu() {
 				// Show submenu immediately
 				submenu := item.Action.Execute().(whichkey.ShowSubmenuMsg)
-				m.whichKeyMenu.SetMenu(submenu.Menu)
+				m.whichKeyMenu.SetMenu(currentMenu)
+				m.whichKeyMenu.EnterSubmenu(item, submenu.Menu)
 				m.whichKeyMenu.Show()
 				return m, nil
 			} else {
 				// Execute direct action
-				return m.Update(item.Action.Execute())
+				recordCmd := m.recordMenuUse(currentMenu, []string{item.Key})
+				updated, cmd := m.Update(item.Action.Execute())
+				return updated, tea.Batch(recordCmd, cmd)
 			}
 		}
 	}
@@ -100,6 +132,20 @@ func (m model) handleLeaderSequence(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// recordMenuUse counts a run of the action reached with keys from menu and
+// saves the usage, which lists the most used actions first in the root menu
+func (m *model) recordMenuUse(menu *whichkey.Menu, keys []string) tea.Cmd {
+	if !m.menuRegistry.RecordUse(menu, keys) {
+		return nil
+	}
+
+	if err := whichkey.SaveUsage(m.config.Storage(), m.menuRegistry.Usage()); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return nil
+}
+
 func (m model) handleWhichKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.whichKeyMenu, cmd = m.whichKeyMenu.Update(msg)
@@ -107,9 +153,13 @@ func (m model) handleWhichKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) canTriggerLeaderKey() bool {
+	if m.isFinderActive {
+		return false
+	}
+
 	switch m.view {
 	case viewMain:
-		return (!m.editor.IsFocused() || m.editor.IsNormalMode()) && !m.isPromptActive && m.focused != focusedCommand
+		return (!m.editor.IsFocused() || m.editor.IsNormalMode()) && !m.isPromptActive && !m.isQueryBuilderActive && m.focused != focusedCommand
 	case viewServers:
 		return m.serverSelection.CanTriggerLeaderKey()
 	case viewExportData:
@@ -120,6 +170,8 @@ func (m model) canTriggerLeaderKey() bool {
 		return m.history.CanTriggerLeaderKey()
 	case viewSnippets:
 		return m.snippets.CanTriggerLeaderKey()
+	case viewLLMExamples:
+		return m.llmExamplesView.CanTriggerLeaderKey()
 	default:
 		return true
 	}
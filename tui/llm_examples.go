@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/examples"
+	examplesView "github.com/ionut-t/perp/tui/examples"
+)
+
+// llmExamplesPath returns the few-shot examples file for the connected server
+func (m *model) llmExamplesPath() string {
+	return examples.GetPath(m.config.Storage(), m.server.Name)
+}
+
+// loadLLMExamples reads the few-shot examples of the connected server
+func (m *model) loadLLMExamples() {
+	m.llmAskPrompt = ""
+	m.llmExampleCandidate = nil
+
+	entries, err := examples.Load(m.llmExamplesPath())
+	if err != nil {
+		m.llmExamples = []examples.Example{}
+		return
+	}
+
+	m.llmExamples = entries
+}
+
+// trackLLMExampleCandidate remembers a successfully executed query as a
+// possible example for the last /ask question
+func (m *model) trackLLMExampleCandidate(query string) {
+	query = strings.TrimSpace(query)
+	if m.llmAskPrompt == "" || query == "" {
+		return
+	}
+
+	m.llmExampleCandidate = &examples.Example{
+		Prompt: m.llmAskPrompt,
+		Query:  query,
+	}
+}
+
+// saveLLMExample stores the last /ask question with the query that ran successfully for it
+func (m model) saveLLMExample() (tea.Model, tea.Cmd) {
+	if m.llmExampleCandidate == nil {
+		return m, m.errorNotification(fmt.Errorf("run a query generated with /ask before saving it as an example"))
+	}
+
+	entries, err := examples.Add(m.llmExamplesPath(), m.llmExampleCandidate.Prompt, m.llmExampleCandidate.Query)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.llmExamples = entries
+	m.llmAskPrompt = ""
+	m.llmExampleCandidate = nil
+
+	return m, m.successNotification("Query saved as an LLM example")
+}
+
+// listLLMExamples opens the view for managing few-shot examples
+func (m model) listLLMExamples() (tea.Model, tea.Cmd) {
+	m.view = viewLLMExamples
+	m.focused = focusedLLMExamples
+	m.editor.Blur()
+
+	m.llmExamplesView = examplesView.New(m.llmExamples, m.width, m.height)
+	m.llmExamplesView.SetStyles(m.styles, m.isDark)
+
+	examplesModel, cmd := m.llmExamplesView.Update(nil)
+	m.llmExamplesView = examplesModel
+	return m, cmd
+}
+
+// deleteLLMExample removes an example and refreshes the examples view
+func (m model) deleteLLMExample(msg examplesView.DeleteMsg) (tea.Model, tea.Cmd) {
+	entries, err := examples.Delete(m.llmExamplesPath(), msg.Prompt)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.llmExamples = entries
+	m.llmExamplesView.SetExamples(entries)
+
+	return m, m.successNotification("LLM example deleted")
+}
@@ -5,9 +5,12 @@ import (
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/examples"
 	"github.com/ionut-t/perp/pkg/llm"
+	llmFactory "github.com/ionut-t/perp/pkg/llm/llm_factory"
 	"github.com/ionut-t/perp/tui/command"
 	"github.com/ionut-t/perp/tui/content"
+	"github.com/ionut-t/perp/tui/prompt"
 )
 
 // ask sends a query to the LLM
@@ -17,6 +20,10 @@ func (m model) ask(prompt string, cmd llm.Command) tea.Cmd {
 			return llmFailureMsg{err: fmt.Errorf("LLM is not configured: %w", m.llmError)}
 		}
 
+		if cmd == llm.Ask {
+			prompt = examples.FormatPrompt(m.llmExamples, prompt)
+		}
+
 		response, err := m.llm.Ask(prompt, cmd)
 		if err != nil {
 			return llmFailureMsg{err: err}
@@ -30,8 +37,33 @@ func (m model) ask(prompt string, cmd llm.Command) tea.Cmd {
 func (m *model) handleLLMResponse(msg llmResponseMsg) {
 	m.loading = false
 	query := strings.TrimSpace(m.editor.GetCurrentContent())
+
+	// The generated predicate is shown as the full filtered query, which is
+	// placed in the editor to be run.
+	if msg.Command == llm.Filter {
+		if predicate := llm.ExtractPredicate(msg.Response); predicate != "" {
+			msg.Response = fmt.Sprintf("```sql\n%s\n```", llm.ApplyFilter(m.lastQuery, predicate))
+		} else {
+			msg.Command = llm.Info
+		}
+	}
+
 	m.content.SetLLMResponse(llm.Response(msg), query)
 
+	if llm.IsAskCommand(query) {
+		m.llmAskPrompt = strings.TrimSpace(query[len("/ask"):])
+		m.llmExampleCandidate = nil
+	}
+
+	// Fixes and optimisations that change the query are shown as a diff and
+	// only applied to the editor once the suggestion is accepted.
+	if m.content.HasPendingSuggestion() {
+		m.focused = focusedContent
+		m.editor.Blur()
+		m.editor.SetNormalMode()
+		return
+	}
+
 	content := llm.ExtractQuery(string(msg.Response))
 	m.editor.SetContent(content)
 
@@ -81,6 +113,44 @@ func (m model) updateLLMModel(msg command.LLMModelChangedMsg) (tea.Model, tea.Cm
 	return m, m.successNotification("LLM model changed to " + msg.Model)
 }
 
+// updateLLMSetting changes a generation setting for the active provider
+func (m model) updateLLMSetting(msg command.LLMSettingChangedMsg) (tea.Model, tea.Cmd) {
+	if err := m.requireLLM(); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	provider, err := llmFactory.ResolveProvider(m.config)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	settings := m.llm.Settings()
+	if err := settings.Set(msg.Name, msg.Value); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	value := settings.Get(msg.Name)
+	if err := m.config.SetLLMSetting(provider, msg.Name, value); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.llm.SetSettings(settings)
+
+	m.focusEditor()
+	return m, m.successNotification(fmt.Sprintf("LLM %s set to %s", strings.ReplaceAll(msg.Name, "_", " "), value))
+}
+
+// openLLMSettingPrompt opens the prompt to edit a generation setting
+func (m *model) openLLMSettingPrompt(action prompt.Action, setting string) {
+	if m.llm == nil {
+		return
+	}
+
+	m.isPromptActive = true
+	m.prompt.SetAction(action)
+	m.prompt.SetInitialValue(m.llm.Settings().Get(setting))
+}
+
 // toggleDBSchemaSharing enables or disables database schema sharing with LLM
 func (m model) toggleDBSchemaSharing(msg command.LLMUseDatabaseSchemaMsg) (tea.Model, tea.Cmd) {
 	if err := m.requireLLM(); err != nil {
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/coffee/styles"
+	editor "github.com/ionut-t/goeditor"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/tui/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLLMTestModel(query string) model {
+	ed := editor.New(80, 10)
+	ed.SetContent(query)
+	ed.Focus()
+
+	c := content.New(80, 20)
+	c.SetStyles(styles.New(true), true)
+
+	return model{
+		editor:  ed,
+		content: c,
+		focused: focusedEditor,
+		loading: true,
+	}
+}
+
+func TestHandleLLMResponseFixWithChanges(t *testing.T) {
+	t.Parallel()
+
+	query := "-- fix\nSELECT * FROM usr"
+	m := newLLMTestModel(query)
+
+	m.handleLLMResponse(llmResponseMsg{
+		Response: "```sql\nSELECT * FROM users\n```",
+		Command:  llm.Fix,
+	})
+
+	assert.False(t, m.loading)
+	assert.Equal(t, focusedContent, m.focused)
+	assert.Equal(t, query, m.editor.GetCurrentContent(), "editor must keep the original query until the suggestion is applied")
+	assert.True(t, m.content.HasPendingSuggestion())
+}
+
+func TestHandleLLMResponseFixWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("SELECT * FROM users; -- fix")
+
+	m.handleLLMResponse(llmResponseMsg{
+		Response: "Nothing to fix.\n```sql\nSELECT * FROM users;\n```",
+		Command:  llm.Fix,
+	})
+
+	assert.Equal(t, focusedEditor, m.focused)
+	assert.Equal(t, "SELECT * FROM users;", m.editor.GetCurrentContent())
+	assert.False(t, m.content.HasPendingSuggestion())
+}
+
+func TestEnterAppliesLLMSuggestionInsteadOfSubmitting(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("SELECT * FROM users -- optimise")
+	m.handleLLMResponse(llmResponseMsg{
+		Response: "```sql\nSELECT id FROM users\n```",
+		Command:  llm.Optimise,
+	})
+	require.True(t, m.content.HasPendingSuggestion())
+
+	enter := tea.KeyPressMsg{Code: tea.KeyEnter}
+
+	updated, cmd, handled := m.tryHandleKeyPress(enter)
+	assert.False(t, handled, "enter must not be handled as a submit while a suggestion is pending")
+	assert.Nil(t, cmd)
+	assert.False(t, updated.(model).loading, "the query must not be sent again")
+
+	_, cmd = m.content.Update(enter)
+	require.NotNil(t, cmd)
+
+	msg, ok := cmd().(content.LLMResponseSelectedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "SELECT id FROM users", msg.Response)
+}
+
+func TestAskResponseTracksExampleCandidate(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("/ask count users")
+
+	m.handleLLMResponse(llmResponseMsg{
+		Response: "```sql\nSELECT count(*) FROM users;\n```",
+		Command:  llm.Ask,
+	})
+
+	assert.Equal(t, "count users", m.llmAskPrompt)
+	assert.Nil(t, m.llmExampleCandidate, "nothing to keep until the query runs")
+
+	m.trackLLMExampleCandidate("SELECT count(id) FROM users;")
+
+	require.NotNil(t, m.llmExampleCandidate)
+	assert.Equal(t, "count users", m.llmExampleCandidate.Prompt)
+	assert.Equal(t, "SELECT count(id) FROM users;", m.llmExampleCandidate.Query)
+}
+
+func TestQueryWithoutAskIsNotExampleCandidate(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("SELECT 1;")
+	m.trackLLMExampleCandidate("SELECT 1;")
+
+	assert.Nil(t, m.llmExampleCandidate)
+}
+
+func TestHandleLLMResponseFilter(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("/filter only failed jobs")
+	m.lastQuery = "SELECT * FROM jobs;"
+
+	m.handleLLMResponse(llmResponseMsg{
+		Response: "WHERE status = 'failed'",
+		Command:  llm.Filter,
+	})
+
+	assert.Equal(t, focusedEditor, m.focused)
+	assert.Equal(t,
+		"SELECT *\nFROM (\nSELECT * FROM jobs\n) AS filtered\nWHERE status = 'failed';",
+		m.editor.GetCurrentContent(),
+	)
+}
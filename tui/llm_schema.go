@@ -6,7 +6,10 @@ import (
 	"slices"
 	"strings"
 
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/llm"
 	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/pkg/schemaindex"
 	"github.com/ionut-t/perp/pkg/utils"
 )
 
@@ -184,6 +187,56 @@ func (m *model) removeTablesSchemaToLLM() (string, error) {
 	return schema, nil
 }
 
+// shouldSelectRelevantTables reports whether /ask prompts should get the most
+// relevant tables attached automatically. Tables added manually with /add take precedence.
+func (m model) shouldSelectRelevantTables() bool {
+	return m.server.ShareDatabaseSchemaLLM &&
+		m.schemaIndex != nil &&
+		m.schemaIndex.Size() > 0 &&
+		len(m.llmSharedTablesSchema) == 0 &&
+		m.requireLLM() == nil
+}
+
+// selectRelevantTables searches the schema index for the tables most relevant
+// to the prompt and describes them for the LLM context.
+func (m model) selectRelevantTables(prompt string) tea.Cmd {
+	return func() tea.Msg {
+		question := strings.TrimSpace(strings.TrimPrefix(prompt, "/ask"))
+
+		results := m.schemaIndex.Search(question, schemaindex.DefaultTopK)
+		tables := make([]string, 0, len(results))
+		for _, result := range results {
+			tables = append(tables, result.Table)
+		}
+
+		schema, err := m.generateSchemaForTables(tables)
+
+		return llmRelevantTablesMsg{
+			prompt: prompt,
+			tables: tables,
+			schema: schema,
+			err:    err,
+		}
+	}
+}
+
+// askWithRelevantTables attaches the selected tables to the LLM context and sends the prompt
+func (m model) askWithRelevantTables(msg llmRelevantTablesMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.loading = false
+		return m, m.errorNotification(fmt.Errorf("failed to describe relevant tables: %w", msg.err))
+	}
+
+	m.llm.ResetInstructions()
+	if len(msg.tables) > 0 {
+		m.llm.AppendInstructions("Database Schema:\n\n" + msg.schema)
+	}
+
+	m.content.SetLLMRelevantSchema(msg.tables, msg.schema)
+
+	return m, m.ask(msg.prompt, llm.Ask)
+}
+
 // generateSchemaForTables uses the psql executor to describe tables.
 // It returns schema information for the LLM context.
 func (m *model) generateSchemaForTables(tables []string) (string, error) {
@@ -1,6 +1,8 @@
 package menu
 
 import (
+	"strings"
+
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/ionut-t/coffee/styles"
@@ -10,11 +12,23 @@ import (
 
 type Model struct {
 	currentMenu *whichkey.Menu
+	trail       []crumb // the menus entered to reach the current one
 	context     *whichkey.MenuContext
 	styles      menuStyles
 	visible     bool
 }
 
+// crumb is a menu above the current one and the key and label of the item
+// opening the next menu
+type crumb struct {
+	menu  *whichkey.Menu
+	key   string
+	label string
+}
+
+// breadcrumbSeparator separates the menus of the path shown as the title
+const breadcrumbSeparator = " ▸ "
+
 // menuStyles defines the visual styling for menus
 type menuStyles struct {
 	Border      lipgloss.Style
@@ -82,7 +96,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, utils.Dispatch(whichkey.CloseMenuMsg{})
 
 	case "backspace":
-		if m.currentMenu.Parent != nil {
+		if n := len(m.trail); n > 0 {
+			m.currentMenu = m.trail[n-1].menu
+			m.trail = m.trail[:n-1]
+		} else if m.currentMenu.Parent != nil {
 			m.currentMenu = m.currentMenu.Parent
 		} else {
 			// No parent, close the menu
@@ -103,13 +120,18 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 				if item.Action.IsSubmenu() {
 					submenu := item.Action.Execute().(whichkey.ShowSubmenuMsg)
-					m.currentMenu = submenu.Menu
+					m.EnterSubmenu(item, submenu.Menu)
 					return m, nil
 				} else {
 					// Execute action and close menu
 					m.visible = false
 					actionMsg := item.Action.Execute()
-					return m, utils.Dispatch(whichkey.ExecuteAndCloseMsg{ActionMsg: actionMsg})
+					menu, keys := m.path()
+					return m, utils.Dispatch(whichkey.ExecuteAndCloseMsg{
+						ActionMsg: actionMsg,
+						Menu:      menu,
+						Keys:      append(keys, item.Key),
+					})
 				}
 			}
 		}
@@ -127,7 +149,7 @@ func (m Model) View() string {
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
-		m.styles.Title.Render(m.currentMenu.Title),
+		m.styles.Title.Render(m.title()),
 		lipgloss.JoinVertical(lipgloss.Left, menuItems...),
 		m.renderFooter(),
 	)
@@ -172,19 +194,57 @@ func (m Model) renderItems() []string {
 	return menuItems
 }
 
+// title returns the path to the current menu, e.g. "Perp Commands ▸ Database",
+// or its title when it was opened directly
+func (m Model) title() string {
+	if len(m.trail) == 0 {
+		return m.currentMenu.Title
+	}
+
+	path := []string{m.trail[0].menu.Title}
+	for _, c := range m.trail {
+		path = append(path, c.label)
+	}
+
+	return strings.Join(path, breadcrumbSeparator)
+}
+
 func (m Model) renderFooter() string {
 	footerText := "Press [esc] to close"
 
-	if m.currentMenu.Parent != nil {
+	if len(m.trail) > 0 || m.currentMenu.Parent != nil {
 		footerText = "Press [esc] to close, [backspace] to go back"
 	}
 
 	return m.styles.Footer.Render(footerText)
 }
 
-// SetMenu changes the current menu
+// SetMenu changes the current menu, starting a new path
 func (m *Model) SetMenu(menu *whichkey.Menu) {
 	m.currentMenu = menu
+	m.trail = nil
+}
+
+// EnterSubmenu opens the submenu of item in the current menu, which
+// backspace goes back to
+func (m *Model) EnterSubmenu(item whichkey.MenuItem, submenu *whichkey.Menu) {
+	m.trail = append(m.trail, crumb{menu: m.currentMenu, key: item.Key, label: item.Label})
+	m.currentMenu = submenu
+}
+
+// path returns the menu the current one was entered from and the keys typed
+// since
+func (m Model) path() (*whichkey.Menu, []string) {
+	if len(m.trail) == 0 {
+		return m.currentMenu, nil
+	}
+
+	keys := make([]string, 0, len(m.trail)+1)
+	for _, c := range m.trail {
+		keys = append(keys, c.key)
+	}
+
+	return m.trail[0].menu, keys
 }
 
 // GetCurrentMenu returns the current menu
@@ -1,10 +1,18 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/ionut-t/goeditor/core"
+	"github.com/ionut-t/perp/pkg/dataquality"
+	"github.com/ionut-t/perp/pkg/datasync"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/joins"
 	"github.com/ionut-t/perp/pkg/llm"
 	"github.com/ionut-t/perp/pkg/lsp"
 	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/pkg/schemaindex"
+	"github.com/ionut-t/perp/pkg/stats"
 	"github.com/ionut-t/perp/pkg/update"
 	"github.com/ionut-t/perp/tui/content"
 )
@@ -16,13 +24,55 @@ type schemaFailureMsg struct {
 	err error
 }
 
+type schemaIndexLoadedMsg struct {
+	index *schemaindex.Index
+}
+
 // Query execution messages
 type executeQueryMsg content.ParsedQueryResult
 
+// executeStatementsMsg holds the results of the statements of a buffer run
+// one after another, the failure that stopped them, and whether a transaction
+// they left open was rolled back
+type executeStatementsMsg struct {
+	results    []content.ParsedQueryResult
+	err        error
+	rolledBack bool
+}
+
+// queryTimingMsg holds the average of the previous timed runs of a query,
+// read once its execution time was recorded, and the notification of its
+// results to add it to
+type queryTimingMsg struct {
+	message string
+	average time.Duration
+	ok      bool
+}
+
 type queryFailureMsg struct {
 	err error
 }
 
+// streamQueryMsg holds the first batch of rows of a query fetched in batches
+type streamQueryMsg struct {
+	stream *db.Stream
+	result content.ParsedQueryResult
+}
+
+// streamRowsMsg holds the next batch of rows of a query fetched in batches
+type streamRowsMsg struct {
+	stream *db.Stream
+	rows   []map[string]db.RowResult
+	err    error
+}
+
+// streamIdleMsg checks whether rows were fetched from a stream since fetched
+// rows were, closing it otherwise
+type streamIdleMsg struct {
+	stream  *db.Stream
+	fetched int
+}
+
 // LLM-related messages
 type llmResponseMsg llm.Response
 
@@ -30,6 +80,25 @@ type llmFailureMsg struct {
 	err error
 }
 
+// offlineAnalysisMsg carries the rule-based fallback for -- explain / -- optimise
+type offlineAnalysisMsg struct {
+	response string
+}
+
+// planNodeExplanationMsg carries the LLM analysis of a single EXPLAIN plan node
+type planNodeExplanationMsg llm.Response
+
+// schemaTourMsg carries the onboarding document written by the LLM for the schema tour
+type schemaTourMsg llm.Response
+
+// llmRelevantTablesMsg carries the tables selected from the schema index for an /ask prompt
+type llmRelevantTablesMsg struct {
+	prompt string
+	tables []string
+	schema string
+	err    error
+}
+
 type llmSharedSchemaMsg struct {
 	schema  string
 	message string
@@ -42,13 +111,56 @@ type psqlCommandMsg struct {
 }
 
 type psqlResultMsg struct {
-	result *psql.Result
+	command     string
+	commandType psql.CommandType
+	result      *psql.Result
 }
 
 type psqlErrorMsg struct {
 	err error
 }
 
+// outputMsg starts writing query results to a file, or stops when path is empty
+type outputMsg struct {
+	path string
+}
+
+// echoMsg shows a message of \echo, or writes one of \qecho to the file set with \o
+type echoMsg struct {
+	text  string
+	query bool
+}
+
+// passwordMsg asks the new password of role for \password
+type passwordMsg struct {
+	role string
+}
+
+// passwordChangedMsg reports the outcome of changing the password of role
+type passwordChangedMsg struct {
+	role     string
+	password string
+	err      error
+}
+
+// sessionResetMsg reports the outcome of DISCARD ALL run by :reset-session
+type sessionResetMsg struct {
+	err error
+}
+
+// variableMsg lists the variables when name is empty, or sets or removes one
+type variableMsg struct {
+	name  string
+	value string
+	unset bool
+}
+
+// psetMsg shows the display options, or changes one of them
+type psetMsg struct {
+	option string
+	value  string
+}
+
 // PSQL toggle and control messages
 type (
 	toggleExpandedMsg struct{}
@@ -67,6 +179,14 @@ type updateAvailableMsg struct {
 	release *update.LatestReleaseInfo
 }
 
+type updateInstalledMsg struct {
+	version string
+}
+
+type releaseNotesMsg struct {
+	notes *update.ReleaseNotes
+}
+
 // LSP messages
 type lspConnectedMsg struct {
 	client *lsp.Client
@@ -78,6 +198,126 @@ type lspFailedMsg struct {
 
 type lspCompletionResultMsg struct {
 	completions []core.Completion
+	columns     []core.Completion // the columns of the tables in the statement
 	context     core.CompletionContext
 	err         error
 }
+
+// Pipe messages
+type pipeOutputMsg struct {
+	command string
+	output  string
+	err     error
+}
+
+// Image preview messages
+type imagePreviewMsg struct {
+	sequence string
+}
+
+// Result info messages
+type resultInfoMsg struct {
+	info        content.ResultInfo
+	estimate    int
+	hasEstimate bool
+}
+
+// On connect messages
+type onConnectMsg struct {
+	results []db.StatementResult
+	err     error
+}
+
+// Gexec messages
+type gexecStatementsMsg struct {
+	query      string
+	statements []string
+}
+
+// destructiveQueryMsg holds a query to confirm before it runs
+type destructiveQueryMsg struct {
+	query      string
+	statements []psql.Destructive
+}
+
+// includeStatementsMsg holds the statements of the file run by \i
+type includeStatementsMsg struct {
+	path       string
+	statements []string
+}
+
+// gexecResultMsg reports the outcome of the statements run by \gexec or \i,
+// the failure that stopped them when ON_ERROR_STOP is on, whether a
+// transaction they left open was rolled back, and the variables of \i once
+// its statements ran
+type gexecResultMsg struct {
+	command    string
+	result     *psql.Result
+	total      int
+	failed     int
+	ddl        bool
+	err        error
+	rolledBack bool
+	variables  psql.Variables
+}
+
+// Constraint validation messages
+type validationLocksMsg struct {
+	validation dataquality.Validation
+	conflicts  int64
+	err        error
+}
+
+type constraintValidatedMsg struct {
+	validation dataquality.Validation
+	elapsed    time.Duration
+	err        error
+}
+
+// Function editing messages
+type editFunctionMsg struct {
+	signature  string
+	path       string
+	definition string
+}
+
+type functionEditedMsg struct {
+	editFunctionMsg
+	err error
+}
+
+// Scratch table messages
+type materializedMsg struct {
+	table string
+	rows  int
+	err   error
+}
+
+// Server comparison messages
+type serversComparedMsg struct {
+	server     string // the name of the other server
+	current    content.ParsedQueryResult
+	currentErr error
+	other      content.ParsedQueryResult
+	otherErr   error
+}
+
+type syncPreviewMsg struct {
+	server string // the name of the source server
+	sql    bool
+	report *datasync.Report
+	err    error
+}
+
+// joinFoundMsg carries the foreign keys between the table added by :join and
+// the tables of the query
+type joinFoundMsg struct {
+	joins []joins.Join
+	err   error
+}
+
+// Statistics messages
+type walStatsMsg struct {
+	stats *stats.WALStats
+	err   error
+}
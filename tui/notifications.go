@@ -11,6 +11,12 @@ func (m *model) successNotification(msg string) tea.Cmd {
 	return utils.ClearAfter(NotificationDuration)
 }
 
+// warningNotification displays a warning message
+func (m *model) warningNotification(msg string) tea.Cmd {
+	m.notification = m.styles.Warning.Render(msg)
+	return utils.ClearAfter(NotificationDuration)
+}
+
 // errorNotification displays an error message
 func (m *model) errorNotification(err error) tea.Cmd {
 	m.notification = m.styles.Error.Render(err.Error())
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/advisor"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/llm"
+)
+
+// analyseOffline is the fallback for -- explain and -- optimise when no LLM
+// is configured. It runs a plain EXPLAIN (never ANALYZE, so the statement is
+// not executed) and feeds the plan to the rule-based advisor.
+func (m model) analyseOffline(prompt string, cmd llm.Command) tea.Cmd {
+	return func() tea.Msg {
+		query := llm.StripCommandComments(prompt)
+		if query == "" {
+			return llmFailureMsg{err: fmt.Errorf("no query to analyse")}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		result, err := m.db.Query(ctx, "EXPLAIN "+query)
+		if err != nil {
+			return llmFailureMsg{err: fmt.Errorf("failed to explain query: %w", err)}
+		}
+
+		rows, _, err := db.ExtractPsqlResults(result.Rows())
+		if err != nil {
+			return llmFailureMsg{err: fmt.Errorf("failed to read query plan: %w", err)}
+		}
+
+		plan := make([]string, 0, len(rows))
+		for _, row := range rows {
+			plan = append(plan, fmt.Sprintf("%v", row["QUERY PLAN"]))
+		}
+
+		return offlineAnalysisMsg{
+			response: renderOfflineAnalysis(query, plan, cmd),
+		}
+	}
+}
+
+// renderOfflineAnalysis renders the plan and advisor hints as markdown
+func renderOfflineAnalysis(query string, plan []string, cmd llm.Command) string {
+	var sb strings.Builder
+
+	if cmd == llm.Optimise {
+		sb.WriteString("# Optimisation hints\n\n")
+	} else {
+		sb.WriteString("# Query plan\n\n")
+	}
+
+	sb.WriteString("_No LLM is configured, showing a rule-based analysis._\n\n")
+
+	if cmd == llm.Explain {
+		if nodes := advisor.NodeTypes(plan); len(nodes) > 0 {
+			sb.WriteString("## Plan nodes\n\n")
+			for _, node := range nodes {
+				fmt.Fprintf(&sb, "- %s\n", node)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	hints := append(advisor.AnalyseQuery(query), advisor.AnalysePlan(plan)...)
+
+	sb.WriteString("## Hints\n\n")
+	if len(hints) == 0 {
+		sb.WriteString("No issues found.\n\n")
+	}
+	for _, hint := range hints {
+		fmt.Fprintf(&sb, "- **%s**: %s\n", hint.Title, hint.Detail)
+	}
+
+	sb.WriteString("\n## EXPLAIN\n\n```\n")
+	sb.WriteString(strings.Join(plan, "\n"))
+	sb.WriteString("\n```\n")
+
+	return sb.String()
+}
+
+// handleOfflineAnalysis shows the offline analysis without touching the editor
+func (m *model) handleOfflineAnalysis(msg offlineAnalysisMsg) {
+	m.loading = false
+	m.content.SetLLMResponse(llm.Response{
+		Response: msg.response,
+		Time:     time.Now(),
+		Command:  llm.Info,
+	}, "")
+	m.focused = focusedContent
+	m.editor.Blur()
+	m.editor.SetNormalMode()
+}
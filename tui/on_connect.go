@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// runOnConnect fetches the output of the server's on connect snippet, which
+// the pool runs on every new connection
+func (m model) runOnConnect() tea.Cmd {
+	if m.server.OnConnect == "" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		results, err := m.db.OnConnect(ctx)
+		return onConnectMsg{results: results, err: err}
+	}
+}
+
+func (m *model) handleOnConnect(msg onConnectMsg) tea.Cmd {
+	if msg.err != nil {
+		m.content.SetOnConnectOutput(m.styles.Error.Render(msg.err.Error()))
+		return m.errorNotification(msg.err)
+	}
+
+	m.content.SetOnConnectOutput(formatStatementResults(msg.results))
+	return nil
+}
+
+// formatStatementResults renders the rows returned by each statement as an
+// aligned table, or its command tag when it returns none
+func formatStatementResults(results []db.StatementResult) string {
+	outputs := make([]string, 0, len(results))
+
+	for _, result := range results {
+		if len(result.Columns) == 0 {
+			outputs = append(outputs, result.CommandTag)
+			continue
+		}
+
+		var sb strings.Builder
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(result.Columns, "\t"))
+		for _, row := range result.Rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		_ = w.Flush()
+
+		rows := "rows"
+		if len(result.Rows) == 1 {
+			rows = "row"
+		}
+		fmt.Fprintf(&sb, "(%d %s)", len(result.Rows), rows)
+
+		outputs = append(outputs, sb.String())
+	}
+
+	return strings.Join(outputs, "\n\n")
+}
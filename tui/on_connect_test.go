@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatStatementResults(t *testing.T) {
+	t.Parallel()
+
+	out := formatStatementResults([]db.StatementResult{
+		{CommandTag: "SET"},
+		{
+			Columns:    []string{"version", "now"},
+			Rows:       [][]string{{"17.2", "2025-03-01"}},
+			CommandTag: "SELECT 1",
+		},
+		{Columns: []string{"id"}, CommandTag: "SELECT 0"},
+	})
+
+	expected := "SET\n\n" +
+		"version  now\n" +
+		"17.2     2025-03-01\n" +
+		"(1 row)\n\n" +
+		"id\n" +
+		"(0 rows)"
+
+	assert.Equal(t, expected, out)
+}
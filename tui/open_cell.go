@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/browser"
+)
+
+// openCell opens a URL or file path stored in a cell with the system opener
+func (m *model) openCell(value string) tea.Cmd {
+	target, ok := browser.Target(value)
+	if !ok {
+		return m.errorNotification(errors.New("the selected cell does not contain a URL or an existing file path"))
+	}
+
+	if err := browser.Open(target); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification("Opened " + target)
+}
+
+// openLink opens a link of an LLM response with the system opener
+func (m *model) openLink(link string) tea.Cmd {
+	target, ok := browser.Target(link)
+	if !ok {
+		return m.errorNotification(errors.New("the link is not a URL or an existing file path: " + link))
+	}
+
+	if err := browser.Open(target); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification("Opened " + target)
+}
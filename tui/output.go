@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/debug"
+	"github.com/ionut-t/perp/pkg/redirect"
+)
+
+// setOutput handles \o. With a file, the results of the next queries are also
+// written to it; without one, the redirection stops.
+func (m model) setOutput(msg outputMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	resetCmd := m.resetEditor()
+
+	if msg.path == "" {
+		if !m.output.Active() {
+			return m, tea.Batch(resetCmd, m.successNotification("Query results are not redirected"))
+		}
+
+		path := m.output.Path
+		err := m.output.Close()
+		m.output = redirect.Target{}
+		if err != nil {
+			return m, tea.Batch(resetCmd, m.errorNotification(err))
+		}
+		return m, tea.Batch(resetCmd, m.successNotification(fmt.Sprintf("Stopped writing query results to %s", path)))
+	}
+
+	storage := filepath.Join(m.config.Storage(), m.server.Name, exportDataDirectory)
+
+	target, err := redirect.Start(msg.path, storage)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	// the previous file, if any, is complete once closed
+	if err := m.output.Close(); err != nil {
+		debug.Printf("failed to close %s: %v", m.output.Path, err)
+	}
+
+	m.output = target
+
+	return m, tea.Batch(
+		resetCmd,
+		m.successNotification(fmt.Sprintf("Writing query results to %s until \\o", target.Path)),
+	)
+}
+
+// echo handles \echo and \qecho. The message of \qecho is written to the
+// file set with \o when there is one.
+func (m model) echo(msg echoMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	resetCmd := m.resetEditor()
+
+	if msg.query && m.output.Active() {
+		if err := m.output.WriteText(msg.text); err != nil {
+			return m, m.errorNotification(fmt.Errorf("failed to write to %s: %w", m.output.Path, err))
+		}
+		return m, resetCmd
+	}
+
+	if msg.text == "" {
+		return m, resetCmd
+	}
+
+	return m, tea.Batch(resetCmd, m.successNotification(msg.text))
+}
+
+// writeOutput appends the current results to the file set with \o, with
+// their columns in order
+func (m *model) writeOutput() error {
+	if !m.output.Active() {
+		return nil
+	}
+
+	info, _ := m.content.ResultInfo()
+
+	var columns []string
+	for _, column := range info.Columns {
+		columns = append(columns, column.Name)
+	}
+
+	if err := m.output.Write(m.content.GetQueryResults(), columns); err != nil {
+		debug.Printf("failed to redirect query results: %v", err)
+		return fmt.Errorf("failed to write results to %s: %w", m.output.Path, err)
+	}
+
+	return nil
+}
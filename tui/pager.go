@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/pager"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// openPager shows text in the pager set in $PAGER, suspending the TUI until
+// the pager exits
+func (m *model) openPager(text string) tea.Cmd {
+	cmd, path, err := pager.Command(pager.FromEnv(os.Getenv), text)
+	if err != nil {
+		return m.errorNotification(err)
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		_ = os.Remove(path)
+
+		if err != nil {
+			return notificationErrorMsg{err: fmt.Errorf("pager failed: %w", err)}
+		}
+		return nil
+	})
+}
+
+// pageLongOutput opens the output just shown in the content in the pager,
+// when it is taller than the content with \pset pager on, or always
+func (m *model) pageLongOutput() tea.Cmd {
+	text, ok := m.content.PagerText()
+	if !ok {
+		return nil
+	}
+
+	switch m.displayOptions.Pager {
+	case psql.PagerAlways:
+		return m.openPager(text)
+	case psql.PagerOn:
+		if pager.Exceeds(text, m.content.Height()) {
+			return m.openPager(text)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/export"
+	"github.com/ionut-t/perp/pkg/pane"
+)
+
+// openInPane opens the selected export record, or all query results, in a new terminal pane
+func (m model) openInPane() tea.Cmd {
+	if m.view == viewExportData {
+		path := m.exportData.SelectedPath()
+		if path == "" {
+			return m.errorNotification(errors.New("no exported record selected"))
+		}
+
+		return m.openFileInPane(path)
+	}
+
+	data, err := export.PrepareJSON(m.content.GetQueryResults(), nil, true)
+	if err != nil {
+		return m.errorNotification(err)
+	}
+
+	path, err := writeResultsFile(data)
+	if err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.openFileInPane(path)
+}
+
+func (m model) openFileInPane(path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := pane.Open(m.config.PaneCommand(), path); err != nil {
+			return notificationErrorMsg{err: err}
+		}
+
+		return nil
+	}
+}
+
+// writeResultsFile writes data to a temporary JSON file. The file is kept because
+// the viewer in the new pane reads it after the pane command returns.
+func writeResultsFile(data any) (string, error) {
+	file, err := os.CreateTemp("", "perp-results-*.json")
+	if err != nil {
+		return "", err
+	}
+
+	//nolint:errcheck
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
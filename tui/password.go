@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/google/uuid"
+	"github.com/ionut-t/perp/pkg/psql"
+	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/tui/prompt"
+)
+
+// askPassword handles \password by asking the new password of the role, the
+// connected user by default, twice in a masked prompt
+func (m model) askPassword(msg passwordMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	m.passwordRole = msg.role
+	if m.passwordRole == "" {
+		m.passwordRole = m.server.Username
+	}
+
+	m.isPromptActive = true
+	m.prompt.SetAction(prompt.PasswordAction)
+
+	return m, nil
+}
+
+// changePassword sets the confirmed password with ALTER ROLE, encrypted as
+// set in password_encryption. The statement is not added to the history.
+func (m model) changePassword(msg command.PasswordMsg) (tea.Model, tea.Cmd) {
+	role := m.passwordRole
+	m.passwordRole = ""
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		method, err := psql.PasswordEncryption(ctx, m.db)
+		if err != nil {
+			return passwordChangedMsg{role: role, err: err}
+		}
+
+		sql, err := psql.AlterPasswordSQL(role, msg.Password, method)
+		if err != nil {
+			return passwordChangedMsg{role: role, err: err}
+		}
+
+		_, _, err = m.execStatement(ctx, m.db, sql, nil)
+		return passwordChangedMsg{role: role, password: msg.Password, err: err}
+	})
+}
+
+// handlePasswordChanged reports the new password and, when it belongs to the
+// user of a saved server, offers to save it there for the next connections
+func (m model) handlePasswordChanged(msg passwordChangedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(fmt.Errorf("failed to change the password of %s: %w", msg.role, msg.err))
+	}
+
+	resetCmd := m.resetEditor()
+
+	if msg.role == m.server.Username && m.server.ID != uuid.Nil && msg.password != m.server.Password {
+		m.isPromptActive = true
+		m.prompt.SetAction(prompt.UpdateServerPasswordAction)
+		m.prompt.SetPendingPassword(msg.password)
+	}
+
+	return m, tea.Batch(
+		resetCmd,
+		m.successNotification(fmt.Sprintf("Password changed for role %s", msg.role)),
+	)
+}
+
+func (m model) saveServerPassword(msg command.ServerPasswordMsg) (tea.Model, tea.Cmd) {
+	if err := m.server.SetPassword(msg.Password, m.config.Storage()); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	return m, m.successNotification(fmt.Sprintf("Saved the new password in %s", m.server.Name))
+}
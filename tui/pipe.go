@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/pipe"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// pipeResults streams the current results through a shell command
+func (m model) pipeResults(msg command.PipeMsg) (tea.Model, tea.Cmd) {
+	input, err := pipe.Encode(m.content.GetQueryResults(), msg.Format)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(
+		func() tea.Msg {
+			output, err := pipe.Run(context.Background(), msg.Command, input)
+			return pipeOutputMsg{command: msg.Command, output: output, err: err}
+		},
+		m.spinner.Tick,
+	)
+}
+
+// handlePipeOutput shows the output of a pipe command in the content viewport
+func (m model) handlePipeOutput(msg pipeOutputMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(fmt.Errorf("pipe failed: %w", msg.err))
+	}
+
+	m.content.SetPipeOutput(msg.command, msg.output)
+	m.focused = focusedContent
+	m.editor.Blur()
+	m.command.Reset()
+
+	return m, m.pageLongOutput()
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/llm"
+)
+
+// hasSelectedPlanNode reports whether the results show an EXPLAIN plan with a selected node
+func (m model) hasSelectedPlanNode() bool {
+	_, _, ok := m.content.SelectedPlanNode()
+	return ok
+}
+
+// explainPlanNode asks the LLM why the selected plan node is slow and how to improve it
+func (m model) explainPlanNode() (tea.Model, tea.Cmd) {
+	if err := m.requireLLM(); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	node, plan, ok := m.content.SelectedPlanNode()
+	if !ok {
+		return m, m.errorNotification(fmt.Errorf("select a node of an EXPLAIN plan first"))
+	}
+
+	prompt := planNodePrompt(m.lastQuery, node, plan)
+	m.loading = true
+
+	return m, tea.Batch(
+		func() tea.Msg {
+			response, err := m.llm.Ask(prompt, llm.Explain)
+			if err != nil {
+				return llmFailureMsg{err: err}
+			}
+
+			return planNodeExplanationMsg(*response)
+		},
+		m.spinner.Tick,
+	)
+}
+
+// handlePlanNodeExplanation shows the plan node analysis without touching the editor
+func (m *model) handlePlanNodeExplanation(msg planNodeExplanationMsg) {
+	m.loading = false
+	m.content.SetLLMResponse(llm.Response(msg), "")
+	m.focused = focusedContent
+	m.editor.Blur()
+	m.editor.SetNormalMode()
+}
+
+func planNodePrompt(query, node string, plan []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("-- explain\n")
+	sb.WriteString("Explain why this node of the query plan may be slow and how to improve it ")
+	sb.WriteString("(indexes, query rewrites or configuration). Focus on the selected node.\n\n")
+
+	if query = strings.TrimSpace(query); query != "" {
+		fmt.Fprintf(&sb, "Query:\n```sql\n%s\n```\n\n", query)
+	}
+
+	fmt.Fprintf(&sb, "Selected plan node:\n```\n%s\n```\n\n", node)
+	fmt.Fprintf(&sb, "Full plan:\n```\n%s\n```", strings.Join(plan, "\n"))
+
+	return sb.String()
+}
@@ -1,18 +1,32 @@
 package prompt
 
 import (
+	"errors"
 	"path/filepath"
+	"strings"
 
-	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/lipgloss/v2"
 	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/llm"
 	"github.com/ionut-t/perp/pkg/utils"
 	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/ui/form"
 )
 
 type CancelMsg struct{}
 
+// formID is the ID of the form of the prompt
+const formID = "prompt"
+
+// the keys of the form values
+const (
+	valueKey   = "value"
+	confirmKey = "confirm"
+)
+
+// answers of the yes/no prompts, No being selected first
+var answers = []string{"Yes", "No"}
+
 type Action int
 
 const (
@@ -22,6 +36,18 @@ const (
 	ExportAllAsCSVAction
 	ChangeLeaderKeyAction
 	SaveSnippetAction
+	LLMTemperatureAction
+	LLMMaxTokensAction
+	LLMTimeoutAction
+	SaveWorkspaceAction
+	GexecAction
+	PasswordAction
+	UpdateServerPasswordAction
+	FindDuplicatesAction
+	FindOrphansAction
+	ValidateConstraintAction
+	RunEditedFunctionAction
+	GrepAction
 )
 
 func (a Action) prompt() string {
@@ -36,6 +62,30 @@ func (a Action) prompt() string {
 		return "Leader key"
 	case SaveSnippetAction:
 		return "Snippet name"
+	case LLMTemperatureAction:
+		return "Temperature"
+	case LLMMaxTokensAction:
+		return "Max tokens"
+	case LLMTimeoutAction:
+		return "Timeout"
+	case SaveWorkspaceAction:
+		return "Workspace name"
+	case GexecAction:
+		return "Run them?"
+	case PasswordAction:
+		return "New password"
+	case UpdateServerPasswordAction:
+		return "Update the saved server?"
+	case FindDuplicatesAction:
+		return "Table and columns"
+	case FindOrphansAction:
+		return "Table"
+	case ValidateConstraintAction:
+		return "Validate it?"
+	case RunEditedFunctionAction:
+		return "Run it?"
+	case GrepAction:
+		return "Text"
 	default:
 		return "unknown"
 	}
@@ -55,42 +105,128 @@ func (a Action) title() string {
 		return "Change leader key"
 	case SaveSnippetAction:
 		return "Save current query as snippet"
+	case LLMTemperatureAction:
+		return "Change LLM temperature"
+	case LLMMaxTokensAction:
+		return "Change LLM max tokens"
+	case LLMTimeoutAction:
+		return "Change LLM request timeout"
+	case SaveWorkspaceAction:
+		return "Save workspace"
+	case GexecAction:
+		return "Execute the statements generated by \\gexec"
+	case PasswordAction:
+		return "Change role password"
+	case UpdateServerPasswordAction:
+		return "Password changed"
+	case FindDuplicatesAction:
+		return "Find duplicate rows, e.g. users email, name"
+	case FindOrphansAction:
+		return "Find rows whose foreign key parent is missing"
+	case ValidateConstraintAction:
+		return "Validate the NOT VALID constraint"
+	case RunEditedFunctionAction:
+		return "Function changed, the new definition is in the editor"
+	case GrepAction:
+		return "Search the exports and snippets"
 	default:
 		return "unknown"
 	}
 }
 
+// isQuestion reports whether the action is answered with yes or no
+func (a Action) isQuestion() bool {
+	switch a {
+	case GexecAction, UpdateServerPasswordAction, ValidateConstraintAction, RunEditedFunctionAction:
+		return true
+	default:
+		return false
+	}
+}
+
+// fields returns the fields of the form asking for the action
+func (a Action) fields() []form.Field {
+	switch {
+	case a == PasswordAction:
+		return []form.Field{
+			{Key: valueKey, Label: a.prompt(), Kind: form.Password, Required: true},
+			{Key: confirmKey, Label: "Confirm password", Kind: form.Password, Required: true},
+		}
+
+	case a.isQuestion():
+		return []form.Field{
+			{Key: valueKey, Label: a.prompt(), Kind: form.Select, Options: answers, Value: "No"},
+		}
+
+	default:
+		return []form.Field{
+			{Key: valueKey, Label: a.prompt(), Required: true, Validate: a.validate},
+		}
+	}
+}
+
+// validate checks the value typed for the action before it is submitted
+func (a Action) validate(value string) error {
+	var setting string
+
+	switch a {
+	case LLMTemperatureAction:
+		setting = llm.SettingTemperature
+	case LLMMaxTokensAction:
+		setting = llm.SettingMaxTokens
+	case LLMTimeoutAction:
+		setting = llm.SettingTimeout
+	default:
+		return nil
+	}
+
+	settings := llm.DefaultSettings()
+	return settings.Set(setting, value)
+}
+
 type Model struct {
-	input  textinput.Model
-	action Action
-	styles styles.Styles
+	form     form.Model
+	action   Action
+	styles   styles.Styles
+	password string // the password to save in the server
 }
 
 func New() Model {
-	input := textinput.New()
-	input.Prompt = "> "
-	input.CharLimit = 256
-	input.SetWidth(50)
-	input.Focus()
-
 	return Model{
-		input: input,
+		form: form.New(formID, ""),
 	}
 }
 
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
-	m.input.Styles().Focused.Prompt.Foreground(s.Primary.GetForeground())
-	m.input.Styles().Focused.Text.Foreground(s.Primary.GetForeground())
+	m.form.SetStyles(s)
 }
 
 func (m *Model) SetAction(action Action) {
 	m.action = action
-	m.input.Prompt = action.prompt() + ": "
+	m.password = ""
+
+	m.form = form.New(formID, action.title(), action.fields()...)
+	m.form.SetStyles(m.styles)
+
+	if action == PasswordAction {
+		m.form.SetValidate(func(values form.Values) error {
+			if values[valueKey] != values[confirmKey] {
+				return errors.New("passwords didn't match")
+			}
+			return nil
+		})
+	}
+}
+
+// SetPendingPassword keeps the changed password until the user chooses
+// whether to save it in the server
+func (m *Model) SetPendingPassword(password string) {
+	m.password = password
 }
 
 func (m *Model) SetInitialValue(value string) {
-	m.input.SetValue(value)
+	m.form.SetValue(valueKey, value)
 }
 
 func (m Model) Init() tea.Cmd {
@@ -99,47 +235,37 @@ func (m Model) Init() tea.Cmd {
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.input.SetValue("")
+	case form.CancelledMsg:
+		if msg.ID == formID {
+			m.password = ""
 			return m, utils.Dispatch(CancelMsg{})
-		case "enter":
-			value := m.input.Value()
+		}
 
-			if value == "" {
-				return m, nil
-			}
+	case form.SubmittedMsg:
+		if msg.ID == formID {
+			cmd := m.handleAction(msg.Values)
+			m.password = ""
 
-			m.input.SetValue("")
 			return m, tea.Batch(
-				m.handleAction(value),
+				cmd,
 				utils.Dispatch(CancelMsg{}),
 			)
 		}
 	}
 
 	var cmd tea.Cmd
-	m.input, cmd = m.input.Update(msg)
+	m.form, cmd = m.form.Update(msg)
 	return m, cmd
 }
 
 func (m Model) View() string {
-	border := lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(m.styles.Primary.GetForeground()).
-		Padding(1, 2)
-
-	content := lipgloss.JoinVertical(
-		lipgloss.Center,
-		m.styles.Primary.Bold(true).MarginBottom(1).Render(m.action.title()),
-		m.input.View(),
-	)
-
-	return border.Render(content)
+	return m.form.View()
 }
 
-func (m Model) handleAction(value string) tea.Cmd {
+func (m Model) handleAction(values form.Values) tea.Cmd {
+	value := values[valueKey]
+	yes := value == answers[0]
+
 	switch m.action {
 	case EditorAction:
 		return utils.Dispatch(command.EditorChangedMsg{Editor: value})
@@ -176,6 +302,50 @@ func (m Model) handleAction(value string) tea.Cmd {
 
 	case SaveSnippetAction:
 		return utils.Dispatch(command.SaveSnippetMsg{Name: value})
+
+	case LLMTemperatureAction:
+		return utils.Dispatch(command.LLMSettingChangedMsg{Name: llm.SettingTemperature, Value: value})
+
+	case LLMMaxTokensAction:
+		return utils.Dispatch(command.LLMSettingChangedMsg{Name: llm.SettingMaxTokens, Value: value})
+
+	case LLMTimeoutAction:
+		return utils.Dispatch(command.LLMSettingChangedMsg{Name: llm.SettingTimeout, Value: value})
+
+	case SaveWorkspaceAction:
+		return utils.Dispatch(command.SaveWorkspaceMsg{Name: value})
+
+	case GexecAction:
+		if yes {
+			return utils.Dispatch(command.GexecMsg{})
+		}
+
+	case PasswordAction:
+		return utils.Dispatch(command.PasswordMsg{Password: value})
+
+	case FindDuplicatesAction:
+		return utils.Dispatch(command.FindDuplicatesMsg{Input: value})
+
+	case FindOrphansAction:
+		return utils.Dispatch(command.FindOrphansMsg{Table: strings.TrimSpace(value)})
+
+	case GrepAction:
+		return utils.Dispatch(command.GrepMsg{Text: strings.TrimSpace(value)})
+
+	case ValidateConstraintAction:
+		if yes {
+			return utils.Dispatch(command.ValidateConstraintMsg{})
+		}
+
+	case RunEditedFunctionAction:
+		if yes {
+			return utils.Dispatch(command.RunEditedFunctionMsg{})
+		}
+
+	case UpdateServerPasswordAction:
+		if yes {
+			return utils.Dispatch(command.ServerPasswordMsg{Password: m.password})
+		}
 	}
 
 	return nil
@@ -0,0 +1,115 @@
+package prompt
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/ui/form"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	return m
+}
+
+// submit presses enter and runs the form submission through the prompt,
+// returning the messages it dispatches
+func submit(t *testing.T, m Model) (Model, []tea.Msg) {
+	t.Helper()
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		return m, nil
+	}
+
+	submitted, ok := cmd().(form.SubmittedMsg)
+	require.True(t, ok, "the form is submitted")
+
+	m, cmd = m.Update(submitted)
+	require.NotNil(t, cmd)
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		return m, []tea.Msg{cmd()}
+	}
+
+	var msgs []tea.Msg
+	for _, c := range batch {
+		if c != nil {
+			msgs = append(msgs, c())
+		}
+	}
+
+	return m, msgs
+}
+
+func TestPasswordIsConfirmed(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.SetAction(PasswordAction)
+
+	m = typeText(m, "secret")
+	m, msgs := submit(t, m)
+	assert.Empty(t, msgs, "enter moves to the confirmation")
+
+	m = typeText(m, "secrets")
+	m, msgs = submit(t, m)
+	assert.Empty(t, msgs, "different passwords are not submitted")
+	assert.Contains(t, m.View(), "passwords didn't match")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	_, msgs = submit(t, m)
+	assert.Equal(t, []tea.Msg{command.PasswordMsg{Password: "secret"}, CancelMsg{}}, msgs)
+}
+
+func TestQuestionIsAnsweredWithNoByDefault(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.SetAction(GexecAction)
+
+	_, msgs := submit(t, m)
+	assert.Equal(t, []tea.Msg{CancelMsg{}}, msgs)
+
+	m.SetAction(GexecAction)
+	m = typeText(m, "y")
+
+	_, msgs = submit(t, m)
+	assert.Equal(t, []tea.Msg{command.GexecMsg{}, CancelMsg{}}, msgs)
+}
+
+func TestInvalidSettingIsNotSubmitted(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.SetAction(LLMTemperatureAction)
+
+	m = typeText(m, "3")
+	m, msgs := submit(t, m)
+	assert.Empty(t, msgs)
+	assert.Contains(t, m.View(), "expected a number between 0 and 2")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	m = typeText(m, "0.5")
+
+	_, msgs = submit(t, m)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "0.5", msgs[0].(command.LLMSettingChangedMsg).Value)
+}
+
+func TestRequiredValue(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.SetAction(SaveSnippetAction)
+
+	m, msgs := submit(t, m)
+	assert.Empty(t, msgs)
+	assert.Contains(t, m.View(), "snippet name is required")
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/config"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// displayOptionsFromConfig returns the display options set in the config file.
+// Invalid options keep their defaults and are reported with the error.
+func displayOptionsFromConfig(cfg config.Config) (psql.DisplayOptions, error) {
+	options, err := psql.LoadDisplayOptions(cfg.GetPsetOption)
+	if err != nil {
+		return options, fmt.Errorf("display options: %w", err)
+	}
+
+	return options, nil
+}
+
+// setDisplayOption handles \pset. Without an option it shows the current
+// options, otherwise it changes the option and stores it in the config file.
+func (m model) setDisplayOption(msg psetMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.option == "" {
+		m.content.SetPsqlResult(psql.PSQL_Pset, m.displayOptions.Result())
+		return m, m.resetEditor()
+	}
+
+	options := m.displayOptions
+	value, err := options.Set(msg.option, msg.value)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	if err := m.config.SetPsetOption(msg.option, value); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.displayOptions = options
+	m.expandedDisplay = options.Expanded
+	m.content.SetDisplayOptions(options)
+
+	return m, tea.Batch(
+		m.resetEditor(),
+		m.successNotification(options.Describe(msg.option)),
+	)
+}
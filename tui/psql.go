@@ -1,8 +1,8 @@
 package tui
 
 import (
-	"context"
 	"fmt"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/ionut-t/perp/pkg/psql"
@@ -22,9 +22,48 @@ func (m model) executePsqlCommand(input string) tea.Cmd {
 
 		switch cmd.Type {
 		case psql.CmdToggleExpanded:
+			if len(cmd.Arguments) > 0 {
+				// \x on|off|auto is the same as \pset expanded
+				return psetMsg{option: psql.PsetExpanded, value: cmd.Arguments[0]}
+			}
 			return toggleExpandedMsg{}
 		case psql.CmdToggleTiming:
 			return toggleTimingMsg{}
+		case psql.CmdEcho, psql.CmdQecho:
+			return echoMsg{text: psql.EchoText(cmd), query: cmd.Type == psql.CmdQecho}
+		case psql.CmdOutput:
+			return outputMsg{path: strings.Join(cmd.Arguments, " ")}
+		case psql.CmdPset:
+			option, value := psql.ParsePset(cmd)
+			return psetMsg{option: option, value: value}
+		case psql.CmdSet:
+			name, value, err := psql.ParseSet(cmd)
+			if err != nil {
+				return psqlErrorMsg{err: err}
+			}
+			return variableMsg{name: name, value: value}
+		case psql.CmdExecuteFile:
+			return m.includeFile(cmd)
+		case psql.CmdUnset:
+			name, err := psql.ParseUnset(cmd)
+			if err != nil {
+				return psqlErrorMsg{err: err}
+			}
+			return variableMsg{name: name, unset: true}
+		case psql.CmdSample:
+			return m.sampleTable(cmd)
+		case psql.CmdGx, psql.CmdGdesc:
+			if m.lastQuery == "" {
+				return psqlErrorMsg{err: errNoLastQuery}
+			}
+			if cmd.Type == psql.CmdGx {
+				return m.executeExpanded(m.lastQuery)()
+			}
+			return m.describeQuery(m.lastQuery)()
+		case psql.CmdEditFunction:
+			return m.loadFunction(cmd)
+		case psql.CmdPassword:
+			return passwordMsg{role: psql.PasswordRole(cmd)}
 		case psql.CmdHelp:
 			return showPsqlHelpMsg{}
 		case psql.CmdQuit:
@@ -43,7 +82,7 @@ func (m model) executePsqlCommand(input string) tea.Cmd {
 // runPsqlCommand executes a psql command against the database
 func (m model) runPsqlCommand(cmd *psql.Command) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		ctx, cancel := m.queryContext()
 		defer cancel()
 
 		executor := psql.New(m.db)
@@ -52,11 +91,30 @@ func (m model) runPsqlCommand(cmd *psql.Command) tea.Cmd {
 			return psqlErrorMsg{err: err}
 		}
 
-		return psqlResultMsg{result: result}
+		return psqlResultMsg{command: cmd.Raw, commandType: cmd.Type, result: result}
 	}
 }
 
 // connectToDatabase handles the \c command
+// sampleTable runs the query generated by \sample. Its results show the
+// generated query above the table, ready to be yanked and reused.
+func (m model) sampleTable(cmd *psql.Command) tea.Msg {
+	sample, err := psql.ParseSample(cmd)
+	if err != nil {
+		return psqlErrorMsg{err: err}
+	}
+
+	ctx, cancel := m.queryContext()
+	defer cancel()
+
+	query, err := psql.SampleSQL(ctx, m.db, sample)
+	if err != nil {
+		return psqlErrorMsg{err: err}
+	}
+
+	return m.queryResultMsg(ctx, query)
+}
+
 func (m model) connectToDatabase(database string) tea.Msg {
 	if m.server.Database == database {
 		return notificationErrorMsg{err: fmt.Errorf("already connected to '%s' database", database)}
@@ -69,31 +127,38 @@ func (m model) connectToDatabase(database string) tea.Msg {
 
 func (m model) handlePsqlResult(msg psqlResultMsg) (tea.Model, tea.Cmd) {
 	resetCmd := m.resetEditor()
-	m.finishQueryExecution()
+	closeCmd := m.finishQueryExecution()
 
 	var timingCmd tea.Cmd
 	if m.server.TimingEnabled {
 		timingCmd = m.successNotification(fmt.Sprintf("Execution time: %s", utils.Duration(msg.result.ExecutionTime)))
 	}
 
-	m.content.SetPsqlResult(msg.result)
+	if msg.commandType == psql.CmdConnInfo {
+		m.content.SetSessionInfo(msg.result)
+	} else {
+		m.content.SetPsqlResult(msg.command, msg.result)
+	}
 
 	return m, tea.Batch(
 		resetCmd,
+		closeCmd,
 		timingCmd,
 	)
 }
 
 func (m model) toggleExpandedDisplay() (tea.Model, tea.Cmd) {
 	m.loading = false
-	m.expandedDisplay = !m.expandedDisplay
-	m.content.SetExpandedDisplay(m.expandedDisplay)
+	// toggling never fails; from auto it turns the expanded display on
+	_, _ = m.displayOptions.Set(psql.PsetExpanded, "")
+	m.expandedDisplay = m.displayOptions.Expanded
+	m.content.SetDisplayOptions(m.displayOptions)
 
 	resetCmd := m.resetEditor()
 
 	return m, tea.Batch(
 		resetCmd,
-		m.successNotification(fmt.Sprintf("Expanded display is %s", toggleStatus(m.expandedDisplay))),
+		m.successNotification(m.displayOptions.Describe(psql.PsetExpanded)),
 	)
 }
 
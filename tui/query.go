@@ -2,13 +2,19 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/debug"
+	"github.com/ionut-t/perp/pkg/clipboard"
 	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/history"
 	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/psql"
 	"github.com/ionut-t/perp/pkg/utils"
 	"github.com/ionut-t/perp/tui/content"
 )
@@ -29,32 +35,134 @@ func (m model) sendQueryCmd() tea.Cmd {
 		return cmd
 	}
 
+	// Try queries generating statements with \gexec
+	if query, ok := psql.CutGexec(prompt); ok {
+		return m.generateStatements(psql.Interpolate(query, m.variables))
+	}
+
+	// Try queries ending with \gx or \gdesc
+	if query, ok := psql.CutGx(prompt); ok {
+		return m.executeExpanded(psql.Interpolate(query, m.variables))
+	}
+
+	if query, ok := psql.CutGdesc(prompt); ok {
+		return m.describeQuery(psql.Interpolate(query, m.variables))
+	}
+
+	// Try statements chained with \gset, which interpolate the variables
+	// of each statement once the previous ones are stored
+	if steps, ok := psql.SplitGset(prompt); ok {
+		return m.executeChain(steps)
+	}
+
+	// Replace the references to the variables set with \set
+	prompt = psql.Interpolate(prompt, m.variables)
+
 	// Try psql commands
 	if strings.HasPrefix(prompt, "\\") {
 		return m.executePsqlCommand(prompt)
 	}
 
+	// Buffers holding only comments have nothing to send to the server
+	if db.IsEmptyQuery(prompt) {
+		return utils.Dispatch(notificationErrorMsg{err: errors.New("nothing to execute: the buffer only has comments")})
+	}
+
+	// Destructive statements run once confirmed
+	if statements := psql.DestructiveStatements(prompt); len(statements) > 0 {
+		return utils.Dispatch(destructiveQueryMsg{query: prompt, statements: statements})
+	}
+
 	// Default to SQL query execution
-	return m.executeQuery(prompt)
+	return m.executeBuffer(prompt)
+}
+
+// executeBuffer runs the SQL of the editor: several statements one after
+// another with a result each, or a single one
+func (m model) executeBuffer(query string) tea.Cmd {
+	statements := psql.SplitStatements(query)
+
+	isMetaCommand := func(statement string) bool { return strings.HasPrefix(statement, "\\") }
+	if len(statements) < 2 || slices.ContainsFunc(statements, isMetaCommand) {
+		if batch := m.variables.FetchCount(); batch > 0 && len(statements) == 1 && db.IsStreamable(query) {
+			return m.streamQuery(query, batch)
+		}
+		return m.executeQuery(query)
+	}
+
+	return m.executeStatements(statements)
+}
+
+// executeStatements runs the statements in order on one connection, so a
+// transaction they start spans the next ones, and returns the result of each,
+// stopping at the first failure
+func (m model) executeStatements(statements []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		batch, err := m.db.Batch(ctx)
+		if err != nil {
+			return queryFailureMsg{err: err}
+		}
+
+		var results []content.ParsedQueryResult
+		for i, statement := range statements {
+			result, err := runQuery(ctx, batch, statement)
+			if err != nil {
+				err = fmt.Errorf("statement %d of %d failed: %w", i+1, len(statements), err)
+				if batch.Close() {
+					err = fmt.Errorf("%w (the transaction was rolled back)", err)
+				}
+				if len(results) == 0 {
+					return queryFailureMsg{err: err}
+				}
+				return executeStatementsMsg{results: results, err: err}
+			}
+
+			results = append(results, result)
+		}
+
+		return executeStatementsMsg{results: results, rolledBack: batch.Close()}
+	}
 }
 
 // tryLLMCommands checks if the prompt is an LLM command and returns the appropriate command
 func (m model) tryLLMCommands(prompt string) tea.Cmd {
 	if llm.IsAskCommand(prompt) {
 		m.focused = focusedContent
+		if m.shouldSelectRelevantTables() {
+			return m.selectRelevantTables(prompt)
+		}
 		return m.ask(prompt, llm.Ask)
 	}
 
 	if llm.IsExplainCommand(prompt) {
 		m.focused = focusedContent
+		if m.requireLLM() != nil {
+			return m.analyseOffline(prompt, llm.Explain)
+		}
 		return m.ask(prompt, llm.Explain)
 	}
 
 	if llm.IsOptimiseCommand(prompt) {
 		m.focused = focusedContent
+		if m.requireLLM() != nil {
+			return m.analyseOffline(prompt, llm.Optimise)
+		}
 		return m.ask(prompt, llm.Optimise)
 	}
 
+	if llm.IsFilterCommand(prompt) {
+		if m.lastQuery == "" {
+			return utils.Dispatch(notificationErrorMsg{err: fmt.Errorf("run a query before filtering its results")})
+		}
+
+		m.focused = focusedContent
+		description := strings.TrimSpace(prompt[len("/filter"):])
+		return m.ask(llm.FilterPrompt(m.lastQuery, m.lastQueryColumns, description), llm.Filter)
+	}
+
 	if llm.IsFixCommand(prompt) {
 		m.focused = focusedContent
 		if error := m.content.GetError(); error != nil {
@@ -129,43 +237,123 @@ func (m model) handleRemoveTables() tea.Cmd {
 
 func (m model) executeQuery(query string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		ctx, cancel := m.queryContext()
 		defer cancel()
 
-		result, err := m.db.Query(ctx, query)
-		if err != nil {
-			return queryFailureMsg{err: err}
-		}
+		return m.queryResultMsg(ctx, query)
+	}
+}
 
-		var queryResult content.ParsedQueryResult
+// queryResultMsg runs the query and returns its results or failure
+func (m model) queryResultMsg(ctx context.Context, query string) tea.Msg {
+	queryResult, err := runQuery(ctx, m.db, query)
+	if err != nil {
+		return queryFailureMsg{err: err}
+	}
 
-		rows, columns, err := db.ExtractResults(result.Rows())
-		if err != nil {
-			return queryFailureMsg{err: err}
+	return executeQueryMsg(queryResult)
+}
+
+// runQuery runs the query on querier and reads all of its results
+func runQuery(ctx context.Context, querier db.Querier, query string) (content.ParsedQueryResult, error) {
+	var queryResult content.ParsedQueryResult
+
+	result, err := querier.Query(ctx, query)
+	if err != nil {
+		return queryResult, err
+	}
+
+	rows, columns, err := db.ExtractResults(result.Rows())
+	if err != nil {
+		return queryResult, err
+	}
+
+	queryResult.IsDDL = result.IsDDL()
+	queryResult.Query = result.Query()
+	result.Rows().Close()
+	queryResult.AffectedRows = result.Rows().CommandTag().RowsAffected()
+	queryResult.Columns = columns
+	queryResult.Rows = rows
+	queryResult.ExecutionTime = result.ExecutionTime()
+
+	return queryResult, nil
+}
+
+// handleStatementsResult shows the result of each statement of a buffer,
+// the last one first, and reports the failure that stopped them
+func (m model) handleStatementsResult(msg executeStatementsMsg) (tea.Model, tea.Cmd) {
+	resetCmd := m.resetEditor()
+	closeCmd := m.finishQueryExecution()
+
+	if err := m.content.SetQueryResultPages(msg.results); err != nil {
+		return m, closeCmd
+	}
+
+	isDDL := false
+	for _, result := range msg.results {
+		isDDL = isDDL || result.IsDDL
+
+		if !result.IsDDL && len(result.Columns) > 0 {
+			m.lastQuery = result.Query
+			m.lastQueryColumns = result.Columns
 		}
+	}
 
-		queryResult.IsDDL = result.IsDDL()
-		queryResult.Query = result.Query()
-		result.Rows().Close()
-		queryResult.AffectedRows = result.Rows().CommandTag().RowsAffected()
-		queryResult.Columns = columns
-		queryResult.Rows = rows
-		queryResult.ExecutionTime = result.ExecutionTime()
+	notificationCmd := m.successNotification(fmt.Sprintf("Executed %d statements. Press [ and ] to page through their results", len(msg.results)))
+	switch {
+	case m.running.cancelled(msg.err):
+		notificationCmd = m.warningNotification("Query cancelled")
+	case msg.err != nil:
+		notificationCmd = tea.Batch(m.errorNotification(msg.err), m.reconnectIfLost(msg.err))
+	case msg.rolledBack:
+		notificationCmd = m.warningNotification(fmt.Sprintf("Executed %d statements. The transaction they left open was rolled back", len(msg.results)))
+	}
 
-		return executeQueryMsg(queryResult)
+	if err := m.writeOutput(); err != nil {
+		notificationCmd = m.errorNotification(err)
 	}
+
+	var schemaCmd tea.Cmd
+	if isDDL {
+		schemaCmd = m.generateSchema()
+	}
+
+	return m, tea.Batch(
+		resetCmd,
+		closeCmd,
+		notificationCmd,
+		schemaCmd,
+	)
 }
 
 func (m model) handleQueryResult(msg executeQueryMsg) (tea.Model, tea.Cmd) {
 	resetCmd := m.resetEditor()
-	m.finishQueryExecution()
+	closeCmd := m.finishQueryExecution()
 
 	err := m.content.SetQueryResults(content.ParsedQueryResult(msg))
 	if err != nil {
-		return m, nil
+		return m, closeCmd
+	}
+
+	m.restoreColumnWidths(msg.Query)
+
+	m.trackLLMExampleCandidate(msg.Query)
+
+	if !msg.IsDDL && len(msg.Columns) > 0 {
+		m.lastQuery = msg.Query
+		m.lastQueryColumns = msg.Columns
 	}
 
 	message := m.formatQuerySuccessMessage(msg.AffectedRows, msg.ExecutionTime)
+	notificationCmd := m.successNotification(message)
+	if err := m.writeOutput(); err != nil {
+		notificationCmd = m.errorNotification(err)
+	}
+
+	var timingCmd tea.Cmd
+	if m.server.TimingEnabled {
+		timingCmd = m.recordQueryTiming(msg.Query, msg.ExecutionTime, message)
+	}
 
 	var schemaCmd tea.Cmd
 	if msg.IsDDL {
@@ -174,12 +362,33 @@ func (m model) handleQueryResult(msg executeQueryMsg) (tea.Model, tea.Cmd) {
 
 	return m, tea.Batch(
 		resetCmd,
-		m.successNotification(message),
+		closeCmd,
+		notificationCmd,
+		timingCmd,
 		schemaCmd,
 	)
 }
 
-// formatQuerySuccessMessage creates a success message for query execution
+// yankQuery copies the query shown above the results to the clipboard
+func (m *model) yankQuery(query string) tea.Cmd {
+	if err := clipboard.Write(query); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification("Query copied to clipboard")
+}
+
+// yankCodeBlock copies a code block of an LLM response to the clipboard
+func (m *model) yankCodeBlock(code string) tea.Cmd {
+	if err := clipboard.Write(code); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification("Code block copied to clipboard")
+}
+
+// formatQuerySuccessMessage creates a success message for query execution,
+// with the execution time when timing is enabled
 func (m *model) formatQuerySuccessMessage(affectedRows int64, executionTime time.Duration) string {
 	message := fmt.Sprintf("Query executed successfully. Affected rows: %d", affectedRows)
 	if m.server.TimingEnabled {
@@ -187,3 +396,33 @@ func (m *model) formatQuerySuccessMessage(affectedRows int64, executionTime time
 	}
 	return message
 }
+
+// recordQueryTiming stores the execution time of the query in the history and
+// returns the average of its previous timed runs, to be added to message, the
+// notification of the results
+func (m model) recordQueryTiming(query string, executionTime time.Duration, message string) tea.Cmd {
+	storage := m.config.Storage()
+
+	return func() tea.Msg {
+		average, runs, averageErr := history.AverageDuration(storage, query, timingWindow)
+		if averageErr != nil {
+			debug.Printf("failed to read query timings: %v", averageErr)
+		}
+
+		if recordErr := history.RecordDuration(storage, query, executionTime); recordErr != nil {
+			debug.Printf("failed to record query timing: %v", recordErr)
+		}
+
+		return queryTimingMsg{message: message, average: average, ok: averageErr == nil && runs > 0}
+	}
+}
+
+// showQueryTiming adds the average of the previous runs of a query to the
+// notification of its results, unless another notification replaced it
+func (m model) showQueryTiming(msg queryTimingMsg) (tea.Model, tea.Cmd) {
+	if !msg.ok || m.notification != m.styles.Success.Render(msg.message) {
+		return m, nil
+	}
+
+	return m, m.successNotification(fmt.Sprintf("%s (usually ~%s)", msg.message, utils.Duration(msg.average)))
+}
@@ -0,0 +1,21 @@
+package tui
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/tui/querybuilder"
+)
+
+// openQueryBuilder shows the form writing a SELECT from the tables of the
+// schema index
+func (m model) openQueryBuilder() (tea.Model, tea.Cmd) {
+	if m.schemaIndex == nil || m.schemaIndex.Size() == 0 {
+		return m, m.errorNotification(errors.New("no tables to build a query on, the schema may still be loading"))
+	}
+
+	m.queryBuilder = querybuilder.New(m.schemaIndex, m.styles)
+	m.isQueryBuilderActive = true
+
+	return m, m.queryBuilder.Init()
+}
@@ -0,0 +1,189 @@
+// Package querybuilder is a guided form writing a SELECT statement: a table,
+// its columns, filters, then the order and limit of the rows.
+package querybuilder
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/querybuilder"
+	"github.com/ionut-t/perp/pkg/schemaindex"
+	"github.com/ionut-t/perp/pkg/utils"
+)
+
+// BuiltMsg carries the statement written from the choices made in the form
+type BuiltMsg struct {
+	Query string
+}
+
+type CancelMsg struct{}
+
+const (
+	width        = 60
+	defaultLimit = "100"
+)
+
+// values are bound to the fields of the form, and shared by the copies of the
+// model
+type values struct {
+	table   string
+	columns []string
+	filters string
+	orderBy string
+	desc    bool
+	limit   string
+}
+
+type Model struct {
+	form   *huh.Form
+	values *values
+	styles styles.Styles
+}
+
+// New returns the form for the tables of index
+func New(index *schemaindex.Index, s styles.Styles) Model {
+	v := &values{limit: defaultLimit}
+
+	tables := index.Tables()
+	if len(tables) > 0 {
+		v.table = tables[0]
+	}
+
+	columnOptions := func() []huh.Option[string] {
+		columns := index.Columns(v.table)
+		options := make([]huh.Option[string], len(columns))
+		for i, column := range columns {
+			options[i] = huh.NewOption(column.Name+"  "+column.Type, column.Name)
+		}
+		return options
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Table").
+				Options(huh.NewOptions(tables...)...).
+				Height(12).
+				Value(&v.table),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Columns").
+				Description("All the columns when none is selected").
+				OptionsFunc(columnOptions, &v.table).
+				Height(12).
+				Value(&v.columns),
+		),
+		huh.NewGroup(
+			huh.NewText().
+				Title("Filters (optional)").
+				Description("One per line, e.g. status = active, created_at >= 2024-01-01 or deleted_at is null").
+				Lines(5).
+				Validate(func(text string) error {
+					_, err := querybuilder.ParseFilters(text)
+					return err
+				}).
+				Value(&v.filters),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Order by").
+				OptionsFunc(func() []huh.Option[string] {
+					return append([]huh.Option[string]{huh.NewOption("(none)", "")}, columnOptions()...)
+				}, &v.table).
+				Value(&v.orderBy),
+			huh.NewConfirm().
+				Title("Direction").
+				Affirmative("Descending").
+				Negative("Ascending").
+				Value(&v.desc),
+			huh.NewInput().
+				Title("Limit").
+				Description("Leave empty for all the rows").
+				Validate(validateLimit).
+				Value(&v.limit),
+		),
+	).WithWidth(width)
+
+	form.WithTheme(styles.HuhThemeCatppuccin{Styles: s})
+
+	return Model{form: form, values: v, styles: s}
+}
+
+func validateLimit(limit string) error {
+	if strings.TrimSpace(limit) == "" {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(limit)); err != nil || n <= 0 {
+		return errors.New("the limit must be a positive number")
+	}
+
+	return nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && !m.filtering() {
+		return m, utils.Dispatch(CancelMsg{})
+	}
+
+	form, cmd := m.form.Update(msg)
+	m.form = form.(*huh.Form)
+
+	if m.form.State == huh.StateCompleted {
+		query, err := m.query().SQL()
+		if err != nil {
+			return m, utils.Dispatch(CancelMsg{})
+		}
+
+		return m, tea.Batch(utils.Dispatch(CancelMsg{}), utils.Dispatch(BuiltMsg{Query: query}))
+	}
+
+	return m, cmd
+}
+
+// filtering reports whether the focused list is being filtered, which esc
+// stops instead of closing the form
+func (m Model) filtering() bool {
+	field, ok := m.form.GetFocusedField().(interface{ GetFiltering() bool })
+	return ok && field.GetFiltering()
+}
+
+// query returns the choices made in the form, validated by its fields
+func (m Model) query() querybuilder.Query {
+	filters, _ := querybuilder.ParseFilters(m.values.filters)
+	limit, _ := strconv.Atoi(strings.TrimSpace(m.values.limit))
+
+	return querybuilder.Query{
+		Table:      m.values.table,
+		Columns:    m.values.columns,
+		Filters:    filters,
+		OrderBy:    m.values.orderBy,
+		Descending: m.values.desc,
+		Limit:      limit,
+	}
+}
+
+func (m Model) View() string {
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.Primary.GetForeground()).
+		Padding(1, 2)
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.styles.Primary.Bold(true).MarginBottom(1).Render("Build a query"),
+		m.form.View(),
+	)
+
+	return border.Render(content)
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+)
+
+// reconnectMsg attempts to reconnect to the server after the connection was
+// lost
+type reconnectMsg struct {
+	db      db.Database
+	attempt int // counted from 1
+}
+
+// reconnectedMsg reports an attempt to reconnect
+type reconnectedMsg struct {
+	db      db.Database
+	attempt int
+	err     error
+}
+
+// reconnectIfLost starts reconnecting to the server when err means the
+// connection was lost. The attempts are spaced by db.DefaultBackoff and the
+// session keeps working in the meantime: the editor, the results and the
+// other views don't need the server.
+func (m *model) reconnectIfLost(err error) tea.Cmd {
+	if m.db == nil || m.reconnecting || !db.IsConnectionLost(err) {
+		return nil
+	}
+
+	m.reconnecting = true
+
+	return tea.Batch(
+		m.warningNotification(fmt.Sprintf("Lost the connection to %s, reconnecting", m.server.Name)),
+		scheduleReconnect(m.db, 1),
+	)
+}
+
+func scheduleReconnect(database db.Database, attempt int) tea.Cmd {
+	return tea.Tick(db.DefaultBackoff.Delay(attempt), func(time.Time) tea.Msg {
+		return reconnectMsg{db: database, attempt: attempt}
+	})
+}
+
+func (m model) reconnect(msg reconnectMsg) (tea.Model, tea.Cmd) {
+	// the user connected to another server in the meantime
+	if msg.db != m.db {
+		return m, nil
+	}
+
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		return reconnectedMsg{db: msg.db, attempt: msg.attempt, err: msg.db.Reconnect(ctx)}
+	}
+}
+
+func (m model) handleReconnected(msg reconnectedMsg) (tea.Model, tea.Cmd) {
+	if msg.db != m.db {
+		return m, nil
+	}
+
+	if msg.err == nil {
+		m.reconnecting = false
+		return m, m.successNotification(fmt.Sprintf("Reconnected to %s", m.server.Name))
+	}
+
+	if msg.attempt >= db.DefaultBackoff.Attempts {
+		m.reconnecting = false
+		return m, m.errorNotification(fmt.Errorf(
+			"failed to reconnect to %s after %d attempts, run :connect %s to try again: %w",
+			m.server.Name, msg.attempt, m.server.Name, msg.err,
+		))
+	}
+
+	next := msg.attempt + 1
+
+	return m, tea.Batch(
+		m.warningNotification(fmt.Sprintf(
+			"Reconnecting to %s failed, retrying in %s (attempt %d of %d)",
+			m.server.Name, db.DefaultBackoff.Delay(next), next, db.DefaultBackoff.Attempts,
+		)),
+		scheduleReconnect(msg.db, next),
+	)
+}
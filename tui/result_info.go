@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/advisor"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/pkg/utils"
+	"github.com/ionut-t/perp/tui/content"
+)
+
+// showResultInfo fetches the planner's row estimate for the query of the
+// result, with a plain EXPLAIN so the query is not run again.
+func (m model) showResultInfo(info content.ResultInfo) tea.Cmd {
+	return func() tea.Msg {
+		msg := resultInfoMsg{info: info}
+		if info.Psql || !isReadQuery(info.Query) {
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		result, err := m.db.Query(ctx, "EXPLAIN "+info.Query)
+		if err != nil {
+			return msg
+		}
+
+		rows, _, err := db.ExtractPsqlResults(result.Rows())
+		if err != nil {
+			return msg
+		}
+
+		plan := make([]string, 0, len(rows))
+		for _, row := range rows {
+			plan = append(plan, fmt.Sprintf("%v", row["QUERY PLAN"]))
+		}
+
+		msg.estimate, msg.hasEstimate = advisor.EstimatedRows(plan)
+		return msg
+	}
+}
+
+func (m *model) handleResultInfo(msg resultInfoMsg) {
+	m.content.SetResultInfo(renderResultInfo(m.server, msg))
+}
+
+// isReadQuery reports whether the query only reads rows, so it can be explained
+func isReadQuery(query string) bool {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch strings.TrimLeft(fields[0], "(") {
+	case "select", "with", "values", "table":
+		return true
+	}
+	return false
+}
+
+// renderResultInfo renders the metadata of a result as markdown
+func renderResultInfo(s server.Server, msg resultInfoMsg) string {
+	info := msg.info
+
+	var sb strings.Builder
+	sb.WriteString("# Result info\n\n")
+
+	fmt.Fprintf(&sb, "- **Server**: %s (%s@%s:%d)\n", s.Name, s.Username, s.Address, s.Port)
+	fmt.Fprintf(&sb, "- **Database**: %s\n", s.Database)
+	fmt.Fprintf(&sb, "- **Executed at**: %s\n", info.ExecutedAt.Format(time.DateTime))
+	fmt.Fprintf(&sb, "- **Duration**: %s\n", utils.Duration(info.ExecutionTime))
+
+	rows := fmt.Sprintf("%d fetched", info.Rows)
+	if msg.hasEstimate {
+		rows += fmt.Sprintf(" of ~%d estimated", msg.estimate)
+	}
+	fmt.Fprintf(&sb, "- **Rows**: %s\n", rows)
+
+	fmt.Fprintf(&sb, "- **Limit injected**: %s\n", yesNo(info.LimitInjected))
+	fmt.Fprintf(&sb, "- **From cache**: %s\n\n", yesNo(info.Cached))
+
+	language := "sql"
+	if info.Psql {
+		language = ""
+	}
+	fmt.Fprintf(&sb, "## Query\n\n```%s\n%s\n```\n\n", language, info.Query)
+
+	if len(info.Columns) > 0 {
+		sb.WriteString("## Columns\n\n| Column | Type |\n| --- | --- |\n")
+		for _, column := range info.Columns {
+			columnType := column.Type
+			if columnType == "" {
+				columnType = "-"
+			}
+			fmt.Fprintf(&sb, "| %s | %s |\n", strings.ReplaceAll(column.Name, "|", "\\|"), columnType)
+		}
+	}
+
+	return sb.String()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/tui/content"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{query: "SELECT 1", expected: true},
+		{query: "  with t AS (SELECT 1) SELECT * FROM t", expected: true},
+		{query: "(SELECT 1) UNION (SELECT 2)", expected: true},
+		{query: "TABLE users", expected: true},
+		{query: "DELETE FROM users", expected: false},
+		{query: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, isReadQuery(tt.query))
+		})
+	}
+}
+
+func TestRenderResultInfo(t *testing.T) {
+	t.Parallel()
+
+	s := server.Server{Name: "local", Address: "localhost", Port: 5432, Username: "postgres", Database: "shop"}
+	msg := resultInfoMsg{
+		info: content.ResultInfo{
+			Query:         "SELECT id, note FROM orders LIMIT 10",
+			ExecutedAt:    time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+			ExecutionTime: 12 * time.Millisecond,
+			Rows:          10,
+			Columns:       []content.ResultColumn{{Name: "id", Type: "int8"}, {Name: "a|b"}},
+		},
+		estimate:    10,
+		hasEstimate: true,
+	}
+
+	out := renderResultInfo(s, msg)
+	assert.Contains(t, out, "- **Server**: local (postgres@localhost:5432)")
+	assert.Contains(t, out, "- **Database**: shop")
+	assert.Contains(t, out, "- **Executed at**: 2025-03-01 12:30:00")
+	assert.Contains(t, out, "- **Duration**: 12ms")
+	assert.Contains(t, out, "- **Rows**: 10 fetched of ~10 estimated")
+	assert.Contains(t, out, "- **Limit injected**: no")
+	assert.Contains(t, out, "- **From cache**: no")
+	assert.Contains(t, out, "```sql\nSELECT id, note FROM orders LIMIT 10\n```")
+	assert.Contains(t, out, "| id | int8 |")
+	assert.Contains(t, out, "| a\\|b | - |")
+
+	msg.hasEstimate = false
+	assert.Contains(t, renderResultInfo(s, msg), "- **Rows**: 10 fetched\n")
+}
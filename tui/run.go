@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"context"
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/config"
+)
+
+// Options set how the UI starts, from the command line flags
+type Options struct {
+	URL        string // connection URL to connect to
+	ServerName string // name of a saved server to connect to
+	Query      string // loaded into the editor
+	Execute    bool   // run Query once connected
+}
+
+// Run starts the UI and blocks until it exits. The terminal is restored by
+// Bubble Tea on every exit path (quit, SIGTERM, cancelled context and panics);
+// Run then closes the file of \o and releases the database connection and
+// LSP client of the final model.
+func Run(ctx context.Context, config config.Config, opts Options) error {
+	p := tea.NewProgram(New(config, opts), tea.WithContext(ctx))
+
+	final, err := p.Run()
+
+	if m, ok := final.(model); ok {
+		// the terminal is gone, a failed save has nowhere to be reported
+		_ = m.saveActiveWorkspace()
+		_ = m.output.Close()
+		m.closeDbConnection()
+	}
+
+	// A cancelled context means perp was asked to stop (e.g. by a signal)
+	if errors.Is(err, tea.ErrProgramKilled) && !errors.Is(err, tea.ErrProgramPanic) && ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+// runStartupQuery runs the query loaded from --file once the connection is
+// ready, when --execute was set, or restores the results of a workspace
+func (m model) runStartupQuery() (tea.Model, tea.Cmd) {
+	if !m.executeOnConnect {
+		return m.restoreResults()
+	}
+
+	m.executeOnConnect = false
+
+	return m.submitQuery()
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/export"
+	"github.com/ionut-t/perp/pkg/llm"
+	"github.com/ionut-t/perp/pkg/schemaindex"
+)
+
+// schemaTourTables is the number of tables, the largest first, the schema
+// tour covers
+const schemaTourTables = 25
+
+// startSchemaTour asks the LLM for an onboarding document walking through
+// the major tables of the database, from their schema and comments
+func (m model) startSchemaTour() (tea.Model, tea.Cmd) {
+	if err := m.requireLLM(); err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	if !m.server.ShareDatabaseSchemaLLM {
+		return m, m.errorNotification(errors.New("enable sharing the database schema with the LLM to take the schema tour"))
+	}
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(
+		func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+			defer cancel()
+
+			tables, err := schemaindex.MajorTables(ctx, m.db, schemaTourTables)
+			if err != nil {
+				return llmFailureMsg{err: err}
+			}
+
+			if len(tables) == 0 {
+				return llmFailureMsg{err: errors.New("no tables to walk through")}
+			}
+
+			response, err := m.llm.Ask(schemaTourPrompt(m.server.Database, tables), llm.Explain)
+			if err != nil {
+				return llmFailureMsg{err: err}
+			}
+
+			return schemaTourMsg(*response)
+		},
+		m.spinner.Tick,
+	)
+}
+
+// handleSchemaTour shows the tour in the results pane, where it scrolls like
+// other LLM responses, and saves it with the exports of the server
+func (m model) handleSchemaTour(msg schemaTourMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.content.SetLLMResponse(llm.Response(msg), "")
+	m.focused = focusedContent
+	m.editor.Blur()
+	m.editor.SetNormalMode()
+
+	storage := filepath.Join(m.config.Storage(), m.server.Name, exportDataDirectory)
+	fileName := fmt.Sprintf("schema-tour-%s-%s.md", m.server.Database, msg.Time.Format(time.DateOnly))
+
+	fileName, err := export.AsMarkdown(storage, msg.Response, fileName)
+	if err != nil {
+		return m, m.errorNotification(fmt.Errorf("failed to save the schema tour: %w", err))
+	}
+
+	return m, m.successNotification(fmt.Sprintf("Schema tour saved to %s", fileName))
+}
+
+func schemaTourPrompt(database string, tables []schemaindex.Table) string {
+	var sb strings.Builder
+
+	sb.WriteString("-- explain\n")
+	fmt.Fprintf(&sb, "Write an onboarding guide to the %s database for a new team member, in Markdown. ", database)
+	sb.WriteString("Start with a short overview of what the database is for. ")
+	sb.WriteString("Then add a section per table, in the order given, explaining what it holds, ")
+	sb.WriteString("its important columns and how it relates to the other tables. ")
+	sb.WriteString("End with the main relationships between the tables as a list. ")
+	sb.WriteString("Base everything on the schema and comments below, the largest tables first; ")
+	sb.WriteString("say when the purpose of a table or column is unclear instead of guessing. ")
+	sb.WriteString("Only include SQL to show a common join.\n\n")
+	sb.WriteString("Tables:\n\n")
+	sb.WriteString(schemaindex.Describe(tables))
+
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// materialize copies the shown query results into a temporary table
+func (m model) materialize(msg command.MaterializeMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	columns, rows, ok := m.content.ResultSet()
+	if !ok {
+		return m, m.errorNotification(errors.New("no query results to materialize"))
+	}
+
+	table := db.ScratchTable{
+		Name:    msg.Table,
+		Columns: columns,
+		Types:   make([]uint32, len(columns)),
+		Rows:    make([][]any, len(rows)),
+	}
+
+	for i, column := range columns {
+		table.Types[i] = rows[0][column].Type
+	}
+
+	for i, row := range rows {
+		values := make([]any, len(columns))
+		for j, column := range columns {
+			values[j] = row[column].Value
+		}
+		table.Rows[i] = values
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		err := m.db.Materialize(ctx, table)
+		return materializedMsg{table: table.Name, rows: len(table.Rows), err: err}
+	})
+}
+
+func (m model) handleMaterialized(msg materializedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.err != nil {
+		return m, m.errorNotification(msg.err)
+	}
+
+	return m, m.successNotification(fmt.Sprintf("Copied %d rows into the temporary table %s", msg.rows, msg.table))
+}
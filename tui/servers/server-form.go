@@ -63,6 +63,8 @@ func newServerFormModel(servers []server.Server) serverFormModel {
 		Affirmative("Yes").
 		Negative("No")
 
+	onConnect := newOnConnectInput()
+
 	// URI mode fields
 	connectionURI := huh.NewInput().
 		Title("Connection URI").
@@ -86,6 +88,7 @@ func newServerFormModel(servers []server.Server) serverFormModel {
 			name,
 			connectionURI,
 			shareDatabaseSchemaLLM,
+			onConnect,
 		).WithHideFunc(func() bool {
 			return inputMode != "uri"
 		}),
@@ -99,6 +102,7 @@ func newServerFormModel(servers []server.Server) serverFormModel {
 			password,
 			database,
 			shareDatabaseSchemaLLM,
+			onConnect,
 		).WithHideFunc(func() bool {
 			return inputMode != "form"
 		}),
@@ -161,6 +165,9 @@ func editServerFormModel(servers []server.Server, server *server.Server, s style
 		Negative("No").
 		Value(&server.ShareDatabaseSchemaLLM)
 
+	onConnect := newOnConnectInput()
+	onConnect.Value(&server.OnConnect)
+
 	name.Focus()
 
 	serverForm := huh.NewForm(
@@ -172,6 +179,7 @@ func editServerFormModel(servers []server.Server, server *server.Server, s style
 			password,
 			database,
 			shareDatabaseSchemaLLM,
+			onConnect,
 		),
 	)
 
@@ -237,6 +245,8 @@ func (m serverFormModel) Update(msg tea.Msg) (serverFormModel, tea.Cmd) {
 			}
 		}
 
+		value.OnConnect = strings.TrimSpace(m.form.GetString("onConnect"))
+
 		if m.editedServer != nil {
 			return m, utils.Dispatch(updateServerMsg{
 				server:  *m.editedServer,
@@ -254,6 +264,15 @@ func (m serverFormModel) View() string {
 	return m.styles.Primary.Render(m.form.View())
 }
 
+// newOnConnectInput returns the optional SQL run every time perp connects to the server
+func newOnConnectInput() *huh.Text {
+	return huh.NewText().
+		Title("On Connect (optional)").
+		Key("onConnect").
+		Description("SQL run after connecting, e.g. SET search_path TO app, public").
+		Lines(3)
+}
+
 func getKeymap() *huh.KeyMap {
 	keymap := huh.NewDefaultKeyMap()
 	keymap.Confirm.Accept.Unbind()
@@ -288,6 +288,9 @@ func (m *serversListModel) renderServerInfo() string {
 
 	sb.WriteString("Connection URI: " + connectionString + "\n")
 	sb.WriteString("Share Database Schema with LLM: " + schemaShared + "\n")
+	if srv.OnConnect != "" {
+		sb.WriteString("On Connect: " + strings.Join(strings.Fields(srv.OnConnect), " ") + "\n")
+	}
 	sb.WriteString("Created At: " + createdAt + "\n")
 	sb.WriteString("Updated At: " + updatedAt + "\n")
 
@@ -1,12 +1,14 @@
 package servers
 
 import (
+	"fmt"
 	"sort"
 
 	"charm.land/bubbles/v2/cursor"
 	tea "charm.land/bubbletea/v2"
 	"github.com/ionut-t/coffee/styles"
 	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/ui/confirm"
 )
 
 type SelectedServerMsg struct {
@@ -20,6 +22,9 @@ const (
 	viewForm
 )
 
+// deleteServerDialog is the ID of the dialog confirming a server deletion
+const deleteServerDialog = "delete-server"
+
 type Model struct {
 	storage       string
 	servers       []server.Server
@@ -28,6 +33,8 @@ type Model struct {
 	view          view
 	width, height int
 	styles        styles.Styles
+	confirm       confirm.Model
+	pendingDelete *server.Server // the server waiting for the deletion to be confirmed
 }
 
 func New(storage string) Model {
@@ -71,6 +78,13 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// the confirmation dialog takes the keys until it is answered
+	if _, ok := msg.(tea.KeyMsg); ok && m.confirm.IsActive() {
+		var cmd tea.Cmd
+		m.confirm, cmd = m.confirm.Update(msg)
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
@@ -87,16 +101,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, cursor.Blink
 
 	case deleteServerMsg:
-		if servers, err := server.Delete(msg.Server.ID, m.storage); err == nil {
-			m.servers = servers
-			m.serversList.setServers(m.servers)
-
-			if len(m.servers) == 0 {
-				m.serverForm = newServerFormModel(m.servers)
-				m.serverForm.setStyles(m.styles)
-				m.view = viewForm
-			}
+		m.pendingDelete = &msg.Server
+		m.confirm = confirm.NewYesNo(
+			deleteServerDialog,
+			"Delete server",
+			fmt.Sprintf("Delete %s and its saved connection details?", msg.Server.Name),
+		)
+		m.confirm.SetStyles(m.styles)
+		return m, nil
+
+	case confirm.ConfirmedMsg:
+		if msg.ID == deleteServerDialog && m.pendingDelete != nil {
+			m.deleteServer(*m.pendingDelete)
 		}
+		m.pendingDelete = nil
+		return m, nil
+
+	case confirm.CancelledMsg:
+		if msg.ID == deleteServerDialog {
+			m.pendingDelete = nil
+		}
+		return m, nil
 
 	case createServerMsg:
 		return m, m.createServer(msg.server)
@@ -134,6 +159,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m Model) View() string {
 	switch m.view {
 	case viewSelect:
+		if m.confirm.IsActive() {
+			return confirm.Overlay(m.serversList.View(), m.confirm.View(), m.width, m.height)
+		}
 		return m.serversList.View()
 	case viewForm:
 		return m.serverForm.View()
@@ -142,6 +170,24 @@ func (m Model) View() string {
 	return ""
 }
 
+// deleteServer removes the confirmed server, opening the form to add one
+// when it was the last
+func (m *Model) deleteServer(srv server.Server) {
+	servers, err := server.Delete(srv.ID, m.storage)
+	if err != nil {
+		return
+	}
+
+	m.servers = servers
+	m.serversList.setServers(m.servers)
+
+	if len(m.servers) == 0 {
+		m.serverForm = newServerFormModel(m.servers)
+		m.serverForm.setStyles(m.styles)
+		m.view = viewForm
+	}
+}
+
 func (m *Model) createServer(newServer server.CreateServer) tea.Cmd {
 	srv, err := server.New(newServer, m.storage)
 
@@ -190,5 +236,5 @@ func (m *Model) editServer(server server.Server, updatedServer server.CreateServ
 }
 
 func (m Model) CanTriggerLeaderKey() bool {
-	return m.view == viewSelect
+	return m.view == viewSelect && !m.confirm.IsActive()
 }
@@ -637,10 +637,20 @@ func TestKeyPressIntegration(t *testing.T) {
 				firstServerID, deleteMsg.Server.ID)
 		}
 
-		// Process message
+		// Process message, which asks to confirm the deletion
 		model, _ = m.Update(msg)
 		m = model
 
+		if len(m.servers) != 2 {
+			t.Fatalf("Expected no deletion before confirming, got %d servers", len(m.servers))
+		}
+
+		if !m.confirm.IsActive() {
+			t.Fatal("Expected the deletion to be confirmed first")
+		}
+
+		m = confirmDeletion(t, m, 'y')
+
 		// Should have 1 server left
 		if len(m.servers) != 1 {
 			t.Errorf("Expected 1 server after deletion, got %d", len(m.servers))
@@ -703,9 +713,10 @@ func TestKeyPressIntegration(t *testing.T) {
 			t.Error("deleteServerMsg contains wrong server ID")
 		}
 
-		// Process deletion message
+		// Process deletion message and confirm it
 		model, _ = m.Update(msg)
 		m = model
+		m = confirmDeletion(t, m, 'y')
 
 		// Should now have 0 servers
 		if len(m.servers) != 0 {
@@ -717,6 +728,58 @@ func TestKeyPressIntegration(t *testing.T) {
 			t.Errorf("Expected form view after deleting last server, got %v", m.view)
 		}
 	})
+
+	t.Run("cancelling the deletion keeps the server", func(t *testing.T) {
+		tempDir := setupTempDir(t)
+		defer removeTempDir(t, tempDir)
+
+		_, _ = server.New(server.CreateServer{
+			Name:     "Kept Server",
+			Address:  "localhost",
+			Port:     "5432",
+			Username: "user",
+			Password: "pass",
+			Database: "db",
+		}, tempDir)
+
+		m := New(tempDir)
+		m.SetSize(100, 50)
+
+		model, _ := m.Update(deleteServerMsg{Server: m.servers[0]})
+		m = model
+
+		if m.CanTriggerLeaderKey() {
+			t.Error("Expected the leader key to be disabled while confirming")
+		}
+
+		m = confirmDeletion(t, m, 'n')
+
+		if len(m.servers) != 1 {
+			t.Errorf("Expected the server to be kept, got %d servers", len(m.servers))
+		}
+
+		if m.view != viewSelect {
+			t.Errorf("Expected select view after cancelling, got %v", m.view)
+		}
+	})
+}
+
+// confirmDeletion answers the confirmation dialog with answer and processes
+// the answer
+func confirmDeletion(t *testing.T, m Model, answer rune) Model {
+	t.Helper()
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: answer, Text: string(answer)})
+	if cmd == nil {
+		t.Fatal("Expected an answer from the confirmation dialog")
+	}
+
+	if m.confirm.IsActive() {
+		t.Error("Expected the confirmation dialog to close once answered")
+	}
+
+	m, _ = m.Update(cmd())
+	return m
 }
 
 func TestUILayout(t *testing.T) {
@@ -154,6 +154,7 @@ func New(store snippets.Store, server server.Server, width, height int, s styles
 		PlaceholderTitle:    "No snippets available.",
 		PlaceholderSubtitle: "Press '<leader>ns' to save a snippet or '<leader>c' to go back.",
 		SuccessDeleteMsg:    "Snippet deleted successfully.",
+		DeleteTitle:         "Delete snippet",
 		SuccessRenameMsg:    "Snippet renamed successfully.",
 	}
 
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/perp/tui/content"
+)
+
+// togglePinnedResults pins the current results to the left of the results
+// pane, so the next query can be compared with them, or unpins them
+func (m model) togglePinnedResults() (tea.Model, tea.Cmd) {
+	if m.pinned != nil {
+		m.pinned = nil
+		if m.focused == focusedPinned {
+			m.focused = focusedContent
+		}
+		m.updateSize()
+
+		contentModel, cmd := m.content.Update(content.ResizeMsg{})
+		m.content = contentModel
+
+		return m, tea.Batch(cmd, m.successNotification("Results unpinned"))
+	}
+
+	pinned := m.content.Pinned()
+	m.pinned = &pinned
+	m.updateSize()
+
+	contentModel, cmd := m.content.Update(content.ResizeMsg{})
+	m.content = contentModel
+
+	return m, tea.Batch(cmd, m.successNotification("Results pinned, run another query to compare"))
+}
+
+// updatePinned passes a message to the pinned results. Selecting cells and
+// showing the result info act on the results pane, so they are not available
+// in the pinned one.
+func (m model) updatePinned(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, visualSelect, showResultInfo) {
+		return m, nil
+	}
+
+	pinned, cmd := m.pinned.Update(msg)
+	m.pinned = &pinned
+
+	return m, cmd
+}
+
+// setResultsSize sizes the results pane, sharing the width with the pinned
+// results when there are any
+func (m *model) setResultsSize(width, height int) {
+	if m.pinned == nil {
+		m.content.SetSize(width, height)
+		return
+	}
+
+	frame := m.styles.ActiveBorder.GetHorizontalFrameSize()
+	paneWidth := width + frame
+	pinnedWidth := paneWidth / 2
+
+	m.pinned.SetSize(pinnedWidth-frame, height)
+	*m.pinned, _ = m.pinned.Update(content.ResizeMsg{})
+
+	m.content.SetSize(paneWidth-pinnedWidth-frame, height)
+}
+
+// renderResults renders the results pane, to the right of the pinned results
+// when there are any
+func (m *model) renderResults(border lipgloss.Style, paneWidth, height int, results string) string {
+	if m.pinned == nil {
+		return border.Width(paneWidth).Height(height).Render(results)
+	}
+
+	pinnedBorder := m.styles.InactiveBorder
+	if m.focused == focusedPinned {
+		pinnedBorder = m.styles.ActiveBorder
+	}
+
+	pinnedWidth := paneWidth / 2
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		pinnedBorder.Width(pinnedWidth).Height(height).Render(m.pinned.View()),
+		border.Width(paneWidth-pinnedWidth).Height(height).Render(results),
+	)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/stats"
+)
+
+// hasDropSuggestions reports whether the index usage report with unused
+// indexes is shown
+func (m model) hasDropSuggestions() bool {
+	if _, ok := m.content.SelectedValue(stats.DropIndexColumn); !ok {
+		return false
+	}
+
+	return len(stats.DropStatements(m.content.GetQueryResults())) > 0
+}
+
+// reviewDropIndexes puts the DROP INDEX statements suggested by the index
+// usage report in the editor. They are never run without the user.
+func (m model) reviewDropIndexes() (tea.Model, tea.Cmd) {
+	if _, ok := m.content.SelectedValue(stats.DropIndexColumn); !ok {
+		return m, m.errorNotification(errors.New("open the index usage report first"))
+	}
+
+	statements := stats.DropStatements(m.content.GetQueryResults())
+	if len(statements) == 0 {
+		return m, m.successNotification("No unused indexes")
+	}
+
+	editorCmd := m.applyQueryToEditor(strings.Join(statements, "\n"))
+
+	return m, tea.Batch(
+		editorCmd,
+		m.successNotification(fmt.Sprintf("%d DROP INDEX statements added to the editor for review", len(statements))),
+	)
+}
+
+// showHealth collects the health metrics of the database. Running it again
+// refreshes them.
+func (m model) showHealth() (tea.Model, tea.Cmd) {
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		health, err := stats.GetHealth(ctx, m.db)
+		if err != nil {
+			return psqlErrorMsg{err: err}
+		}
+
+		return psqlResultMsg{
+			command: fmt.Sprintf("Database health at %s", time.Now().Format(time.TimeOnly)),
+			result:  health.Result(),
+		}
+	})
+}
+
+// loadWALStats reads the WAL and checkpoint counters of the server
+func (m model) loadWALStats() (tea.Model, tea.Cmd) {
+	if m.db == nil || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), DatabaseQueryTimeout)
+		defer cancel()
+
+		walStats, err := stats.GetWALStats(ctx, m.db)
+		return walStatsMsg{stats: walStats, err: err}
+	})
+}
+
+// showWALStats shows the counters with their change since the previous
+// refresh of the session
+func (m model) showWALStats(msg walStatsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.loading = false
+		return m, m.errorNotification(msg.err)
+	}
+
+	command := fmt.Sprintf("WAL and checkpoints at %s", msg.stats.TakenAt.Format(time.TimeOnly))
+	if m.walStats != nil {
+		command += fmt.Sprintf(", changes since %s", m.walStats.TakenAt.Format(time.TimeOnly))
+	}
+
+	result := msg.stats.Result(m.walStats)
+	m.walStats = msg.stats
+
+	return m.handlePsqlResult(psqlResultMsg{command: command, result: result})
+}
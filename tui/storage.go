@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/diskusage"
+	"github.com/ionut-t/perp/tui/command"
+	"github.com/ionut-t/perp/ui/confirm"
+)
+
+// emptyTrashDialog is the ID of the dialog confirming the trash is emptied
+const emptyTrashDialog = "empty-trash"
+
+// storageCleanedMsg reports the space freed by a cleanup of the storage
+type storageCleanedMsg struct {
+	message string
+	err     error
+}
+
+// handleStorage shows the disk usage of the storage directory per server, or
+// frees space: the exports older than the days given are moved to the trash
+// or archived, and the trash is emptied once confirmed
+func (m model) handleStorage(msg command.StorageMsg) (tea.Model, tea.Cmd) {
+	m.focusEditor()
+
+	if m.loading {
+		return m, nil
+	}
+
+	storage := m.config.Storage()
+	cutoff := time.Now().AddDate(0, 0, -msg.Days)
+
+	switch msg.Action {
+	case command.StorageTrash:
+		return m, func() tea.Msg {
+			cleanup, err := diskusage.TrashExports(storage, cutoff, time.Now())
+			return storageCleanedMsg{
+				message: describeCleanup("Moved", cleanup, msg.Days, "to the trash"),
+				err:     err,
+			}
+		}
+
+	case command.StorageArchive:
+		return m, func() tea.Msg {
+			cleanup, err := diskusage.ArchiveExports(storage, cutoff, time.Now())
+			return storageCleanedMsg{
+				message: describeCleanup("Archived", cleanup, msg.Days, "into the archives of their server"),
+				err:     err,
+			}
+		}
+
+	case command.StorageEmptyTrash:
+		m.confirm = confirm.NewYesNo(
+			emptyTrashDialog,
+			"Empty trash",
+			"The exports in the trash are deleted for good. Empty it?",
+		)
+		m.confirm.SetStyles(m.styles)
+
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		report, err := diskusage.Measure(storage)
+		if err != nil {
+			return psqlErrorMsg{err: err}
+		}
+
+		return psqlResultMsg{
+			command: fmt.Sprintf("Storage usage of %s", storage),
+			result:  report.Result(),
+		}
+	})
+}
+
+// describeCleanup describes the exports moved by a cleanup, e.g. "Moved 3
+// exports (1.2 MB) older than 30 days to the trash"
+func describeCleanup(verb string, cleanup diskusage.Cleanup, days int, destination string) string {
+	if cleanup.Files == 0 {
+		return fmt.Sprintf("No export is older than %d days", days)
+	}
+
+	exports := "exports"
+	if cleanup.Files == 1 {
+		exports = "export"
+	}
+
+	return fmt.Sprintf("%s %d %s (%s) older than %d days %s",
+		verb, cleanup.Files, exports, diskusage.FormatSize(cleanup.Size), days, destination)
+}
+
+// emptyTrash deletes the exports moved to the trash, once confirmed
+func (m model) emptyTrash() (tea.Model, tea.Cmd) {
+	storage := m.config.Storage()
+
+	return m, func() tea.Msg {
+		freed, err := diskusage.EmptyTrash(storage)
+		return storageCleanedMsg{
+			message: fmt.Sprintf("Emptied the trash, freeing %s", diskusage.FormatSize(freed)),
+			err:     err,
+		}
+	}
+}
+
+func (m model) handleStorageCleaned(msg storageCleanedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.errorNotification(msg.err)
+	}
+
+	return m, m.successNotification(msg.message)
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/pkg/utils"
+	"github.com/ionut-t/perp/tui/content"
+)
+
+// streamQuery runs the query through a cursor and returns its first batch of
+// rows. The next ones are fetched when the last row is selected, so a large
+// result is neither read whole nor held in memory at once.
+func (m model) streamQuery(query string, batch int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		start := time.Now()
+
+		stream, err := m.db.Stream(ctx, query, batch)
+		if err != nil {
+			return queryFailureMsg{err: err}
+		}
+
+		rows, err := stream.Next(ctx)
+		if err != nil {
+			return queryFailureMsg{err: err}
+		}
+
+		return streamQueryMsg{
+			stream: stream,
+			result: content.ParsedQueryResult{
+				Query:         query,
+				Columns:       stream.Columns(),
+				Rows:          rows,
+				AffectedRows:  int64(len(rows)),
+				ExecutionTime: time.Since(start),
+			},
+		}
+	}
+}
+
+// handleStreamQuery shows the first batch of rows, keeping the stream for
+// the next ones when there are more
+func (m model) handleStreamQuery(msg streamQueryMsg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.handleQueryResult(executeQueryMsg(msg.result))
+	m = updated.(model)
+
+	if msg.stream.Done() {
+		return m, cmd
+	}
+
+	m.stream = msg.stream
+	m.content.SetMoreRows(true)
+
+	message := fmt.Sprintf("Fetched the first %d rows. The next ones are fetched when the last row is selected", len(msg.result.Rows))
+
+	return m, tea.Batch(cmd, m.successNotification(message), closeIdleStream(msg.stream))
+}
+
+// fetchMoreRows fetches the next batch of rows of the shown results
+func (m model) fetchMoreRows() (tea.Model, tea.Cmd) {
+	stream := m.stream
+	if stream == nil || m.loading {
+		// asked again once the last row is selected next
+		m.content.SetMoreRows(stream != nil)
+		return m, nil
+	}
+
+	m.loading = true
+
+	fetch := func() tea.Msg {
+		ctx, cancel := m.queryContext()
+		defer cancel()
+
+		rows, err := stream.Next(ctx)
+		return streamRowsMsg{stream: stream, rows: rows, err: err}
+	}
+
+	return m, tea.Batch(fetch, m.spinner.Tick)
+}
+
+// handleStreamRows adds the next batch of rows to the shown results. Rows of
+// a stream replaced by other results since they were asked for are dropped.
+func (m model) handleStreamRows(msg streamRowsMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	if msg.stream != m.stream {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		closeCmd := m.closeStream()
+		m.content.SetMoreRows(false)
+
+		if m.running.cancelled(msg.err) {
+			return m, tea.Batch(closeCmd, m.warningNotification("Fetching rows cancelled"))
+		}
+		return m, tea.Batch(closeCmd, m.errorNotification(msg.err), m.reconnectIfLost(msg.err))
+	}
+
+	more := !msg.stream.Done()
+	m.content.AppendQueryRows(msg.rows, more)
+
+	if !more {
+		m.stream = nil
+		return m, m.successNotification(fmt.Sprintf("Fetched all %d rows", msg.stream.Fetched()))
+	}
+
+	return m, closeIdleStream(msg.stream)
+}
+
+// closeIdleStream checks the stream again after streamIdleTimeout, closing it
+// when no rows were fetched meanwhile, so results left aside don't hold a
+// connection in a transaction
+func closeIdleStream(stream *db.Stream) tea.Cmd {
+	fetched := stream.Fetched()
+
+	return tea.Tick(streamIdleTimeout, func(time.Time) tea.Msg {
+		return streamIdleMsg{stream: stream, fetched: fetched}
+	})
+}
+
+// handleStreamIdle closes the stream of the shown results when no rows were
+// fetched from it since the check was scheduled
+func (m model) handleStreamIdle(msg streamIdleMsg) (tea.Model, tea.Cmd) {
+	if msg.stream != m.stream || msg.stream.Fetched() != msg.fetched {
+		return m, nil
+	}
+
+	if m.loading {
+		// a batch may be being fetched, checked again later
+		return m, closeIdleStream(msg.stream)
+	}
+
+	closeCmd := m.closeStream()
+	m.content.SetMoreRows(false)
+
+	message := fmt.Sprintf("No rows were fetched for %s, the rest of the results were released. Run the query again to fetch them", utils.Duration(streamIdleTimeout))
+
+	return m, tea.Batch(closeCmd, m.warningNotification(message))
+}
+
+// closeStream forgets the stream of the rows left to fetch, if any, and
+// returns the command ending its transaction, which waits for the server
+func (m *model) closeStream() tea.Cmd {
+	stream := m.stream
+	if stream == nil {
+		return nil
+	}
+
+	m.stream = nil
+
+	return func() tea.Msg {
+		// the rows were shown, a failure to end the transaction has no effect on them
+		_ = stream.Close()
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleStreamIdle(t *testing.T) {
+	t.Parallel()
+
+	stream := &db.Stream{}
+
+	m := newLLMTestModel("SELECT * FROM events")
+	m.loading = false
+	m.stream = stream
+
+	updated, cmd := m.handleStreamIdle(streamIdleMsg{stream: stream, fetched: 1})
+	assert.Same(t, stream, updated.(model).stream, "rows were fetched since the check was scheduled")
+	assert.Nil(t, cmd)
+
+	updated, cmd = m.handleStreamIdle(streamIdleMsg{stream: &db.Stream{}})
+	assert.Same(t, stream, updated.(model).stream, "the check of another stream is ignored")
+	assert.Nil(t, cmd)
+
+	m.loading = true
+	updated, cmd = m.handleStreamIdle(streamIdleMsg{stream: stream})
+	assert.Same(t, stream, updated.(model).stream, "a batch may be being fetched")
+	assert.NotNil(t, cmd, "checked again later")
+
+	m.loading = false
+	updated, cmd = m.handleStreamIdle(streamIdleMsg{stream: stream})
+	assert.Nil(t, updated.(model).stream, "the idle stream is released")
+	assert.NotNil(t, cmd)
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/config"
+	"github.com/ionut-t/perp/pkg/db"
+	"github.com/ionut-t/perp/tui/command"
+)
+
+// timeDisplayFromConfig returns the time display set in the config file.
+// An invalid time zone is dropped and reported with the error.
+func timeDisplayFromConfig(cfg config.Config) (db.TimeDisplay, error) {
+	display, err := db.NewTimeDisplay(cfg.DisplayTimeZone(), cfg.TimestampFormat())
+	if err != nil {
+		return db.TimeDisplay{Layout: cfg.TimestampFormat()}, fmt.Errorf("%s: %w", config.DisplayTimeZoneKey, err)
+	}
+
+	return display, nil
+}
+
+// setTimeZone overrides the display time zone for the session. An empty zone
+// restores the one from the config file.
+func (m model) setTimeZone(msg command.TimeZoneMsg) (tea.Model, tea.Cmd) {
+	display, err := timeDisplayFromConfig(m.config)
+	if msg.Zone != "" {
+		display, err = db.NewTimeDisplay(msg.Zone, m.config.TimestampFormat())
+	}
+
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.content.SetTimeDisplay(display)
+	m.focusEditor()
+
+	return m, m.successNotification(fmt.Sprintf("Showing timestamps in the %s time zone", display.Zone()))
+}
@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	tea "charm.land/bubbletea/v2"
 	"github.com/ionut-t/perp/internal/version"
 	"github.com/ionut-t/perp/pkg/browser"
@@ -23,6 +27,46 @@ func (m model) checkForUpdates() tea.Cmd {
 	}
 }
 
+// installUpdate replaces the running binary with the latest release.
+// Failures are reported and leave the current binary in place.
+func (m model) installUpdate() tea.Cmd {
+	return func() tea.Msg {
+		updater, err := update.NewUpdater(version.Version())
+		if err != nil {
+			return notificationErrorMsg{err: err}
+		}
+
+		result, err := updater.Install(context.Background())
+		if err != nil {
+			return notificationErrorMsg{err: fmt.Errorf("update failed: %w", err)}
+		}
+
+		return updateInstalledMsg{version: result.Version}
+	}
+}
+
+// showReleaseNotes fetches the changelog of the installed version
+func (m model) showReleaseNotes() (tea.Model, tea.Cmd) {
+	if m.view == viewServers {
+		return m, m.errorNotification(errors.New("connect to a server to view the release notes"))
+	}
+
+	m.loading = true
+	return m, tea.Batch(m.fetchReleaseNotes(version.Version()), m.spinner.Tick)
+}
+
+// fetchReleaseNotes fetches the changelog of the given version for the in-app viewer
+func (m model) fetchReleaseNotes(version string) tea.Cmd {
+	return func() tea.Msg {
+		notes, err := update.FetchReleaseNotes(context.Background(), version)
+		if err != nil {
+			return notificationErrorMsg{err: err}
+		}
+
+		return releaseNotesMsg{notes: notes}
+	}
+}
+
 func (m *model) openReleaseNotes() tea.Cmd {
 	if m.latestRelease == nil {
 		return nil
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/psql"
+)
+
+// setVariable lists, sets or removes a variable of \set and \unset
+func (m model) setVariable(msg variableMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	switch {
+	case msg.name == "":
+		m.content.SetPsqlResult(psql.PSQL_Set, m.variables.Result())
+		return m, m.resetEditor()
+
+	case msg.unset:
+		delete(m.variables, msg.name)
+		return m, tea.Batch(m.resetEditor(), m.successNotification(fmt.Sprintf("Unset :%s", msg.name)))
+	}
+
+	if m.variables == nil {
+		m.variables = make(psql.Variables)
+	}
+	m.variables[msg.name] = msg.value
+
+	return m, tea.Batch(m.resetEditor(), m.successNotification(fmt.Sprintf(":%s = %s", msg.name, msg.value)))
+}
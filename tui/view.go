@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"path/filepath"
 	"strings"
 
 	"charm.land/lipgloss/v2"
@@ -80,9 +81,12 @@ func (m *model) renderMain(width, height int) string {
 
 		fullScreenContentView := lipgloss.JoinVertical(
 			lipgloss.Left,
-			contentBorder.Width(paneWidth).
-				Height(fullScreenContentHeight+m.styles.ActiveBorder.GetVerticalFrameSize()).
-				Render(m.content.View()),
+			m.renderResults(
+				contentBorder,
+				paneWidth,
+				fullScreenContentHeight+m.styles.ActiveBorder.GetVerticalFrameSize(),
+				m.content.View(),
+			),
 			commandLine,
 		)
 		return padding.Render(fullScreenContentView)
@@ -94,21 +98,23 @@ func (m *model) renderMain(width, height int) string {
 	if m.loading {
 		return padding.Render(lipgloss.JoinVertical(
 			lipgloss.Left,
-			contentBorder.Width(paneWidth).
-				Height(contentHeight+m.styles.ActiveBorder.GetVerticalFrameSize()).
-				AlignHorizontal(lipgloss.Center).
-				AlignVertical(lipgloss.Center).
-				Render(
-					m.spinner.View(),
-				),
+			m.renderResults(
+				contentBorder.AlignHorizontal(lipgloss.Center).AlignVertical(lipgloss.Center),
+				paneWidth,
+				contentHeight+m.styles.ActiveBorder.GetVerticalFrameSize(),
+				m.renderLoading(),
+			),
 			primaryView))
 	}
 
 	return padding.Render(lipgloss.JoinVertical(
 		lipgloss.Left,
-		contentBorder.Width(paneWidth).
-			Height(contentHeight+m.styles.ActiveBorder.GetVerticalFrameSize()).
-			Render(m.content.View()),
+		m.renderResults(
+			contentBorder,
+			paneWidth,
+			contentHeight+m.styles.ActiveBorder.GetVerticalFrameSize(),
+			m.content.View(),
+		),
 		primaryView))
 }
 
@@ -125,6 +131,10 @@ func (m *model) renderStatusBar(width int) string {
 
 	left := serverName + separator + database + separator + llm
 
+	if m.output.Active() {
+		left += separator + m.styles.Info.Background(bg).Render("\\o "+filepath.Base(m.output.Path))
+	}
+
 	leftInfo := m.styles.Surface0.Padding(0, 1).Render(left)
 
 	helpText := m.styles.Info.Background(bg).PaddingRight(1).Render("<leader>? Help")
@@ -165,3 +175,18 @@ func (m *model) renderLLMModel() string {
 
 	return m.styles.Accent.Render(llmModel)
 }
+
+// renderLoading shows the spinner, with how to cancel the query being run
+func (m model) renderLoading() string {
+	status := m.running.status()
+	if status == "" {
+		return m.spinner.View()
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		m.spinner.View(),
+		"",
+		m.styles.Subtext0.Render(status),
+	)
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/pkg/workspace"
+	"github.com/ionut-t/perp/tui/servers"
+)
+
+// snapshotWorkspace captures the current session as a workspace
+func (m *model) snapshotWorkspace(name string) workspace.Workspace {
+	focus := workspace.FocusEditor
+	if m.focused == focusedContent {
+		focus = workspace.FocusResults
+	}
+
+	lastQuery, lastQueryAt := m.content.ExecutedQuery()
+
+	return workspace.Workspace{
+		Name:        name,
+		Server:      m.server.Name,
+		Buffer:      m.editor.GetCurrentContent(),
+		Layout:      workspace.Layout{FullScreen: m.fullScreen, Focus: focus},
+		LastQuery:   lastQuery,
+		LastQueryAt: lastQueryAt,
+	}
+}
+
+func (m model) saveWorkspace(name string) (tea.Model, tea.Cmd) {
+	m.isPromptActive = false
+
+	workspaces, err := workspace.Save(m.config.Storage(), m.snapshotWorkspace(name))
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	m.workspaces = workspaces
+	m.workspace = name
+
+	return m, m.successNotification(fmt.Sprintf("Workspace %s saved", name))
+}
+
+// saveActiveWorkspace stores the current session in the active workspace, so
+// it is restored on the next start
+func (m *model) saveActiveWorkspace() error {
+	if m.workspace == "" || m.server.Name == "" {
+		return nil
+	}
+
+	workspaces, err := workspace.Save(m.config.Storage(), m.snapshotWorkspace(m.workspace))
+	if err != nil {
+		return err
+	}
+
+	m.workspaces = workspaces
+
+	return nil
+}
+
+// switchWorkspace saves the active workspace and restores the given one,
+// connecting to its server when it is not the current one
+func (m model) switchWorkspace(name string) (tea.Model, tea.Cmd) {
+	ws, ok := workspace.Find(m.workspaces, name)
+	if !ok {
+		return m, m.errorNotification(fmt.Errorf("workspace %q not found", name))
+	}
+
+	if m.workspace != name {
+		if err := m.saveActiveWorkspace(); err != nil {
+			return m, m.errorNotification(err)
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	m.restoreQuery = ws.LastQuery
+
+	if ws.Server != "" && ws.Server != m.server.Name {
+		srv, err := server.FindByName(m.config.Storage(), ws.Server)
+		if err != nil {
+			return m, m.errorNotification(err)
+		}
+
+		_, cmd := m.handleServerConnection(servers.SelectedServerMsg{Server: *srv})
+		cmds = append(cmds, cmd)
+	}
+
+	m.view = viewMain
+	m.editor.SetContent(ws.Buffer)
+	m.fullScreen = ws.Layout.FullScreen
+	if ws.Layout.Focus == workspace.FocusResults {
+		m.focused = focusedContent
+		m.editor.Blur()
+	} else {
+		m.focusEditor()
+	}
+	m.updateSize()
+
+	m.workspace = name
+	if err := workspace.SetActive(m.config.Storage(), name); err != nil {
+		cmds = append(cmds, m.errorNotification(err))
+	}
+
+	// a connection in progress restores the results once the schema is loaded
+	if !m.loading && m.db != nil {
+		updated, cmd := m.restoreResults()
+		m = updated.(model)
+		cmds = append(cmds, cmd)
+	}
+
+	cmds = append(cmds, m.successNotification(fmt.Sprintf("Switched to workspace %s", name)))
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) deleteWorkspace() (tea.Model, tea.Cmd) {
+	if m.workspace == "" {
+		return m, nil
+	}
+
+	workspaces, err := workspace.Delete(m.config.Storage(), m.workspace)
+	if err != nil {
+		return m, m.errorNotification(err)
+	}
+
+	deleted := m.workspace
+	m.workspaces = workspaces
+	m.workspace = ""
+
+	return m, m.successNotification(fmt.Sprintf("Workspace %s deleted", deleted))
+}
+
+// restoreResults runs the query of the last results of a restored workspace.
+// Only read queries are run again, anything else could change data.
+func (m model) restoreResults() (tea.Model, tea.Cmd) {
+	query := m.restoreQuery
+	m.restoreQuery = ""
+
+	if !isReadQuery(query) || m.loading {
+		return m, nil
+	}
+
+	m.loading = true
+
+	return m, tea.Batch(m.executeQuery(query), m.spinner.Tick)
+}
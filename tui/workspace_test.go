@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/ionut-t/perp/pkg/server"
+	"github.com/ionut-t/perp/pkg/workspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotWorkspace(t *testing.T) {
+	t.Parallel()
+
+	m := newLLMTestModel("SELECT * FROM users;")
+	m.server = server.Server{Name: "staging"}
+	m.fullScreen = true
+	m.focused = focusedContent
+
+	ws := m.snapshotWorkspace("reports")
+
+	assert.Equal(t, "reports", ws.Name)
+	assert.Equal(t, "staging", ws.Server)
+	assert.Equal(t, "SELECT * FROM users;", ws.Buffer)
+	assert.Equal(t, workspace.Layout{FullScreen: true, Focus: workspace.FocusResults}, ws.Layout)
+}
+
+func TestRestoreResultsOnlyRunsReadQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{query: "SELECT * FROM users", expected: true},
+		{query: "WITH t AS (SELECT 1) SELECT * FROM t", expected: true},
+		{query: "DELETE FROM users", expected: false},
+		{query: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			t.Parallel()
+
+			m := newLLMTestModel("")
+			m.loading = false
+			m.restoreQuery = tt.query
+
+			updated, cmd := m.restoreResults()
+			restored := updated.(model)
+
+			assert.Empty(t, restored.restoreQuery)
+			assert.Equal(t, tt.expected, restored.loading)
+			assert.Equal(t, tt.expected, cmd != nil)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ionut-t/perp/internal/whichkey"
+	"github.com/ionut-t/perp/pkg/clipboard"
+	"github.com/ionut-t/perp/pkg/export"
+)
+
+// yankResults copies the rows of the results to the clipboard in format, or
+// only the cells selected in visual mode
+func (m *model) yankResults(format whichkey.YankFormat) tea.Cmd {
+	info, ok := m.content.ResultInfo()
+	if !ok {
+		return m.errorNotification(errors.New("no results to copy"))
+	}
+
+	queryResults := m.content.GetQueryResults()
+
+	columns := make([]string, len(info.Columns))
+	for i, column := range info.Columns {
+		columns[i] = column.Name
+	}
+
+	copied := "Rows"
+	if rows, selected, ok := m.content.SelectedRange(); ok {
+		queryResults = selectRows(selectColumns(queryResults, selected), rows)
+		columns, copied = selected, "Selection"
+		m.content.ExitVisualMode()
+	}
+
+	var text, name string
+	var err error
+
+	switch format {
+	case whichkey.YankCSV:
+		text, err = export.Delimited(queryResults, columns, ',')
+		name = "CSV"
+	case whichkey.YankTSV:
+		text, err = export.Delimited(queryResults, columns, '\t')
+		name = "TSV"
+	default:
+		var data []byte
+		data, err = json.MarshalIndent(queryResults, "", "  ")
+		text, name = string(data), "JSON"
+	}
+
+	if err != nil {
+		return m.errorNotification(err)
+	}
+
+	if err := clipboard.Write(text); err != nil {
+		return m.errorNotification(err)
+	}
+
+	return m.successNotification(fmt.Sprintf("%s copied to clipboard as %s", copied, name))
+}
+
+// selectRows keeps the 1-based rows of the results
+func selectRows(queryResults []map[string]any, rows []int) []map[string]any {
+	selected := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		if row >= 1 && row <= len(queryResults) {
+			selected = append(selected, queryResults[row-1])
+		}
+	}
+	return selected
+}
@@ -0,0 +1,296 @@
+// Package confirm is a dialog asking to confirm an action before it runs:
+// answering yes or no, typing a name, or ticking every item of a list.
+package confirm
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/utils"
+)
+
+// Kind is the way a dialog is confirmed
+type Kind int
+
+const (
+	// YesNo is confirmed with y, or enter on Yes
+	YesNo Kind = iota
+	// TypeToConfirm is confirmed by typing the expected text, e.g. a name
+	TypeToConfirm
+	// Checklist is confirmed once every item is ticked
+	Checklist
+)
+
+// ConfirmedMsg is sent when the dialog with the ID is confirmed
+type ConfirmedMsg struct {
+	ID string
+}
+
+// CancelledMsg is sent when the dialog with the ID is dismissed
+type CancelledMsg struct {
+	ID string
+}
+
+type Model struct {
+	id      string
+	kind    Kind
+	title   string
+	message string
+	active  bool
+
+	yes bool // Yes is selected rather than No
+
+	expected string
+	input    textinput.Model
+
+	items   []string
+	checked []bool
+	cursor  int
+
+	styles styles.Styles
+}
+
+// NewYesNo creates a dialog answered with yes or no, No being selected
+func NewYesNo(id, title, message string) Model {
+	return Model{
+		id:      id,
+		kind:    YesNo,
+		title:   title,
+		message: message,
+		active:  true,
+	}
+}
+
+// NewTypeToConfirm creates a dialog confirmed by typing expected
+func NewTypeToConfirm(id, title, message, expected string) Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.CharLimit = 256
+	input.SetWidth(50)
+	input.Focus()
+
+	return Model{
+		id:       id,
+		kind:     TypeToConfirm,
+		title:    title,
+		message:  message,
+		active:   true,
+		expected: expected,
+		input:    input,
+	}
+}
+
+// NewChecklist creates a dialog confirmed once every item is ticked
+func NewChecklist(id, title, message string, items []string) Model {
+	return Model{
+		id:      id,
+		kind:    Checklist,
+		title:   title,
+		message: message,
+		active:  true,
+		items:   items,
+		checked: make([]bool, len(items)),
+	}
+}
+
+func (m *Model) SetStyles(s styles.Styles) {
+	m.styles = s
+
+	if m.kind != TypeToConfirm {
+		return
+	}
+
+	inputStyles := m.input.Styles()
+	inputStyles.Focused.Prompt = inputStyles.Focused.Prompt.Foreground(s.Primary.GetForeground())
+	inputStyles.Focused.Text = inputStyles.Focused.Text.Foreground(s.Primary.GetForeground())
+	m.input.SetStyles(inputStyles)
+}
+
+// IsActive reports whether the dialog is waiting for an answer
+func (m Model) IsActive() bool {
+	return m.active
+}
+
+// ID returns the ID the messages of the dialog carry
+func (m Model) ID() string {
+	return m.id
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !m.active || !ok {
+		return m, nil
+	}
+
+	if keyMsg.String() == "esc" {
+		return m.close(CancelledMsg{ID: m.id})
+	}
+
+	switch m.kind {
+	case YesNo:
+		switch keyMsg.String() {
+		case "y", "Y":
+			return m.close(ConfirmedMsg{ID: m.id})
+		case "n", "N":
+			return m.close(CancelledMsg{ID: m.id})
+		case "left", "right", "h", "l", "tab", "shift+tab":
+			m.yes = !m.yes
+		case "enter":
+			if m.yes {
+				return m.close(ConfirmedMsg{ID: m.id})
+			}
+			return m.close(CancelledMsg{ID: m.id})
+		}
+
+	case TypeToConfirm:
+		if keyMsg.String() == "enter" {
+			if m.input.Value() == m.expected {
+				return m.close(ConfirmedMsg{ID: m.id})
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+
+	case Checklist:
+		switch keyMsg.String() {
+		case "up", "k", "shift+tab":
+			m.cursor = (m.cursor - 1 + len(m.items)) % max(1, len(m.items))
+		case "down", "j", "tab":
+			m.cursor = (m.cursor + 1) % max(1, len(m.items))
+		case "space", " ", "x":
+			if m.cursor < len(m.checked) {
+				m.checked[m.cursor] = !m.checked[m.cursor]
+			}
+		case "enter":
+			if m.allChecked() {
+				return m.close(ConfirmedMsg{ID: m.id})
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// close hides the dialog and sends its answer
+func (m Model) close(answer tea.Msg) (Model, tea.Cmd) {
+	m.active = false
+	return m, utils.Dispatch(answer)
+}
+
+func (m Model) allChecked() bool {
+	for _, checked := range m.checked {
+		if !checked {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) View() string {
+	if !m.active {
+		return ""
+	}
+
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.Error.GetForeground()).
+		Padding(1, 2)
+
+	sections := []string{m.styles.Error.Bold(true).Render(m.title)}
+	if m.message != "" {
+		sections = append(sections, m.styles.Text.Render(m.message))
+	}
+
+	switch m.kind {
+	case YesNo:
+		sections = append(sections, m.renderButtons())
+	case TypeToConfirm:
+		sections = append(sections,
+			m.styles.Subtext0.Render("Type "+m.styles.Accent.Render(m.expected)+" to confirm"),
+			m.input.View(),
+		)
+	case Checklist:
+		sections = append(sections, m.renderItems())
+	}
+
+	sections = append(sections, m.styles.Overlay1.Render(m.footer()))
+
+	return border.Render(lipgloss.JoinVertical(lipgloss.Left, joinSections(sections)...))
+}
+
+func (m Model) renderButtons() string {
+	button := lipgloss.NewStyle().Padding(0, 2)
+
+	yes, no := button.Inherit(m.styles.Subtext0), button.Inherit(m.styles.Subtext0)
+	if m.yes {
+		yes = button.Inherit(m.styles.Error).Bold(true).Reverse(true)
+	} else {
+		no = button.Inherit(m.styles.Primary).Bold(true).Reverse(true)
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Left, yes.Render("Yes"), "  ", no.Render("No"))
+}
+
+func (m Model) renderItems() string {
+	lines := make([]string, len(m.items))
+
+	for i, item := range m.items {
+		box := "[ ]"
+		if m.checked[i] {
+			box = "[x]"
+		}
+
+		line := box + " " + item
+		if i == m.cursor {
+			lines[i] = m.styles.Accent.Render("> " + line)
+		} else {
+			lines[i] = m.styles.Text.Render("  " + line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) footer() string {
+	switch m.kind {
+	case TypeToConfirm:
+		return "[enter] confirm  [esc] cancel"
+	case Checklist:
+		return "[space] tick  [enter] confirm once all are ticked  [esc] cancel"
+	default:
+		return "[y] yes  [n] no  [←/→] select  [esc] cancel"
+	}
+}
+
+// Overlay centres the dialog over background, a view of width by height
+func Overlay(background, dialog string, width, height int) string {
+	x := max(0, (width-lipgloss.Width(dialog))/2)
+	y := max(0, (height-lipgloss.Height(dialog))/2)
+
+	bg := lipgloss.NewLayer(background)
+	overlay := lipgloss.NewLayer(dialog).X(x).Y(y).Z(1)
+
+	return lipgloss.NewCompositor(bg, overlay).Render()
+}
+
+// joinSections separates the sections of the dialog with a blank line
+func joinSections(sections []string) []string {
+	joined := make([]string, 0, 2*len(sections)-1)
+	for i, section := range sections {
+		if i > 0 {
+			joined = append(joined, "")
+		}
+		joined = append(joined, section)
+	}
+	return joined
+}
@@ -0,0 +1,331 @@
+// Package form is a dialog of fields filled in one after another: text,
+// masked text such as passwords, or a choice between options. Fields are
+// validated as they are left and the whole form once more when submitted.
+package form
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/ionut-t/coffee/styles"
+	"github.com/ionut-t/perp/pkg/utils"
+)
+
+// Kind is the way a field is filled in
+type Kind int
+
+const (
+	// Text is typed
+	Text Kind = iota
+	// Password is typed without being shown
+	Password
+	// Select is one of the options, chosen with ←/→ or its first letter
+	Select
+)
+
+// Field describes a field of the form
+type Field struct {
+	Key      string // the key of the value in Values
+	Label    string
+	Kind     Kind
+	Value    string   // the initial value, one of Options for a Select
+	Options  []string // Select only
+	Required bool
+	Validate func(value string) error
+}
+
+// Values are the values of the fields, by key
+type Values map[string]string
+
+// SubmittedMsg is sent when the form with the ID is submitted with valid values
+type SubmittedMsg struct {
+	ID     string
+	Values Values
+}
+
+// CancelledMsg is sent when the form with the ID is dismissed
+type CancelledMsg struct {
+	ID string
+}
+
+type field struct {
+	Field
+	input    textinput.Model
+	selected int
+	err      error
+}
+
+type Model struct {
+	id       string
+	title    string
+	fields   []field
+	focused  int
+	validate func(Values) error
+	err      error
+	styles   styles.Styles
+}
+
+// New creates a form with the fields, the first one focused
+func New(id, title string, fields ...Field) Model {
+	m := Model{
+		id:     id,
+		title:  title,
+		fields: make([]field, len(fields)),
+	}
+
+	for i, f := range fields {
+		input := textinput.New()
+		input.Prompt = f.Label + ": "
+		input.CharLimit = 256
+		input.SetWidth(50)
+		input.SetValue(f.Value)
+
+		if f.Kind == Password {
+			input.EchoMode = textinput.EchoPassword
+		}
+
+		m.fields[i] = field{
+			Field:    f,
+			input:    input,
+			selected: max(0, slices.Index(f.Options, f.Value)),
+		}
+	}
+
+	m.focus(0)
+
+	return m
+}
+
+// SetValidate sets a validation of the values together, run once every field
+// is valid, e.g. to check that a password was typed twice the same
+func (m *Model) SetValidate(validate func(Values) error) {
+	m.validate = validate
+}
+
+func (m *Model) SetStyles(s styles.Styles) {
+	m.styles = s
+
+	for i := range m.fields {
+		inputStyles := m.fields[i].input.Styles()
+		inputStyles.Focused.Prompt = inputStyles.Focused.Prompt.Foreground(s.Primary.GetForeground())
+		inputStyles.Focused.Text = inputStyles.Focused.Text.Foreground(s.Primary.GetForeground())
+		m.fields[i].input.SetStyles(inputStyles)
+	}
+}
+
+// ID returns the ID the messages of the form carry
+func (m Model) ID() string {
+	return m.id
+}
+
+// SetValue replaces the value of the field with the key
+func (m *Model) SetValue(key, value string) {
+	for i := range m.fields {
+		f := &m.fields[i]
+		if f.Key != key {
+			continue
+		}
+
+		f.input.SetValue(value)
+		if index := slices.Index(f.Options, value); index >= 0 {
+			f.selected = index
+		}
+	}
+}
+
+// Values returns the current values of the fields
+func (m Model) Values() Values {
+	values := make(Values, len(m.fields))
+	for _, f := range m.fields {
+		values[f.Key] = f.value()
+	}
+	return values
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if len(m.fields) == 0 {
+		return m, nil
+	}
+
+	current := &m.fields[m.focused]
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		// e.g. the blinking of the cursor
+		var cmd tea.Cmd
+		current.input, cmd = current.input.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		return m, utils.Dispatch(CancelledMsg{ID: m.id})
+
+	case "tab", "down":
+		m.focus(min(m.focused+1, len(m.fields)-1))
+		return m, nil
+
+	case "shift+tab", "up":
+		m.focus(max(m.focused-1, 0))
+		return m, nil
+
+	case "enter":
+		if current.err = current.check(); current.err != nil {
+			return m, nil
+		}
+
+		if m.focused < len(m.fields)-1 {
+			m.focus(m.focused + 1)
+			return m, nil
+		}
+
+		return m.submit()
+	}
+
+	m.err = nil
+
+	if current.Kind == Select {
+		current.choose(keyMsg.String())
+		return m, nil
+	}
+
+	current.err = nil
+
+	var cmd tea.Cmd
+	current.input, cmd = current.input.Update(msg)
+	return m, cmd
+}
+
+// submit validates every field, focusing the first invalid one, then the
+// values together
+func (m Model) submit() (Model, tea.Cmd) {
+	for i := range m.fields {
+		if m.fields[i].err = m.fields[i].check(); m.fields[i].err != nil {
+			m.focus(i)
+			return m, nil
+		}
+	}
+
+	values := m.Values()
+
+	if m.validate != nil {
+		if m.err = m.validate(values); m.err != nil {
+			return m, nil
+		}
+	}
+
+	return m, utils.Dispatch(SubmittedMsg{ID: m.id, Values: values})
+}
+
+func (m *Model) focus(index int) {
+	if len(m.fields) == 0 {
+		return
+	}
+
+	m.fields[m.focused].input.Blur()
+	m.focused = index
+
+	if m.fields[index].Kind != Select {
+		m.fields[index].input.Focus()
+	}
+}
+
+func (f field) value() string {
+	if f.Kind == Select {
+		if f.selected < len(f.Options) {
+			return f.Options[f.selected]
+		}
+		return ""
+	}
+	return f.input.Value()
+}
+
+// check returns why the value of the field is invalid, or nil
+func (f field) check() error {
+	value := f.value()
+
+	if f.Required && strings.TrimSpace(value) == "" {
+		return errors.New(strings.ToLower(f.Label) + " is required")
+	}
+
+	if f.Validate != nil {
+		return f.Validate(value)
+	}
+
+	return nil
+}
+
+// choose moves the selection of a Select with ←/→, or to the first option
+// starting with the typed letter
+func (f *field) choose(key string) {
+	if len(f.Options) == 0 {
+		return
+	}
+
+	switch key {
+	case "left":
+		f.selected = (f.selected - 1 + len(f.Options)) % len(f.Options)
+	case "right", "space", " ":
+		f.selected = (f.selected + 1) % len(f.Options)
+	default:
+		index := slices.IndexFunc(f.Options, func(option string) bool {
+			return len(key) == 1 && strings.HasPrefix(strings.ToLower(option), strings.ToLower(key))
+		})
+		if index >= 0 {
+			f.selected = index
+		}
+	}
+}
+
+func (m Model) View() string {
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.Primary.GetForeground()).
+		Padding(1, 2)
+
+	lines := []string{m.styles.Primary.Bold(true).MarginBottom(1).Render(m.title)}
+
+	for i, f := range m.fields {
+		if f.Kind == Select {
+			lines = append(lines, m.renderSelect(f, i == m.focused))
+		} else {
+			lines = append(lines, f.input.View())
+		}
+
+		if f.err != nil {
+			lines = append(lines, m.styles.Error.Render(f.err.Error()))
+		}
+	}
+
+	if m.err != nil {
+		lines = append(lines, m.styles.Error.MarginTop(1).Render(m.err.Error()))
+	}
+
+	return border.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (m Model) renderSelect(f field, focused bool) string {
+	label := m.styles.Subtext0.Render(f.Label + ": ")
+	if focused {
+		label = m.styles.Primary.Render(f.Label + ": ")
+	}
+
+	options := make([]string, len(f.Options))
+	for i, option := range f.Options {
+		if i == f.selected {
+			options[i] = m.styles.Primary.Bold(true).Reverse(focused).Padding(0, 1).Render(option)
+		} else {
+			options[i] = m.styles.Subtext0.Padding(0, 1).Render(option)
+		}
+	}
+
+	return label + strings.Join(options, " ")
+}
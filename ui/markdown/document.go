@@ -0,0 +1,202 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CodeBlock is a fenced code block of a document
+type CodeBlock struct {
+	Language string
+	Code     string
+}
+
+// Document is markdown prepared for the terminal: the numeric columns of its
+// tables are aligned right, and its code blocks and links are numbered so
+// they can be yanked and opened by number
+type Document struct {
+	Markdown   string
+	CodeBlocks []CodeBlock
+	Links      []string
+}
+
+var (
+	delimiterCell = regexp.MustCompile(`^:?-+:?$`)
+	linkPattern   = regexp.MustCompile(`\[[^\]]*\]\((\S+?)\)|<(https?://[^>\s]+)>|(https?://[^\s)>\]]+)`)
+)
+
+// Prepare numbers the code blocks of markdown with a caption above each,
+// lists its links below it, and aligns right the table columns holding only
+// numbers when their alignment isn't set
+func Prepare(markdown string) Document {
+	var doc Document
+	var out []string
+
+	lines := strings.Split(markdown, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence, indent, language, ok := openingFence(line); ok {
+			end := i + 1
+			for end < len(lines) && !isClosingFence(lines[end], fence) {
+				end++
+			}
+
+			code := make([]string, 0, end-i-1)
+			for _, codeLine := range lines[i+1 : min(end, len(lines))] {
+				code = append(code, strings.TrimPrefix(codeLine, indent))
+			}
+
+			doc.CodeBlocks = append(doc.CodeBlocks, CodeBlock{Language: language, Code: strings.Join(code, "\n")})
+
+			caption := fmt.Sprintf("[%d]", len(doc.CodeBlocks))
+			if language != "" {
+				caption += " " + language
+			}
+
+			out = append(out, indent+"_"+caption+"_", "")
+			out = append(out, lines[i:min(end+1, len(lines))]...)
+			i = end
+			continue
+		}
+
+		if i+1 < len(lines) && isTableRow(line) && isDelimiterRow(lines[i+1]) {
+			end := i + 2
+			for end < len(lines) && isTableRow(lines[end]) {
+				end++
+			}
+
+			out = append(out, line, alignNumbers(lines[i+1], lines[i+2:end]))
+			out = append(out, lines[i+2:end]...)
+			doc.addLinks(lines[i:end])
+			i = end - 1
+			continue
+		}
+
+		out = append(out, line)
+		doc.addLinks([]string{line})
+	}
+
+	if len(doc.Links) > 0 {
+		out = append(out, "", "**Links**", "")
+		for i, link := range doc.Links {
+			out = append(out, fmt.Sprintf("%d. %s", i+1, link))
+		}
+	}
+
+	doc.Markdown = strings.Join(out, "\n")
+
+	return doc
+}
+
+// addLinks keeps the links of lines not seen yet, in order
+func (doc *Document) addLinks(lines []string) {
+	for _, line := range lines {
+		for _, match := range linkPattern.FindAllStringSubmatch(line, -1) {
+			link := strings.TrimRight(match[1]+match[2]+match[3], ".,;:")
+			if link != "" && !slices.Contains(doc.Links, link) {
+				doc.Links = append(doc.Links, link)
+			}
+		}
+	}
+}
+
+// openingFence reports whether line opens a fenced code block, returning
+// the fence, the indentation and the language of the block
+func openingFence(line string) (fence, indent, language string, ok bool) {
+	// any indentation, as the blocks of list items are indented
+	trimmed := strings.TrimLeft(line, " ")
+
+	for _, marker := range []string{"```", "~~~"} {
+		if !strings.HasPrefix(trimmed, marker) {
+			continue
+		}
+
+		fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, marker[:1]))]
+		info := strings.Fields(trimmed[len(fence):])
+		if len(info) > 0 {
+			language = info[0]
+		}
+
+		return fence, line[:len(line)-len(trimmed)], language, true
+	}
+
+	return "", "", "", false
+}
+
+// isClosingFence reports whether line closes the code block opened by fence
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func isTableRow(line string) bool {
+	return strings.Contains(line, "|") && strings.TrimSpace(line) != ""
+}
+
+func isDelimiterRow(line string) bool {
+	cells := tableCells(line)
+	if len(cells) == 0 {
+		return false
+	}
+
+	for _, cell := range cells {
+		if !delimiterCell.MatchString(cell) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tableCells returns the trimmed cells of a table row
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+
+	return cells
+}
+
+// alignNumbers returns the delimiter row of a table with the columns left
+// without alignment aligned right when all of their cells are numbers
+func alignNumbers(delimiter string, rows []string) string {
+	cells := tableCells(delimiter)
+
+	for column, cell := range cells {
+		if strings.Contains(cell, ":") || len(rows) == 0 {
+			continue
+		}
+
+		numeric := true
+		for _, row := range rows {
+			values := tableCells(row)
+			if column < len(values) && values[column] != "" && !isNumber(values[column]) {
+				numeric = false
+				break
+			}
+		}
+
+		if numeric {
+			cells[column] = strings.Repeat("-", max(3, len(cell)-1)) + ":"
+		}
+	}
+
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// isNumber reports whether value is a number, e.g. 42, -3.5, 1,024 or 12%
+func isNumber(value string) bool {
+	value = strings.TrimSuffix(strings.ReplaceAll(value, ",", ""), "%")
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
@@ -23,17 +23,31 @@ func getThemeBytes(isDark bool) []byte {
 type Model struct {
 	renderer *glamour.TermRenderer
 	error    error
+	isDark   bool
+	width    int
 }
 
 func New(isDark bool) Model {
-	renderer, err := createGlamourRenderer(isDark)
+	renderer, err := createGlamourRenderer(isDark, 0)
 
 	return Model{
 		renderer: renderer,
 		error:    err,
+		isDark:   isDark,
 	}
 }
 
+// SetWidth wraps the text and fits the tables to width, 0 keeping the
+// default of 80 columns
+func (m *Model) SetWidth(width int) {
+	if width == m.width {
+		return
+	}
+
+	m.width = width
+	m.renderer, m.error = createGlamourRenderer(m.isDark, width)
+}
+
 // Render renders markdown
 func (m Model) Render(markdown string) (string, error) {
 	if m.error != nil {
@@ -43,10 +57,16 @@ func (m Model) Render(markdown string) (string, error) {
 	return m.renderer.Render(markdown)
 }
 
-func createGlamourRenderer(isDark bool) (*glamour.TermRenderer, error) {
+func createGlamourRenderer(isDark bool, width int) (*glamour.TermRenderer, error) {
 	themeBytes := getThemeBytes(isDark)
 
-	return glamour.NewTermRenderer(
+	options := []glamour.TermRendererOption{
 		glamour.WithStylesFromJSONBytes(themeBytes),
-	)
+	}
+
+	if width > 0 {
+		options = append(options, glamour.WithWordWrap(width))
+	}
+
+	return glamour.NewTermRenderer(options...)
 }